@@ -1,12 +1,15 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"os/signal"
+	"runtime/pprof"
 	"strings"
 	"sync"
 	"syscall"
@@ -23,13 +26,16 @@ import (
 	libovsdbclient "github.com/ovn-org/libovsdb/client"
 	"github.com/urfave/cli/v2"
 
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/cachesnapshot"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/clustermanager"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/libovsdb"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/libovsdbops"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/metrics"
 	controllerManager "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/network-controller-manager"
 	ovnnode "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/node"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovndbmanager"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 
@@ -183,6 +189,7 @@ type ovnkubeRunMode struct {
 	clusterManager           bool // cluster manager (--init-cluster-manager or --init-master) is enabled
 	node                     bool // node (--init-node) is enabled
 	cleanupNode              bool // cleanup (--cleanup-node) is enabled
+	allInOne                 bool // all-in-one (--init-all) is enabled, along with all the modes above but cleanup
 
 	// Along with the run mode, an identity is provided that uniquely identifies
 	// this instance vs other instances that might be running in the cluster.
@@ -197,6 +204,8 @@ type ovnkubeRunMode struct {
 //   - master (controller manager + cluster manager) + node
 //   - network controller manager + cluster manager
 //   - network controller manager + node
+//   - all-in-one (cluster manager + network controller manager + node,
+//     without leader election)
 func determineOvnkubeRunMode(ctx *cli.Context) (*ovnkubeRunMode, error) {
 	mode := &ovnkubeRunMode{}
 
@@ -205,6 +214,17 @@ func determineOvnkubeRunMode(ctx *cli.Context) (*ovnkubeRunMode, error) {
 	nm := ctx.String("init-network-controller-manager")
 	node := ctx.String("init-node")
 	cleanup := ctx.String("cleanup-node")
+	all := ctx.String("init-all")
+
+	if all != "" {
+		if master != "" || cm != "" || nm != "" || node != "" {
+			return nil, fmt.Errorf("cannot run all-in-one mode along with any other mode")
+		}
+		mode.allInOne = true
+		mode.networkControllerManager = true
+		mode.clusterManager = true
+		mode.node = true
+	}
 
 	if master != "" {
 		// If init-master is set, then both network controller manager and cluster manager
@@ -241,7 +261,7 @@ func determineOvnkubeRunMode(ctx *cli.Context) (*ovnkubeRunMode, error) {
 		return nil, fmt.Errorf("cannot run in both cluster manager and node mode")
 	}
 
-	identities := sets.NewString(master, cm, nm, node, cleanup)
+	identities := sets.NewString(master, cm, nm, node, cleanup, all)
 	identities.Delete("")
 	if identities.Len() != 1 {
 		return nil, fmt.Errorf("provided no identity or different identities for different modes")
@@ -281,6 +301,16 @@ func startOvnKube(ctx *cli.Context, cancel context.CancelFunc) error {
 		return err
 	}
 
+	if runMode.allInOne && config.OvnKubeNode.Mode != types.NodeModeFull {
+		return fmt.Errorf("all-in-one mode is not supported with ovnkube node mode %s", config.OvnKubeNode.Mode)
+	}
+
+	// only the network controller manager can run without changing anything,
+	// against databases run by another instance
+	if config.Default.DryRun && (runMode.clusterManager || runMode.node || config.EmbeddedDB.Enabled) {
+		return fmt.Errorf("dry-run mode is only supported by the network controller manager, without embedded databases")
+	}
+
 	eventRecorder := util.EventRecorder(ovnClientset.KubeClient)
 
 	ovnKubeStartWg := &sync.WaitGroup{}
@@ -294,7 +324,18 @@ func startOvnKube(ctx *cli.Context, cancel context.CancelFunc) error {
 	// Non LE master instances also are required to expose the metrics server.
 	if config.Metrics.BindAddress != "" {
 		metrics.StartMetricsServer(config.Metrics.BindAddress, config.Metrics.EnablePprof,
-			config.Metrics.NodeServerCert, config.Metrics.NodeServerPrivKey, ctx.Done(), ovnKubeStartWg)
+			config.Metrics.NodeServerCert, config.Metrics.NodeServerPrivKey, config.Metrics.NodeServerClientCA,
+			config.Metrics.NodeServerAllowedClients, ctx.Done(), ovnKubeStartWg)
+		// Allow listing the ExternalIDs schemas of the OVN db objects
+		metrics.RegisterDebugHandler("dbobjectids", http.HandlerFunc(libovsdbops.ObjectIDsSchemaHandler))
+	}
+
+	if config.Metrics.EnableWatchdog {
+		metrics.StartWatchdog(metrics.WatchdogConfig{
+			GoroutineThreshold: config.Metrics.WatchdogGoroutineThreshold,
+			LockWaitThreshold:  time.Duration(config.Metrics.WatchdogLockWaitThreshold) * time.Millisecond,
+			StallThreshold:     time.Duration(config.Metrics.WatchdogStallThreshold) * time.Second,
+		}, ctx.Done(), ovnKubeStartWg)
 	}
 
 	// no need for leader election in node mode
@@ -302,6 +343,23 @@ func startOvnKube(ctx *cli.Context, cancel context.CancelFunc) error {
 		return runOvnKube(ctx.Context, runMode, ovnClientset, eventRecorder)
 	}
 
+	// nor in all-in-one mode, where this is the only instance running the
+	// controllers of the cluster
+	if runMode.allInOne {
+		metrics.RegisterClusterManagerBase()
+		metrics.RegisterMasterBase()
+		ovnkubeMasterMetrics{runMode}.On(runMode.identity)
+		return runOvnKube(ctx.Context, runMode, ovnClientset, eventRecorder)
+	}
+
+	// nor in dry-run mode, which must not take the leadership over the
+	// instance that is actually running the controllers
+	if config.Default.DryRun {
+		metrics.RegisterMasterBase()
+		klog.Infof("Running in dry-run mode, OVN database transactions and Kubernetes API writes are not applied")
+		return runOvnKube(ctx.Context, runMode, ovnClientset, eventRecorder)
+	}
+
 	// Register prometheus metrics that do not depend on becoming ovnkube-master
 	// leader and get the proper HA config depending on the mode. For network
 	// manager mode or combined cluster and network manager modes (the classic
@@ -410,23 +468,47 @@ func runOvnKube(ctx context.Context, runMode *ovnkubeRunMode, ovnClientset *util
 		return ovnnode.CleanupClusterNode(runMode.identity)
 	}
 
+	// everything started below registers how it is stopped in the shutdown
+	// sequence, which runs once ovnkube is cancelled or fails to start
+	shutdown := &shutdownSequence{}
+	defer shutdown.run()
+
 	stopChan := make(chan struct{})
 	wg := &sync.WaitGroup{}
-	defer func() {
+	shutdown.add("libovsdb clients and metrics servers", func() {
 		close(stopChan)
 		wg.Wait()
-	}()
+	})
 
 	var masterWatchFactory *factory.WatchFactory
+	var libovsdbOvnNBClient, libovsdbOvnSBClient libovsdbclient.Client
 	var err error
 
 	if runMode.networkControllerManager {
-		// create factory and start the controllers asked for
+		// create the factory and the libovsdb clients shared by the
+		// controllers asked for
 		masterWatchFactory, err = factory.NewMasterWatchFactory(ovnClientset.GetMasterClientset())
 		if err != nil {
 			return err
 		}
-		defer masterWatchFactory.Shutdown()
+		shutdown.add("master watch factory", masterWatchFactory.Shutdown)
+		registerCacheSnapshot("master", masterWatchFactory, shutdown)
+
+		if config.EmbeddedDB.Enabled {
+			// the databases of the zone are run by us, start them before
+			// connecting to them
+			if err = ovndbmanager.RunEmbeddedDBs(stopChan, wg); err != nil {
+				return fmt.Errorf("failed to start the embedded databases: %w", err)
+			}
+		}
+
+		if libovsdbOvnNBClient, err = libovsdb.NewNBClient(stopChan); err != nil {
+			return fmt.Errorf("error when trying to initialize libovsdb NB client: %v", err)
+		}
+
+		if libovsdbOvnSBClient, err = libovsdb.NewSBClient(stopChan); err != nil {
+			return fmt.Errorf("error when trying to initialize libovsdb SB client: %v", err)
+		}
 	}
 
 	if runMode.clusterManager {
@@ -438,7 +520,8 @@ func runOvnKube(ctx context.Context, runMode *ovnkubeRunMode, ovnClientset *util
 			if err != nil {
 				return err
 			}
-			defer clusterManagerWatchFactory.Shutdown()
+			shutdown.add("cluster manager watch factory", clusterManagerWatchFactory.Shutdown)
+			registerCacheSnapshot("cluster-manager", clusterManagerWatchFactory, shutdown)
 		}
 
 		cm, err := clustermanager.NewClusterManager(ovnClientset.GetClusterManagerClientset(), clusterManagerWatchFactory,
@@ -450,23 +533,13 @@ func runOvnKube(ctx context.Context, runMode *ovnkubeRunMode, ovnClientset *util
 		if err != nil {
 			return fmt.Errorf("failed to start cluster manager: %w", err)
 		}
-		defer cm.Stop()
+		shutdown.add("cluster manager", cm.Stop)
 
 		// record delay until ready
 		metrics.MetricClusterManagerReadyDuration.Set(time.Since(startTime).Seconds())
 	}
 
 	if runMode.networkControllerManager {
-		var libovsdbOvnNBClient, libovsdbOvnSBClient libovsdbclient.Client
-
-		if libovsdbOvnNBClient, err = libovsdb.NewNBClient(stopChan); err != nil {
-			return fmt.Errorf("error when trying to initialize libovsdb NB client: %v", err)
-		}
-
-		if libovsdbOvnSBClient, err = libovsdb.NewSBClient(stopChan); err != nil {
-			return fmt.Errorf("error when trying to initialize libovsdb SB client: %v", err)
-		}
-
 		cm, err := controllerManager.NewNetworkControllerManager(ovnClientset, runMode.identity,
 			masterWatchFactory, libovsdbOvnNBClient, libovsdbOvnSBClient, eventRecorder, wg)
 		if err != nil {
@@ -476,7 +549,7 @@ func runOvnKube(ctx context.Context, runMode *ovnkubeRunMode, ovnClientset *util
 		if err != nil {
 			return fmt.Errorf("failed to start network controller manager: %w", err)
 		}
-		defer cm.Stop()
+		shutdown.add("network controller manager", cm.Stop)
 
 		// record delay until ready
 		metrics.MetricMasterReadyDuration.Set(time.Since(startTime).Seconds())
@@ -486,12 +559,13 @@ func runOvnKube(ctx context.Context, runMode *ovnkubeRunMode, ovnClientset *util
 		var nodeWatchFactory factory.NodeWatchFactory
 
 		if masterWatchFactory == nil {
-			var err error
-			nodeWatchFactory, err = factory.NewNodeWatchFactory(ovnClientset.GetNodeClientset(), runMode.identity)
+			wf, err := factory.NewNodeWatchFactory(ovnClientset.GetNodeClientset(), runMode.identity)
 			if err != nil {
 				return err
 			}
-			defer nodeWatchFactory.Shutdown()
+			shutdown.add("node watch factory", wf.Shutdown)
+			registerCacheSnapshot("node", wf, shutdown)
+			nodeWatchFactory = wf
 		} else {
 			nodeWatchFactory = masterWatchFactory
 		}
@@ -501,6 +575,11 @@ func runOvnKube(ctx context.Context, runMode *ovnkubeRunMode, ovnClientset *util
 		}
 		// register ovnkube node specific prometheus metrics exported by the node
 		metrics.RegisterNodeMetrics()
+		// there is no OVS on a dpu-host node to collect the traffic of the pods from
+		if config.Metrics.NamespaceUsageInterval > 0 && config.OvnKubeNode.Mode != types.NodeModeDPUHost {
+			metrics.StartNamespaceUsageCollector(time.Duration(config.Metrics.NamespaceUsageInterval)*time.Second,
+				config.Metrics.NamespaceUsageMaxNamespaces, stopChan)
+		}
 		ncm, err := controllerManager.NewNodeNetworkControllerManager(ovnClientset, nodeWatchFactory, runMode.identity, eventRecorder)
 		if err != nil {
 			return fmt.Errorf("failed to create ovnkube node network controller manager: %w", err)
@@ -509,7 +588,7 @@ func runOvnKube(ctx context.Context, runMode *ovnkubeRunMode, ovnClientset *util
 		if err != nil {
 			return fmt.Errorf("failed to start node network manager: %w", err)
 		}
-		defer ncm.Stop()
+		shutdown.add("node network controller manager", ncm.Stop)
 
 		// record delay until ready
 		metrics.MetricNodeReadyDuration.Set(time.Since(startTime).Seconds())
@@ -523,7 +602,8 @@ func runOvnKube(ctx context.Context, runMode *ovnkubeRunMode, ovnClientset *util
 		}
 		metrics.RegisterOvnMetrics(ovnClientset.KubeClient, runMode.identity, stopChan)
 		metrics.StartOVNMetricsServer(config.Metrics.OVNMetricsBindAddress,
-			config.Metrics.NodeServerCert, config.Metrics.NodeServerPrivKey, stopChan, wg)
+			config.Metrics.NodeServerCert, config.Metrics.NodeServerPrivKey, config.Metrics.NodeServerClientCA,
+			config.Metrics.NodeServerAllowedClients, stopChan, wg)
 	}
 
 	// run until cancelled
@@ -531,6 +611,72 @@ func runOvnKube(ctx context.Context, runMode *ovnkubeRunMode, ovnClientset *util
 	return nil
 }
 
+// shutdownSequence stops the components started by runOvnKube in the reverse
+// order they were started in: the controllers stop handling events first, then
+// the watch factories feeding them are shut down and the libovsdb clients they
+// share are closed last. The whole sequence must complete within
+// config.Default.ShutdownTimeout, otherwise the components still running and
+// the goroutine stacks are logged and the remaining components are abandoned.
+type shutdownSequence struct {
+	names []string
+	stops []func()
+}
+
+func (s *shutdownSequence) add(name string, stop func()) {
+	s.names = append(s.names, name)
+	s.stops = append(s.stops, stop)
+}
+
+func (s *shutdownSequence) run() {
+	start := time.Now()
+	var timeout <-chan time.Time
+	if config.Default.ShutdownTimeout > 0 {
+		timer := time.NewTimer(time.Duration(config.Default.ShutdownTimeout) * time.Second)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	for i := len(s.stops) - 1; i >= 0; i-- {
+		klog.Infof("Stopping %s", s.names[i])
+		stepStart := time.Now()
+		stopped := make(chan struct{})
+		go func(stop func()) {
+			defer close(stopped)
+			stop()
+		}(s.stops[i])
+		select {
+		case <-stopped:
+			klog.Infof("Stopped %s in %v", s.names[i], time.Since(stepStart))
+		case <-timeout:
+			var stacks bytes.Buffer
+			if err := pprof.Lookup("goroutine").WriteTo(&stacks, 1); err != nil {
+				klog.Errorf("Failed to get the goroutine stacks: %v", err)
+			}
+			notStopped := make([]string, 0, i+1)
+			for j := i; j >= 0; j-- {
+				notStopped = append(notStopped, s.names[j])
+			}
+			klog.Errorf("Shutdown did not complete within %ds, still stopping %s for %v, not stopped: %s. "+
+				"Goroutines:\n%s", config.Default.ShutdownTimeout, s.names[i], time.Since(stepStart),
+				strings.Join(notStopped, ", "), stacks.String())
+			return
+		}
+	}
+	klog.Infof("Shutdown completed in %v", time.Since(start))
+}
+
+// registerCacheSnapshot makes the informer caches of the given watch factory
+// downloadable from the /debug/snapshot/<name> handler of the metrics server
+// until the watch factory is shut down
+func registerCacheSnapshot(name string, wf *factory.WatchFactory, shutdown *shutdownSequence) {
+	debugPath := "snapshot/" + name
+	metrics.RegisterDebugHandler(debugPath, cachesnapshot.Handler(func() (interface{}, error) {
+		return wf.Snapshot()
+	}))
+	shutdown.add(name+" cache snapshot", func() {
+		metrics.UnregisterDebugHandler(debugPath)
+	})
+}
+
 type ovnkubeMasterMetrics struct {
 	runMode *ovnkubeRunMode
 }
@@ -22,10 +22,11 @@ if  ! ( command -v controller-gen > /dev/null ); then
 fi
 
 for crd in ${crds}; do
+  version=$(ls pkg/crd/$crd)
   echo "Generating deepcopy funcs for $crd"
   deepcopy-gen \
     --go-header-file hack/boilerplate.go.txt \
-    --input-dirs github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/v1 \
+    --input-dirs github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/$version \
     -O zz_generated.deepcopy \
     --bounding-dirs github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd
 
@@ -35,26 +36,26 @@ for crd in ${crds}; do
     --go-header-file hack/boilerplate.go.txt \
     --clientset-name "${CLIENTSET_NAME_VERSIONED:-versioned}" \
     --input-base "" \
-    --input github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/v1 \
-    --output-package github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/v1/apis/clientset \
+    --input github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/$version \
+    --output-package github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/$version/apis/clientset \
     --plural-exceptions="EgressQoS:EgressQoSes" \
     "$@"
 
   echo "Generating listers for $crd"
   lister-gen \
     --go-header-file hack/boilerplate.go.txt \
-    --input-dirs github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/v1 \
-    --output-package github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/v1/apis/listers \
+    --input-dirs github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/$version \
+    --output-package github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/$version/apis/listers \
     --plural-exceptions="EgressQoS:EgressQoSes" \
     "$@"
 
   echo "Generating informers for $crd"
   informer-gen \
     --go-header-file hack/boilerplate.go.txt \
-    --input-dirs github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/v1 \
-    --versioned-clientset-package github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/v1/apis/clientset/versioned \
-    --listers-package  github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/v1/apis/listers \
-    --output-package github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/v1/apis/informers \
+    --input-dirs github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/$version \
+    --versioned-clientset-package github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/$version/apis/clientset/versioned \
+    --listers-package  github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/$version/apis/listers \
+    --output-package github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/$version/apis/informers \
     --plural-exceptions="EgressQoS:EgressQoSes" \
     "$@"
 done
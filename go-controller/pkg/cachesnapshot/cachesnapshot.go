@@ -0,0 +1,40 @@
+// Package cachesnapshot lets developers download the content of the informer
+// caches of ovnkube, sanitized, to reproduce an issue offline against the
+// cluster state the controllers acted on. The snapshot function of a watch
+// factory is served as a Handler.
+package cachesnapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+)
+
+// Func returns the snapshot of a cache, serializable as JSON
+type Func func() (interface{}, error)
+
+// Handler serves the snapshots of a Func: a GET returns the snapshot as a
+// JSON file named after the last element of the request path
+type Handler Func
+
+func (snapshot Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	objects, err := snapshot()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to take the snapshot: %v", err), http.StatusInternalServerError)
+		return
+	}
+	data, err := json.Marshal(objects)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to serialize the snapshot: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", path.Base(r.URL.Path)+"-snapshot.json"))
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,58 @@
+package cachesnapshot
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	master := Handler(func() (interface{}, error) {
+		return map[string][]string{"pods": {"ns1/pod1"}}, nil
+	})
+	failing := Handler(func() (interface{}, error) { return nil, fmt.Errorf("boom") })
+
+	tests := []struct {
+		desc                string
+		handler             Handler
+		method              string
+		expectedCode        int
+		expectedBody        string
+		expectedDisposition string
+	}{
+		{
+			desc:                "returns the snapshot",
+			handler:             master,
+			method:              http.MethodGet,
+			expectedCode:        http.StatusOK,
+			expectedBody:        `{"pods":["ns1/pod1"]}`,
+			expectedDisposition: `attachment; filename="master-snapshot.json"`,
+		},
+		{
+			desc:         "reports snapshot failures",
+			handler:      failing,
+			method:       http.MethodGet,
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "failed to take the snapshot: boom\n",
+		},
+		{
+			desc:         "rejects other methods",
+			handler:      master,
+			method:       http.MethodPost,
+			expectedCode: http.StatusMethodNotAllowed,
+			expectedBody: "method not allowed\n",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tc.handler.ServeHTTP(w, httptest.NewRequest(tc.method, "/debug/snapshot/master", nil))
+			assert.Equal(t, tc.expectedCode, w.Code)
+			assert.Equal(t, tc.expectedBody, w.Body.String())
+			assert.Equal(t, tc.expectedDisposition, w.Header().Get("Content-Disposition"))
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package clustermanager
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/clustermanager/allocationauditor"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/clustermanager/subnetallocator"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/metrics"
+)
+
+// names of the allocation audits of the cluster manager, the node subnets of
+// the secondary networks are audited as nodeSubnetsAuditName/<network>
+const (
+	nodeSubnetsAuditName          = "node-subnets"
+	hybridOverlaySubnetsAuditName = "hybrid-overlay-subnets"
+	nodeIDsAuditName              = "node-ids"
+)
+
+// allocationAuditDebugPath is the path of the allocation audit handler under /debug/ on the metrics server
+const allocationAuditDebugPath = "allocations"
+
+// reservedNodeIDOwners are the owners of the node ids that are never allocated to nodes
+var reservedNodeIDOwners = sets.NewString("zero", "one")
+
+// startAllocationAudit adds the audit of the node ids, which also determine the join and transit switch addresses
+// of the nodes, serves the on demand audits and, if configured, starts the periodic audit. The network cluster
+// controllers add the audits of their node subnets when they start.
+func (cm *ClusterManager) startAllocationAudit(stopChan <-chan struct{}) {
+	cm.allocationAuditor.Add(nodeIDsAuditName, cm.zoneClusterController.nodeIDsCheck,
+		cm.zoneClusterController.nodeIDAllocator.releaseID)
+	metrics.RegisterDebugHandler(allocationAuditDebugPath, cm.allocationAuditor)
+
+	if config.ClusterManager.AllocationAuditInterval == 0 {
+		return
+	}
+	cm.wg.Add(1)
+	go func() {
+		defer cm.wg.Done()
+		cm.allocationAuditor.Run(time.Duration(config.ClusterManager.AllocationAuditInterval)*time.Second, stopChan)
+	}()
+}
+
+// stopAllocationAudit stops serving the on demand audits and removes the audit of the node ids
+func (cm *ClusterManager) stopAllocationAudit() {
+	metrics.UnregisterDebugHandler(allocationAuditDebugPath)
+	cm.allocationAuditor.Remove(nodeIDsAuditName)
+}
+
+// recordLeakedAllocations records the result of the audit of an allocator in the metrics
+func recordLeakedAllocations(result *allocationauditor.Result) {
+	leaked := len(result.Leaked)
+	if result.Reclaimed {
+		leaked = 0
+	}
+	metrics.RecordLeakedAllocations(result.Allocator, leaked)
+}
+
+// nodeSubnetsAuditName returns the name of the audit of the node subnets of the network
+func (ncc *networkClusterController) nodeSubnetsAuditName() string {
+	if !ncc.IsSecondary() {
+		return nodeSubnetsAuditName
+	}
+	return nodeSubnetsAuditName + "/" + ncc.networkName
+}
+
+// addAllocationAudits adds the audits of the node subnets of the network, and of the hybrid overlay subnets if
+// they are allocated, to the allocation auditor
+func (ncc *networkClusterController) addAllocationAudits() {
+	if ncc.allocationAuditor == nil || !ncc.hasNodeSubnets() {
+		return
+	}
+	ncc.allocationAuditor.Add(ncc.nodeSubnetsAuditName(),
+		subnetAllocationCheck(ncc.watchFactory, ncc.clusterSubnetAllocator),
+		ncc.clusterSubnetAllocator.ReleaseAllNodeSubnets)
+	if ncc.enableHybridOverlaySubnetAllocator {
+		ncc.allocationAuditor.Add(hybridOverlaySubnetsAuditName,
+			subnetAllocationCheck(ncc.watchFactory, ncc.hybridOverlaySubnetAllocator),
+			ncc.hybridOverlaySubnetAllocator.ReleaseAllNodeSubnets)
+	}
+}
+
+// removeAllocationAudits removes the audits added by addAllocationAudits, and their metrics for the secondary
+// networks, which may be deleted for good
+func (ncc *networkClusterController) removeAllocationAudits() {
+	if ncc.allocationAuditor == nil || !ncc.hasNodeSubnets() {
+		return
+	}
+	ncc.allocationAuditor.Remove(ncc.nodeSubnetsAuditName())
+	if ncc.enableHybridOverlaySubnetAllocator {
+		ncc.allocationAuditor.Remove(hybridOverlaySubnetsAuditName)
+	}
+	if ncc.IsSecondary() {
+		metrics.DeleteLeakedAllocations(ncc.nodeSubnetsAuditName())
+	}
+}
+
+// subnetAllocationCheck returns a check of the owners of the subnets of the given allocator against the nodes
+func subnetAllocationCheck(wf *factory.WatchFactory, allocator *subnetallocator.HostSubnetAllocator) allocationauditor.Check {
+	return func() ([]string, sets.String, error) {
+		owners := allocator.Owners()
+		nodes, err := nodeNames(wf)
+		return owners, nodes, err
+	}
+}
+
+// nodeIDsCheck returns the owners of the node ids and the nodes
+func (zcc *zoneClusterController) nodeIDsCheck() ([]string, sets.String, error) {
+	owners := []string{}
+	for _, owner := range zcc.nodeIDAllocator.owners() {
+		if !reservedNodeIDOwners.Has(owner) {
+			owners = append(owners, owner)
+		}
+	}
+	nodes, err := nodeNames(zcc.watchFactory)
+	return owners, nodes, err
+}
+
+func nodeNames(wf *factory.WatchFactory) (sets.String, error) {
+	nodes, err := wf.GetNodes()
+	if err != nil {
+		return nil, err
+	}
+	names := sets.NewString()
+	for _, node := range nodes {
+		names.Insert(node.Name)
+	}
+	return names, nil
+}
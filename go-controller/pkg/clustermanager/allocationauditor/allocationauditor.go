@@ -0,0 +1,170 @@
+// Package allocationauditor finds the allocations of the cluster manager, e.g.
+// node subnets or node IDs, whose owner no longer exists, typically left
+// behind by a missed delete event in a long-lived cluster. Allocators are added
+// with a check that returns the owners of their allocations and the owners that
+// still exist; every audit reports the leaked allocations and, on request,
+// releases them.
+package allocationauditor
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+)
+
+// Check returns the owners of the allocations of one allocator and the owners
+// that still exist. The allocations must be listed before the existing owners,
+// so that an owner added in between is never reported as leaked.
+type Check func() (allocated []string, existing sets.String, err error)
+
+// ReleaseFunc releases the allocations of the given owner
+type ReleaseFunc func(owner string)
+
+// Result is the result of the audit of one allocator
+type Result struct {
+	Allocator string
+	// Leaked holds the owners of the allocations that no longer exist
+	Leaked []string
+	// Reclaimed is true if the leaked allocations were released
+	Reclaimed bool
+}
+
+func (r *Result) String() string {
+	if r.Reclaimed {
+		return fmt.Sprintf("%s: reclaimed %v", r.Allocator, r.Leaked)
+	}
+	return fmt.Sprintf("%s: leaked %v", r.Allocator, r.Leaked)
+}
+
+type allocator struct {
+	check   Check
+	release ReleaseFunc
+}
+
+// Auditor holds the audited allocators and serves the on demand audits
+type Auditor struct {
+	lock       sync.Mutex
+	allocators map[string]*allocator
+	record     func(*Result)
+}
+
+// New returns an Auditor without allocators that passes the result of every
+// audit of an allocator to record, if not nil
+func New(record func(*Result)) *Auditor {
+	return &Auditor{allocators: map[string]*allocator{}, record: record}
+}
+
+// Add adds the given allocator to the audits, replacing any previous
+// allocator with the same name
+func (a *Auditor) Add(name string, check Check, release ReleaseFunc) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.allocators[name] = &allocator{check: check, release: release}
+}
+
+// Remove removes the given allocator, typically when its controller is
+// stopped
+func (a *Auditor) Remove(name string) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	delete(a.allocators, name)
+}
+
+// Audit runs the checks of all the allocators and returns their results
+// sorted by allocator name, releasing the leaked allocations if reclaim is
+// set. Checks that fail are skipped and reported in the returned error.
+func (a *Auditor) Audit(reclaim bool) ([]*Result, error) {
+	a.lock.Lock()
+	names := make([]string, 0, len(a.allocators))
+	toRun := make(map[string]*allocator, len(a.allocators))
+	for name, alloc := range a.allocators {
+		names = append(names, name)
+		toRun[name] = alloc
+	}
+	a.lock.Unlock()
+	sort.Strings(names)
+
+	results := make([]*Result, 0, len(names))
+	var failed []string
+	for _, name := range names {
+		alloc := toRun[name]
+		allocated, existing, err := alloc.check()
+		if err != nil {
+			klog.Errorf("Allocation audit check %s failed: %v", name, err)
+			failed = append(failed, name)
+			continue
+		}
+		result := &Result{Allocator: name}
+		for _, owner := range allocated {
+			if !existing.Has(owner) {
+				result.Leaked = append(result.Leaked, owner)
+			}
+		}
+		sort.Strings(result.Leaked)
+		if len(result.Leaked) > 0 {
+			if reclaim {
+				for _, owner := range result.Leaked {
+					alloc.release(owner)
+				}
+				result.Reclaimed = true
+				klog.Infof("Allocation audit %s", result)
+			} else {
+				klog.Warningf("Allocation audit found %s", result)
+			}
+		}
+		if a.record != nil {
+			a.record(result)
+		}
+		results = append(results, result)
+	}
+	if len(failed) > 0 {
+		return results, fmt.Errorf("allocation audit checks %s failed", strings.Join(failed, ", "))
+	}
+	return results, nil
+}
+
+// Run audits the allocations every interval until stopCh is closed. The
+// leaked allocations are only reported.
+func (a *Auditor) Run(interval time.Duration, stopCh <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := a.Audit(false); err != nil {
+				klog.Error(err)
+			}
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// ServeHTTP serves on demand audits: a GET reports the leaked allocations of
+// every allocator, one allocator per line, and a POST also releases them
+func (a *Auditor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var reclaim bool
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		reclaim = true
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	results, err := a.Audit(reclaim)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, result := range results {
+		fmt.Fprintln(w, result)
+	}
+}
@@ -0,0 +1,87 @@
+package allocationauditor
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// testAllocator is an allocator of one id per owner
+type testAllocator struct {
+	owners sets.String
+	nodes  sets.String
+}
+
+func (a *testAllocator) check() ([]string, sets.String, error) {
+	return a.owners.List(), a.nodes, nil
+}
+
+func (a *testAllocator) release(owner string) {
+	a.owners.Delete(owner)
+}
+
+func TestAudit(t *testing.T) {
+	var recorded []string
+	auditor := New(func(result *Result) {
+		recorded = append(recorded, result.String())
+	})
+	subnets := &testAllocator{owners: sets.NewString("node1", "node2", "node3"), nodes: sets.NewString("node1", "node4")}
+	auditor.Add("subnets", subnets.check, subnets.release)
+	ids := &testAllocator{owners: sets.NewString("node1"), nodes: sets.NewString("node1", "node4")}
+	auditor.Add("ids", ids.check, ids.release)
+
+	results, err := auditor.Audit(false)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Result{
+		{Allocator: "ids"},
+		{Allocator: "subnets", Leaked: []string{"node2", "node3"}},
+	}, results)
+	assert.Equal(t, []string{"node1", "node2", "node3"}, subnets.owners.List())
+
+	results, err = auditor.Audit(true)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Result{
+		{Allocator: "ids"},
+		{Allocator: "subnets", Leaked: []string{"node2", "node3"}, Reclaimed: true},
+	}, results)
+	assert.Equal(t, []string{"node1"}, subnets.owners.List())
+	assert.Equal(t, []string{"ids: leaked []", "subnets: leaked [node2 node3]",
+		"ids: leaked []", "subnets: reclaimed [node2 node3]"}, recorded)
+
+	auditor.Add("failing", func() ([]string, sets.String, error) {
+		return nil, nil, fmt.Errorf("boom")
+	}, func(string) {})
+	results, err = auditor.Audit(false)
+	assert.Error(t, err)
+	assert.Len(t, results, 2)
+
+	auditor.Remove("failing")
+	_, err = auditor.Audit(false)
+	assert.NoError(t, err)
+}
+
+func TestServeHTTP(t *testing.T) {
+	auditor := New(nil)
+	subnets := &testAllocator{owners: sets.NewString("node1", "node2"), nodes: sets.NewString("node1")}
+	auditor.Add("subnets", subnets.check, subnets.release)
+
+	w := httptest.NewRecorder()
+	auditor.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/allocations", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "subnets: leaked [node2]\n", w.Body.String())
+	assert.True(t, subnets.owners.Has("node2"))
+
+	w = httptest.NewRecorder()
+	auditor.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/debug/allocations", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "subnets: reclaimed [node2]\n", w.Body.String())
+	assert.False(t, subnets.owners.Has("node2"))
+
+	w = httptest.NewRecorder()
+	auditor.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/debug/allocations", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
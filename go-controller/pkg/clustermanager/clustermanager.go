@@ -9,6 +9,7 @@ import (
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/clustermanager/allocationauditor"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/metrics"
@@ -31,6 +32,8 @@ type ClusterManager struct {
 	wf                          *factory.WatchFactory
 	wg                          *sync.WaitGroup
 	secondaryNetClusterManager  *secondaryNetworkClusterManager
+	// allocationAuditor audits the node ids and the node subnets of all the networks
+	allocationAuditor *allocationauditor.Auditor
 	// event recorder used to post events to k8s
 	recorder record.EventRecorder
 
@@ -42,8 +45,9 @@ type ClusterManager struct {
 // NewClusterManager creates a new cluster manager to manage the cluster nodes.
 func NewClusterManager(ovnClient *util.OVNClusterManagerClientset, wf *factory.WatchFactory,
 	identity string, wg *sync.WaitGroup, recorder record.EventRecorder) (*ClusterManager, error) {
+	allocationAuditor := allocationauditor.New(recordLeakedAllocations)
 	defaultNetClusterController := newNetworkClusterController(ovntypes.DefaultNetworkName, defaultNetworkID, config.Default.ClusterSubnets,
-		ovnClient, wf, config.HybridOverlay.Enabled, &util.DefaultNetInfo{})
+		ovnClient, wf, config.HybridOverlay.Enabled, &util.DefaultNetInfo{}, allocationAuditor)
 
 	zoneClusterController, err := newZoneClusterController(ovnClient, wf)
 	if err != nil {
@@ -58,10 +62,11 @@ func NewClusterManager(ovnClient *util.OVNClusterManagerClientset, wf *factory.W
 		wf:                          wf,
 		recorder:                    recorder,
 		identity:                    identity,
+		allocationAuditor:           allocationAuditor,
 	}
 
 	if config.OVNKubernetesFeature.EnableMultiNetwork {
-		cm.secondaryNetClusterManager, err = newSecondaryNetworkClusterManager(ovnClient, wf, recorder)
+		cm.secondaryNetClusterManager, err = newSecondaryNetworkClusterManager(ovnClient, wf, recorder, allocationAuditor)
 		if err != nil {
 			return nil, err
 		}
@@ -93,12 +98,15 @@ func (cm *ClusterManager) Start(ctx context.Context) error {
 		}
 	}
 
+	cm.startAllocationAudit(ctx.Done())
+
 	return nil
 }
 
 // Stop the cluster manager.
 func (cm *ClusterManager) Stop() {
 	klog.Info("Stopping the cluster manager")
+	cm.stopAllocationAudit()
 	cm.defaultNetClusterController.Stop()
 	cm.zoneClusterController.Stop()
 	if config.OVNKubernetesFeature.EnableMultiNetwork {
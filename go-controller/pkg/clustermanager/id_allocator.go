@@ -77,3 +77,13 @@ func (idAllocator *idAllocator) releaseID(name string) {
 		idAllocator.nameIdMap.Delete(name)
 	}
 }
+
+// owners returns the names of the resources that have an id allocated
+func (idAllocator *idAllocator) owners() []string {
+	owners := []string{}
+	idAllocator.nameIdMap.Range(func(name, _ interface{}) bool {
+		owners = append(owners, name.(string))
+		return true
+	})
+	return owners
+}
@@ -0,0 +1,64 @@
+package clustermanager
+
+import (
+	"fmt"
+	"net"
+	"sync"
+)
+
+// macAllocator keeps track of the MAC addresses reserved by the resources of a network, so a MAC address is never
+// used by two resources of the same network
+type macAllocator struct {
+	sync.Mutex
+	nameMacMap map[string]string
+	macNameMap map[string]string
+}
+
+// newMACAllocator returns a macAllocator
+func newMACAllocator() *macAllocator {
+	return &macAllocator{
+		nameMacMap: map[string]string{},
+		macNameMap: map[string]string{},
+	}
+}
+
+// reserveMAC reserves the MAC address 'mac' for the resource 'name'. It returns an
+// error if 'mac' is already reserved by a resource other than 'name'. It also
+// returns an error if the resource 'name' has a different MAC already reserved.
+func (macAllocator *macAllocator) reserveMAC(name string, mac net.HardwareAddr) error {
+	macAllocator.Lock()
+	defer macAllocator.Unlock()
+	macStr := mac.String()
+	if reserved, ok := macAllocator.nameMacMap[name]; ok {
+		if reserved == macStr {
+			// All good. The MAC is already reserved by the same resource name.
+			return nil
+		}
+		return fmt.Errorf("can't reserve MAC %s for the resource %s. It is already allocated with a different MAC %s",
+			macStr, name, reserved)
+	}
+	if owner, ok := macAllocator.macNameMap[macStr]; ok {
+		return fmt.Errorf("MAC %s is already reserved by the resource %s", macStr, owner)
+	}
+	macAllocator.nameMacMap[name] = macStr
+	macAllocator.macNameMap[macStr] = name
+	return nil
+}
+
+// releaseMAC releases the MAC address reserved for the resource 'name'
+func (macAllocator *macAllocator) releaseMAC(name string) {
+	macAllocator.Lock()
+	defer macAllocator.Unlock()
+	if mac, ok := macAllocator.nameMacMap[name]; ok {
+		delete(macAllocator.macNameMap, mac)
+		delete(macAllocator.nameMacMap, name)
+	}
+}
+
+// isReserved returns true if the resource 'name' has a MAC reserved
+func (macAllocator *macAllocator) isReserved(name string) bool {
+	macAllocator.Lock()
+	defer macAllocator.Unlock()
+	_, ok := macAllocator.nameMacMap[name]
+	return ok
+}
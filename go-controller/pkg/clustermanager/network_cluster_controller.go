@@ -7,18 +7,24 @@ import (
 	"reflect"
 	"sync"
 
+	networkattchmentdefclientset "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	cache "k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 
 	hotypes "github.com/ovn-org/ovn-kubernetes/go-controller/hybrid-overlay/pkg/types"
 	houtil "github.com/ovn-org/ovn-kubernetes/go-controller/hybrid-overlay/pkg/util"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/clustermanager/allocationauditor"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/clustermanager/subnetallocator"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	ipamclaimsapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/ipamclaims/v1alpha1"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/kube"
 	objretry "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/retry"
+	ovntypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 )
 
@@ -26,12 +32,17 @@ import (
 // An instance of this struct is expected to be created for each network.
 // A network is identified by its name and its unique id.
 // It listens to the node events and does the following.
-//   - allocates subnet from the cluster subnet pool. It also allocates subnets
-//     from the hybrid overlay subnet pool if hybrid overlay is enabled.
+//   - allocates subnet from the cluster subnet pool, for the networks with a
+//     subnet per node. It also allocates subnets from the hybrid overlay subnet
+//     pool if hybrid overlay is enabled.
 //     It stores these allocated subnets in the node annotation
 //   - stores the network id in each node's annotation.
+//
+// For the secondary networks spanning all the nodes, it also listens to the pod events and allocates the pod
+// addresses when interconnect is enabled, see podAllocator.
 type networkClusterController struct {
-	kube         kube.Interface
+	kube         kube.InterfaceOVN
+	nadClient    networkattchmentdefclientset.Interface
 	watchFactory *factory.WatchFactory
 	stopChan     chan struct{}
 	wg           *sync.WaitGroup
@@ -42,6 +53,21 @@ type networkClusterController struct {
 	// retry framework for nodes
 	retryNodes *objretry.RetryFramework
 
+	// pod events factory handler
+	podHandler *factory.Handler
+
+	// retry framework for pods, only set when the controller allocates the pod addresses
+	retryPods *objretry.RetryFramework
+
+	// IPAMClaim events factory handler
+	ipamClaimsHandler *factory.Handler
+
+	// retry framework for IPAMClaims, only set when the pods of the network can keep their IPs in IPAMClaims
+	retryIPAMClaims *objretry.RetryFramework
+
+	// podAllocator allocates the pod addresses of the network, nil if the zone controllers allocate them
+	podAllocator *podAllocator
+
 	// name of the network
 	networkName string
 	// unique id of the network
@@ -53,15 +79,19 @@ type networkClusterController struct {
 	enableHybridOverlaySubnetAllocator bool
 	hybridOverlaySubnetAllocator       *subnetallocator.HostSubnetAllocator
 
+	// allocationAuditor audits the node subnets of the network, nil if they are not audited
+	allocationAuditor *allocationauditor.Auditor
+
 	util.NetInfo
 }
 
 func newNetworkClusterController(networkName string, networkID int, clusterSubnets []config.CIDRNetworkEntry,
 	ovnClient *util.OVNClusterManagerClientset, wf *factory.WatchFactory,
-	enableHybridOverlaySubnetAllocator bool, netInfo util.NetInfo) *networkClusterController {
+	enableHybridOverlaySubnetAllocator bool, netInfo util.NetInfo, allocationAuditor *allocationauditor.Auditor) *networkClusterController {
 
-	kube := &kube.Kube{
-		KClient: ovnClient.KubeClient,
+	kube := &kube.KubeOVN{
+		Kube:             kube.Kube{KClient: ovnClient.KubeClient},
+		IPAMClaimsClient: ovnClient.IPAMClaimsClient,
 	}
 
 	wg := &sync.WaitGroup{}
@@ -72,6 +102,7 @@ func newNetworkClusterController(networkName string, networkID int, clusterSubne
 	}
 	ncc := &networkClusterController{
 		kube:                               kube,
+		nadClient:                          ovnClient.NetworkAttchDefClient,
 		watchFactory:                       wf,
 		stopChan:                           make(chan struct{}),
 		wg:                                 wg,
@@ -81,6 +112,7 @@ func newNetworkClusterController(networkName string, networkID int, clusterSubne
 		clusterSubnets:                     clusterSubnets,
 		hybridOverlaySubnetAllocator:       hybridOverlaySubnetAllocator,
 		enableHybridOverlaySubnetAllocator: enableHybridOverlaySubnetAllocator,
+		allocationAuditor:                  allocationAuditor,
 		NetInfo:                            netInfo,
 	}
 
@@ -90,13 +122,24 @@ func newNetworkClusterController(networkName string, networkID int, clusterSubne
 
 func (ncc *networkClusterController) initRetryFramework() {
 	ncc.retryNodes = ncc.newRetryFramework(factory.NodeType, true)
+
+	if ncc.hasPodAllocation() {
+		ncc.podAllocator = newPodAllocator(ncc.NetInfo, ncc.networkID, ncc.kube, ncc.watchFactory)
+		ncc.retryPods = ncc.newRetryFramework(factory.PodType, true)
+		if util.IsPersistentIPsEnabled() && ncc.AllowsPersistentIPs() {
+			ncc.retryIPAMClaims = ncc.newRetryFramework(factory.IPAMClaimsType, false)
+		}
+	}
 }
 
 // Start the network cluster controller
 // It does the following
 //   - initializes the network subnet allocator ranges
 //     and hybrid network subnet allocator ranges if hybrid overlay is enabled.
+//   - adds the node subnets of the network to the allocation audits
 //   - Starts watching the kubernetes nodes
+//   - Starts watching the kubernetes pods, if the pod addresses of the network are allocated here, after the
+//     IPAMClaims so that the claimed IPs are not handed out to other pods
 func (ncc *networkClusterController) Start(ctx context.Context) error {
 	if err := ncc.clusterSubnetAllocator.InitRanges(ncc.clusterSubnets); err != nil {
 		return fmt.Errorf("failed to initialize cluster subnet allocator ranges: %w", err)
@@ -108,6 +151,8 @@ func (ncc *networkClusterController) Start(ctx context.Context) error {
 		}
 	}
 
+	ncc.addAllocationAudits()
+
 	nodeHandler, err := ncc.retryNodes.WatchResource()
 
 	if err != nil {
@@ -115,16 +160,41 @@ func (ncc *networkClusterController) Start(ctx context.Context) error {
 	}
 
 	ncc.nodeHandler = nodeHandler
-	return err
+
+	if ncc.podAllocator != nil {
+		if err := ncc.podAllocator.init(); err != nil {
+			return fmt.Errorf("failed to initialize the pod allocator: %w", err)
+		}
+		if ncc.retryIPAMClaims != nil {
+			ipamClaimsHandler, err := ncc.retryIPAMClaims.WatchResource()
+			if err != nil {
+				return fmt.Errorf("unable to watch IPAMClaims: %w", err)
+			}
+			ncc.ipamClaimsHandler = ipamClaimsHandler
+		}
+		podHandler, err := ncc.retryPods.WatchResource()
+		if err != nil {
+			return fmt.Errorf("unable to watch pods: %w", err)
+		}
+		ncc.podHandler = podHandler
+	}
+	return nil
 }
 
 func (ncc *networkClusterController) Stop() {
 	close(ncc.stopChan)
 	ncc.wg.Wait()
+	ncc.removeAllocationAudits()
 
 	if ncc.nodeHandler != nil {
 		ncc.watchFactory.RemoveNodeHandler(ncc.nodeHandler)
 	}
+	if ncc.podHandler != nil {
+		ncc.watchFactory.RemovePodHandler(ncc.podHandler)
+	}
+	if ncc.ipamClaimsHandler != nil {
+		ncc.watchFactory.RemoveIPAMClaimsHandler(ncc.ipamClaimsHandler)
+	}
 }
 
 func (ncc *networkClusterController) newRetryFramework(objectType reflect.Type, hasUpdateFunc bool) *objretry.RetryFramework {
@@ -207,16 +277,23 @@ func (ncc *networkClusterController) syncNodeNetworkAnnotations(node *corev1.Nod
 	ncc.clusterSubnetAllocator.Lock()
 	defer ncc.clusterSubnetAllocator.Unlock()
 
-	existingSubnets, err := util.ParseNodeHostSubnetAnnotation(node, ncc.networkName)
+	networkID, err := util.ParseNetworkIDAnnotation(node, ncc.networkName)
 	if err != nil && !util.IsAnnotationNotSetError(err) {
 		// Log the error and try to allocate new subnets
-		klog.Warningf("Failed to get node %s host subnets annotations for network %s : %v", node.Name, ncc.networkName, err)
+		klog.Warningf("Failed to get node %s network id annotations for network %s : %v", node.Name, ncc.networkName, err)
 	}
 
-	networkID, err := util.ParseNetworkIDAnnotation(node, ncc.networkName)
+	if !ncc.hasNodeSubnets() {
+		if ncc.networkID != networkID {
+			return ncc.updateNodeNetworkAnnotationsWithRetry(node.Name, nil, ncc.networkID)
+		}
+		return nil
+	}
+
+	existingSubnets, err := util.ParseNodeHostSubnetAnnotation(node, ncc.networkName)
 	if err != nil && !util.IsAnnotationNotSetError(err) {
 		// Log the error and try to allocate new subnets
-		klog.Warningf("Failed to get node %s network id annotations for network %s : %v", node.Name, ncc.networkName, err)
+		klog.Warningf("Failed to get node %s host subnets annotations for network %s : %v", node.Name, ncc.networkName, err)
 	}
 
 	// On return validExistingSubnets will contain any valid subnets that
@@ -249,6 +326,19 @@ func (ncc *networkClusterController) syncNodeNetworkAnnotations(node *corev1.Nod
 	return nil
 }
 
+// hasNodeSubnets returns true if a subnet is allocated to every node for the network, false for the networks
+// spanning all the nodes, for which only the network id is annotated on the nodes
+func (ncc *networkClusterController) hasNodeSubnets() bool {
+	return !ncc.IsSecondary() || ncc.TopologyType() == ovntypes.Layer3Topology
+}
+
+// hasPodAllocation returns true if the pod addresses of the network are allocated here rather than by the zone
+// controllers: the secondary networks spanning all the nodes get their pod addresses allocated cluster wide when
+// the nodes are split in interconnected zones
+func (ncc *networkClusterController) hasPodAllocation() bool {
+	return config.OVNKubernetesFeature.EnableInterconnect && !ncc.hasNodeSubnets()
+}
+
 // handleDeleteNode handles the delete node event
 func (ncc *networkClusterController) handleDeleteNode(node *corev1.Node) error {
 	if ncc.enableHybridOverlaySubnetAllocator {
@@ -335,6 +425,12 @@ func (ncc *networkClusterController) updateNodeNetworkAnnotationsWithRetry(nodeN
 	return nil
 }
 
+// Reconfigure reconfigures the secondary network with the updated network information, its subnets, and so the
+// allocations of its nodes and pods, are unchanged
+func (ncc *networkClusterController) Reconfigure(netInfo util.NetInfo) error {
+	return ncc.NetInfo.Reconfigure(netInfo)
+}
+
 // Cleanup the subnet annotations from the node for the secondary networks
 func (ncc *networkClusterController) Cleanup(netName string) error {
 	if !ncc.IsSecondary() {
@@ -365,6 +461,92 @@ func (ncc *networkClusterController) Cleanup(netName string) error {
 		ncc.clusterSubnetAllocator.ReleaseAllNodeSubnets(node.Name)
 	}
 
+	return ncc.cleanupStalePodNetworks()
+}
+
+// cleanupStalePodNetworks removes from the pods' network annotation the entries of the NADs that no longer exist, so
+// that the pods don't keep dangling references to a removed network that would confuse the CNI when their sandbox
+// is recreated. The pods are only watched when the cluster manager allocates the pod addresses, with interconnect.
+func (ncc *networkClusterController) cleanupStalePodNetworks() error {
+	if !config.OVNKubernetesFeature.EnableInterconnect || ncc.nadClient == nil {
+		return nil
+	}
+	pods, err := ncc.watchFactory.GetAllPods()
+	if err != nil {
+		return fmt.Errorf("error in retrieving the pods: %v", err)
+	}
+
+	// cache the existence of the NADs, pods commonly share them
+	nadExists := map[string]bool{}
+	isStaleNAD := func(nadName string) (bool, error) {
+		if exists, ok := nadExists[nadName]; ok {
+			return !exists, nil
+		}
+		namespace, name, err := cache.SplitMetaNamespaceKey(nadName)
+		if err != nil {
+			return false, err
+		}
+		_, err = ncc.nadClient.K8sCniCncfIoV1().NetworkAttachmentDefinitions(namespace).Get(context.TODO(), name,
+			metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return false, fmt.Errorf("failed to get NAD %s: %w", nadName, err)
+		}
+		nadExists[nadName] = err == nil
+		return !nadExists[nadName], nil
+	}
+
+	for _, pod := range pods {
+		podNetworks, err := util.UnmarshalPodAnnotationAllNetworks(pod.Annotations)
+		if err != nil {
+			klog.Warningf("Failed to parse the network annotation of pod %s/%s: %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+		staleNADs := []string{}
+		for nadName := range podNetworks {
+			if nadName == ovntypes.DefaultNetworkName {
+				continue
+			}
+			stale, err := isStaleNAD(nadName)
+			if err != nil {
+				return err
+			}
+			if stale {
+				staleNADs = append(staleNADs, nadName)
+			}
+		}
+		if len(staleNADs) == 0 {
+			continue
+		}
+		klog.Infof("Remove the network annotation of the deleted NADs %v from pod %s/%s", staleNADs, pod.Namespace,
+			pod.Name)
+		if err := ncc.deletePodNetworksWithRetry(pod, staleNADs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ncc *networkClusterController) deletePodNetworksWithRetry(origPod *corev1.Pod, nadNames []string) error {
+	resultErr := retry.RetryOnConflict(util.OvnConflictBackoff, func() error {
+		// Informer cache should not be mutated, so get a copy of the object
+		pod, err := ncc.watchFactory.GetPod(origPod.Namespace, origPod.Name)
+		if err != nil {
+			return err
+		}
+
+		cpod := pod.DeepCopy()
+		cpod.Annotations, err = util.DeletePodAnnotationNetworks(cpod.Annotations, nadNames...)
+		if err != nil {
+			return err
+		}
+		return ncc.kube.UpdatePod(cpod)
+	})
+	if resultErr != nil {
+		if apierrors.IsNotFound(resultErr) {
+			return nil
+		}
+		return fmt.Errorf("failed to update annotation on pod %s/%s: %v", origPod.Namespace, origPod.Name, resultErr)
+	}
 	return nil
 }
 
@@ -396,6 +578,18 @@ func (h *networkClusterControllerEventHandler) AddResource(obj interface{}, from
 				node.Name, err)
 			return err
 		}
+	case factory.PodType:
+		pod, ok := obj.(*corev1.Pod)
+		if !ok {
+			return fmt.Errorf("could not cast %T object to *corev1.Pod", obj)
+		}
+		return h.ncc.podAllocator.addUpdatePod(pod)
+	case factory.IPAMClaimsType:
+		claim, ok := obj.(*ipamclaimsapi.IPAMClaim)
+		if !ok {
+			return fmt.Errorf("could not cast %T object to *ipamclaimsapi.IPAMClaim", obj)
+		}
+		return h.ncc.podAllocator.addIPAMClaim(claim)
 	default:
 		return fmt.Errorf("no add function for object type %s", h.objType)
 	}
@@ -419,6 +613,12 @@ func (h *networkClusterControllerEventHandler) UpdateResource(oldObj, newObj int
 				node.Name, err)
 			return err
 		}
+	case factory.PodType:
+		pod, ok := newObj.(*corev1.Pod)
+		if !ok {
+			return fmt.Errorf("could not cast %T object to *corev1.Pod", newObj)
+		}
+		return h.ncc.podAllocator.addUpdatePod(pod)
 	default:
 		return fmt.Errorf("no update function for object type %s", h.objType)
 	}
@@ -435,6 +635,18 @@ func (h *networkClusterControllerEventHandler) DeleteResource(obj, cachedObj int
 			return fmt.Errorf("could not cast obj of type %T to *knet.Node", obj)
 		}
 		return h.ncc.handleDeleteNode(node)
+	case factory.PodType:
+		pod, ok := obj.(*corev1.Pod)
+		if !ok {
+			return fmt.Errorf("could not cast obj of type %T to *corev1.Pod", obj)
+		}
+		return h.ncc.podAllocator.deletePod(pod)
+	case factory.IPAMClaimsType:
+		claim, ok := obj.(*ipamclaimsapi.IPAMClaim)
+		if !ok {
+			return fmt.Errorf("could not cast obj of type %T to *ipamclaimsapi.IPAMClaim", obj)
+		}
+		return h.ncc.podAllocator.deleteIPAMClaim(claim)
 	}
 	return nil
 }
@@ -450,6 +662,13 @@ func (h *networkClusterControllerEventHandler) SyncFunc(objs []interface{}) erro
 		case factory.NodeType:
 			syncFunc = h.ncc.syncNodes
 
+		case factory.PodType:
+			syncFunc = h.ncc.podAllocator.syncPods
+
+		case factory.IPAMClaimsType:
+			// the IPs of the existing claims are reserved by their add events
+			syncFunc = nil
+
 		default:
 			return fmt.Errorf("no sync function for object type %s", h.objType)
 		}
@@ -479,13 +698,19 @@ func (h *networkClusterControllerEventHandler) RecordSuccessEvent(obj interface{
 func (h *networkClusterControllerEventHandler) RecordErrorEvent(obj interface{}, reason string, err error) {
 }
 
-// isResourceScheduled returns true if the object has been scheduled.  Always returns true.
+// isResourceScheduled returns true if the object has been scheduled.  Always returns true but for unscheduled pods.
 func (h *networkClusterControllerEventHandler) IsResourceScheduled(obj interface{}) bool {
+	if h.objType == factory.PodType {
+		return util.PodScheduled(obj.(*corev1.Pod))
+	}
 	return true
 }
 
-// IsObjectInTerminalState returns true if the object is a in terminal state.  Always returns true.
+// IsObjectInTerminalState returns true if the object is a in terminal state, i.e. a completed pod.
 func (h *networkClusterControllerEventHandler) IsObjectInTerminalState(obj interface{}) bool {
+	if h.objType == factory.PodType {
+		return util.PodCompleted(obj.(*corev1.Pod))
+	}
 	return false
 }
 
@@ -518,10 +743,10 @@ func (h *networkClusterControllerEventHandler) GetInternalCacheEntry(obj interfa
 // given an object key and its type
 func (h *networkClusterControllerEventHandler) GetResourceFromInformerCache(key string) (interface{}, error) {
 	var obj interface{}
-	var name string
+	var namespace, name string
 	var err error
 
-	_, name, err = cache.SplitMetaNamespaceKey(key)
+	namespace, name, err = cache.SplitMetaNamespaceKey(key)
 	if err != nil {
 		return nil, fmt.Errorf("failed to split key %s: %v", key, err)
 	}
@@ -531,6 +756,12 @@ func (h *networkClusterControllerEventHandler) GetResourceFromInformerCache(key
 		factory.EgressNodeType:
 		obj, err = h.ncc.watchFactory.GetNode(name)
 
+	case factory.PodType:
+		obj, err = h.ncc.watchFactory.GetPod(namespace, name)
+
+	case factory.IPAMClaimsType:
+		obj, err = h.ncc.watchFactory.GetIPAMClaim(namespace, name)
+
 	default:
 		err = fmt.Errorf("object type %s not supported, cannot retrieve it from informers cache",
 			h.objType)
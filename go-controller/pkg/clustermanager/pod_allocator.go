@@ -0,0 +1,476 @@
+package clustermanager
+
+import (
+	"fmt"
+	"net"
+
+	nadapi "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	ipamclaimsapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/ipamclaims/v1alpha1"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/kube"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/ipallocator"
+	lsm "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/logical_switch_manager"
+	ovntypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+)
+
+const (
+	// Maximum pod tunnel ids of a layer2 network: OVN carries the logical port tunnel keys on 15 bits in the
+	// geneve encapsulation.
+	maxPodTunnelIDs = 1 << 15
+)
+
+// podAllocator allocates the addresses of the pods attached to a secondary network spanning all the nodes, i.e. a
+// layer2 or localnet network, when interconnect is enabled. Such a network spans all the zones, so its pod IPs are
+// allocated cluster wide and annotated on the pods, where the zone controllers read them. The pods of layer2 networks
+// also get a tunnel id, the tunnel key of their port on the network switch which all the zones share as a transit
+// switch. Pods referencing an IPAMClaim keep the IPs stored in the claim across their recreation. The pod MACs are
+// tracked so that a MAC requested in the pod network selection element can't collide with the MAC of another pod.
+type podAllocator struct {
+	util.NetInfo
+	networkID    int
+	kube         kube.InterfaceOVN
+	watchFactory *factory.WatchFactory
+
+	// ipAllocator allocates the pod IPs from the network subnets, nil if the network has no IPAM
+	ipAllocator *lsm.LogicalSwitchManager
+	// tunnelIDAllocator allocates the pod tunnel ids, nil for localnet networks
+	tunnelIDAllocator *idAllocator
+	// macAllocator tracks the MACs of the pods of the network
+	macAllocator *macAllocator
+}
+
+func newPodAllocator(netInfo util.NetInfo, networkID int, kube kube.InterfaceOVN, wf *factory.WatchFactory) *podAllocator {
+	return &podAllocator{
+		NetInfo:      netInfo,
+		networkID:    networkID,
+		kube:         kube,
+		watchFactory: wf,
+	}
+}
+
+// init sets up the IP, tunnel id and MAC allocators of the network. The excluded subnets of the network are never
+// allocated, and the tunnel id 0 is not a valid tunnel key.
+func (pa *podAllocator) init() error {
+	pa.macAllocator = newMACAllocator()
+	if len(pa.Subnets()) > 0 {
+		pa.ipAllocator = lsm.NewL2SwitchManager()
+		subnets := make([]*net.IPNet, 0, len(pa.Subnets()))
+		for _, subnet := range pa.Subnets() {
+			subnets = append(subnets, subnet.CIDR)
+		}
+		if err := pa.ipAllocator.AddSwitch(pa.GetNetworkName(), "", subnets); err != nil {
+			return fmt.Errorf("failed to initialize the IP allocator of network %s: %w", pa.GetNetworkName(), err)
+		}
+		if err := pa.ipAllocator.ExcludeSubnets(pa.GetNetworkName(), pa.ExcludeSubnets()); err != nil {
+			return fmt.Errorf("failed to exclude subnets of network %s: %w", pa.GetNetworkName(), err)
+		}
+	}
+
+	if pa.TopologyType() == ovntypes.Layer2Topology {
+		tunnelIDAllocator, err := NewIDAllocator(pa.GetNetworkName()+"-TunnelIDs", maxPodTunnelIDs)
+		if err != nil {
+			return fmt.Errorf("failed to create the tunnel id allocator of network %s: %w", pa.GetNetworkName(), err)
+		}
+		if err := tunnelIDAllocator.reserveID("zero", 0); err != nil {
+			return fmt.Errorf("failed to reserve the tunnel id 0 of network %s: %w", pa.GetNetworkName(), err)
+		}
+		pa.tunnelIDAllocator = tunnelIDAllocator
+	}
+	return nil
+}
+
+// syncPods reserves the IPs and tunnel ids already annotated on the existing pods of the network
+func (pa *podAllocator) syncPods(pods []interface{}) error {
+	for _, podInterface := range pods {
+		pod, ok := podInterface.(*corev1.Pod)
+		if !ok {
+			return fmt.Errorf("spurious object in syncPods: %v", podInterface)
+		}
+		if !util.PodScheduled(pod) || util.PodCompleted(pod) {
+			continue
+		}
+		on, networkMap, err := util.GetPodNADToNetworkMapping(pod, pa.NetInfo)
+		if err != nil || !on {
+			continue
+		}
+		for nadName := range networkMap {
+			podAnnotation, err := util.UnmarshalPodAnnotation(pod.Annotations, nadName)
+			if err != nil {
+				continue
+			}
+			portName := util.GetSecondaryNetworkLogicalPortName(pod.Namespace, pod.Name, nadName)
+			if pa.ipAllocator != nil && len(podAnnotation.IPs) > 0 {
+				// the IPs of a pod referencing an IPAMClaim are already reserved by the claim
+				err := pa.ipAllocator.AllocateIPs(pa.GetNetworkName(), podAnnotation.IPs)
+				if err != nil && err != ipallocator.ErrAllocated {
+					klog.Errorf("Failed to reserve IPs %s of pod %s on network %s: %v",
+						util.JoinIPNetIPs(podAnnotation.IPs, " "), portName, pa.GetNetworkName(), err)
+				}
+			}
+			if pa.tunnelIDAllocator != nil && podAnnotation.TunnelID != 0 {
+				if err := pa.tunnelIDAllocator.reserveID(portName, podAnnotation.TunnelID); err != nil {
+					klog.Errorf("Failed to reserve tunnel id %d of pod %s on network %s: %v",
+						podAnnotation.TunnelID, portName, pa.GetNetworkName(), err)
+				}
+			}
+			if len(podAnnotation.MAC) > 0 {
+				if err := pa.macAllocator.reserveMAC(portName, podAnnotation.MAC); err != nil {
+					klog.Errorf("Failed to reserve MAC %s of pod %s on network %s: %v",
+						podAnnotation.MAC, portName, pa.GetNetworkName(), err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// addUpdatePod allocates the addresses of the given pod on every NAD of the network it is attached to, if not
+// annotated yet
+func (pa *podAllocator) addUpdatePod(pod *corev1.Pod) error {
+	if !util.PodScheduled(pod) || util.PodWantsHostNetwork(pod) || util.PodCompleted(pod) {
+		return nil
+	}
+	on, networkMap, err := util.GetPodNADToNetworkMapping(pod, pa.NetInfo)
+	if err != nil {
+		// configuration error, no need to retry, do not return error
+		klog.Errorf("Error getting network-attachment for pod %s/%s network %s: %v",
+			pod.Namespace, pod.Name, pa.GetNetworkName(), err)
+		return nil
+	}
+	if !on {
+		return nil
+	}
+
+	var errs []error
+	for nadName, network := range networkMap {
+		if err := pa.allocatePodOnNAD(pod, nadName, network); err != nil {
+			errs = append(errs, fmt.Errorf("failed to allocate the addresses of pod %s/%s for NAD %s: %w",
+				pod.Namespace, pod.Name, nadName, err))
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+func (pa *podAllocator) allocatePodOnNAD(pod *corev1.Pod, nadName string, network *nadapi.NetworkSelectionElement) (err error) {
+	portName := util.GetSecondaryNetworkLogicalPortName(pod.Namespace, pod.Name, nadName)
+	podAnnotation, annotationErr := util.UnmarshalPodAnnotation(pod.Annotations, nadName)
+	if annotationErr == nil {
+		if pa.tunnelIDAllocator == nil || podAnnotation.TunnelID != 0 {
+			return nil
+		}
+		// the pod was annotated before interconnect was enabled, it only lacks a tunnel id
+		if podAnnotation.TunnelID, err = pa.tunnelIDAllocator.allocateID(portName); err != nil {
+			return err
+		}
+		if err = pa.updatePodAnnotationWithRetry(pod, podAnnotation, nadName); err != nil {
+			pa.tunnelIDAllocator.releaseID(portName)
+		}
+		return err
+	}
+
+	// pods referencing an IPAMClaim get the IPs stored in the claim, if any
+	ipamClaim, err := pa.getPodIPAMClaim(pod, nadName)
+	if err != nil {
+		return err
+	}
+	claimedIPs := ipamClaim != nil && len(ipamClaim.Status.IPs) > 0
+
+	if pa.macAllocator.isReserved(portName) {
+		// the informer cache lags behind the annotation update of a previous allocation
+		return fmt.Errorf("addresses of pod %s already allocated, waiting for its annotation", portName)
+	}
+
+	podAnnotation = &util.PodAnnotation{}
+	if network != nil {
+		podAnnotation.Gateways = append(podAnnotation.Gateways, network.GatewayRequest...)
+	}
+
+	var allocatedIPs []*net.IPNet
+	var macReserved bool
+	defer func() {
+		if err == nil {
+			return
+		}
+		// the IPs of an IPAMClaim are only released when the claim is deleted
+		if len(allocatedIPs) > 0 && !claimedIPs {
+			if relErr := pa.ipAllocator.ReleaseIPs(pa.GetNetworkName(), allocatedIPs); relErr != nil {
+				klog.Errorf("Failed to release IPs %s of pod %s on network %s: %v",
+					util.JoinIPNetIPs(allocatedIPs, " "), portName, pa.GetNetworkName(), relErr)
+			}
+		}
+		if pa.tunnelIDAllocator != nil {
+			pa.tunnelIDAllocator.releaseID(portName)
+		}
+		if macReserved {
+			pa.macAllocator.releaseMAC(portName)
+		}
+	}()
+
+	switch {
+	case claimedIPs:
+		allocatedIPs, err = pa.allocateIPAMClaimIPs(ipamClaim)
+		if err != nil {
+			return err
+		}
+		podAnnotation.IPs = allocatedIPs
+	case pa.ipAllocator != nil:
+		allocatedIPs, err = pa.ipAllocator.AllocateNextIPs(pa.GetNetworkName())
+		if err != nil {
+			return err
+		}
+		podAnnotation.IPs = allocatedIPs
+	case network != nil && network.IPRequest != nil:
+		klog.V(5).Infof("Will use static IP addresses for pod %s on a flatL2 topology without subnet defined", portName)
+		for _, ip := range network.IPRequest {
+			ipAddr, ipNet, err := net.ParseCIDR(ip)
+			if err != nil {
+				return fmt.Errorf("failed to parse IP %s requested in annotation for pod %s: %w", ip, portName, err)
+			}
+			ipNet.IP = ipAddr
+			podAnnotation.IPs = append(podAnnotation.IPs, ipNet)
+		}
+	}
+
+	macPrefix := util.GetNetworkMACPrefix(pa.networkID)
+	switch {
+	case network != nil && network.MacRequest != "":
+		podAnnotation.MAC, err = net.ParseMAC(network.MacRequest)
+		if err != nil {
+			return fmt.Errorf("failed to parse mac %s requested in annotation for pod %s: %w",
+				network.MacRequest, portName, err)
+		}
+	case len(podAnnotation.IPs) > 0:
+		podAnnotation.MAC = util.NetworkPodIPToHWAddr(macPrefix, podAnnotation.IPs[0].IP)
+	default:
+		podAnnotation.MAC, err = lsm.GenerateRandMAC()
+		if err != nil {
+			return err
+		}
+		copy(podAnnotation.MAC, macPrefix)
+	}
+	if err = pa.macAllocator.reserveMAC(portName, podAnnotation.MAC); err != nil {
+		return fmt.Errorf("failed to reserve MAC %s of pod %s: %w", podAnnotation.MAC, portName, err)
+	}
+	macReserved = true
+
+	if pa.tunnelIDAllocator != nil {
+		podAnnotation.TunnelID, err = pa.tunnelIDAllocator.allocateID(portName)
+		if err != nil {
+			return err
+		}
+	}
+
+	if ipamClaim != nil && !claimedIPs {
+		if err = pa.updateIPAMClaimIPs(ipamClaim, podAnnotation.IPs); err != nil {
+			return err
+		}
+	}
+
+	klog.V(5).Infof("Allocated addresses of pod %s on network %s: ip=%v ; mac=%s ; tunnel id=%d", portName,
+		pa.GetNetworkName(), podAnnotation.IPs, podAnnotation.MAC, podAnnotation.TunnelID)
+	return pa.updatePodAnnotationWithRetry(pod, podAnnotation, nadName)
+}
+
+// deletePod releases the IPs and tunnel ids annotated on the given pod for the network
+func (pa *podAllocator) deletePod(pod *corev1.Pod) error {
+	if !util.PodScheduled(pod) || util.PodWantsHostNetwork(pod) {
+		return nil
+	}
+	on, networkMap, err := util.GetPodNADToNetworkMapping(pod, pa.NetInfo)
+	if err != nil || !on {
+		return nil
+	}
+	for nadName := range networkMap {
+		podAnnotation, err := util.UnmarshalPodAnnotation(pod.Annotations, nadName)
+		if err != nil {
+			continue
+		}
+		portName := util.GetSecondaryNetworkLogicalPortName(pod.Namespace, pod.Name, nadName)
+		// the IPs of pods referencing an IPAMClaim are kept until the claim is deleted
+		claimed, err := pa.isPodIPAMClaimed(pod, nadName)
+		if err != nil {
+			return err
+		}
+		if claimed {
+			klog.Infof("Keeping IPs %s of pod %s on network %s, they are held by an IPAMClaim",
+				util.JoinIPNetIPs(podAnnotation.IPs, " "), portName, pa.GetNetworkName())
+		} else if pa.ipAllocator != nil && len(podAnnotation.IPs) > 0 {
+			klog.Infof("Releasing IPs %s of pod %s on network %s", util.JoinIPNetIPs(podAnnotation.IPs, " "),
+				portName, pa.GetNetworkName())
+			if err := pa.ipAllocator.ReleaseIPs(pa.GetNetworkName(), podAnnotation.IPs); err != nil {
+				return fmt.Errorf("failed to release IPs of pod %s on network %s: %w", portName, pa.GetNetworkName(), err)
+			}
+		}
+		if pa.tunnelIDAllocator != nil {
+			pa.tunnelIDAllocator.releaseID(portName)
+		}
+		pa.macAllocator.releaseMAC(portName)
+	}
+	return nil
+}
+
+func (pa *podAllocator) updatePodAnnotationWithRetry(origPod *corev1.Pod, podInfo *util.PodAnnotation, nadName string) error {
+	resultErr := retry.RetryOnConflict(util.OvnConflictBackoff, func() error {
+		// Informer cache should not be mutated, so get a copy of the object
+		pod, err := pa.watchFactory.GetPod(origPod.Namespace, origPod.Name)
+		if err != nil {
+			return err
+		}
+
+		cpod := pod.DeepCopy()
+		cpod.Annotations, err = util.MarshalPodAnnotation(cpod.Annotations, podInfo, nadName)
+		if err != nil {
+			return err
+		}
+		return pa.kube.UpdatePod(cpod)
+	})
+	if resultErr != nil {
+		return fmt.Errorf("failed to update annotation on pod %s/%s: %v", origPod.Namespace, origPod.Name, resultErr)
+	}
+	return nil
+}
+
+// allowPersistentIPs returns true if the pods of the network can keep their IPs across pod recreation by
+// referencing an IPAMClaim
+func (pa *podAllocator) allowPersistentIPs() bool {
+	return util.IsPersistentIPsEnabled() && pa.ipAllocator != nil && pa.AllowsPersistentIPs()
+}
+
+// getPodIPAMClaim returns the IPAMClaim the pod references for the given NAD, or nil if the pod does not
+// reference any. The error wraps the informer error, so a missing claim can be checked with apierrors.IsNotFound.
+func (pa *podAllocator) getPodIPAMClaim(pod *corev1.Pod, nadName string) (*ipamclaimsapi.IPAMClaim, error) {
+	if !pa.allowPersistentIPs() {
+		return nil, nil
+	}
+	claimName, err := util.GetK8sPodIPAMClaimReference(pod, nadName)
+	if err != nil || claimName == "" {
+		return nil, err
+	}
+	claim, err := pa.watchFactory.GetIPAMClaim(pod.Namespace, claimName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get IPAMClaim %s/%s referenced by pod %s for NAD %s: %w",
+			pod.Namespace, claimName, pod.Name, nadName, err)
+	}
+	if claim.Spec.Network != pa.GetNetworkName() {
+		return nil, fmt.Errorf("IPAMClaim %s/%s referenced by pod %s for NAD %s belongs to network %s, not to %s",
+			claim.Namespace, claim.Name, pod.Name, nadName, claim.Spec.Network, pa.GetNetworkName())
+	}
+	return claim, nil
+}
+
+// isPodIPAMClaimed returns true if the IPs of the pod for the given NAD are held by an existing IPAMClaim and
+// must not be released when the pod goes away
+func (pa *podAllocator) isPodIPAMClaimed(pod *corev1.Pod, nadName string) (bool, error) {
+	claim, err := pa.getPodIPAMClaim(pod, nadName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return claim != nil, nil
+}
+
+// allocateIPAMClaimIPs reserves the IPs stored in the IPAMClaim status; IPs that are already reserved, e.g.
+// when the claim was synced, are not an error.
+func (pa *podAllocator) allocateIPAMClaimIPs(claim *ipamclaimsapi.IPAMClaim) ([]*net.IPNet, error) {
+	ips, err := parseIPAMClaimIPs(claim)
+	if err != nil {
+		return nil, err
+	}
+	if err = pa.ipAllocator.AllocateIPs(pa.GetNetworkName(), ips); err != nil && err != ipallocator.ErrAllocated {
+		return nil, fmt.Errorf("failed to allocate IPs %s of IPAMClaim %s/%s on network %s: %w",
+			util.JoinIPNets(ips, " "), claim.Namespace, claim.Name, pa.GetNetworkName(), err)
+	}
+	return ips, nil
+}
+
+// updateIPAMClaimIPs stores the IPs allocated to the pod in the IPAMClaim status, so that they can be reused
+// when the pod is recreated
+func (pa *podAllocator) updateIPAMClaimIPs(claim *ipamclaimsapi.IPAMClaim, podIPs []*net.IPNet) error {
+	ips := make([]string, 0, len(podIPs))
+	for _, podIP := range podIPs {
+		ips = append(ips, podIP.String())
+	}
+	if err := pa.kube.UpdateIPAMClaimIPs(claim, ips); err != nil {
+		return fmt.Errorf("failed to update the IPs of IPAMClaim %s/%s: %w", claim.Namespace, claim.Name, err)
+	}
+	klog.Infof("Persisted IPs %v in IPAMClaim %s/%s for network %s", ips, claim.Namespace, claim.Name, pa.GetNetworkName())
+	return nil
+}
+
+// addIPAMClaim reserves the IPs held by an IPAMClaim of the network
+func (pa *podAllocator) addIPAMClaim(claim *ipamclaimsapi.IPAMClaim) error {
+	if !pa.allowPersistentIPs() || claim.Spec.Network != pa.GetNetworkName() || len(claim.Status.IPs) == 0 {
+		return nil
+	}
+	_, err := pa.allocateIPAMClaimIPs(claim)
+	return err
+}
+
+// deleteIPAMClaim releases the IPs held by an IPAMClaim of the network, unless a pod is still using them; the
+// IPs are then released along with the pod.
+func (pa *podAllocator) deleteIPAMClaim(claim *ipamclaimsapi.IPAMClaim) error {
+	if !pa.allowPersistentIPs() || claim.Spec.Network != pa.GetNetworkName() || len(claim.Status.IPs) == 0 {
+		return nil
+	}
+	ips, err := parseIPAMClaimIPs(claim)
+	if err != nil {
+		return err
+	}
+	inUse, err := pa.arePodIPsInUse(claim.Namespace, ips)
+	if err != nil || inUse {
+		return err
+	}
+	klog.Infof("Releasing IPs %s of deleted IPAMClaim %s/%s for network %s", util.JoinIPNetIPs(ips, " "),
+		claim.Namespace, claim.Name, pa.GetNetworkName())
+	return pa.ipAllocator.ReleaseIPs(pa.GetNetworkName(), ips)
+}
+
+// arePodIPsInUse returns true if a running pod of the given namespace has any of the given IPs annotated for
+// the network
+func (pa *podAllocator) arePodIPsInUse(namespace string, ips []*net.IPNet) (bool, error) {
+	pods, err := pa.watchFactory.GetPods(namespace)
+	if err != nil {
+		return false, fmt.Errorf("failed to get the pods of namespace %s: %w", namespace, err)
+	}
+	for _, pod := range pods {
+		if util.PodCompleted(pod) || util.PodWantsHostNetwork(pod) || !util.PodScheduled(pod) {
+			continue
+		}
+		podIPs, err := util.GetPodIPsOfNetwork(pod, pa.NetInfo)
+		if err != nil {
+			continue
+		}
+		for _, podIP := range podIPs {
+			for _, ip := range ips {
+				if podIP.Equal(ip.IP) {
+					klog.Infof("Not releasing IP %s, it is used by pod %s/%s on network %s", ip.IP, pod.Namespace,
+						pod.Name, pa.GetNetworkName())
+					return true, nil
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+func parseIPAMClaimIPs(claim *ipamclaimsapi.IPAMClaim) ([]*net.IPNet, error) {
+	ips := make([]*net.IPNet, 0, len(claim.Status.IPs))
+	for _, ipStr := range claim.Status.IPs {
+		ip, ipNet, err := net.ParseCIDR(ipStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse IP %s of IPAMClaim %s/%s: %w", ipStr, claim.Namespace, claim.Name, err)
+		}
+		ipNet.IP = ip
+		ips = append(ips, ipNet)
+	}
+	return ips, nil
+}
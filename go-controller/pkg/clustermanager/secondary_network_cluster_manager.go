@@ -2,12 +2,15 @@ package clustermanager
 
 import (
 	"fmt"
+	"net"
 
 	"github.com/containernetworking/cni/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/clustermanager/allocationauditor"
 	ovncnitypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/cni/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
 	nad "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/network-attach-def-controller"
 	ovntypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
@@ -17,6 +20,8 @@ import (
 const (
 	// Maximum secondary network IDs that can be generated. An arbitrary value is chosen.
 	maxSecondaryNetworkIDs = 4096
+	// Name prefix of the network ids reserved for the MAC prefixes of the default network pods.
+	defaultMACPrefixReservedName = "default-mac-prefix-"
 )
 
 // secondaryNetworkClusterManager object manages the multi net-attach-def controllers.
@@ -27,12 +32,14 @@ type secondaryNetworkClusterManager struct {
 	nadController *nad.NetAttachDefinitionController
 	ovnClient     *util.OVNClusterManagerClientset
 	watchFactory  *factory.WatchFactory
-	// networkIDAllocator is used to allocate a unique ID for each secondary layer3 network
+	// networkIDAllocator is used to allocate a unique ID for each secondary network
 	networkIDAllocator *idAllocator
+	// allocationAuditor audits the node subnets of the secondary networks, if not nil
+	allocationAuditor *allocationauditor.Auditor
 }
 
 func newSecondaryNetworkClusterManager(ovnClient *util.OVNClusterManagerClientset,
-	wf *factory.WatchFactory, recorder record.EventRecorder) (*secondaryNetworkClusterManager, error) {
+	wf *factory.WatchFactory, recorder record.EventRecorder, allocationAuditor *allocationauditor.Auditor) (*secondaryNetworkClusterManager, error) {
 	klog.Infof("Creating secondary network cluster manager")
 	networkIDAllocator, err := NewIDAllocator("NetworkIDs", maxSecondaryNetworkIDs)
 	if err != nil {
@@ -43,10 +50,21 @@ func newSecondaryNetworkClusterManager(ovnClient *util.OVNClusterManagerClientse
 	if err := networkIDAllocator.reserveID("default", defaultNetworkID); err != nil {
 		return nil, fmt.Errorf("idAllocator failed to reserve defaultNetworkID %d", defaultNetworkID)
 	}
+
+	// Reserve the ids whose MAC prefix is the one of the default network pods, so that the pods of the secondary
+	// networks never get a MAC from the default network MAC space. Both prefixes may map to the same id, or to
+	// none, the errors can be ignored.
+	defaultMACPrefix, _ := config.ParseMACPrefix(config.DefaultPodMACPrefix)
+	for _, prefix := range []net.HardwareAddr{defaultMACPrefix, config.Default.PodMACPrefix} {
+		if id := util.GetMACPrefixNetworkID(prefix); id != util.InvalidNetworkID && id < maxSecondaryNetworkIDs {
+			_ = networkIDAllocator.reserveID(defaultMACPrefixReservedName+prefix.String(), id)
+		}
+	}
 	sncm := &secondaryNetworkClusterManager{
 		ovnClient:          ovnClient,
 		watchFactory:       wf,
 		networkIDAllocator: networkIDAllocator,
+		allocationAuditor:  allocationAuditor,
 	}
 
 	sncm.nadController, err = nad.NewNetAttachDefinitionController(
@@ -62,7 +80,7 @@ func newSecondaryNetworkClusterManager(ovnClient *util.OVNClusterManagerClientse
 func (sncm *secondaryNetworkClusterManager) Start() error {
 	klog.Infof("Starting secondary network cluster manager")
 
-	// Reserve the network ids in the id allocator for the existing secondary layer3 networks.
+	// Reserve the network ids in the id allocator for the existing secondary networks.
 	nodes, err := sncm.watchFactory.GetNodes()
 	if err != nil {
 		return fmt.Errorf("error getting the nodes from the watch factory : err - %v", err)
@@ -91,22 +109,22 @@ func (sncm *secondaryNetworkClusterManager) Stop() {
 
 // NewNetworkController implements the networkAttachDefController.NetworkControllerManager
 // interface function.  This function is called by the net-attach-def controller when
-// a layer2 or layer3 secondary network is created.  Layer2 type is not handled here.
+// a secondary network is created. Every network gets a unique network id, from which the
+// MAC prefix of its pods is derived, and layer3 networks also get a subnet per node.
 func (sncm *secondaryNetworkClusterManager) NewNetworkController(nInfo util.NetInfo) (nad.NetworkController, error) {
-	topoType := nInfo.TopologyType()
-	if topoType == ovntypes.Layer3Topology {
-		networkId, err := sncm.networkIDAllocator.allocateID(nInfo.GetNetworkName())
-		if err != nil {
-			return nil, fmt.Errorf("failed to create NetworkController for secondary layer3 network %s : %w", nInfo.GetNetworkName(), err)
-		}
-
-		sncc := newNetworkClusterController(nInfo.GetNetworkName(), networkId, nInfo.Subnets(),
-			sncm.ovnClient, sncm.watchFactory, false, nInfo)
-		return sncc, nil
+	networkId, err := sncm.networkIDAllocator.allocateID(nInfo.GetNetworkName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create NetworkController for secondary %s network %s : %w",
+			nInfo.TopologyType(), nInfo.GetNetworkName(), err)
 	}
 
-	// Secondary network cluster manager doesn't manage other topology types
-	return nil, nad.ErrNetworkControllerTopologyNotManaged
+	var clusterSubnets []config.CIDRNetworkEntry
+	if nInfo.TopologyType() == ovntypes.Layer3Topology {
+		clusterSubnets = nInfo.Subnets()
+	}
+	sncc := newNetworkClusterController(nInfo.GetNetworkName(), networkId, clusterSubnets,
+		sncm.ovnClient, sncm.watchFactory, false, nInfo, sncm.allocationAuditor)
+	return sncc, nil
 }
 
 // CleanupDeletedNetworks implements the networkAttachDefController.NetworkControllerManager
@@ -124,9 +142,13 @@ func (sncm *secondaryNetworkClusterManager) CleanupDeletedNetworks(allController
 	}
 
 	for _, node := range existingNodes {
-		nodeNetworks, err := util.GetNodeSubnetAnnotationNetworkNames(node)
-		if err != nil {
-			continue
+		// a network may be left in either of the subnet and network id annotations if a previous cleanup of the
+		// node failed midway, look for it in both
+		nodeNetworks, _ := util.GetNodeSubnetAnnotationNetworkNames(node)
+		if networkIDs, err := util.GetNodeNetworkIDsAnnotationNetworkIDs(node); err == nil {
+			for netName := range networkIDs {
+				nodeNetworks = append(nodeNetworks, netName)
+			}
 		}
 
 		for i := range nodeNetworks {
@@ -164,5 +186,5 @@ func (sncm *secondaryNetworkClusterManager) CleanupDeletedNetworks(allController
 func (sncm *secondaryNetworkClusterManager) newDummyLayer3NetworkController(netName string) nad.NetworkController {
 	netInfo, _ := util.NewNetInfo(&ovncnitypes.NetConf{NetConf: types.NetConf{Name: netName}, Topology: ovntypes.Layer3Topology})
 	return newNetworkClusterController(netInfo.GetNetworkName(), util.InvalidNetworkID, nil, sncm.ovnClient, sncm.watchFactory,
-		false, netInfo)
+		false, netInfo, nil)
 }
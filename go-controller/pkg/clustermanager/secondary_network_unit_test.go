@@ -7,6 +7,8 @@ import (
 	"sync"
 
 	"github.com/containernetworking/cni/pkg/types"
+	nadapi "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	nadfake "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned/fake"
 	"github.com/onsi/ginkgo"
 	"github.com/onsi/gomega"
 	"github.com/urfave/cli/v2"
@@ -17,8 +19,11 @@ import (
 
 	ovncnitypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/cni/types"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	ipamclaimsapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/ipamclaims/v1alpha1"
+	ipamclaimsfake "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/ipamclaims/v1alpha1/apis/clientset/versioned/fake"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
 	nad "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/network-attach-def-controller"
+	ovntest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing"
 	ovntypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 )
@@ -86,7 +91,7 @@ var _ = ginkgo.Describe("Secondary Layer3 Cluster Controller Manager", func() {
 				err = f.Start()
 				gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
-				sncm, err := newSecondaryNetworkClusterManager(fakeClient, f, record.NewFakeRecorder(0))
+				sncm, err := newSecondaryNetworkClusterManager(fakeClient, f, record.NewFakeRecorder(0), nil)
 				gomega.Expect(err).NotTo(gomega.HaveOccurred())
 				netInfo, err := util.NewNetInfo(&ovncnitypes.NetConf{NetConf: types.NetConf{Name: "blue"}, Topology: ovntypes.Layer3Topology, Subnets: "192.168.0.0/16/24"})
 				gomega.Expect(err).NotTo(gomega.HaveOccurred())
@@ -152,13 +157,357 @@ var _ = ginkgo.Describe("Secondary Layer3 Cluster Controller Manager", func() {
 				err = f.Start()
 				gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
-				sncm, err := newSecondaryNetworkClusterManager(fakeClient, f, record.NewFakeRecorder(0))
+				sncm, err := newSecondaryNetworkClusterManager(fakeClient, f, record.NewFakeRecorder(0), nil)
 				gomega.Expect(err).NotTo(gomega.HaveOccurred())
 				netInfo, err := util.NewNetInfo(&ovncnitypes.NetConf{NetConf: types.NetConf{Name: "blue"}, Topology: ovntypes.Layer2Topology})
 				gomega.Expect(err).NotTo(gomega.HaveOccurred())
 				nc, err := sncm.NewNetworkController(netInfo)
-				gomega.Expect(err).To(gomega.Equal(nad.ErrNetworkControllerTopologyNotManaged))
-				gomega.Expect(nc).To(gomega.BeNil())
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(nc).NotTo(gomega.BeNil())
+				nc.Start(ctx.Context)
+				defer nc.Stop()
+
+				// Check that network controller for "blue" network has set the network id annotation for each node,
+				// from which the MAC prefix of its pods is derived, but no subnet annotation.
+				for _, n := range nodes {
+					gomega.Eventually(func() (int, error) {
+						updatedNode, err := fakeClient.KubeClient.CoreV1().Nodes().Get(context.TODO(), n.Name, metav1.GetOptions{})
+						if err != nil {
+							return util.InvalidNetworkID, err
+						}
+
+						return util.ParseNetworkIDAnnotation(updatedNode, "blue")
+					}, 2).Should(gomega.Equal(1))
+					updatedNode, err := fakeClient.KubeClient.CoreV1().Nodes().Get(context.TODO(), n.Name, metav1.GetOptions{})
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					_, err = util.ParseNodeHostSubnetAnnotation(updatedNode, "blue")
+					gomega.Expect(util.IsAnnotationNotSetError(err)).To(gomega.BeTrue())
+				}
+
+				return nil
+			}
+
+			err := app.Run([]string{
+				app.Name,
+			})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("Allocates the pod addresses of a secondary layer2 network with interconnect", func() {
+			app.Action = func(ctx *cli.Context) error {
+				const nadName = "ns1/blue-nad"
+				pod := &v1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pod1",
+						Namespace: "ns1",
+						Annotations: map[string]string{
+							nadapi.NetworkAttachmentAnnot: `[{"name":"blue-nad","namespace":"ns1"}]`,
+						},
+					},
+					Spec: v1.PodSpec{NodeName: "node1"},
+				}
+				kubeFakeClient := fake.NewSimpleClientset(
+					&v1.NodeList{Items: []v1.Node{{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}}},
+					&v1.PodList{Items: []v1.Pod{*pod}},
+				)
+				fakeClient := &util.OVNClusterManagerClientset{
+					KubeClient: kubeFakeClient,
+				}
+
+				_, err := config.InitConfig(ctx, nil, nil)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				config.Kubernetes.HostNetworkNamespace = ""
+				config.OVNKubernetesFeature.EnableMultiNetwork = true
+				config.OVNKubernetesFeature.EnableInterconnect = true
+
+				f, err = factory.NewClusterManagerWatchFactory(fakeClient)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				err = f.Start()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				sncm, err := newSecondaryNetworkClusterManager(fakeClient, f, record.NewFakeRecorder(0), nil)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				netInfo, err := util.NewNetInfo(&ovncnitypes.NetConf{NetConf: types.NetConf{Name: "blue"},
+					Topology: ovntypes.Layer2Topology, Subnets: "192.168.0.0/24", ExcludeSubnets: "192.168.0.0/30"})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				netInfo.AddNAD(nadName)
+				nc, err := sncm.NewNetworkController(netInfo)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				nc.Start(ctx.Context)
+				defer nc.Stop()
+
+				// the first IP out of the excluded subnet, its MAC derived from the network id 1, and the first
+				// tunnel id
+				var podAnnotation *util.PodAnnotation
+				gomega.Eventually(func() error {
+					updatedPod, err := fakeClient.KubeClient.CoreV1().Pods(pod.Namespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+					if err != nil {
+						return err
+					}
+					podAnnotation, err = util.UnmarshalPodAnnotation(updatedPod.Annotations, nadName)
+					return err
+				}, 2).Should(gomega.Succeed())
+				gomega.Expect(podAnnotation.IPs).To(gomega.Equal(ovntest.MustParseIPNets("192.168.0.4/24")))
+				gomega.Expect(podAnnotation.MAC).To(gomega.Equal(
+					util.NetworkPodIPToHWAddr(util.GetNetworkMACPrefix(1), ovntest.MustParseIP("192.168.0.4"))))
+				gomega.Expect(podAnnotation.TunnelID).To(gomega.Equal(1))
+
+				return nil
+			}
+
+			err := app.Run([]string{
+				app.Name,
+			})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("Allocates the requested MACs of the pods of a secondary layer2 network unless already in use", func() {
+			app.Action = func(ctx *cli.Context) error {
+				const nadName = "ns1/blue-nad"
+				newPod := func(name, mac string) *v1.Pod {
+					selection := `[{"name":"blue-nad","namespace":"ns1"}]`
+					if mac != "" {
+						selection = fmt.Sprintf(`[{"name":"blue-nad","namespace":"ns1","mac":%q}]`, mac)
+					}
+					return &v1.Pod{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:        name,
+							Namespace:   "ns1",
+							Annotations: map[string]string{nadapi.NetworkAttachmentAnnot: selection},
+						},
+						Spec: v1.PodSpec{NodeName: "node1"},
+					}
+				}
+				// pod1 is already annotated with the MAC that pod2 requests
+				pod1 := newPod("pod1", "")
+				var err error
+				pod1.Annotations, err = util.MarshalPodAnnotation(pod1.Annotations, &util.PodAnnotation{
+					IPs:      ovntest.MustParseIPNets("192.168.0.10/24"),
+					MAC:      ovntest.MustParseMAC("0a:58:c0:a8:00:aa"),
+					TunnelID: 5,
+				}, nadName)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				pod2 := newPod("pod2", "0a:58:c0:a8:00:aa")
+				pod3 := newPod("pod3", "0a:58:c0:a8:00:bb")
+				kubeFakeClient := fake.NewSimpleClientset(
+					&v1.NodeList{Items: []v1.Node{{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}}},
+					&v1.PodList{Items: []v1.Pod{*pod1, *pod2, *pod3}},
+				)
+				fakeClient := &util.OVNClusterManagerClientset{
+					KubeClient: kubeFakeClient,
+				}
+
+				_, err = config.InitConfig(ctx, nil, nil)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				config.Kubernetes.HostNetworkNamespace = ""
+				config.OVNKubernetesFeature.EnableMultiNetwork = true
+				config.OVNKubernetesFeature.EnableInterconnect = true
+
+				f, err = factory.NewClusterManagerWatchFactory(fakeClient)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				err = f.Start()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				sncm, err := newSecondaryNetworkClusterManager(fakeClient, f, record.NewFakeRecorder(10), nil)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				netInfo, err := util.NewNetInfo(&ovncnitypes.NetConf{NetConf: types.NetConf{Name: "blue"},
+					Topology: ovntypes.Layer2Topology, Subnets: "192.168.0.0/24"})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				netInfo.AddNAD(nadName)
+				nc, err := sncm.NewNetworkController(netInfo)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				nc.Start(ctx.Context)
+				defer nc.Stop()
+
+				getPodAnnotation := func(name string) (*util.PodAnnotation, error) {
+					updatedPod, err := fakeClient.KubeClient.CoreV1().Pods("ns1").Get(context.TODO(), name, metav1.GetOptions{})
+					if err != nil {
+						return nil, err
+					}
+					return util.UnmarshalPodAnnotation(updatedPod.Annotations, nadName)
+				}
+				var podAnnotation *util.PodAnnotation
+				gomega.Eventually(func() error {
+					podAnnotation, err = getPodAnnotation(pod3.Name)
+					return err
+				}, 2).Should(gomega.Succeed())
+				gomega.Expect(podAnnotation.MAC).To(gomega.Equal(ovntest.MustParseMAC("0a:58:c0:a8:00:bb")))
+
+				// the MAC requested by pod2 is in use by pod1
+				gomega.Consistently(func() error {
+					_, err := getPodAnnotation(pod2.Name)
+					return err
+				}, 1).Should(gomega.HaveOccurred())
+
+				return nil
+			}
+
+			err := app.Run([]string{
+				app.Name,
+			})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("Allocates the pod addresses held by IPAMClaims on a secondary layer2 network with interconnect", func() {
+			app.Action = func(ctx *cli.Context) error {
+				const nadName = "ns1/blue-nad"
+				newClaimingPod := func(name, claimName string) v1.Pod {
+					return v1.Pod{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      name,
+							Namespace: "ns1",
+							Annotations: map[string]string{
+								nadapi.NetworkAttachmentAnnot: fmt.Sprintf(
+									`[{"name":"blue-nad","namespace":"ns1","ipam-claim-reference":%q}]`, claimName),
+							},
+						},
+						Spec: v1.PodSpec{NodeName: "node1"},
+					}
+				}
+				newIPAMClaim := func(name string, ips ...string) *ipamclaimsapi.IPAMClaim {
+					return &ipamclaimsapi.IPAMClaim{
+						ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns1"},
+						Spec:       ipamclaimsapi.IPAMClaimSpec{Network: "blue", Interface: "net1"},
+						Status:     ipamclaimsapi.IPAMClaimStatus{IPs: ips},
+					}
+				}
+				kubeFakeClient := fake.NewSimpleClientset(
+					&v1.NodeList{Items: []v1.Node{{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}}},
+					&v1.PodList{Items: []v1.Pod{newClaimingPod("pod1", "claim1"), newClaimingPod("pod2", "claim2")}},
+				)
+				fakeClient := &util.OVNClusterManagerClientset{
+					KubeClient:       kubeFakeClient,
+					IPAMClaimsClient: ipamclaimsfake.NewSimpleClientset(newIPAMClaim("claim1", "192.168.0.4/24"), newIPAMClaim("claim2")),
+				}
+
+				_, err := config.InitConfig(ctx, nil, nil)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				config.Kubernetes.HostNetworkNamespace = ""
+				config.OVNKubernetesFeature.EnableMultiNetwork = true
+				config.OVNKubernetesFeature.EnableInterconnect = true
+				config.OVNKubernetesFeature.EnablePersistentIPs = true
+
+				f, err = factory.NewClusterManagerWatchFactory(fakeClient)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				err = f.Start()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				sncm, err := newSecondaryNetworkClusterManager(fakeClient, f, record.NewFakeRecorder(0), nil)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				netInfo, err := util.NewNetInfo(&ovncnitypes.NetConf{NetConf: types.NetConf{Name: "blue"},
+					Topology: ovntypes.Layer2Topology, Subnets: "192.168.0.0/24", ExcludeSubnets: "192.168.0.0/30",
+					AllowPersistentIPs: true})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				netInfo.AddNAD(nadName)
+				nc, err := sncm.NewNetworkController(netInfo)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				nc.Start(ctx.Context)
+				defer nc.Stop()
+
+				getPodIPs := func(name string) func() ([]*net.IPNet, error) {
+					return func() ([]*net.IPNet, error) {
+						pod, err := fakeClient.KubeClient.CoreV1().Pods("ns1").Get(context.TODO(), name, metav1.GetOptions{})
+						if err != nil {
+							return nil, err
+						}
+						podAnnotation, err := util.UnmarshalPodAnnotation(pod.Annotations, nadName)
+						if err != nil {
+							return nil, err
+						}
+						return podAnnotation.IPs, nil
+					}
+				}
+
+				// pod1 gets the IP held by its claim, pod2 the next free IP which is persisted in its claim
+				gomega.Eventually(getPodIPs("pod1"), 2).Should(gomega.Equal(ovntest.MustParseIPNets("192.168.0.4/24")))
+				gomega.Eventually(getPodIPs("pod2"), 2).Should(gomega.Equal(ovntest.MustParseIPNets("192.168.0.5/24")))
+				claim, err := fakeClient.IPAMClaimsClient.K8sV1alpha1().IPAMClaims("ns1").Get(context.TODO(), "claim2", metav1.GetOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(claim.Status.IPs).To(gomega.Equal([]string{"192.168.0.5/24"}))
+
+				// the IP of a deleted pod is kept as long as its claim exists
+				podAllocator := nc.(*networkClusterController).podAllocator
+				isIPAllocated := func() bool {
+					ip := ovntest.MustParseIPNets("192.168.0.4/24")
+					err := podAllocator.ipAllocator.AllocateIPs("blue", ip)
+					if err == nil {
+						// undo the allocation done by the check itself
+						gomega.Expect(podAllocator.ipAllocator.ReleaseIPs("blue", ip)).To(gomega.Succeed())
+					}
+					return err != nil
+				}
+				err = fakeClient.KubeClient.CoreV1().Pods("ns1").Delete(context.TODO(), "pod1", metav1.DeleteOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Consistently(isIPAllocated, 1).Should(gomega.BeTrue())
+				err = fakeClient.IPAMClaimsClient.K8sV1alpha1().IPAMClaims("ns1").Delete(context.TODO(), "claim1", metav1.DeleteOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Eventually(isIPAllocated, 2).Should(gomega.BeFalse())
+
+				return nil
+			}
+
+			err := app.Run([]string{
+				app.Name,
+			})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("Removes the network annotation of the deleted NADs from the pods on cleanup", func() {
+			app.Action = func(ctx *cli.Context) error {
+				pod := &v1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pod1",
+						Namespace: "ns1",
+						Annotations: map[string]string{
+							nadapi.NetworkAttachmentAnnot: `[{"name":"blue-nad","namespace":"ns1"},{"name":"red-nad","namespace":"ns1"}]`,
+							util.OvnPodAnnotationName: `{"default":{"ip_addresses":["10.244.0.5/24"],"mac_address":"0a:58:0a:f4:00:05"},` +
+								`"ns1/blue-nad":{"ip_addresses":["192.168.0.5/24"],"mac_address":"0a:58:c0:a8:00:05"},` +
+								`"ns1/red-nad":{"ip_addresses":["192.169.0.5/24"],"mac_address":"0a:58:c0:a9:00:05"}}`,
+						},
+					},
+					Spec: v1.PodSpec{NodeName: "node1"},
+				}
+				fakeClient := &util.OVNClusterManagerClientset{
+					KubeClient: fake.NewSimpleClientset(
+						&v1.NodeList{Items: []v1.Node{{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}}},
+						&v1.PodList{Items: []v1.Pod{*pod}},
+					),
+					NetworkAttchDefClient: nadfake.NewSimpleClientset(),
+				}
+				// the red NAD still exists while the blue one was deleted. The fake clientset guesses the wrong
+				// resource of the NADs given at creation, so create it through the client.
+				_, err := fakeClient.NetworkAttchDefClient.K8sCniCncfIoV1().NetworkAttachmentDefinitions("ns1").Create(
+					context.TODO(), &nadapi.NetworkAttachmentDefinition{ObjectMeta: metav1.ObjectMeta{Name: "red-nad", Namespace: "ns1"}},
+					metav1.CreateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				_, err = config.InitConfig(ctx, nil, nil)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				config.Kubernetes.HostNetworkNamespace = ""
+				config.OVNKubernetesFeature.EnableMultiNetwork = true
+				config.OVNKubernetesFeature.EnableInterconnect = true
+
+				f, err = factory.NewClusterManagerWatchFactory(fakeClient)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				err = f.Start()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				sncm, err := newSecondaryNetworkClusterManager(fakeClient, f, record.NewFakeRecorder(0), nil)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				netInfo, err := util.NewNetInfo(&ovncnitypes.NetConf{NetConf: types.NetConf{Name: "blue"},
+					Topology: ovntypes.Layer2Topology, Subnets: "192.168.0.0/24"})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				oc := newNetworkClusterController(netInfo.GetNetworkName(), util.InvalidNetworkID, nil, sncm.ovnClient,
+					sncm.watchFactory, false, netInfo, nil)
+				gomega.Expect(oc.Cleanup(netInfo.GetNetworkName())).To(gomega.Succeed())
+
+				updatedPod, err := fakeClient.KubeClient.CoreV1().Pods(pod.Namespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				podNetworks, err := util.UnmarshalPodAnnotationAllNetworks(updatedPod.Annotations)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(podNetworks).To(gomega.HaveLen(2))
+				gomega.Expect(podNetworks).To(gomega.HaveKey(ovntypes.DefaultNetworkName))
+				gomega.Expect(podNetworks).To(gomega.HaveKey("ns1/red-nad"))
 
 				return nil
 			}
@@ -213,7 +562,7 @@ var _ = ginkgo.Describe("Secondary Layer3 Cluster Controller Manager", func() {
 				err = f.Start()
 				gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
-				sncm, err := newSecondaryNetworkClusterManager(fakeClient, f, record.NewFakeRecorder(0))
+				sncm, err := newSecondaryNetworkClusterManager(fakeClient, f, record.NewFakeRecorder(0), nil)
 				gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
 				// Create a fake nad controller for blue network so that the red network gets cleared
@@ -224,7 +573,7 @@ var _ = ginkgo.Describe("Secondary Layer3 Cluster Controller Manager", func() {
 				// So testing the cleanup one at a time.
 				netInfo, err := util.NewNetInfo(&ovncnitypes.NetConf{NetConf: types.NetConf{Name: "blue"}, Topology: ovntypes.Layer3Topology})
 				gomega.Expect(err).NotTo(gomega.HaveOccurred())
-				oc := newNetworkClusterController(netInfo.GetNetworkName(), util.InvalidNetworkID, nil, sncm.ovnClient, sncm.watchFactory, false, netInfo)
+				oc := newNetworkClusterController(netInfo.GetNetworkName(), util.InvalidNetworkID, nil, sncm.ovnClient, sncm.watchFactory, false, netInfo, nil)
 				nadControllers := []nad.NetworkController{oc}
 
 				err = sncm.CleanupDeletedNetworks(nadControllers)
@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	utilnet "k8s.io/utils/net"
 )
 
@@ -25,6 +26,8 @@ type SubnetAllocator interface {
 	ReleaseNetworks(string, ...*net.IPNet) error
 	// ReleaseAllNetworks releases all networks owned by the given owner
 	ReleaseAllNetworks(string)
+	// Owners returns the owners of the allocated networks
+	Owners() []string
 }
 
 type BaseSubnetAllocator struct {
@@ -188,6 +191,18 @@ func (sna *BaseSubnetAllocator) ReleaseAllNetworks(owner string) {
 	sna.releaseAllNetworks(owner)
 }
 
+func (sna *BaseSubnetAllocator) Owners() []string {
+	sna.Lock()
+	defer sna.Unlock()
+	owners := sets.NewString()
+	for _, snr := range append(sna.v4ranges, sna.v6ranges...) {
+		for _, owner := range snr.allocMap {
+			owners.Insert(owner)
+		}
+	}
+	return owners.List()
+}
+
 // releaseNetworks attempts to release all given subnets, even if a failure
 // occurs during release. It returns nil, or an aggregate error for any
 // failures that occurred.
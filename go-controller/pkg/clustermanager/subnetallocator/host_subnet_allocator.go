@@ -174,3 +174,8 @@ func (sna *HostSubnetAllocator) ReleaseAllNodeSubnets(nodeName string) {
 	_, v4used, _, v6used := sna.base.Usage()
 	metrics.RecordSubnetUsage(float64(v4used), float64(v6used))
 }
+
+// Owners returns the names of the nodes that have subnets allocated
+func (sna *HostSubnetAllocator) Owners() []string {
+	return sna.base.Owners()
+}
@@ -136,6 +136,18 @@ func (pr *PodRequest) cmdAdd(kubeAuth *KubeAPIAuth, clientset *ClientSet) (*Resp
 	if err = pr.checkOrUpdatePodUID(pod); err != nil {
 		return nil, err
 	}
+	if pr.nadName != types.DefaultNetworkName {
+		// the interface name ends up in the multus network-status annotation
+		// through the CNI result, make sure it's the validated one
+		ifName, err := util.GetPodNetworkInterfaceName(pod, pr.nadName)
+		if err != nil {
+			return nil, fmt.Errorf("invalid interface of pod %s/%s for network %s: %v", namespace, podName, pr.nadName, err)
+		}
+		if ifName != pr.IfName {
+			return nil, fmt.Errorf("pod %s/%s interface for network %s is named %s, expected %s",
+				namespace, podName, pr.nadName, pr.IfName, ifName)
+		}
+	}
 
 	podInterfaceInfo, err := PodAnnotation2PodInfo(annotations, podNADAnnotation, pr.PodUID, netdevName,
 		pr.nadName, pr.netName, pr.CNIConf.MTU)
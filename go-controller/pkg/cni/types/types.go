@@ -27,6 +27,16 @@ type NetConf struct {
 	ExcludeSubnets string `json:"excludeSubnets,omitempty"`
 	// VLANID, valid in localnet topology network only
 	VLANID int `json:"vlanID,omitempty"`
+	// AllowPersistentIPs lets pods referencing an IPAMClaim keep their IPs
+	// across pod recreation, valid for layer2 and localnet network topology
+	AllowPersistentIPs bool `json:"allowPersistentIPs,omitempty"`
+	// EnableMulticast allows the pods of the namespaces annotated with k8s.ovn.org/multicast-enabled to exchange
+	// multicast traffic, valid for layer3 and layer2 network topology. Multicast must be enabled in the cluster too
+	EnableMulticast bool `json:"enableMulticast,omitempty"`
+	// RAGuard drops the IPv6 router advertisements sent by the pods, valid in localnet topology network only
+	RAGuard bool `json:"raGuard,omitempty"`
+	// DHCPv6Guard drops the DHCPv6 server messages sent by the pods, valid in localnet topology network only
+	DHCPv6Guard bool `json:"dhcpv6Guard,omitempty"`
 
 	// PciAddrs in case of using sriov or Auxiliry device name in case of SF
 	DeviceID string `json:"deviceID,omitempty"`
@@ -59,4 +69,7 @@ type NetworkSelectionElement struct {
 	MacRequest string `json:"mac,omitempty"`
 	// GatewayRequest contains default route IP address for the pod
 	GatewayRequest []net.IP `json:"default-route,omitempty"`
+	// IPAMClaimReference contains the name of the IPAMClaim, in the pod
+	// namespace, holding the persistent IPs of this network attachment
+	IPAMClaimReference string `json:"ipam-claim-reference,omitempty"`
 }
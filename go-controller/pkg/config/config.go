@@ -14,6 +14,8 @@ import (
 	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/urfave/cli/v2"
 	gcfg "gopkg.in/gcfg.v1"
@@ -31,9 +33,19 @@ const DefaultEncapPort = 6081
 
 const DefaultAPIServer = "http://localhost:8443"
 
+// DefaultPodMACPrefix is the prefix of MAC addresses derived from IP addresses
+const DefaultPodMACPrefix = "0a:58"
+
 // Default IANA-assigned UDP port number for VXLAN
 const DefaultVXLANPort = 4789
 
+const (
+	// MinEgressMSS is the minimum TCP MSS a host is required to accept (RFC 879)
+	MinEgressMSS = 536
+	// MaxEgressMSS is the largest TCP MSS that fits in an IPv4 packet
+	MaxEgressMSS = 65495
+)
+
 // The following are global config parameters that other modules may access directly
 var (
 	// Build information. Populated at build-time.
@@ -57,18 +69,23 @@ var (
 
 	// Default holds parsed config file parameters and command-line overrides
 	Default = DefaultConfig{
-		MTU:                   1400,
-		ConntrackZone:         64000,
-		EncapType:             "geneve",
-		EncapIP:               "",
-		EncapPort:             DefaultEncapPort,
-		InactivityProbe:       100000, // in Milliseconds
-		OpenFlowProbe:         180,    // in Seconds
-		OfctrlWaitBeforeClear: 0,      // in Milliseconds
-		MonitorAll:            true,
-		LFlowCacheEnable:      true,
-		RawClusterSubnets:     "10.128.0.0/14/23",
-		Zone:                  types.OvnDefaultZone,
+		MTU:                    1400,
+		ConntrackZone:          64000,
+		EncapType:              "geneve",
+		EncapIP:                "",
+		EncapPort:              DefaultEncapPort,
+		InactivityProbe:        100000, // in Milliseconds
+		OpenFlowProbe:          180,    // in Seconds
+		OfctrlWaitBeforeClear:  0,      // in Milliseconds
+		MonitorAll:             true,
+		LFlowCacheEnable:       true,
+		RawClusterSubnets:      "10.128.0.0/14/23",
+		Zone:                   types.OvnDefaultZone,
+		RawPodMACPrefix:        DefaultPodMACPrefix,
+		COPPDefaultRate:        25, // in packets per second
+		RetryMaxBackoff:        60, // in seconds
+		RetryMaxFailedAttempts: 15,
+		ShutdownTimeout:        30, // in seconds
 	}
 
 	// Logging holds logging-related parsed config file parameters and command-line overrides
@@ -113,7 +130,12 @@ var (
 	}
 
 	// Metrics holds Prometheus metrics-related parameters.
-	Metrics MetricsConfig
+	Metrics = MetricsConfig{
+		WatchdogGoroutineThreshold:  10000,
+		WatchdogLockWaitThreshold:   5000, // in Milliseconds
+		WatchdogStallThreshold:      120,  // in Seconds
+		NamespaceUsageMaxNamespaces: 500,
+	}
 
 	// OVNKubernetesFeatureConfig holds OVN-Kubernetes feature enhancement config file parameters and command-line overrides
 	OVNKubernetesFeature = OVNKubernetesFeatureConfig{
@@ -172,6 +194,14 @@ var (
 		V4TransitSwitchSubnet: "168.254.0.0/16",
 		V6TransitSwitchSubnet: "fd97::/64",
 	}
+
+	// EmbeddedDB holds the configuration of the OVN databases managed by ovnkube-controller
+	EmbeddedDB = EmbeddedDBConfig{
+		DBDir:              "/etc/ovn",
+		CompactionInterval: 3600,
+		BackupInterval:     86400,
+		BackupCount:        3,
+	}
 )
 
 const (
@@ -242,6 +272,79 @@ type DefaultConfig struct {
 
 	// Zone name to which ovnkube-node/ovnkube-network-controller-manager belongs to
 	Zone string `gcfg:"zone"`
+
+	// DeterministicPodMAC derives pod MAC addresses from the pod's primary IP
+	// address on every network, made of PodMACPrefix followed by the four least
+	// significant bytes of the IP, so that a pod always gets the same MAC for the
+	// same IP and no two pods on a subnet of /96 or longer share a MAC
+	DeterministicPodMAC bool `gcfg:"deterministic-pod-mac"`
+	// RawPodMACPrefix holds the unparsed two byte pod MAC prefix. Should only be
+	// used inside config module.
+	RawPodMACPrefix string `gcfg:"pod-mac-prefix"`
+	// PodMACPrefix holds the parsed two byte locally administered unicast
+	// prefix of MAC addresses derived from IP addresses
+	PodMACPrefix net.HardwareAddr
+
+	// COPPDefaultRate is the rate in packets per second of the control plane
+	// protection meters of the protocols that have no explicit rate
+	COPPDefaultRate int `gcfg:"copp-default-rate"`
+	// RawCOPPRates holds the unparsed comma-separated list of <protocol>=<rate>
+	// control plane protection rates. Should only be used inside config module.
+	RawCOPPRates string `gcfg:"copp-rates"`
+	// COPPRates holds the parsed control plane protection rates in packets per
+	// second, keyed by OVN CoPP protocol name (eg, arp, nd-ns, dns, bfd)
+	COPPRates map[string]int
+
+	// RawClusterRouterOptions holds the unparsed comma-separated list of
+	// <option>=<value> OVN options of the cluster routers. Should only be used
+	// inside config module.
+	RawClusterRouterOptions string `gcfg:"cluster-router-options"`
+	// ClusterRouterOptions holds the parsed OVN options set on the cluster
+	// routers on top of the ones set by ovnkube
+	ClusterRouterOptions map[string]string
+	// RawGatewayRouterOptions holds the unparsed comma-separated list of
+	// <option>=<value> OVN options of the gateway routers. Should only be used
+	// inside config module.
+	RawGatewayRouterOptions string `gcfg:"gateway-router-options"`
+	// GatewayRouterOptions holds the parsed OVN options set on the gateway
+	// routers on top of the ones set by ovnkube
+	GatewayRouterOptions map[string]string
+	// RawPortSecurityExemptionCIDRs holds the unparsed comma-separated list of
+	// CIDRs the pods may claim extra IPs from with the port security exemption
+	// annotation. Should only be used inside config module.
+	RawPortSecurityExemptionCIDRs string `gcfg:"port-security-exemption-cidrs"`
+	// PortSecurityExemptionCIDRs holds the parsed CIDRs the pods may claim
+	// extra IPs from, the annotation is rejected when there is none
+	PortSecurityExemptionCIDRs []*net.IPNet
+
+	// RetryMaxBackoff is the maximum time in seconds between two retries of a
+	// resource that failed to be reconciled, the time doubles after every
+	// failed attempt starting from one second
+	RetryMaxBackoff int `gcfg:"retry-max-backoff"`
+	// RetryMaxFailedAttempts is the number of failed attempts after which the
+	// retry of a resource is given up, for resource types that don't define
+	// their own limit
+	RetryMaxFailedAttempts int `gcfg:"retry-max-failed-attempts"`
+	// RawRetryMaxFailedAttemptsOverrides holds the unparsed comma-separated
+	// list of <type>=<attempts> overrides of the retry limit. Should only be
+	// used inside config module.
+	RawRetryMaxFailedAttemptsOverrides string `gcfg:"retry-max-failed-attempts-overrides"`
+	// RetryMaxFailedAttemptsOverrides holds the parsed number of failed
+	// attempts after which the retry of a resource is given up, keyed by
+	// resource type name (eg, Pod, Node), 0 to never give up. They take
+	// precedence over the limits of the resource types.
+	RetryMaxFailedAttemptsOverrides map[string]int
+
+	// DryRun runs the network controller manager without changing anything:
+	// its OVN database transactions are logged instead of being sent, and its
+	// Kubernetes API writes are sent as server side dry-run requests
+	DryRun bool `gcfg:"dry-run"`
+
+	// ShutdownTimeout is the time budget in seconds of the shutdown of
+	// ovnkube. When it runs out, the components still running and the stacks
+	// of all goroutines are logged and ovnkube exits without stopping the
+	// remaining components. 0 waits forever.
+	ShutdownTimeout int `gcfg:"shutdown-timeout"`
 }
 
 // LoggingConfig holds logging-related parsed config file parameters and command-line overrides
@@ -323,6 +426,25 @@ type KubernetesConfig struct {
 	HostNetworkNamespace string `gcfg:"host-network-namespace"`
 	PlatformType         string `gcfg:"platform-type"`
 	HealthzBindAddress   string `gcfg:"healthz-bind-address"`
+	// RawWatchNamespaces holds the unparsed comma-separated list of namespaces that
+	// namespace-scoped informers are restricted to. Should only be used inside config module.
+	RawWatchNamespaces string `gcfg:"watch-namespaces"`
+	// WatchNamespaces holds the parsed list of namespaces that namespace-scoped
+	// informers are restricted to; empty means all namespaces are watched.
+	WatchNamespaces []string
+	// RawCoalesceUpdates holds the unparsed comma-separated list of resource kinds
+	// whose queued update events are coalesced. Should only be used inside config module.
+	RawCoalesceUpdates string `gcfg:"coalesce-updates"`
+	// CoalesceUpdates holds the parsed set of resource kinds (eg, Pod) for which
+	// only the newest pending update event of an object is delivered to handlers
+	CoalesceUpdates sets.String
+	// RawDeduplicateUpdates holds the unparsed comma-separated list of resource kinds
+	// whose no-op update events are dropped. Should only be used inside config module.
+	RawDeduplicateUpdates string `gcfg:"deduplicate-updates"`
+	// DeduplicateUpdates holds the parsed set of resource kinds (eg, EndpointSlice) for
+	// which update events that only change metadata maintained by the API server are
+	// not delivered to handlers
+	DeduplicateUpdates sets.String
 
 	// CompatMetricsBindAddress is overridden by the corresponding option in MetricsConfig
 	CompatMetricsBindAddress string `gcfg:"metrics-bind-address"`
@@ -340,10 +462,42 @@ type MetricsConfig struct {
 	EnablePprof           bool   `gcfg:"enable-pprof"`
 	NodeServerPrivKey     string `gcfg:"node-server-privkey"`
 	NodeServerCert        string `gcfg:"node-server-cert"`
+	// NodeServerClientCA is the CA bundle the metrics servers verify client certificates against. When set,
+	// the metrics servers only accept TLS clients presenting a certificate signed by one of these CAs
+	NodeServerClientCA string `gcfg:"node-server-client-ca"`
+	// RawNodeServerAllowedClients holds the unparsed comma-separated list of client certificate
+	// common names. Should only be used inside config module.
+	RawNodeServerAllowedClients string `gcfg:"node-server-allowed-clients"`
+	// NodeServerAllowedClients holds the parsed set of client certificate common names allowed
+	// to query the metrics servers. An empty set allows any client with a verified certificate
+	NodeServerAllowedClients sets.String
 	// EnableConfigDuration holds the boolean flag to enable OVN-Kubernetes master to monitor OVN-Kubernetes master
 	// configuration duration and optionally, its application to all nodes
 	EnableConfigDuration bool `gcfg:"enable-config-duration"`
 	EnableScaleMetrics   bool `gcfg:"enable-scale-metrics"`
+	// EnableWatchdog enables the internal watchdog that monitors goroutine counts,
+	// lock wait times and event loop stalls, and dumps goroutine stacks when any
+	// of the thresholds below is exceeded
+	EnableWatchdog bool `gcfg:"enable-watchdog"`
+	// WatchdogGoroutineThreshold is the number of goroutines above which the watchdog dumps stacks
+	WatchdogGoroutineThreshold int `gcfg:"watchdog-goroutine-threshold"`
+	// WatchdogLockWaitThreshold is the time in milliseconds spent waiting for a lock above which
+	// the watchdog dumps stacks
+	WatchdogLockWaitThreshold int `gcfg:"watchdog-lock-wait-threshold"`
+	// WatchdogStallThreshold is the time in seconds an event loop may spend processing a single
+	// event before the watchdog dumps stacks
+	WatchdogStallThreshold int `gcfg:"watchdog-stall-threshold"`
+	// NBAuditInterval is the time in seconds between two audits of the OVN northbound database
+	// against the state the controllers expect, 0 disables the periodic audit
+	NBAuditInterval int `gcfg:"nb-audit-interval"`
+	// NBAuditLogDiff logs the rows found missing, stale or modified by every audit
+	NBAuditLogDiff bool `gcfg:"nb-audit-log-diff"`
+	// NamespaceUsageInterval is the time in seconds between two collections of the traffic of the pods of the
+	// node per namespace, from the statistics of their OVS interfaces, 0 disables the collection
+	NamespaceUsageInterval int `gcfg:"namespace-usage-interval"`
+	// NamespaceUsageMaxNamespaces is the maximum number of namespaces the traffic is reported for by a node, the
+	// traffic of the other namespaces is reported under the "_other" namespace
+	NamespaceUsageMaxNamespaces int `gcfg:"namespace-usage-max-namespaces"`
 }
 
 // OVNKubernetesFeatureConfig holds OVN-Kubernetes feature enhancement config file parameters and command-line overrides
@@ -360,6 +514,11 @@ type OVNKubernetesFeatureConfig struct {
 	EnableMultiNetworkPolicy        bool `gcfg:"enable-multi-networkpolicy"`
 	EnableStatelessNetPol           bool `gcfg:"enable-stateless-netpol"`
 	EnableInterconnect              bool `gcfg:"enable-interconnect"`
+	EnableAdminNetworkPolicy        bool `gcfg:"enable-admin-network-policy"`
+	EnablePersistentIPs             bool `gcfg:"enable-persistent-ips"`
+	EnableTopologyAwareRouting      bool `gcfg:"enable-topology-aware-routing"`
+	// EgressIP failover latency SLO in seconds, 0 disables the SLO mode
+	EgressIPFailoverSLO int `gcfg:"egressip-failover-slo"`
 }
 
 // GatewayMode holds the node gateway mode
@@ -406,6 +565,10 @@ type GatewayConfig struct {
 	SingleNode bool `gcfg:"single-node"`
 	// DisableForwarding (enabled by default) controls if forwarding is allowed on OVNK controlled interfaces
 	DisableForwarding bool `gcfg:"disable-forwarding"`
+	// EgressMSS, when non-zero, is the maximum TCP MSS of pod egress traffic leaving the
+	// cluster through the gateway routers. It is needed when the upstream path MTU is lower
+	// than the overlay MTU and path MTU discovery does not work through the node SNAT.
+	EgressMSS int `gcfg:"egress-mss"`
 }
 
 // OvnAuthConfig holds client authentication and location details for
@@ -461,6 +624,26 @@ type ClusterManagerConfig struct {
 	V4TransitSwitchSubnet string `gcfg:"v4-transit-switch-subnet"`
 	// V6TransitSwitchSubnet to be used in the cluster for interconnecting multiple zones
 	V6TransitSwitchSubnet string `gcfg:"v6-transit-switch-subnet"`
+	// AllocationAuditInterval is the time in seconds between two audits of the allocations against the
+	// nodes, 0 disables the periodic audit
+	AllocationAuditInterval int `gcfg:"allocation-audit-interval"`
+}
+
+// EmbeddedDBConfig holds the configuration of the NB and SB databases and of ovn-northd when they are run by
+// ovnkube-controller itself, for interconnect zones with a single node
+type EmbeddedDBConfig struct {
+	// Enabled makes ovnkube-controller launch and monitor the databases and ovn-northd of its zone
+	Enabled bool `gcfg:"enabled"`
+	// DBDir is the directory holding the database files
+	DBDir string `gcfg:"db-dir"`
+	// CompactionInterval is the time in seconds between two compactions of the databases, 0 disables them
+	CompactionInterval int `gcfg:"compaction-interval"`
+	// BackupDir is the directory the databases are backed up to, backups are disabled if empty
+	BackupDir string `gcfg:"backup-dir"`
+	// BackupInterval is the time in seconds between two backups of the databases
+	BackupInterval int `gcfg:"backup-interval"`
+	// BackupCount is the number of backups kept for each database
+	BackupCount int `gcfg:"backup-count"`
 }
 
 // OvnDBScheme describes the OVN database connection transport method
@@ -493,6 +676,7 @@ type config struct {
 	HybridOverlay        HybridOverlayConfig
 	OvnKubeNode          OvnKubeNodeConfig
 	ClusterManager       ClusterManagerConfig
+	EmbeddedDB           EmbeddedDBConfig
 }
 
 var (
@@ -512,6 +696,7 @@ var (
 	savedHybridOverlay        HybridOverlayConfig
 	savedOvnKubeNode          OvnKubeNodeConfig
 	savedClusterManager       ClusterManagerConfig
+	savedEmbeddedDB           EmbeddedDBConfig
 
 	// legacy service-cluster-ip-range CLI option
 	serviceClusterIPRange string
@@ -542,6 +727,7 @@ func init() {
 	savedHybridOverlay = HybridOverlay
 	savedOvnKubeNode = OvnKubeNode
 	savedClusterManager = ClusterManager
+	savedEmbeddedDB = EmbeddedDB
 	cli.VersionPrinter = func(c *cli.Context) {
 		fmt.Printf("Version: %s\n", Version)
 		fmt.Printf("Git commit: %s\n", Commit)
@@ -572,6 +758,7 @@ func PrepareTestConfig() error {
 	HybridOverlay = savedHybridOverlay
 	OvnKubeNode = savedOvnKubeNode
 	ClusterManager = savedClusterManager
+	EmbeddedDB = savedEmbeddedDB
 
 	if err := completeConfig(); err != nil {
 		return err
@@ -664,6 +851,10 @@ var CommonFlags = []cli.Flag{
 		Name:  "init-node",
 		Usage: "initialize node, requires the name that node is registered with in kubernetes cluster",
 	},
+	&cli.StringFlag{
+		Name:  "init-all",
+		Usage: "initialize cluster-manager, network-controller-manager and node in a single process without leader election, requires the name that node is registered with in kubernetes cluster",
+	},
 	&cli.StringFlag{
 		Name:  "cleanup-node",
 		Usage: "cleanup node, requires the name that node is registered with in kubernetes cluster",
@@ -843,6 +1034,83 @@ var CommonFlags = []cli.Flag{
 		Value:       Default.Zone,
 		Destination: &cliConfig.Default.Zone,
 	},
+	&cli.BoolFlag{
+		Name:        "deterministic-pod-mac",
+		Usage:       "Derive pod MAC addresses from the pod's primary IP address on all networks, so the same IP always maps to the same MAC",
+		Destination: &cliConfig.Default.DeterministicPodMAC,
+	},
+	&cli.StringFlag{
+		Name:        "pod-mac-prefix",
+		Usage:       "Two byte locally administered prefix of MAC addresses derived from IP addresses (eg, 0a:58)",
+		Value:       Default.RawPodMACPrefix,
+		Destination: &cliConfig.Default.RawPodMACPrefix,
+	},
+	&cli.IntFlag{
+		Name:        "copp-default-rate",
+		Usage:       "Rate in packets per second of the control plane protection meters of protocols without an explicit rate",
+		Value:       Default.COPPDefaultRate,
+		Destination: &cliConfig.Default.COPPDefaultRate,
+	},
+	&cli.StringFlag{
+		Name: "copp-rates",
+		Usage: "A comma-separated list of <protocol>=<rate> control plane protection rates in packets per second " +
+			"applied on the gateway and cluster routers, where protocol is an OVN CoPP protocol name " +
+			"(eg, arp=100,nd-ns=100,dns=200). Protocols listed here are protected in addition to the default ones.",
+		Destination: &cliConfig.Default.RawCOPPRates,
+	},
+	&cli.StringFlag{
+		Name: "cluster-router-options",
+		Usage: "A comma-separated list of <option>=<value> OVN logical router options set on the cluster routers " +
+			"(eg, always_learn_from_arp_request=true,mac_binding_age_threshold=300)",
+		Destination: &cliConfig.Default.RawClusterRouterOptions,
+	},
+	&cli.StringFlag{
+		Name: "port-security-exemption-cidrs",
+		Usage: "A comma-separated list of CIDRs the pods may claim extra IPs from, such as the virtual IPs shared by " +
+			"VRRP pods, with the k8s.ovn.org/port-security-exemptions annotation (eg, 10.128.250.0/24,fd00:250::/64). " +
+			"The annotation is rejected when no CIDR is set.",
+		Destination: &cliConfig.Default.RawPortSecurityExemptionCIDRs,
+	},
+	&cli.StringFlag{
+		Name: "gateway-router-options",
+		Usage: "A comma-separated list of <option>=<value> OVN logical router options set on the gateway routers " +
+			"(eg, always_learn_from_arp_request=true,dynamic_neigh_routers=false)",
+		Destination: &cliConfig.Default.RawGatewayRouterOptions,
+	},
+	&cli.IntFlag{
+		Name:        "retry-max-backoff",
+		Usage:       "Maximum time in seconds between two retries of a resource that failed to be reconciled",
+		Value:       Default.RetryMaxBackoff,
+		Destination: &cliConfig.Default.RetryMaxBackoff,
+	},
+	&cli.IntFlag{
+		Name:        "retry-max-failed-attempts",
+		Usage:       "Number of failed attempts after which the retry of a resource is given up, for resource types without their own limit",
+		Value:       Default.RetryMaxFailedAttempts,
+		Destination: &cliConfig.Default.RetryMaxFailedAttempts,
+	},
+	&cli.StringFlag{
+		Name: "retry-max-failed-attempts-overrides",
+		Usage: "A comma-separated list of <type>=<attempts> numbers of failed attempts after which the retry of a " +
+			"resource of the given type is given up, where type is a resource type name as reported by the " +
+			"ovnkube_resource_retry_entries metric and 0 never gives up (eg, Node=15,Pod=0). They take precedence " +
+			"over -retry-max-failed-attempts and over the limits of the resource types.",
+		Destination: &cliConfig.Default.RawRetryMaxFailedAttemptsOverrides,
+	},
+	&cli.IntFlag{
+		Name: "shutdown-timeout",
+		Usage: "Time budget in seconds of the shutdown of ovnkube, after which the components still running are " +
+			"logged and ovnkube exits anyway. 0 waits forever.",
+		Value:       Default.ShutdownTimeout,
+		Destination: &cliConfig.Default.ShutdownTimeout,
+	},
+	&cli.BoolFlag{
+		Name: "dry-run",
+		Usage: "Run the network controller manager without changing anything: log the OVN database transactions " +
+			"instead of sending them and send the Kubernetes API writes as dry-run requests. Only supported " +
+			"with init-network-controller-manager, without leader election.",
+		Destination: &cliConfig.Default.DryRun,
+	},
 }
 
 // MonitoringFlags capture monitoring-related options
@@ -940,6 +1208,13 @@ var OVNK8sFeatureFlags = []cli.Flag{
 		Usage:       "Configure EgressIP node reachability using gRPC on this TCP port.",
 		Destination: &cliConfig.OVNKubernetesFeature.EgressIPNodeHealthCheckPort,
 	},
+	&cli.IntFlag{
+		Name: "egressip-failover-slo",
+		Usage: "Target time in seconds to detect an unreachable egress node and reassign its egress IPs. " +
+			"When set, egress nodes are health checked concurrently at an interval derived from it, " +
+			"which requires egressip-node-healthcheck-port (default: 0, disabled)",
+		Destination: &cliConfig.OVNKubernetesFeature.EgressIPFailoverSLO,
+	},
 	&cli.BoolFlag{
 		Name:        "enable-multi-network",
 		Usage:       "Configure to use multiple NetworkAttachmentDefinition CRD feature with ovn-kubernetes.",
@@ -970,6 +1245,24 @@ var OVNK8sFeatureFlags = []cli.Flag{
 		Destination: &cliConfig.OVNKubernetesFeature.EnableEgressService,
 		Value:       OVNKubernetesFeature.EnableEgressService,
 	},
+	&cli.BoolFlag{
+		Name:        "enable-admin-network-policy",
+		Usage:       "Configure to use the AdminNetworkPolicy and BaselineAdminNetworkPolicy CRDs of sigs.k8s.io/network-policy-api with ovn-kubernetes.",
+		Destination: &cliConfig.OVNKubernetesFeature.EnableAdminNetworkPolicy,
+		Value:       OVNKubernetesFeature.EnableAdminNetworkPolicy,
+	},
+	&cli.BoolFlag{
+		Name:        "enable-persistent-ips",
+		Usage:       "Configure to use the IPAMClaim CRD to keep the IPs of pods on layer2 and localnet secondary networks across pod recreation.",
+		Destination: &cliConfig.OVNKubernetesFeature.EnablePersistentIPs,
+		Value:       OVNKubernetesFeature.EnablePersistentIPs,
+	},
+	&cli.BoolFlag{
+		Name:        "enable-topology-aware-routing",
+		Usage:       "Configure to honor the zone hints of the EndpointSlices of services with topology aware routing, keeping their traffic within the zone of the node when possible.",
+		Destination: &cliConfig.OVNKubernetesFeature.EnableTopologyAwareRouting,
+		Value:       OVNKubernetesFeature.EnableTopologyAwareRouting,
+	},
 }
 
 // K8sFlags capture Kubernetes-related options
@@ -1060,6 +1353,27 @@ var K8sFlags = []cli.Flag{
 		Usage:       "The IP address and port for the node proxy healthz server to serve on (set to '0.0.0.0:10256' or '[::]:10256' for listening in all interfaces and IP families). Disabled by default.",
 		Destination: &cliConfig.Kubernetes.HealthzBindAddress,
 	},
+	&cli.StringFlag{
+		Name: "watch-namespaces",
+		Usage: "A comma-separated list of namespaces that namespace-scoped resources (pods, services, " +
+			"endpointslices, network policies, ...) are listed and watched in. Cluster-scoped " +
+			"resources are always watched cluster-wide. (default: all namespaces)",
+		Destination: &cliConfig.Kubernetes.RawWatchNamespaces,
+	},
+	&cli.StringFlag{
+		Name: "coalesce-updates",
+		Usage: "A comma-separated list of resource kinds (Pod, Namespace, Node) for which rapid " +
+			"update events of the same object are coalesced, so that handlers only see the " +
+			"newest pending update.",
+		Destination: &cliConfig.Kubernetes.RawCoalesceUpdates,
+	},
+	&cli.StringFlag{
+		Name: "deduplicate-updates",
+		Usage: "A comma-separated list of resource kinds (EndpointSlice) for which update " +
+			"events that only change the resource version or managed fields are dropped " +
+			"before reaching handlers.",
+		Destination: &cliConfig.Kubernetes.RawDeduplicateUpdates,
+	},
 }
 
 // MetricsFlags capture metrics-related options
@@ -1095,6 +1409,19 @@ var MetricsFlags = []cli.Flag{
 		Usage:       "Certificate that the OVN node K8s metrics server uses to serve metrics over TLS.",
 		Destination: &cliConfig.Metrics.NodeServerCert,
 	},
+	&cli.StringFlag{
+		Name: "node-server-client-ca",
+		Usage: "CA bundle that the metrics servers use to verify client certificates. When set, " +
+			"only TLS clients presenting a certificate signed by one of these CAs are served. " +
+			"Requires node-server-cert and node-server-privkey.",
+		Destination: &cliConfig.Metrics.NodeServerClientCA,
+	},
+	&cli.StringFlag{
+		Name: "node-server-allowed-clients",
+		Usage: "A comma-separated list of client certificate common names allowed to query " +
+			"the metrics servers. Requires node-server-client-ca.",
+		Destination: &cliConfig.Metrics.RawNodeServerAllowedClients,
+	},
 	&cli.BoolFlag{
 		Name:        "metrics-enable-config-duration",
 		Usage:       "Enables monitoring OVN-Kubernetes master and OVN configuration duration",
@@ -1105,6 +1432,50 @@ var MetricsFlags = []cli.Flag{
 		Usage:       "Enables metrics related to scaling",
 		Destination: &cliConfig.Metrics.EnableScaleMetrics,
 	},
+	&cli.BoolFlag{
+		Name:        "metrics-enable-watchdog",
+		Usage:       "Enables a watchdog that monitors goroutine counts, lock contention and event loop stalls and dumps goroutine stacks when thresholds are exceeded",
+		Destination: &cliConfig.Metrics.EnableWatchdog,
+	},
+	&cli.IntFlag{
+		Name:        "metrics-watchdog-goroutine-threshold",
+		Usage:       "Number of goroutines above which the watchdog dumps goroutine stacks (0 disables the check)",
+		Destination: &cliConfig.Metrics.WatchdogGoroutineThreshold,
+		Value:       Metrics.WatchdogGoroutineThreshold,
+	},
+	&cli.IntFlag{
+		Name:        "metrics-watchdog-lock-wait-threshold",
+		Usage:       "Time in milliseconds spent waiting for a lock above which the watchdog dumps goroutine stacks (0 disables the check)",
+		Destination: &cliConfig.Metrics.WatchdogLockWaitThreshold,
+		Value:       Metrics.WatchdogLockWaitThreshold,
+	},
+	&cli.IntFlag{
+		Name:        "metrics-watchdog-stall-threshold",
+		Usage:       "Time in seconds an event loop may spend processing a single event before the watchdog dumps goroutine stacks (0 disables the check)",
+		Destination: &cliConfig.Metrics.WatchdogStallThreshold,
+		Value:       Metrics.WatchdogStallThreshold,
+	},
+	&cli.IntFlag{
+		Name:        "metrics-nb-audit-interval",
+		Usage:       "Time in seconds between two audits of the OVN northbound database against the expected state, exporting the drift as metrics (0 disables the periodic audit)",
+		Destination: &cliConfig.Metrics.NBAuditInterval,
+	},
+	&cli.BoolFlag{
+		Name:        "metrics-nb-audit-log-diff",
+		Usage:       "Log the rows found missing, stale or modified by every audit of the OVN northbound database",
+		Destination: &cliConfig.Metrics.NBAuditLogDiff,
+	},
+	&cli.IntFlag{
+		Name:        "metrics-namespace-usage-interval",
+		Usage:       "Time in seconds between two collections of the traffic of the node pods per namespace, from the statistics of their OVS interfaces (0 disables the collection)",
+		Destination: &cliConfig.Metrics.NamespaceUsageInterval,
+	},
+	&cli.IntFlag{
+		Name:        "metrics-namespace-usage-max-namespaces",
+		Usage:       "Maximum number of namespaces the traffic is reported for by a node, the traffic of the other namespaces is reported under the \"_other\" namespace",
+		Destination: &cliConfig.Metrics.NamespaceUsageMaxNamespaces,
+		Value:       Metrics.NamespaceUsageMaxNamespaces,
+	},
 }
 
 // OvnNBFlags capture OVN northbound database options
@@ -1259,6 +1630,13 @@ var OVNGatewayFlags = []cli.Flag{
 		Usage:       "Disable OpenFlow checks for if packet size is greater than pod MTU",
 		Destination: &cliConfig.Gateway.DisablePacketMTUCheck,
 	},
+	&cli.IntFlag{
+		Name: "gateway-egress-mss",
+		Usage: "Clamp the TCP MSS of pod egress traffic leaving the cluster through the gateway " +
+			"routers to this value. Useful when the upstream path MTU is lower than the overlay MTU. " +
+			"(default: 0, disabled)",
+		Destination: &cliConfig.Gateway.EgressMSS,
+	},
 	&cli.StringFlag{
 		Name: "gateway-router-subnet",
 		Usage: "The Subnet to be used for the gateway router external port (shared mode only). " +
@@ -1400,6 +1778,52 @@ var ClusterManagerFlags = []cli.Flag{
 		Destination: &cliConfig.ClusterManager.V6TransitSwitchSubnet,
 		Value:       ClusterManager.V6TransitSwitchSubnet,
 	},
+	&cli.IntFlag{
+		Name:        "cluster-manager-allocation-audit-interval",
+		Usage:       "Time in seconds between two audits of the node subnets and IDs against the nodes, exporting the leaked allocations as metrics (0 disables the periodic audit)",
+		Destination: &cliConfig.ClusterManager.AllocationAuditInterval,
+	},
+}
+
+// EmbeddedDBFlags captures the configuration of the OVN databases managed by ovnkube-controller
+var EmbeddedDBFlags = []cli.Flag{
+	&cli.BoolFlag{
+		Name: "enable-embedded-dbs",
+		Usage: "Launch, monitor, compact and back up the NB and SB databases and ovn-northd of the zone " +
+			"from ovnkube-controller. Only for interconnect zones with a single node.",
+		Destination: &cliConfig.EmbeddedDB.Enabled,
+		Value:       EmbeddedDB.Enabled,
+	},
+	&cli.StringFlag{
+		Name:        "embedded-db-dir",
+		Usage:       "The directory holding the files of the embedded databases",
+		Destination: &cliConfig.EmbeddedDB.DBDir,
+		Value:       EmbeddedDB.DBDir,
+	},
+	&cli.IntFlag{
+		Name:        "embedded-db-compaction-interval",
+		Usage:       "The time in seconds between two compactions of the embedded databases, 0 disables them",
+		Destination: &cliConfig.EmbeddedDB.CompactionInterval,
+		Value:       EmbeddedDB.CompactionInterval,
+	},
+	&cli.StringFlag{
+		Name:        "embedded-db-backup-dir",
+		Usage:       "The directory the embedded databases are backed up to. Backups are disabled if not set.",
+		Destination: &cliConfig.EmbeddedDB.BackupDir,
+		Value:       EmbeddedDB.BackupDir,
+	},
+	&cli.IntFlag{
+		Name:        "embedded-db-backup-interval",
+		Usage:       "The time in seconds between two backups of the embedded databases",
+		Destination: &cliConfig.EmbeddedDB.BackupInterval,
+		Value:       EmbeddedDB.BackupInterval,
+	},
+	&cli.IntFlag{
+		Name:        "embedded-db-backup-count",
+		Usage:       "The number of backups kept for each embedded database",
+		Destination: &cliConfig.EmbeddedDB.BackupCount,
+		Value:       EmbeddedDB.BackupCount,
+	},
 }
 
 // Flags are general command-line flags. Apps should add these flags to their
@@ -1424,6 +1848,7 @@ func GetFlags(customFlags []cli.Flag) []cli.Flag {
 	flags = append(flags, IPFIXFlags...)
 	flags = append(flags, OvnKubeNodeFlags...)
 	flags = append(flags, ClusterManagerFlags...)
+	flags = append(flags, EmbeddedDBFlags...)
 	flags = append(flags, customFlags...)
 	return flags
 }
@@ -1616,9 +2041,63 @@ func completeKubernetesConfig(allSubnets *configSubnets) error {
 		}
 	}
 
+	Kubernetes.WatchNamespaces = nil
+	if Kubernetes.RawWatchNamespaces != "" {
+		seen := sets.NewString()
+		for _, namespace := range strings.Split(Kubernetes.RawWatchNamespaces, ",") {
+			namespace = strings.TrimSpace(namespace)
+			if namespace == "" || seen.Has(namespace) {
+				continue
+			}
+			if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+				return fmt.Errorf("watch-namespaces entry %q is invalid: %s", namespace, strings.Join(errs, ", "))
+			}
+			seen.Insert(namespace)
+			Kubernetes.WatchNamespaces = append(Kubernetes.WatchNamespaces, namespace)
+		}
+	}
+
+	Kubernetes.CoalesceUpdates = sets.NewString()
+	if Kubernetes.RawCoalesceUpdates != "" {
+		for _, kind := range strings.Split(Kubernetes.RawCoalesceUpdates, ",") {
+			kind = strings.TrimSpace(kind)
+			if kind == "" {
+				continue
+			}
+			if !coalescableKinds.Has(kind) {
+				return fmt.Errorf("coalesce-updates entry %q is invalid: must be one of %s",
+					kind, strings.Join(coalescableKinds.List(), ", "))
+			}
+			Kubernetes.CoalesceUpdates.Insert(kind)
+		}
+	}
+
+	Kubernetes.DeduplicateUpdates = sets.NewString()
+	if Kubernetes.RawDeduplicateUpdates != "" {
+		for _, kind := range strings.Split(Kubernetes.RawDeduplicateUpdates, ",") {
+			kind = strings.TrimSpace(kind)
+			if kind == "" {
+				continue
+			}
+			if !deduplicatableKinds.Has(kind) {
+				return fmt.Errorf("deduplicate-updates entry %q is invalid: must be one of %s",
+					kind, strings.Join(deduplicatableKinds.List(), ", "))
+			}
+			Kubernetes.DeduplicateUpdates.Insert(kind)
+		}
+	}
+
 	return nil
 }
 
+// coalescableKinds are the resource kinds whose informers deliver events
+// through per-object queues, and so can coalesce update events
+var coalescableKinds = sets.NewString("Namespace", "Node", "Pod")
+
+// deduplicatableKinds are the resource kinds for which the watch factory knows
+// how to detect no-op update events
+var deduplicatableKinds = sets.NewString("EndpointSlice")
+
 func buildMetricsConfig(cli, file *config) error {
 	// Copy KubernetesConfig backwards-compat values over default values
 	if Kubernetes.CompatMetricsBindAddress != "" {
@@ -1639,6 +2118,35 @@ func buildMetricsConfig(cli, file *config) error {
 		return err
 	}
 
+	if Metrics.WatchdogGoroutineThreshold < 0 || Metrics.WatchdogLockWaitThreshold < 0 || Metrics.WatchdogStallThreshold < 0 {
+		return fmt.Errorf("metrics watchdog thresholds must not be negative")
+	}
+	if Metrics.NBAuditInterval < 0 {
+		return fmt.Errorf("invalid metrics-nb-audit-interval %d, must not be negative", Metrics.NBAuditInterval)
+	}
+	if Metrics.NamespaceUsageInterval < 0 {
+		return fmt.Errorf("invalid metrics-namespace-usage-interval %d, must not be negative", Metrics.NamespaceUsageInterval)
+	}
+	if Metrics.NamespaceUsageMaxNamespaces < 1 {
+		return fmt.Errorf("invalid metrics-namespace-usage-max-namespaces %d, must be positive", Metrics.NamespaceUsageMaxNamespaces)
+	}
+	if Metrics.NodeServerClientCA != "" && (Metrics.NodeServerCert == "" || Metrics.NodeServerPrivKey == "") {
+		return fmt.Errorf("node-server-client-ca requires node-server-cert and node-server-privkey")
+	}
+
+	Metrics.NodeServerAllowedClients = sets.NewString()
+	if Metrics.RawNodeServerAllowedClients != "" {
+		if Metrics.NodeServerClientCA == "" {
+			return fmt.Errorf("node-server-allowed-clients requires node-server-client-ca")
+		}
+		for _, name := range strings.Split(Metrics.RawNodeServerAllowedClients, ",") {
+			name = strings.TrimSpace(name)
+			if name != "" {
+				Metrics.NodeServerAllowedClients.Insert(name)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -1691,6 +2199,22 @@ func buildGatewayConfig(ctx *cli.Context, cli, file *config) error {
 		return fmt.Errorf("gateway VLAN ID option: %d is supported only in shared gateway mode", Gateway.VLANID)
 	}
 
+	if err := validateEgressMSS(Gateway.EgressMSS); err != nil {
+		return fmt.Errorf("invalid gateway egress-mss option: %v", err)
+	}
+
+	return nil
+}
+
+// validateEgressMSS checks that a TCP MSS clamp value is either zero (disabled)
+// or within the range of a valid MSS.
+func validateEgressMSS(mss int) error {
+	if mss == 0 {
+		return nil
+	}
+	if mss < MinEgressMSS || mss > MaxEgressMSS {
+		return fmt.Errorf("MSS %d is out of range, must be between %d and %d", mss, MinEgressMSS, MaxEgressMSS)
+	}
 	return nil
 }
 
@@ -1721,6 +2245,12 @@ func buildOVNKubernetesFeatureConfig(ctx *cli.Context, cli, file *config) error
 	if err := overrideFields(&OVNKubernetesFeature, &cli.OVNKubernetesFeature, &savedOVNKubernetesFeature); err != nil {
 		return err
 	}
+	if OVNKubernetesFeature.EgressIPFailoverSLO < 0 {
+		return fmt.Errorf("invalid egressip-failover-slo %d, must not be negative", OVNKubernetesFeature.EgressIPFailoverSLO)
+	}
+	if OVNKubernetesFeature.EgressIPFailoverSLO > 0 && OVNKubernetesFeature.EgressIPNodeHealthCheckPort == 0 {
+		return fmt.Errorf("egressip-failover-slo requires egressip-node-healthcheck-port to be set")
+	}
 	return nil
 }
 
@@ -1870,18 +2400,69 @@ func buildClusterManagerConfig(ctx *cli.Context, cli, file *config) error {
 
 // completeClusterManagerConfig completes the ClusterManager config by parsing raw values
 // into their final form.
-func completeClusterManagerConfig() error {
+func completeClusterManagerConfig(allSubnets *configSubnets) error {
 	// Validate v4 and v6 transit switch subnets
-	v4IP, _, err := net.ParseCIDR(ClusterManager.V4TransitSwitchSubnet)
+	v4IP, v4TransitSwitchCIDR, err := net.ParseCIDR(ClusterManager.V4TransitSwitchSubnet)
 	if err != nil || utilnet.IsIPv6(v4IP) {
 		return fmt.Errorf("invalid transit switch v4 subnet specified, subnet: %s: error: %v", ClusterManager.V4TransitSwitchSubnet, err)
 	}
 
-	v6IP, _, err := net.ParseCIDR(ClusterManager.V6TransitSwitchSubnet)
+	v6IP, v6TransitSwitchCIDR, err := net.ParseCIDR(ClusterManager.V6TransitSwitchSubnet)
 	if err != nil || !utilnet.IsIPv6(v6IP) {
-		return fmt.Errorf("invalid transit switch v4 join subnet specified, subnet: %s: error: %v", ClusterManager.V6TransitSwitchSubnet, err)
+		return fmt.Errorf("invalid transit switch v6 subnet specified, subnet: %s: error: %v", ClusterManager.V6TransitSwitchSubnet, err)
+	}
+	// the transit switch subnets are only routed in interconnect mode
+	if OVNKubernetesFeature.EnableInterconnect {
+		allSubnets.append(configSubnetTransitSwitch, v4TransitSwitchCIDR)
+		allSubnets.append(configSubnetTransitSwitch, v6TransitSwitchCIDR)
+	}
+
+	if ClusterManager.AllocationAuditInterval < 0 {
+		return fmt.Errorf("invalid cluster-manager-allocation-audit-interval %d, must not be negative",
+			ClusterManager.AllocationAuditInterval)
+	}
+
+	return nil
+}
+
+func buildEmbeddedDBConfig(cli, file *config) error {
+	// Copy config file values over default values
+	if err := overrideFields(&EmbeddedDB, &file.EmbeddedDB, &savedEmbeddedDB); err != nil {
+		return err
 	}
 
+	// And CLI overrides over config file and default values
+	return overrideFields(&EmbeddedDB, &cli.EmbeddedDB, &savedEmbeddedDB)
+}
+
+// completeEmbeddedDBConfig validates the EmbeddedDB config. The embedded databases only serve the zone of a single
+// node, they are reached through their local unix sockets.
+func completeEmbeddedDBConfig() error {
+	if !EmbeddedDB.Enabled {
+		return nil
+	}
+	if !OVNKubernetesFeature.EnableInterconnect {
+		return fmt.Errorf("embedded databases require interconnect to be enabled")
+	}
+	if OvnNorth.Scheme != OvnDBSchemeUnix || OvnSouth.Scheme != OvnDBSchemeUnix {
+		return fmt.Errorf("embedded databases must be reached through unix sockets, got NB address %q and SB address %q",
+			OvnNorth.Address, OvnSouth.Address)
+	}
+	if EmbeddedDB.DBDir == "" {
+		return fmt.Errorf("embedded-db-dir must be set")
+	}
+	if EmbeddedDB.CompactionInterval < 0 {
+		return fmt.Errorf("invalid embedded-db-compaction-interval %d, must not be negative",
+			EmbeddedDB.CompactionInterval)
+	}
+	if EmbeddedDB.BackupDir != "" {
+		if EmbeddedDB.BackupInterval <= 0 {
+			return fmt.Errorf("invalid embedded-db-backup-interval %d, must be positive", EmbeddedDB.BackupInterval)
+		}
+		if EmbeddedDB.BackupCount < 1 {
+			return fmt.Errorf("invalid embedded-db-backup-count %d, must be at least 1", EmbeddedDB.BackupCount)
+		}
+	}
 	return nil
 }
 
@@ -1920,9 +2501,182 @@ func completeDefaultConfig(allSubnets *configSubnets) error {
 		allSubnets.append(configSubnetCluster, subnet.CIDR)
 	}
 
+	Default.PodMACPrefix, err = ParseMACPrefix(Default.RawPodMACPrefix)
+	if err != nil {
+		return fmt.Errorf("pod-mac-prefix invalid: %v", err)
+	}
+
+	if Default.COPPDefaultRate <= 0 {
+		return fmt.Errorf("copp-default-rate %d is invalid: must be greater than zero", Default.COPPDefaultRate)
+	}
+	Default.COPPRates, err = ParseCOPPRates(Default.RawCOPPRates)
+	if err != nil {
+		return fmt.Errorf("copp-rates invalid: %v", err)
+	}
+	Default.ClusterRouterOptions, err = ParseRouterOptions(Default.RawClusterRouterOptions)
+	if err != nil {
+		return fmt.Errorf("cluster-router-options invalid: %v", err)
+	}
+	Default.GatewayRouterOptions, err = ParseRouterOptions(Default.RawGatewayRouterOptions)
+	if err != nil {
+		return fmt.Errorf("gateway-router-options invalid: %v", err)
+	}
+	Default.PortSecurityExemptionCIDRs = nil
+	for _, cidr := range strings.Split(Default.RawPortSecurityExemptionCIDRs, ",") {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return fmt.Errorf("port-security-exemption-cidrs invalid: %v", err)
+		}
+		Default.PortSecurityExemptionCIDRs = append(Default.PortSecurityExemptionCIDRs, ipNet)
+	}
+
+	if Default.RetryMaxBackoff <= 0 {
+		return fmt.Errorf("retry-max-backoff %d is invalid: must be greater than zero", Default.RetryMaxBackoff)
+	}
+	if Default.RetryMaxFailedAttempts <= 0 {
+		return fmt.Errorf("retry-max-failed-attempts %d is invalid: must be greater than zero", Default.RetryMaxFailedAttempts)
+	}
+	Default.RetryMaxFailedAttemptsOverrides, err = ParseRetryMaxFailedAttemptsOverrides(Default.RawRetryMaxFailedAttemptsOverrides)
+	if err != nil {
+		return fmt.Errorf("retry-max-failed-attempts-overrides invalid: %v", err)
+	}
+	if Default.ShutdownTimeout < 0 {
+		return fmt.Errorf("shutdown-timeout %d is invalid: must not be negative", Default.ShutdownTimeout)
+	}
+
 	return nil
 }
 
+// coppProtocols are the protocol names supported by OVN control plane protection
+var coppProtocols = sets.NewString("arp", "arp-resolve", "bfd", "dhcpv4-opts", "dhcpv6-opts", "dns",
+	"event-elb", "icmp4-error", "icmp6-error", "igmp", "nd-na", "nd-ns", "nd-ns-resolve", "nd-ra-opts",
+	"reject", "svc-monitor", "tcp-reset")
+
+// ParseCOPPRates parses a comma-separated list of <protocol>=<rate> entries
+func ParseCOPPRates(raw string) (map[string]int, error) {
+	rates := map[string]int{}
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, "=")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("entry %q must be of the form <protocol>=<rate>", entry)
+		}
+		protocol := strings.TrimSpace(parts[0])
+		if !coppProtocols.Has(protocol) {
+			return nil, fmt.Errorf("unsupported protocol %q, must be one of %s", protocol,
+				strings.Join(coppProtocols.List(), ", "))
+		}
+		rate, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("rate %q of protocol %q must be a number greater than zero", parts[1], protocol)
+		}
+		rates[protocol] = rate
+	}
+	return rates, nil
+}
+
+// ParseRetryMaxFailedAttemptsOverrides parses a comma-separated list of <type>=<attempts> entries
+func ParseRetryMaxFailedAttemptsOverrides(raw string) (map[string]int, error) {
+	overrides := map[string]int{}
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, "=")
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("entry %q must be of the form <type>=<attempts>", entry)
+		}
+		resourceType := strings.TrimSpace(parts[0])
+		attempts, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil || attempts < 0 {
+			return nil, fmt.Errorf("attempts %q of type %q must be a number not lower than zero", parts[1], resourceType)
+		}
+		overrides[resourceType] = attempts
+	}
+	return overrides, nil
+}
+
+// routerOptionKind is the kind of value of a tunable OVN logical router option
+type routerOptionKind int
+
+const (
+	routerOptionBool routerOptionKind = iota
+	routerOptionSeconds
+)
+
+// routerOptions are the OVN logical router options that can be configured.
+// The options ovnkube derives from the cluster state, like chassis or
+// lb_force_snat_ip, can't be.
+var routerOptions = map[string]routerOptionKind{
+	"always_learn_from_arp_request": routerOptionBool,
+	"dynamic_neigh_routers":         routerOptionBool,
+	"ct-commit-all":                 routerOptionBool,
+	"mac_binding_age_threshold":     routerOptionSeconds,
+}
+
+// ParseRouterOptions parses a comma-separated list of <option>=<value> entries
+func ParseRouterOptions(raw string) (map[string]string, error) {
+	options := map[string]string{}
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, "=")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("entry %q must be of the form <option>=<value>", entry)
+		}
+		option := strings.TrimSpace(parts[0])
+		value := strings.TrimSpace(parts[1])
+		kind, ok := routerOptions[option]
+		if !ok {
+			return nil, fmt.Errorf("unsupported option %q, must be one of %s", option,
+				strings.Join(sets.StringKeySet(routerOptions).List(), ", "))
+		}
+		switch kind {
+		case routerOptionBool:
+			if value != "true" && value != "false" {
+				return nil, fmt.Errorf("value %q of option %q must be true or false", value, option)
+			}
+		case routerOptionSeconds:
+			if seconds, err := strconv.Atoi(value); err != nil || seconds < 0 {
+				return nil, fmt.Errorf("value %q of option %q must be a number of seconds", value, option)
+			}
+		}
+		options[option] = value
+	}
+	return options, nil
+}
+
+// ParseMACPrefix parses a two byte MAC prefix in colon separated hex form,
+// which must be a locally administered unicast prefix
+func ParseMACPrefix(prefix string) (net.HardwareAddr, error) {
+	octets := strings.Split(prefix, ":")
+	if len(octets) != 2 {
+		return nil, fmt.Errorf("%q must consist of two octets", prefix)
+	}
+	mac := make(net.HardwareAddr, 0, 2)
+	for _, octet := range octets {
+		b, err := strconv.ParseUint(octet, 16, 8)
+		if err != nil || len(octet) != 2 {
+			return nil, fmt.Errorf("%q has an invalid octet %q", prefix, octet)
+		}
+		mac = append(mac, byte(b))
+	}
+	if mac[0]&0x01 != 0 || mac[0]&0x02 == 0 {
+		return nil, fmt.Errorf("%q is not a locally administered unicast prefix", prefix)
+	}
+	return mac, nil
+}
+
 // getConfigFilePath returns config file path and 'true' if the config file is
 // the fallback path (eg not given by the user), 'false' if given explicitly
 // by the user
@@ -1974,6 +2728,7 @@ func initConfigWithPath(ctx *cli.Context, exec kexec.Interface, saPath string, d
 		Logging:              savedLogging,
 		IPFIX:                savedIPFIX,
 		CNI:                  savedCNI,
+		Metrics:              savedMetrics,
 		OVNKubernetesFeature: savedOVNKubernetesFeature,
 		Kubernetes:           savedKubernetes,
 		OvnNorth:             savedOvnNorth,
@@ -1983,6 +2738,7 @@ func initConfigWithPath(ctx *cli.Context, exec kexec.Interface, saPath string, d
 		HybridOverlay:        savedHybridOverlay,
 		OvnKubeNode:          savedOvnKubeNode,
 		ClusterManager:       savedClusterManager,
+		EmbeddedDB:           savedEmbeddedDB,
 	}
 
 	configFile, configFileIsDefault = getConfigFilePath(ctx)
@@ -2104,6 +2860,10 @@ func initConfigWithPath(ctx *cli.Context, exec kexec.Interface, saPath string, d
 		return "", err
 	}
 
+	if err = buildEmbeddedDBConfig(&cliConfig, &cfg); err != nil {
+		return "", err
+	}
+
 	tmpAuth, err := buildOvnAuth(exec, true, &cliConfig.OvnNorth, &cfg.OvnNorth, defaults.OvnNorthAddress)
 	if err != nil {
 		return "", err
@@ -2132,6 +2892,7 @@ func initConfigWithPath(ctx *cli.Context, exec kexec.Interface, saPath string, d
 	klog.V(5).Infof("Hybrid Overlay config: %+v", HybridOverlay)
 	klog.V(5).Infof("Ovnkube Node config: %+v", OvnKubeNode)
 	klog.V(5).Infof("Ovnkube Cluster Manager config: %+v", ClusterManager)
+	klog.V(5).Infof("Embedded DB config: %+v", EmbeddedDB)
 
 	return retConfigFile, nil
 }
@@ -2155,7 +2916,11 @@ func completeConfig() error {
 		return err
 	}
 
-	if err := completeClusterManagerConfig(); err != nil {
+	if err := completeClusterManagerConfig(allSubnets); err != nil {
+		return err
+	}
+
+	if err := completeEmbeddedDBConfig(); err != nil {
 		return err
 	}
 
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"io/ioutil"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
@@ -847,6 +848,129 @@ service-cidr=172.18.0.0/24
 		gomega.Expect(err).NotTo(gomega.HaveOccurred())
 	})
 
+	It("parses and de-duplicates the watch-namespaces option", func() {
+		app.Action = func(ctx *cli.Context) error {
+			_, err := InitConfig(ctx, kexec.New(), nil)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(Kubernetes.WatchNamespaces).To(gomega.Equal([]string{"tenant-a", "tenant-b"}))
+			return nil
+		}
+		cliArgs := []string{
+			app.Name,
+			"-watch-namespaces=tenant-a, tenant-b,tenant-a",
+		}
+		err := app.Run(cliArgs)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	It("returns an error when a watch-namespaces entry is invalid", func() {
+		app.Action = func(ctx *cli.Context) error {
+			_, err := InitConfig(ctx, kexec.New(), nil)
+			gomega.Expect(err).To(gomega.HaveOccurred())
+			gomega.Expect(err.Error()).To(gomega.ContainSubstring("watch-namespaces entry \"Not_Valid\" is invalid"))
+			return nil
+		}
+		cliArgs := []string{
+			app.Name,
+			"-watch-namespaces=default,Not_Valid",
+		}
+		err := app.Run(cliArgs)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	It("parses the coalesce-updates resource kinds", func() {
+		app.Action = func(ctx *cli.Context) error {
+			_, err := InitConfig(ctx, kexec.New(), nil)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(Kubernetes.CoalesceUpdates.List()).To(gomega.Equal([]string{"Node", "Pod"}))
+			return nil
+		}
+		cliArgs := []string{
+			app.Name,
+			"-coalesce-updates=Pod, Node",
+		}
+		err := app.Run(cliArgs)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	It("returns an error when a coalesce-updates entry is not a queued resource kind", func() {
+		app.Action = func(ctx *cli.Context) error {
+			_, err := InitConfig(ctx, kexec.New(), nil)
+			gomega.Expect(err).To(gomega.MatchError("coalesce-updates entry \"Service\" is invalid: must be one of Namespace, Node, Pod"))
+			return nil
+		}
+		cliArgs := []string{
+			app.Name,
+			"-coalesce-updates=Pod,Service",
+		}
+		err := app.Run(cliArgs)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	It("parses the deduplicate-updates resource kinds", func() {
+		app.Action = func(ctx *cli.Context) error {
+			_, err := InitConfig(ctx, kexec.New(), nil)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(Kubernetes.DeduplicateUpdates.List()).To(gomega.Equal([]string{"EndpointSlice"}))
+			return nil
+		}
+		cliArgs := []string{
+			app.Name,
+			"-deduplicate-updates=EndpointSlice",
+		}
+		err := app.Run(cliArgs)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	It("returns an error when a deduplicate-updates entry is not supported", func() {
+		app.Action = func(ctx *cli.Context) error {
+			_, err := InitConfig(ctx, kexec.New(), nil)
+			gomega.Expect(err).To(gomega.MatchError("deduplicate-updates entry \"Pod\" is invalid: must be one of EndpointSlice"))
+			return nil
+		}
+		cliArgs := []string{
+			app.Name,
+			"-deduplicate-updates=EndpointSlice,Pod",
+		}
+		err := app.Run(cliArgs)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	It("parses the metrics servers allowed clients", func() {
+		app.Action = func(ctx *cli.Context) error {
+			_, err := InitConfig(ctx, kexec.New(), nil)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(Metrics.NodeServerClientCA).To(gomega.Equal("/tls/clientca"))
+			gomega.Expect(Metrics.NodeServerAllowedClients.List()).To(gomega.Equal([]string{"prometheus", "system:monitoring"}))
+			return nil
+		}
+		cliArgs := []string{
+			app.Name,
+			"-node-server-privkey=/tls/nodeprivkey",
+			"-node-server-cert=/tls/nodecert",
+			"-node-server-client-ca=/tls/clientca",
+			"-node-server-allowed-clients=prometheus, system:monitoring",
+		}
+		err := app.Run(cliArgs)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	It("returns an error when the metrics servers allowed clients are set without a client CA", func() {
+		app.Action = func(ctx *cli.Context) error {
+			_, err := InitConfig(ctx, kexec.New(), nil)
+			gomega.Expect(err).To(gomega.MatchError("node-server-allowed-clients requires node-server-client-ca"))
+			return nil
+		}
+		cliArgs := []string{
+			app.Name,
+			"-node-server-privkey=/tls/nodeprivkey",
+			"-node-server-cert=/tls/nodecert",
+			"-node-server-allowed-clients=prometheus",
+		}
+		err := app.Run(cliArgs)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
 	It("overrides config file and defaults with CLI legacy cluster-subnet option", func() {
 		err := ioutil.WriteFile(cfgFile.Name(), []byte(`[default]
 cluster-subnets=172.18.0.0/23
@@ -1035,6 +1159,270 @@ enable-pprof=true
 		err := app.Run(cliArgs)
 		gomega.Expect(err).NotTo(gomega.HaveOccurred())
 	})
+	It("returns an error when the gateway egress MSS is out of range", func() {
+		app.Action = func(ctx *cli.Context) error {
+			_, err := InitConfig(ctx, kexec.New(), nil)
+			gomega.Expect(err).To(gomega.MatchError("invalid gateway egress-mss option: MSS 100 is out of range, must be between 536 and 65495"))
+			return nil
+		}
+		cliArgs := []string{
+			app.Name,
+			"-gateway-mode=shared",
+			"-gateway-egress-mss=100",
+		}
+		err := app.Run(cliArgs)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	It("parses the pod MAC prefix", func() {
+		app.Action = func(ctx *cli.Context) error {
+			_, err := InitConfig(ctx, kexec.New(), nil)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(Default.DeterministicPodMAC).To(gomega.BeTrue())
+			gomega.Expect(Default.PodMACPrefix).To(gomega.Equal(net.HardwareAddr{0x0e, 0x01}))
+			return nil
+		}
+		cliArgs := []string{
+			app.Name,
+			"-deterministic-pod-mac",
+			"-pod-mac-prefix=0e:01",
+		}
+		err := app.Run(cliArgs)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	It("returns an error when the pod MAC prefix is not locally administered", func() {
+		app.Action = func(ctx *cli.Context) error {
+			_, err := InitConfig(ctx, kexec.New(), nil)
+			gomega.Expect(err).To(gomega.MatchError("pod-mac-prefix invalid: \"00:58\" is not a locally administered unicast prefix"))
+			return nil
+		}
+		cliArgs := []string{
+			app.Name,
+			"-pod-mac-prefix=00:58",
+		}
+		err := app.Run(cliArgs)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	It("parses the control plane protection rates", func() {
+		app.Action = func(ctx *cli.Context) error {
+			_, err := InitConfig(ctx, kexec.New(), nil)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(Default.COPPDefaultRate).To(gomega.Equal(50))
+			gomega.Expect(Default.COPPRates).To(gomega.Equal(map[string]int{"arp": 100, "dns": 200}))
+			return nil
+		}
+		cliArgs := []string{
+			app.Name,
+			"-copp-default-rate=50",
+			"-copp-rates=arp=100, dns=200",
+		}
+		err := app.Run(cliArgs)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	It("returns an error when a control plane protection rate is invalid", func() {
+		app.Action = func(ctx *cli.Context) error {
+			_, err := InitConfig(ctx, kexec.New(), nil)
+			gomega.Expect(err).To(gomega.MatchError("copp-rates invalid: rate \"0\" of protocol \"arp\" must be a number greater than zero"))
+			return nil
+		}
+		cliArgs := []string{
+			app.Name,
+			"-copp-rates=arp=0",
+		}
+		err := app.Run(cliArgs)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	It("parses the router options", func() {
+		app.Action = func(ctx *cli.Context) error {
+			_, err := InitConfig(ctx, kexec.New(), nil)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(Default.ClusterRouterOptions).To(gomega.Equal(map[string]string{
+				"always_learn_from_arp_request": "true",
+				"mac_binding_age_threshold":     "300",
+			}))
+			gomega.Expect(Default.GatewayRouterOptions).To(gomega.Equal(map[string]string{"dynamic_neigh_routers": "false"}))
+			return nil
+		}
+		cliArgs := []string{
+			app.Name,
+			"-cluster-router-options=always_learn_from_arp_request=true, mac_binding_age_threshold=300",
+			"-gateway-router-options=dynamic_neigh_routers=false",
+		}
+		err := app.Run(cliArgs)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	It("returns an error when a router option can't be configured", func() {
+		app.Action = func(ctx *cli.Context) error {
+			_, err := InitConfig(ctx, kexec.New(), nil)
+			gomega.Expect(err).To(gomega.MatchError("gateway-router-options invalid: unsupported option \"chassis\", " +
+				"must be one of always_learn_from_arp_request, ct-commit-all, dynamic_neigh_routers, mac_binding_age_threshold"))
+			return nil
+		}
+		cliArgs := []string{
+			app.Name,
+			"-gateway-router-options=chassis=foo",
+		}
+		err := app.Run(cliArgs)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	It("parses the port security exemption CIDRs", func() {
+		app.Action = func(ctx *cli.Context) error {
+			_, err := InitConfig(ctx, kexec.New(), nil)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(Default.PortSecurityExemptionCIDRs).To(gomega.Equal([]*net.IPNet{
+				ovntest.MustParseIPNet("10.128.250.0/24"),
+				ovntest.MustParseIPNet("fd00:250::/64"),
+			}))
+			return nil
+		}
+		cliArgs := []string{
+			app.Name,
+			"-port-security-exemption-cidrs=10.128.250.0/24, fd00:250::/64",
+		}
+		err := app.Run(cliArgs)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	It("returns an error when the transit switch subnet overlaps the cluster subnets in interconnect mode", func() {
+		app.Action = func(ctx *cli.Context) error {
+			_, err := InitConfig(ctx, kexec.New(), nil)
+			gomega.Expect(err).To(gomega.MatchError("illegal network configuration: transit switch subnet \"10.128.0.0/16\" " +
+				"overlaps cluster subnet \"10.128.0.0/14\""))
+			return nil
+		}
+		cliArgs := []string{
+			app.Name,
+			"-cluster-subnets=10.128.0.0/14",
+			"-enable-interconnect",
+			"-cluster-manager-v4-transit-switch-subnet=10.128.0.0/16",
+		}
+		err := app.Run(cliArgs)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	It("parses the retry limit overrides", func() {
+		app.Action = func(ctx *cli.Context) error {
+			_, err := InitConfig(ctx, kexec.New(), nil)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(Default.RetryMaxFailedAttemptsOverrides).To(gomega.Equal(map[string]int{"Node": 15, "Pod": 0}))
+			return nil
+		}
+		cliArgs := []string{
+			app.Name,
+			"-retry-max-failed-attempts-overrides=Node=15, Pod=0",
+		}
+		err := app.Run(cliArgs)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	It("returns an error when a retry limit override is invalid", func() {
+		app.Action = func(ctx *cli.Context) error {
+			_, err := InitConfig(ctx, kexec.New(), nil)
+			gomega.Expect(err).To(gomega.MatchError("retry-max-failed-attempts-overrides invalid: " +
+				"attempts \"-1\" of type \"Pod\" must be a number not lower than zero"))
+			return nil
+		}
+		cliArgs := []string{
+			app.Name,
+			"-retry-max-failed-attempts-overrides=Pod=-1",
+		}
+		err := app.Run(cliArgs)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	It("returns an error when the retry options are invalid", func() {
+		app.Action = func(ctx *cli.Context) error {
+			_, err := InitConfig(ctx, kexec.New(), nil)
+			gomega.Expect(err).To(gomega.MatchError("retry-max-failed-attempts 0 is invalid: must be greater than zero"))
+			return nil
+		}
+		cliArgs := []string{
+			app.Name,
+			"-retry-max-backoff=300",
+			"-retry-max-failed-attempts=0",
+		}
+		err := app.Run(cliArgs)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	It("returns an error when the shutdown timeout is negative", func() {
+		app.Action = func(ctx *cli.Context) error {
+			_, err := InitConfig(ctx, kexec.New(), nil)
+			gomega.Expect(err).To(gomega.MatchError("shutdown-timeout -1 is invalid: must not be negative"))
+			return nil
+		}
+		cliArgs := []string{
+			app.Name,
+			"-shutdown-timeout=-1",
+		}
+		err := app.Run(cliArgs)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	It("parses the embedded database options", func() {
+		app.Action = func(ctx *cli.Context) error {
+			_, err := InitConfig(ctx, kexec.New(), nil)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(EmbeddedDB).To(gomega.Equal(EmbeddedDBConfig{
+				Enabled:            true,
+				DBDir:              "/var/lib/ovn",
+				CompactionInterval: 600,
+				BackupDir:          "/var/lib/ovn/backups",
+				BackupInterval:     86400,
+				BackupCount:        5,
+			}))
+			return nil
+		}
+		cliArgs := []string{
+			app.Name,
+			"-enable-interconnect",
+			"-enable-embedded-dbs",
+			"-embedded-db-dir=/var/lib/ovn",
+			"-embedded-db-compaction-interval=600",
+			"-embedded-db-backup-dir=/var/lib/ovn/backups",
+			"-embedded-db-backup-count=5",
+		}
+		err := app.Run(cliArgs)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	It("returns an error when embedded databases are enabled without interconnect", func() {
+		app.Action = func(ctx *cli.Context) error {
+			_, err := InitConfig(ctx, kexec.New(), nil)
+			gomega.Expect(err).To(gomega.MatchError("embedded databases require interconnect to be enabled"))
+			return nil
+		}
+		cliArgs := []string{
+			app.Name,
+			"-enable-embedded-dbs",
+		}
+		err := app.Run(cliArgs)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	It("returns an error when embedded databases are reached through TCP", func() {
+		app.Action = func(ctx *cli.Context) error {
+			_, err := InitConfig(ctx, kexec.New(), nil)
+			gomega.Expect(err).To(gomega.MatchError("embedded databases must be reached through unix sockets, " +
+				"got NB address \"tcp:1.2.3.4:6641\" and SB address \"unix:/var/run/ovn/ovnsb_db.sock\""))
+			return nil
+		}
+		cliArgs := []string{
+			app.Name,
+			"-enable-interconnect",
+			"-enable-embedded-dbs",
+			"-nb-address=tcp://1.2.3.4:6641",
+		}
+		err := app.Run(cliArgs)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
 	It("returns an error when the v4 join subnet specified is invalid", func() {
 		app.Action = func(ctx *cli.Context) error {
 			_, err := InitConfig(ctx, kexec.New(), nil)
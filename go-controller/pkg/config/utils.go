@@ -164,6 +164,8 @@ const (
 	configSubnetCluster configSubnetType = "cluster subnet"
 	configSubnetService configSubnetType = "service subnet"
 	configSubnetHybrid  configSubnetType = "hybrid overlay subnet"
+	// the transit switch subnets are configured for both IP families whatever the IP families of the cluster
+	configSubnetTransitSwitch configSubnetType = "transit switch subnet"
 )
 
 type configSubnet struct {
@@ -189,7 +191,7 @@ func newConfigSubnets() *configSubnets {
 // append adds a single subnet to cs
 func (cs *configSubnets) append(subnetType configSubnetType, subnet *net.IPNet) {
 	cs.subnets = append(cs.subnets, configSubnet{subnetType: subnetType, subnet: subnet})
-	if subnetType != configSubnetJoin {
+	if subnetType != configSubnetJoin && subnetType != configSubnetTransitSwitch {
 		if utilnet.IsIPv6CIDR(subnet) {
 			cs.v6[subnetType] = true
 		} else {
@@ -0,0 +1,5 @@
+// Package v1alpha1 contains the subset of the sigs.k8s.io/network-policy-api
+// v1alpha1 API that is implemented by ovn-kubernetes
+// +k8s:deepcopy-gen=package,register
+// +groupName=policy.networking.k8s.io
+package v1alpha1
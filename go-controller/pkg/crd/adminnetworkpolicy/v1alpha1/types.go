@@ -0,0 +1,236 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The types in this package mirror the v1alpha1 AdminNetworkPolicy API of
+// sigs.k8s.io/network-policy-api, whose CRDs must be installed in the cluster.
+// Only the fields implemented by ovn-kubernetes are declared here.
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +resource:path=adminnetworkpolicy
+// +kubebuilder:resource:shortName=anp,scope=Cluster
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// AdminNetworkPolicy is a cluster level resource that allows the cluster
+// administrator to define network policies which take precedence over
+// namespace scoped NetworkPolicies.
+type AdminNetworkPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of AdminNetworkPolicy.
+	Spec AdminNetworkPolicySpec `json:"spec"`
+	// Observed status of AdminNetworkPolicy.
+	// +optional
+	Status AdminNetworkPolicyStatus `json:"status,omitempty"`
+}
+
+// AdminNetworkPolicyStatus defines the observed state of an
+// AdminNetworkPolicy or a BaselineAdminNetworkPolicy.
+type AdminNetworkPolicyStatus struct {
+	Conditions []metav1.Condition `json:"conditions"`
+}
+
+// AdminNetworkPolicySpec is a desired state description of AdminNetworkPolicy.
+type AdminNetworkPolicySpec struct {
+	// Priority is a value from 0 to 1000. Policies with lower priority values
+	// have higher precedence.
+	Priority int32 `json:"priority"`
+	// Subject defines the pods to which this AdminNetworkPolicy applies.
+	Subject AdminNetworkPolicySubject `json:"subject"`
+	// Ingress is the list of ingress rules to be applied to the subject pods,
+	// evaluated in order.
+	// +optional
+	Ingress []AdminNetworkPolicyIngressRule `json:"ingress,omitempty"`
+	// Egress is the list of egress rules to be applied to the subject pods,
+	// evaluated in order.
+	// +optional
+	Egress []AdminNetworkPolicyEgressRule `json:"egress,omitempty"`
+}
+
+// AdminNetworkPolicySubject selects the pods a policy applies to. Exactly
+// one of the fields must be set.
+type AdminNetworkPolicySubject struct {
+	// Namespaces selects all the pods in the namespaces matched by the selector.
+	// +optional
+	Namespaces *metav1.LabelSelector `json:"namespaces,omitempty"`
+	// Pods selects the matching pods in the matching namespaces.
+	// +optional
+	Pods *NamespacedPodSubject `json:"pods,omitempty"`
+}
+
+// NamespacedPodSubject selects pods by both namespace and pod labels.
+type NamespacedPodSubject struct {
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+	PodSelector       metav1.LabelSelector `json:"podSelector"`
+}
+
+// AdminNetworkPolicyRuleAction is the action taken by an AdminNetworkPolicy rule.
+type AdminNetworkPolicyRuleAction string
+
+const (
+	// AdminNetworkPolicyRuleActionAllow allows the selected traffic.
+	AdminNetworkPolicyRuleActionAllow AdminNetworkPolicyRuleAction = "Allow"
+	// AdminNetworkPolicyRuleActionDeny drops the selected traffic.
+	AdminNetworkPolicyRuleActionDeny AdminNetworkPolicyRuleAction = "Deny"
+	// AdminNetworkPolicyRuleActionPass skips all the remaining
+	// AdminNetworkPolicy rules and delegates the decision to NetworkPolicies.
+	AdminNetworkPolicyRuleActionPass AdminNetworkPolicyRuleAction = "Pass"
+)
+
+// AdminNetworkPolicyIngressRule describes traffic allowed, denied or passed
+// from a set of peers to the subject pods.
+type AdminNetworkPolicyIngressRule struct {
+	// +optional
+	Name   string                       `json:"name,omitempty"`
+	Action AdminNetworkPolicyRuleAction `json:"action"`
+	From   []AdminNetworkPolicyPeer     `json:"from"`
+	// +optional
+	Ports *[]AdminNetworkPolicyPort `json:"ports,omitempty"`
+}
+
+// AdminNetworkPolicyEgressRule describes traffic allowed, denied or passed
+// from the subject pods to a set of peers.
+type AdminNetworkPolicyEgressRule struct {
+	// +optional
+	Name   string                       `json:"name,omitempty"`
+	Action AdminNetworkPolicyRuleAction `json:"action"`
+	To     []AdminNetworkPolicyPeer     `json:"to"`
+	// +optional
+	Ports *[]AdminNetworkPolicyPort `json:"ports,omitempty"`
+}
+
+// AdminNetworkPolicyPeer selects the pods at the other end of a rule. Exactly
+// one of the fields must be set.
+type AdminNetworkPolicyPeer struct {
+	// +optional
+	Namespaces *NamespacedPeer `json:"namespaces,omitempty"`
+	// +optional
+	Pods *NamespacedPodPeer `json:"pods,omitempty"`
+}
+
+// NamespacedPeer selects all the pods in the matching namespaces.
+type NamespacedPeer struct {
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// NamespacedPodPeer selects the matching pods in the matching namespaces.
+type NamespacedPodPeer struct {
+	Namespaces  NamespacedPeer       `json:"namespaces"`
+	PodSelector metav1.LabelSelector `json:"podSelector"`
+}
+
+// AdminNetworkPolicyPort restricts a rule to a port or a range of ports.
+// Exactly one of the fields must be set.
+type AdminNetworkPolicyPort struct {
+	// +optional
+	PortNumber *Port `json:"portNumber,omitempty"`
+	// +optional
+	PortRange *PortRange `json:"portRange,omitempty"`
+}
+
+// Port is a single port and protocol.
+type Port struct {
+	Protocol corev1.Protocol `json:"protocol"`
+	Port     int32           `json:"port"`
+}
+
+// PortRange is an inclusive range of ports for a protocol.
+type PortRange struct {
+	// +optional
+	Protocol corev1.Protocol `json:"protocol,omitempty"`
+	Start    int32           `json:"start"`
+	End      int32           `json:"end"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +resource:path=adminnetworkpolicy
+// AdminNetworkPolicyList is the list of AdminNetworkPolicy.
+type AdminNetworkPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of AdminNetworkPolicy.
+	Items []AdminNetworkPolicy `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +resource:path=baselineadminnetworkpolicy
+// +kubebuilder:resource:shortName=banp,scope=Cluster
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// BaselineAdminNetworkPolicy is a cluster level resource that defines the
+// default network policies of the cluster, which apply when no
+// NetworkPolicy selects the traffic. Only a single instance, named
+// "default", is supported.
+type BaselineAdminNetworkPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of BaselineAdminNetworkPolicy.
+	Spec BaselineAdminNetworkPolicySpec `json:"spec"`
+	// Observed status of BaselineAdminNetworkPolicy.
+	// +optional
+	Status AdminNetworkPolicyStatus `json:"status,omitempty"`
+}
+
+// BaselineAdminNetworkPolicySpec is a desired state description of
+// BaselineAdminNetworkPolicy.
+type BaselineAdminNetworkPolicySpec struct {
+	// Subject defines the pods to which this BaselineAdminNetworkPolicy applies.
+	Subject AdminNetworkPolicySubject `json:"subject"`
+	// +optional
+	Ingress []BaselineAdminNetworkPolicyIngressRule `json:"ingress,omitempty"`
+	// +optional
+	Egress []BaselineAdminNetworkPolicyEgressRule `json:"egress,omitempty"`
+}
+
+// BaselineAdminNetworkPolicyRuleAction is the action taken by a
+// BaselineAdminNetworkPolicy rule.
+type BaselineAdminNetworkPolicyRuleAction string
+
+const (
+	// BaselineAdminNetworkPolicyRuleActionAllow allows the selected traffic.
+	BaselineAdminNetworkPolicyRuleActionAllow BaselineAdminNetworkPolicyRuleAction = "Allow"
+	// BaselineAdminNetworkPolicyRuleActionDeny drops the selected traffic.
+	BaselineAdminNetworkPolicyRuleActionDeny BaselineAdminNetworkPolicyRuleAction = "Deny"
+)
+
+// BaselineAdminNetworkPolicyIngressRule describes traffic allowed or denied
+// from a set of peers to the subject pods.
+type BaselineAdminNetworkPolicyIngressRule struct {
+	// +optional
+	Name   string                               `json:"name,omitempty"`
+	Action BaselineAdminNetworkPolicyRuleAction `json:"action"`
+	From   []AdminNetworkPolicyPeer             `json:"from"`
+	// +optional
+	Ports *[]AdminNetworkPolicyPort `json:"ports,omitempty"`
+}
+
+// BaselineAdminNetworkPolicyEgressRule describes traffic allowed or denied
+// from the subject pods to a set of peers.
+type BaselineAdminNetworkPolicyEgressRule struct {
+	// +optional
+	Name   string                               `json:"name,omitempty"`
+	Action BaselineAdminNetworkPolicyRuleAction `json:"action"`
+	To     []AdminNetworkPolicyPeer             `json:"to"`
+	// +optional
+	Ports *[]AdminNetworkPolicyPort `json:"ports,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +resource:path=baselineadminnetworkpolicy
+// BaselineAdminNetworkPolicyList is the list of BaselineAdminNetworkPolicy.
+type BaselineAdminNetworkPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of BaselineAdminNetworkPolicy.
+	Items []BaselineAdminNetworkPolicy `json:"items"`
+}
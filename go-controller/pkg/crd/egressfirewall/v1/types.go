@@ -34,7 +34,12 @@ type EgressFirewall struct {
 }
 
 type EgressFirewallStatus struct {
+	// Status is the aggregated status of the EgressFirewall in all the zones
 	Status string `json:"status,omitempty"`
+	// Messages holds the status of the EgressFirewall in each zone, as "<zone>: <status>", each zone updating
+	// its own message
+	// +optional
+	Messages []string `json:"messages,omitempty"`
 }
 
 // EgressFirewallSpec is a desired state description of EgressFirewall.
@@ -31,7 +31,7 @@ func (in *EgressFirewall) DeepCopyInto(out *EgressFirewall) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -171,6 +171,11 @@ func (in *EgressFirewallSpec) DeepCopy() *EgressFirewallSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EgressFirewallStatus) DeepCopyInto(out *EgressFirewallStatus) {
 	*out = *in
+	if in.Messages != nil {
+		in, out := &in.Messages, &out.Messages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -0,0 +1,5 @@
+// Package v1alpha1 contains the IPAMClaim API of
+// github.com/k8snetworkplumbingwg/ipamclaims used by ovn-kubernetes
+// +k8s:deepcopy-gen=package,register
+// +groupName=k8s.cni.cncf.io
+package v1alpha1
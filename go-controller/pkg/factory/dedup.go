@@ -0,0 +1,35 @@
+package factory
+
+import (
+	"reflect"
+
+	discovery "k8s.io/api/discovery/v1"
+)
+
+// updateDeduplicators hold, per object type, the function that returns true
+// when an update event carries no change that handlers care about. They are
+// enabled per resource kind with the deduplicate-updates option.
+var updateDeduplicators = map[reflect.Type]func(oldObj, newObj interface{}) bool{
+	EndpointSliceType: endpointSliceUpdateIsDuplicate,
+}
+
+// endpointSliceUpdateIsDuplicate returns true if the endpoint slices only
+// differ in their resource version or managed fields, which the API server
+// bumps on every write even when nothing else changed
+func endpointSliceUpdateIsDuplicate(oldObj, newObj interface{}) bool {
+	oldSlice, ok := oldObj.(*discovery.EndpointSlice)
+	if !ok {
+		return false
+	}
+	newSlice, ok := newObj.(*discovery.EndpointSlice)
+	if !ok {
+		return false
+	}
+	oldMeta, newMeta := oldSlice.ObjectMeta, newSlice.ObjectMeta
+	oldMeta.ResourceVersion, newMeta.ResourceVersion = "", ""
+	oldMeta.ManagedFields, newMeta.ManagedFields = nil, nil
+	return oldSlice.AddressType == newSlice.AddressType &&
+		reflect.DeepEqual(oldMeta, newMeta) &&
+		reflect.DeepEqual(oldSlice.Endpoints, newSlice.Endpoints) &&
+		reflect.DeepEqual(oldSlice.Ports, newSlice.Ports)
+}
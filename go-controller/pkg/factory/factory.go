@@ -8,6 +8,7 @@ import (
 
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
 	egressfirewallapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1"
+	egressfirewallclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1/apis/clientset/versioned"
 	egressfirewallscheme "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1/apis/clientset/versioned/scheme"
 	egressfirewallinformerfactory "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1/apis/informers/externalversions"
 	egressfirewalllister "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1/apis/listers/egressfirewall/v1"
@@ -23,6 +24,7 @@ import (
 	ocpcloudnetworklister "github.com/openshift/client-go/cloudnetwork/listers/cloudnetwork/v1"
 
 	egressqosapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1"
+	egressqosclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1/apis/clientset/versioned"
 	egressqosscheme "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1/apis/clientset/versioned/scheme"
 	egressqosinformerfactory "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1/apis/informers/externalversions"
 	egressqosinformer "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1/apis/informers/externalversions/egressqos/v1"
@@ -31,15 +33,28 @@ import (
 	nadscheme "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned/scheme"
 
 	mnpapi "github.com/k8snetworkplumbingwg/multi-networkpolicy/pkg/apis/k8s.cni.cncf.io/v1beta1"
+	mnpclientset "github.com/k8snetworkplumbingwg/multi-networkpolicy/pkg/client/clientset/versioned"
 	mnpscheme "github.com/k8snetworkplumbingwg/multi-networkpolicy/pkg/client/clientset/versioned/scheme"
 	mnpinformerfactory "github.com/k8snetworkplumbingwg/multi-networkpolicy/pkg/client/informers/externalversions"
 	mnplister "github.com/k8snetworkplumbingwg/multi-networkpolicy/pkg/client/listers/k8s.cni.cncf.io/v1beta1"
 
 	egressserviceapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressservice/v1"
+	egressserviceclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressservice/v1/apis/clientset/versioned"
 	egressservicescheme "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressservice/v1/apis/clientset/versioned/scheme"
 	egressserviceinformerfactory "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressservice/v1/apis/informers/externalversions"
 	egressserviceinformer "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressservice/v1/apis/informers/externalversions/egressservice/v1"
 
+	anpapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1alpha1"
+	anpscheme "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1alpha1/apis/clientset/versioned/scheme"
+	anpinformerfactory "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1alpha1/apis/informers/externalversions"
+	anpinformer "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1alpha1/apis/informers/externalversions/adminnetworkpolicy/v1alpha1"
+
+	ipamclaimsapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/ipamclaims/v1alpha1"
+	ipamclaimsscheme "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/ipamclaims/v1alpha1/apis/clientset/versioned/scheme"
+	ipamclaimsinformerfactory "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/ipamclaims/v1alpha1/apis/informers/externalversions"
+	ipamclaimsinformer "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/ipamclaims/v1alpha1/apis/informers/externalversions/ipamclaims/v1alpha1"
+	ipamclaimslister "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/ipamclaims/v1alpha1/apis/listers/ipamclaims/v1alpha1"
+
 	kapi "k8s.io/api/core/v1"
 	discovery "k8s.io/api/discovery/v1"
 	knet "k8s.io/api/networking/v1"
@@ -50,7 +65,6 @@ import (
 	utilwait "k8s.io/apimachinery/pkg/util/wait"
 	informerfactory "k8s.io/client-go/informers"
 	v1coreinformers "k8s.io/client-go/informers/core/v1"
-	discoveryinformers "k8s.io/client-go/informers/discovery/v1"
 	"k8s.io/client-go/kubernetes"
 	listers "k8s.io/client-go/listers/core/v1"
 	discoverylisters "k8s.io/client-go/listers/discovery/v1"
@@ -72,6 +86,8 @@ type WatchFactory struct {
 	egressQoSFactory     egressqosinformerfactory.SharedInformerFactory
 	mnpFactory           mnpinformerfactory.SharedInformerFactory
 	egressServiceFactory egressserviceinformerfactory.SharedInformerFactory
+	anpFactory           anpinformerfactory.SharedInformerFactory
+	ipamClaimsFactory    ipamclaimsinformerfactory.SharedInformerFactory
 	informers            map[reflect.Type]*informer
 
 	stopChan chan struct{}
@@ -138,6 +154,9 @@ var (
 	CloudPrivateIPConfigType              reflect.Type = reflect.TypeOf(&ocpcloudnetworkapi.CloudPrivateIPConfig{})
 	EgressQoSType                         reflect.Type = reflect.TypeOf(&egressqosapi.EgressQoS{})
 	EgressServiceType                     reflect.Type = reflect.TypeOf(&egressserviceapi.EgressService{})
+	AdminNetworkPolicyType                reflect.Type = reflect.TypeOf(&anpapi.AdminNetworkPolicy{})
+	BaselineAdminNetworkPolicyType        reflect.Type = reflect.TypeOf(&anpapi.BaselineAdminNetworkPolicy{})
+	IPAMClaimsType                        reflect.Type = reflect.TypeOf(&ipamclaimsapi.IPAMClaim{})
 	AddressSetNamespaceAndPodSelectorType reflect.Type = reflect.TypeOf(&addressSetNamespaceAndPodSelector{})
 	PeerNamespaceSelectorType             reflect.Type = reflect.TypeOf(&peerNamespaceSelector{})
 	AddressSetPodSelectorType             reflect.Type = reflect.TypeOf(&addressSetPodSelector{})
@@ -170,6 +189,8 @@ func NewMasterWatchFactory(ovnClientset *util.OVNMasterClientset) (*WatchFactory
 		egressQoSFactory:     egressqosinformerfactory.NewSharedInformerFactory(ovnClientset.EgressQoSClient, resyncInterval),
 		mnpFactory:           mnpinformerfactory.NewSharedInformerFactory(ovnClientset.MultiNetworkPolicyClient, resyncInterval),
 		egressServiceFactory: egressserviceinformerfactory.NewSharedInformerFactory(ovnClientset.EgressServiceClient, resyncInterval),
+		anpFactory:           anpinformerfactory.NewSharedInformerFactory(ovnClientset.ANPClient, resyncInterval),
+		ipamClaimsFactory:    ipamclaimsinformerfactory.NewSharedInformerFactory(ovnClientset.IPAMClaimsClient, resyncInterval),
 		informers:            make(map[reflect.Type]*informer),
 		stopChan:             make(chan struct{}),
 	}
@@ -186,6 +207,12 @@ func NewMasterWatchFactory(ovnClientset *util.OVNMasterClientset) (*WatchFactory
 	if err := egressserviceapi.AddToScheme(egressservicescheme.Scheme); err != nil {
 		return nil, err
 	}
+	if err := anpapi.AddToScheme(anpscheme.Scheme); err != nil {
+		return nil, err
+	}
+	if err := ipamclaimsapi.AddToScheme(ipamclaimsscheme.Scheme); err != nil {
+		return nil, err
+	}
 
 	if err := nadapi.AddToScheme(nadscheme.Scheme); err != nil {
 		return nil, err
@@ -198,21 +225,30 @@ func NewMasterWatchFactory(ovnClientset *util.OVNMasterClientset) (*WatchFactory
 	// For Services and Endpoints, pre-populate the shared Informer with one that
 	// has a label selector excluding headless services.
 	wf.iFactory.InformerFor(&kapi.Service{}, func(c kubernetes.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
-		return v1coreinformers.NewFilteredServiceInformer(
-			c,
-			kapi.NamespaceAll,
-			resyncPeriod,
-			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
-			noAlternateProxySelector())
+		return newNamespacedServiceInformer(c, resyncPeriod, noAlternateProxySelector())
 	})
 
 	wf.iFactory.InformerFor(&discovery.EndpointSlice{}, func(c kubernetes.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
-		return discoveryinformers.NewFilteredEndpointSliceInformer(
-			c,
-			kapi.NamespaceAll,
-			resyncPeriod,
-			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
-			withServiceNameAndNoHeadlessServiceSelector())
+		return newNamespacedEndpointSliceInformer(c, resyncPeriod, withServiceNameAndNoHeadlessServiceSelector())
+	})
+
+	// The remaining namespace-scoped resources are only watched in the
+	// configured namespaces (all of them by default)
+	wf.iFactory.InformerFor(&kapi.Pod{}, func(c kubernetes.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+		return newNamespacedPodInformer(c, resyncPeriod, nil)
+	})
+	wf.iFactory.InformerFor(&knet.NetworkPolicy{}, newNamespacedNetworkPolicyInformer)
+	wf.efFactory.InformerFor(&egressfirewallapi.EgressFirewall{}, func(c egressfirewallclientset.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+		return newNamespacedEgressFirewallInformer(c, resyncPeriod)
+	})
+	wf.egressQoSFactory.InformerFor(&egressqosapi.EgressQoS{}, func(c egressqosclientset.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+		return newNamespacedEgressQoSInformer(c, resyncPeriod)
+	})
+	wf.egressServiceFactory.InformerFor(&egressserviceapi.EgressService{}, func(c egressserviceclientset.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+		return newNamespacedEgressServiceInformer(c, resyncPeriod)
+	})
+	wf.mnpFactory.InformerFor(&mnpapi.MultiNetworkPolicy{}, func(c mnpclientset.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+		return newNamespacedMultiNetworkPolicyInformer(c, resyncPeriod)
 	})
 
 	var err error
@@ -274,6 +310,22 @@ func NewMasterWatchFactory(ovnClientset *util.OVNMasterClientset) (*WatchFactory
 			return nil, err
 		}
 	}
+	if config.OVNKubernetesFeature.EnableAdminNetworkPolicy {
+		wf.informers[AdminNetworkPolicyType], err = newInformer(AdminNetworkPolicyType, wf.anpFactory.Policy().V1alpha1().AdminNetworkPolicies().Informer())
+		if err != nil {
+			return nil, err
+		}
+		wf.informers[BaselineAdminNetworkPolicyType], err = newInformer(BaselineAdminNetworkPolicyType, wf.anpFactory.Policy().V1alpha1().BaselineAdminNetworkPolicies().Informer())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if util.IsPersistentIPsEnabled() {
+		wf.informers[IPAMClaimsType], err = newInformer(IPAMClaimsType, wf.ipamClaimsFactory.K8s().V1alpha1().IPAMClaims().Informer())
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	if util.IsMultiNetworkPoliciesSupportEnabled() {
 		wf.informers[MultiNetworkPolicyType], err = newInformer(MultiNetworkPolicyType, wf.mnpFactory.K8sCniCncfIo().V1beta1().MultiNetworkPolicies().Informer())
@@ -344,6 +396,24 @@ func (wf *WatchFactory) Start() error {
 		}
 	}
 
+	if config.OVNKubernetesFeature.EnableAdminNetworkPolicy && wf.anpFactory != nil {
+		wf.anpFactory.Start(wf.stopChan)
+		for oType, synced := range wf.anpFactory.WaitForCacheSync(wf.stopChan) {
+			if !synced {
+				return fmt.Errorf("error in syncing cache for %v informer", oType)
+			}
+		}
+	}
+
+	if util.IsPersistentIPsEnabled() && wf.ipamClaimsFactory != nil {
+		wf.ipamClaimsFactory.Start(wf.stopChan)
+		for oType, synced := range wf.ipamClaimsFactory.WaitForCacheSync(wf.stopChan) {
+			if !synced {
+				return fmt.Errorf("error in syncing cache for %v informer", oType)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -364,24 +434,14 @@ func NewNodeWatchFactory(ovnClientset *util.OVNNodeClientset, nodeName string) (
 	// For Services and Endpoints, pre-populate the shared Informer with one that
 	// has a label selector excluding headless services.
 	wf.iFactory.InformerFor(&kapi.Service{}, func(c kubernetes.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
-		return v1coreinformers.NewFilteredServiceInformer(
-			c,
-			kapi.NamespaceAll,
-			resyncPeriod,
-			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
-			noAlternateProxySelector())
+		return newNamespacedServiceInformer(c, resyncPeriod, noAlternateProxySelector())
 	})
 
 	// For Pods, only select pods scheduled to this node
 	wf.iFactory.InformerFor(&kapi.Pod{}, func(c kubernetes.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
-		return v1coreinformers.NewFilteredPodInformer(
-			c,
-			kapi.NamespaceAll,
-			resyncPeriod,
-			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
-			func(opts *metav1.ListOptions) {
-				opts.FieldSelector = fields.OneTermEqualSelector("spec.nodeName", nodeName).String()
-			})
+		return newNamespacedPodInformer(c, resyncPeriod, func(opts *metav1.ListOptions) {
+			opts.FieldSelector = fields.OneTermEqualSelector("spec.nodeName", nodeName).String()
+		})
 	})
 
 	// For namespaces
@@ -393,12 +453,11 @@ func NewNodeWatchFactory(ovnClientset *util.OVNNodeClientset, nodeName string) (
 	})
 
 	wf.iFactory.InformerFor(&discovery.EndpointSlice{}, func(c kubernetes.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
-		return discoveryinformers.NewFilteredEndpointSliceInformer(
-			c,
-			kapi.NamespaceAll,
-			resyncPeriod,
-			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
-			withServiceNameAndNoHeadlessServiceSelector())
+		return newNamespacedEndpointSliceInformer(c, resyncPeriod, withServiceNameAndNoHeadlessServiceSelector())
+	})
+
+	wf.egressServiceFactory.InformerFor(&egressserviceapi.EgressService{}, func(c egressserviceclientset.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+		return newNamespacedEgressServiceInformer(c, resyncPeriod)
 	})
 
 	var err error
@@ -449,11 +508,34 @@ func NewClusterManagerWatchFactory(ovnClientset *util.OVNClusterManagerClientset
 		stopChan:  make(chan struct{}),
 	}
 
+	// The pods are only watched in the configured namespaces, like in the
+	// other watch factories
+	wf.iFactory.InformerFor(&kapi.Pod{}, func(c kubernetes.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+		return newNamespacedPodInformer(c, resyncPeriod, nil)
+	})
+
 	var err error
 	wf.informers[NodeType], err = newInformer(NodeType, wf.iFactory.Core().V1().Nodes().Informer())
 	if err != nil {
 		return nil, err
 	}
+
+	// The pod addresses of the secondary layer2 and localnet networks are
+	// allocated by the cluster manager when interconnect is enabled, along
+	// with the IPs persisted in IPAMClaims.
+	if config.OVNKubernetesFeature.EnableMultiNetwork && config.OVNKubernetesFeature.EnableInterconnect {
+		wf.informers[PodType], err = newInformer(PodType, wf.iFactory.Core().V1().Pods().Informer())
+		if err != nil {
+			return nil, err
+		}
+		if util.IsPersistentIPsEnabled() {
+			wf.ipamClaimsFactory = ipamclaimsinformerfactory.NewSharedInformerFactory(ovnClientset.IPAMClaimsClient, resyncInterval)
+			wf.informers[IPAMClaimsType], err = newInformer(IPAMClaimsType, wf.ipamClaimsFactory.K8s().V1alpha1().IPAMClaims().Informer())
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
 	return wf, nil
 }
 
@@ -466,6 +548,40 @@ func (wf *WatchFactory) Shutdown() {
 	}
 }
 
+// WaitForCacheSync waits until the caches of the informers for the given
+// object types have synced, or for all the informers of the factory if no
+// type is given. It returns an error if a type has no informer or if the
+// factory is shut down before the caches have synced.
+func (wf *WatchFactory) WaitForCacheSync(types ...reflect.Type) error {
+	if len(types) == 0 {
+		for oType := range wf.informers {
+			types = append(types, oType)
+		}
+	}
+	hasSynced := make([]cache.InformerSynced, 0, len(types))
+	for _, oType := range types {
+		inf, ok := wf.informers[oType]
+		if !ok {
+			return fmt.Errorf("no informer for object type %v", oType)
+		}
+		hasSynced = append(hasSynced, inf.inf.HasSynced)
+	}
+	if !cache.WaitForCacheSync(wf.stopChan, hasSynced...) {
+		return fmt.Errorf("error in syncing cache for %v informers", types)
+	}
+	return nil
+}
+
+// GetSyncState returns whether the cache of the informer for each object type
+// of the factory has synced
+func (wf *WatchFactory) GetSyncState() map[reflect.Type]bool {
+	state := make(map[reflect.Type]bool, len(wf.informers))
+	for oType, inf := range wf.informers {
+		state[oType] = inf.inf.HasSynced()
+	}
+	return state
+}
+
 func getObjectMeta(objType reflect.Type, obj interface{}) (*metav1.ObjectMeta, error) {
 	switch objType {
 	case PodType:
@@ -512,6 +628,10 @@ func getObjectMeta(objType reflect.Type, obj interface{}) (*metav1.ObjectMeta, e
 		if multinetworkpolicy, ok := obj.(*mnpapi.MultiNetworkPolicy); ok {
 			return &multinetworkpolicy.ObjectMeta, nil
 		}
+	case IPAMClaimsType:
+		if ipamClaim, ok := obj.(*ipamclaimsapi.IPAMClaim); ok {
+			return &ipamClaim.ObjectMeta, nil
+		}
 	}
 	return nil, fmt.Errorf("cannot get ObjectMeta from type %v", objType)
 }
@@ -571,6 +691,12 @@ func (wf *WatchFactory) GetResourceHandlerFunc(objType reflect.Type) (AddHandler
 			return wf.AddMultiNetworkPolicyHandler(funcs, processExisting)
 		}, nil
 
+	case IPAMClaimsType:
+		return func(namespace string, sel labels.Selector,
+			funcs cache.ResourceEventHandler, processExisting func([]interface{}) error) (*Handler, error) {
+			return wf.AddIPAMClaimsHandler(funcs, processExisting)
+		}, nil
+
 	case NodeType, EgressNodeType, EgressFwNodeType:
 		return func(namespace string, sel labels.Selector,
 			funcs cache.ResourceEventHandler, processExisting func([]interface{}) error) (*Handler, error) {
@@ -624,31 +750,83 @@ func (wf *WatchFactory) GetResourceHandlerFunc(objType reflect.Type) (AddHandler
 }
 
 func (wf *WatchFactory) addHandler(objType reflect.Type, namespace string, sel labels.Selector, funcs cache.ResourceEventHandler, processExisting func([]interface{}) error, priority int) (*Handler, error) {
+	return wf.addHandlerWithFilter(objType, &EventFilter{Namespace: namespace, Selector: sel}, funcs, processExisting, priority)
+}
+
+// EventFilter selects the objects whose events are delivered to a handler. An
+// update that moves an object into the selection is delivered to the handler
+// as an add, and an update that moves it out of the selection as a delete, so
+// handlers need no transition logic of their own.
+type EventFilter struct {
+	// Namespace, if not empty, only selects objects in that namespace
+	Namespace string
+	// Selector, if not nil, only selects objects with matching labels
+	Selector labels.Selector
+	// Match, if not nil, only selects objects it returns true for
+	Match func(obj interface{}) bool
+	// UpdateFilter, if not nil, is called for updates of objects that stay
+	// selected and drops the update when it returns false, eg when only
+	// changes to some fields of the object are of interest
+	UpdateFilter func(oldObj, newObj interface{}) bool
+	// Middlewares wrap the handler, the first one being the outermost. They
+	// only see the events delivered to the handler. Panics in the handler
+	// are always recovered from, whether middlewares are set or not.
+	Middlewares []HandlerMiddleware
+}
+
+// updateFilteringHandler drops the updates that its filter rejects
+type updateFilteringHandler struct {
+	cache.ResourceEventHandler
+	filter func(oldObj, newObj interface{}) bool
+}
+
+func (h *updateFilteringHandler) OnUpdate(oldObj, newObj interface{}) {
+	if h.filter(oldObj, newObj) {
+		h.ResourceEventHandler.OnUpdate(oldObj, newObj)
+	}
+}
+
+func (wf *WatchFactory) addHandlerWithFilter(objType reflect.Type, filter *EventFilter, funcs cache.ResourceEventHandler, processExisting func([]interface{}) error, priority int) (*Handler, error) {
 	inf, ok := wf.informers[objType]
 	if !ok {
 		klog.Fatalf("Tried to add handler of unknown object type %v", objType)
 	}
+	if filter == nil {
+		filter = &EventFilter{}
+	}
 
+	namespace, sel := filter.Namespace, filter.Selector
 	filterFunc := func(obj interface{}) bool {
-		if namespace == "" && sel == nil {
+		if namespace == "" && sel == nil && filter.Match == nil {
 			// Unfiltered handler
 			return true
 		}
-		meta, err := getObjectMeta(objType, obj)
-		if err != nil {
-			klog.Errorf("Watch handler filter error: %v", err)
-			return false
-		}
-		if namespace != "" && meta.Namespace != namespace {
-			return false
-		}
-		if sel != nil && !sel.Matches(labels.Set(meta.Labels)) {
-			return false
+		if namespace != "" || sel != nil {
+			meta, err := getObjectMeta(objType, obj)
+			if err != nil {
+				klog.Errorf("Watch handler filter error: %v", err)
+				return false
+			}
+			if namespace != "" && meta.Namespace != namespace {
+				return false
+			}
+			if sel != nil && !sel.Matches(labels.Set(meta.Labels)) {
+				return false
+			}
 		}
-		return true
+		return filter.Match == nil || filter.Match(obj)
+	}
+	for i := len(filter.Middlewares) - 1; i >= 0; i-- {
+		funcs = filter.Middlewares[i](objType, funcs)
 	}
+	if filter.UpdateFilter != nil {
+		funcs = &updateFilteringHandler{ResourceEventHandler: funcs, filter: filter.UpdateFilter}
+	}
+	funcs = recoverPanics(objType, funcs)
 
+	start := informerLockWait.Start()
 	inf.Lock()
+	informerLockWait.Observe(start)
 	defer inf.Unlock()
 
 	items := make([]interface{}, 0)
@@ -688,6 +866,11 @@ func (wf *WatchFactory) AddPodHandler(handlerFuncs cache.ResourceEventHandler, p
 	return wf.addHandler(PodType, "", nil, handlerFuncs, processExisting, defaultHandlerPriority)
 }
 
+// AddPodHandlerWithFilter adds a handler function that will be executed when Pod objects selected by the filter change
+func (wf *WatchFactory) AddPodHandlerWithFilter(filter *EventFilter, handlerFuncs cache.ResourceEventHandler, processExisting func([]interface{}) error) (*Handler, error) {
+	return wf.addHandlerWithFilter(PodType, filter, handlerFuncs, processExisting, defaultHandlerPriority)
+}
+
 // AddFilteredPodHandler adds a handler function that will be executed when Pod objects that match the given filters change
 func (wf *WatchFactory) AddFilteredPodHandler(namespace string, sel labels.Selector, handlerFuncs cache.ResourceEventHandler, processExisting func([]interface{}) error, priority int) (*Handler, error) {
 	return wf.addHandler(PodType, namespace, sel, handlerFuncs, processExisting, priority)
@@ -762,6 +945,16 @@ func (wf *WatchFactory) RemoveNetworkAttachmentDefinitionHandler(handler *Handle
 	wf.removeHandler(NetworkAttachmentDefinitionType, handler)
 }
 
+// AddIPAMClaimsHandler adds a handler function that will be executed on IPAMClaim object changes
+func (wf *WatchFactory) AddIPAMClaimsHandler(handlerFuncs cache.ResourceEventHandler, processExisting func([]interface{}) error) (*Handler, error) {
+	return wf.addHandler(IPAMClaimsType, "", nil, handlerFuncs, processExisting, defaultHandlerPriority)
+}
+
+// RemoveIPAMClaimsHandler removes an IPAMClaim object event handler function
+func (wf *WatchFactory) RemoveIPAMClaimsHandler(handler *Handler) {
+	wf.removeHandler(IPAMClaimsType, handler)
+}
+
 // AddEgressIPHandler adds a handler function that will be executed on EgressIP object changes
 func (wf *WatchFactory) AddEgressIPHandler(handlerFuncs cache.ResourceEventHandler, processExisting func([]interface{}) error) (*Handler, error) {
 	return wf.addHandler(EgressIPType, "", nil, handlerFuncs, processExisting, defaultHandlerPriority)
@@ -882,6 +1075,12 @@ func (wf *WatchFactory) GetService(namespace, name string) (*kapi.Service, error
 	return serviceLister.Services(namespace).Get(name)
 }
 
+// GetServices returns all the services in the cluster
+func (wf *WatchFactory) GetServices() ([]*kapi.Service, error) {
+	serviceLister := wf.informers[ServiceType].lister.(listers.ServiceLister)
+	return serviceLister.List(labels.Everything())
+}
+
 func (wf *WatchFactory) GetCloudPrivateIPConfig(name string) (*ocpcloudnetworkapi.CloudPrivateIPConfig, error) {
 	cloudPrivateIPConfigLister := wf.informers[CloudPrivateIPConfigType].lister.(ocpcloudnetworklister.CloudPrivateIPConfigLister)
 	return cloudPrivateIPConfigLister.Get(name)
@@ -940,6 +1139,12 @@ func (wf *WatchFactory) GetNetworkPolicy(namespace, name string) (*knet.NetworkP
 	return networkPolicyLister.NetworkPolicies(namespace).Get(name)
 }
 
+// GetNetworkPolicies returns all the network policies in the cluster
+func (wf *WatchFactory) GetNetworkPolicies() ([]*knet.NetworkPolicy, error) {
+	networkPolicyLister := wf.informers[PolicyType].lister.(netlisters.NetworkPolicyLister)
+	return networkPolicyLister.List(labels.Everything())
+}
+
 // GetMultinetworkPolicy gets a specific multinetwork policy by the namespace/name
 func (wf *WatchFactory) GetMultiNetworkPolicy(namespace, name string) (*mnpapi.MultiNetworkPolicy, error) {
 	multinetworkPolicyLister := wf.informers[MultiNetworkPolicyType].lister.(mnplister.MultiNetworkPolicyLister)
@@ -951,6 +1156,12 @@ func (wf *WatchFactory) GetEgressFirewall(namespace, name string) (*egressfirewa
 	return egressFirewallLister.EgressFirewalls(namespace).Get(name)
 }
 
+// GetIPAMClaim returns the IPAMClaim indexed by the given namespace and name
+func (wf *WatchFactory) GetIPAMClaim(namespace, name string) (*ipamclaimsapi.IPAMClaim, error) {
+	ipamClaimLister := wf.informers[IPAMClaimsType].lister.(ipamclaimslister.IPAMClaimLister)
+	return ipamClaimLister.IPAMClaims(namespace).Get(name)
+}
+
 func (wf *WatchFactory) NodeInformer() cache.SharedIndexInformer {
 	return wf.informers[NodeType].inf
 }
@@ -959,6 +1170,10 @@ func (wf *WatchFactory) NodeCoreInformer() v1coreinformers.NodeInformer {
 	return wf.iFactory.Core().V1().Nodes()
 }
 
+func (wf *WatchFactory) NamespaceCoreInformer() v1coreinformers.NamespaceInformer {
+	return wf.iFactory.Core().V1().Namespaces()
+}
+
 // LocalPodInformer returns a shared Informer that may or may not only
 // return pods running on the local node.
 func (wf *WatchFactory) LocalPodInformer() cache.SharedIndexInformer {
@@ -993,6 +1208,18 @@ func (wf *WatchFactory) EgressServiceInformer() egressserviceinformer.EgressServ
 	return wf.egressServiceFactory.K8s().V1().EgressServices()
 }
 
+func (wf *WatchFactory) AdminNetworkPolicyInformer() anpinformer.AdminNetworkPolicyInformer {
+	return wf.anpFactory.Policy().V1alpha1().AdminNetworkPolicies()
+}
+
+func (wf *WatchFactory) BaselineAdminNetworkPolicyInformer() anpinformer.BaselineAdminNetworkPolicyInformer {
+	return wf.anpFactory.Policy().V1alpha1().BaselineAdminNetworkPolicies()
+}
+
+func (wf *WatchFactory) IPAMClaimsInformer() ipamclaimsinformer.IPAMClaimInformer {
+	return wf.ipamClaimsFactory.K8s().V1alpha1().IPAMClaims()
+}
+
 // withServiceNameAndNoHeadlessServiceSelector returns a LabelSelector (added to the
 // watcher for EndpointSlices) that will only choose EndpointSlices with a non-empty
 // "kubernetes.io/service-name" label and without "service.kubernetes.io/headless"
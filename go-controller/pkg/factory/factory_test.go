@@ -11,6 +11,7 @@ import (
 	discovery "k8s.io/api/discovery/v1"
 	knet "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -708,6 +709,63 @@ var _ = Describe("Watch Factory Operations", func() {
 		})
 	})
 
+	Context("when waiting for informer caches", func() {
+		It("reports the sync state of each informer", func() {
+			wf, err = NewMasterWatchFactory(ovnClientset)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(wf.GetSyncState()).To(HaveKeyWithValue(PodType, false))
+
+			err = wf.Start()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(wf.WaitForCacheSync(PodType, EgressIPType)).To(Succeed())
+			Expect(wf.WaitForCacheSync()).To(Succeed())
+			for oType, synced := range wf.GetSyncState() {
+				Expect(synced).To(BeTrue(), "informer for %v should be synced", oType)
+			}
+		})
+		It("fails for a type without informer", func() {
+			config.OVNKubernetesFeature.EnableEgressQoS = false
+			wf, err = NewMasterWatchFactory(ovnClientset)
+			Expect(err).NotTo(HaveOccurred())
+			err = wf.Start()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(wf.WaitForCacheSync(PodType, EgressQoSType)).NotTo(Succeed())
+		})
+	})
+
+	Context("when watching pods on a node", func() {
+		BeforeEach(func() {
+			localPod := newPod("pod1", "default")
+			localPod.Spec.NodeName = nodeName
+			remotePod := newPod("pod2", "default")
+			remotePod.Spec.NodeName = "node2"
+			pods = append(pods, localPod, remotePod)
+			fakeClient.PrependReactor("list", "pods", func(action core.Action) (bool, runtime.Object, error) {
+				restrictions := action.(core.ListAction).GetListRestrictions()
+				obj := &v1.PodList{}
+				for _, p := range pods {
+					if restrictions.Fields.Matches(fields.Set{"spec.nodeName": p.Spec.NodeName}) {
+						obj.Items = append(obj.Items, *p)
+					}
+				}
+				return true, obj, nil
+			})
+		})
+
+		It("only caches the pods of the node", func() {
+			wf, err = NewNodeWatchFactory(ovnClientset.GetNodeClientset(), nodeName)
+			Expect(err).NotTo(HaveOccurred())
+			err = wf.Start()
+			Expect(err).NotTo(HaveOccurred())
+
+			names := []string{}
+			for _, obj := range wf.LocalPodInformer().GetStore().List() {
+				names = append(names, obj.(*v1.Pod).Name)
+			}
+			Expect(names).To(ConsistOf("pod1"))
+		})
+	})
+
 	addFilteredHandler := func(wf *WatchFactory, objType reflect.Type, realObjType reflect.Type, namespace string, sel labels.Selector, funcs cache.ResourceEventHandlerFuncs) (*Handler, *handlerCalls) {
 		calls := handlerCalls{}
 		h, err := wf.addHandler(objType, namespace, sel, cache.ResourceEventHandlerFuncs{
@@ -1863,6 +1921,120 @@ var _ = Describe("Watch Factory Operations", func() {
 		Eventually(c.getDeleted, 2).Should(Equal(1))
 	})
 
+	It("synthesizes add and delete events for custom filter transitions and drops filtered updates", func() {
+		wf, err = NewMasterWatchFactory(ovnClientset)
+		Expect(err).NotTo(HaveOccurred())
+		err = wf.Start()
+		Expect(err).NotTo(HaveOccurred())
+
+		const egressLabel = "k8s.ovn.org/egress-assignable"
+		calls := handlerCalls{}
+		h, err := wf.addHandlerWithFilter(NodeType, &EventFilter{
+			Match: func(obj interface{}) bool {
+				_, ok := obj.(*v1.Node).Labels[egressLabel]
+				return ok
+			},
+			UpdateFilter: func(oldObj, newObj interface{}) bool {
+				return !reflect.DeepEqual(oldObj.(*v1.Node).Labels, newObj.(*v1.Node).Labels)
+			},
+		}, cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				atomic.AddInt32(&calls.added, 1)
+			},
+			UpdateFunc: func(old, new interface{}) {
+				atomic.AddInt32(&calls.updated, 1)
+			},
+			DeleteFunc: func(obj interface{}) {
+				atomic.AddInt32(&calls.deleted, 1)
+			},
+		}, nil, wf.GetHandlerPriority(NodeType))
+		Expect(err).NotTo(HaveOccurred())
+
+		node := newNode("mynode")
+		nodes = append(nodes, node)
+		nodeWatch.Add(node)
+		Consistently(calls.getAdded, 2).Should(Equal(0))
+
+		// gaining the label is an add
+		node = node.DeepCopy()
+		node.Labels[egressLabel] = ""
+		nodeWatch.Modify(node)
+		Eventually(calls.getAdded, 2).Should(Equal(1))
+
+		// updates that do not change the labels are dropped
+		node = node.DeepCopy()
+		node.Status.Phase = v1.NodeTerminated
+		nodeWatch.Modify(node)
+		node = node.DeepCopy()
+		node.Labels["foo"] = "bar"
+		nodeWatch.Modify(node)
+		Eventually(calls.getUpdated, 2).Should(Equal(1))
+
+		// losing the label is a delete
+		node = node.DeepCopy()
+		delete(node.Labels, egressLabel)
+		nodeWatch.Modify(node)
+		Eventually(calls.getDeleted, 2).Should(Equal(1))
+		Consistently(calls.getUpdated, 2).Should(Equal(1))
+
+		wf.RemoveNodeHandler(h)
+	})
+
+	It("applies the handler middlewares and recovers from handler panics", func() {
+		wf, err = NewMasterWatchFactory(ovnClientset)
+		Expect(err).NotTo(HaveOccurred())
+		err = wf.Start()
+		Expect(err).NotTo(HaveOccurred())
+
+		var mutex sync.Mutex
+		events := []string{}
+		getEvents := func() []string {
+			mutex.Lock()
+			defer mutex.Unlock()
+			return append([]string{}, events...)
+		}
+		record := func(name string) HandlerMiddleware {
+			return func(objType reflect.Type, handler cache.ResourceEventHandler) cache.ResourceEventHandler {
+				return aroundEvents(handler, func(event string, obj interface{}, next func()) {
+					mutex.Lock()
+					events = append(events, fmt.Sprintf("%s %s %s", name, event, obj.(*v1.Node).Name))
+					mutex.Unlock()
+					next()
+				})
+			}
+		}
+
+		calls := handlerCalls{}
+		h, err := wf.addHandlerWithFilter(NodeType, &EventFilter{
+			Middlewares: []HandlerMiddleware{record("outer"), record("inner"), TimeEvents("test")},
+		}, cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				atomic.AddInt32(&calls.added, 1)
+				if obj.(*v1.Node).Name == "panicking" {
+					panic("handler failure")
+				}
+			},
+		}, nil, wf.GetHandlerPriority(NodeType))
+		Expect(err).NotTo(HaveOccurred())
+
+		node := newNode("panicking")
+		nodes = append(nodes, node)
+		nodeWatch.Add(node)
+		Eventually(calls.getAdded, 2).Should(Equal(1))
+
+		// the panic only dropped the event, the handler keeps receiving events
+		node = newNode("mynode")
+		nodes = append(nodes, node)
+		nodeWatch.Add(node)
+		Eventually(calls.getAdded, 2).Should(Equal(2))
+		Expect(getEvents()).To(Equal([]string{
+			"outer add panicking", "inner add panicking",
+			"outer add mynode", "inner add mynode",
+		}))
+
+		wf.RemoveNodeHandler(h)
+	})
+
 	It("correctly handles object updates that cause filter changes", func() {
 		wf, err = NewMasterWatchFactory(ovnClientset)
 		Expect(err).NotTo(HaveOccurred())
@@ -3,23 +3,28 @@ package factory
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/cryptorand"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/metrics"
 
 	multinetworkpolicylister "github.com/k8snetworkplumbingwg/multi-networkpolicy/pkg/client/listers/k8s.cni.cncf.io/v1beta1"
 	networkattachmentdefinitionlister "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/listers/k8s.cni.cncf.io/v1"
 
+	anplister "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1alpha1/apis/listers/adminnetworkpolicy/v1alpha1"
 	egressfirewalllister "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1/apis/listers/egressfirewall/v1"
 	egressqoslister "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1/apis/listers/egressqos/v1"
 	egressservicelister "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressservice/v1/apis/listers/egressservice/v1"
+	ipamclaimslister "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/ipamclaims/v1alpha1/apis/listers/ipamclaims/v1alpha1"
 
 	cloudprivateipconfiglister "github.com/openshift/client-go/cloudnetwork/listers/cloudnetwork/v1"
 	egressiplister "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1/apis/listers/egressip/v1"
 
+	"github.com/prometheus/client_golang/prometheus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ktypes "k8s.io/apimachinery/pkg/types"
 	listers "k8s.io/client-go/listers/core/v1"
@@ -78,16 +83,33 @@ type listerInterface interface{}
 
 type initialAddFn func(*Handler, []interface{})
 
+// Lock wait observers for the hot path locks, used by the watchdog to detect
+// contention and deadlocks
+var (
+	informerLockWait = metrics.NewLockWaitObserver("informer")
+	queueMapLockWait = metrics.NewLockWaitObserver("queue_map")
+)
+
 type queueMap struct {
 	sync.Mutex
+	// name identifies the queue map's event loop to the watchdog
+	name    string
 	entries map[ktypes.NamespacedName]*queueMapEntry
 	queues  []chan *event
 	wg      *sync.WaitGroup
+	// coalesceUpdates merges an update event into an update event for the
+	// same object that is still waiting in its queue
+	coalesceUpdates bool
+	// onEnqueue, if set, is called for every event added to the queues
+	onEnqueue func()
 }
 
 type queueMapEntry struct {
 	queue    uint32
 	refcount int32
+	// pendingUpdate is the last update event queued for the object that has
+	// not started processing yet; only tracked when coalescing updates
+	pendingUpdate *event
 }
 
 type informer struct {
@@ -108,32 +130,56 @@ type informer struct {
 
 	// queueMap handles distributing events across a queued handler's queues
 	queueMap *queueMap
+
+	// per priority event processing metrics, indexed by handler priority
+	updateLatency []prometheus.Observer
+	backlog       []prometheus.Gauge
+
+	// duplicateUpdate, if not nil, returns true for update events that are
+	// dropped because they carry no change of interest
+	duplicateUpdate   func(oldObj, newObj interface{}) bool
+	suppressedUpdates prometheus.Counter
 }
 
-func (i *informer) forEachQueuedHandler(f func(h *Handler)) {
+// forEachQueuedHandler calls f for every handler from the highest to the lowest
+// priority. If priorityDone is not nil, it is called once all the handlers of
+// a priority have been called.
+func (i *informer) forEachQueuedHandler(f func(h *Handler), priorityDone func(priority int)) {
+	start := informerLockWait.Start()
 	i.RLock()
+	informerLockWait.Observe(start)
 	defer i.RUnlock()
 
 	for priority := 0; priority <= minHandlerPriority; priority++ { // loop over priority higest to lowest
 		for _, handler := range i.handlers[priority] {
 			f(handler)
 		}
+		if priorityDone != nil {
+			priorityDone(priority)
+		}
 	}
 }
 
-func (i *informer) forEachQueuedHandlerReversed(f func(h *Handler)) {
+func (i *informer) forEachQueuedHandlerReversed(f func(h *Handler), priorityDone func(priority int)) {
+	start := informerLockWait.Start()
 	i.RLock()
+	informerLockWait.Observe(start)
 	defer i.RUnlock()
 
 	for priority := minHandlerPriority; priority >= 0; priority-- { // loop over priority lowest to highest
 		for _, handler := range i.handlers[priority] {
 			f(handler)
 		}
+		if priorityDone != nil {
+			priorityDone(priority)
+		}
 	}
 }
 
-func (i *informer) forEachHandler(obj interface{}, f func(h *Handler)) {
+func (i *informer) forEachHandler(obj interface{}, f func(h *Handler), priorityDone func(priority int)) {
+	start := informerLockWait.Start()
 	i.RLock()
+	informerLockWait.Observe(start)
 	defer i.RUnlock()
 
 	objType := reflect.TypeOf(obj)
@@ -146,11 +192,16 @@ func (i *informer) forEachHandler(obj interface{}, f func(h *Handler)) {
 		for _, handler := range i.handlers[priority] {
 			f(handler)
 		}
+		if priorityDone != nil {
+			priorityDone(priority)
+		}
 	}
 }
 
-func (i *informer) forEachHandlerReversed(obj interface{}, f func(h *Handler)) {
+func (i *informer) forEachHandlerReversed(obj interface{}, f func(h *Handler), priorityDone func(priority int)) {
+	start := informerLockWait.Start()
 	i.RLock()
+	informerLockWait.Observe(start)
 	defer i.RUnlock()
 
 	objType := reflect.TypeOf(obj)
@@ -163,6 +214,9 @@ func (i *informer) forEachHandlerReversed(obj interface{}, f func(h *Handler)) {
 		for _, handler := range i.handlers[priority] {
 			f(handler)
 		}
+		if priorityDone != nil {
+			priorityDone(priority)
+		}
 	}
 }
 
@@ -200,7 +254,9 @@ func (i *informer) removeHandler(handler *Handler) {
 	klog.V(5).Infof("Sending %v event handler %d for removal", i.oType, handler.id)
 
 	go func() {
+		start := informerLockWait.Start()
 		i.Lock()
+		informerLockWait.Observe(start)
 		defer i.Unlock()
 		removed := 0
 		for priority := range i.handlers { // loop over priority
@@ -220,8 +276,9 @@ func (i *informer) removeHandler(handler *Handler) {
 	}()
 }
 
-func newQueueMap(numEventQueues uint32, wg *sync.WaitGroup) *queueMap {
+func newQueueMap(name string, numEventQueues uint32, wg *sync.WaitGroup) *queueMap {
 	qm := &queueMap{
+		name:    name,
 		entries: make(map[ktypes.NamespacedName]*queueMapEntry),
 		queues:  make([]chan *event, numEventQueues),
 		wg:      wg,
@@ -234,13 +291,17 @@ func newQueueMap(numEventQueues uint32, wg *sync.WaitGroup) *queueMap {
 
 func (qm *queueMap) processEvents(queue chan *event, stopChan <-chan struct{}) {
 	defer qm.wg.Done()
+	loop := metrics.RegisterEventLoop(qm.name)
+	defer loop.Unregister()
 	for {
 		select {
 		case e, ok := <-queue:
 			if !ok {
 				return
 			}
+			loop.Begin()
 			e.process(e)
+			loop.End()
 		case <-stopChan:
 			return
 		}
@@ -301,7 +362,9 @@ func (qm *queueMap) getQueueMapEntry(oType reflect.Type, obj interface{}) (ktype
 
 	namespacedName := ktypes.NamespacedName{Namespace: meta.Namespace, Name: meta.Name}
 
+	start := queueMapLockWait.Start()
 	qm.Lock()
+	queueMapLockWait.Observe(start)
 	defer qm.Unlock()
 
 	entry, ok := qm.entries[namespacedName]
@@ -341,7 +404,9 @@ func (qm *queueMap) releaseQueueMapEntry(key ktypes.NamespacedName, entry *queue
 		return
 	}
 
+	start := queueMapLockWait.Start()
 	qm.Lock()
+	queueMapLockWait.Observe(start)
 	defer qm.Unlock()
 	if atomic.AddInt32(&entry.refcount, -1) <= 0 {
 		delete(qm.entries, key)
@@ -350,15 +415,72 @@ func (qm *queueMap) releaseQueueMapEntry(key ktypes.NamespacedName, entry *queue
 
 // enqueueEvent adds an event to the appropriate queue for the object
 func (qm *queueMap) enqueueEvent(oldObj, obj interface{}, oType reflect.Type, isDel bool, processFunc func(*event)) {
+	isUpdate := oldObj != nil
+	if qm.coalesceUpdates && isUpdate && qm.coalesceUpdate(oType, obj) {
+		return
+	}
 	key, entry := qm.getQueueMapEntry(oType, obj)
-	qm.queues[entry.queue] <- &event{
+	e := &event{
 		obj:    obj,
 		oldObj: oldObj,
 		process: func(e *event) {
+			if isUpdate {
+				qm.startUpdate(entry, e)
+			}
 			processFunc(e)
 			qm.releaseQueueMapEntry(key, entry, isDel)
 		},
 	}
+	if qm.coalesceUpdates {
+		qm.Lock()
+		if isUpdate {
+			entry.pendingUpdate = e
+		} else {
+			// adds and deletes must not be reordered with updates, so
+			// later updates may not be merged into earlier ones
+			entry.pendingUpdate = nil
+		}
+		qm.Unlock()
+	}
+	if qm.onEnqueue != nil {
+		qm.onEnqueue()
+	}
+	qm.queues[entry.queue] <- e
+}
+
+// coalesceUpdate replaces the new object of an update event for the same
+// object that is still waiting in its queue, keeping that event's old object,
+// so that handlers see a single update from the oldest to the newest state.
+// Returns false if there is no such event and the update must be queued.
+func (qm *queueMap) coalesceUpdate(oType reflect.Type, obj interface{}) bool {
+	meta, err := getObjectMeta(oType, obj)
+	if err != nil {
+		return false
+	}
+	start := queueMapLockWait.Start()
+	qm.Lock()
+	queueMapLockWait.Observe(start)
+	defer qm.Unlock()
+	entry, ok := qm.entries[ktypes.NamespacedName{Namespace: meta.Namespace, Name: meta.Name}]
+	if !ok || entry.pendingUpdate == nil {
+		return false
+	}
+	entry.pendingUpdate.obj = obj
+	klog.V(5).Infof("Coalesced %s update event for %s/%s", qm.name, meta.Namespace, meta.Name)
+	return true
+}
+
+// startUpdate marks an update event as being processed so that no further
+// updates are merged into it
+func (qm *queueMap) startUpdate(entry *queueMapEntry, e *event) {
+	if !qm.coalesceUpdates {
+		return
+	}
+	qm.Lock()
+	defer qm.Unlock()
+	if entry.pendingUpdate == e {
+		entry.pendingUpdate = nil
+	}
 }
 
 func ensureObjectOnDelete(obj interface{}, expectedType reflect.Type) (interface{}, error) {
@@ -386,17 +508,20 @@ func (i *informer) newFederatedQueuedHandler(numEventQueues uint32) cache.Resour
 				start := time.Now()
 				i.forEachQueuedHandler(func(h *Handler) {
 					h.OnAdd(e.obj)
-				})
+				}, i.priorityProcessed)
 				metrics.MetricResourceAddLatency.Observe(time.Since(start).Seconds())
 			})
 		},
 		UpdateFunc: func(oldObj, newObj interface{}) {
+			if i.isDuplicateUpdate(oldObj, newObj) {
+				return
+			}
 			i.queueMap.enqueueEvent(oldObj, newObj, i.oType, false, func(e *event) {
 				metrics.MetricResourceUpdateCount.WithLabelValues(name, "update").Inc()
 				start := time.Now()
 				i.forEachQueuedHandler(func(h *Handler) {
-					old := oldObj.(metav1.Object)
-					new := newObj.(metav1.Object)
+					old := e.oldObj.(metav1.Object)
+					new := e.obj.(metav1.Object)
 					if old.GetUID() != new.GetUID() {
 						// This occurs not so often, so log this occurance.
 						klog.Infof("Object %s/%s is replaced, invoking delete followed by add handler", new.GetNamespace(), new.GetName())
@@ -405,8 +530,10 @@ func (i *informer) newFederatedQueuedHandler(numEventQueues uint32) cache.Resour
 					} else {
 						h.OnUpdate(e.oldObj, e.obj)
 					}
+				}, func(priority int) {
+					i.updateLatency[priority].Observe(time.Since(start).Seconds())
+					i.priorityProcessed(priority)
 				})
-				metrics.MetricResourceUpdateLatency.Observe(time.Since(start).Seconds())
 			})
 		},
 		DeleteFunc: func(obj interface{}) {
@@ -420,13 +547,36 @@ func (i *informer) newFederatedQueuedHandler(numEventQueues uint32) cache.Resour
 				start := time.Now()
 				i.forEachQueuedHandlerReversed(func(h *Handler) {
 					h.OnDelete(e.obj)
-				})
+				}, i.priorityProcessed)
 				metrics.MetricResourceDeleteLatency.Observe(time.Since(start).Seconds())
 			})
 		},
 	}
 }
 
+// isDuplicateUpdate returns true, and counts the update as suppressed, if
+// update deduplication is enabled and the update carries no change of interest
+func (i *informer) isDuplicateUpdate(oldObj, newObj interface{}) bool {
+	if i.duplicateUpdate == nil || !i.duplicateUpdate(oldObj, newObj) {
+		return false
+	}
+	i.suppressedUpdates.Inc()
+	return true
+}
+
+// eventQueued accounts a queued event in the backlog of every priority
+func (i *informer) eventQueued() {
+	for _, backlog := range i.backlog {
+		backlog.Inc()
+	}
+}
+
+// priorityProcessed removes an event from the backlog of the given priority
+// once all the handlers of that priority have processed it
+func (i *informer) priorityProcessed(priority int) {
+	i.backlog[priority].Dec()
+}
+
 func (i *informer) newFederatedHandler() cache.ResourceEventHandlerFuncs {
 	name := i.oType.Elem().Name()
 	return cache.ResourceEventHandlerFuncs{
@@ -435,10 +585,13 @@ func (i *informer) newFederatedHandler() cache.ResourceEventHandlerFuncs {
 			start := time.Now()
 			i.forEachHandler(obj, func(h *Handler) {
 				h.OnAdd(obj)
-			})
+			}, nil)
 			metrics.MetricResourceAddLatency.Observe(time.Since(start).Seconds())
 		},
 		UpdateFunc: func(oldObj, newObj interface{}) {
+			if i.isDuplicateUpdate(oldObj, newObj) {
+				return
+			}
 			metrics.MetricResourceUpdateCount.WithLabelValues(name, "update").Inc()
 			start := time.Now()
 			i.forEachHandler(newObj, func(h *Handler) {
@@ -452,8 +605,9 @@ func (i *informer) newFederatedHandler() cache.ResourceEventHandlerFuncs {
 				} else {
 					h.OnUpdate(oldObj, newObj)
 				}
+			}, func(priority int) {
+				i.updateLatency[priority].Observe(time.Since(start).Seconds())
 			})
-			metrics.MetricResourceUpdateLatency.Observe(time.Since(start).Seconds())
 		},
 		DeleteFunc: func(obj interface{}) {
 			realObj, err := ensureObjectOnDelete(obj, i.oType)
@@ -465,7 +619,7 @@ func (i *informer) newFederatedHandler() cache.ResourceEventHandlerFuncs {
 			start := time.Now()
 			i.forEachHandlerReversed(realObj, func(h *Handler) {
 				h.OnDelete(realObj)
-			})
+			}, nil)
 			metrics.MetricResourceDeleteLatency.Observe(time.Since(start).Seconds())
 		},
 	}
@@ -516,6 +670,12 @@ func newInformerLister(oType reflect.Type, sharedInformer cache.SharedIndexInfor
 		return multinetworkpolicylister.NewMultiNetworkPolicyLister(sharedInformer.GetIndexer()), nil
 	case EgressServiceType:
 		return egressservicelister.NewEgressServiceLister(sharedInformer.GetIndexer()), nil
+	case AdminNetworkPolicyType:
+		return anplister.NewAdminNetworkPolicyLister(sharedInformer.GetIndexer()), nil
+	case BaselineAdminNetworkPolicyType:
+		return anplister.NewBaselineAdminNetworkPolicyLister(sharedInformer.GetIndexer()), nil
+	case IPAMClaimsType:
+		return ipamclaimslister.NewIPAMClaimLister(sharedInformer.GetIndexer()), nil
 	}
 
 	return nil, fmt.Errorf("cannot create lister from type %v", oType)
@@ -528,12 +688,24 @@ func newBaseInformer(oType reflect.Type, sharedInformer cache.SharedIndexInforme
 		return nil, err
 	}
 
-	return &informer{
-		oType:    oType,
-		inf:      sharedInformer,
-		lister:   lister,
-		handlers: make(map[int]map[uint64]*Handler),
-	}, nil
+	i := &informer{
+		oType:         oType,
+		inf:           sharedInformer,
+		lister:        lister,
+		handlers:      make(map[int]map[uint64]*Handler),
+		updateLatency: make([]prometheus.Observer, minHandlerPriority+1),
+		backlog:       make([]prometheus.Gauge, minHandlerPriority+1),
+	}
+	name := oType.Elem().Name()
+	for priority := 0; priority <= minHandlerPriority; priority++ {
+		i.updateLatency[priority] = metrics.MetricResourceUpdateLatency.WithLabelValues(name, strconv.Itoa(priority))
+		i.backlog[priority] = metrics.MetricResourceEventBacklog.WithLabelValues(name, strconv.Itoa(priority))
+	}
+	if config.Kubernetes.DeduplicateUpdates.Has(name) {
+		i.duplicateUpdate = updateDeduplicators[oType]
+		i.suppressedUpdates = metrics.MetricResourceUpdateSuppressedCount.WithLabelValues(name)
+	}
+	return i, nil
 }
 
 func newInformer(oType reflect.Type, sharedInformer cache.SharedIndexInformer) (*informer, error) {
@@ -560,7 +732,10 @@ func newQueuedInformer(oType reflect.Type, sharedInformer cache.SharedIndexInfor
 	if err != nil {
 		return nil, err
 	}
-	i.queueMap = newQueueMap(numEventQueues, &i.shutdownWg)
+	name := oType.Elem().Name()
+	i.queueMap = newQueueMap(name, numEventQueues, &i.shutdownWg)
+	i.queueMap.coalesceUpdates = config.Kubernetes.CoalesceUpdates.Has(name)
+	i.queueMap.onEnqueue = i.eventQueued
 	i.queueMap.start(stopChan)
 
 	i.initialAddFunc = func(h *Handler, items []interface{}) {
@@ -569,7 +744,7 @@ func newQueuedInformer(oType reflect.Type, sharedInformer cache.SharedIndexInfor
 		// is added, only that handler should receive events for all
 		// existing objects.
 		addsWg := &sync.WaitGroup{}
-		addsMap := newQueueMap(numEventQueues, addsWg)
+		addsMap := newQueueMap(name+"_initial_add", numEventQueues, addsWg)
 		addsMap.start(stopChan)
 
 		// Distribute the existing items into the handler-specific
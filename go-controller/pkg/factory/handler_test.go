@@ -0,0 +1,192 @@
+package factory
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	v1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Queue map", func() {
+	var (
+		qm        *queueMap
+		processed []*event
+	)
+
+	record := func(e *event) {
+		processed = append(processed, &event{obj: e.obj, oldObj: e.oldObj})
+	}
+
+	// drain processes every event waiting in the queues
+	drain := func() {
+		for _, q := range qm.queues {
+			for len(q) > 0 {
+				e := <-q
+				e.process(e)
+			}
+		}
+	}
+
+	podWithVersion := func(version string) *v1.Pod {
+		pod := newPod("pod1", "default")
+		pod.ResourceVersion = version
+		return pod
+	}
+
+	BeforeEach(func() {
+		processed = nil
+		qm = newQueueMap("Pod", 2, &sync.WaitGroup{})
+		qm.coalesceUpdates = true
+	})
+
+	It("delivers only the newest pending update of an object", func() {
+		podType := reflect.TypeOf(&v1.Pod{})
+		qm.enqueueEvent(podWithVersion("1"), podWithVersion("2"), podType, false, record)
+		qm.enqueueEvent(podWithVersion("2"), podWithVersion("3"), podType, false, record)
+		qm.enqueueEvent(podWithVersion("3"), podWithVersion("4"), podType, false, record)
+		drain()
+
+		Expect(processed).To(HaveLen(1))
+		Expect(processed[0].oldObj.(*v1.Pod).ResourceVersion).To(Equal("1"))
+		Expect(processed[0].obj.(*v1.Pod).ResourceVersion).To(Equal("4"))
+
+		// once processed, a new update is queued again
+		qm.enqueueEvent(podWithVersion("4"), podWithVersion("5"), podType, false, record)
+		drain()
+		Expect(processed).To(HaveLen(2))
+	})
+
+	It("does not merge updates across a delete", func() {
+		podType := reflect.TypeOf(&v1.Pod{})
+		qm.enqueueEvent(podWithVersion("1"), podWithVersion("2"), podType, false, record)
+		qm.enqueueEvent(nil, podWithVersion("2"), podType, true, record)
+		qm.enqueueEvent(nil, podWithVersion("3"), podType, false, record)
+		qm.enqueueEvent(podWithVersion("3"), podWithVersion("4"), podType, false, record)
+		drain()
+
+		Expect(processed).To(HaveLen(4))
+	})
+
+	It("queues every update when coalescing is disabled", func() {
+		qm.coalesceUpdates = false
+		podType := reflect.TypeOf(&v1.Pod{})
+		qm.enqueueEvent(podWithVersion("1"), podWithVersion("2"), podType, false, record)
+		qm.enqueueEvent(podWithVersion("2"), podWithVersion("3"), podType, false, record)
+		drain()
+
+		Expect(processed).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("Informer event metrics", func() {
+	backlog := func(priority string) float64 {
+		m := &dto.Metric{}
+		Expect(metrics.MetricResourceEventBacklog.WithLabelValues("Pod", priority).Write(m)).To(Succeed())
+		return m.GetGauge().GetValue()
+	}
+	updateLatencySamples := func(priority string) uint64 {
+		m := &dto.Metric{}
+		observer := metrics.MetricResourceUpdateLatency.WithLabelValues("Pod", priority)
+		Expect(observer.(prometheus.Metric).Write(m)).To(Succeed())
+		return m.GetHistogram().GetSampleCount()
+	}
+
+	It("tracks the backlog and update latency per handler priority", func() {
+		stopChan := make(chan struct{})
+		defer close(stopChan)
+		sharedInformer := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0).Core().V1().Pods().Informer()
+		i, err := newQueuedInformer(PodType, sharedInformer, stopChan, 2)
+		Expect(err).NotTo(HaveOccurred())
+
+		unblock := make(chan struct{})
+		filter := func(obj interface{}) bool { return true }
+		i.addHandler(0, 0, filter, cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(old, new interface{}) { <-unblock },
+		}, nil)
+		i.addHandler(1, 1, filter, cache.ResourceEventHandlerFuncs{}, nil)
+
+		baseBacklog0, baseBacklog1 := backlog("0"), backlog("1")
+		baseSamples0, baseSamples1 := updateLatencySamples("0"), updateLatencySamples("1")
+
+		pod := newPod("pod1", "default")
+		i.newFederatedQueuedHandler(2).UpdateFunc(pod, pod.DeepCopy())
+		Eventually(func() float64 { return backlog("0") - baseBacklog0 }).Should(Equal(1.0))
+		Expect(backlog("1") - baseBacklog1).To(Equal(1.0))
+
+		close(unblock)
+		Eventually(func() float64 { return backlog("1") - baseBacklog1 }).Should(BeZero())
+		Expect(backlog("0") - baseBacklog0).To(BeZero())
+		Expect(updateLatencySamples("0") - baseSamples0).To(Equal(uint64(1)))
+		Expect(updateLatencySamples("1") - baseSamples1).To(Equal(uint64(1)))
+	})
+})
+
+var _ = Describe("Update deduplication", func() {
+	var updates int
+
+	suppressed := func() float64 {
+		m := &dto.Metric{}
+		Expect(metrics.MetricResourceUpdateSuppressedCount.WithLabelValues("EndpointSlice").Write(m)).To(Succeed())
+		return m.GetCounter().GetValue()
+	}
+
+	newEndpointSliceInformer := func() *informer {
+		sharedInformer := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0).Discovery().V1().EndpointSlices().Informer()
+		i, err := newInformer(EndpointSliceType, sharedInformer)
+		Expect(err).NotTo(HaveOccurred())
+		i.addHandler(0, 0, func(obj interface{}) bool { return true }, cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(old, new interface{}) { updates++ },
+		}, nil)
+		return i
+	}
+
+	BeforeEach(func() {
+		config.PrepareTestConfig()
+		updates = 0
+	})
+
+	It("drops endpoint slice updates that only change the resource version or managed fields", func() {
+		config.Kubernetes.DeduplicateUpdates = sets.NewString("EndpointSlice")
+		i := newEndpointSliceInformer()
+		base := suppressed()
+
+		old := newEndpointSlice("slice1", "default", "svc1")
+		old.ResourceVersion = "1"
+		updated := old.DeepCopy()
+		updated.ResourceVersion = "2"
+		updated.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "endpointslice-controller"}}
+		i.newFederatedHandler().UpdateFunc(old, updated)
+		Expect(updates).To(Equal(0))
+		Expect(suppressed() - base).To(Equal(1.0))
+
+		changed := updated.DeepCopy()
+		changed.ResourceVersion = "3"
+		changed.Endpoints = append(changed.Endpoints, discovery.Endpoint{Addresses: []string{"10.0.0.1"}})
+		i.newFederatedHandler().UpdateFunc(updated, changed)
+		Expect(updates).To(Equal(1))
+		Expect(suppressed() - base).To(Equal(1.0))
+	})
+
+	It("delivers every update when deduplication is disabled", func() {
+		i := newEndpointSliceInformer()
+		old := newEndpointSlice("slice1", "default", "svc1")
+		updated := old.DeepCopy()
+		updated.ResourceVersion = "2"
+		i.newFederatedHandler().UpdateFunc(old, updated)
+		Expect(updates).To(Equal(1))
+	})
+})
@@ -0,0 +1,88 @@
+package factory
+
+import (
+	"reflect"
+	"runtime/debug"
+	"time"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/metrics"
+
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// HandlerMiddleware wraps the event handler of a registration, eg to log or
+// time its events. It is given the type of the objects the handler is for.
+type HandlerMiddleware func(objType reflect.Type, handler cache.ResourceEventHandler) cache.ResourceEventHandler
+
+// aroundEvents returns a handler that calls around for each event of handler,
+// with the name of the event, the object it is for, and a function that
+// delivers the event to handler
+func aroundEvents(handler cache.ResourceEventHandler, around func(event string, obj interface{}, next func())) cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			around("add", obj, func() { handler.OnAdd(obj) })
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			around("update", newObj, func() { handler.OnUpdate(oldObj, newObj) })
+		},
+		DeleteFunc: func(obj interface{}) {
+			around("delete", obj, func() { handler.OnDelete(obj) })
+		},
+	}
+}
+
+// eventObjectKey returns the namespace/name key of the object of an event, for logging
+func eventObjectKey(obj interface{}) string {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return "<unknown>"
+	}
+	return key
+}
+
+// recoverPanics is applied to all the handlers, so that a panic in one
+// handler drops the event for that handler only instead of killing the event
+// queue shared by all the handlers of the informer
+func recoverPanics(objType reflect.Type, handler cache.ResourceEventHandler) cache.ResourceEventHandler {
+	return aroundEvents(handler, func(event string, obj interface{}, next func()) {
+		defer func() {
+			if r := recover(); r != nil {
+				metrics.IncrementEventHandlerPanics(objType.Elem().Name())
+				klog.Errorf("Recovered from panic in %v %s event handler for %s: %v\n%s",
+					objType, event, eventObjectKey(obj), r, debug.Stack())
+			}
+		}()
+		next()
+	})
+}
+
+// LogEvents returns a middleware that logs the events processed by the
+// handler with the given name, and how long they took, at the given verbosity
+func LogEvents(name string, level klog.Level) HandlerMiddleware {
+	return func(objType reflect.Type, handler cache.ResourceEventHandler) cache.ResourceEventHandler {
+		return aroundEvents(handler, func(event string, obj interface{}, next func()) {
+			if !klog.V(level).Enabled() {
+				next()
+				return
+			}
+			key := eventObjectKey(obj)
+			klog.V(level).Infof("Handler %s processing %s event for %v %s", name, event, objType, key)
+			start := time.Now()
+			next()
+			klog.V(level).Infof("Handler %s processed %s event for %v %s in %v", name, event, objType, key, time.Since(start))
+		})
+	}
+}
+
+// TimeEvents returns a middleware that records the time the handler with the
+// given name takes to process each event in the event handler duration metric
+func TimeEvents(name string) HandlerMiddleware {
+	return func(objType reflect.Type, handler cache.ResourceEventHandler) cache.ResourceEventHandler {
+		return aroundEvents(handler, func(event string, obj interface{}, next func()) {
+			start := time.Now()
+			next()
+			metrics.ObserveEventHandlerDuration(name, event, time.Since(start))
+		})
+	}
+}
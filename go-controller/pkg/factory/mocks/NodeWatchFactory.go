@@ -112,6 +112,29 @@ func (_m *NodeWatchFactory) AddPodHandler(handlerFuncs cache.ResourceEventHandle
 	return r0, r1
 }
 
+// AddPodHandlerWithFilter provides a mock function with given fields: filter, handlerFuncs, processExisting
+func (_m *NodeWatchFactory) AddPodHandlerWithFilter(filter *factory.EventFilter, handlerFuncs cache.ResourceEventHandler, processExisting func([]interface{}) error) (*factory.Handler, error) {
+	ret := _m.Called(filter, handlerFuncs, processExisting)
+
+	var r0 *factory.Handler
+	if rf, ok := ret.Get(0).(func(*factory.EventFilter, cache.ResourceEventHandler, func([]interface{}) error) *factory.Handler); ok {
+		r0 = rf(filter, handlerFuncs, processExisting)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*factory.Handler)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*factory.EventFilter, cache.ResourceEventHandler, func([]interface{}) error) error); ok {
+		r1 = rf(filter, handlerFuncs, processExisting)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // AddServiceHandler provides a mock function with given fields: handlerFuncs, processExisting
 func (_m *NodeWatchFactory) AddServiceHandler(handlerFuncs cache.ResourceEventHandler, processExisting func([]interface{}) error) (*factory.Handler, error) {
 	ret := _m.Called(handlerFuncs, processExisting)
@@ -0,0 +1,507 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	mnpapi "github.com/k8snetworkplumbingwg/multi-networkpolicy/pkg/apis/k8s.cni.cncf.io/v1beta1"
+	mnpclientset "github.com/k8snetworkplumbingwg/multi-networkpolicy/pkg/client/clientset/versioned"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	egressfirewallapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1"
+	egressfirewallclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1/apis/clientset/versioned"
+	egressqosapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1"
+	egressqosclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1/apis/clientset/versioned"
+	egressserviceapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressservice/v1"
+	egressserviceclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressservice/v1/apis/clientset/versioned"
+
+	kapi "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	knet "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// namespacedListFunc lists objects of a single type in the given namespace
+type namespacedListFunc func(namespace string, options metav1.ListOptions) (runtime.Object, error)
+
+// namespacedWatchFunc watches objects of a single type in the given namespace
+type namespacedWatchFunc func(namespace string, options metav1.ListOptions) (watch.Interface, error)
+
+// watchedNamespaces returns the namespaces that namespace-scoped informers list
+// and watch. When no namespaces are configured every namespace is watched.
+func watchedNamespaces() []string {
+	if len(config.Kubernetes.WatchNamespaces) == 0 {
+		return []string{metav1.NamespaceAll}
+	}
+	return config.Kubernetes.WatchNamespaces
+}
+
+// newNamespaceListWatch returns a ListerWatcher that lists and watches objects
+// in the given namespace
+func newNamespaceListWatch(namespace string, tweakListOptions func(*metav1.ListOptions),
+	listFunc namespacedListFunc, watchFunc namespacedWatchFunc) *cache.ListWatch {
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			if tweakListOptions != nil {
+				tweakListOptions(&options)
+			}
+			return listFunc(namespace, options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			if tweakListOptions != nil {
+				tweakListOptions(&options)
+			}
+			return watchFunc(namespace, options)
+		},
+	}
+}
+
+// multiNamespaceInformer is a SharedIndexInformer made of one shared informer
+// per namespace. Resource versions are only meaningful within the list and
+// watch they come from, so every namespace is listed and watched by its own
+// reflector and a watch that has to be re-established only relists its own
+// namespace. Every namespace informer delivers events from its own goroutine,
+// so the event handlers are wrapped to be called one event at a time, as with
+// a single informer.
+type multiNamespaceInformer struct {
+	informers map[string]cache.SharedIndexInformer
+	indexer   *multiNamespaceIndexer
+}
+
+// multiNamespaceRegistration holds the registrations of an event handler with
+// every namespace informer
+type multiNamespaceRegistration map[string]cache.ResourceEventHandlerRegistration
+
+func newMultiNamespaceInformer(namespaces []string, objType runtime.Object, resyncPeriod time.Duration,
+	tweakListOptions func(*metav1.ListOptions), listFunc namespacedListFunc, watchFunc namespacedWatchFunc) *multiNamespaceInformer {
+	mi := &multiNamespaceInformer{
+		informers: make(map[string]cache.SharedIndexInformer, len(namespaces)),
+		indexer:   &multiNamespaceIndexer{indexers: make(map[string]cache.Indexer, len(namespaces))},
+	}
+	for _, namespace := range namespaces {
+		inf := cache.NewSharedIndexInformer(
+			newNamespaceListWatch(namespace, tweakListOptions, listFunc, watchFunc),
+			objType,
+			resyncPeriod,
+			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+		)
+		mi.informers[namespace] = inf
+		mi.indexer.indexers[namespace] = inf.GetIndexer()
+	}
+	return mi
+}
+
+func (mi *multiNamespaceInformer) AddEventHandler(handler cache.ResourceEventHandler) (cache.ResourceEventHandlerRegistration, error) {
+	return mi.AddEventHandlerWithResyncPeriod(handler, 0)
+}
+
+func (mi *multiNamespaceInformer) AddEventHandlerWithResyncPeriod(handler cache.ResourceEventHandler,
+	resyncPeriod time.Duration) (cache.ResourceEventHandlerRegistration, error) {
+	registration := make(multiNamespaceRegistration, len(mi.informers))
+	handler = &serializedHandler{handler: handler}
+	for namespace, inf := range mi.informers {
+		r, err := inf.AddEventHandlerWithResyncPeriod(handler, resyncPeriod)
+		if err != nil {
+			_ = mi.RemoveEventHandler(registration)
+			return nil, fmt.Errorf("failed to add event handler for namespace %q: %w", namespace, err)
+		}
+		registration[namespace] = r
+	}
+	return registration, nil
+}
+
+func (mi *multiNamespaceInformer) RemoveEventHandler(handle cache.ResourceEventHandlerRegistration) error {
+	registration, ok := handle.(multiNamespaceRegistration)
+	if !ok {
+		return fmt.Errorf("unexpected event handler registration %T", handle)
+	}
+	for namespace, r := range registration {
+		if err := mi.informers[namespace].RemoveEventHandler(r); err != nil {
+			return fmt.Errorf("failed to remove event handler for namespace %q: %w", namespace, err)
+		}
+	}
+	return nil
+}
+
+func (mi *multiNamespaceInformer) GetStore() cache.Store {
+	return mi.indexer
+}
+
+func (mi *multiNamespaceInformer) GetIndexer() cache.Indexer {
+	return mi.indexer
+}
+
+func (mi *multiNamespaceInformer) GetController() cache.Controller {
+	return mi
+}
+
+// Run runs the informers of every namespace until stopCh is closed
+func (mi *multiNamespaceInformer) Run(stopCh <-chan struct{}) {
+	wg := &sync.WaitGroup{}
+	for _, inf := range mi.informers {
+		wg.Add(1)
+		go func(inf cache.SharedIndexInformer) {
+			defer wg.Done()
+			inf.Run(stopCh)
+		}(inf)
+	}
+	wg.Wait()
+}
+
+// HasSynced returns true once the informers of every namespace have synced
+func (mi *multiNamespaceInformer) HasSynced() bool {
+	for _, inf := range mi.informers {
+		if !inf.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+// LastSyncResourceVersion returns an empty string as the namespace informers
+// have no resource version in common. It is only used to resume a watch, and
+// every namespace informer resumes its own watch from its own resource version.
+func (mi *multiNamespaceInformer) LastSyncResourceVersion() string {
+	return ""
+}
+
+func (mi *multiNamespaceInformer) SetWatchErrorHandler(handler cache.WatchErrorHandler) error {
+	for _, inf := range mi.informers {
+		if err := inf.SetWatchErrorHandler(handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (mi *multiNamespaceInformer) SetTransform(handler cache.TransformFunc) error {
+	for _, inf := range mi.informers {
+		if err := inf.SetTransform(handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsStopped returns true once the informers of every namespace have stopped
+func (mi *multiNamespaceInformer) IsStopped() bool {
+	for _, inf := range mi.informers {
+		if !inf.IsStopped() {
+			return false
+		}
+	}
+	return true
+}
+
+func (mi *multiNamespaceInformer) AddIndexers(indexers cache.Indexers) error {
+	for _, inf := range mi.informers {
+		if err := inf.AddIndexers(indexers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// serializedHandler calls handler for the events of all the namespace
+// informers of a multiNamespaceInformer one at a time
+type serializedHandler struct {
+	lock    sync.Mutex
+	handler cache.ResourceEventHandler
+}
+
+func (h *serializedHandler) OnAdd(obj interface{}) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.handler.OnAdd(obj)
+}
+
+func (h *serializedHandler) OnUpdate(oldObj, newObj interface{}) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.handler.OnUpdate(oldObj, newObj)
+}
+
+func (h *serializedHandler) OnDelete(obj interface{}) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.handler.OnDelete(obj)
+}
+
+// multiNamespaceIndexer presents the indexers of the namespace informers of a
+// multiNamespaceInformer as a single indexer. Objects are stored in the indexer
+// of their namespace.
+type multiNamespaceIndexer struct {
+	indexers map[string]cache.Indexer
+}
+
+func (m *multiNamespaceIndexer) indexerForObject(obj interface{}) (cache.Indexer, error) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return nil, err
+	}
+	return m.indexerForKey(key)
+}
+
+func (m *multiNamespaceIndexer) indexerForKey(key string) (cache.Indexer, error) {
+	namespace, _, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return nil, err
+	}
+	indexer, ok := m.indexers[namespace]
+	if !ok {
+		return nil, fmt.Errorf("namespace %q of %s is not watched", namespace, key)
+	}
+	return indexer, nil
+}
+
+func (m *multiNamespaceIndexer) Add(obj interface{}) error {
+	indexer, err := m.indexerForObject(obj)
+	if err != nil {
+		return err
+	}
+	return indexer.Add(obj)
+}
+
+func (m *multiNamespaceIndexer) Update(obj interface{}) error {
+	indexer, err := m.indexerForObject(obj)
+	if err != nil {
+		return err
+	}
+	return indexer.Update(obj)
+}
+
+func (m *multiNamespaceIndexer) Delete(obj interface{}) error {
+	indexer, err := m.indexerForObject(obj)
+	if err != nil {
+		return err
+	}
+	return indexer.Delete(obj)
+}
+
+func (m *multiNamespaceIndexer) List() []interface{} {
+	var items []interface{}
+	for _, indexer := range m.indexers {
+		items = append(items, indexer.List()...)
+	}
+	return items
+}
+
+func (m *multiNamespaceIndexer) ListKeys() []string {
+	var keys []string
+	for _, indexer := range m.indexers {
+		keys = append(keys, indexer.ListKeys()...)
+	}
+	return keys
+}
+
+func (m *multiNamespaceIndexer) Get(obj interface{}) (interface{}, bool, error) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return nil, false, err
+	}
+	return m.GetByKey(key)
+}
+
+func (m *multiNamespaceIndexer) GetByKey(key string) (interface{}, bool, error) {
+	indexer, err := m.indexerForKey(key)
+	if err != nil {
+		// objects of namespaces that are not watched don't exist
+		return nil, false, nil
+	}
+	return indexer.GetByKey(key)
+}
+
+// Replace replaces the content of the indexer of every namespace with the
+// given objects of that namespace
+func (m *multiNamespaceIndexer) Replace(list []interface{}, resourceVersion string) error {
+	byNamespace := make(map[string][]interface{}, len(m.indexers))
+	for _, obj := range list {
+		key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+		if err != nil {
+			return err
+		}
+		namespace, _, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			return err
+		}
+		if _, ok := m.indexers[namespace]; !ok {
+			return fmt.Errorf("namespace %q of %s is not watched", namespace, key)
+		}
+		byNamespace[namespace] = append(byNamespace[namespace], obj)
+	}
+	for namespace, indexer := range m.indexers {
+		if err := indexer.Replace(byNamespace[namespace], resourceVersion); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *multiNamespaceIndexer) Resync() error {
+	for _, indexer := range m.indexers {
+		if err := indexer.Resync(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *multiNamespaceIndexer) Index(indexName string, obj interface{}) ([]interface{}, error) {
+	indexer, err := m.indexerForObject(obj)
+	if err != nil {
+		return nil, err
+	}
+	return indexer.Index(indexName, obj)
+}
+
+func (m *multiNamespaceIndexer) IndexKeys(indexName, indexedValue string) ([]string, error) {
+	var keys []string
+	for _, indexer := range m.indexers {
+		nsKeys, err := indexer.IndexKeys(indexName, indexedValue)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, nsKeys...)
+	}
+	return keys, nil
+}
+
+func (m *multiNamespaceIndexer) ListIndexFuncValues(indexName string) []string {
+	values := sets.NewString()
+	for _, indexer := range m.indexers {
+		values.Insert(indexer.ListIndexFuncValues(indexName)...)
+	}
+	return values.UnsortedList()
+}
+
+func (m *multiNamespaceIndexer) ByIndex(indexName, indexedValue string) ([]interface{}, error) {
+	var items []interface{}
+	for _, indexer := range m.indexers {
+		nsItems, err := indexer.ByIndex(indexName, indexedValue)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, nsItems...)
+	}
+	return items, nil
+}
+
+// GetIndexers returns the indexers of the namespace indexers, which are all the same
+func (m *multiNamespaceIndexer) GetIndexers() cache.Indexers {
+	for _, indexer := range m.indexers {
+		return indexer.GetIndexers()
+	}
+	return cache.Indexers{}
+}
+
+func (m *multiNamespaceIndexer) AddIndexers(newIndexers cache.Indexers) error {
+	for _, indexer := range m.indexers {
+		if err := indexer.AddIndexers(newIndexers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newNamespacedInformer returns a shared informer for objType restricted to the
+// watched namespaces
+func newNamespacedInformer(objType runtime.Object, resyncPeriod time.Duration, tweakListOptions func(*metav1.ListOptions),
+	listFunc namespacedListFunc, watchFunc namespacedWatchFunc) cache.SharedIndexInformer {
+	namespaces := watchedNamespaces()
+	if len(namespaces) > 1 {
+		return newMultiNamespaceInformer(namespaces, objType, resyncPeriod, tweakListOptions, listFunc, watchFunc)
+	}
+	return cache.NewSharedIndexInformer(
+		newNamespaceListWatch(namespaces[0], tweakListOptions, listFunc, watchFunc),
+		objType,
+		resyncPeriod,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+}
+
+func newNamespacedPodInformer(c kubernetes.Interface, resyncPeriod time.Duration, tweakListOptions func(*metav1.ListOptions)) cache.SharedIndexInformer {
+	return newNamespacedInformer(&kapi.Pod{}, resyncPeriod, tweakListOptions,
+		func(namespace string, options metav1.ListOptions) (runtime.Object, error) {
+			return c.CoreV1().Pods(namespace).List(context.TODO(), options)
+		},
+		func(namespace string, options metav1.ListOptions) (watch.Interface, error) {
+			return c.CoreV1().Pods(namespace).Watch(context.TODO(), options)
+		})
+}
+
+func newNamespacedServiceInformer(c kubernetes.Interface, resyncPeriod time.Duration, tweakListOptions func(*metav1.ListOptions)) cache.SharedIndexInformer {
+	return newNamespacedInformer(&kapi.Service{}, resyncPeriod, tweakListOptions,
+		func(namespace string, options metav1.ListOptions) (runtime.Object, error) {
+			return c.CoreV1().Services(namespace).List(context.TODO(), options)
+		},
+		func(namespace string, options metav1.ListOptions) (watch.Interface, error) {
+			return c.CoreV1().Services(namespace).Watch(context.TODO(), options)
+		})
+}
+
+func newNamespacedEndpointSliceInformer(c kubernetes.Interface, resyncPeriod time.Duration, tweakListOptions func(*metav1.ListOptions)) cache.SharedIndexInformer {
+	return newNamespacedInformer(&discovery.EndpointSlice{}, resyncPeriod, tweakListOptions,
+		func(namespace string, options metav1.ListOptions) (runtime.Object, error) {
+			return c.DiscoveryV1().EndpointSlices(namespace).List(context.TODO(), options)
+		},
+		func(namespace string, options metav1.ListOptions) (watch.Interface, error) {
+			return c.DiscoveryV1().EndpointSlices(namespace).Watch(context.TODO(), options)
+		})
+}
+
+func newNamespacedNetworkPolicyInformer(c kubernetes.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return newNamespacedInformer(&knet.NetworkPolicy{}, resyncPeriod, nil,
+		func(namespace string, options metav1.ListOptions) (runtime.Object, error) {
+			return c.NetworkingV1().NetworkPolicies(namespace).List(context.TODO(), options)
+		},
+		func(namespace string, options metav1.ListOptions) (watch.Interface, error) {
+			return c.NetworkingV1().NetworkPolicies(namespace).Watch(context.TODO(), options)
+		})
+}
+
+func newNamespacedEgressFirewallInformer(c egressfirewallclientset.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return newNamespacedInformer(&egressfirewallapi.EgressFirewall{}, resyncPeriod, nil,
+		func(namespace string, options metav1.ListOptions) (runtime.Object, error) {
+			return c.K8sV1().EgressFirewalls(namespace).List(context.TODO(), options)
+		},
+		func(namespace string, options metav1.ListOptions) (watch.Interface, error) {
+			return c.K8sV1().EgressFirewalls(namespace).Watch(context.TODO(), options)
+		})
+}
+
+func newNamespacedEgressQoSInformer(c egressqosclientset.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return newNamespacedInformer(&egressqosapi.EgressQoS{}, resyncPeriod, nil,
+		func(namespace string, options metav1.ListOptions) (runtime.Object, error) {
+			return c.K8sV1().EgressQoSes(namespace).List(context.TODO(), options)
+		},
+		func(namespace string, options metav1.ListOptions) (watch.Interface, error) {
+			return c.K8sV1().EgressQoSes(namespace).Watch(context.TODO(), options)
+		})
+}
+
+func newNamespacedEgressServiceInformer(c egressserviceclientset.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return newNamespacedInformer(&egressserviceapi.EgressService{}, resyncPeriod, nil,
+		func(namespace string, options metav1.ListOptions) (runtime.Object, error) {
+			return c.K8sV1().EgressServices(namespace).List(context.TODO(), options)
+		},
+		func(namespace string, options metav1.ListOptions) (watch.Interface, error) {
+			return c.K8sV1().EgressServices(namespace).Watch(context.TODO(), options)
+		})
+}
+
+func newNamespacedMultiNetworkPolicyInformer(c mnpclientset.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return newNamespacedInformer(&mnpapi.MultiNetworkPolicy{}, resyncPeriod, nil,
+		func(namespace string, options metav1.ListOptions) (runtime.Object, error) {
+			return c.K8sCniCncfIoV1beta1().MultiNetworkPolicies(namespace).List(context.TODO(), options)
+		},
+		func(namespace string, options metav1.ListOptions) (watch.Interface, error) {
+			return c.K8sCniCncfIoV1beta1().MultiNetworkPolicies(namespace).Watch(context.TODO(), options)
+		})
+}
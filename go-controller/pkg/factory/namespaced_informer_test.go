@@ -0,0 +1,206 @@
+package factory
+
+import (
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Namespace-scoped informers", func() {
+	var (
+		lock               sync.Mutex
+		podsByNamespace    map[string][]v1.Pod
+		listRVByNamespace  map[string]string
+		listOptions        map[string][]metav1.ListOptions
+		watchOptions       map[string][]metav1.ListOptions
+		watchers           map[string]*watch.FakeWatcher
+		listFunc           namespacedListFunc
+		watchFunc          namespacedWatchFunc
+		stopCh             chan struct{}
+		addedPods          sets.String
+		informerNamespaces []string
+	)
+
+	withRV := func(pod *v1.Pod, rv string) *v1.Pod {
+		pod.ResourceVersion = rv
+		return pod
+	}
+
+	watcherFor := func(namespace string) func() *watch.FakeWatcher {
+		return func() *watch.FakeWatcher {
+			lock.Lock()
+			defer lock.Unlock()
+			return watchers[namespace]
+		}
+	}
+
+	BeforeEach(func() {
+		// every namespace has its own, unrelated, resource versions
+		podsByNamespace = map[string][]v1.Pod{
+			"ns1": {*withRV(newPod("pod1", "ns1"), "10")},
+			"ns2": {*withRV(newPod("pod2", "ns2"), "500"), *withRV(newPod("pod3", "ns2"), "501")},
+			"ns3": {*withRV(newPod("pod4", "ns3"), "7")},
+		}
+		listRVByNamespace = map[string]string{"ns1": "10", "ns2": "501", "ns3": "7"}
+		listOptions = map[string][]metav1.ListOptions{}
+		watchOptions = map[string][]metav1.ListOptions{}
+		watchers = map[string]*watch.FakeWatcher{}
+		listFunc = func(namespace string, options metav1.ListOptions) (runtime.Object, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			listOptions[namespace] = append(listOptions[namespace], options)
+			return &v1.PodList{
+				ListMeta: metav1.ListMeta{ResourceVersion: listRVByNamespace[namespace]},
+				Items:    podsByNamespace[namespace],
+			}, nil
+		}
+		watchFunc = func(namespace string, options metav1.ListOptions) (watch.Interface, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			watchOptions[namespace] = append(watchOptions[namespace], options)
+			w := watch.NewFake()
+			watchers[namespace] = w
+			return w, nil
+		}
+		stopCh = make(chan struct{})
+		addedPods = sets.NewString()
+		informerNamespaces = []string{"ns1", "ns2"}
+	})
+
+	AfterEach(func() {
+		close(stopCh)
+	})
+
+	startInformer := func() *multiNamespaceInformer {
+		inf := newMultiNamespaceInformer(informerNamespaces, &v1.Pod{}, 0, func(options *metav1.ListOptions) {
+			options.LabelSelector = "foo=bar"
+		}, listFunc, watchFunc)
+		_, err := inf.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				lock.Lock()
+				defer lock.Unlock()
+				addedPods.Insert(obj.(*v1.Pod).Name)
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		go inf.Run(stopCh)
+		Eventually(inf.HasSynced).Should(BeTrue())
+		return inf
+	}
+
+	getAddedPods := func() []string {
+		lock.Lock()
+		defer lock.Unlock()
+		return addedPods.List()
+	}
+
+	It("lists and watches every namespace with its own options", func() {
+		inf := startInformer()
+
+		Expect(getAddedPods()).To(ConsistOf("pod1", "pod2", "pod3"))
+		Expect(inf.GetIndexer().ListKeys()).To(ConsistOf("ns1/pod1", "ns2/pod2", "ns2/pod3"))
+
+		lock.Lock()
+		defer lock.Unlock()
+		Expect(listOptions).To(HaveLen(2))
+		Expect(listOptions["ns1"][0].LabelSelector).To(Equal("foo=bar"))
+		Expect(listOptions["ns1"][0].ResourceVersionMatch).To(BeEmpty())
+		Expect(listOptions["ns2"][0].ResourceVersionMatch).To(BeEmpty())
+		Expect(watchOptions["ns1"][0].ResourceVersion).To(Equal("10"))
+		Expect(watchOptions["ns2"][0].ResourceVersion).To(Equal("501"))
+	})
+
+	It("doesn't lose events across namespaces", func() {
+		inf := startInformer()
+		Eventually(watcherFor("ns1")).ShouldNot(BeNil())
+		Eventually(watcherFor("ns2")).ShouldNot(BeNil())
+
+		// interleave events of both namespaces, with resource versions that
+		// are unrelated across namespaces
+		watcherFor("ns2")().Add(withRV(newPod("pod5", "ns2"), "502"))
+		watcherFor("ns1")().Add(withRV(newPod("pod6", "ns1"), "11"))
+		watcherFor("ns2")().Add(withRV(newPod("pod7", "ns2"), "503"))
+		watcherFor("ns1")().Add(withRV(newPod("pod8", "ns1"), "12"))
+		Eventually(getAddedPods).Should(ConsistOf("pod1", "pod2", "pod3", "pod5", "pod6", "pod7", "pod8"))
+
+		// terminating the watch of ns1 only re-establishes the watch of ns1,
+		// from its own resource version
+		watcherFor("ns1")().Stop()
+		Eventually(func() int {
+			lock.Lock()
+			defer lock.Unlock()
+			return len(watchOptions["ns1"])
+		}).Should(Equal(2))
+		lock.Lock()
+		Expect(watchOptions["ns1"][1].ResourceVersion).To(Equal("12"))
+		Expect(watchOptions["ns2"]).To(HaveLen(1))
+		lock.Unlock()
+
+		watcherFor("ns2")().Add(withRV(newPod("pod9", "ns2"), "504"))
+		watcherFor("ns1")().Add(withRV(newPod("pod10", "ns1"), "13"))
+		Eventually(getAddedPods).Should(ContainElements("pod9", "pod10"))
+		Eventually(func() []string {
+			return inf.GetIndexer().ListKeys()
+		}).Should(ConsistOf("ns1/pod1", "ns1/pod6", "ns1/pod8", "ns1/pod10",
+			"ns2/pod2", "ns2/pod3", "ns2/pod5", "ns2/pod7", "ns2/pod9"))
+	})
+
+	It("delivers the events of all the namespaces one at a time", func() {
+		informerNamespaces = []string{"ns1", "ns2", "ns3"}
+		inf := newMultiNamespaceInformer(informerNamespaces, &v1.Pod{}, 0, nil, listFunc, watchFunc)
+		var inHandler, maxInHandler, events int
+		_, err := inf.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				lock.Lock()
+				inHandler++
+				if inHandler > maxInHandler {
+					maxInHandler = inHandler
+				}
+				lock.Unlock()
+				time.Sleep(10 * time.Millisecond)
+				lock.Lock()
+				inHandler--
+				events++
+				lock.Unlock()
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		go inf.Run(stopCh)
+		Eventually(func() int {
+			lock.Lock()
+			defer lock.Unlock()
+			return events
+		}).Should(Equal(4))
+		lock.Lock()
+		defer lock.Unlock()
+		Expect(maxInHandler).To(Equal(1))
+	})
+
+	It("serves lookups and indexes across namespaces", func() {
+		informerNamespaces = []string{"ns1", "ns2", "ns3"}
+		inf := startInformer()
+
+		obj, exists, err := inf.GetIndexer().GetByKey("ns2/pod3")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exists).To(BeTrue())
+		Expect(obj.(*v1.Pod).Name).To(Equal("pod3"))
+
+		_, exists, err = inf.GetIndexer().GetByKey("other/pod1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exists).To(BeFalse())
+
+		pods, err := inf.GetIndexer().ByIndex(cache.NamespaceIndex, "ns2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pods).To(HaveLen(2))
+		Expect(inf.GetIndexer().ListIndexFuncValues(cache.NamespaceIndex)).To(ConsistOf("ns1", "ns2", "ns3"))
+	})
+})
@@ -0,0 +1,146 @@
+package factory
+
+import (
+	"fmt"
+
+	mnpapi "github.com/k8snetworkplumbingwg/multi-networkpolicy/pkg/apis/k8s.cni.cncf.io/v1beta1"
+	ocpcloudnetworkapi "github.com/openshift/api/cloudnetwork/v1"
+
+	anpapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1alpha1"
+	egressfirewallapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1"
+	egressipapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1"
+	egressqosapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1"
+	egressserviceapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressservice/v1"
+	ipamclaimsapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/ipamclaims/v1alpha1"
+
+	kapi "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	knet "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// lastAppliedConfigAnnotation holds the full object as last applied by kubectl, it is dropped from the snapshots as
+// it duplicates the object and may hold the fields the snapshots sanitize
+const lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// Snapshot holds the objects of the informer caches of a watch factory, so that the state a controller acted on can
+// be reproduced offline. The objects are sanitized: their managed fields and last applied configuration are dropped,
+// as well as the commands, arguments and environment variables of the pods' containers, which may hold credentials,
+// and the images of the nodes. The types the watch factory doesn't watch are left empty.
+type Snapshot struct {
+	Pods                         []*kapi.Pod                                `json:"pods,omitempty"`
+	Services                     []*kapi.Service                            `json:"services,omitempty"`
+	EndpointSlices               []*discovery.EndpointSlice                 `json:"endpointSlices,omitempty"`
+	NetworkPolicies              []*knet.NetworkPolicy                      `json:"networkPolicies,omitempty"`
+	Namespaces                   []*kapi.Namespace                          `json:"namespaces,omitempty"`
+	Nodes                        []*kapi.Node                               `json:"nodes,omitempty"`
+	EgressFirewalls              []*egressfirewallapi.EgressFirewall        `json:"egressFirewalls,omitempty"`
+	EgressIPs                    []*egressipapi.EgressIP                    `json:"egressIPs,omitempty"`
+	CloudPrivateIPConfigs        []*ocpcloudnetworkapi.CloudPrivateIPConfig `json:"cloudPrivateIPConfigs,omitempty"`
+	EgressQoSes                  []*egressqosapi.EgressQoS                  `json:"egressQoSes,omitempty"`
+	EgressServices               []*egressserviceapi.EgressService          `json:"egressServices,omitempty"`
+	AdminNetworkPolicies         []*anpapi.AdminNetworkPolicy               `json:"adminNetworkPolicies,omitempty"`
+	BaselineAdminNetworkPolicies []*anpapi.BaselineAdminNetworkPolicy       `json:"baselineAdminNetworkPolicies,omitempty"`
+	IPAMClaims                   []*ipamclaimsapi.IPAMClaim                 `json:"ipamClaims,omitempty"`
+	MultiNetworkPolicies         []*mnpapi.MultiNetworkPolicy               `json:"multiNetworkPolicies,omitempty"`
+}
+
+// Snapshot returns the sanitized copies of the objects in the informer caches of the watch factory
+func (wf *WatchFactory) Snapshot() (*Snapshot, error) {
+	snapshot := &Snapshot{}
+	for oType, inf := range wf.informers {
+		for _, obj := range inf.inf.GetStore().List() {
+			if err := snapshot.add(obj); err != nil {
+				return nil, fmt.Errorf("failed to snapshot the %v informer: %w", oType, err)
+			}
+		}
+	}
+	return snapshot, nil
+}
+
+func (s *Snapshot) add(obj interface{}) error {
+	switch o := obj.(type) {
+	case *kapi.Pod:
+		pod := o.DeepCopy()
+		sanitizeObjectMeta(&pod.ObjectMeta)
+		sanitizeContainers(pod.Spec.InitContainers)
+		sanitizeContainers(pod.Spec.Containers)
+		for i := range pod.Spec.EphemeralContainers {
+			container := &pod.Spec.EphemeralContainers[i]
+			container.Command, container.Args, container.Env, container.EnvFrom = nil, nil, nil, nil
+		}
+		s.Pods = append(s.Pods, pod)
+	case *kapi.Service:
+		svc := o.DeepCopy()
+		sanitizeObjectMeta(&svc.ObjectMeta)
+		s.Services = append(s.Services, svc)
+	case *discovery.EndpointSlice:
+		eps := o.DeepCopy()
+		sanitizeObjectMeta(&eps.ObjectMeta)
+		s.EndpointSlices = append(s.EndpointSlices, eps)
+	case *knet.NetworkPolicy:
+		policy := o.DeepCopy()
+		sanitizeObjectMeta(&policy.ObjectMeta)
+		s.NetworkPolicies = append(s.NetworkPolicies, policy)
+	case *kapi.Namespace:
+		ns := o.DeepCopy()
+		sanitizeObjectMeta(&ns.ObjectMeta)
+		s.Namespaces = append(s.Namespaces, ns)
+	case *kapi.Node:
+		node := o.DeepCopy()
+		sanitizeObjectMeta(&node.ObjectMeta)
+		node.Status.Images = nil
+		s.Nodes = append(s.Nodes, node)
+	case *egressfirewallapi.EgressFirewall:
+		ef := o.DeepCopy()
+		sanitizeObjectMeta(&ef.ObjectMeta)
+		s.EgressFirewalls = append(s.EgressFirewalls, ef)
+	case *egressipapi.EgressIP:
+		eip := o.DeepCopy()
+		sanitizeObjectMeta(&eip.ObjectMeta)
+		s.EgressIPs = append(s.EgressIPs, eip)
+	case *ocpcloudnetworkapi.CloudPrivateIPConfig:
+		cpipc := o.DeepCopy()
+		sanitizeObjectMeta(&cpipc.ObjectMeta)
+		s.CloudPrivateIPConfigs = append(s.CloudPrivateIPConfigs, cpipc)
+	case *egressqosapi.EgressQoS:
+		eq := o.DeepCopy()
+		sanitizeObjectMeta(&eq.ObjectMeta)
+		s.EgressQoSes = append(s.EgressQoSes, eq)
+	case *egressserviceapi.EgressService:
+		es := o.DeepCopy()
+		sanitizeObjectMeta(&es.ObjectMeta)
+		s.EgressServices = append(s.EgressServices, es)
+	case *anpapi.AdminNetworkPolicy:
+		anp := o.DeepCopy()
+		sanitizeObjectMeta(&anp.ObjectMeta)
+		s.AdminNetworkPolicies = append(s.AdminNetworkPolicies, anp)
+	case *anpapi.BaselineAdminNetworkPolicy:
+		banp := o.DeepCopy()
+		sanitizeObjectMeta(&banp.ObjectMeta)
+		s.BaselineAdminNetworkPolicies = append(s.BaselineAdminNetworkPolicies, banp)
+	case *ipamclaimsapi.IPAMClaim:
+		claim := o.DeepCopy()
+		sanitizeObjectMeta(&claim.ObjectMeta)
+		s.IPAMClaims = append(s.IPAMClaims, claim)
+	case *mnpapi.MultiNetworkPolicy:
+		policy := o.DeepCopy()
+		sanitizeObjectMeta(&policy.ObjectMeta)
+		s.MultiNetworkPolicies = append(s.MultiNetworkPolicies, policy)
+	default:
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+	return nil
+}
+
+func sanitizeObjectMeta(meta *metav1.ObjectMeta) {
+	meta.ManagedFields = nil
+	delete(meta.Annotations, lastAppliedConfigAnnotation)
+}
+
+func sanitizeContainers(containers []kapi.Container) {
+	for i := range containers {
+		container := &containers[i]
+		container.Command, container.Args, container.Env, container.EnvFrom = nil, nil, nil, nil
+	}
+}
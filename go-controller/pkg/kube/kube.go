@@ -11,6 +11,8 @@ import (
 	egressipv1 "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1"
 	egressipclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1/apis/clientset/versioned"
 	egressserviceclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressservice/v1/apis/clientset/versioned"
+	ipamclaimsapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/ipamclaims/v1alpha1"
+	ipamclaimsclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/ipamclaims/v1alpha1/apis/clientset/versioned"
 	kapi "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -35,6 +37,7 @@ type InterfaceOVN interface {
 	UpdateCloudPrivateIPConfig(cloudPrivateIPConfig *ocpcloudnetworkapi.CloudPrivateIPConfig) (*ocpcloudnetworkapi.CloudPrivateIPConfig, error)
 	DeleteCloudPrivateIPConfig(name string) error
 	UpdateEgressServiceStatus(namespace, name, host string) error
+	UpdateIPAMClaimIPs(claim *ipamclaimsapi.IPAMClaim, ips []string) error
 }
 
 // Interface represents the exported methods for dealing with getting/setting
@@ -73,6 +76,7 @@ type KubeOVN struct {
 	EgressFirewallClient egressfirewallclientset.Interface
 	CloudNetworkClient   ocpcloudnetworkclientset.Interface
 	EgressServiceClient  egressserviceclientset.Interface
+	IPAMClaimsClient     ipamclaimsclientset.Interface
 }
 
 // SetAnnotationsOnPod takes the pod object and map of key/value string pairs to set as annotations
@@ -402,3 +406,11 @@ func (k *KubeOVN) UpdateEgressServiceStatus(namespace, name, host string) error
 	_, err = k.EgressServiceClient.K8sV1().EgressServices(es.Namespace).UpdateStatus(context.TODO(), es, metav1.UpdateOptions{})
 	return err
 }
+
+// UpdateIPAMClaimIPs stores the given IPs, in CIDR notation, in the status of the IPAMClaim
+func (k *KubeOVN) UpdateIPAMClaimIPs(claim *ipamclaimsapi.IPAMClaim, ips []string) error {
+	updatedClaim := claim.DeepCopy()
+	updatedClaim.Status.IPs = ips
+	_, err := k.IPAMClaimsClient.K8sV1alpha1().IPAMClaims(claim.Namespace).UpdateStatus(context.TODO(), updatedClaim, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,33 @@
+package libovsdb
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"k8s.io/klog/v2"
+)
+
+// dryRunClient is a client that logs its transactions instead of sending them to the database. Its cache is
+// still populated from the database, so the controllers keep reconciling against the actual state.
+type dryRunClient struct {
+	client.Client
+	database string
+}
+
+func newDryRunClient(c client.Client, database string) *dryRunClient {
+	return &dryRunClient{Client: c, database: database}
+}
+
+// Transact logs the given operations and returns an empty result for each of them, as if they succeeded. The
+// inserted rows get no UUID.
+func (c *dryRunClient) Transact(ctx context.Context, ops ...ovsdb.Operation) ([]ovsdb.OperationResult, error) {
+	opsJSON, err := json.Marshal(ops)
+	if err != nil {
+		klog.Infof("Dry run: not sending %s transaction of %d operations: %+v", c.database, len(ops), ops)
+	} else {
+		klog.Infof("Dry run: not sending %s transaction of %d operations: %s", c.database, len(ops), opsJSON)
+	}
+	return make([]ovsdb.OperationResult, len(ops)), nil
+}
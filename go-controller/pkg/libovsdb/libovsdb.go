@@ -91,10 +91,14 @@ func NewSBClientWithConfig(cfg config.OvnAuthConfig, promRegistry prometheus.Reg
 	enableMetricsOption := client.WithMetricsRegistryNamespaceSubsystem(promRegistry,
 		"ovnkube", "master_libovsdb")
 
-	c, err := newClient(cfg, dbModel, stopCh, enableMetricsOption)
+	if err := registerClientMetrics(promRegistry); err != nil {
+		return nil, err
+	}
+	ovsdbClient, err := newClient(cfg, dbModel, stopCh, enableMetricsOption)
 	if err != nil {
 		return nil, err
 	}
+	c := newInstrumentedClient(ovsdbClient, dbModel.Name())
 
 	ctx, cancel := context.WithTimeout(context.Background(), types.OVSDBTimeout)
 	go func() {
@@ -132,6 +136,9 @@ func NewSBClientWithConfig(cfg config.OvnAuthConfig, promRegistry prometheus.Reg
 		return nil, err
 	}
 
+	if config.Default.DryRun {
+		return newDryRunClient(c, dbModel.Name()), nil
+	}
 	return c, nil
 }
 
@@ -150,18 +157,40 @@ func NewNBClientWithConfig(cfg config.OvnAuthConfig, promRegistry prometheus.Reg
 	enableMetricsOption := client.WithMetricsRegistryNamespaceSubsystem(promRegistry, "ovnkube",
 		"master_libovsdb")
 
-	// define client indexes for objects that are using dbIDs
+	// define client indexes for objects that are using dbIDs: the primary id index identifies a single object, the
+	// owner indexes serve the lookups of all the objects of an owner, optionally with a given name
+	ownerIndex := model.ClientIndex{Columns: []model.ColumnKey{
+		{Column: "external_ids", Key: types.OwnerControllerKey},
+		{Column: "external_ids", Key: types.OwnerTypeKey},
+	}}
+	ownerNameIndex := model.ClientIndex{Columns: []model.ColumnKey{
+		{Column: "external_ids", Key: types.OwnerControllerKey},
+		{Column: "external_ids", Key: types.OwnerTypeKey},
+		{Column: "external_ids", Key: types.ObjectNameKey},
+	}}
 	dbModel.SetIndexes(map[string][]model.ClientIndex{
-		nbdb.ACLTable:           {{Columns: []model.ColumnKey{{Column: "external_ids", Key: types.PrimaryIDKey}}}},
+		nbdb.ACLTable: {
+			{Columns: []model.ColumnKey{{Column: "external_ids", Key: types.PrimaryIDKey}}},
+			ownerIndex,
+			ownerNameIndex,
+			{Columns: []model.ColumnKey{{Column: "priority"}}},
+		},
+		nbdb.AddressSetTable:    {ownerIndex, ownerNameIndex},
+		nbdb.PortGroupTable:     {ownerIndex, ownerNameIndex},
+		nbdb.QoSTable:           {ownerIndex, ownerNameIndex},
 		nbdb.LoadBalancerTable:  {{Columns: []model.ColumnKey{{Column: "name"}}}},
 		nbdb.LogicalSwitchTable: {{Columns: []model.ColumnKey{{Column: "name"}}}},
 		nbdb.LogicalRouterTable: {{Columns: []model.ColumnKey{{Column: "name"}}}},
 	})
 
-	c, err := newClient(cfg, dbModel, stopCh, enableMetricsOption)
+	if err := registerClientMetrics(promRegistry); err != nil {
+		return nil, err
+	}
+	ovsdbClient, err := newClient(cfg, dbModel, stopCh, enableMetricsOption)
 	if err != nil {
 		return nil, err
 	}
+	c := newInstrumentedClient(ovsdbClient, dbModel.Name())
 
 	ctx, cancel := context.WithTimeout(context.Background(), types.OVSDBTimeout)
 	go func() {
@@ -175,6 +204,9 @@ func NewNBClientWithConfig(cfg config.OvnAuthConfig, promRegistry prometheus.Reg
 		return nil, err
 	}
 
+	if config.Default.DryRun {
+		return newDryRunClient(c, dbModel.Name()), nil
+	}
 	return c, nil
 }
 
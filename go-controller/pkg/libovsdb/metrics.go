@@ -0,0 +1,66 @@
+package libovsdb
+
+import (
+	"context"
+	"time"
+
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metricPendingTransactions is the number of transactions waiting for a reply,
+// including the ones blocked while the client reconnects
+var metricPendingTransactions = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "ovnkube",
+	Subsystem: "master_libovsdb",
+	Name:      "pending_transactions",
+	Help:      "The number of transactions sent to the database that did not get a reply yet"},
+	[]string{
+		"database",
+	},
+)
+
+var metricTransactionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "ovnkube",
+	Subsystem: "master_libovsdb",
+	Name:      "transaction_duration_seconds",
+	Help:      "The duration between sending a transaction to the database and getting its reply",
+	Buckets:   prometheus.ExponentialBuckets(.001, 2, 15)},
+	[]string{
+		"database",
+	},
+)
+
+// registerClientMetrics registers the client metrics with the given registry,
+// which may already have them when it is shared by the NB and SB clients
+func registerClientMetrics(promRegistry prometheus.Registerer) error {
+	for _, collector := range []prometheus.Collector{metricPendingTransactions, metricTransactionDuration} {
+		if err := promRegistry.Register(collector); err != nil {
+			if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// instrumentedClient is a client that records the metrics of its transactions
+type instrumentedClient struct {
+	client.Client
+	database string
+}
+
+func newInstrumentedClient(c client.Client, database string) *instrumentedClient {
+	return &instrumentedClient{Client: c, database: database}
+}
+
+func (c *instrumentedClient) Transact(ctx context.Context, ops ...ovsdb.Operation) ([]ovsdb.OperationResult, error) {
+	pending := metricPendingTransactions.WithLabelValues(c.database)
+	pending.Inc()
+	defer pending.Dec()
+	start := time.Now()
+	results, err := c.Client.Transact(ctx, ops...)
+	metricTransactionDuration.WithLabelValues(c.database).Observe(time.Since(start).Seconds())
+	return results, err
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	libovsdbclient "github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/libovsdb/model"
 	libovsdb "github.com/ovn-org/libovsdb/ovsdb"
 
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
@@ -35,6 +36,36 @@ func FindACLsWithPredicate(nbClient libovsdbclient.Client, p aclPredicate) ([]*n
 	return acls, err
 }
 
+// FindACLsWithDbIDs looks up the ACLs matching GetPredicate(objectIDs, p) from the cache, using the client indexes
+func FindACLsWithDbIDs(nbClient libovsdbclient.Client, objectIDs *DbObjectIDs, p aclPredicate) ([]*nbdb.ACL, error) {
+	acl := &nbdb.ACL{}
+	return findWithDbIDs[*nbdb.ACL](nbClient, acl, &acl.ExternalIDs, objectIDs, p)
+}
+
+// FindACLsWithPriority looks up the ACLs with the given priority matching the given predicate, or all of them if it
+// is nil, from the cache, using the client indexes
+func FindACLsWithPriority(nbClient libovsdbclient.Client, priority int, p aclPredicate) ([]*nbdb.ACL, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), types.OVSDBTimeout)
+	defer cancel()
+	acl := &nbdb.ACL{}
+	found := []*nbdb.ACL{}
+	err := nbClient.WhereAll(acl, model.Condition{
+		Field:    &acl.Priority,
+		Function: libovsdb.ConditionEqual,
+		Value:    priority,
+	}).List(ctx, &found)
+	if err != nil || p == nil {
+		return found, err
+	}
+	acls := make([]*nbdb.ACL, 0, len(found))
+	for _, item := range found {
+		if p(item) {
+			acls = append(acls, item)
+		}
+	}
+	return acls, nil
+}
+
 func FindACLs(nbClient libovsdbclient.Client, acls []*nbdb.ACL) ([]*nbdb.ACL, error) {
 	opModels := make([]operationModel, 0, len(acls))
 	foundACLs := make([]*nbdb.ACL, 0, len(acls))
@@ -94,13 +125,18 @@ func BuildACL(name string, direction nbdb.ACLDirection, priority int, match stri
 	return acl
 }
 
-func SetACLLogging(acl *nbdb.ACL, severity nbdb.ACLSeverity, log bool) {
+func SetACLLogging(acl *nbdb.ACL, severity nbdb.ACLSeverity, log bool, meter string) {
 	var realSeverity *string
 	if len(severity) != 0 {
 		realSeverity = &severity
 	}
+	var realMeter *string
+	if len(meter) != 0 {
+		realMeter = &meter
+	}
 	acl.Severity = realSeverity
 	acl.Log = log
+	acl.Meter = realMeter
 }
 
 // CreateOrUpdateACLsOps creates or updates the provided ACLs returning the
@@ -157,8 +193,8 @@ func CreateOrUpdateACLs(nbClient libovsdbclient.Client, acls ...*nbdb.ACL) error
 	return err
 }
 
-// UpdateACLsLoggingOps updates the log and severity on the provided ACLs and
-// returns the corresponding ops
+// UpdateACLsLoggingOps updates the log, severity and meter on the provided ACLs
+// and returns the corresponding ops
 func UpdateACLsLoggingOps(nbClient libovsdbclient.Client, ops []libovsdb.Operation, acls ...*nbdb.ACL) ([]libovsdb.Operation, error) {
 	opModels := make([]operationModel, 0, len(acls))
 	for i := range acls {
@@ -166,7 +202,7 @@ func UpdateACLsLoggingOps(nbClient libovsdbclient.Client, ops []libovsdb.Operati
 		acl := acls[i]
 		opModel := operationModel{
 			Model:          acl,
-			OnModelUpdates: []interface{}{&acl.Severity, &acl.Log},
+			OnModelUpdates: []interface{}{&acl.Severity, &acl.Log, &acl.Meter},
 			ErrNotFound:    true,
 			BulkOp:         false,
 		}
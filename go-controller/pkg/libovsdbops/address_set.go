@@ -40,6 +40,14 @@ func FindAddressSetsWithPredicate(nbClient libovsdbclient.Client, p addressSetPr
 	return found, err
 }
 
+// FindAddressSetsWithDbIDs looks up the address sets matching GetPredicate(objectIDs, p) from the cache, using the
+// client indexes
+func FindAddressSetsWithDbIDs(nbClient libovsdbclient.Client, objectIDs *DbObjectIDs,
+	p addressSetPredicate) ([]*nbdb.AddressSet, error) {
+	as := &nbdb.AddressSet{}
+	return findWithDbIDs[*nbdb.AddressSet](nbClient, as, &as.ExternalIDs, objectIDs, p)
+}
+
 // GetAddressSet looks up an address sets from the cache
 func GetAddressSet(nbClient libovsdbclient.Client, as *nbdb.AddressSet) (*nbdb.AddressSet, error) {
 	found := []*nbdb.AddressSet{}
@@ -210,12 +218,13 @@ func DeleteAddressSetsWithPredicateOps(nbClient libovsdbclient.Client, ops []lib
 }
 
 // DeleteAddressSetsWithPredicate looks up address sets from the cache based on
-// a given predicate and deletes them
+// a given predicate and deletes them, in several transactions if there are
+// many of them
 func DeleteAddressSetsWithPredicate(nbClient libovsdbclient.Client, p addressSetPredicate) error {
 	ops, err := DeleteAddressSetsWithPredicateOps(nbClient, nil, p)
 	if err != nil {
 		return nil
 	}
-	_, err = TransactAndCheck(nbClient, ops)
+	_, err = TransactAndCheckBatched(nbClient, ops)
 	return err
 }
@@ -0,0 +1,139 @@
+package libovsdbops
+
+import (
+	"fmt"
+
+	libovsdbclient "github.com/ovn-org/libovsdb/client"
+	libovsdb "github.com/ovn-org/libovsdb/ovsdb"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
+)
+
+// getStaleObjectPredicate returns a predicate matching the db objects of the given idsType owned by the given
+// controller, whose ObjectNameKey is not one of the liveOwners.
+func getStaleObjectPredicate[nbdbT hasExternalIDs](idsType *ObjectIDsType, controller string,
+	liveOwners sets.Set[string]) func(item nbdbT) bool {
+	return GetPredicate[nbdbT](NewDbObjectIDs(idsType, controller, nil), func(item nbdbT) bool {
+		return !liveOwners.Has(item.GetExternalIDs()[ObjectNameKey.String()])
+	})
+}
+
+// GarbageCollectObjects deletes the db objects created with DbObjectIDs by the given controller that belong to an
+// owner that doesn't exist anymore. liveOwners maps every ObjectIDsType to garbage collect to the set of its live
+// owners, as stored in the ObjectNameKey ExternalID of the db objects, other ObjectIDsTypes are ignored.
+// ACLs and QoSes are deleted by removing them from all the port groups and logical switches referencing them,
+// and before the address sets that they may reference.
+// An ObjectIDsType that doesn't use ObjectNameKey, or whose db table is not supported, returns an error.
+func GarbageCollectObjects(nbClient libovsdbclient.Client, controller string,
+	liveOwners map[*ObjectIDsType]sets.Set[string]) error {
+	staleACLs := []*nbdb.ACL{}
+	staleQoSes := []*nbdb.QoS{}
+	staleAddressSets := []*nbdb.AddressSet{}
+	for idsType, owners := range liveOwners {
+		if !idsType.HasKey(ObjectNameKey) {
+			return fmt.Errorf("cannot garbage collect %s objects: they are not identified by owner name",
+				idsType.ownerObjectType)
+		}
+		switch idsType.dbTable {
+		case acl:
+			acls, err := FindACLsWithPredicate(nbClient, getStaleObjectPredicate[*nbdb.ACL](idsType, controller, owners))
+			if err != nil {
+				return fmt.Errorf("failed to find stale %s ACLs: %w", idsType.ownerObjectType, err)
+			}
+			staleACLs = append(staleACLs, acls...)
+		case qos:
+			qoses, err := FindQoSesWithPredicate(nbClient, getStaleObjectPredicate[*nbdb.QoS](idsType, controller, owners))
+			if err != nil {
+				return fmt.Errorf("failed to find stale %s QoSes: %w", idsType.ownerObjectType, err)
+			}
+			staleQoSes = append(staleQoSes, qoses...)
+		case addressSet:
+			addressSets, err := FindAddressSetsWithPredicate(nbClient,
+				getStaleObjectPredicate[*nbdb.AddressSet](idsType, controller, owners))
+			if err != nil {
+				return fmt.Errorf("failed to find stale %s address sets: %w", idsType.ownerObjectType, err)
+			}
+			staleAddressSets = append(staleAddressSets, addressSets...)
+		default:
+			return fmt.Errorf("cannot garbage collect %s objects: db table is not supported", idsType.ownerObjectType)
+		}
+	}
+
+	ops, err := removeACLsFromAllReferencesOps(nbClient, nil, staleACLs...)
+	if err != nil {
+		return err
+	}
+	ops, err = deleteQoSesFromAllReferencesOps(nbClient, ops, staleQoSes...)
+	if err != nil {
+		return err
+	}
+	ops, err = DeleteAddressSetsOps(nbClient, ops, staleAddressSets...)
+	if err != nil {
+		return fmt.Errorf("failed to get ops to delete stale address sets: %w", err)
+	}
+	_, err = TransactAndCheckBatched(nbClient, ops)
+	return err
+}
+
+// removeACLsFromAllReferencesOps removes the provided ACLs from all the port groups and logical switches
+// referencing them, so that they are garbage collected by the db, and returns the corresponding ops
+func removeACLsFromAllReferencesOps(nbClient libovsdbclient.Client, ops []libovsdb.Operation,
+	acls ...*nbdb.ACL) ([]libovsdb.Operation, error) {
+	if len(acls) == 0 {
+		return ops, nil
+	}
+	aclUUIDs := sets.New[string]()
+	for _, acl := range acls {
+		aclUUIDs.Insert(acl.UUID)
+	}
+	portGroups, err := FindPortGroupsWithPredicate(nbClient, func(pg *nbdb.PortGroup) bool {
+		return aclUUIDs.HasAny(pg.ACLs...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find port groups referencing stale ACLs: %w", err)
+	}
+	for _, pg := range portGroups {
+		ops, err = DeleteACLsFromPortGroupOps(nbClient, ops, pg.Name, acls...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ops to remove stale ACLs from port group %s: %w", pg.Name, err)
+		}
+	}
+	ops, err = RemoveACLsFromLogicalSwitchesWithPredicateOps(nbClient, ops, func(sw *nbdb.LogicalSwitch) bool {
+		return aclUUIDs.HasAny(sw.ACLs...)
+	}, acls...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ops to remove stale ACLs from logical switches: %w", err)
+	}
+	return ops, nil
+}
+
+// deleteQoSesFromAllReferencesOps removes the provided QoSes from all the logical switches referencing them,
+// deletes them, and returns the corresponding ops
+func deleteQoSesFromAllReferencesOps(nbClient libovsdbclient.Client, ops []libovsdb.Operation,
+	qoses ...*nbdb.QoS) ([]libovsdb.Operation, error) {
+	if len(qoses) == 0 {
+		return ops, nil
+	}
+	qosUUIDs := sets.New[string]()
+	for _, qos := range qoses {
+		qosUUIDs.Insert(qos.UUID)
+	}
+	switches, err := FindLogicalSwitchesWithPredicate(nbClient, func(sw *nbdb.LogicalSwitch) bool {
+		return qosUUIDs.HasAny(sw.QOSRules...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find logical switches referencing stale QoSes: %w", err)
+	}
+	for _, sw := range switches {
+		ops, err = RemoveQoSesFromLogicalSwitchOps(nbClient, ops, sw.Name, qoses...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ops to remove stale QoSes from logical switch %s: %w", sw.Name, err)
+		}
+	}
+	ops, err = DeleteQoSesOps(nbClient, ops, qoses...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ops to delete stale QoSes: %w", err)
+	}
+	return ops, nil
+}
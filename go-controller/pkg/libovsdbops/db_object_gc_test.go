@@ -0,0 +1,131 @@
+package libovsdbops
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
+	libovsdbtest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing/libovsdb"
+)
+
+func TestGarbageCollectObjects(t *testing.T) {
+	const controller = "controller"
+	newACL := func(uuid string, dbIDs *DbObjectIDs) *nbdb.ACL {
+		return &nbdb.ACL{UUID: uuid, Match: uuid, ExternalIDs: dbIDs.GetExternalIDs()}
+	}
+	newAddressSet := func(uuid string, dbIDs *DbObjectIDs) *nbdb.AddressSet {
+		return &nbdb.AddressSet{UUID: uuid, Name: uuid, ExternalIDs: dbIDs.GetExternalIDs()}
+	}
+	efACLIDs := func(controller, namespace string) *DbObjectIDs {
+		return NewDbObjectIDs(ACLEgressFirewall, controller, map[ExternalIDKey]string{
+			ObjectNameKey: namespace,
+			RuleIndex:     "0",
+		})
+	}
+	nsAddrSetIDs := func(controller, namespace string) *DbObjectIDs {
+		return NewDbObjectIDs(AddressSetNamespace, controller, map[ExternalIDKey]string{
+			ObjectNameKey:         namespace,
+			AddressSetIPFamilyKey: "ipv4",
+		})
+	}
+
+	liveACL := newACL("live-acl-UUID", efACLIDs(controller, "live"))
+	staleACL := newACL("stale-acl-UUID", efACLIDs(controller, "stale"))
+	staleSwitchACL := newACL("stale-switch-acl-UUID", efACLIDs(controller, "stale"))
+	otherControllerACL := newACL("other-controller-acl-UUID", efACLIDs("other", "stale"))
+	qosIDs := func(namespace string) *DbObjectIDs {
+		return NewDbObjectIDs(QoSEgressQoS, controller, map[ExternalIDKey]string{
+			ObjectNameKey: namespace,
+			PriorityKey:   "1000",
+		})
+	}
+	liveQoS := &nbdb.QoS{UUID: "live-qos-UUID", Match: "live", ExternalIDs: qosIDs("live").GetExternalIDs()}
+	staleQoS := &nbdb.QoS{UUID: "stale-qos-UUID", Match: "stale", ExternalIDs: qosIDs("stale").GetExternalIDs()}
+	liveAddressSet := newAddressSet("live-as-UUID", nsAddrSetIDs(controller, "live"))
+	staleAddressSet := newAddressSet("stale-as-UUID", nsAddrSetIDs(controller, "stale"))
+	otherControllerAddressSet := newAddressSet("other-controller-as-UUID", nsAddrSetIDs("other", "stale"))
+	// not garbage collected, its ObjectIDsType is not given
+	otherTypeAddressSet := newAddressSet("other-type-as-UUID", NewDbObjectIDs(AddressSetEgressFirewallDNS, controller,
+		map[ExternalIDKey]string{
+			ObjectNameKey:         "stale",
+			AddressSetIPFamilyKey: "ipv4",
+		}))
+
+	nbClient, cleanup, err := libovsdbtest.NewNBTestHarness(libovsdbtest.TestSetup{
+		NBData: []libovsdbtest.TestData{
+			liveACL,
+			staleACL,
+			staleSwitchACL,
+			otherControllerACL,
+			liveQoS,
+			staleQoS,
+			&nbdb.PortGroup{
+				UUID: "pg-UUID",
+				Name: "pg",
+				ACLs: []string{liveACL.UUID, staleACL.UUID, otherControllerACL.UUID},
+			},
+			&nbdb.LogicalSwitch{
+				UUID:     "ls-UUID",
+				Name:     "ls",
+				ACLs:     []string{staleSwitchACL.UUID},
+				QOSRules: []string{liveQoS.UUID, staleQoS.UUID},
+			},
+			liveAddressSet,
+			staleAddressSet,
+			otherControllerAddressSet,
+			otherTypeAddressSet,
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to set up test harness: %v", err)
+	}
+	t.Cleanup(cleanup.Cleanup)
+
+	err = GarbageCollectObjects(nbClient, controller, map[*ObjectIDsType]sets.Set[string]{
+		ACLEgressFirewall:   sets.New("live"),
+		QoSEgressQoS:        sets.New("live"),
+		AddressSetNamespace: sets.New("live"),
+	})
+	if err != nil {
+		t.Fatalf("GarbageCollectObjects() error = %v", err)
+	}
+
+	// test server does not garbage collect ACLs, so we just expect stale ACLs to be unreferenced
+	expectedData := []libovsdbtest.TestData{
+		liveACL,
+		staleACL,
+		staleSwitchACL,
+		otherControllerACL,
+		liveQoS,
+		&nbdb.PortGroup{
+			UUID: "pg-UUID",
+			Name: "pg",
+			ACLs: []string{liveACL.UUID, otherControllerACL.UUID},
+		},
+		&nbdb.LogicalSwitch{
+			UUID:     "ls-UUID",
+			Name:     "ls",
+			QOSRules: []string{liveQoS.UUID},
+		},
+		liveAddressSet,
+		otherControllerAddressSet,
+		otherTypeAddressSet,
+	}
+	matcher := libovsdbtest.HaveData(expectedData)
+	success, err := matcher.Match(nbClient)
+	if !success {
+		t.Fatalf("didn't match expected with actual, err: %v", matcher.FailureMessage(nbClient))
+	}
+	if err != nil {
+		t.Fatalf("encountered error: %v", err)
+	}
+
+	// types that are not identified by owner name can't be garbage collected
+	err = GarbageCollectObjects(nbClient, controller, map[*ObjectIDsType]sets.Set[string]{
+		ACLMulticastCluster: sets.New[string](),
+	})
+	if err == nil {
+		t.Fatalf("expected GarbageCollectObjects() to fail for an ObjectIDsType without %s", ObjectNameKey)
+	}
+}
@@ -1,7 +1,15 @@
 package libovsdbops
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"sort"
+
+	libovsdbclient "github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/libovsdb/model"
+	"github.com/ovn-org/libovsdb/ovsdb"
 
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
 )
@@ -50,11 +58,11 @@ const (
 	// A combination of OwnerControllerKey, OwnerTypeKey, and ObjectNameKey will be used a secondary client index.
 	// While owner-related keys together with PrimaryIDKey will always be present in the ExternalIDs,
 	// ObjectNameKey may or may not be used, based on ObjectIDsType.
-	OwnerControllerKey ExternalIDKey = types.OvnK8sPrefix + "/owner-controller"
-	OwnerTypeKey       ExternalIDKey = types.OvnK8sPrefix + "/owner-type"
+	OwnerControllerKey ExternalIDKey = types.OwnerControllerKey
+	OwnerTypeKey       ExternalIDKey = types.OwnerTypeKey
 	// ObjectNameKey is a part of a secondary index, together with OwnerControllerKey and OwnerTypeKey
 	// May be used by controllers to store e.g. namespace+name of the object.
-	ObjectNameKey ExternalIDKey = types.OvnK8sPrefix + "/name"
+	ObjectNameKey ExternalIDKey = types.ObjectNameKey
 	// PrimaryIDKey will be used as a primary index, that is unique for every db object,
 	// and can be built based on the combination of all the other ids.
 	PrimaryIDKey ExternalIDKey = types.PrimaryIDKey
@@ -62,21 +70,78 @@ const (
 
 // dbIDsMap is used to make sure the same ownerType is not defined twice for the same dbObjType to avoid conflicts.
 // It is filled in newObjectIDsType when registering new ObjectIDsType
-var dbIDsMap = map[dbObjType]map[ownerType]bool{}
+var dbIDsMap = map[dbObjType]map[ownerType]*ObjectIDsType{}
+
+// reservedExternalIDKeys are set for every ObjectIDsType and can't be used as object-related keys.
+var reservedExternalIDKeys = map[ExternalIDKey]bool{
+	OwnerControllerKey: true,
+	OwnerTypeKey:       true,
+	PrimaryIDKey:       true,
+}
 
 func newObjectIDsType(dbTable dbObjType, ownerObjectType ownerType, keys []ExternalIDKey) *ObjectIDsType {
-	if dbIDsMap[dbTable][ownerObjectType] {
+	if dbIDsMap[dbTable][ownerObjectType] != nil {
 		panic(fmt.Sprintf("ObjectIDsType for params %v %v is already registered", dbTable, ownerObjectType))
 	}
-	if dbIDsMap[dbTable] == nil {
-		dbIDsMap[dbTable] = map[ownerType]bool{}
-	}
-	dbIDsMap[dbTable][ownerObjectType] = true
 	keysMap := map[ExternalIDKey]bool{}
 	for _, key := range keys {
+		if reservedExternalIDKeys[key] {
+			panic(fmt.Sprintf("ObjectIDsType for params %v %v uses reserved key %s", dbTable, ownerObjectType, key))
+		}
+		if keysMap[key] {
+			panic(fmt.Sprintf("ObjectIDsType for params %v %v uses key %s more than once", dbTable, ownerObjectType, key))
+		}
 		keysMap[key] = true
 	}
-	return &ObjectIDsType{dbTable, ownerObjectType, keys, keysMap}
+	if dbIDsMap[dbTable] == nil {
+		dbIDsMap[dbTable] = map[ownerType]*ObjectIDsType{}
+	}
+	idsType := &ObjectIDsType{dbTable, ownerObjectType, keys, keysMap}
+	dbIDsMap[dbTable][ownerObjectType] = idsType
+	return idsType
+}
+
+// ObjectIDsSchema describes the ExternalIDs of the db objects of one ObjectIDsType, so that tools can decode them.
+type ObjectIDsSchema struct {
+	Table     string `json:"table"`
+	OwnerType string `json:"ownerType"`
+	// Keys are the object-related ExternalIDs keys, in the order their values appear in the PrimaryIDKey value
+	// after the owner controller and the owner type.
+	Keys []string `json:"keys"`
+}
+
+// GetObjectIDsSchemas returns the schemas of all the registered ObjectIDsTypes, sorted by table and owner type.
+func GetObjectIDsSchemas() []ObjectIDsSchema {
+	schemas := []ObjectIDsSchema{}
+	for dbTable, idsTypes := range dbIDsMap {
+		for owner, idsType := range idsTypes {
+			keys := make([]string, 0, len(idsType.externalIDKeys))
+			for _, key := range idsType.externalIDKeys {
+				keys = append(keys, key.String())
+			}
+			schemas = append(schemas, ObjectIDsSchema{Table: dbTableNames[dbTable], OwnerType: string(owner), Keys: keys})
+		}
+	}
+	sort.Slice(schemas, func(i, j int) bool {
+		if schemas[i].Table != schemas[j].Table {
+			return schemas[i].Table < schemas[j].Table
+		}
+		return schemas[i].OwnerType < schemas[j].OwnerType
+	})
+	return schemas
+}
+
+// ObjectIDsSchemaHandler serves the schemas of all the registered ObjectIDsTypes as JSON
+func ObjectIDsSchemaHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(GetObjectIDsSchemas()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
 }
 
 // DbObjectIDs is a structure representing a set of db object ExternalIDs, used to identify
@@ -303,6 +368,48 @@ func GetPredicate[nbdbT hasExternalIDs](objectIDs *DbObjectIDs, f func(item nbdb
 	}
 }
 
+// getIndexedExternalIDs returns the ExternalIDs of the given objectIDs that are served by a client index: the primary
+// id if it can be built, otherwise the owner ids and ObjectNameKey, if set.
+func (objectIDs *DbObjectIDs) getIndexedExternalIDs() map[string]string {
+	if primaryID, err := objectIDs.getUniqueID(); err == nil {
+		return map[string]string{PrimaryIDKey.String(): primaryID}
+	}
+	externalIDs := map[string]string{
+		OwnerControllerKey.String(): objectIDs.ownerControllerName,
+		OwnerTypeKey.String():       string(objectIDs.idsType.ownerObjectType),
+	}
+	if name, ok := objectIDs.objectIDs[ObjectNameKey]; ok {
+		externalIDs[ObjectNameKey.String()] = name
+	}
+	return externalIDs
+}
+
+// findWithDbIDs looks up the db objects of the table of the given model whose ExternalIDs include the indexed ids of
+// objectIDs, using the client indexes rather than scanning the whole table, and returns the ones matching
+// GetPredicate(objectIDs, f). externalIDs must point to the ExternalIDs field of the model.
+func findWithDbIDs[nbdbT hasExternalIDs](nbClient libovsdbclient.Client, m nbdbT, externalIDs *map[string]string,
+	objectIDs *DbObjectIDs, f func(item nbdbT) bool) ([]nbdbT, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), types.OVSDBTimeout)
+	defer cancel()
+	found := []nbdbT{}
+	err := nbClient.WhereAll(m, model.Condition{
+		Field:    externalIDs,
+		Function: ovsdb.ConditionIncludes,
+		Value:    objectIDs.getIndexedExternalIDs(),
+	}).List(ctx, &found)
+	if err != nil {
+		return nil, err
+	}
+	p := GetPredicate[nbdbT](objectIDs, f)
+	result := make([]nbdbT, 0, len(found))
+	for _, item := range found {
+		if p(item) {
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
 func deepcopyMap(m map[ExternalIDKey]string) map[ExternalIDKey]string {
 	result := map[ExternalIDKey]string{}
 	for key, value := range m {
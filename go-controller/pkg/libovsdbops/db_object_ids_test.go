@@ -0,0 +1,189 @@
+package libovsdbops
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
+	libovsdbtest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing/libovsdb"
+)
+
+func TestFindWithDbIDs(t *testing.T) {
+	const controller = "controller"
+	efACLIDs := func(controller, namespace, ruleIndex string) *DbObjectIDs {
+		return NewDbObjectIDs(ACLEgressFirewall, controller, map[ExternalIDKey]string{
+			ObjectNameKey: namespace,
+			RuleIndex:     ruleIndex,
+		})
+	}
+	newACL := func(uuid string, priority int, dbIDs *DbObjectIDs) *nbdb.ACL {
+		return &nbdb.ACL{UUID: uuid, Match: uuid, Priority: priority, ExternalIDs: dbIDs.GetExternalIDs()}
+	}
+	ns1ACL := newACL("ns1-acl-UUID", 1000, efACLIDs(controller, "ns1", "0"))
+	ns1ACL2 := newACL("ns1-acl2-UUID", 999, efACLIDs(controller, "ns1", "1"))
+	ns2ACL := newACL("ns2-acl-UUID", 1000, efACLIDs(controller, "ns2", "0"))
+	otherControllerACL := newACL("other-controller-acl-UUID", 1000, efACLIDs("other", "ns1", "0"))
+	otherTypeACL := newACL("other-type-acl-UUID", 1000, NewDbObjectIDs(ACLMulticastNamespace, controller,
+		map[ExternalIDKey]string{
+			ObjectNameKey:      "ns1",
+			PolicyDirectionKey: "Ingress",
+		}))
+	nsAddrSetIDs := func(namespace string) *DbObjectIDs {
+		return NewDbObjectIDs(AddressSetNamespace, controller, map[ExternalIDKey]string{
+			ObjectNameKey:         namespace,
+			AddressSetIPFamilyKey: "ipv4",
+		})
+	}
+	ns1AddressSet := &nbdb.AddressSet{UUID: "ns1-as-UUID", Name: "ns1", ExternalIDs: nsAddrSetIDs("ns1").GetExternalIDs()}
+	ns2AddressSet := &nbdb.AddressSet{UUID: "ns2-as-UUID", Name: "ns2", ExternalIDs: nsAddrSetIDs("ns2").GetExternalIDs()}
+	// not owned by a controller, never found with ObjectIDs
+	unownedAddressSet := &nbdb.AddressSet{UUID: "unowned-as-UUID", Name: "unowned"}
+
+	nbClient, cleanup, err := libovsdbtest.NewNBTestHarness(libovsdbtest.TestSetup{
+		NBData: []libovsdbtest.TestData{
+			ns1ACL,
+			ns1ACL2,
+			ns2ACL,
+			otherControllerACL,
+			otherTypeACL,
+			ns1AddressSet,
+			ns2AddressSet,
+			unownedAddressSet,
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("test harness set up failed: %v", err)
+	}
+	t.Cleanup(cleanup.Cleanup)
+
+	checkUUIDs := func(desc string, found []string, expected ...string) {
+		t.Helper()
+		if len(found) != len(expected) {
+			t.Errorf("%s: expected %v, found %v", desc, expected, found)
+			return
+		}
+		foundSet := map[string]bool{}
+		for _, uuid := range found {
+			foundSet[uuid] = true
+		}
+		for _, uuid := range expected {
+			if !foundSet[uuid] {
+				t.Errorf("%s: expected %v, found %v", desc, expected, found)
+				return
+			}
+		}
+	}
+	// the test harness replaces the UUIDs, the ACLs are identified by their match
+	aclUUIDs := func(acls []*nbdb.ACL) []string {
+		uuids := []string{}
+		for _, acl := range acls {
+			uuids = append(uuids, acl.Match)
+		}
+		return uuids
+	}
+
+	acls, err := FindACLsWithDbIDs(nbClient, NewDbObjectIDs(ACLEgressFirewall, controller, nil), nil)
+	if err != nil {
+		t.Fatalf("failed to find ACLs by owner: %v", err)
+	}
+	checkUUIDs("owner", aclUUIDs(acls), ns1ACL.UUID, ns1ACL2.UUID, ns2ACL.UUID)
+
+	acls, err = FindACLsWithDbIDs(nbClient, NewDbObjectIDs(ACLEgressFirewall, controller,
+		map[ExternalIDKey]string{ObjectNameKey: "ns1"}), nil)
+	if err != nil {
+		t.Fatalf("failed to find ACLs by owner name: %v", err)
+	}
+	checkUUIDs("owner name", aclUUIDs(acls), ns1ACL.UUID, ns1ACL2.UUID)
+
+	// ObjectIDs that are not indexed are matched by the predicate
+	acls, err = FindACLsWithDbIDs(nbClient, NewDbObjectIDs(ACLEgressFirewall, controller,
+		map[ExternalIDKey]string{ObjectNameKey: "ns1", RuleIndex: "1"}), nil)
+	if err != nil {
+		t.Fatalf("failed to find ACLs by primary id: %v", err)
+	}
+	checkUUIDs("primary id", aclUUIDs(acls), ns1ACL2.UUID)
+
+	acls, err = FindACLsWithDbIDs(nbClient, NewDbObjectIDs(ACLEgressFirewall, controller, nil),
+		func(acl *nbdb.ACL) bool { return acl.Priority == 1000 })
+	if err != nil {
+		t.Fatalf("failed to find ACLs by owner and predicate: %v", err)
+	}
+	checkUUIDs("owner and predicate", aclUUIDs(acls), ns1ACL.UUID, ns2ACL.UUID)
+
+	acls, err = FindACLsWithPriority(nbClient, 1000, nil)
+	if err != nil {
+		t.Fatalf("failed to find ACLs by priority: %v", err)
+	}
+	checkUUIDs("priority", aclUUIDs(acls), ns1ACL.UUID, ns2ACL.UUID, otherControllerACL.UUID, otherTypeACL.UUID)
+
+	acls, err = FindACLsWithPriority(nbClient, 1000, func(acl *nbdb.ACL) bool {
+		return acl.ExternalIDs[OwnerControllerKey.String()] == "other"
+	})
+	if err != nil {
+		t.Fatalf("failed to find ACLs by priority and predicate: %v", err)
+	}
+	checkUUIDs("priority and predicate", aclUUIDs(acls), otherControllerACL.UUID)
+
+	addressSets, err := FindAddressSetsWithDbIDs(nbClient, NewDbObjectIDs(AddressSetNamespace, controller,
+		map[ExternalIDKey]string{ObjectNameKey: "ns2"}), nil)
+	if err != nil {
+		t.Fatalf("failed to find address sets by owner name: %v", err)
+	}
+	if len(addressSets) != 1 || addressSets[0].Name != ns2AddressSet.Name {
+		t.Errorf("expected address set %s, found %v", ns2AddressSet.Name, addressSets)
+	}
+
+	// a model lookup missing the name index must not fall through to the owner indexes, where the unowned address
+	// set would match its empty owner
+	addressSet, err := GetAddressSet(nbClient, &nbdb.AddressSet{Name: "unknown"})
+	if err == nil {
+		t.Errorf("expected address set lookup with unknown name to fail, found %v", addressSet)
+	}
+}
+
+func TestNewObjectIDsTypeCollisions(t *testing.T) {
+	// a table that is not used by the registered ObjectIDsTypes
+	const testTable dbObjType = 1000
+	t.Cleanup(func() { delete(dbIDsMap, testTable) })
+
+	expectPanic := func(desc string, f func()) {
+		t.Helper()
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("%s: expected panic", desc)
+			}
+		}()
+		f()
+	}
+	expectPanic("reserved key", func() {
+		newObjectIDsType(testTable, "test", []ExternalIDKey{ObjectNameKey, PrimaryIDKey})
+	})
+	expectPanic("duplicate key", func() {
+		newObjectIDsType(testTable, "test", []ExternalIDKey{ObjectNameKey, RuleIndex, ObjectNameKey})
+	})
+	// failed registrations don't register the owner type
+	newObjectIDsType(testTable, "test", []ExternalIDKey{ObjectNameKey})
+	expectPanic("duplicate owner type", func() {
+		newObjectIDsType(testTable, "test", []ExternalIDKey{RuleIndex})
+	})
+}
+
+func TestGetObjectIDsSchemas(t *testing.T) {
+	schemas := GetObjectIDsSchemas()
+	found := false
+	for i, schema := range schemas {
+		if i > 0 && (schemas[i-1].Table > schema.Table ||
+			schemas[i-1].Table == schema.Table && schemas[i-1].OwnerType >= schema.OwnerType) {
+			t.Errorf("schemas are not sorted: %v before %v", schemas[i-1], schema)
+		}
+		if schema.Table == "Address_Set" && schema.OwnerType == string(NamespaceOwnerType) {
+			found = true
+			if !reflect.DeepEqual(schema.Keys, []string{ObjectNameKey.String(), AddressSetIPFamilyKey.String()}) {
+				t.Errorf("unexpected keys of the namespace address sets: %v", schema.Keys)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("schema of the namespace address sets not found in %v", schemas)
+	}
+}
@@ -3,13 +3,24 @@ package libovsdbops
 const (
 	addressSet dbObjType = iota
 	acl
+	qos
+	meter
 )
 
+// dbTableNames are the names of the db tables of every dbObjType
+var dbTableNames = map[dbObjType]string{
+	addressSet: "Address_Set",
+	acl:        "ACL",
+	qos:        "QoS",
+	meter:      "Meter",
+}
+
 const (
 	// owner types
-	EgressFirewallDNSOwnerType ownerType = "EgressFirewallDNS"
-	EgressFirewallOwnerType    ownerType = "EgressFirewall"
-	EgressQoSOwnerType         ownerType = "EgressQoS"
+	EgressFirewallDNSOwnerType          ownerType = "EgressFirewallDNS"
+	EgressFirewallNodeSelectorOwnerType ownerType = "EgressFirewallNodeSelector"
+	EgressFirewallOwnerType             ownerType = "EgressFirewall"
+	EgressQoSOwnerType                  ownerType = "EgressQoS"
 	// NetworkPolicyOwnerType is deprecated for address sets, should only be used for sync.
 	// New owner of network policy address sets, is PodSelectorOwnerType.
 	NetworkPolicyOwnerType      ownerType = "NetworkPolicy"
@@ -23,6 +34,13 @@ const (
 	MulticastClusterOwnerType   ownerType = "MulticastCluster"
 	NetpolNodeOwnerType         ownerType = "NetpolNode"
 	NetpolNamespaceOwnerType    ownerType = "NetpolNamespace"
+	// AdminNetworkPolicyOwnerType and BaselineAdminNetworkPolicyOwnerType own the
+	// ACLs and address sets of AdminNetworkPolicies and BaselineAdminNetworkPolicies
+	AdminNetworkPolicyOwnerType         ownerType = "AdminNetworkPolicy"
+	BaselineAdminNetworkPolicyOwnerType ownerType = "BaselineAdminNetworkPolicy"
+	COPPOwnerType                       ownerType = "COPP"
+	NamespaceEgressBandwidthOwnerType   ownerType = "NamespaceEgressBandwidth"
+	LocalnetGuardOwnerType              ownerType = "LocalnetGuard"
 
 	// owner extra IDs, make sure to define only 1 ExternalIDKey for every string value
 	PriorityKey           ExternalIDKey = "priority"
@@ -34,6 +52,7 @@ const (
 	PortPolicyIndexKey    ExternalIDKey = "port-policy-index"
 	IpBlockIndexKey       ExternalIDKey = "ip-block-index"
 	RuleIndex             ExternalIDKey = "rule-index"
+	ProtocolKey           ExternalIDKey = "protocol"
 )
 
 // ObjectIDsTypes should only be created here
@@ -44,6 +63,14 @@ var AddressSetEgressFirewallDNS = newObjectIDsType(addressSet, EgressFirewallDNS
 	AddressSetIPFamilyKey,
 })
 
+// AddressSetEgressFirewallNodeSelector holds the addresses of the nodes selected by a node selector of
+// EgressFirewall rules, it is shared by all the rules with the same selector.
+var AddressSetEgressFirewallNodeSelector = newObjectIDsType(addressSet, EgressFirewallNodeSelectorOwnerType, []ExternalIDKey{
+	// node selector, as formatted by metav1.FormatLabelSelector
+	ObjectNameKey,
+	AddressSetIPFamilyKey,
+})
+
 var AddressSetHybridNodeRoute = newObjectIDsType(addressSet, HybridNodeRouteOwnerType, []ExternalIDKey{
 	// nodeName
 	ObjectNameKey,
@@ -93,6 +120,29 @@ var AddressSetEgressService = newObjectIDsType(addressSet, EgressServiceOwnerTyp
 	AddressSetIPFamilyKey,
 })
 
+// AddressSetAdminNetworkPolicy holds the IPs of the pods selected by an AdminNetworkPolicy,
+// either as its subject or as the peers of one of its rules.
+var AddressSetAdminNetworkPolicy = newObjectIDsType(addressSet, AdminNetworkPolicyOwnerType, []ExternalIDKey{
+	// policy name
+	ObjectNameKey,
+	// egress or ingress for the peers of a rule, subject for the pods the policy applies to
+	PolicyDirectionKey,
+	// gress rule index, -1 for the subject
+	GressIdxKey,
+	AddressSetIPFamilyKey,
+})
+
+// AddressSetBaselineAdminNetworkPolicy uses the same ids as AddressSetAdminNetworkPolicy.
+var AddressSetBaselineAdminNetworkPolicy = newObjectIDsType(addressSet, BaselineAdminNetworkPolicyOwnerType, []ExternalIDKey{
+	// policy name
+	ObjectNameKey,
+	// egress or ingress for the peers of a rule, subject for the pods the policy applies to
+	PolicyDirectionKey,
+	// gress rule index, -1 for the subject
+	GressIdxKey,
+	AddressSetIPFamilyKey,
+})
+
 var ACLNetpolDefault = newObjectIDsType(acl, NetpolDefaultOwnerType, []ExternalIDKey{
 	// for now there is only 1 acl of this type, but we use a name in case more types are needed in the future
 	ObjectNameKey,
@@ -158,3 +208,58 @@ var ACLEgressFirewall = newObjectIDsType(acl, EgressFirewallOwnerType, []Externa
 	// The only additional id we need is the index of the EgressFirewall.Spec.Egress rule.
 	RuleIndex,
 })
+
+// ACLLocalnetGuard defines a unique index for every ACL guarding a localnet network against its pods.
+var ACLLocalnetGuard = newObjectIDsType(acl, LocalnetGuardOwnerType, []ExternalIDKey{
+	// there are 2 possible TypeKey values: RA and DHCPv6
+	TypeKey,
+})
+
+// ACLAdminNetworkPolicy defines a unique index for every AdminNetworkPolicy rule ACL.
+var ACLAdminNetworkPolicy = newObjectIDsType(acl, AdminNetworkPolicyOwnerType, []ExternalIDKey{
+	// policy name
+	ObjectNameKey,
+	// egress or ingress
+	PolicyDirectionKey,
+	// gress rule index
+	GressIdxKey,
+})
+
+// ACLBaselineAdminNetworkPolicy defines a unique index for every BaselineAdminNetworkPolicy rule ACL.
+var ACLBaselineAdminNetworkPolicy = newObjectIDsType(acl, BaselineAdminNetworkPolicyOwnerType, []ExternalIDKey{
+	// policy name
+	ObjectNameKey,
+	// egress or ingress
+	PolicyDirectionKey,
+	// gress rule index
+	GressIdxKey,
+})
+
+// QoSEgressQoS defines a unique index for every EgressQoS rule QoS.
+var QoSEgressQoS = newObjectIDsType(qos, EgressQoSOwnerType, []ExternalIDKey{
+	// namespace
+	ObjectNameKey,
+	// rule priority is unique within the EgressQoS of the namespace
+	PriorityKey,
+})
+
+// QoSNamespaceEgressBandwidth defines a unique index for the QoS limiting the egress bandwidth of a namespace.
+var QoSNamespaceEgressBandwidth = newObjectIDsType(qos, NamespaceEgressBandwidthOwnerType, []ExternalIDKey{
+	// namespace
+	ObjectNameKey,
+})
+
+// MeterNetworkPolicy defines a unique index for the ACL logging meter of a network policy that sets its own rate
+// limit.
+var MeterNetworkPolicy = newObjectIDsType(meter, NetworkPolicyOwnerType, []ExternalIDKey{
+	// policy namespace+name
+	ObjectNameKey,
+})
+
+// MeterCOPP defines a unique index for the meter of every protocol protected by a control plane protection entry.
+var MeterCOPP = newObjectIDsType(meter, COPPOwnerType, []ExternalIDKey{
+	// COPP name
+	ObjectNameKey,
+	// OVN CoPP protocol name
+	ProtocolKey,
+})
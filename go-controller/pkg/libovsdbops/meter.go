@@ -64,3 +64,20 @@ func CreateOrUpdateMeterOps(nbClient libovsdbclient.Client, ops []ovsdb.Operatio
 	m := newModelClient(nbClient)
 	return m.CreateOrUpdateOps(ops, opModel)
 }
+
+type meterPredicate func(*nbdb.Meter) bool
+
+// DeleteMetersWithPredicateOps deletes the meters found using the predicate
+// and returns the corresponding ops
+func DeleteMetersWithPredicateOps(nbClient libovsdbclient.Client, ops []ovsdb.Operation, p meterPredicate) ([]ovsdb.Operation, error) {
+	meter := nbdb.Meter{}
+	opModel := operationModel{
+		Model:          &meter,
+		ModelPredicate: p,
+		ErrNotFound:    false,
+		BulkOp:         true,
+	}
+
+	m := newModelClient(nbClient)
+	return m.DeleteOps(ops, opModel)
+}
@@ -276,6 +276,62 @@ func copyIndexes(model model.Model) model.Model {
 	}
 }
 
+// matchesLookupIndexes returns true if the given row has the value of at least one of the indexes set in the lookup
+// model, as returned by copyIndexes. The keys of a map index that are set in the lookup model match the rows that
+// have the same value, or don't have the key if the value is empty.
+func matchesLookupIndexes(lookup, row model.Model) bool {
+	lookupValue := reflect.ValueOf(lookup).Elem()
+	rowValue := reflect.ValueOf(copyIndexes(row)).Elem()
+	for i := 0; i < lookupValue.NumField(); i++ {
+		lookupField, rowField := lookupValue.Field(i), rowValue.Field(i)
+		if lookupField.Kind() != reflect.Map {
+			if !lookupField.IsZero() && reflect.DeepEqual(lookupField.Interface(), rowField.Interface()) {
+				return true
+			}
+			continue
+		}
+		if lookupField.Len() == 0 {
+			continue
+		}
+		matches := true
+		for _, key := range lookupField.MapKeys() {
+			rowKeyValue := rowField.MapIndex(key)
+			if !rowKeyValue.IsValid() {
+				rowKeyValue = reflect.Zero(rowField.Type().Elem())
+			}
+			if !reflect.DeepEqual(lookupField.MapIndex(key).Interface(), rowKeyValue.Interface()) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// filterModels removes from the given pointer to a slice of models the ones for which keep returns false
+func filterModels(models interface{}, keep func(model.Model) bool) {
+	v := reflect.ValueOf(models)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return
+	}
+	v = v.Elem()
+	kept := reflect.MakeSlice(v.Type(), 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		item := v.Index(i)
+		m := item.Interface()
+		if item.Kind() == reflect.Struct {
+			m = item.Addr().Interface()
+		}
+		if keep(m) {
+			kept = reflect.Append(kept, item)
+		}
+	}
+	v.Set(kept)
+}
+
 func getListFromModel(model model.Model) interface{} {
 	switch t := model.(type) {
 	case *nbdb.ACL:
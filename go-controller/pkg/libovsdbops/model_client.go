@@ -461,6 +461,11 @@ func (m *modelClient) where(opModel *operationModel) error {
 	if err = m.client.Where(copyModel).List(ctx, opModel.ExistingResult); err != nil {
 		return err
 	}
+	// the cache tries the indexes in turn until one has a match, including the secondary client indexes that are
+	// not set in copyModel, filter out the rows found by these
+	filterModels(opModel.ExistingResult, func(row model.Model) bool {
+		return matchesLookupIndexes(copyModel, row)
+	})
 	if opModel.Model == nil || opModel.BulkOp {
 		return nil
 	}
@@ -21,6 +21,14 @@ func FindPortGroupsWithPredicate(nbClient libovsdbclient.Client, p portGroupPred
 	return found, err
 }
 
+// FindPortGroupsWithDbIDs looks up the port groups matching GetPredicate(objectIDs, p) from the cache, using the
+// client indexes
+func FindPortGroupsWithDbIDs(nbClient libovsdbclient.Client, objectIDs *DbObjectIDs,
+	p portGroupPredicate) ([]*nbdb.PortGroup, error) {
+	pg := &nbdb.PortGroup{}
+	return findWithDbIDs[*nbdb.PortGroup](nbClient, pg, &pg.ExternalIDs, objectIDs, p)
+}
+
 // BuildPortGroup builds a port group referencing the provided ports and ACLs
 func BuildPortGroup(hashName string, ports []*nbdb.LogicalSwitchPort, acls []*nbdb.ACL, externalIds map[string]string) *nbdb.PortGroup {
 	pg := nbdb.PortGroup{
@@ -248,15 +256,15 @@ func DeletePortGroupsOps(nbClient libovsdbclient.Client, ops []libovsdb.Operatio
 	return m.DeleteOps(ops, opModels...)
 }
 
-// DeletePortGroups deletes the provided port groups and returns the
-// corresponding ops
+// DeletePortGroups deletes the provided port groups, in several transactions
+// if there are many of them
 func DeletePortGroups(nbClient libovsdbclient.Client, names ...string) error {
 	ops, err := DeletePortGroupsOps(nbClient, nil, names...)
 	if err != nil {
 		return err
 	}
 
-	_, err = TransactAndCheck(nbClient, ops)
+	_, err = TransactAndCheckBatched(nbClient, ops)
 	return err
 }
 
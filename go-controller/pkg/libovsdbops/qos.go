@@ -22,6 +22,12 @@ func FindQoSesWithPredicate(nbClient libovsdbclient.Client, p QoSPredicate) ([]*
 	return found, err
 }
 
+// FindQoSesWithDbIDs looks up the QoSes matching GetPredicate(objectIDs, p) from the cache, using the client indexes
+func FindQoSesWithDbIDs(nbClient libovsdbclient.Client, objectIDs *DbObjectIDs, p QoSPredicate) ([]*nbdb.QoS, error) {
+	qos := &nbdb.QoS{}
+	return findWithDbIDs[*nbdb.QoS](nbClient, qos, &qos.ExternalIDs, objectIDs, p)
+}
+
 // CreateOrUpdateQoSesOps returns the ops to create or update the provided QoSes.
 func CreateOrUpdateQoSesOps(nbClient libovsdbclient.Client, ops []libovsdb.Operation, qoses ...*nbdb.QoS) ([]libovsdb.Operation, error) {
 	opModels := make([]operationModel, 0, len(qoses))
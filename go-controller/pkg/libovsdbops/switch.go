@@ -393,6 +393,33 @@ func DeleteLogicalSwitchPorts(nbClient libovsdbclient.Client, sw *nbdb.LogicalSw
 
 type logicalSwitchPortPredicate func(*nbdb.LogicalSwitchPort) bool
 
+// FindLogicalSwitchPortsOnSwitchWithPredicate looks up the logical switch ports
+// of the provided logical switch from the cache based on a given predicate
+func FindLogicalSwitchPortsOnSwitchWithPredicate(nbClient libovsdbclient.Client, sw *nbdb.LogicalSwitch, p logicalSwitchPortPredicate) ([]*nbdb.LogicalSwitchPort, error) {
+	swName := sw.Name
+	sw, err := GetLogicalSwitch(nbClient, sw)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving logical switch %s from libovsdb cache: %w", swName, err)
+	}
+
+	lsps := []*nbdb.LogicalSwitchPort{}
+	for _, port := range sw.Ports {
+		lsp := &nbdb.LogicalSwitchPort{UUID: port}
+		lsp, err = GetLogicalSwitchPort(nbClient, lsp)
+		if err != nil {
+			if errors.Is(err, libovsdbclient.ErrNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("error retrieving logical switch port with UUID %s associated with logical"+
+				" switch %s from libovsdb cache: %w", port, swName, err)
+		}
+		if p(lsp) {
+			lsps = append(lsps, lsp)
+		}
+	}
+	return lsps, nil
+}
+
 // DeleteLogicalSwitchPortsWithPredicateOps looks up logical switch ports from
 // the cache based on a given predicate and removes from them the provided
 // logical switch
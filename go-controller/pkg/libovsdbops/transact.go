@@ -2,10 +2,12 @@ package libovsdbops
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
+	"regexp"
 	"time"
 
 	"github.com/ovn-org/libovsdb/client"
@@ -56,6 +58,106 @@ func TransactAndCheck(c client.Client, ops []ovsdb.Operation) ([]ovsdb.Operation
 	return results, nil
 }
 
+const (
+	// maxBatchedTransactionOps is the maximum number of operations of a transaction sent by TransactAndCheckBatched
+	maxBatchedTransactionOps = 1000
+	// maxBatchedTransactionSize is the maximum size, in bytes of JSON encoded operations, of a transaction sent by
+	// TransactAndCheckBatched. It is well below the jsonrpc message size limits, leaving room for the message
+	// envelope.
+	maxBatchedTransactionSize = 1024 * 1024
+)
+
+// namedUUIDRegexp matches the named-uuid references in JSON encoded operations
+var namedUUIDRegexp = regexp.MustCompile(`\["named-uuid","([^"]+)"\]`)
+
+// TransactAndCheckBatched transacts the given ops like TransactAndCheck, but splits them in several transactions of
+// bounded number of operations and size, so that large sets of changes don't hit the jsonrpc message size limits.
+// The ops are transacted in order and the ones that depend on each other are kept in the same transaction: a row
+// inserted with a named-uuid is in the same transaction as all the ops referencing it, and a wait op is in the same
+// transaction as the op that follows it. A single op, or a set of dependent ops, that is over the limits is
+// transacted on its own.
+// The ops are not transacted atomically: if a transaction fails, the previous ones are not rolled back and the
+// following ones are not sent. Callers must only batch ops that can be applied partially, and retry all of them on
+// error. Returns the results of all the ops, in order.
+func TransactAndCheckBatched(c client.Client, ops []ovsdb.Operation) ([]ovsdb.OperationResult, error) {
+	batches, err := batchOps(ops, maxBatchedTransactionOps, maxBatchedTransactionSize)
+	if err != nil {
+		return nil, err
+	}
+	if len(batches) <= 1 {
+		return TransactAndCheck(c, ops)
+	}
+
+	klog.V(5).Infof("Splitting %d ops in %d transactions", len(ops), len(batches))
+	results := make([]ovsdb.OperationResult, 0, len(ops))
+	for i, batch := range batches {
+		batchResults, err := TransactAndCheck(c, batch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to transact batch %d of %d: %w", i+1, len(batches), err)
+		}
+		results = append(results, batchResults...)
+	}
+	return results, nil
+}
+
+// batchOps splits the given ops in batches of at most maxOps operations and maxSize bytes of JSON encoded
+// operations, keeping the ops that depend on each other in the same batch
+func batchOps(ops []ovsdb.Operation, maxOps, maxSize int) ([][]ovsdb.Operation, error) {
+	// find the named-uuids inserted or referenced by every op and the last op using each of them
+	sizes := make([]int, len(ops))
+	namedUUIDs := make([][]string, len(ops))
+	lastUse := map[string]int{}
+	for i := range ops {
+		encoded, err := json.Marshal(ops[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode op %+v: %v", ops[i], err)
+		}
+		sizes[i] = len(encoded)
+		if ops[i].UUIDName != "" {
+			namedUUIDs[i] = append(namedUUIDs[i], ops[i].UUIDName)
+		}
+		for _, match := range namedUUIDRegexp.FindAllSubmatch(encoded, -1) {
+			namedUUIDs[i] = append(namedUUIDs[i], string(match[1]))
+		}
+		for _, namedUUID := range namedUUIDs[i] {
+			lastUse[namedUUID] = i
+		}
+	}
+
+	var batches [][]ovsdb.Operation
+	var batch []ovsdb.Operation
+	batchSize := 0
+	for start := 0; start < len(ops); {
+		// build the smallest unit of dependent ops starting at start: it extends up to the last use of the
+		// named-uuids of its ops, and past wait ops
+		end, unitSize := start, 0
+		for i := start; i <= end; i++ {
+			for _, namedUUID := range namedUUIDs[i] {
+				if lastUse[namedUUID] > end {
+					end = lastUse[namedUUID]
+				}
+			}
+			if i == end && ops[i].Op == ovsdb.OperationWait && end+1 < len(ops) {
+				end++
+			}
+			unitSize += sizes[i]
+		}
+		unit := ops[start : end+1]
+
+		if len(batch) > 0 && (len(batch)+len(unit) > maxOps || batchSize+unitSize > maxSize) {
+			batches = append(batches, batch)
+			batch, batchSize = nil, 0
+		}
+		batch = append(batch, unit...)
+		batchSize += unitSize
+		start = end + 1
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches, nil
+}
+
 // TransactAndCheckAndSetUUIDs transacts the given ops against client and returns
 // results if no error occurred or an error otherwise. It sets the real uuids for
 // the passed models if they were inserted and have a named-uuid (as built by
@@ -0,0 +1,139 @@
+package libovsdbops
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ovn-org/libovsdb/ovsdb"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
+	libovsdbtest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing/libovsdb"
+)
+
+func TestBatchOps(t *testing.T) {
+	insert := func(namedUUID string) ovsdb.Operation {
+		return ovsdb.Operation{
+			Op:       ovsdb.OperationInsert,
+			Table:    "Logical_Switch_Port",
+			Row:      ovsdb.Row{"name": namedUUID},
+			UUIDName: namedUUID,
+		}
+	}
+	reference := func(namedUUIDs ...string) ovsdb.Operation {
+		ports := make([]interface{}, 0, len(namedUUIDs))
+		for _, namedUUID := range namedUUIDs {
+			ports = append(ports, ovsdb.UUID{GoUUID: namedUUID})
+		}
+		set, _ := ovsdb.NewOvsSet(ports)
+		return ovsdb.Operation{
+			Op:        ovsdb.OperationMutate,
+			Table:     "Logical_Switch",
+			Mutations: []ovsdb.Mutation{{Column: "ports", Mutator: ovsdb.MutateOperationInsert, Value: set}},
+		}
+	}
+	wait := ovsdb.Operation{Op: ovsdb.OperationWait, Table: "Logical_Switch_Port"}
+
+	tests := []struct {
+		desc       string
+		ops        []ovsdb.Operation
+		maxOps     int
+		maxSize    int
+		expBatches []int
+	}{
+		{
+			desc:       "no ops",
+			maxOps:     2,
+			maxSize:    1024,
+			expBatches: nil,
+		},
+		{
+			desc:       "independent ops are split by number",
+			ops:        []ovsdb.Operation{insert("u1"), insert("u2"), insert("u3"), insert("u4"), insert("u5")},
+			maxOps:     2,
+			maxSize:    1024,
+			expBatches: []int{2, 2, 1},
+		},
+		{
+			desc:       "independent ops are split by size",
+			ops:        []ovsdb.Operation{insert("u1"), insert("u2"), insert("u3")},
+			maxOps:     10,
+			maxSize:    150,
+			expBatches: []int{1, 1, 1},
+		},
+		{
+			desc:       "inserted rows are kept with the ops referencing them",
+			ops:        []ovsdb.Operation{insert("u1"), insert("u2"), insert("u3"), reference("u1", "u3"), insert("u4")},
+			maxOps:     2,
+			maxSize:    1024,
+			expBatches: []int{4, 1},
+		},
+		{
+			desc:       "wait ops are kept with the op they guard",
+			ops:        []ovsdb.Operation{insert("u1"), wait, insert("u2"), insert("u3")},
+			maxOps:     2,
+			maxSize:    1024,
+			expBatches: []int{1, 2, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			batches, err := batchOps(tt.ops, tt.maxOps, tt.maxSize)
+			if err != nil {
+				t.Fatalf("batchOps() error = %v", err)
+			}
+			var batchLens []int
+			var batchedOps []ovsdb.Operation
+			for _, batch := range batches {
+				batchLens = append(batchLens, len(batch))
+				batchedOps = append(batchedOps, batch...)
+			}
+			if fmt.Sprint(batchLens) != fmt.Sprint(tt.expBatches) {
+				t.Fatalf("expected batches of %v ops, got %v", tt.expBatches, batchLens)
+			}
+			if fmt.Sprintf("%+v", batchedOps) != fmt.Sprintf("%+v", tt.ops) {
+				t.Fatalf("expected the ops to be batched in order, got %+v", batches)
+			}
+		})
+	}
+}
+
+func TestTransactAndCheckBatched(t *testing.T) {
+	nbClient, cleanup, err := libovsdbtest.NewNBTestHarness(libovsdbtest.TestSetup{}, nil)
+	if err != nil {
+		t.Fatalf("failed to set up test harness: %v", err)
+	}
+	t.Cleanup(cleanup.Cleanup)
+
+	// every switch is created with a port referencing it by named-uuid
+	// enough switches for their ops to be split in two transactions
+	numSwitches := maxBatchedTransactionOps/2 + 1
+	var ops []ovsdb.Operation
+	var expectedData []libovsdbtest.TestData
+	for i := 0; i < numSwitches; i++ {
+		lsp := &nbdb.LogicalSwitchPort{Name: fmt.Sprintf("lsp%d", i)}
+		ls := &nbdb.LogicalSwitch{Name: fmt.Sprintf("ls%d", i)}
+		ops, err = createOrUpdateLogicalSwitchPortsOps(nbClient, ops, ls, true, lsp)
+		if err != nil {
+			t.Fatalf("failed to build ops: %v", err)
+		}
+		expectedData = append(expectedData,
+			&nbdb.LogicalSwitchPort{UUID: lsp.Name + "-UUID", Name: lsp.Name},
+			&nbdb.LogicalSwitch{UUID: ls.Name + "-UUID", Name: ls.Name, Ports: []string{lsp.Name + "-UUID"}})
+	}
+
+	results, err := TransactAndCheckBatched(nbClient, ops)
+	if err != nil {
+		t.Fatalf("TransactAndCheckBatched() error = %v", err)
+	}
+	if len(results) != len(ops) {
+		t.Fatalf("expected %d results, got %d", len(ops), len(results))
+	}
+	matcher := libovsdbtest.HaveDataIgnoringUUIDs(expectedData)
+	success, err := matcher.Match(nbClient)
+	if !success {
+		t.Fatalf("didn't match expected with actual, err: %v", matcher.FailureMessage(nbClient))
+	}
+	if err != nil {
+		t.Fatalf("encountered error: %v", err)
+	}
+}
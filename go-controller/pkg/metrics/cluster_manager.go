@@ -54,6 +54,16 @@ var metricV6AllocatedHostSubnetCount = prometheus.NewGauge(prometheus.GaugeOpts{
 	Help:      "The total number of v6 host subnets currently allocated",
 })
 
+var metricLeakedAllocations = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Subsystem: MetricOvnkubeSubsystemClusterManager,
+	Name:      "leaked_allocations",
+	Help:      "The number of owners of allocations that no longer exist found by the last audit, by allocator"},
+	[]string{
+		"allocator",
+	},
+)
+
 // RegisterClusterManagerBase registers ovnkube cluster manager base metrics with the Prometheus registry.
 // This function should only be called once.
 func RegisterClusterManagerBase() {
@@ -78,6 +88,7 @@ func RegisterClusterManagerBase() {
 			},
 			func() float64 { return 1 },
 		))
+		RegisterEventHandlerMetrics()
 	})
 }
 
@@ -88,6 +99,7 @@ func RegisterClusterManagerFunctional() {
 	prometheus.MustRegister(metricV6HostSubnetCount)
 	prometheus.MustRegister(metricV4AllocatedHostSubnetCount)
 	prometheus.MustRegister(metricV6AllocatedHostSubnetCount)
+	prometheus.MustRegister(metricLeakedAllocations)
 }
 
 func UnregisterClusterManagerFunctional() {
@@ -95,6 +107,7 @@ func UnregisterClusterManagerFunctional() {
 	prometheus.Unregister(metricV6HostSubnetCount)
 	prometheus.Unregister(metricV4AllocatedHostSubnetCount)
 	prometheus.Unregister(metricV6AllocatedHostSubnetCount)
+	prometheus.Unregister(metricLeakedAllocations)
 }
 
 // RecordSubnetUsage records the number of subnets allocated for nodes
@@ -109,3 +122,14 @@ func RecordSubnetCount(v4SubnetCount, v6SubnetCount float64) {
 	metricV4HostSubnetCount.Set(v4SubnetCount)
 	metricV6HostSubnetCount.Set(v6SubnetCount)
 }
+
+// RecordLeakedAllocations records how many owners of allocations that no longer exist the last audit of the given
+// allocator found, 0 once they were reclaimed
+func RecordLeakedAllocations(allocator string, leaked int) {
+	metricLeakedAllocations.WithLabelValues(allocator).Set(float64(leaked))
+}
+
+// DeleteLeakedAllocations removes the leaked allocations of the given allocator, once it is no longer audited
+func DeleteLeakedAllocations(allocator string) {
+	metricLeakedAllocations.DeleteLabelValues(allocator)
+}
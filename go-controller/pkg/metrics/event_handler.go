@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metricEventHandlerDuration is the time taken by a timed event handler to process an event
+var metricEventHandlerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Name:      "event_handler_duration_seconds",
+	Help:      "The duration for an event handler to process an add, update or delete event",
+	Buckets:   prometheus.ExponentialBuckets(.001, 4, 10)},
+	[]string{
+		"handler",
+		"event",
+	},
+)
+
+// metricEventHandlerPanics is the number of panics recovered from in the event handlers
+var metricEventHandlerPanics = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Name:      "event_handler_panics_total",
+	Help:      "The number of panics recovered from in the event handlers, by resource",
+},
+	[]string{
+		"resource",
+	},
+)
+
+var registerEventHandlerMetricsOnce sync.Once
+
+// RegisterEventHandlerMetrics registers the event handler metrics, shared by all the ovnkube components
+func RegisterEventHandlerMetrics() {
+	registerEventHandlerMetricsOnce.Do(func() {
+		prometheus.MustRegister(metricEventHandlerDuration)
+		prometheus.MustRegister(metricEventHandlerPanics)
+	})
+}
+
+// ObserveEventHandlerDuration records the time the given handler took to process an event
+func ObserveEventHandlerDuration(handler, event string, duration time.Duration) {
+	metricEventHandlerDuration.WithLabelValues(handler, event).Observe(duration.Seconds())
+}
+
+// IncrementEventHandlerPanics counts a panic recovered from in an event handler of the resource
+func IncrementEventHandlerPanics(resource string) {
+	metricEventHandlerPanics.WithLabelValues(resource).Inc()
+}
@@ -50,6 +50,20 @@ var metricDbTimestamp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 	},
 )
 
+// metricCacheUpdateLag is the time it takes for the e2e-timestamp written to NB DB to be delivered to the
+// libovsdb client caches. For the southbound database it includes the time northd takes to copy it.
+var metricCacheUpdateLag = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Subsystem: MetricOvnkubeSubsystemMaster,
+	Name:      "libovsdb_cache_update_lag_seconds",
+	Help: "The duration between writing the e2e-timestamp to the northbound database and the update being " +
+		"delivered to the client cache of the database",
+	Buckets: prometheus.ExponentialBuckets(.001, 2, 15)},
+	[]string{
+		"database",
+	},
+)
+
 // metricPodCreationLatency is the time between a pod being scheduled and
 // completing its logical switch port configuration.
 var metricPodCreationLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
@@ -83,6 +97,18 @@ var MetricResourceUpdateCount = prometheus.NewCounterVec(prometheus.CounterOpts{
 	},
 )
 
+// MetricResourceUpdateSuppressedCount is the number of update events of a resource
+// dropped by the watch factory because they carried no change of interest.
+var MetricResourceUpdateSuppressedCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Subsystem: MetricOvnkubeSubsystemMaster,
+	Name:      "resource_update_suppressed_total",
+	Help:      "The number of update events of a given resource dropped because only the resource version or managed fields changed"},
+	[]string{
+		"name",
+	},
+)
+
 // MetricResourceAddLatency is the time taken to complete resource update by an handler.
 // This measures the latency for all of the handlers for a given resource.
 var MetricResourceAddLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
@@ -93,14 +119,32 @@ var MetricResourceAddLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
 	Buckets:   prometheus.ExponentialBuckets(.1, 2, 15)},
 )
 
-// MetricResourceUpdateLatency is the time taken to complete resource update by an handler.
-// This measures the latency for all of the handlers for a given resource.
-var MetricResourceUpdateLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
+// MetricResourceUpdateLatency is the time taken to complete resource update by the handlers
+// of a given priority. This measures the latency from the start of processing the update
+// until all the handlers of that priority, and so all higher priority handlers, are done.
+var MetricResourceUpdateLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
 	Namespace: MetricOvnkubeNamespace,
 	Subsystem: MetricOvnkubeSubsystemMaster,
 	Name:      "resource_update_latency_seconds",
-	Help:      "The duration to process all handlers for a given resource event - update.",
+	Help:      "The duration to process the handlers of a given priority and all higher priority handlers for a given resource event - update.",
 	Buckets:   prometheus.ExponentialBuckets(.1, 2, 15)},
+	[]string{
+		"name",
+		"priority",
+	},
+)
+
+// MetricResourceEventBacklog is the number of queued events of a resource that the
+// handlers of a given priority have not processed yet.
+var MetricResourceEventBacklog = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Subsystem: MetricOvnkubeSubsystemMaster,
+	Name:      "resource_event_backlog",
+	Help:      "The number of queued events of a given resource not yet processed by the handlers of a given priority."},
+	[]string{
+		"name",
+		"priority",
+	},
 )
 
 // MetricResourceDeleteLatency is the time taken to complete resource update by an handler.
@@ -138,6 +182,14 @@ var MetricSyncServiceLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
 	Buckets:   prometheus.ExponentialBuckets(.1, 2, 15)},
 )
 
+// MetricTopologyAwareServices is the number of services load balanced with topology aware routing.
+var MetricTopologyAwareServices = prometheus.NewGauge(prometheus.GaugeOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Subsystem: MetricOvnkubeSubsystemMaster,
+	Name:      "topology_aware_services",
+	Help:      "The number of services whose load balancers honor the zone hints of their endpoints",
+})
+
 var MetricMasterReadyDuration = prometheus.NewGauge(prometheus.GaugeOpts{
 	Namespace: MetricOvnkubeNamespace,
 	Subsystem: MetricOvnkubeSubsystemMaster,
@@ -201,6 +253,41 @@ var metricEgressIPRebalanceCount = prometheus.NewCounter(prometheus.CounterOpts{
 	Help:      "The total number of times assigned egress IP(s) needed to be moved to a different node"},
 )
 
+var metricDuplicateLogicalSwitchPortsCount = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Subsystem: MetricOvnkubeSubsystemMaster,
+	Name:      "duplicate_logical_switch_ports_total",
+	Help:      "The total number of detected logical switch ports claiming the address of another pod's logical switch port"},
+)
+
+var metricDuplicateLogicalSwitchPortsDeletedCount = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Subsystem: MetricOvnkubeSubsystemMaster,
+	Name:      "duplicate_logical_switch_ports_deleted_total",
+	Help:      "The total number of duplicate logical switch ports that were deleted"},
+)
+
+var metricNBDBDrift = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Subsystem: MetricOvnkubeSubsystemMaster,
+	Name:      "nbdb_drift",
+	Help: "The number of OVN northbound database rows found missing, stale or modified by the last audit " +
+		"against the expected state, by check"},
+	[]string{
+		"check",
+		"kind",
+	},
+)
+
+var metricEgressIPFailoverDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Subsystem: MetricOvnkubeSubsystemMaster,
+	Name:      "egress_ips_failover_duration_seconds",
+	Help: "The time from the last successful health check of an unreachable egress node until its egress IPs " +
+		"were reassigned",
+	Buckets: prometheus.ExponentialBuckets(.1, 2, 15),
+})
+
 var metricNetpolEventLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
 	Namespace: MetricOvnkubeNamespace,
 	Subsystem: MetricOvnkubeSubsystemMaster,
@@ -378,6 +465,7 @@ func RegisterMasterBase() {
 		},
 		func() float64 { return 1 },
 	))
+	RegisterEventHandlerMetrics()
 }
 
 // RegisterMasterPerformance registers metrics that help us understand ovnkube-master performance. Call once after LE is won.
@@ -385,12 +473,15 @@ func RegisterMasterPerformance(nbClient libovsdbclient.Client) {
 	// No need to unregister because process exits when leadership is lost.
 	prometheus.MustRegister(metricPodCreationLatency)
 	prometheus.MustRegister(MetricResourceUpdateCount)
+	prometheus.MustRegister(MetricResourceUpdateSuppressedCount)
 	prometheus.MustRegister(MetricResourceAddLatency)
 	prometheus.MustRegister(MetricResourceUpdateLatency)
+	prometheus.MustRegister(MetricResourceEventBacklog)
 	prometheus.MustRegister(MetricResourceDeleteLatency)
 	prometheus.MustRegister(MetricRequeueServiceCount)
 	prometheus.MustRegister(MetricSyncServiceCount)
 	prometheus.MustRegister(MetricSyncServiceLatency)
+	prometheus.MustRegister(MetricTopologyAwareServices)
 	prometheus.MustRegister(metricOvnCliLatency)
 	// This is set to not create circular import between metrics and util package
 	util.MetricOvnCliLatency = metricOvnCliLatency
@@ -426,6 +517,10 @@ func RegisterMasterFunctional() {
 	}
 	prometheus.MustRegister(metricEgressIPNodeUnreacheableCount)
 	prometheus.MustRegister(metricEgressIPRebalanceCount)
+	prometheus.MustRegister(metricEgressIPFailoverDuration)
+	prometheus.MustRegister(metricDuplicateLogicalSwitchPortsCount)
+	prometheus.MustRegister(metricDuplicateLogicalSwitchPortsDeletedCount)
+	prometheus.MustRegister(metricNBDBDrift)
 	prometheus.MustRegister(metricEgressFirewallRuleCount)
 	prometheus.MustRegister(metricEgressFirewallCount)
 	prometheus.MustRegister(metricEgressRoutingViaHost)
@@ -434,6 +529,11 @@ func RegisterMasterFunctional() {
 			panic(err)
 		}
 	}
+	if err := prometheus.Register(MetricResourceRetryEntries); err != nil {
+		if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+			panic(err)
+		}
+	}
 }
 
 // RunTimestamp adds a goroutine that registers and updates timestamp metrics.
@@ -460,6 +560,13 @@ func RunTimestamp(stopChan <-chan struct{}, sbClient, nbClient libovsdbclient.Cl
 	// (OVN_Northbound|OVN_Southbound) set as a label. Updated every 30s.
 	prometheus.MustRegister(metricDbTimestamp)
 
+	// Metric named libovsdb_cache_update_lag_seconds measures how long every e2e-timestamp written to the NB DB
+	// takes to reach the NB and SB DB client caches.
+	prometheus.MustRegister(metricCacheUpdateLag)
+	lagProbe := &cacheUpdateLagProbe{}
+	nbClient.Cache().AddEventHandler(lagProbe.eventHandler(nbClient.Schema().Name))
+	sbClient.Cache().AddEventHandler(lagProbe.eventHandler(sbClient.Schema().Name))
+
 	go func() {
 		ticker := time.NewTicker(30 * time.Second)
 		defer ticker.Stop()
@@ -467,6 +574,7 @@ func RunTimestamp(stopChan <-chan struct{}, sbClient, nbClient libovsdbclient.Cl
 			select {
 			case <-ticker.C:
 				currentTime := time.Now().Unix()
+				lagProbe.send(currentTime)
 				if setNbE2eTimestamp(nbClient, currentTime) {
 					metricNbE2eTimestamp.Set(float64(currentTime))
 				} else {
@@ -482,6 +590,51 @@ func RunTimestamp(stopChan <-chan struct{}, sbClient, nbClient libovsdbclient.Cl
 	}()
 }
 
+// cacheUpdateLagProbe records the lag between writing an e2e-timestamp to the NB DB and the client cache of every
+// database getting it
+type cacheUpdateLagProbe struct {
+	sync.Mutex
+	timestamp string
+	sentAt    time.Time
+	// observed holds the databases whose cache already got the current timestamp
+	observed map[string]bool
+}
+
+func (p *cacheUpdateLagProbe) send(timestamp int64) {
+	p.Lock()
+	defer p.Unlock()
+	p.timestamp = fmt.Sprintf("%d", timestamp)
+	p.sentAt = time.Now()
+	p.observed = map[string]bool{}
+}
+
+func (p *cacheUpdateLagProbe) observe(dbName string, options map[string]string) {
+	p.Lock()
+	defer p.Unlock()
+	if p.timestamp == "" || p.observed[dbName] || options[globalOptionsTimestampField] != p.timestamp {
+		return
+	}
+	p.observed[dbName] = true
+	metricCacheUpdateLag.WithLabelValues(dbName).Observe(time.Since(p.sentAt).Seconds())
+}
+
+func (p *cacheUpdateLagProbe) eventHandler(dbName string) *cache.EventHandlerFuncs {
+	handler := func(table string, m model.Model) {
+		switch row := m.(type) {
+		case *nbdb.NBGlobal:
+			p.observe(dbName, row.Options)
+		case *sbdb.SBGlobal:
+			p.observe(dbName, row.Options)
+		}
+	}
+	return &cache.EventHandlerFuncs{
+		AddFunc: handler,
+		UpdateFunc: func(table string, _, new model.Model) {
+			handler(table, new)
+		},
+	}
+}
+
 // RecordPodCreated extracts the scheduled timestamp and records how long it took
 // us to notice this and set up the pod's scheduling.
 func RecordPodCreated(pod *kapi.Pod, netInfo util.NetInfo) {
@@ -531,6 +684,25 @@ func RecordEgressIPRebalance(count int) {
 	metricEgressIPRebalanceCount.Add(float64(count))
 }
 
+// RecordEgressIPFailover records how long it took to move the egress IPs away from an unreachable egress node.
+func RecordEgressIPFailover(duration time.Duration) {
+	metricEgressIPFailoverDuration.Observe(duration.Seconds())
+}
+
+// RecordDuplicateLogicalSwitchPorts records how many duplicate logical switch ports were detected and how many of
+// them were deleted.
+func RecordDuplicateLogicalSwitchPorts(detected, deleted int) {
+	metricDuplicateLogicalSwitchPortsCount.Add(float64(detected))
+	metricDuplicateLogicalSwitchPortsDeletedCount.Add(float64(deleted))
+}
+
+// RecordNBDBDrift records how many rows the last audit of the given check found missing, stale or modified.
+func RecordNBDBDrift(check string, missing, stale, modified int) {
+	metricNBDBDrift.WithLabelValues(check, "missing").Set(float64(missing))
+	metricNBDBDrift.WithLabelValues(check, "stale").Set(float64(stale))
+	metricNBDBDrift.WithLabelValues(check, "modified").Set(float64(modified))
+}
+
 func RecordNetpolEvent(eventName string, duration time.Duration) {
 	metricNetpolEventLatency.WithLabelValues(eventName).Observe(duration.Seconds())
 }
@@ -11,6 +11,8 @@ import (
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/metrics/mocks"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
 	libovsdbtest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing/libovsdb"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	fakeclientgo "k8s.io/client-go/kubernetes/fake"
@@ -234,3 +236,51 @@ var _ = ginkgo.Describe("Config Duration Operations", func() {
 		})
 	})
 })
+
+var _ = ginkgo.Describe("libovsdb cache update lag", func() {
+	var (
+		nbClient client.Client
+		cleanup  *libovsdbtest.Cleanup
+	)
+
+	ginkgo.BeforeEach(func() {
+		metricCacheUpdateLag.Reset()
+		_, nbClient, cleanup = setupOvn(libovsdbtest.TestSetup{
+			NBData: []libovsdbtest.TestData{&nbdb.NBGlobal{UUID: "lag-uuid"}}})
+	})
+
+	ginkgo.AfterEach(func() {
+		cleanup.Cleanup()
+	})
+
+	getSampleCount := func(dbName string) uint64 {
+		metric := &dto.Metric{}
+		err := metricCacheUpdateLag.WithLabelValues(dbName).(prometheus.Histogram).Write(metric)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		return metric.GetHistogram().GetSampleCount()
+	}
+
+	ginkgo.It("records the lag of every e2e timestamp once", func() {
+		dbName := nbClient.Schema().Name
+		probe := &cacheUpdateLagProbe{}
+		nbClient.Cache().AddEventHandler(probe.eventHandler(dbName))
+
+		// timestamps that were not sent by the probe are ignored
+		gomega.Expect(setNbE2eTimestamp(nbClient, 1)).To(gomega.BeTrue())
+		gomega.Consistently(func() uint64 { return getSampleCount(dbName) }, 100*time.Millisecond).Should(gomega.BeZero())
+
+		probe.send(2)
+		gomega.Expect(setNbE2eTimestamp(nbClient, 2)).To(gomega.BeTrue())
+		gomega.Eventually(func() uint64 { return getSampleCount(dbName) }).Should(gomega.BeEquivalentTo(1))
+
+		// an unrelated update of the row doesn't record the lag again
+		nbGlobal, err := libovsdbops.GetNBGlobal(nbClient, &nbdb.NBGlobal{})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		nbGlobal.HvCfg = 1
+		ops, err := nbClient.Where(nbGlobal).Update(nbGlobal, &nbGlobal.HvCfg)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		_, err = libovsdbops.TransactAndCheck(nbClient, ops)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Consistently(func() uint64 { return getSampleCount(dbName) }, 100*time.Millisecond).Should(gomega.BeEquivalentTo(1))
+	})
+})
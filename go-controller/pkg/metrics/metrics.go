@@ -3,10 +3,12 @@ package metrics
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/pprof"
+	"os"
 	"regexp"
 	"strconv"
 	"strings"
@@ -19,6 +21,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	utilwait "k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
@@ -81,6 +84,17 @@ var MetricResourceRetryFailuresCount = prometheus.NewCounter(prometheus.CounterO
 	Help:      "The total number of times processing a Kubernetes resource reached the maximum retry limit and was no longer processed",
 })
 
+// MetricResourceRetryEntries is the number of Kubernetes resources waiting to be retried, per resource type.
+// Like MetricResourceRetryFailuresCount, it is applicable for both master and node.
+var MetricResourceRetryEntries = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Name:      "resource_retry_entries",
+	Help:      "The number of Kubernetes resources waiting to be retried"},
+	[]string{
+		"resource_type",
+	},
+)
+
 // OVN/OVS components, namely ovn-northd, ovn-controller, and ovs-vswitchd provide various
 // metrics through the 'coverage/show' command. The following data structure holds all the
 // metrics we are interested in that output for a given component. We generalize capturing
@@ -398,15 +412,33 @@ func checkPodRunsOnGivenNode(clientset kubernetes.Interface, labels []string, k8
 
 // using the cyrpto/tls module's GetCertificate() callback function helps in picking up
 // the latest certificate (due to cert rotation on cert expiry)
-func getTLSServer(addr, certFile, privKeyFile string, handler http.Handler) *http.Server {
+func getTLSServer(addr, certFile, privKeyFile, clientCAFile string, allowedClients sets.String,
+	handler http.Handler) *http.Server {
+	getCertificate := func(info *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		cert, err := tls.LoadX509KeyPair(certFile, privKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error generating x509 certs for metrics TLS endpoint: %v", err)
+		}
+		return &cert, nil
+	}
 	tlsConfig := &tls.Config{
-		GetCertificate: func(info *tls.ClientHelloInfo) (*tls.Certificate, error) {
-			cert, err := tls.LoadX509KeyPair(certFile, privKeyFile)
+		GetCertificate: getCertificate,
+	}
+	if clientCAFile != "" {
+		// Like the server certificate, the client CA bundle is read on every handshake
+		// so that rotated CAs are picked up without a restart
+		tlsConfig.GetConfigForClient = func(info *tls.ClientHelloInfo) (*tls.Config, error) {
+			clientCAs, err := loadClientCAs(clientCAFile)
 			if err != nil {
-				return nil, fmt.Errorf("error generating x509 certs for metrics TLS endpoint: %v", err)
+				return nil, err
 			}
-			return &cert, nil
-		},
+			return &tls.Config{
+				GetCertificate: getCertificate,
+				ClientAuth:     tls.RequireAndVerifyClientCert,
+				ClientCAs:      clientCAs,
+			}, nil
+		}
+		handler = authorizeClients(allowedClients, handler)
 	}
 	server := &http.Server{
 		Addr:      addr,
@@ -416,6 +448,38 @@ func getTLSServer(addr, certFile, privKeyFile string, handler http.Handler) *htt
 	return server
 }
 
+// loadClientCAs returns the pool of the CA certificates found in the PEM file clientCAFile
+func loadClientCAs(clientCAFile string) (*x509.CertPool, error) {
+	pemCerts, err := os.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the client CA bundle of the metrics TLS endpoint: %v", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(pemCerts) {
+		return nil, fmt.Errorf("no valid certificate found in the client CA bundle %s of the metrics TLS endpoint",
+			clientCAFile)
+	}
+	return clientCAs, nil
+}
+
+// authorizeClients wraps an http Handler to only serve the clients whose verified certificate
+// common name is in allowedClients. An empty allowedClients authorizes any verified client.
+func authorizeClients(allowedClients sets.String, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 || len(req.TLS.VerifiedChains[0]) == 0 {
+			writePlainText(http.StatusUnauthorized, "a verified client certificate is required", w)
+			return
+		}
+		name := req.TLS.VerifiedChains[0][0].Subject.CommonName
+		if allowedClients.Len() > 0 && !allowedClients.Has(name) {
+			klog.Warningf("Metrics server denied a request from client %q to %s", name, req.URL.Path)
+			writePlainText(http.StatusForbidden, fmt.Sprintf("client %q is not allowed", name), w)
+			return
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
+
 // stringFlagSetterFunc is a func used for setting string type flag.
 type stringFlagSetterFunc func(string) (string, error)
 
@@ -461,10 +525,53 @@ func writePlainText(statusCode int, text string, w http.ResponseWriter) {
 	fmt.Fprintln(w, text)
 }
 
+var (
+	debugHandlersLock sync.RWMutex
+	debugHandlers     = map[string]http.Handler{}
+)
+
+// RegisterDebugHandler serves handler at /debug/<path> on the metrics server when pprof is enabled,
+// replacing any previous handler of path. It can be called before or after the server is started.
+func RegisterDebugHandler(path string, handler http.Handler) {
+	debugHandlersLock.Lock()
+	defer debugHandlersLock.Unlock()
+	debugHandlers[path] = handler
+}
+
+// UnregisterDebugHandler stops serving the handler registered at /debug/<path>, typically when
+// the controller that registered it is stopped
+func UnregisterDebugHandler(path string) {
+	debugHandlersLock.Lock()
+	defer debugHandlersLock.Unlock()
+	delete(debugHandlers, path)
+}
+
+// debugHandler serves the handlers registered with RegisterDebugHandler. Some of them act on the
+// controllers on POST, e.g. to force a resync, so unless the clients are authenticated with a
+// certificate only GET requests are let through.
+func debugHandler(authenticated bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		debugHandlersLock.RLock()
+		handler, ok := debugHandlers[strings.TrimPrefix(req.URL.Path, "/debug/")]
+		debugHandlersLock.RUnlock()
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		if !authenticated && req.Method != http.MethodGet && req.Method != http.MethodHead {
+			writePlainText(http.StatusForbidden,
+				fmt.Sprintf("%s requests require client certificate authentication", req.Method), w)
+			return
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
+
 // StartMetricsServer runs the prometheus listener so that OVN K8s metrics can be collected
-// It puts the endpoint behind TLS if certFile and keyFile are defined.
-func StartMetricsServer(bindAddress string, enablePprof bool, certFile string, keyFile string,
-	stopChan <-chan struct{}, wg *sync.WaitGroup) {
+// It puts the endpoint behind TLS if certFile and keyFile are defined, and also requires
+// and authorizes client certificates if clientCAFile is defined.
+func StartMetricsServer(bindAddress string, enablePprof bool, certFile, keyFile, clientCAFile string,
+	allowedClients sets.String, stopChan <-chan struct{}, wg *sync.WaitGroup) {
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.Handler())
 
@@ -477,6 +584,8 @@ func StartMetricsServer(bindAddress string, enablePprof bool, certFile string, k
 
 		// Allow changes to log level at runtime
 		mux.HandleFunc("/debug/flags/v", stringFlagPutHandler(klogSetter))
+		// Serve the debug handlers registered by the controllers
+		mux.Handle("/debug/", debugHandler(certFile != "" && keyFile != "" && clientCAFile != ""))
 	}
 	wg.Add(1)
 
@@ -487,7 +596,7 @@ func StartMetricsServer(bindAddress string, enablePprof bool, certFile string, k
 			klog.Infof("Starting metrics server to serve at address %q", bindAddress)
 			var err error
 			if certFile != "" && keyFile != "" {
-				server = getTLSServer(bindAddress, certFile, keyFile, mux)
+				server = getTLSServer(bindAddress, certFile, keyFile, clientCAFile, allowedClients, mux)
 				err = server.ListenAndServeTLS("", "")
 			} else {
 				server = &http.Server{
@@ -515,7 +624,8 @@ func StartMetricsServer(bindAddress string, enablePprof bool, certFile string, k
 var ovnRegistry = prometheus.NewRegistry()
 
 // StartOVNMetricsServer runs the prometheus listener so that OVN metrics can be collected
-func StartOVNMetricsServer(bindAddress, certFile, keyFile string,
+// The endpoint is secured the same way as the one of StartMetricsServer.
+func StartOVNMetricsServer(bindAddress, certFile, keyFile, clientCAFile string, allowedClients sets.String,
 	stopChan <-chan struct{}, wg *sync.WaitGroup) {
 	handler := promhttp.InstrumentMetricHandler(ovnRegistry,
 		promhttp.HandlerFor(ovnRegistry, promhttp.HandlerOpts{}))
@@ -531,7 +641,7 @@ func StartOVNMetricsServer(bindAddress, certFile, keyFile string,
 			klog.Infof("Starting OVN related metrics server to serve at address %q", bindAddress)
 			var err error
 			if certFile != "" && keyFile != "" {
-				server = getTLSServer(bindAddress, certFile, keyFile, mux)
+				server = getTLSServer(bindAddress, certFile, keyFile, clientCAFile, allowedClients, mux)
 				err = server.ListenAndServeTLS("", "")
 			} else {
 				server = &http.Server{
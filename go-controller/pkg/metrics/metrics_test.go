@@ -1,8 +1,15 @@
 package metrics
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 func Test_parseStopwatchShowOutput(t *testing.T) {
@@ -133,3 +140,104 @@ Statistics for 'ovn-northd-loop'
 		})
 	}
 }
+
+func Test_authorizeClients(t *testing.T) {
+	clientCert := func(name string) *tls.ConnectionState {
+		return &tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: name}}}},
+		}
+	}
+	tests := []struct {
+		name           string
+		allowedClients sets.String
+		tlsState       *tls.ConnectionState
+		wantStatus     int
+	}{
+		{
+			name:           "should refuse a client without a verified certificate",
+			allowedClients: sets.NewString(),
+			wantStatus:     http.StatusUnauthorized,
+		},
+		{
+			name:           "should serve any verified client when no client is listed",
+			allowedClients: sets.NewString(),
+			tlsState:       clientCert("prometheus"),
+			wantStatus:     http.StatusOK,
+		},
+		{
+			name:           "should serve a listed client",
+			allowedClients: sets.NewString("prometheus"),
+			tlsState:       clientCert("prometheus"),
+			wantStatus:     http.StatusOK,
+		},
+		{
+			name:           "should refuse a client that is not listed",
+			allowedClients: sets.NewString("prometheus"),
+			tlsState:       clientCert("intruder"),
+			wantStatus:     http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := authorizeClients(tt.allowedClients, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			req.TLS = tt.tlsState
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("authorizeClients() status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_debugHandler(t *testing.T) {
+	RegisterDebugHandler("test", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer UnregisterDebugHandler("test")
+	tests := []struct {
+		name          string
+		authenticated bool
+		method        string
+		target        string
+		wantStatus    int
+	}{
+		{
+			name:       "should serve a GET to a registered handler",
+			method:     http.MethodGet,
+			target:     "/debug/test",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "should not find an unregistered handler",
+			method:     http.MethodGet,
+			target:     "/debug/other",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "should refuse a POST from a client that is not authenticated",
+			method:     http.MethodPost,
+			target:     "/debug/test",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:          "should serve a POST from an authenticated client",
+			authenticated: true,
+			method:        http.MethodPost,
+			target:        "/debug/test",
+			wantStatus:    http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			debugHandler(tt.authenticated).ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("debugHandler() status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
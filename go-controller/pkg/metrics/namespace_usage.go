@@ -0,0 +1,229 @@
+package metrics
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/klog/v2"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+)
+
+// namespaceUsageOther is the namespace the traffic is reported under once the maximum number of namespaces is
+// reached, it is not a valid namespace name
+const namespaceUsageOther = "_other"
+
+// The traffic of the pods of the node per namespace, for chargeback. The direction is seen from the pods: the
+// egress traffic is the traffic received by OVS from the pods.
+var metricNamespaceUsageBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Subsystem: MetricOvnkubeSubsystemNode,
+	Name:      "namespace_bytes_total",
+	Help:      "The number of bytes sent (egress) or received (ingress) by the pods of the node in a namespace"},
+	[]string{"namespace", "direction"},
+)
+
+var metricNamespaceUsagePackets = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Subsystem: MetricOvnkubeSubsystemNode,
+	Name:      "namespace_packets_total",
+	Help:      "The number of packets sent (egress) or received (ingress) by the pods of the node in a namespace"},
+	[]string{"namespace", "direction"},
+)
+
+// interfaceStats holds the statistics of an OVS interface the usage is accounted for
+type interfaceStats struct {
+	namespace                              string
+	rxBytes, txBytes, rxPackets, txPackets float64
+}
+
+// namespaceUsageCollector accounts the statistics of the OVS interfaces of the pods to their namespace. The counters
+// of an interface are accounted as deltas between two collections, so that the counters of a namespace don't
+// decrease when a pod is deleted.
+type namespaceUsageCollector struct {
+	ovsVsctl      ovsClient
+	maxNamespaces int
+	// interfaces holds the statistics of the last collection, by interface UUID
+	interfaces map[string]interfaceStats
+	// namespaces holds the namespaces reported with their own label, and their number of interfaces
+	namespaces map[string]int
+}
+
+var startNamespaceUsageOnce sync.Once
+
+// StartNamespaceUsageCollector registers the per namespace traffic metrics and collects them from the statistics of
+// the OVS interfaces of the pods of the node every interval, until stopChan is closed
+func StartNamespaceUsageCollector(interval time.Duration, maxNamespaces int, stopChan <-chan struct{}) {
+	startNamespaceUsageOnce.Do(func() {
+		prometheus.MustRegister(metricNamespaceUsageBytes)
+		prometheus.MustRegister(metricNamespaceUsagePackets)
+		c := newNamespaceUsageCollector(util.RunOVSVsctl, maxNamespaces)
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					if err := c.collect(); err != nil {
+						klog.Errorf("Updating namespace usage metrics failed: %v", err)
+					}
+				case <-stopChan:
+					return
+				}
+			}
+		}()
+	})
+}
+
+func newNamespaceUsageCollector(ovsVsctl ovsClient, maxNamespaces int) *namespaceUsageCollector {
+	return &namespaceUsageCollector{
+		ovsVsctl:      ovsVsctl,
+		maxNamespaces: maxNamespaces,
+		interfaces:    map[string]interfaceStats{},
+		namespaces:    map[string]int{},
+	}
+}
+
+// collect lists the OVS interfaces of the pods and adds the traffic since the last collection to the counters of
+// their namespace. The namespaces left without interfaces are removed from the metrics, freeing their label.
+func (c *namespaceUsageCollector) collect() error {
+	stdout, stderr, err := c.ovsVsctl("--no-headings", "--data=bare", "--format=csv",
+		"--columns=_uuid,external_ids,statistics", "list", "Interface")
+	if err != nil {
+		return fmt.Errorf("failed to get output for ovs-vsctl list Interface stderr(%s): %v", stderr, err)
+	}
+	if stderr != "" {
+		return fmt.Errorf("failed to get OVS interface statistics due to stderr: %s", stderr)
+	}
+	records, err := csv.NewReader(strings.NewReader(stdout)).ReadAll()
+	if err != nil {
+		return fmt.Errorf("unexpected data format received while trying to get OVS interface statistics: %v", err)
+	}
+
+	current := make(map[string]interfaceStats, len(records))
+	for _, record := range records {
+		if len(record) != 3 {
+			return fmt.Errorf("unexpected data format received while trying to get OVS interface statistics: %v", record)
+		}
+		namespace := podInterfaceNamespace(parseOvsMap(record[1]))
+		if namespace == "" {
+			// not the interface of a pod
+			continue
+		}
+		stats := interfaceStats{}
+		for key, value := range parseOvsMap(record[2]) {
+			var counter *float64
+			switch key {
+			case "rx_bytes":
+				counter = &stats.rxBytes
+			case "tx_bytes":
+				counter = &stats.txBytes
+			case "rx_packets":
+				counter = &stats.rxPackets
+			case "tx_packets":
+				counter = &stats.txPackets
+			default:
+				continue
+			}
+			if *counter, err = strconv.ParseFloat(value, 64); err != nil {
+				return fmt.Errorf("expected statistic %s=%q to contain an integer: %v", key, value, err)
+			}
+		}
+		// an interface keeps the namespace label it was first accounted to
+		if last, ok := c.interfaces[record[0]]; ok {
+			stats.namespace = last.namespace
+		} else {
+			stats.namespace = c.accountNamespace(namespace)
+		}
+		current[record[0]] = stats
+	}
+
+	for uuid, stats := range current {
+		// a new interface starts from zero
+		last := c.interfaces[uuid]
+		addUsage(stats.namespace, "egress", delta(stats.rxBytes, last.rxBytes), delta(stats.rxPackets, last.rxPackets))
+		addUsage(stats.namespace, "ingress", delta(stats.txBytes, last.txBytes), delta(stats.txPackets, last.txPackets))
+	}
+	for uuid, last := range c.interfaces {
+		if _, ok := current[uuid]; !ok {
+			c.releaseNamespace(last.namespace)
+		}
+	}
+	c.interfaces = current
+	return nil
+}
+
+// accountNamespace returns the namespace label a new interface of the given namespace is accounted to
+func (c *namespaceUsageCollector) accountNamespace(namespace string) string {
+	if _, ok := c.namespaces[namespace]; !ok {
+		reported := len(c.namespaces)
+		if _, ok := c.namespaces[namespaceUsageOther]; ok {
+			reported--
+		}
+		if reported >= c.maxNamespaces {
+			namespace = namespaceUsageOther
+		}
+	}
+	c.namespaces[namespace]++
+	return namespace
+}
+
+// releaseNamespace releases a deleted interface from its namespace label, and deletes the metrics of the label once
+// it has no interface left
+func (c *namespaceUsageCollector) releaseNamespace(namespace string) {
+	c.namespaces[namespace]--
+	if c.namespaces[namespace] > 0 {
+		return
+	}
+	delete(c.namespaces, namespace)
+	metricNamespaceUsageBytes.DeletePartialMatch(prometheus.Labels{"namespace": namespace})
+	metricNamespaceUsagePackets.DeletePartialMatch(prometheus.Labels{"namespace": namespace})
+}
+
+func addUsage(namespace, direction string, bytes, packets float64) {
+	metricNamespaceUsageBytes.WithLabelValues(namespace, direction).Add(bytes)
+	metricNamespaceUsagePackets.WithLabelValues(namespace, direction).Add(packets)
+}
+
+// delta returns the traffic between two readings of a counter, the whole counter if it was reset in between
+func delta(current, last float64) float64 {
+	if current < last {
+		return current
+	}
+	return current - last
+}
+
+// podInterfaceNamespace returns the namespace of the pod an OVS interface was created for, from its iface-id, or an
+// empty string if the interface is not the interface of a pod
+func podInterfaceNamespace(externalIDs map[string]string) string {
+	ifaceID := externalIDs["iface-id"]
+	if ifaceID == "" || externalIDs["sandbox"] == "" {
+		return ""
+	}
+	if nadName := externalIDs[types.NADExternalID]; nadName != "" {
+		ifaceID = strings.TrimPrefix(ifaceID, util.GetSecondaryNetworkPrefix(nadName))
+	}
+	namespace, _, found := strings.Cut(ifaceID, "_")
+	if !found {
+		return ""
+	}
+	return namespace
+}
+
+// parseOvsMap parses an OVS map column printed with --data=bare, as space separated key=value pairs
+func parseOvsMap(column string) map[string]string {
+	m := map[string]string{}
+	for _, field := range strings.Fields(column) {
+		key, value, found := strings.Cut(field, "=")
+		if found {
+			m[key] = value
+		}
+	}
+	return m
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func getNamespaceUsage(vec *prometheus.CounterVec, namespace, direction string) float64 {
+	metric := &dto.Metric{}
+	gomega.Expect(vec.WithLabelValues(namespace, direction).Write(metric)).To(gomega.Succeed())
+	return metric.GetCounter().GetValue()
+}
+
+var _ = ginkgo.Describe("Namespace usage metrics", func() {
+	const (
+		// a pod of ns1 on the default network and on a secondary network, a pod of ns2, and a non pod interface
+		firstCollection = `uuid1,attached_mac=0a:58:0a:f4:00:05 iface-id=ns1_pod1 iface-id-ver=1 sandbox=abc,rx_bytes=100 rx_packets=10 tx_bytes=200 tx_packets=20
+uuid2,"attached_mac=0a:58:c0:a8:00:05 iface-id=ns1.blue_ns1_pod1 iface-id-ver=1 k8s.ovn.org/nad=ns1/blue ip_addresses=192.168.0.5/24,fd00::5/64 sandbox=abc",rx_bytes=1 rx_packets=1 tx_bytes=2 tx_packets=2
+uuid3,iface-id=ns2_pod2 iface-id-ver=2 sandbox=def,rx_bytes=1000 rx_packets=100 tx_bytes=0 tx_packets=0
+uuid4,,rx_bytes=5000 rx_packets=500 tx_bytes=5000 tx_packets=500`
+		// the pod of ns2 is deleted and a pod of ns3 is created
+		secondCollection = `uuid1,iface-id=ns1_pod1 iface-id-ver=1 sandbox=abc,rx_bytes=150 rx_packets=15 tx_bytes=200 tx_packets=20
+uuid2,iface-id=ns1.blue_ns1_pod1 iface-id-ver=1 k8s.ovn.org/nad=ns1/blue sandbox=abc,rx_bytes=1 rx_packets=1 tx_bytes=2 tx_packets=2
+uuid5,iface-id=ns3_pod3 iface-id-ver=3 sandbox=ghi,rx_bytes=10 rx_packets=1 tx_bytes=10 tx_packets=1`
+	)
+
+	ginkgo.BeforeEach(func() {
+		metricNamespaceUsageBytes.Reset()
+		metricNamespaceUsagePackets.Reset()
+	})
+
+	ginkgo.It("accounts the traffic of the pod interfaces to their namespace", func() {
+		ovsVsctl := NewFakeOVSClient([]clientOutput{{stdout: firstCollection}, {stdout: secondCollection}})
+		c := newNamespaceUsageCollector(ovsVsctl.FakeCall, 10)
+
+		gomega.Expect(c.collect()).To(gomega.Succeed())
+		gomega.Expect(getNamespaceUsage(metricNamespaceUsageBytes, "ns1", "egress")).To(gomega.Equal(101.0))
+		gomega.Expect(getNamespaceUsage(metricNamespaceUsageBytes, "ns1", "ingress")).To(gomega.Equal(202.0))
+		gomega.Expect(getNamespaceUsage(metricNamespaceUsagePackets, "ns1", "egress")).To(gomega.Equal(11.0))
+		gomega.Expect(getNamespaceUsage(metricNamespaceUsagePackets, "ns2", "egress")).To(gomega.Equal(100.0))
+		gomega.Expect(c.namespaces).To(gomega.Equal(map[string]int{"ns1": 2, "ns2": 1}))
+
+		// only the traffic since the last collection is added, and the namespaces without pods are released
+		gomega.Expect(c.collect()).To(gomega.Succeed())
+		gomega.Expect(getNamespaceUsage(metricNamespaceUsageBytes, "ns1", "egress")).To(gomega.Equal(151.0))
+		gomega.Expect(getNamespaceUsage(metricNamespaceUsageBytes, "ns1", "ingress")).To(gomega.Equal(202.0))
+		gomega.Expect(getNamespaceUsage(metricNamespaceUsageBytes, "ns3", "ingress")).To(gomega.Equal(10.0))
+		gomega.Expect(c.namespaces).To(gomega.Equal(map[string]int{"ns1": 2, "ns3": 1}))
+	})
+
+	ginkgo.It("reports the namespaces over the maximum under the other namespace", func() {
+		ovsVsctl := NewFakeOVSClient([]clientOutput{{stdout: firstCollection}, {stdout: secondCollection}})
+		c := newNamespaceUsageCollector(ovsVsctl.FakeCall, 1)
+
+		gomega.Expect(c.collect()).To(gomega.Succeed())
+		gomega.Expect(getNamespaceUsage(metricNamespaceUsageBytes, "ns1", "egress")).To(gomega.Equal(101.0))
+		gomega.Expect(getNamespaceUsage(metricNamespaceUsageBytes, namespaceUsageOther, "egress")).To(gomega.Equal(1000.0))
+
+		// the other namespace keeps its counters as long as it has interfaces
+		gomega.Expect(c.collect()).To(gomega.Succeed())
+		gomega.Expect(getNamespaceUsage(metricNamespaceUsageBytes, namespaceUsageOther, "egress")).To(gomega.Equal(1010.0))
+		gomega.Expect(c.namespaces).To(gomega.Equal(map[string]int{"ns1": 2, namespaceUsageOther: 1}))
+	})
+
+	ginkgo.It("returns an error when the statistics are malformed", func() {
+		ovsVsctl := NewFakeOVSClient([]clientOutput{{stdout: "uuid1,iface-id=ns1_pod1 sandbox=abc,rx_bytes=foo"}})
+		c := newNamespaceUsageCollector(ovsVsctl.FakeCall, 10)
+		gomega.Expect(c.collect()).NotTo(gomega.Succeed())
+	})
+})
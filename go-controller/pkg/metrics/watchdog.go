@@ -0,0 +1,291 @@
+package metrics
+
+import (
+	"bytes"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// watchdogCheckInterval is how often the watchdog samples goroutine
+	// counts and event loop activity
+	watchdogCheckInterval = 10 * time.Second
+	// watchdogMinDumpInterval rate limits goroutine stack dumps so that a
+	// persistent condition does not flood the logs
+	watchdogMinDumpInterval = 5 * time.Minute
+
+	watchdogReasonGoroutines = "goroutines"
+	watchdogReasonLockWait   = "lock_wait"
+	watchdogReasonStall      = "event_loop_stall"
+)
+
+// metricGoroutineCount is the number of goroutines as sampled by the watchdog
+var metricGoroutineCount = prometheus.NewGauge(prometheus.GaugeOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Name:      "watchdog_goroutines",
+	Help:      "The number of goroutines as sampled by the watchdog",
+})
+
+// metricLockWaitDuration is the time spent waiting to acquire locks in hot paths
+var metricLockWaitDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Name:      "lock_wait_duration_seconds",
+	Help:      "The duration spent waiting to acquire a lock in a hot path",
+	Buckets:   prometheus.ExponentialBuckets(.0001, 4, 10)},
+	[]string{
+		"lock",
+	},
+)
+
+// metricEventLoopBusyDuration is the longest time an event loop has been
+// processing its current event, or zero when all of its workers are idle
+var metricEventLoopBusyDuration = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Name:      "event_loop_busy_duration_seconds",
+	Help:      "The longest duration any worker of an event loop has been processing its current event",
+},
+	[]string{
+		"name",
+	},
+)
+
+// metricWatchdogStackDumps is the number of goroutine stack dumps triggered by the watchdog
+var metricWatchdogStackDumps = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Name:      "watchdog_stack_dumps_total",
+	Help:      "The number of goroutine stack dumps triggered by the watchdog",
+},
+	[]string{
+		"reason",
+	},
+)
+
+// LockWaitObserver records how long callers waited to acquire a given lock.
+type LockWaitObserver struct {
+	name     string
+	observer prometheus.Observer
+}
+
+// NewLockWaitObserver returns an observer for the lock with the given name
+func NewLockWaitObserver(name string) *LockWaitObserver {
+	return &LockWaitObserver{
+		name:     name,
+		observer: metricLockWaitDuration.WithLabelValues(name),
+	}
+}
+
+// Start returns the time the caller starts acquiring the lock, to be passed to
+// Observe once the lock is acquired. Lock waits are only timed while the
+// watchdog runs: Start returns the zero time otherwise.
+func (o *LockWaitObserver) Start() time.Time {
+	if atomic.LoadInt32(&wd.running) == 0 {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// Observe records the wait for a lock that the caller started acquiring at
+// start, as returned by Start, and has just acquired
+func (o *LockWaitObserver) Observe(start time.Time) {
+	if start.IsZero() {
+		return
+	}
+	wait := time.Since(start)
+	o.observer.Observe(wait.Seconds())
+	wd.checkLockWait(o.name, wait)
+}
+
+// EventLoop tracks the event being processed by a single worker goroutine
+// so that the watchdog can detect workers that are stuck.
+type EventLoop struct {
+	name string
+	// busySince is the UnixNano time the worker started processing its
+	// current event, or zero when it is idle
+	busySince int64
+}
+
+// RegisterEventLoop registers a worker of the event loop with the given name
+// with the watchdog. Several workers may share the same name. Unregister must
+// be called when the worker exits.
+func RegisterEventLoop(name string) *EventLoop {
+	l := &EventLoop{name: name}
+	wd.Lock()
+	defer wd.Unlock()
+	wd.eventLoops[l] = struct{}{}
+	return l
+}
+
+// Unregister removes the worker from the watchdog
+func (l *EventLoop) Unregister() {
+	wd.Lock()
+	defer wd.Unlock()
+	delete(wd.eventLoops, l)
+}
+
+// Begin marks the start of processing an event
+func (l *EventLoop) Begin() {
+	atomic.StoreInt64(&l.busySince, time.Now().UnixNano())
+}
+
+// End marks the end of processing an event
+func (l *EventLoop) End() {
+	atomic.StoreInt64(&l.busySince, 0)
+}
+
+// busyFor returns how long the worker has been processing its current event
+func (l *EventLoop) busyFor(now time.Time) time.Duration {
+	since := atomic.LoadInt64(&l.busySince)
+	if since == 0 {
+		return 0
+	}
+	return now.Sub(time.Unix(0, since))
+}
+
+// WatchdogConfig holds the thresholds above which the watchdog dumps goroutine
+// stacks. A zero threshold disables the corresponding check.
+type WatchdogConfig struct {
+	GoroutineThreshold int
+	LockWaitThreshold  time.Duration
+	StallThreshold     time.Duration
+}
+
+type watchdog struct {
+	sync.Mutex
+	eventLoops map[*EventLoop]struct{}
+	// names of the event loops that have been exported, so that loops
+	// that are no longer registered can be reset
+	exported map[string]bool
+	// running and lockWaitThreshold are read from hot paths, so they are
+	// stored separately as atomics, the threshold in nanoseconds
+	running           int32
+	lockWaitThreshold int64
+	config            WatchdogConfig
+	lastDump          time.Time
+	// dumpStacks writes the goroutine stacks to the log; overridden in tests
+	dumpStacks func(reason string)
+}
+
+var wd = &watchdog{
+	eventLoops: make(map[*EventLoop]struct{}),
+	exported:   make(map[string]bool),
+	dumpStacks: logGoroutineStacks,
+}
+
+var registerWatchdogOnce sync.Once
+
+// StartWatchdog registers the watchdog metrics and starts monitoring goroutine
+// counts, lock waits and event loop stalls until stopChan is closed. Goroutine
+// stacks are dumped to the log when any of the thresholds is exceeded.
+func StartWatchdog(cfg WatchdogConfig, stopChan <-chan struct{}, wg *sync.WaitGroup) {
+	registerWatchdogOnce.Do(func() {
+		prometheus.MustRegister(metricGoroutineCount)
+		prometheus.MustRegister(metricLockWaitDuration)
+		prometheus.MustRegister(metricEventLoopBusyDuration)
+		prometheus.MustRegister(metricWatchdogStackDumps)
+	})
+
+	wd.Lock()
+	wd.config = cfg
+	wd.Unlock()
+	atomic.StoreInt64(&wd.lockWaitThreshold, int64(cfg.LockWaitThreshold))
+	atomic.StoreInt32(&wd.running, 1)
+
+	klog.Infof("Starting watchdog with goroutine threshold %d, lock wait threshold %v and event loop stall threshold %v",
+		cfg.GoroutineThreshold, cfg.LockWaitThreshold, cfg.StallThreshold)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(watchdogCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				wd.check(time.Now())
+			case <-stopChan:
+				atomic.StoreInt32(&wd.running, 0)
+				atomic.StoreInt64(&wd.lockWaitThreshold, 0)
+				return
+			}
+		}
+	}()
+}
+
+// check samples the goroutine count and the event loops and dumps goroutine
+// stacks if any of them exceeds its threshold
+func (w *watchdog) check(now time.Time) {
+	w.Lock()
+	defer w.Unlock()
+
+	goroutines := runtime.NumGoroutine()
+	metricGoroutineCount.Set(float64(goroutines))
+	if w.config.GoroutineThreshold > 0 && goroutines > w.config.GoroutineThreshold {
+		klog.Warningf("Watchdog: %d goroutines running, exceeds threshold %d", goroutines, w.config.GoroutineThreshold)
+		w.maybeDumpStacksLocked(watchdogReasonGoroutines, now)
+	}
+
+	busy := make(map[string]time.Duration, len(w.exported))
+	for l := range w.eventLoops {
+		if d := l.busyFor(now); d >= busy[l.name] {
+			busy[l.name] = d
+		}
+	}
+	for name := range w.exported {
+		if _, ok := busy[name]; !ok {
+			metricEventLoopBusyDuration.DeleteLabelValues(name)
+			delete(w.exported, name)
+		}
+	}
+	stalled := false
+	for name, d := range busy {
+		metricEventLoopBusyDuration.WithLabelValues(name).Set(d.Seconds())
+		w.exported[name] = true
+		if w.config.StallThreshold > 0 && d > w.config.StallThreshold {
+			klog.Warningf("Watchdog: event loop %s has been processing an event for %v, exceeds threshold %v",
+				name, d, w.config.StallThreshold)
+			stalled = true
+		}
+	}
+	if stalled {
+		w.maybeDumpStacksLocked(watchdogReasonStall, now)
+	}
+}
+
+// checkLockWait dumps goroutine stacks if waiting for the named lock exceeded
+// the threshold
+func (w *watchdog) checkLockWait(name string, wait time.Duration) {
+	threshold := time.Duration(atomic.LoadInt64(&w.lockWaitThreshold))
+	if threshold <= 0 || wait <= threshold {
+		return
+	}
+	klog.Warningf("Watchdog: waited %v to acquire lock %s, exceeds threshold %v", wait, name, threshold)
+	w.Lock()
+	defer w.Unlock()
+	w.maybeDumpStacksLocked(watchdogReasonLockWait, time.Now())
+}
+
+// maybeDumpStacksLocked dumps goroutine stacks unless they were dumped less
+// than watchdogMinDumpInterval ago. Must be called with the watchdog lock held.
+func (w *watchdog) maybeDumpStacksLocked(reason string, now time.Time) {
+	if !w.lastDump.IsZero() && now.Sub(w.lastDump) < watchdogMinDumpInterval {
+		return
+	}
+	w.lastDump = now
+	metricWatchdogStackDumps.WithLabelValues(reason).Inc()
+	w.dumpStacks(reason)
+}
+
+func logGoroutineStacks(reason string) {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 2); err != nil {
+		klog.Errorf("Watchdog: failed to dump goroutine stacks: %v", err)
+		return
+	}
+	klog.Warningf("Watchdog: dumping goroutine stacks (reason: %s):\n%s", reason, buf.String())
+}
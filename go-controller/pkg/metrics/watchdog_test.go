@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Watchdog", func() {
+	var (
+		savedWatchdog *watchdog
+		dumps         []string
+	)
+
+	ginkgo.BeforeEach(func() {
+		savedWatchdog = wd
+		dumps = nil
+		wd = &watchdog{
+			eventLoops: make(map[*EventLoop]struct{}),
+			exported:   make(map[string]bool),
+			dumpStacks: func(reason string) {
+				dumps = append(dumps, reason)
+			},
+		}
+	})
+
+	ginkgo.AfterEach(func() {
+		wd = savedWatchdog
+	})
+
+	ginkgo.It("dumps stacks once when an event loop stalls", func() {
+		wd.config = WatchdogConfig{StallThreshold: time.Minute}
+		loop := RegisterEventLoop("Pod")
+		defer loop.Unregister()
+		idle := RegisterEventLoop("Pod")
+		defer idle.Unregister()
+
+		now := time.Now()
+		loop.Begin()
+		wd.check(now)
+		gomega.Expect(dumps).To(gomega.BeEmpty())
+
+		wd.check(now.Add(2 * time.Minute))
+		gomega.Expect(dumps).To(gomega.Equal([]string{watchdogReasonStall}))
+
+		// dumps are rate limited while the loop remains stalled
+		wd.check(now.Add(3 * time.Minute))
+		gomega.Expect(dumps).To(gomega.HaveLen(1))
+
+		loop.End()
+		wd.check(now.Add(10 * time.Minute))
+		gomega.Expect(dumps).To(gomega.HaveLen(1))
+	})
+
+	ginkgo.It("stops exporting event loops once they are unregistered", func() {
+		loop := RegisterEventLoop("Node")
+		wd.check(time.Now())
+		gomega.Expect(wd.exported).To(gomega.HaveKey("Node"))
+
+		loop.Unregister()
+		wd.check(time.Now())
+		gomega.Expect(wd.exported).To(gomega.BeEmpty())
+	})
+
+	ginkgo.It("dumps stacks when waiting for a lock exceeds the threshold", func() {
+		observer := NewLockWaitObserver("test")
+		observer.Observe(time.Now().Add(-time.Hour))
+		gomega.Expect(dumps).To(gomega.BeEmpty(), "lock wait check is disabled without a threshold")
+
+		wd.lockWaitThreshold = int64(time.Second)
+		observer.Observe(time.Now())
+		gomega.Expect(dumps).To(gomega.BeEmpty())
+
+		observer.Observe(time.Now().Add(-time.Minute))
+		gomega.Expect(dumps).To(gomega.Equal([]string{watchdogReasonLockWait}))
+	})
+
+	ginkgo.It("only times lock waits while the watchdog runs", func() {
+		observer := NewLockWaitObserver("test")
+		wd.lockWaitThreshold = int64(time.Second)
+		gomega.Expect(observer.Start().IsZero()).To(gomega.BeTrue())
+
+		wd.running = 1
+		start := observer.Start()
+		gomega.Expect(start.IsZero()).To(gomega.BeFalse())
+		observer.Observe(start.Add(-time.Minute))
+		gomega.Expect(dumps).To(gomega.Equal([]string{watchdogReasonLockWait}))
+	})
+
+	ginkgo.It("dumps stacks when the number of goroutines exceeds the threshold", func() {
+		wd.config = WatchdogConfig{GoroutineThreshold: 1}
+		wd.check(time.Now())
+		gomega.Expect(dumps).To(gomega.Equal([]string{watchdogReasonGoroutines}))
+	})
+})
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
 	"sync"
 	"time"
 
@@ -44,6 +45,10 @@ const (
 
 var ErrNetworkControllerTopologyNotManaged = errors.New("no cluster network controller to manage topology")
 
+// ErrNetworkControllerNotReconfigurable is returned by a network controller that can't be reconfigured, its network
+// is recreated instead
+var ErrNetworkControllerNotReconfigurable = errors.New("network controller can't be reconfigured")
+
 type BaseNetworkController interface {
 	Start(ctx context.Context) error
 	Stop()
@@ -57,10 +62,23 @@ type NetworkController interface {
 	DeleteNAD(nadName string)
 	HasNAD(nadName string) bool
 	// Cleanup cleans up the given network, it could be called to clean up network controllers that are deleted when
-	// ovn-k8s is down; so it's receiver could be a dummy network controller.
+	// ovn-k8s is down; so it's receiver could be a dummy network controller that only knows the network name and
+	// topology. It must therefore find everything it deletes from the network name alone, and remove all the
+	// entities of the network: the cluster wide ones as well as the ones created per zone or per node, like the
+	// interconnect transit switch or the node annotations. It must be idempotent and succeed when some or all
+	// of these entities are already gone, since it may be called again for a partially cleaned up network.
 	Cleanup(netName string) error
 }
 
+// ReconfigurableNetworkController is a network controller that can apply the configuration changes of its network
+// allowed by util.CanReconfigureNetInfo in place, without being recreated
+type ReconfigurableNetworkController interface {
+	NetworkController
+	// Reconfigure reconfigures the started network controller with the updated network information. It may return
+	// ErrNetworkControllerNotReconfigurable, in which case the network is recreated.
+	Reconfigure(netInfo util.NetInfo) error
+}
+
 // NetworkControllerManager manages all network controllers
 type NetworkControllerManager interface {
 	NewNetworkController(netInfo util.NetInfo) (NetworkController, error)
@@ -306,14 +324,13 @@ func (nadController *NetAttachDefinitionController) onNetworkAttachDefinitionUpd
 		return
 	}
 
-	err := fmt.Sprintf("%s: Updating net-attach-def %s/%s is not supported", nadController.name, newNAD.Namespace, newNAD.Name)
-	nadRef := kapi.ObjectReference{
-		Kind:      "NetworkAttachmentDefinition",
-		Namespace: newNAD.Namespace,
-		Name:      newNAD.Name,
+	// metadata updates, like the network controller status annotation, don't change the network
+	if reflect.DeepEqual(oldNAD.Spec, newNAD.Spec) {
+		return
 	}
-	nadController.recorder.Eventf(&nadRef, kapi.EventTypeWarning, "ErrorUpdatingResource", err)
-	klog.Warningf(err)
+
+	klog.V(4).Infof("%s: Updating net-attach-def %s/%s", nadController.name, newNAD.Namespace, newNAD.Name)
+	nadController.queueNetworkAttachDefinition(newObj)
 }
 
 func (nadController *NetAttachDefinitionController) onNetworkAttachDefinitionDelete(obj interface{}) {
@@ -352,6 +369,7 @@ func (nadController *NetAttachDefinitionController) AddNetAttachDef(ncm NetworkC
 	var nInfo util.NetInfo
 	var err, invalidNADErr error
 	var netName string
+	var isDefaultNetwork bool
 
 	netAttachDefName := util.GetNADName(netattachdef.Namespace, netattachdef.Name)
 	klog.Infof("%s: Add net-attach-def %s", nadController.name, netAttachDefName)
@@ -360,9 +378,13 @@ func (nadController *NetAttachDefinitionController) AddNetAttachDef(ncm NetworkC
 	if invalidNADErr == nil {
 		netName = nInfo.GetNetworkName()
 		if netName == types.DefaultNetworkName {
+			isDefaultNetwork = true
 			invalidNADErr = fmt.Errorf("NAD for default network, skip it")
 		}
 	}
+	if invalidNADErr != nil && !isDefaultNetwork {
+		nadController.recordInvalidNAD(netattachdef, invalidNADErr)
+	}
 
 	return nadController.perNADNetInfo.DoWithLock(netAttachDefName, func(nadName string) error {
 		nadNci, loaded := nadController.perNADNetInfo.LoadOrStore(nadName, nInfo)
@@ -406,6 +428,22 @@ func (nadController *NetAttachDefinitionController) AddNetAttachDef(ncm NetworkC
 			}
 			if nadUpdated {
 				klog.V(5).Infof("%s: net-attach-def %s network %s updated", nadController.name, nadName, netName)
+				if invalidNADErr == nil {
+					reconfigured, err := nadController.reconfigureNetwork(nadName, nadNci, nInfo)
+					if err != nil {
+						klog.Errorf("%s: Failed to reconfigure network %s of net-attach-def %s: %v", nadController.name, netName, nadName, err)
+						return err
+					}
+					if reconfigured {
+						nadController.perNADNetInfo.Delete(nadName)
+						nadController.perNADNetInfo.LoadOrStore(nadName, nInfo)
+						nadController.recordNetworkUpdate(netattachdef, "NetworkReconfigured",
+							fmt.Sprintf("Network %s reconfigured", netName))
+						return nil
+					}
+				}
+				nadController.recordNetworkUpdate(netattachdef, "NetworkRecreated",
+					fmt.Sprintf("Network %s can't be reconfigured in place and is recreated", nadNci.GetNetworkName()))
 				// delete the NAD from the old network first
 				oldNetName := nadNci.GetNetworkName()
 				err := nadController.deleteNADFromController(oldNetName, nadName)
@@ -433,6 +471,65 @@ func (nadController *NetAttachDefinitionController) AddNetAttachDef(ncm NetworkC
 	})
 }
 
+// reconfigureNetwork reconfigures the network of the given updated NAD in place, which is only possible when the
+// change is allowed by util.CanReconfigureNetInfo, the network controller is started and supports it, and the NAD
+// is the only one of the network: all the NADs of a network must share its configuration. It returns false when the
+// network has to be recreated instead.
+func (nadController *NetAttachDefinitionController) reconfigureNetwork(nadName string, oldNInfo util.BasicNetInfo,
+	nInfo util.NetInfo) (bool, error) {
+	if !util.CanReconfigureNetInfo(oldNInfo, nInfo) {
+		return false, nil
+	}
+	reconfigured := false
+	err := nadController.perNetworkNADInfo.DoWithLock(nInfo.GetNetworkName(), func(networkName string) error {
+		nni, found := nadController.perNetworkNADInfo.Load(networkName)
+		if !found || !nni.isStarted || len(nni.nadNames) != 1 {
+			return nil
+		}
+		if _, ok := nni.nadNames[nadName]; !ok {
+			return nil
+		}
+		rc, ok := nni.nc.(ReconfigurableNetworkController)
+		if !ok {
+			return nil
+		}
+		klog.V(5).Infof("%s: Reconfigure network controller for network %s", nadController.name, networkName)
+		err := rc.Reconfigure(nInfo)
+		if errors.Is(err, ErrNetworkControllerNotReconfigurable) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		reconfigured = true
+		return nil
+	})
+	return reconfigured, err
+}
+
+// recordNetworkUpdate posts an event telling how the update of the given NAD was applied to its network
+func (nadController *NetAttachDefinitionController) recordNetworkUpdate(netattachdef *nettypes.NetworkAttachmentDefinition,
+	reason, message string) {
+	nadRef := kapi.ObjectReference{
+		Kind:      "NetworkAttachmentDefinition",
+		Namespace: netattachdef.Namespace,
+		Name:      netattachdef.Name,
+	}
+	nadController.recorder.Eventf(&nadRef, kapi.EventTypeNormal, reason, "%s: %s", nadController.name, message)
+}
+
+// recordInvalidNAD posts a warning event telling why the given NAD is rejected
+func (nadController *NetAttachDefinitionController) recordInvalidNAD(netattachdef *nettypes.NetworkAttachmentDefinition,
+	err error) {
+	nadRef := kapi.ObjectReference{
+		Kind:      "NetworkAttachmentDefinition",
+		Namespace: netattachdef.Namespace,
+		Name:      netattachdef.Name,
+	}
+	nadController.recorder.Eventf(&nadRef, kapi.EventTypeWarning, "InvalidNetworkAttachmentDefinition",
+		"%s: net-attach-def is invalid and ignored: %v", nadController.name, err)
+}
+
 // DeleteNetAttachDef deletes the given NAD from the associated controller. It delete the controller if this
 // is the last NAD of the network
 func (nadController *NetAttachDefinitionController) DeleteNetAttachDef(netAttachDefName string) error {
@@ -3,10 +3,12 @@ package networkControllerManager
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/containernetworking/cni/pkg/types"
+	nadclientset "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned"
 	libovsdbclient "github.com/ovn-org/libovsdb/client"
 	ovncnitypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/cni/types"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
@@ -56,6 +58,8 @@ type networkControllerManager struct {
 
 	// net-attach-def controller handle net-attach-def and create/delete network controllers
 	nadController *nad.NetAttachDefinitionController
+	// net-attach-def client used to report the status of the network controllers
+	nadClient nadclientset.Interface
 }
 
 func (cm *networkControllerManager) NewNetworkController(nInfo util.NetInfo) (nad.NetworkController, error) {
@@ -63,22 +67,19 @@ func (cm *networkControllerManager) NewNetworkController(nInfo util.NetInfo) (na
 	if err != nil {
 		return nil, fmt.Errorf("failed to create network controller info %w", err)
 	}
+	var nc nad.NetworkController
 	topoType := nInfo.TopologyType()
 	switch topoType {
 	case ovntypes.Layer3Topology:
-		return ovn.NewSecondaryLayer3NetworkController(cnci, nInfo), nil
+		nc = ovn.NewSecondaryLayer3NetworkController(cnci, nInfo)
 	case ovntypes.Layer2Topology:
-		if config.OVNKubernetesFeature.EnableInterconnect {
-			return nil, fmt.Errorf("topology type %s not supported when Interconnect feature is enabled", topoType)
-		}
-		return ovn.NewSecondaryLayer2NetworkController(cnci, nInfo), nil
+		nc = ovn.NewSecondaryLayer2NetworkController(cnci, nInfo)
 	case ovntypes.LocalnetTopology:
-		if config.OVNKubernetesFeature.EnableInterconnect {
-			return nil, fmt.Errorf("topology type %s not supported when Interconnect feature is enabled", topoType)
-		}
-		return ovn.NewSecondaryLocalnetNetworkController(cnci, nInfo), nil
+		nc = ovn.NewSecondaryLocalnetNetworkController(cnci, nInfo)
+	default:
+		return nil, fmt.Errorf("topology type %s not supported", topoType)
 	}
-	return nil, fmt.Errorf("topology type %s not supported", topoType)
+	return newStatusReportingNetworkController(nc, cm.nadClient, config.Default.Zone), nil
 }
 
 // newDummyNetworkController creates a dummy network controller used to clean up specific network
@@ -99,28 +100,93 @@ func (cm *networkControllerManager) newDummyNetworkController(topoType, netName
 	return nil, fmt.Errorf("topology type %s not supported", topoType)
 }
 
-// Find all the OVN logical switches/routers for the secondary networks
-func findAllSecondaryNetworkLogicalEntities(nbClient libovsdbclient.Client) ([]*nbdb.LogicalSwitch,
-	[]*nbdb.LogicalRouter, error) {
-	p1 := func(item *nbdb.LogicalSwitch) bool {
+// secondaryNetworkControllerSuffix is appended to the network name to build the name of the controller owning the
+// address sets of a secondary network
+const secondaryNetworkControllerSuffix = "-network-controller"
+
+// findStaleSecondaryNetworks finds the secondary networks that are not in existingNetworks but still have OVN
+// logical entities, and returns their topology, if known, keyed by network name. A network is found as long as
+// any of its switches, routers, port groups or address sets is left. It also returns the interconnect transit
+// switches that don't belong to any existing network: they are not tagged with the network name, so a network
+// whose transit switch is the only entity left can't be found otherwise.
+func findStaleSecondaryNetworks(nbClient libovsdbclient.Client, existingNetworks map[string]struct{}) (map[string]string,
+	[]string, error) {
+	staleNetworks := map[string]string{}
+	addStaleNetwork := func(netName, topoType string) {
+		if _, ok := existingNetworks[netName]; ok || netName == "" || netName == ovntypes.DefaultNetworkName {
+			// network still exists, no cleanup to do
+			return
+		}
+		if staleNetworks[netName] == "" {
+			staleNetworks[netName] = topoType
+		}
+	}
+
+	switches, err := libovsdbops.FindLogicalSwitchesWithPredicate(nbClient, func(item *nbdb.LogicalSwitch) bool {
 		_, ok := item.ExternalIDs[ovntypes.NetworkExternalID]
-		return ok
+		return ok || item.OtherConfig["interconn-ts"] != ""
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get all logical switches of secondary networks: %v", err)
 	}
-	nodeSwitches, err := libovsdbops.FindLogicalSwitchesWithPredicate(nbClient, p1)
+	// TopologyExternalID always co-exists with NetworkExternalID on switches and routers
+	var transitSwitches []string
+	for _, ls := range switches {
+		if netName, ok := ls.ExternalIDs[ovntypes.NetworkExternalID]; ok {
+			addStaleNetwork(netName, ls.ExternalIDs[ovntypes.TopologyExternalID])
+		} else if ls.Name != ovntypes.TransitSwitch {
+			transitSwitches = append(transitSwitches, ls.Name)
+		}
+	}
+
+	routers, err := libovsdbops.FindLogicalRoutersWithPredicate(nbClient, func(item *nbdb.LogicalRouter) bool {
+		_, ok := item.ExternalIDs[ovntypes.NetworkExternalID]
+		return ok
+	})
 	if err != nil {
-		klog.Errorf("Failed to get all logical switches of secondary network error: %v", err)
-		return nil, nil, err
+		return nil, nil, fmt.Errorf("failed to get all logical routers of secondary networks: %v", err)
+	}
+	for _, lr := range routers {
+		addStaleNetwork(lr.ExternalIDs[ovntypes.NetworkExternalID], lr.ExternalIDs[ovntypes.TopologyExternalID])
 	}
-	p2 := func(item *nbdb.LogicalRouter) bool {
+
+	portGroups, err := libovsdbops.FindPortGroupsWithPredicate(nbClient, func(item *nbdb.PortGroup) bool {
 		_, ok := item.ExternalIDs[ovntypes.NetworkExternalID]
 		return ok
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get all port groups of secondary networks: %v", err)
+	}
+	for _, pg := range portGroups {
+		addStaleNetwork(pg.ExternalIDs[ovntypes.NetworkExternalID], "")
 	}
-	clusterRouters, err := libovsdbops.FindLogicalRoutersWithPredicate(nbClient, p2)
+
+	addressSets, err := libovsdbops.FindAddressSetsWithPredicate(nbClient, func(item *nbdb.AddressSet) bool {
+		return strings.HasSuffix(item.ExternalIDs[libovsdbops.OwnerControllerKey.String()], secondaryNetworkControllerSuffix)
+	})
 	if err != nil {
-		klog.Errorf("Failed to get all distributed logical routers: %v", err)
-		return nil, nil, err
+		return nil, nil, fmt.Errorf("failed to get all address sets of secondary networks: %v", err)
+	}
+	for _, as := range addressSets {
+		addStaleNetwork(strings.TrimSuffix(as.ExternalIDs[libovsdbops.OwnerControllerKey.String()],
+			secondaryNetworkControllerSuffix), "")
+	}
+
+	existingTransitSwitches := make(map[string]struct{}, len(existingNetworks))
+	for netName := range existingNetworks {
+		existingTransitSwitches[util.GetSecondaryNetworkPrefix(netName)+ovntypes.TransitSwitch] = struct{}{}
+	}
+	for netName := range staleNetworks {
+		// the transit switch is deleted with the rest of the stale network
+		existingTransitSwitches[util.GetSecondaryNetworkPrefix(netName)+ovntypes.TransitSwitch] = struct{}{}
+	}
+	var staleTransitSwitches []string
+	for _, name := range transitSwitches {
+		if _, ok := existingTransitSwitches[name]; !ok {
+			staleTransitSwitches = append(staleTransitSwitches, name)
+		}
 	}
-	return nodeSwitches, clusterRouters, nil
+	return staleNetworks, staleTransitSwitches, nil
 }
 
 func (cm *networkControllerManager) CleanupDeletedNetworks(allControllers []nad.NetworkController) error {
@@ -129,51 +195,37 @@ func (cm *networkControllerManager) CleanupDeletedNetworks(allControllers []nad.
 		existingNetworksMap[oc.GetNetworkName()] = struct{}{}
 	}
 
-	// Get all the existing secondary networks and its logical entities
-	switches, routers, err := findAllSecondaryNetworkLogicalEntities(cm.nbClient)
+	// Get all the stale secondary networks that still have logical entities
+	staleNetworks, staleTransitSwitches, err := findStaleSecondaryNetworks(cm.nbClient, existingNetworksMap)
 	if err != nil {
 		return err
 	}
 
-	staleNetworkControllers := map[string]nad.NetworkController{}
-	for _, ls := range switches {
-		netName := ls.ExternalIDs[ovntypes.NetworkExternalID]
-		if _, ok := existingNetworksMap[netName]; ok {
-			// network still exists, no cleanup to do
-			continue
+	for netName, topoType := range staleNetworks {
+		if topoType == "" {
+			// the cleanup doesn't depend on the topology, any controller can do it
+			topoType = ovntypes.Layer3Topology
 		}
-		// TopologyExternalID always co-exists with NetworkExternalID
-		topoType := ls.ExternalIDs[ovntypes.TopologyExternalID]
 		// Create dummy network controllers to clean up logical entities
 		klog.V(5).Infof("Found stale %s network %s", topoType, netName)
-		if oc, err := cm.newDummyNetworkController(topoType, netName); err == nil {
-			staleNetworkControllers[netName] = oc
-			continue
-		}
-	}
-	for _, lr := range routers {
-		netName := lr.ExternalIDs[ovntypes.NetworkExternalID]
-		if _, ok := existingNetworksMap[netName]; ok {
-			// network still exists, no cleanup to do
-			continue
-		}
-		// TopologyExternalID always co-exists with NetworkExternalID
-		topoType := lr.ExternalIDs[ovntypes.TopologyExternalID]
-		// Create dummy network controllers to clean up logical entities
-		klog.V(5).Infof("Found stale %s network %s", topoType, netName)
-		if oc, err := cm.newDummyNetworkController(topoType, netName); err == nil {
-			staleNetworkControllers[netName] = oc
+		oc, err := cm.newDummyNetworkController(topoType, netName)
+		if err != nil {
+			klog.Errorf("Failed to create dummy network controller to clean up network %s: %v", netName, err)
 			continue
 		}
-	}
-
-	for netName, oc := range staleNetworkControllers {
 		klog.Infof("Cleanup entities for stale network %s", netName)
 		err = oc.Cleanup(netName)
 		if err != nil {
 			klog.Errorf("Failed to delete stale OVN logical entities for network %s: %v", netName, err)
 		}
 	}
+
+	for _, name := range staleTransitSwitches {
+		klog.Infof("Deleting stale interconnect transit switch %s", name)
+		if err = libovsdbops.DeleteLogicalSwitch(cm.nbClient, name); err != nil {
+			klog.Errorf("Failed to delete stale interconnect transit switch %s: %v", name, err)
+		}
+	}
 	return nil
 }
 
@@ -191,6 +243,7 @@ func NewNetworkControllerManager(ovnClient *util.OVNClientset, identity string,
 			EgressFirewallClient: ovnClient.EgressFirewallClient,
 			CloudNetworkClient:   ovnClient.CloudNetworkClient,
 			EgressServiceClient:  ovnClient.EgressServiceClient,
+			IPAMClaimsClient:     ovnClient.IPAMClaimsClient,
 		},
 		stopChan:     make(chan struct{}),
 		watchFactory: wf,
@@ -198,6 +251,7 @@ func NewNetworkControllerManager(ovnClient *util.OVNClientset, identity string,
 		nbClient:     libovsdbOvnNBClient,
 		sbClient:     libovsdbOvnSBClient,
 		podRecorder:  &podRecorder,
+		nadClient:    ovnClient.NetworkAttchDefClient,
 
 		wg:               wg,
 		identity:         identity,
@@ -0,0 +1,93 @@
+package networkControllerManager
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/libovsdbops"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
+	libovsdbtest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing/libovsdb"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+)
+
+var _ = Describe("Stale secondary networks", func() {
+	var cleanup *libovsdbtest.Cleanup
+
+	AfterEach(func() {
+		if cleanup != nil {
+			cleanup.Cleanup()
+		}
+	})
+
+	It("are found from any of their logical entities", func() {
+		transitSwitch := func(netName string) *nbdb.LogicalSwitch {
+			name := types.TransitSwitch
+			if netName != types.DefaultNetworkName {
+				name = util.GetSecondaryNetworkPrefix(netName) + types.TransitSwitch
+			}
+			return &nbdb.LogicalSwitch{
+				UUID:        name + "-UUID",
+				Name:        name,
+				OtherConfig: map[string]string{"interconn-ts": name},
+			}
+		}
+		initialNBDB := libovsdbtest.TestSetup{
+			NBData: []libovsdbtest.TestData{
+				// existing network
+				&nbdb.LogicalSwitch{
+					UUID: "blue-switch-UUID",
+					Name: "blue_node1",
+					ExternalIDs: map[string]string{
+						types.NetworkExternalID:  "blue",
+						types.TopologyExternalID: types.Layer3Topology,
+					},
+				},
+				transitSwitch("blue"),
+				// default network
+				transitSwitch(types.DefaultNetworkName),
+				&nbdb.AddressSet{
+					UUID: "default-as-UUID",
+					ExternalIDs: map[string]string{
+						libovsdbops.OwnerControllerKey.String(): types.DefaultNetworkName + secondaryNetworkControllerSuffix,
+					},
+				},
+				// stale network with a switch
+				&nbdb.LogicalSwitch{
+					UUID: "green-switch-UUID",
+					Name: "green_ovn_layer2_switch",
+					ExternalIDs: map[string]string{
+						types.NetworkExternalID:  "green",
+						types.TopologyExternalID: types.Layer2Topology,
+					},
+				},
+				// stale network with only a port group left
+				&nbdb.PortGroup{
+					UUID:        "red-pg-UUID",
+					Name:        "red_pg",
+					ExternalIDs: map[string]string{types.NetworkExternalID: "red"},
+				},
+				// stale network with only an address set left
+				&nbdb.AddressSet{
+					UUID: "yellow-as-UUID",
+					ExternalIDs: map[string]string{
+						libovsdbops.OwnerControllerKey.String(): "yellow" + secondaryNetworkControllerSuffix,
+					},
+				},
+				// stale network with only its transit switch left
+				transitSwitch("orange"),
+			},
+		}
+		nbClient, nbCleanup, err := libovsdbtest.NewNBTestHarness(initialNBDB, nil)
+		Expect(err).NotTo(HaveOccurred())
+		cleanup = nbCleanup
+
+		staleNetworks, staleTransitSwitches, err := findStaleSecondaryNetworks(nbClient, map[string]struct{}{"blue": {}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(staleNetworks).To(Equal(map[string]string{
+			"green":  types.Layer2Topology,
+			"red":    "",
+			"yellow": "",
+		}))
+		Expect(staleTransitSwitches).To(ConsistOf(transitSwitch("orange").Name))
+	})
+})
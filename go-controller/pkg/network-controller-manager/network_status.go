@@ -0,0 +1,143 @@
+package networkControllerManager
+
+import (
+	"context"
+	"sync"
+
+	nadclientset "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned"
+	nad "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/network-attach-def-controller"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+// statusReportingNetworkController wraps the network controller of a secondary network and reports its state in
+// the network controller status annotation of the net-attach-defs of the network, so that users can tell why a
+// network isn't functioning in a zone from the net-attach-defs alone.
+type statusReportingNetworkController struct {
+	nad.NetworkController
+	nadClient nadclientset.Interface
+	zone      string
+
+	sync.Mutex
+	status util.NetworkControllerStatus
+	// net-attach-defs the status is reported to
+	nadNames map[string]struct{}
+}
+
+func newStatusReportingNetworkController(nc nad.NetworkController, nadClient nadclientset.Interface,
+	zone string) *statusReportingNetworkController {
+	return &statusReportingNetworkController{
+		NetworkController: nc,
+		nadClient:         nadClient,
+		zone:              zone,
+		nadNames:          map[string]struct{}{},
+	}
+}
+
+// Start starts the network controller, reporting the network as syncing until it either started or failed to
+func (sc *statusReportingNetworkController) Start(ctx context.Context) error {
+	sc.setStatus(util.NetworkControllerSyncing, "")
+	err := sc.NetworkController.Start(ctx)
+	if err != nil {
+		sc.setStatus(util.NetworkControllerDegraded, err.Error())
+		return err
+	}
+	sc.setStatus(util.NetworkControllerStarted, "")
+	return nil
+}
+
+// Reconfigure reconfigures the network controller, if it supports it, reporting the network as degraded if it fails
+func (sc *statusReportingNetworkController) Reconfigure(netInfo util.NetInfo) error {
+	rc, ok := sc.NetworkController.(nad.ReconfigurableNetworkController)
+	if !ok {
+		return nad.ErrNetworkControllerNotReconfigurable
+	}
+	err := rc.Reconfigure(netInfo)
+	if err != nil {
+		sc.setStatus(util.NetworkControllerDegraded, err.Error())
+		return err
+	}
+	sc.setStatus(util.NetworkControllerStarted, "")
+	return nil
+}
+
+// AddNAD adds the net-attach-def to the network controller and reports the current status of the network on it
+func (sc *statusReportingNetworkController) AddNAD(nadName string) {
+	sc.NetworkController.AddNAD(nadName)
+	sc.Lock()
+	sc.nadNames[nadName] = struct{}{}
+	status := sc.status
+	sc.Unlock()
+	if status.State != "" {
+		sc.publishStatus(nadName, status)
+	}
+}
+
+func (sc *statusReportingNetworkController) DeleteNAD(nadName string) {
+	sc.NetworkController.DeleteNAD(nadName)
+	sc.Lock()
+	delete(sc.nadNames, nadName)
+	sc.Unlock()
+}
+
+// setStatus updates the status of the network and reports it on all its net-attach-defs, unless it didn't change
+func (sc *statusReportingNetworkController) setStatus(state util.NetworkControllerState, lastError string) {
+	sc.Lock()
+	if sc.status.State == state && sc.status.LastError == lastError {
+		sc.Unlock()
+		return
+	}
+	klog.V(5).Infof("Network controller of network %s is %s", sc.GetNetworkName(), state)
+	sc.status = util.NetworkControllerStatus{
+		State:              state,
+		LastError:          lastError,
+		LastTransitionTime: metav1.Now(),
+	}
+	status := sc.status
+	nadNames := make([]string, 0, len(sc.nadNames))
+	for nadName := range sc.nadNames {
+		nadNames = append(nadNames, nadName)
+	}
+	sc.Unlock()
+
+	for _, nadName := range nadNames {
+		sc.publishStatus(nadName, status)
+	}
+}
+
+// publishStatus sets the status of the zone in the annotation of the given net-attach-def. Failing to report the
+// status must not affect the network controller, errors are only logged.
+func (sc *statusReportingNetworkController) publishStatus(nadName string, status util.NetworkControllerStatus) {
+	namespace, name, err := cache.SplitMetaNamespaceKey(nadName)
+	if err != nil {
+		klog.Errorf("Failed to report the status of network %s on net-attach-def %s: %v",
+			sc.GetNetworkName(), nadName, err)
+		return
+	}
+	// other zones update the annotation of the same net-attach-def concurrently
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		netAttachDef, err := sc.nadClient.K8sCniCncfIoV1().NetworkAttachmentDefinitions(namespace).Get(
+			context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		netAttachDef = netAttachDef.DeepCopy()
+		netAttachDef.Annotations, err = util.UpdateNetworkControllerStatusAnnotation(netAttachDef.Annotations,
+			sc.zone, status)
+		if err != nil {
+			return err
+		}
+		_, err = sc.nadClient.K8sCniCncfIoV1().NetworkAttachmentDefinitions(namespace).Update(
+			context.TODO(), netAttachDef, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		klog.Errorf("Failed to report the status of network %s on net-attach-def %s: %v",
+			sc.GetNetworkName(), nadName, err)
+	}
+}
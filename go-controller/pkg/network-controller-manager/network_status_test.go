@@ -0,0 +1,96 @@
+package networkControllerManager
+
+import (
+	"context"
+	"fmt"
+
+	nettypes "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	nadfake "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned/fake"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	nad "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/network-attach-def-controller"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeNetworkController is a network controller failing to start with startErr
+type fakeNetworkController struct {
+	nad.NetworkController
+	startErr error
+}
+
+func (nc *fakeNetworkController) Start(ctx context.Context) error { return nc.startErr }
+func (nc *fakeNetworkController) GetNetworkName() string          { return "blue" }
+func (nc *fakeNetworkController) AddNAD(nadName string)           {}
+func (nc *fakeNetworkController) DeleteNAD(nadName string)        {}
+
+var _ = Describe("Network controller status", func() {
+	var nadClient *nadfake.Clientset
+
+	BeforeEach(func() {
+		// the fake clientset tracks the initial objects under a resource name the client doesn't use, create them
+		nadClient = nadfake.NewSimpleClientset()
+		for _, namespace := range []string{"ns1", "ns2"} {
+			_, err := nadClient.K8sCniCncfIoV1().NetworkAttachmentDefinitions(namespace).Create(context.TODO(),
+				&nettypes.NetworkAttachmentDefinition{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "blue"}},
+				metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	getStatus := func(namespace, zone string) util.NetworkControllerStatus {
+		netAttachDef, err := nadClient.K8sCniCncfIoV1().NetworkAttachmentDefinitions(namespace).Get(
+			context.TODO(), "blue", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		statuses, err := util.ParseNetworkControllerStatusAnnotation(netAttachDef.Annotations)
+		Expect(err).NotTo(HaveOccurred())
+		return statuses[zone]
+	}
+
+	It("is reported on all the net-attach-defs of the network", func() {
+		nc := &fakeNetworkController{startErr: fmt.Errorf("failed to create logical switch")}
+		sc := newStatusReportingNetworkController(nc, nadClient, "zone1")
+		sc.AddNAD("ns1/blue")
+
+		Expect(sc.Start(context.TODO())).NotTo(Succeed())
+		status := getStatus("ns1", "zone1")
+		Expect(status.State).To(Equal(util.NetworkControllerDegraded))
+		Expect(status.LastError).To(Equal("failed to create logical switch"))
+
+		nc.startErr = nil
+		Expect(sc.Start(context.TODO())).To(Succeed())
+		status = getStatus("ns1", "zone1")
+		Expect(status.State).To(Equal(util.NetworkControllerStarted))
+		Expect(status.LastError).To(BeEmpty())
+
+		// a net-attach-def added to a started network gets the current status
+		sc.AddNAD("ns2/blue")
+		Expect(getStatus("ns2", "zone1").State).To(Equal(util.NetworkControllerStarted))
+	})
+
+	It("keeps the status reported by the other zones", func() {
+		other := newStatusReportingNetworkController(&fakeNetworkController{}, nadClient, "zone2")
+		other.AddNAD("ns1/blue")
+		Expect(other.Start(context.TODO())).To(Succeed())
+
+		sc := newStatusReportingNetworkController(&fakeNetworkController{startErr: fmt.Errorf("boom")},
+			nadClient, "zone1")
+		sc.AddNAD("ns1/blue")
+		Expect(sc.Start(context.TODO())).NotTo(Succeed())
+
+		Expect(getStatus("ns1", "zone1").State).To(Equal(util.NetworkControllerDegraded))
+		Expect(getStatus("ns1", "zone2").State).To(Equal(util.NetworkControllerStarted))
+	})
+
+	It("is not reported on deleted net-attach-defs", func() {
+		sc := newStatusReportingNetworkController(&fakeNetworkController{}, nadClient, "zone1")
+		sc.AddNAD("ns1/blue")
+		sc.AddNAD("ns2/blue")
+		sc.DeleteNAD("ns2/blue")
+		Expect(sc.Start(context.TODO())).To(Succeed())
+
+		Expect(getStatus("ns1", "zone1").State).To(Equal(util.NetworkControllerStarted))
+		Expect(getStatus("ns2", "zone1").State).To(BeEmpty())
+	})
+})
@@ -101,12 +101,26 @@ func dpuConnectionDetailChanged(oldDPUCD, newDPUCD *util.DPUConnectionDetails) b
 func (bnnc *BaseNodeNetworkController) watchPodsDPU() (*factory.Handler, error) {
 	clientSet := cni.NewClientSet(bnnc.client, corev1listers.NewPodLister(bnnc.watchFactory.LocalPodInformer().GetIndexer()))
 
+	// only pods of this node that are not host networked get a VF; a pod
+	// scheduled to this node is delivered as an add and, once selected, only
+	// changes of its DPU connection details are of interest
+	filter := &factory.EventFilter{
+		Match: func(obj interface{}) bool {
+			pod := obj.(*kapi.Pod)
+			return pod.Spec.NodeName == bnnc.name && !util.PodWantsHostNetwork(pod)
+		},
+		UpdateFilter: func(oldObj, newObj interface{}) bool {
+			return oldObj.(*kapi.Pod).Annotations[util.DPUConnectionDetailsAnnot] !=
+				newObj.(*kapi.Pod).Annotations[util.DPUConnectionDetailsAnnot]
+		},
+	}
+
 	netName := bnnc.GetNetworkName()
-	return bnnc.watchFactory.AddPodHandler(cache.ResourceEventHandlerFuncs{
+	return bnnc.watchFactory.AddPodHandlerWithFilter(filter, cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			pod := obj.(*kapi.Pod)
 			klog.V(5).Infof("Add for Pod: %s/%s for network %s", pod.Namespace, pod.Name, netName)
-			if util.PodWantsHostNetwork(pod) || pod.Status.Phase == kapi.PodRunning {
+			if pod.Status.Phase == kapi.PodRunning {
 				return
 			}
 
@@ -103,6 +103,8 @@ type DefaultNodeNetworkController struct {
 
 func newDefaultNodeNetworkController(cnnci *CommonNodeNetworkControllerInfo, stopChan chan struct{},
 	wg *sync.WaitGroup) *DefaultNodeNetworkController {
+	routeManager := newRouteManager(wg, true, 2*time.Minute)
+	routeManager.recordRestoredRoutes(cnnci.recorder, cnnci.name)
 	return &DefaultNodeNetworkController{
 		BaseNodeNetworkController: BaseNodeNetworkController{
 			CommonNodeNetworkControllerInfo: *cnnci,
@@ -110,7 +112,7 @@ func newDefaultNodeNetworkController(cnnci *CommonNodeNetworkControllerInfo, sto
 			stopChan:                        stopChan,
 			wg:                              wg,
 		},
-		routeManager: newRouteManager(wg, true, 2*time.Minute),
+		routeManager: routeManager,
 	}
 }
 
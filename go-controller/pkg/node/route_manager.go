@@ -9,6 +9,9 @@ import (
 	"github.com/vishvananda/netlink"
 	"github.com/vishvananda/netlink/nl"
 	"golang.org/x/sys/unix"
+	kapi "k8s.io/api/core/v1"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	utilnet "k8s.io/utils/net"
 )
@@ -23,6 +26,10 @@ type routeManager struct {
 	addRouteCh chan routesPerLink
 	delRouteCh chan routesPerLink
 	wg         *sync.WaitGroup
+	// if set, an event is recorded on the node when managed routes removed or modified by another agent are
+	// restored
+	recorder record.EventRecorder
+	nodeRef  *kapi.ObjectReference
 }
 
 // newRouteManager manages routes which include adding and deletion of routes. It also manages restoration of managed routes.
@@ -40,6 +47,18 @@ func newRouteManager(wg *sync.WaitGroup, logRouteChanges bool, syncPeriod time.D
 	}
 }
 
+// recordRestoredRoutes makes the route manager record an event on the given node each time it restores managed
+// routes, so that the agents that remove them, such as NetworkManager or cloud agents, can be tracked down.
+// Must be called before run().
+func (rm *routeManager) recordRestoredRoutes(recorder record.EventRecorder, nodeName string) {
+	rm.recorder = recorder
+	rm.nodeRef = &kapi.ObjectReference{
+		Kind: "Node",
+		Name: nodeName,
+		UID:  ktypes.UID(nodeName),
+	}
+}
+
 func (rm *routeManager) run(stopCh <-chan struct{}) {
 	var err error
 	var subscribed bool
@@ -182,11 +201,22 @@ func (rm *routeManager) processNetlinkEvent(ru netlink.RouteUpdate) error {
 		klog.Infof("Route Manager: sync required for routes associated with link %q. Reason: %s", infName, syncReason)
 		if err = rm.applyRoutesPerLink(rl); err != nil {
 			klog.Errorf("Route Manager: failed to apply route for link (%s): %w", rl.String(), err)
+		} else {
+			rm.recordRestore(infName, syncReason)
 		}
 	}
 	return nil
 }
 
+// recordRestore records an event on the node for managed routes of the link that were restored
+func (rm *routeManager) recordRestore(infName, reason string) {
+	if rm.recorder == nil {
+		return
+	}
+	rm.recorder.Eventf(rm.nodeRef, kapi.EventTypeWarning, "RestoredManagedRoutes",
+		"Restored the routes of link %s managed by ovnkube-node: %s", infName, reason)
+}
+
 func (rm *routeManager) applyRoutesPerLink(rl routesPerLink) error {
 	for _, r := range rl.routes {
 		if err := rm.applyRoute(rl.link, r.gwIP, r.subnet, r.mtu, r.srcIP); err != nil {
@@ -340,6 +370,8 @@ func (rm *routeManager) sync() {
 			klog.Infof("Route Manager: sync required for routes associated with link %q. Reason: %s", infName, syncReason)
 			if err = rm.applyRoutesPerLink(rl); err != nil {
 				klog.Errorf("Route Manager: sync failed to apply route (%s): %v", rl.String(), err)
+			} else {
+				rm.recordRestore(infName, syncReason)
 			}
 		}
 	}
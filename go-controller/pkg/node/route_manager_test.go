@@ -12,10 +12,12 @@ import (
 	"github.com/onsi/ginkgo"
 	"github.com/onsi/gomega"
 	"github.com/vishvananda/netlink"
+	"k8s.io/client-go/tools/record"
 )
 
 var _ = ginkgo.Describe("Route Manager", func() {
 	var rm *routeManager
+	var recorder *record.FakeRecorder
 	var stopCh chan struct{}
 	var wg *sync.WaitGroup
 	var testNS ns.NetNS
@@ -50,6 +52,8 @@ var _ = ginkgo.Describe("Route Manager", func() {
 		syncPeriod := 10 * time.Millisecond
 		logAllActivity := true
 		rm = newRouteManager(wg, logAllActivity, syncPeriod)
+		recorder = record.NewFakeRecorder(100)
+		rm.recordRestoredRoutes(recorder, "node1")
 		err = testNS.Do(func(netNS ns.NetNS) error {
 			defer ginkgo.GinkgoRecover()
 			loLink, err = netlink.LinkByName(loLinkName)
@@ -178,6 +182,8 @@ var _ = ginkgo.Describe("Route Manager", func() {
 			gomega.Eventually(func() bool {
 				return doesRouteEntryExist(testNS, loLink, r)
 			}, time.Second).Should(gomega.BeTrue())
+			// the restoration is reported on the node
+			gomega.Eventually(recorder.Events, time.Second).Should(gomega.Receive(gomega.HavePrefix("Warning RestoredManagedRoutes")))
 		})
 
 		ginkgo.It("reapplies managed route that was removed (mtu, src IP)", func() {
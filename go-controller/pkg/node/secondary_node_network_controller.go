@@ -53,6 +53,11 @@ func (nc *SecondaryNodeNetworkController) Stop() {
 	}
 }
 
+// Reconfigure reconfigures the secondary network with the updated network information
+func (nc *SecondaryNodeNetworkController) Reconfigure(netInfo util.NetInfo) error {
+	return nc.NetInfo.Reconfigure(netInfo)
+}
+
 // Cleanup cleans up node entities for the given secondary network
 func (nc *SecondaryNodeNetworkController) Cleanup(netName string) error {
 	return nil
@@ -79,6 +79,12 @@ func getACLName(dbIDs *libovsdbops.DbObjectIDs) string {
 		aclName = "NP:" + dbIDs.GetObjectID(libovsdbops.ObjectNameKey) + ":" + dbIDs.GetObjectID(libovsdbops.PolicyDirectionKey)
 	case t.IsSameType(libovsdbops.ACLEgressFirewall):
 		aclName = "EF:" + dbIDs.GetObjectID(libovsdbops.ObjectNameKey) + ":" + dbIDs.GetObjectID(libovsdbops.RuleIndex)
+	case t.IsSameType(libovsdbops.ACLAdminNetworkPolicy):
+		aclName = "ANP:" + dbIDs.GetObjectID(libovsdbops.ObjectNameKey) + ":" + dbIDs.GetObjectID(libovsdbops.PolicyDirectionKey) +
+			":" + dbIDs.GetObjectID(libovsdbops.GressIdxKey)
+	case t.IsSameType(libovsdbops.ACLBaselineAdminNetworkPolicy):
+		aclName = "BANP:" + dbIDs.GetObjectID(libovsdbops.ObjectNameKey) + ":" + dbIDs.GetObjectID(libovsdbops.PolicyDirectionKey) +
+			":" + dbIDs.GetObjectID(libovsdbops.GressIdxKey)
 	}
 	return fmt.Sprintf("%.63s", aclName)
 }
@@ -110,7 +116,7 @@ func BuildACL(dbIDs *libovsdbops.DbObjectIDs, priority int, match, action string
 		priority,
 		match,
 		action,
-		types.OvnACLLoggingMeter,
+		getLogMeter(logLevels),
 		logSeverity,
 		log,
 		externalIDs,
@@ -148,6 +154,14 @@ func getLogSeverity(action string, aclLogging *ACLLoggingLevels) (log bool, seve
 	return log, severity
 }
 
+// getLogMeter returns the meter that rate limits the logged packets of ACLs
+func getLogMeter(aclLogging *ACLLoggingLevels) string {
+	if aclLogging != nil && aclLogging.Meter != "" {
+		return aclLogging.Meter
+	}
+	return types.OvnACLLoggingMeter
+}
+
 // UpdateACLLoggingWithPredicate finds all ACLs based on a given predicate, updates log settings,
 // then transacts these changes with a single transaction.
 func UpdateACLLoggingWithPredicate(nbClient libovsdbclient.Client, p func(*nbdb.ACL) bool, aclLogging *ACLLoggingLevels) error {
@@ -164,7 +178,7 @@ func UpdateACLLogging(nbClient libovsdbclient.Client, ACLs []*nbdb.ACL, aclLoggi
 	}
 	for i := range ACLs {
 		log, severity := getLogSeverity(ACLs[i].Action, aclLogging)
-		libovsdbops.SetACLLogging(ACLs[i], severity, log)
+		libovsdbops.SetACLLogging(ACLs[i], severity, log, getLogMeter(aclLogging))
 	}
 	ops, err := libovsdbops.UpdateACLsLoggingOps(nbClient, nil, ACLs...)
 	if err != nil {
@@ -112,8 +112,7 @@ func (asf *ovnAddressSetFactory) GetAddressSet(dbIDs *libovsdbops.DbObjectIDs) (
 	var (
 		v4set, v6set *ovnAddressSet
 	)
-	p := libovsdbops.GetPredicate[*nbdb.AddressSet](dbIDs, nil)
-	addrSetList, err := libovsdbops.FindAddressSetsWithPredicate(asf.nbClient, p)
+	addrSetList, err := libovsdbops.FindAddressSetsWithDbIDs(asf.nbClient, dbIDs, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error getting address sets: %w", err)
 	}
@@ -133,8 +132,7 @@ func (asf *ovnAddressSetFactory) GetAddressSet(dbIDs *libovsdbops.DbObjectIDs) (
 func (asf *ovnAddressSetFactory) forEachAddressSet(ownerController string, dbIDsType *libovsdbops.ObjectIDsType,
 	do func(*nbdb.AddressSet) error) error {
 	predIDs := libovsdbops.NewDbObjectIDs(dbIDsType, ownerController, nil)
-	p := libovsdbops.GetPredicate[*nbdb.AddressSet](predIDs, nil)
-	addrSetList, err := libovsdbops.FindAddressSetsWithPredicate(asf.nbClient, p)
+	addrSetList, err := libovsdbops.FindAddressSetsWithDbIDs(asf.nbClient, predIDs, nil)
 	if err != nil {
 		return fmt.Errorf("error reading address sets: %+v", err)
 	}
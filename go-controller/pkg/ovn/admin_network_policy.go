@@ -0,0 +1,879 @@
+package ovn
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	anpapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1alpha1"
+	anpinformer "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1alpha1/apis/informers/externalversions/adminnetworkpolicy/v1alpha1"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/libovsdbops"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	kapi "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	v1coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const (
+	maxAdminNetworkPolicyRetries = 10
+	// BaselineAdminNetworkPolicy is a singleton, only the instance with this name is implemented
+	defaultBaselineAdminNetworkPolicyName = "default"
+	// anpSubjectDirection is the PolicyDirectionKey of the address set holding the IPs of the
+	// pods an (Baseline)AdminNetworkPolicy applies to
+	anpSubjectDirection = "Subject"
+	anpSubjectIdx       = -1
+)
+
+// anpKey is the work queue key of an AdminNetworkPolicy or a BaselineAdminNetworkPolicy;
+// both resources are cluster scoped and share the queue.
+type anpKey struct {
+	baseline bool
+	name     string
+}
+
+func (k anpKey) String() string {
+	if k.baseline {
+		return "BaselineAdminNetworkPolicy " + k.name
+	}
+	return "AdminNetworkPolicy " + k.name
+}
+
+// adminNetworkPolicy is the common representation of AdminNetworkPolicies and
+// BaselineAdminNetworkPolicies used to build their ACLs.
+type adminNetworkPolicy struct {
+	key     anpKey
+	subject anpapi.AdminNetworkPolicySubject
+	rules   []*adminNetworkPolicyRule
+}
+
+type adminNetworkPolicyRule struct {
+	direction aclDirection
+	// index of the rule in the ingress or egress rules of the policy
+	idx      int
+	priority int
+	action   string
+	peers    []anpapi.AdminNetworkPolicyPeer
+	ports    []anpapi.AdminNetworkPolicyPort
+}
+
+func getANPRuleAction(action anpapi.AdminNetworkPolicyRuleAction) (string, error) {
+	switch action {
+	case anpapi.AdminNetworkPolicyRuleActionAllow:
+		return nbdb.ACLActionAllowRelated, nil
+	case anpapi.AdminNetworkPolicyRuleActionDeny:
+		return nbdb.ACLActionDrop, nil
+	case anpapi.AdminNetworkPolicyRuleActionPass:
+		// ACLs have no tiers, so there is no way to skip the remaining
+		// AdminNetworkPolicy ACLs while still evaluating the NetworkPolicy ones
+		return "", fmt.Errorf("action %s is not supported", action)
+	default:
+		return "", fmt.Errorf("unknown action %q", action)
+	}
+}
+
+func getBANPRuleAction(action anpapi.BaselineAdminNetworkPolicyRuleAction) (string, error) {
+	switch action {
+	case anpapi.BaselineAdminNetworkPolicyRuleActionAllow:
+		return nbdb.ACLActionAllowRelated, nil
+	case anpapi.BaselineAdminNetworkPolicyRuleActionDeny:
+		return nbdb.ACLActionDrop, nil
+	default:
+		return "", fmt.Errorf("unknown action %q", action)
+	}
+}
+
+func derefANPPorts(ports *[]anpapi.AdminNetworkPolicyPort) []anpapi.AdminNetworkPolicyPort {
+	if ports == nil {
+		return nil
+	}
+	return *ports
+}
+
+// newAdminNetworkPolicy validates an AdminNetworkPolicy and computes the ACL priorities of its rules.
+// AdminNetworkPolicy rules are above all the other ACLs, the lower the policy priority value
+// the higher the ACL priority.
+func newAdminNetworkPolicy(anp *anpapi.AdminNetworkPolicy) (*adminNetworkPolicy, error) {
+	if anp.Spec.Priority < 0 || anp.Spec.Priority > types.AdminNetworkPolicyMaxPriority {
+		return nil, fmt.Errorf("priority %d is not supported, it must be between 0 and %d",
+			anp.Spec.Priority, types.AdminNetworkPolicyMaxPriority)
+	}
+	if len(anp.Spec.Ingress) > types.AdminNetworkPolicyMaxRules || len(anp.Spec.Egress) > types.AdminNetworkPolicyMaxRules {
+		return nil, fmt.Errorf("cannot have more than %d ingress or egress rules", types.AdminNetworkPolicyMaxRules)
+	}
+	startPriority := types.AdminNetworkPolicyStartPriority - int(anp.Spec.Priority)*types.AdminNetworkPolicyMaxRules
+	policy := &adminNetworkPolicy{
+		key:     anpKey{name: anp.Name},
+		subject: anp.Spec.Subject,
+	}
+	for i, rule := range anp.Spec.Ingress {
+		action, err := getANPRuleAction(rule.Action)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ingress rule %d: %w", i, err)
+		}
+		policy.rules = append(policy.rules, &adminNetworkPolicyRule{
+			direction: aclIngress,
+			idx:       i,
+			priority:  startPriority - i,
+			action:    action,
+			peers:     rule.From,
+			ports:     derefANPPorts(rule.Ports),
+		})
+	}
+	for i, rule := range anp.Spec.Egress {
+		action, err := getANPRuleAction(rule.Action)
+		if err != nil {
+			return nil, fmt.Errorf("invalid egress rule %d: %w", i, err)
+		}
+		policy.rules = append(policy.rules, &adminNetworkPolicyRule{
+			direction: aclEgress,
+			idx:       i,
+			priority:  startPriority - i,
+			action:    action,
+			peers:     rule.To,
+			ports:     derefANPPorts(rule.Ports),
+		})
+	}
+	return policy, nil
+}
+
+// newBaselineAdminNetworkPolicy validates a BaselineAdminNetworkPolicy and computes the ACL
+// priorities of its rules, which are below the NetworkPolicy ones.
+func newBaselineAdminNetworkPolicy(banp *anpapi.BaselineAdminNetworkPolicy) (*adminNetworkPolicy, error) {
+	if banp.Name != defaultBaselineAdminNetworkPolicyName {
+		return nil, fmt.Errorf("name must be %s", defaultBaselineAdminNetworkPolicyName)
+	}
+	if len(banp.Spec.Ingress) > types.AdminNetworkPolicyMaxRules || len(banp.Spec.Egress) > types.AdminNetworkPolicyMaxRules {
+		return nil, fmt.Errorf("cannot have more than %d ingress or egress rules", types.AdminNetworkPolicyMaxRules)
+	}
+	policy := &adminNetworkPolicy{
+		key:     anpKey{baseline: true, name: banp.Name},
+		subject: banp.Spec.Subject,
+	}
+	for i, rule := range banp.Spec.Ingress {
+		action, err := getBANPRuleAction(rule.Action)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ingress rule %d: %w", i, err)
+		}
+		policy.rules = append(policy.rules, &adminNetworkPolicyRule{
+			direction: aclIngress,
+			idx:       i,
+			priority:  types.BaselineAdminNetworkPolicyStartPriority - i,
+			action:    action,
+			peers:     rule.From,
+			ports:     derefANPPorts(rule.Ports),
+		})
+	}
+	for i, rule := range banp.Spec.Egress {
+		action, err := getBANPRuleAction(rule.Action)
+		if err != nil {
+			return nil, fmt.Errorf("invalid egress rule %d: %w", i, err)
+		}
+		policy.rules = append(policy.rules, &adminNetworkPolicyRule{
+			direction: aclEgress,
+			idx:       i,
+			priority:  types.BaselineAdminNetworkPolicyStartPriority - i,
+			action:    action,
+			peers:     rule.To,
+			ports:     derefANPPorts(rule.Ports),
+		})
+	}
+	return policy, nil
+}
+
+func getANPIDsTypes(baseline bool) (aclIDsType, addrSetIDsType *libovsdbops.ObjectIDsType) {
+	if baseline {
+		return libovsdbops.ACLBaselineAdminNetworkPolicy, libovsdbops.AddressSetBaselineAdminNetworkPolicy
+	}
+	return libovsdbops.ACLAdminNetworkPolicy, libovsdbops.AddressSetAdminNetworkPolicy
+}
+
+func getANPACLDbIDs(key anpKey, direction aclDirection, idx int, controller string) *libovsdbops.DbObjectIDs {
+	aclIDsType, _ := getANPIDsTypes(key.baseline)
+	return libovsdbops.NewDbObjectIDs(aclIDsType, controller, map[libovsdbops.ExternalIDKey]string{
+		libovsdbops.ObjectNameKey:      key.name,
+		libovsdbops.PolicyDirectionKey: string(direction),
+		libovsdbops.GressIdxKey:        strconv.Itoa(idx),
+	})
+}
+
+func getANPAddrSetDbIDs(key anpKey, direction string, idx int, controller string) *libovsdbops.DbObjectIDs {
+	_, addrSetIDsType := getANPIDsTypes(key.baseline)
+	return libovsdbops.NewDbObjectIDs(addrSetIDsType, controller, map[libovsdbops.ExternalIDKey]string{
+		libovsdbops.ObjectNameKey:      key.name,
+		libovsdbops.PolicyDirectionKey: direction,
+		libovsdbops.GressIdxKey:        strconv.Itoa(idx),
+	})
+}
+
+// getANPPortProtocol returns the match protocol of a rule port, TCP if not set.
+func getANPPortProtocol(protocol kapi.Protocol) string {
+	if protocol == "" {
+		protocol = kapi.ProtocolTCP
+	}
+	return strings.ToLower(string(protocol))
+}
+
+// getANPPortsMatch returns the L4 match of the given rule ports, an empty match selects all ports.
+func getANPPortsMatch(ports []anpapi.AdminNetworkPolicyPort) string {
+	matches := []string{}
+	for _, port := range ports {
+		switch {
+		case port.PortNumber != nil:
+			protocol := getANPPortProtocol(port.PortNumber.Protocol)
+			matches = append(matches, fmt.Sprintf("(%s && %s.dst == %d)", protocol, protocol, port.PortNumber.Port))
+		case port.PortRange != nil:
+			protocol := getANPPortProtocol(port.PortRange.Protocol)
+			matches = append(matches, fmt.Sprintf("(%s && %s.dst >= %d && %s.dst <= %d)", protocol, protocol,
+				port.PortRange.Start, protocol, port.PortRange.End))
+		}
+	}
+	if len(matches) == 0 {
+		return ""
+	}
+	return "(" + strings.Join(matches, " || ") + ")"
+}
+
+// getANPRuleMatch returns the match of a rule ACL. Since the ACLs are applied to every node
+// switch, both the subject and the peers are matched on the pod IPs.
+func getANPRuleMatch(rule *adminNetworkPolicyRule, subjectV4, subjectV6, peersV4, peersV6 string) string {
+	subjectDir, peersDir := "dst", "src"
+	if rule.direction == aclEgress {
+		subjectDir, peersDir = "src", "dst"
+	}
+	match := getACLMatchAF(
+		fmt.Sprintf("ip4.%s == $%s", subjectDir, subjectV4),
+		fmt.Sprintf("ip6.%s == $%s", subjectDir, subjectV6),
+		config.IPv4Mode, config.IPv6Mode)
+	match += " && " + getACLMatchAF(
+		fmt.Sprintf("ip4.%s == $%s", peersDir, peersV4),
+		fmt.Sprintf("ip6.%s == $%s", peersDir, peersV6),
+		config.IPv4Mode, config.IPv6Mode)
+	if portsMatch := getANPPortsMatch(rule.ports); portsMatch != "" {
+		match += " && " + portsMatch
+	}
+	return match
+}
+
+// initAdminNetworkPolicyController initializes the AdminNetworkPolicy controller.
+func (oc *DefaultNetworkController) initAdminNetworkPolicyController(
+	anpInformer anpinformer.AdminNetworkPolicyInformer,
+	banpInformer anpinformer.BaselineAdminNetworkPolicyInformer,
+	podInformer v1coreinformers.PodInformer,
+	namespaceInformer v1coreinformers.NamespaceInformer) error {
+	klog.Info("Setting up event handlers for AdminNetworkPolicies")
+	oc.anpLister = anpInformer.Lister()
+	oc.anpSynced = anpInformer.Informer().HasSynced
+	oc.banpLister = banpInformer.Lister()
+	oc.banpSynced = banpInformer.Informer().HasSynced
+	oc.anpPodLister = podInformer.Lister()
+	oc.anpPodSynced = podInformer.Informer().HasSynced
+	oc.anpNamespaceLister = namespaceInformer.Lister()
+	oc.anpNamespaceSynced = namespaceInformer.Informer().HasSynced
+	oc.anpQueue = workqueue.NewNamedRateLimitingQueue(
+		workqueue.NewItemFastSlowRateLimiter(1*time.Second, 5*time.Second, 5),
+		"adminnetworkpolicy",
+	)
+
+	_, err := anpInformer.Informer().AddEventHandler(factory.WithUpdateHandlingForObjReplace(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { oc.onAdminNetworkPolicyEvent(obj, false) },
+		UpdateFunc: func(_, newObj interface{}) { oc.onAdminNetworkPolicyEvent(newObj, false) },
+		DeleteFunc: func(obj interface{}) { oc.onAdminNetworkPolicyEvent(obj, false) },
+	}))
+	if err != nil {
+		return fmt.Errorf("could not add Event Handler for anpInformer during adminNetworkPolicyController initialization, %w", err)
+	}
+	_, err = banpInformer.Informer().AddEventHandler(factory.WithUpdateHandlingForObjReplace(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { oc.onAdminNetworkPolicyEvent(obj, true) },
+		UpdateFunc: func(_, newObj interface{}) { oc.onAdminNetworkPolicyEvent(newObj, true) },
+		DeleteFunc: func(obj interface{}) { oc.onAdminNetworkPolicyEvent(obj, true) },
+	}))
+	if err != nil {
+		return fmt.Errorf("could not add Event Handler for banpInformer during adminNetworkPolicyController initialization, %w", err)
+	}
+	_, err = podInformer.Informer().AddEventHandler(factory.WithUpdateHandlingForObjReplace(cache.ResourceEventHandlerFuncs{
+		AddFunc:    oc.onAdminNetworkPolicyPodEvent,
+		UpdateFunc: oc.onAdminNetworkPolicyPodUpdate,
+		DeleteFunc: oc.onAdminNetworkPolicyPodEvent,
+	}))
+	if err != nil {
+		return fmt.Errorf("could not add Event Handler for podInformer during adminNetworkPolicyController initialization, %w", err)
+	}
+	_, err = namespaceInformer.Informer().AddEventHandler(factory.WithUpdateHandlingForObjReplace(cache.ResourceEventHandlerFuncs{
+		AddFunc:    oc.onAdminNetworkPolicyNamespaceEvent,
+		UpdateFunc: oc.onAdminNetworkPolicyNamespaceUpdate,
+		DeleteFunc: oc.onAdminNetworkPolicyNamespaceEvent,
+	}))
+	if err != nil {
+		return fmt.Errorf("could not add Event Handler for namespaceInformer during adminNetworkPolicyController initialization, %w", err)
+	}
+	return nil
+}
+
+func (oc *DefaultNetworkController) runAdminNetworkPolicyController(threadiness int, stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+
+	klog.Infof("Starting AdminNetworkPolicy Controller")
+
+	if !cache.WaitForNamedCacheSync("adminnetworkpolicy", stopCh,
+		oc.anpSynced, oc.banpSynced, oc.anpPodSynced, oc.anpNamespaceSynced) {
+		utilruntime.HandleError(fmt.Errorf("timed out waiting for caches to sync"))
+		klog.Infof("Synchronization failed")
+		return
+	}
+
+	klog.Infof("Repairing AdminNetworkPolicies")
+	if err := oc.repairAdminNetworkPolicies(); err != nil {
+		klog.Errorf("Failed to delete stale AdminNetworkPolicy entries: %v", err)
+	}
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < threadiness; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wait.Until(func() {
+				oc.runAdminNetworkPolicyWorker(wg)
+			}, time.Second, stopCh)
+		}()
+	}
+
+	// wait until we're told to stop
+	<-stopCh
+
+	klog.Infof("Shutting down AdminNetworkPolicy controller")
+	oc.anpQueue.ShutDown()
+
+	wg.Wait()
+}
+
+// onAdminNetworkPolicyEvent queues the AdminNetworkPolicy or BaselineAdminNetworkPolicy for processing.
+func (oc *DefaultNetworkController) onAdminNetworkPolicyEvent(obj interface{}, baseline bool) {
+	name, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("couldn't get key for object %+v: %v", obj, err))
+		return
+	}
+	oc.anpQueue.Add(anpKey{baseline: baseline, name: name})
+}
+
+// enqueueAllAdminNetworkPolicies queues all the policies for processing.
+func (oc *DefaultNetworkController) enqueueAllAdminNetworkPolicies() {
+	oc.enqueueAdminNetworkPolicies(nil)
+}
+
+// enqueueAdminNetworkPolicies queues the policies with a subject or peer selector for
+// which selects returns true, or all the policies if selects is nil. Invalid policies
+// have nothing to update and are not queued.
+func (oc *DefaultNetworkController) enqueueAdminNetworkPolicies(selects func(anpSelector) bool) {
+	anps, err := oc.anpLister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("couldn't list AdminNetworkPolicies: %v", err))
+		return
+	}
+	for _, anp := range anps {
+		if selects == nil {
+			oc.anpQueue.Add(anpKey{name: anp.Name})
+			continue
+		}
+		policy, err := newAdminNetworkPolicy(anp)
+		if err == nil && policy.hasSelector(selects) {
+			oc.anpQueue.Add(policy.key)
+		}
+	}
+	banps, err := oc.banpLister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("couldn't list BaselineAdminNetworkPolicies: %v", err))
+		return
+	}
+	for _, banp := range banps {
+		if selects == nil {
+			oc.anpQueue.Add(anpKey{baseline: true, name: banp.Name})
+			continue
+		}
+		policy, err := newBaselineAdminNetworkPolicy(banp)
+		if err == nil && policy.hasSelector(selects) {
+			oc.anpQueue.Add(policy.key)
+		}
+	}
+}
+
+// enqueueAdminNetworkPoliciesForPods queues the policies selecting any of the given pods
+// of a namespace, which may change the IPs in their address sets.
+func (oc *DefaultNetworkController) enqueueAdminNetworkPoliciesForPods(pods ...*kapi.Pod) {
+	namespace, err := oc.anpNamespaceLister.Get(pods[0].Namespace)
+	if err != nil {
+		// without the namespace labels, the policies selecting the pods can't be found
+		oc.enqueueAllAdminNetworkPolicies()
+		return
+	}
+	oc.enqueueAdminNetworkPolicies(func(s anpSelector) bool {
+		for _, pod := range pods {
+			if s.selects(namespace, pod) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// enqueueAdminNetworkPoliciesForNamespaces queues the policies selecting the pods of any
+// of the given namespaces.
+func (oc *DefaultNetworkController) enqueueAdminNetworkPoliciesForNamespaces(namespaces ...*kapi.Namespace) {
+	oc.enqueueAdminNetworkPolicies(func(s anpSelector) bool {
+		for _, namespace := range namespaces {
+			if s.selects(namespace, nil) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (oc *DefaultNetworkController) onAdminNetworkPolicyPodEvent(obj interface{}) {
+	pod, ok := obj.(*kapi.Pod)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("couldn't get object from tombstone %#v", obj))
+			return
+		}
+		pod, ok = tombstone.Obj.(*kapi.Pod)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("tombstone contained object that is not a Pod: %#v", tombstone.Obj))
+			return
+		}
+	}
+	oc.enqueueAdminNetworkPoliciesForPods(pod)
+}
+
+func (oc *DefaultNetworkController) onAdminNetworkPolicyPodUpdate(oldObj, newObj interface{}) {
+	oldPod := oldObj.(*kapi.Pod)
+	newPod := newObj.(*kapi.Pod)
+
+	if oldPod.ResourceVersion == newPod.ResourceVersion {
+		return
+	}
+	oldPodIPs, _ := util.GetPodIPsOfNetwork(oldPod, oc.NetInfo)
+	newPodIPs, _ := util.GetPodIPsOfNetwork(newPod, oc.NetInfo)
+	if labels.Equals(oldPod.Labels, newPod.Labels) &&
+		util.PodCompleted(oldPod) == util.PodCompleted(newPod) &&
+		len(oldPodIPs) == len(newPodIPs) {
+		return
+	}
+	oc.enqueueAdminNetworkPoliciesForPods(oldPod, newPod)
+}
+
+func (oc *DefaultNetworkController) onAdminNetworkPolicyNamespaceEvent(obj interface{}) {
+	namespace, ok := obj.(*kapi.Namespace)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("couldn't get object from tombstone %#v", obj))
+			return
+		}
+		namespace, ok = tombstone.Obj.(*kapi.Namespace)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("tombstone contained object that is not a Namespace: %#v", tombstone.Obj))
+			return
+		}
+	}
+	oc.enqueueAdminNetworkPoliciesForNamespaces(namespace)
+}
+
+func (oc *DefaultNetworkController) onAdminNetworkPolicyNamespaceUpdate(oldObj, newObj interface{}) {
+	oldNamespace := oldObj.(*kapi.Namespace)
+	newNamespace := newObj.(*kapi.Namespace)
+
+	if labels.Equals(oldNamespace.Labels, newNamespace.Labels) {
+		return
+	}
+	oc.enqueueAdminNetworkPoliciesForNamespaces(oldNamespace, newNamespace)
+}
+
+func (oc *DefaultNetworkController) runAdminNetworkPolicyWorker(wg *sync.WaitGroup) {
+	for oc.processNextAdminNetworkPolicyWorkItem(wg) {
+	}
+}
+
+func (oc *DefaultNetworkController) processNextAdminNetworkPolicyWorkItem(wg *sync.WaitGroup) bool {
+	wg.Add(1)
+	defer wg.Done()
+
+	key, quit := oc.anpQueue.Get()
+	if quit {
+		return false
+	}
+
+	defer oc.anpQueue.Done(key)
+
+	err := oc.syncAdminNetworkPolicy(key.(anpKey))
+	if err == nil {
+		oc.anpQueue.Forget(key)
+		return true
+	}
+
+	utilruntime.HandleError(fmt.Errorf("%v failed with : %v", key, err))
+
+	if oc.anpQueue.NumRequeues(key) < maxAdminNetworkPolicyRetries {
+		oc.anpQueue.AddRateLimited(key)
+		return true
+	}
+
+	oc.anpQueue.Forget(key)
+	return true
+}
+
+// repairAdminNetworkPolicies deletes the ACLs and address sets of the policies that were
+// deleted while ovnkube-master was not running.
+func (oc *DefaultNetworkController) repairAdminNetworkPolicies() error {
+	existing := map[anpKey]bool{}
+	anps, err := oc.anpLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, anp := range anps {
+		existing[anpKey{name: anp.Name}] = true
+	}
+	banps, err := oc.banpLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, banp := range banps {
+		existing[anpKey{baseline: true, name: banp.Name}] = true
+	}
+
+	for _, baseline := range []bool{false, true} {
+		aclIDsType, addrSetIDsType := getANPIDsTypes(baseline)
+		isStale := func(externalIDs map[string]string) bool {
+			return !existing[anpKey{baseline: baseline, name: externalIDs[libovsdbops.ObjectNameKey.String()]}]
+		}
+		predicateIDs := libovsdbops.NewDbObjectIDs(aclIDsType, oc.controllerName, nil)
+		aclPredicate := libovsdbops.GetPredicate[*nbdb.ACL](predicateIDs, func(acl *nbdb.ACL) bool {
+			return isStale(acl.ExternalIDs)
+		})
+		staleACLs, err := libovsdbops.FindACLsWithPredicate(oc.nbClient, aclPredicate)
+		if err != nil {
+			return err
+		}
+		err = libovsdbops.DeleteACLsFromPortGroups(oc.nbClient,
+			[]string{oc.getClusterPortGroupName(types.ClusterPortGroupNameBase)}, staleACLs...)
+		if err != nil {
+			return fmt.Errorf("failed to remove stale admin network policy acls: %v", err)
+		}
+
+		predicateIDs = libovsdbops.NewDbObjectIDs(addrSetIDsType, oc.controllerName, nil)
+		asPredicate := libovsdbops.GetPredicate[*nbdb.AddressSet](predicateIDs, func(as *nbdb.AddressSet) bool {
+			return isStale(as.ExternalIDs)
+		})
+		if err := libovsdbops.DeleteAddressSetsWithPredicate(oc.nbClient, asPredicate); err != nil {
+			return fmt.Errorf("failed to remove stale admin network policy address sets: %v", err)
+		}
+	}
+	return nil
+}
+
+func (oc *DefaultNetworkController) syncAdminNetworkPolicy(key anpKey) error {
+	startTime := time.Now()
+	klog.Infof("Processing sync for %s", key)
+	defer func() {
+		klog.V(4).Infof("Finished syncing %s: %v", key, time.Since(startTime))
+	}()
+
+	var policy *adminNetworkPolicy
+	if key.baseline {
+		banp, err := oc.banpLister.Get(key.name)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		if banp != nil {
+			policy, err = newBaselineAdminNetworkPolicy(banp)
+			if err != nil {
+				klog.Errorf("Ignoring %s: %v", key, err)
+			}
+		}
+	} else {
+		anp, err := oc.anpLister.Get(key.name)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		if anp != nil {
+			policy, err = newAdminNetworkPolicy(anp)
+			if err != nil {
+				klog.Errorf("Ignoring %s: %v", key, err)
+			}
+		}
+	}
+
+	if policy == nil {
+		// the policy was deleted or is invalid, remove everything that was created for it
+		return oc.deleteAdminNetworkPolicy(key)
+	}
+	return oc.ensureAdminNetworkPolicy(policy)
+}
+
+func (oc *DefaultNetworkController) ensureAdminNetworkPolicy(policy *adminNetworkPolicy) error {
+	subjectIPs, err := oc.getANPSubjectIPs(policy.subject)
+	if err != nil {
+		return err
+	}
+	subjectAddrSet, err := oc.addressSetFactory.NewAddressSet(
+		getANPAddrSetDbIDs(policy.key, anpSubjectDirection, anpSubjectIdx, oc.controllerName), subjectIPs)
+	if err != nil {
+		return fmt.Errorf("failed to ensure the subject address set of %s: %v", policy.key, err)
+	}
+	subjectV4, subjectV6 := subjectAddrSet.GetASHashNames()
+
+	acls := []*nbdb.ACL{}
+	// direction:index of the address sets in use
+	addrSets := sets.NewString(anpSubjectDirection + ":" + strconv.Itoa(anpSubjectIdx))
+	for _, rule := range policy.rules {
+		peerIPs, err := oc.getANPPeersIPs(rule.peers)
+		if err != nil {
+			return err
+		}
+		peersAddrSet, err := oc.addressSetFactory.NewAddressSet(
+			getANPAddrSetDbIDs(policy.key, string(rule.direction), rule.idx, oc.controllerName), peerIPs)
+		if err != nil {
+			return fmt.Errorf("failed to ensure the peers address set of %s %s rule %d: %v",
+				policy.key, rule.direction, rule.idx, err)
+		}
+		addrSets.Insert(string(rule.direction) + ":" + strconv.Itoa(rule.idx))
+		peersV4, peersV6 := peersAddrSet.GetASHashNames()
+
+		acl := BuildACL(
+			getANPACLDbIDs(policy.key, rule.direction, rule.idx, oc.controllerName),
+			rule.priority,
+			getANPRuleMatch(rule, subjectV4, subjectV6, peersV4, peersV6),
+			rule.action,
+			nil,
+			aclDirectionToACLPipeline(rule.direction),
+		)
+		acls = append(acls, acl)
+	}
+
+	staleACLs, err := oc.findAdminNetworkPolicyACLs(policy.key, func(acl *nbdb.ACL) bool {
+		for _, newACL := range acls {
+			if acl.ExternalIDs[libovsdbops.PrimaryIDKey.String()] == newACL.ExternalIDs[libovsdbops.PrimaryIDKey.String()] {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	pgName := oc.getClusterPortGroupName(types.ClusterPortGroupNameBase)
+	ops, err := libovsdbops.CreateOrUpdateACLsOps(oc.nbClient, nil, acls...)
+	if err != nil {
+		return fmt.Errorf("failed to create ACLs of %s: %v", policy.key, err)
+	}
+	// Applying ACLs on types.ClusterPortGroupName is equivalent to applying on every node switch, since
+	// types.ClusterPortGroupName contains management port from every switch.
+	ops, err = libovsdbops.AddACLsToPortGroupOps(oc.nbClient, ops, pgName, acls...)
+	if err != nil {
+		return fmt.Errorf("failed to add ACLs of %s to port group %s: %v", policy.key, pgName, err)
+	}
+	ops, err = libovsdbops.DeleteACLsFromPortGroupOps(oc.nbClient, ops, pgName, staleACLs...)
+	if err != nil {
+		return fmt.Errorf("failed to remove stale ACLs of %s from port group %s: %v", policy.key, pgName, err)
+	}
+	if _, err = libovsdbops.TransactAndCheck(oc.nbClient, ops); err != nil {
+		return fmt.Errorf("failed to transact ACLs of %s: %v", policy.key, err)
+	}
+
+	// the address sets of removed rules are no longer referenced
+	return oc.deleteAdminNetworkPolicyAddressSets(policy.key, func(as *nbdb.AddressSet) bool {
+		return !addrSets.Has(as.ExternalIDs[libovsdbops.PolicyDirectionKey.String()] + ":" +
+			as.ExternalIDs[libovsdbops.GressIdxKey.String()])
+	})
+}
+
+func (oc *DefaultNetworkController) deleteAdminNetworkPolicy(key anpKey) error {
+	acls, err := oc.findAdminNetworkPolicyACLs(key, nil)
+	if err != nil {
+		return err
+	}
+	err = libovsdbops.DeleteACLsFromPortGroups(oc.nbClient,
+		[]string{oc.getClusterPortGroupName(types.ClusterPortGroupNameBase)}, acls...)
+	if err != nil {
+		return fmt.Errorf("failed to delete ACLs of %s: %v", key, err)
+	}
+	return oc.deleteAdminNetworkPolicyAddressSets(key, nil)
+}
+
+func (oc *DefaultNetworkController) findAdminNetworkPolicyACLs(key anpKey, f func(*nbdb.ACL) bool) ([]*nbdb.ACL, error) {
+	aclIDsType, _ := getANPIDsTypes(key.baseline)
+	predicateIDs := libovsdbops.NewDbObjectIDs(aclIDsType, oc.controllerName, map[libovsdbops.ExternalIDKey]string{
+		libovsdbops.ObjectNameKey: key.name,
+	})
+	acls, err := libovsdbops.FindACLsWithDbIDs(oc.nbClient, predicateIDs, f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find ACLs of %s: %v", key, err)
+	}
+	return acls, nil
+}
+
+func (oc *DefaultNetworkController) deleteAdminNetworkPolicyAddressSets(key anpKey, f func(*nbdb.AddressSet) bool) error {
+	_, addrSetIDsType := getANPIDsTypes(key.baseline)
+	predicateIDs := libovsdbops.NewDbObjectIDs(addrSetIDsType, oc.controllerName, map[libovsdbops.ExternalIDKey]string{
+		libovsdbops.ObjectNameKey: key.name,
+	})
+	asPredicate := libovsdbops.GetPredicate[*nbdb.AddressSet](predicateIDs, f)
+	if err := libovsdbops.DeleteAddressSetsWithPredicate(oc.nbClient, asPredicate); err != nil {
+		return fmt.Errorf("failed to delete address sets of %s: %v", key, err)
+	}
+	return nil
+}
+
+// anpSelector is a subject or peer selector of a policy: the pods matching podSelector,
+// or all the pods if it is nil, in the namespaces matching namespaceSelector.
+type anpSelector struct {
+	namespaceSelector *metav1.LabelSelector
+	podSelector       *metav1.LabelSelector
+}
+
+// selects returns true if the selector selects the given pod of the namespace, or any
+// pod of the namespace if pod is nil. Like in getANPPodIPs, a nil namespaceSelector
+// matches no namespace; invalid selectors select nothing.
+func (s anpSelector) selects(namespace *kapi.Namespace, pod *kapi.Pod) bool {
+	nsSel, err := metav1.LabelSelectorAsSelector(s.namespaceSelector)
+	if err != nil || !nsSel.Matches(labels.Set(namespace.Labels)) {
+		return false
+	}
+	if pod == nil || s.podSelector == nil {
+		return true
+	}
+	podSel, err := metav1.LabelSelectorAsSelector(s.podSelector)
+	return err == nil && podSel.Matches(labels.Set(pod.Labels))
+}
+
+// selectors returns the selectors of the subject and of the rule peers of the policy.
+func (policy *adminNetworkPolicy) selectors() []anpSelector {
+	selectors := []anpSelector{}
+	switch {
+	case policy.subject.Namespaces != nil:
+		selectors = append(selectors, anpSelector{namespaceSelector: policy.subject.Namespaces})
+	case policy.subject.Pods != nil:
+		selectors = append(selectors, anpSelector{
+			namespaceSelector: &policy.subject.Pods.NamespaceSelector,
+			podSelector:       &policy.subject.Pods.PodSelector,
+		})
+	}
+	for _, rule := range policy.rules {
+		for _, peer := range rule.peers {
+			switch {
+			case peer.Namespaces != nil:
+				selectors = append(selectors, anpSelector{namespaceSelector: peer.Namespaces.NamespaceSelector})
+			case peer.Pods != nil:
+				selectors = append(selectors, anpSelector{
+					namespaceSelector: peer.Pods.Namespaces.NamespaceSelector,
+					podSelector:       &peer.Pods.PodSelector,
+				})
+			}
+		}
+	}
+	return selectors
+}
+
+// hasSelector returns true if selects returns true for any selector of the policy.
+func (policy *adminNetworkPolicy) hasSelector(selects func(anpSelector) bool) bool {
+	for _, selector := range policy.selectors() {
+		if selects(selector) {
+			return true
+		}
+	}
+	return false
+}
+
+// getANPSubjectIPs returns the IPs of the pods selected by the given subject.
+func (oc *DefaultNetworkController) getANPSubjectIPs(subject anpapi.AdminNetworkPolicySubject) ([]net.IP, error) {
+	switch {
+	case subject.Namespaces != nil:
+		return oc.getANPPodIPs(subject.Namespaces, nil)
+	case subject.Pods != nil:
+		return oc.getANPPodIPs(&subject.Pods.NamespaceSelector, &subject.Pods.PodSelector)
+	}
+	return nil, nil
+}
+
+// getANPPeersIPs returns the IPs of the pods selected by the given peers.
+func (oc *DefaultNetworkController) getANPPeersIPs(peers []anpapi.AdminNetworkPolicyPeer) ([]net.IP, error) {
+	ips := []net.IP{}
+	for _, peer := range peers {
+		var peerIPs []net.IP
+		var err error
+		switch {
+		case peer.Namespaces != nil:
+			peerIPs, err = oc.getANPPodIPs(peer.Namespaces.NamespaceSelector, nil)
+		case peer.Pods != nil:
+			peerIPs, err = oc.getANPPodIPs(peer.Pods.Namespaces.NamespaceSelector, &peer.Pods.PodSelector)
+		}
+		if err != nil {
+			return nil, err
+		}
+		ips = append(ips, peerIPs...)
+	}
+	return ips, nil
+}
+
+// getANPPodIPs returns the IPs of the pods matching podSelector, or all the pods if it
+// is nil, in the namespaces matching namespaceSelector. A nil namespaceSelector
+// matches no namespace.
+func (oc *DefaultNetworkController) getANPPodIPs(namespaceSelector, podSelector *metav1.LabelSelector) ([]net.IP, error) {
+	nsSel, err := metav1.LabelSelectorAsSelector(namespaceSelector)
+	if err != nil {
+		return nil, err
+	}
+	podSel := labels.Everything()
+	if podSelector != nil {
+		podSel, err = metav1.LabelSelectorAsSelector(podSelector)
+		if err != nil {
+			return nil, err
+		}
+	}
+	namespaces, err := oc.anpNamespaceLister.List(nsSel)
+	if err != nil {
+		return nil, err
+	}
+	ips := []net.IP{}
+	for _, namespace := range namespaces {
+		pods, err := oc.anpPodLister.Pods(namespace.Name).List(podSel)
+		if err != nil {
+			return nil, err
+		}
+		for _, pod := range pods {
+			// we don't handle HostNetworked or completed pods
+			if util.PodWantsHostNetwork(pod) || util.PodCompleted(pod) {
+				continue
+			}
+			podIPs, err := util.GetPodIPsOfNetwork(pod, oc.NetInfo)
+			if errors.Is(err, util.ErrNoPodIPFound) {
+				// the policies are synced again when the pod gets its IPs
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			ips = append(ips, podIPs...)
+		}
+	}
+	return ips, nil
+}
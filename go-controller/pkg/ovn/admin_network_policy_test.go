@@ -0,0 +1,423 @@
+package ovn
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	anpapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1alpha1"
+	anplisters "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1alpha1/apis/listers/adminnetworkpolicy/v1alpha1"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
+	addressset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/address_set"
+	libovsdbtest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing/libovsdb"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v2"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+func newNamespacesPeer(namespace string) anpapi.AdminNetworkPolicyPeer {
+	return anpapi.AdminNetworkPolicyPeer{
+		Namespaces: &anpapi.NamespacedPeer{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"name": namespace}},
+		},
+	}
+}
+
+var _ = ginkgo.Describe("OVN AdminNetworkPolicy Operations", func() {
+	var (
+		app            *cli.App
+		fakeOVN        *FakeOVN
+		controllerName = DefaultNetworkControllerName
+	)
+
+	const (
+		node1Name = "node1"
+		pod1IP    = "10.128.1.3"
+		pod2IP    = "10.128.2.3"
+	)
+
+	ginkgo.BeforeEach(func() {
+		// Restore global default values before each testcase
+		config.PrepareTestConfig()
+		config.OVNKubernetesFeature.EnableAdminNetworkPolicy = true
+		config.IPv4Mode = true
+
+		app = cli.NewApp()
+		app.Name = "test"
+		app.Flags = config.Flags
+
+		fakeOVN = NewFakeOVN(false)
+	})
+
+	ginkgo.AfterEach(func() {
+		fakeOVN.shutdown()
+	})
+
+	ginkgo.It("creates and deletes the ACLs and address sets of AdminNetworkPolicies", func() {
+		app.Action = func(ctx *cli.Context) error {
+			clusterPortGroup := newClusterPortGroup()
+			fakeOVN.startWithDBSetup(libovsdbtest.TestSetup{NBData: []libovsdbtest.TestData{clusterPortGroup}},
+				&v1.NamespaceList{
+					Items: []v1.Namespace{*newNamespace("ns1"), *newNamespace("ns2")},
+				},
+				&v1.PodList{
+					Items: []v1.Pod{*newPod("ns1", "pod1", node1Name, pod1IP), *newPod("ns2", "pod2", node1Name, pod2IP)},
+				},
+			)
+			anp := &anpapi.AdminNetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "deny-ns2"},
+				Spec: anpapi.AdminNetworkPolicySpec{
+					Priority: 5,
+					Subject: anpapi.AdminNetworkPolicySubject{
+						Namespaces: &metav1.LabelSelector{MatchLabels: map[string]string{"name": "ns1"}},
+					},
+					Ingress: []anpapi.AdminNetworkPolicyIngressRule{
+						{
+							Name:   "deny-ns2-http",
+							Action: anpapi.AdminNetworkPolicyRuleActionDeny,
+							From:   []anpapi.AdminNetworkPolicyPeer{newNamespacesPeer("ns2")},
+							Ports: &[]anpapi.AdminNetworkPolicyPort{
+								{PortNumber: &anpapi.Port{Protocol: v1.ProtocolTCP, Port: 80}},
+							},
+						},
+					},
+				},
+			}
+			_, err := fakeOVN.fakeClient.ANPClient.PolicyV1alpha1().AdminNetworkPolicies().Create(context.TODO(), anp, metav1.CreateOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			fakeOVN.InitAndRunAdminNetworkPolicyController()
+
+			key := anpKey{name: anp.Name}
+			subjectAS, _ := addressset.GetDbObjsForAS(getANPAddrSetDbIDs(key, anpSubjectDirection, anpSubjectIdx, controllerName),
+				[]net.IP{net.ParseIP(pod1IP)})
+			peersAS, _ := addressset.GetDbObjsForAS(getANPAddrSetDbIDs(key, string(aclIngress), 0, controllerName),
+				[]net.IP{net.ParseIP(pod2IP)})
+			acl := BuildACL(
+				getANPACLDbIDs(key, aclIngress, 0, controllerName),
+				types.AdminNetworkPolicyStartPriority-5*types.AdminNetworkPolicyMaxRules,
+				fmt.Sprintf("ip4.dst == $%s && ip4.src == $%s && ((tcp && tcp.dst == 80))", subjectAS.Name, peersAS.Name),
+				nbdb.ACLActionDrop,
+				nil,
+				lportIngress,
+			)
+			acl.UUID = "anp-acl-UUID"
+			clusterPortGroup.ACLs = []string{acl.UUID}
+			gomega.Eventually(fakeOVN.nbClient).Should(libovsdbtest.HaveDataIgnoringUUIDs(
+				[]libovsdbtest.TestData{clusterPortGroup, acl, subjectAS, peersAS}))
+
+			// a new pod in the peer namespace is added to the peers address set
+			_, err = fakeOVN.fakeClient.KubeClient.CoreV1().Pods("ns2").Create(context.TODO(),
+				newPod("ns2", "pod3", node1Name, "10.128.2.4"), metav1.CreateOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			peersAS.Addresses = []string{pod2IP, "10.128.2.4"}
+			gomega.Eventually(fakeOVN.nbClient).Should(libovsdbtest.HaveDataIgnoringUUIDs(
+				[]libovsdbtest.TestData{clusterPortGroup, acl, subjectAS, peersAS}))
+
+			err = fakeOVN.fakeClient.ANPClient.PolicyV1alpha1().AdminNetworkPolicies().Delete(context.TODO(), anp.Name, metav1.DeleteOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			clusterPortGroup.ACLs = []string{}
+			// this ACL will be deleted when test server starts deleting dereferenced ACLs
+			gomega.Eventually(fakeOVN.nbClient).Should(libovsdbtest.HaveDataIgnoringUUIDs(
+				[]libovsdbtest.TestData{clusterPortGroup, acl}))
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("implements the default BaselineAdminNetworkPolicy and ignores policies with Pass rules", func() {
+		app.Action = func(ctx *cli.Context) error {
+			clusterPortGroup := newClusterPortGroup()
+			fakeOVN.startWithDBSetup(libovsdbtest.TestSetup{NBData: []libovsdbtest.TestData{clusterPortGroup}},
+				&v1.NamespaceList{
+					Items: []v1.Namespace{*newNamespace("ns1"), *newNamespace("ns2")},
+				},
+				&v1.PodList{
+					Items: []v1.Pod{*newPod("ns1", "pod1", node1Name, pod1IP), *newPod("ns2", "pod2", node1Name, pod2IP)},
+				},
+				&anpapi.AdminNetworkPolicyList{
+					Items: []anpapi.AdminNetworkPolicy{
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: "pass-ns2"},
+							Spec: anpapi.AdminNetworkPolicySpec{
+								Priority: 1,
+								Subject: anpapi.AdminNetworkPolicySubject{
+									Namespaces: &metav1.LabelSelector{MatchLabels: map[string]string{"name": "ns1"}},
+								},
+								Egress: []anpapi.AdminNetworkPolicyEgressRule{
+									{
+										Action: anpapi.AdminNetworkPolicyRuleActionPass,
+										To:     []anpapi.AdminNetworkPolicyPeer{newNamespacesPeer("ns2")},
+									},
+								},
+							},
+						},
+					},
+				},
+				&anpapi.BaselineAdminNetworkPolicyList{
+					Items: []anpapi.BaselineAdminNetworkPolicy{
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: defaultBaselineAdminNetworkPolicyName},
+							Spec: anpapi.BaselineAdminNetworkPolicySpec{
+								Subject: anpapi.AdminNetworkPolicySubject{
+									Pods: &anpapi.NamespacedPodSubject{
+										NamespaceSelector: metav1.LabelSelector{},
+										PodSelector:       metav1.LabelSelector{MatchLabels: map[string]string{"name": "pod1"}},
+									},
+								},
+								Egress: []anpapi.BaselineAdminNetworkPolicyEgressRule{
+									{
+										Action: anpapi.BaselineAdminNetworkPolicyRuleActionAllow,
+										To:     []anpapi.AdminNetworkPolicyPeer{newNamespacesPeer("ns2")},
+									},
+								},
+							},
+						},
+					},
+				},
+			)
+
+			fakeOVN.InitAndRunAdminNetworkPolicyController()
+
+			key := anpKey{baseline: true, name: defaultBaselineAdminNetworkPolicyName}
+			subjectAS, _ := addressset.GetDbObjsForAS(getANPAddrSetDbIDs(key, anpSubjectDirection, anpSubjectIdx, controllerName),
+				[]net.IP{net.ParseIP(pod1IP)})
+			peersAS, _ := addressset.GetDbObjsForAS(getANPAddrSetDbIDs(key, string(aclEgress), 0, controllerName),
+				[]net.IP{net.ParseIP(pod2IP)})
+			acl := BuildACL(
+				getANPACLDbIDs(key, aclEgress, 0, controllerName),
+				types.BaselineAdminNetworkPolicyStartPriority,
+				fmt.Sprintf("ip4.src == $%s && ip4.dst == $%s", subjectAS.Name, peersAS.Name),
+				nbdb.ACLActionAllowRelated,
+				nil,
+				lportEgressAfterLB,
+			)
+			acl.UUID = "banp-acl-UUID"
+			clusterPortGroup.ACLs = []string{acl.UUID}
+			// nothing is created for the AdminNetworkPolicy with a Pass rule
+			gomega.Eventually(fakeOVN.nbClient).Should(libovsdbtest.HaveDataIgnoringUUIDs(
+				[]libovsdbtest.TestData{clusterPortGroup, acl, subjectAS, peersAS}))
+			gomega.Consistently(fakeOVN.nbClient).Should(libovsdbtest.HaveDataIgnoringUUIDs(
+				[]libovsdbtest.TestData{clusterPortGroup, acl, subjectAS, peersAS}))
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+})
+
+func (o *FakeOVN) InitAndRunAdminNetworkPolicyController() {
+	klog.Warningf("#### [%p] INIT AdminNetworkPolicy", o)
+	err := o.controller.initAdminNetworkPolicyController(o.watcher.AdminNetworkPolicyInformer(),
+		o.watcher.BaselineAdminNetworkPolicyInformer(), o.watcher.PodCoreInformer(), o.watcher.NamespaceCoreInformer())
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	o.anpWg.Add(1)
+	go func() {
+		defer o.anpWg.Done()
+		o.controller.runAdminNetworkPolicyController(1, o.stopChan)
+	}()
+}
+
+func TestEnqueueAdminNetworkPolicies(t *testing.T) {
+	anpIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	banpIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	namespaceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	podSubject := anpapi.AdminNetworkPolicySubject{
+		Pods: &anpapi.NamespacedPodSubject{
+			NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"name": "ns1"}},
+			PodSelector:       metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		},
+	}
+	for _, obj := range []interface{}{
+		&anpapi.AdminNetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "subject-web"},
+			Spec:       anpapi.AdminNetworkPolicySpec{Priority: 1, Subject: podSubject},
+		},
+		&anpapi.AdminNetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "peer-ns2"},
+			Spec: anpapi.AdminNetworkPolicySpec{
+				Priority: 2,
+				Subject: anpapi.AdminNetworkPolicySubject{
+					Namespaces: &metav1.LabelSelector{MatchLabels: map[string]string{"name": "ns3"}},
+				},
+				Egress: []anpapi.AdminNetworkPolicyEgressRule{{
+					Action: anpapi.AdminNetworkPolicyRuleActionDeny,
+					To:     []anpapi.AdminNetworkPolicyPeer{newNamespacesPeer("ns2")},
+				}},
+			},
+		},
+		// invalid policies are never queued
+		&anpapi.AdminNetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "pass-ns2"},
+			Spec: anpapi.AdminNetworkPolicySpec{
+				Priority: 3,
+				Subject: anpapi.AdminNetworkPolicySubject{
+					Namespaces: &metav1.LabelSelector{MatchLabels: map[string]string{"name": "ns2"}},
+				},
+				Egress: []anpapi.AdminNetworkPolicyEgressRule{{
+					Action: anpapi.AdminNetworkPolicyRuleActionPass,
+					To:     []anpapi.AdminNetworkPolicyPeer{newNamespacesPeer("ns2")},
+				}},
+			},
+		},
+	} {
+		if err := anpIndexer.Add(obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := banpIndexer.Add(&anpapi.BaselineAdminNetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: defaultBaselineAdminNetworkPolicyName},
+		Spec: anpapi.BaselineAdminNetworkPolicySpec{
+			Subject: anpapi.AdminNetworkPolicySubject{
+				Namespaces: &metav1.LabelSelector{MatchLabels: map[string]string{"name": "ns3"}},
+			},
+			Ingress: []anpapi.BaselineAdminNetworkPolicyIngressRule{{
+				Action: anpapi.BaselineAdminNetworkPolicyRuleActionDeny,
+				From: []anpapi.AdminNetworkPolicyPeer{{
+					Pods: &anpapi.NamespacedPodPeer{
+						Namespaces:  anpapi.NamespacedPeer{NamespaceSelector: &metav1.LabelSelector{}},
+						PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+					},
+				}},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ns := range []string{"ns1", "ns2", "ns3"} {
+		if err := namespaceIndexer.Add(newNamespace(ns)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	podWithLabels := func(namespace string, podLabels map[string]string) *v1.Pod {
+		pod := newPod(namespace, "pod", "node1", "10.128.1.3")
+		pod.Labels = podLabels
+		return pod
+	}
+	anp := func(name string) anpKey { return anpKey{name: name} }
+	banp := anpKey{baseline: true, name: defaultBaselineAdminNetworkPolicyName}
+
+	tests := []struct {
+		desc     string
+		enqueue  func(oc *DefaultNetworkController)
+		expected []anpKey
+	}{
+		{
+			desc: "pod selected by a subject",
+			enqueue: func(oc *DefaultNetworkController) {
+				oc.enqueueAdminNetworkPoliciesForPods(podWithLabels("ns1", map[string]string{"app": "web"}))
+			},
+			expected: []anpKey{anp("subject-web")},
+		},
+		{
+			desc: "pod not selected by any policy",
+			enqueue: func(oc *DefaultNetworkController) {
+				oc.enqueueAdminNetworkPoliciesForPods(podWithLabels("ns1", map[string]string{"app": "other"}))
+			},
+		},
+		{
+			desc: "pod selected by a peer before a label change",
+			enqueue: func(oc *DefaultNetworkController) {
+				oc.enqueueAdminNetworkPoliciesForPods(podWithLabels("ns1", map[string]string{"app": "db"}),
+					podWithLabels("ns1", map[string]string{"app": "other"}))
+			},
+			expected: []anpKey{banp},
+		},
+		{
+			desc: "pod in a namespace selected by a peer",
+			enqueue: func(oc *DefaultNetworkController) {
+				oc.enqueueAdminNetworkPoliciesForPods(podWithLabels("ns2", nil))
+			},
+			expected: []anpKey{anp("peer-ns2")},
+		},
+		{
+			desc: "pod in an unknown namespace",
+			enqueue: func(oc *DefaultNetworkController) {
+				oc.enqueueAdminNetworkPoliciesForPods(podWithLabels("deleted", nil))
+			},
+			expected: []anpKey{anp("subject-web"), anp("peer-ns2"), anp("pass-ns2"), banp},
+		},
+		{
+			desc: "namespace selected by subjects and peers",
+			enqueue: func(oc *DefaultNetworkController) {
+				oc.enqueueAdminNetworkPoliciesForNamespaces(newNamespace("ns3"))
+			},
+			expected: []anpKey{anp("peer-ns2"), banp},
+		},
+		{
+			desc: "namespace selected by a pod selector",
+			enqueue: func(oc *DefaultNetworkController) {
+				oc.enqueueAdminNetworkPoliciesForNamespaces(newNamespace("ns1"))
+			},
+			expected: []anpKey{anp("subject-web"), banp},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			oc := &DefaultNetworkController{
+				anpLister:          anplisters.NewAdminNetworkPolicyLister(anpIndexer),
+				banpLister:         anplisters.NewBaselineAdminNetworkPolicyLister(banpIndexer),
+				anpNamespaceLister: corev1listers.NewNamespaceLister(namespaceIndexer),
+				anpQueue:           workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+			}
+			defer oc.anpQueue.ShutDown()
+			tc.enqueue(oc)
+			queued := []anpKey{}
+			for oc.anpQueue.Len() > 0 {
+				key, _ := oc.anpQueue.Get()
+				queued = append(queued, key.(anpKey))
+				oc.anpQueue.Done(key)
+			}
+			assert.ElementsMatch(t, tc.expected, queued)
+		})
+	}
+}
+
+func TestGetANPPortsMatch(t *testing.T) {
+	tests := []struct {
+		desc     string
+		ports    []anpapi.AdminNetworkPolicyPort
+		expected string
+	}{
+		{
+			desc:     "no ports",
+			expected: "",
+		},
+		{
+			desc: "port numbers",
+			ports: []anpapi.AdminNetworkPolicyPort{
+				{PortNumber: &anpapi.Port{Protocol: v1.ProtocolUDP, Port: 53}},
+				{PortNumber: &anpapi.Port{Port: 80}},
+			},
+			expected: "((udp && udp.dst == 53) || (tcp && tcp.dst == 80))",
+		},
+		{
+			desc: "port range",
+			ports: []anpapi.AdminNetworkPolicyPort{
+				{PortRange: &anpapi.PortRange{Start: 8080, End: 8090}},
+			},
+			expected: "((tcp && tcp.dst >= 8080 && tcp.dst <= 8090))",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.expected, getANPPortsMatch(tc.ports))
+		})
+	}
+}
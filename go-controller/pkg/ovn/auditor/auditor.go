@@ -0,0 +1,151 @@
+// Package auditor detects drift between the OVN northbound database and the
+// state the controllers expect to have programmed, e.g. rows left behind by a
+// missed delete event or mutated by an external tool. Controllers create an
+// Auditor with a check per kind of row; every audit runs all the checks and
+// reports the rows that are missing, stale or modified, without fixing
+// anything.
+package auditor
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// Check returns the rows of one kind that are expected in the northbound
+// database and the ones that are actually there. Both maps are keyed by an
+// identifier that is stable across restarts, e.g. the row name, and hold a
+// fingerprint of the columns the owning controller programs, or an empty
+// string when only the existence of the row is audited.
+type Check func() (expected, actual map[string]string, err error)
+
+// Diff is the result of a check
+type Diff struct {
+	Check string
+	// Missing holds the keys of the expected rows that are not in the database
+	Missing []string
+	// Stale holds the keys of the rows in the database that are not expected
+	Stale []string
+	// Modified holds the keys of the rows whose fingerprint differs from the
+	// expected one
+	Modified []string
+}
+
+// Empty returns true if no drift was found
+func (d *Diff) Empty() bool {
+	return len(d.Missing) == 0 && len(d.Stale) == 0 && len(d.Modified) == 0
+}
+
+func (d *Diff) String() string {
+	return fmt.Sprintf("%s: missing %v, stale %v, modified %v", d.Check, d.Missing, d.Stale, d.Modified)
+}
+
+// Compare computes the diff between the expected and actual rows of a check
+func Compare(check string, expected, actual map[string]string) *Diff {
+	diff := &Diff{Check: check}
+	for key, fingerprint := range expected {
+		actualFingerprint, ok := actual[key]
+		switch {
+		case !ok:
+			diff.Missing = append(diff.Missing, key)
+		case actualFingerprint != fingerprint:
+			diff.Modified = append(diff.Modified, key)
+		}
+	}
+	for key := range actual {
+		if _, ok := expected[key]; !ok {
+			diff.Stale = append(diff.Stale, key)
+		}
+	}
+	sort.Strings(diff.Missing)
+	sort.Strings(diff.Stale)
+	sort.Strings(diff.Modified)
+	return diff
+}
+
+// Auditor runs a set of checks on demand or periodically
+type Auditor struct {
+	checks  map[string]Check
+	logDiff bool
+	record  func(*Diff)
+}
+
+// New returns an Auditor running the given checks, keyed by name. Every diff
+// is passed to recordDiff, if set, and the non empty ones are logged if
+// logDiff is set.
+func New(checks map[string]Check, logDiff bool, recordDiff func(*Diff)) *Auditor {
+	return &Auditor{checks: checks, logDiff: logDiff, record: recordDiff}
+}
+
+// Audit runs all the checks and returns their diffs sorted by check name.
+// Checks that fail are skipped and reported in the returned error.
+func (a *Auditor) Audit() ([]*Diff, error) {
+	names := make([]string, 0, len(a.checks))
+	for name := range a.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	diffs := make([]*Diff, 0, len(names))
+	var failed []string
+	for _, name := range names {
+		expected, actual, err := a.checks[name]()
+		if err != nil {
+			klog.Errorf("NB audit check %s failed: %v", name, err)
+			failed = append(failed, name)
+			continue
+		}
+		diff := Compare(name, expected, actual)
+		if a.logDiff && !diff.Empty() {
+			klog.Warningf("NB audit found drift in %s", diff)
+		}
+		if a.record != nil {
+			a.record(diff)
+		}
+		diffs = append(diffs, diff)
+	}
+	if len(failed) > 0 {
+		return diffs, fmt.Errorf("NB audit checks %s failed", strings.Join(failed, ", "))
+	}
+	return diffs, nil
+}
+
+// Run audits the northbound database every interval until stopCh is closed
+func (a *Auditor) Run(interval time.Duration, stopCh <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			start := time.Now()
+			if _, err := a.Audit(); err != nil {
+				klog.Error(err)
+			}
+			klog.V(5).Infof("NB audit took %v", time.Since(start))
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// ServeHTTP serves on demand audits: a GET runs all the checks and writes
+// their diffs, one check per line
+func (a *Auditor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	diffs, err := a.Audit()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, diff := range diffs {
+		fmt.Fprintln(w, diff)
+	}
+}
@@ -0,0 +1,88 @@
+package auditor
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		desc     string
+		expected map[string]string
+		actual   map[string]string
+		expDiff  *Diff
+	}{
+		{
+			desc:     "no drift",
+			expected: map[string]string{"a": "1", "b": ""},
+			actual:   map[string]string{"a": "1", "b": ""},
+			expDiff:  &Diff{Check: "test"},
+		},
+		{
+			desc:     "missing, stale and modified rows",
+			expected: map[string]string{"a": "1", "c": "", "b": "2", "e": ""},
+			actual:   map[string]string{"a": "1", "b": "3", "d": "", "f": ""},
+			expDiff: &Diff{
+				Check:    "test",
+				Missing:  []string{"c", "e"},
+				Stale:    []string{"d", "f"},
+				Modified: []string{"b"},
+			},
+		},
+	}
+	for i, tc := range tests {
+		t.Run(fmt.Sprintf("%d:%s", i, tc.desc), func(t *testing.T) {
+			diff := Compare("test", tc.expected, tc.actual)
+			assert.Equal(t, tc.expDiff, diff)
+			assert.Equal(t, len(tc.expDiff.Missing)+len(tc.expDiff.Stale)+len(tc.expDiff.Modified) == 0, diff.Empty())
+		})
+	}
+}
+
+func TestAudit(t *testing.T) {
+	var recorded []string
+	a := New(map[string]Check{
+		"b": func() (map[string]string, map[string]string, error) {
+			return map[string]string{"x": ""}, map[string]string{"y": ""}, nil
+		},
+		"a": func() (map[string]string, map[string]string, error) {
+			return map[string]string{"x": ""}, map[string]string{"x": ""}, nil
+		},
+	}, false, func(diff *Diff) { recorded = append(recorded, diff.Check) })
+
+	diffs, err := a.Audit()
+	assert.NoError(t, err)
+	assert.Equal(t, []*Diff{
+		{Check: "a"},
+		{Check: "b", Missing: []string{"x"}, Stale: []string{"y"}},
+	}, diffs)
+	assert.Equal(t, []string{"a", "b"}, recorded)
+
+	a.checks["failing"] = func() (map[string]string, map[string]string, error) {
+		return nil, nil, fmt.Errorf("boom")
+	}
+	diffs, err = a.Audit()
+	assert.Error(t, err)
+	assert.Len(t, diffs, 2)
+}
+
+func TestServeHTTP(t *testing.T) {
+	a := New(map[string]Check{
+		"lsps": func() (map[string]string, map[string]string, error) {
+			return map[string]string{"pod": "mac ip"}, map[string]string{"pod": "mac other-ip"}, nil
+		},
+	}, false, nil)
+
+	w := httptest.NewRecorder()
+	a.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/nbaudit", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "lsps: missing [], stale [], modified [pod]\n", w.Body.String())
+
+	w = httptest.NewRecorder()
+	a.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/debug/nbaudit", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
@@ -11,7 +11,9 @@ import (
 
 	mnpapi "github.com/k8snetworkplumbingwg/multi-networkpolicy/pkg/apis/k8s.cni.cncf.io/v1beta1"
 	egressfirewall "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1"
+	ipamclaimsapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/ipamclaims/v1alpha1"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/retry"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 )
 
@@ -126,6 +128,17 @@ func (h *baseNetworkControllerEventHandler) areResourcesEqual(objType reflect.Ty
 			return false, fmt.Errorf("could not cast obj2 of type %T to *multinetworkpolicyapi.MultiNetworkPolicy", obj2)
 		}
 		return reflect.DeepEqual(mnp1, mnp2), nil
+
+	case factory.IPAMClaimsType:
+		claim1, ok := obj1.(*ipamclaimsapi.IPAMClaim)
+		if !ok {
+			return false, fmt.Errorf("could not cast obj1 of type %T to *ipamclaimsapi.IPAMClaim", obj1)
+		}
+		claim2, ok := obj2.(*ipamclaimsapi.IPAMClaim)
+		if !ok {
+			return false, fmt.Errorf("could not cast obj2 of type %T to *ipamclaimsapi.IPAMClaim", obj2)
+		}
+		return reflect.DeepEqual(claim1.Spec, claim2.Spec) && reflect.DeepEqual(claim1.Status, claim2.Status), nil
 	}
 
 	return false, fmt.Errorf("no object comparison for type %s", objType)
@@ -173,6 +186,9 @@ func (h *baseNetworkControllerEventHandler) getResourceFromInformerCache(objType
 	case factory.MultiNetworkPolicyType:
 		obj, err = watchFactory.GetMultiNetworkPolicy(namespace, name)
 
+	case factory.IPAMClaimsType:
+		obj, err = watchFactory.GetIPAMClaim(namespace, name)
+
 	default:
 		err = fmt.Errorf("object type %s not supported, cannot retrieve it from informers cache",
 			objType)
@@ -206,6 +222,17 @@ func needsUpdateDuringRetry(objType reflect.Type) bool {
 	return false
 }
 
+// maxFailedAttempts returns the number of failed attempts after which the retry of an object of the given type is
+// given up, 0 for the configured default. Only nodes are special-cased: they are retried until they succeed, since
+// everything running on a node depends on its setup. -retry-max-failed-attempts-overrides takes precedence.
+func maxFailedAttempts(objType reflect.Type) int {
+	switch objType {
+	case factory.NodeType:
+		return retry.RetryForever
+	}
+	return 0
+}
+
 // IsObjectInTerminalState returns true if the object is in a terminal state.
 func (h *baseNetworkControllerEventHandler) isObjectInTerminalState(objType reflect.Type, obj interface{}) bool {
 	switch objType {
@@ -218,6 +218,9 @@ func (bnc *BaseNetworkController) createOvnClusterRouter() (*nbdb.LogicalRouter,
 			"mcast_relay": "true",
 		}
 	}
+	for option, value := range config.Default.ClusterRouterOptions {
+		logicalRouter.Options[option] = value
+	}
 
 	err = libovsdbops.CreateOrUpdateLogicalRouter(bnc.nbClient, &logicalRouter, &logicalRouter.Options,
 		&logicalRouter.ExternalIDs, &logicalRouter.Copp)
@@ -697,6 +700,26 @@ func (bnc *BaseNetworkController) doesNetworkRequireIPAM() bool {
 	return !((bnc.TopologyType() == types.Layer2Topology || bnc.TopologyType() == types.LocalnetTopology) && len(bnc.Subnets()) == 0)
 }
 
+// doesNetworkRequireNamespaces returns true if the controller has to watch the namespaces and maintain their address
+// sets. Secondary networks only need them for multi-network policies and multicast, which are not supported on
+// IPAM-less secondary networks.
+func (bnc *BaseNetworkController) doesNetworkRequireNamespaces() bool {
+	if !bnc.IsSecondary() {
+		return true
+	}
+	return bnc.doesNetworkRequireIPAM() && (util.IsMultiNetworkPoliciesSupportEnabled() || bnc.multicastSupport)
+}
+
+// allocatesPodAnnotation returns true if the controller allocates the addresses of its pods. With interconnect, the
+// layer2 and localnet secondary networks span all the zones, so the cluster manager allocates their pod addresses
+// and the controller waits for the pod annotation.
+func (bnc *BaseNetworkController) allocatesPodAnnotation() bool {
+	if !config.OVNKubernetesFeature.EnableInterconnect || !bnc.IsSecondary() {
+		return true
+	}
+	return bnc.TopologyType() == types.Layer3Topology
+}
+
 func (bnc *BaseNetworkController) buildPortGroup(hashName, name string, ports []*nbdb.LogicalSwitchPort, acls []*nbdb.ACL) *nbdb.PortGroup {
 	externalIds := map[string]string{"name": name}
 	if bnc.IsSecondary() {
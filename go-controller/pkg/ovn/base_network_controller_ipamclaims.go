@@ -0,0 +1,151 @@
+package ovn
+
+import (
+	"fmt"
+	"net"
+
+	ipamclaimsapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/ipamclaims/v1alpha1"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/ipallocator"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	kapi "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+)
+
+// allowPersistentIPs returns true if the pods of this network can keep their IPs across pod recreation by
+// referencing an IPAMClaim
+func (bnc *BaseNetworkController) allowPersistentIPs() bool {
+	return util.IsPersistentIPsEnabled() && bnc.doesNetworkRequireIPAM() && bnc.AllowsPersistentIPs()
+}
+
+// getPodIPAMClaim returns the IPAMClaim the pod references for the given NAD, or nil if the pod does not
+// reference any. The error wraps the informer error, so a missing claim can be checked with kerrors.IsNotFound.
+func (bnc *BaseNetworkController) getPodIPAMClaim(pod *kapi.Pod, nadName string) (*ipamclaimsapi.IPAMClaim, error) {
+	if !bnc.allowPersistentIPs() {
+		return nil, nil
+	}
+	claimName, err := util.GetK8sPodIPAMClaimReference(pod, nadName)
+	if err != nil || claimName == "" {
+		return nil, err
+	}
+	claim, err := bnc.watchFactory.GetIPAMClaim(pod.Namespace, claimName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get IPAMClaim %s/%s referenced by pod %s for NAD %s: %w",
+			pod.Namespace, claimName, pod.Name, nadName, err)
+	}
+	if claim.Spec.Network != bnc.GetNetworkName() {
+		return nil, fmt.Errorf("IPAMClaim %s/%s referenced by pod %s for NAD %s belongs to network %s, not to %s",
+			claim.Namespace, claim.Name, pod.Name, nadName, claim.Spec.Network, bnc.GetNetworkName())
+	}
+	return claim, nil
+}
+
+// isPodIPAMClaimed returns true if the IPs of the pod for the given NAD are held by an existing IPAMClaim and
+// must not be released when the pod goes away
+func (bnc *BaseNetworkController) isPodIPAMClaimed(pod *kapi.Pod, nadName string) (bool, error) {
+	claim, err := bnc.getPodIPAMClaim(pod, nadName)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return claim != nil, nil
+}
+
+// allocateIPAMClaimIPs reserves the IPs stored in the IPAMClaim status on the given switch; IPs that are
+// already reserved, e.g. when the claim was synced, are not an error.
+func (bnc *BaseNetworkController) allocateIPAMClaimIPs(switchName string, claim *ipamclaimsapi.IPAMClaim) ([]*net.IPNet, error) {
+	ips, err := parseIPAMClaimIPs(claim)
+	if err != nil {
+		return nil, err
+	}
+	if err = bnc.lsManager.AllocateIPs(switchName, ips); err != nil && err != ipallocator.ErrAllocated {
+		return nil, fmt.Errorf("failed to allocate IPs %s of IPAMClaim %s/%s on switch %s: %v",
+			util.JoinIPNets(ips, " "), claim.Namespace, claim.Name, switchName, err)
+	}
+	return ips, nil
+}
+
+// updateIPAMClaimIPs stores the IPs allocated to the pod in the IPAMClaim status, so that they can be
+// reused when the pod is recreated
+func (bnc *BaseNetworkController) updateIPAMClaimIPs(claim *ipamclaimsapi.IPAMClaim, podIfAddrs []*net.IPNet) error {
+	ips := make([]string, 0, len(podIfAddrs))
+	for _, podIfAddr := range podIfAddrs {
+		ips = append(ips, podIfAddr.String())
+	}
+	if err := bnc.kube.UpdateIPAMClaimIPs(claim, ips); err != nil {
+		return fmt.Errorf("failed to update the IPs of IPAMClaim %s/%s: %v", claim.Namespace, claim.Name, err)
+	}
+	klog.Infof("Persisted IPs %v in IPAMClaim %s/%s for network %s", ips, claim.Namespace, claim.Name, bnc.GetNetworkName())
+	return nil
+}
+
+func parseIPAMClaimIPs(claim *ipamclaimsapi.IPAMClaim) ([]*net.IPNet, error) {
+	ips := make([]*net.IPNet, 0, len(claim.Status.IPs))
+	for _, ipStr := range claim.Status.IPs {
+		ip, ipNet, err := net.ParseCIDR(ipStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse IP %s of IPAMClaim %s/%s: %v", ipStr, claim.Namespace, claim.Name, err)
+		}
+		ipNet.IP = ip
+		ips = append(ips, ipNet)
+	}
+	return ips, nil
+}
+
+// getLogicalSwitchName returns the name of the single logical switch of a layer2 or localnet network
+func (oc *BaseSecondaryLayer2NetworkController) getLogicalSwitchName() string {
+	if oc.TopologyType() == types.LocalnetTopology {
+		return oc.GetNetworkScopedName(types.OVNLocalnetSwitch)
+	}
+	return oc.GetNetworkScopedName(types.OVNLayer2Switch)
+}
+
+// WatchIPAMClaims starts the watching of IPAMClaim resources, reserving the IPs of the existing claims. It
+// needs to run before WatchPods so that claimed IPs are not handed out to other pods.
+func (oc *BaseSecondaryLayer2NetworkController) WatchIPAMClaims() error {
+	if oc.ipamClaimsHandler != nil || oc.retryIPAMClaims == nil {
+		return nil
+	}
+
+	handler, err := oc.retryIPAMClaims.WatchResource()
+	if err == nil {
+		oc.ipamClaimsHandler = handler
+	}
+	return err
+}
+
+// addIPAMClaim reserves the IPs held by an IPAMClaim of this network
+func (oc *BaseSecondaryLayer2NetworkController) addIPAMClaim(claim *ipamclaimsapi.IPAMClaim) error {
+	if claim.Spec.Network != oc.GetNetworkName() || len(claim.Status.IPs) == 0 {
+		return nil
+	}
+	_, err := oc.allocateIPAMClaimIPs(oc.getLogicalSwitchName(), claim)
+	return err
+}
+
+// deleteIPAMClaim releases the IPs held by an IPAMClaim of this network, unless a pod is still using them;
+// the IPs are then released along with the pod.
+func (oc *BaseSecondaryLayer2NetworkController) deleteIPAMClaim(claim *ipamclaimsapi.IPAMClaim) error {
+	if claim.Spec.Network != oc.GetNetworkName() || len(claim.Status.IPs) == 0 {
+		return nil
+	}
+	ips, err := parseIPAMClaimIPs(claim)
+	if err != nil {
+		return err
+	}
+	canRelease, err := oc.canReleasePodIPs(ips)
+	if err != nil || !canRelease {
+		return err
+	}
+	klog.Infof("Releasing IPs %s of deleted IPAMClaim %s/%s for network %s", util.JoinIPNetIPs(ips, " "),
+		claim.Namespace, claim.Name, oc.GetNetworkName())
+	return oc.releasePodIPs(&lpInfo{
+		name:          claim.Namespace + "/" + claim.Name,
+		logicalSwitch: oc.getLogicalSwitchName(),
+		ips:           ips,
+	})
+}
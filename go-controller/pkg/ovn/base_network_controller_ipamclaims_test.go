@@ -0,0 +1,204 @@
+package ovn
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	cnitypes "github.com/containernetworking/cni/pkg/types"
+	nettypes "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+	"github.com/urfave/cli/v2"
+
+	libovsdbclient "github.com/ovn-org/libovsdb/client"
+	ovncnitypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/cni/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	ipamclaimsapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/ipamclaims/v1alpha1"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/libovsdbops"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/ipallocator"
+	ovntest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing"
+	libovsdbtest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing/libovsdb"
+	ovntypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = ginkgo.Describe("OVN IPAMClaim Operations", func() {
+	const (
+		namespaceName        = "namespace1"
+		nodeName             = "node1"
+		secondaryNetworkName = "network1"
+		nadName              = "nad1"
+		claimName            = "vm1.nad1"
+		claimedIP            = "10.1.1.5/24"
+	)
+	var (
+		app     *cli.App
+		fakeOvn *FakeOVN
+		nad     *nettypes.NetworkAttachmentDefinition
+	)
+
+	ginkgo.BeforeEach(func() {
+		var err error
+		// Restore global default values before each testcase
+		config.PrepareTestConfig()
+		config.OVNKubernetesFeature.EnableMultiNetwork = true
+		config.OVNKubernetesFeature.EnablePersistentIPs = true
+
+		app = cli.NewApp()
+		app.Name = "test"
+		app.Flags = config.Flags
+
+		fakeOvn = NewFakeOVN(true)
+
+		nad, err = newNetworkAttachmentDefinition(
+			namespaceName,
+			nadName,
+			ovncnitypes.NetConf{
+				NetConf: cnitypes.NetConf{
+					Name: secondaryNetworkName,
+					Type: "ovn-k8s-cni-overlay",
+				},
+				Topology:           ovntypes.Layer2Topology,
+				NADName:            util.GetNADName(namespaceName, nadName),
+				Subnets:            "10.1.1.0/24",
+				AllowPersistentIPs: true,
+			},
+		)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.AfterEach(func() {
+		fakeOvn.shutdown()
+	})
+
+	startOvn := func(claims []ipamclaimsapi.IPAMClaim, pods []v1.Pod) *BaseSecondaryLayer2NetworkController {
+		layer2Switch := &nbdb.LogicalSwitch{
+			Name:        secondaryNetworkName + "_" + ovntypes.OVNLayer2Switch,
+			UUID:        secondaryNetworkName + "_" + ovntypes.OVNLayer2Switch + "_UUID",
+			ExternalIDs: map[string]string{ovntypes.NetworkExternalID: secondaryNetworkName},
+		}
+		fakeOvn.startWithDBSetup(libovsdbtest.TestSetup{NBData: []libovsdbtest.TestData{layer2Switch}},
+			&v1.NamespaceList{
+				Items: []v1.Namespace{*newNamespace(namespaceName)},
+			},
+			&v1.NodeList{
+				Items: []v1.Node{*newNode(nodeName, "192.168.126.202/24")},
+			},
+			&v1.PodList{
+				Items: pods,
+			},
+			&ipamclaimsapi.IPAMClaimList{
+				Items: claims,
+			},
+			&nettypes.NetworkAttachmentDefinitionList{
+				Items: []nettypes.NetworkAttachmentDefinition{*nad},
+			},
+		)
+		ocInfo, ok := fakeOvn.secondaryControllers[secondaryNetworkName]
+		gomega.Expect(ok).To(gomega.BeTrue())
+		oc := ocInfo.bl2nc
+		gomega.Expect(oc).NotTo(gomega.BeNil())
+
+		err := oc.lsManager.AddSwitch(oc.getLogicalSwitchName(), layer2Switch.UUID,
+			[]*net.IPNet{ovntest.MustParseIPNet("10.1.1.0/24")})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		err = oc.WatchIPAMClaims()
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		return oc
+	}
+
+	newIPAMClaim := func(ips ...string) ipamclaimsapi.IPAMClaim {
+		return ipamclaimsapi.IPAMClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: claimName, Namespace: namespaceName},
+			Spec: ipamclaimsapi.IPAMClaimSpec{
+				Network:   secondaryNetworkName,
+				Interface: "net1",
+			},
+			Status: ipamclaimsapi.IPAMClaimStatus{IPs: ips},
+		}
+	}
+
+	isIPAllocated := func(oc *BaseSecondaryLayer2NetworkController, ip string) func() bool {
+		return func() bool {
+			ipNet := ovntest.MustParseIPNet(ip)
+			err := oc.lsManager.AllocateIPs(oc.getLogicalSwitchName(), []*net.IPNet{ipNet})
+			if err == nil {
+				// undo the allocation done by the check itself
+				_ = oc.lsManager.ReleaseIPs(oc.getLogicalSwitchName(), []*net.IPNet{ipNet})
+			}
+			return err == ipallocator.ErrAllocated
+		}
+	}
+
+	ginkgo.It("reserves the IPs of existing IPAMClaims and releases them when the claims are deleted", func() {
+		app.Action = func(ctx *cli.Context) error {
+			oc := startOvn([]ipamclaimsapi.IPAMClaim{newIPAMClaim(claimedIP)}, nil)
+			gomega.Expect(isIPAllocated(oc, claimedIP)()).To(gomega.BeTrue())
+
+			err := fakeOvn.fakeClient.IPAMClaimsClient.K8sV1alpha1().IPAMClaims(namespaceName).Delete(context.TODO(),
+				claimName, metav1.DeleteOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Eventually(isIPAllocated(oc, claimedIP)).Should(gomega.BeFalse())
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("persists the IPs of a pod in the IPAMClaim it references and keeps them when the pod is deleted", func() {
+		app.Action = func(ctx *cli.Context) error {
+			pod := newPod(namespaceName, "virt-launcher-vm1", nodeName, "")
+			pod.Annotations = map[string]string{
+				nettypes.NetworkAttachmentAnnot: fmt.Sprintf(`[{"name":%q,"namespace":%q,"ipam-claim-reference":%q}]`,
+					nadName, namespaceName, claimName),
+			}
+			oc := startOvn([]ipamclaimsapi.IPAMClaim{newIPAMClaim()}, []v1.Pod{*pod})
+
+			err := oc.WatchPods()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var claimIPs []string
+			gomega.Eventually(func() []string {
+				claim, err := fakeOvn.fakeClient.IPAMClaimsClient.K8sV1alpha1().IPAMClaims(namespaceName).Get(context.TODO(),
+					claimName, metav1.GetOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				claimIPs = claim.Status.IPs
+				return claimIPs
+			}).Should(gomega.HaveLen(1))
+
+			gomega.Eventually(func() ([]string, error) {
+				pod, err := fakeOvn.fakeClient.KubeClient.CoreV1().Pods(namespaceName).Get(context.TODO(),
+					pod.Name, metav1.GetOptions{})
+				if err != nil {
+					return nil, err
+				}
+				podAnnotation, err := util.UnmarshalPodAnnotation(pod.Annotations, util.GetNADName(namespaceName, nadName))
+				if err != nil {
+					return nil, err
+				}
+				return []string{podAnnotation.IPs[0].String()}, nil
+			}).Should(gomega.Equal(claimIPs))
+
+			err = fakeOvn.fakeClient.KubeClient.CoreV1().Pods(namespaceName).Delete(context.TODO(), pod.Name,
+				metav1.DeleteOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			portName := util.GetSecondaryNetworkLogicalPortName(namespaceName, pod.Name, util.GetNADName(namespaceName, nadName))
+			gomega.Eventually(func() error {
+				_, err := libovsdbops.GetLogicalSwitchPort(fakeOvn.nbClient, &nbdb.LogicalSwitchPort{Name: portName})
+				return err
+			}).Should(gomega.MatchError(libovsdbclient.ErrNotFound))
+			gomega.Consistently(isIPAllocated(oc, claimIPs[0])).Should(gomega.BeTrue())
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+})
@@ -266,8 +266,7 @@ func (bnc *BaseNetworkController) createDefaultAllowMulticastPolicy() error {
 func (bnc *BaseNetworkController) disableMulticast() error {
 	// default mcast acls have ACLMulticastCluster type
 	predicateIDs := libovsdbops.NewDbObjectIDs(libovsdbops.ACLMulticastCluster, bnc.controllerName, nil)
-	mcastAclPred := libovsdbops.GetPredicate[*nbdb.ACL](predicateIDs, nil)
-	mcastACLs, err := libovsdbops.FindACLsWithPredicate(bnc.nbClient, mcastAclPred)
+	mcastACLs, err := libovsdbops.FindACLsWithDbIDs(bnc.nbClient, predicateIDs, nil)
 	if err != nil {
 		return fmt.Errorf("unable to find default multicast ACLs: %v", err)
 	}
@@ -304,8 +303,7 @@ func (bnc *BaseNetworkController) syncNsMulticast(k8sNamespaces map[string]bool)
 	// since we can't filter multicast port groups specifically, find multicast ACLs, and then find
 	// port groups they are referenced from.
 	predicateIDs := libovsdbops.NewDbObjectIDs(libovsdbops.ACLMulticastNamespace, bnc.controllerName, nil)
-	mcastAclPred := libovsdbops.GetPredicate[*nbdb.ACL](predicateIDs, nil)
-	mcastACLs, err := libovsdbops.FindACLsWithPredicate(bnc.nbClient, mcastAclPred)
+	mcastACLs, err := libovsdbops.FindACLsWithDbIDs(bnc.nbClient, predicateIDs, nil)
 	if err != nil {
 		return fmt.Errorf("unable to find multicast ACLs for namespaces: %v", err)
 	}
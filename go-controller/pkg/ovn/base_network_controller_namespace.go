@@ -64,13 +64,8 @@ func getNamespaceAddrSetDbIDs(namespaceName, controller string) *libovsdbops.DbO
 // WatchNamespaces starts the watching of namespace resource and calls
 // back the appropriate handler logic
 func (bnc *BaseNetworkController) WatchNamespaces() error {
-	if bnc.IsSecondary() {
-		// For secondary networks, we don't have to watch namespace events if
-		// multi-network policy support is not enabled. We don't support
-		// multi-network policy for IPAM-less secondary networks either.
-		if !util.IsMultiNetworkPoliciesSupportEnabled() || !bnc.doesNetworkRequireIPAM() {
-			return nil
-		}
+	if !bnc.doesNetworkRequireNamespaces() {
+		return nil
 	}
 
 	if bnc.namespaceHandler != nil {
@@ -84,6 +79,10 @@ func (bnc *BaseNetworkController) WatchNamespaces() error {
 	return err
 }
 
+// validACLLogLevels are the various preestablished log levels or the empty string, that disables logging
+var validACLLogLevels = sets.NewString(nbdb.ACLSeverityAlert, nbdb.ACLSeverityWarning, nbdb.ACLSeverityNotice,
+	nbdb.ACLSeverityInfo, nbdb.ACLSeverityDebug, "")
+
 // aclLoggingUpdateNsInfo parses the provided annotation values and sets nsInfo.aclLogging.Deny and
 // nsInfo.aclLogging.Allow. If errors are encountered parsing the annotation, disable logging completely. If either
 // value contains invalid input, disable logging for the respective key. This is needed to ensure idempotency.
@@ -117,12 +116,8 @@ func (bnc *BaseNetworkController) aclLoggingUpdateNsInfo(annotation string, nsIn
 		}
 	}
 
-	// Valid log levels are the various preestablished levels or the empty string.
-	validLogLevels := sets.NewString(nbdb.ACLSeverityAlert, nbdb.ACLSeverityWarning, nbdb.ACLSeverityNotice,
-		nbdb.ACLSeverityInfo, nbdb.ACLSeverityDebug, "")
-
 	// Set Deny logging.
-	if validLogLevels.Has(aclLevels.Deny) {
+	if validACLLogLevels.Has(aclLevels.Deny) {
 		nsInfo.aclLogging.Deny = aclLevels.Deny
 	} else {
 		errors = append(errors, fmt.Errorf("disabling deny logging due to invalid deny annotation. "+
@@ -131,7 +126,7 @@ func (bnc *BaseNetworkController) aclLoggingUpdateNsInfo(annotation string, nsIn
 	}
 
 	// Set Allow logging.
-	if validLogLevels.Has(aclLevels.Allow) {
+	if validACLLogLevels.Has(aclLevels.Allow) {
 		nsInfo.aclLogging.Allow = aclLevels.Allow
 	} else {
 		errors = append(errors, fmt.Errorf("disabling allow logging due to an invalid allow annotation. "+
@@ -3,11 +3,13 @@ package ovn
 import (
 	"fmt"
 	"net"
+	"strconv"
 	"strings"
 	"time"
 
 	nadapi "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	ipamclaimsapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/ipamclaims/v1alpha1"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/ipallocator"
 	logicalswitchmanager "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/logical_switch_manager"
 	ovntypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
@@ -593,6 +595,10 @@ func (bnc *BaseNetworkController) addLogicalPortToNetwork(pod *kapi.Pod, nadName
 	lsp.Options["requested-chassis"] = pod.Spec.NodeName
 
 	podAnnotation, err = util.UnmarshalPodAnnotation(pod.Annotations, nadName)
+	if err != nil && !bnc.allocatesPodAnnotation() {
+		return nil, nil, nil, false, fmt.Errorf("[%s] waiting for the cluster manager to allocate the addresses of the pod on network %s: %v",
+			podDesc, bnc.GetNetworkName(), err)
+	}
 
 	// the IPs we allocate in this function need to be released back to the
 	// IPAM pool if there is some error in any step of addLogicalPort past
@@ -629,9 +635,13 @@ func (bnc *BaseNetworkController) addLogicalPortToNetwork(pod *kapi.Pod, nadName
 			} else {
 				needsIP = false
 			}
-		} else if len(podIfAddrs) > 0 {
+		} else if len(podIfAddrs) > 0 && bnc.allocatesPodAnnotation() {
 			return nil, nil, nil, false, fmt.Errorf("IPAMless network with IPs present in the annotations; rejecting to handle this request")
 		}
+		if !bnc.allocatesPodAnnotation() {
+			// the cluster manager owns the annotation, it is never updated here
+			needsIP = false
+		}
 	}
 
 	// It is possible that IPs have already been allocated for this pod and annotation has been updated, then the last
@@ -639,7 +649,14 @@ func (bnc *BaseNetworkController) addLogicalPortToNetwork(pod *kapi.Pod, nadName
 	// the informer cache still lags behind, we would fail to get the updated pod annotation. Just continue to allocate
 	// new IPs and this function will eventually fail in updatePodAnnotationWithRetry() with ErrOverridePodIPs
 	// when it tries to override the pod IP annotation. Newly allocated IPs will be released then.
+	var ipamClaim *ipamclaimsapi.IPAMClaim
 	if needsIP {
+		macPrefix := bnc.getPodMACPrefix(pod.Spec.NodeName)
+		// pods referencing an IPAMClaim get the IPs stored in the claim, if any
+		ipamClaim, err = bnc.getPodIPAMClaim(pod, nadName)
+		if err != nil {
+			return nil, nil, nil, false, err
+		}
 		if existingLSP != nil {
 			// try to get the MAC and IPs from existing OVN port first
 			podMac, podIfAddrs, err = bnc.getPortAddresses(switchName, existingLSP)
@@ -662,16 +679,24 @@ func (bnc *BaseNetworkController) addLogicalPortToNetwork(pod *kapi.Pod, nadName
 			}
 		}
 		if needsNewMacOrIPAllocation {
-			if network != nil && network.IPRequest != nil && !bnc.doesNetworkRequireIPAM() {
+			if ipamClaim != nil && len(ipamClaim.Status.IPs) > 0 {
+				podIfAddrs, err = bnc.allocateIPAMClaimIPs(switchName, ipamClaim)
+				if err != nil {
+					return nil, nil, nil, false, err
+				}
+				if podMac == nil {
+					podMac = util.NetworkPodIPToHWAddr(macPrefix, podIfAddrs[0].IP)
+				}
+			} else if network != nil && network.IPRequest != nil && !bnc.doesNetworkRequireIPAM() {
 				klog.V(5).Infof("Will use static IP addresses for pod %s on a flatL2 topology without subnet defined", podDesc)
 				podIfAddrs, err = calculateStaticIPs(podDesc, network.IPRequest)
 				if err != nil {
 					return nil, nil, nil, false, err
 				}
-				podMac = util.IPAddrToHWAddr(podIfAddrs[0].IP)
+				podMac = util.NetworkPodIPToHWAddr(macPrefix, podIfAddrs[0].IP)
 			} else {
 				// Previous attempts to use already configured IPs failed, need to assign new
-				generatedPodMac, generatedPodIfAddrs, err := bnc.assignPodAddresses(switchName)
+				generatedPodMac, generatedPodIfAddrs, err := bnc.assignPodAddresses(switchName, macPrefix)
 				if err != nil {
 					return nil, nil, nil, false, fmt.Errorf("failed to assign pod addresses for pod %s on switch: %s, err: %v",
 						podDesc, switchName, err)
@@ -685,7 +710,14 @@ func (bnc *BaseNetworkController) addLogicalPortToNetwork(pod *kapi.Pod, nadName
 			}
 		}
 
-		releaseIPs = true
+		if config.Default.DeterministicPodMAC && len(podIfAddrs) > 0 {
+			// The MAC must always follow the pod's primary IP, even if the
+			// existing OVN port was created with a different one
+			podMac = util.NetworkPodIPToHWAddr(macPrefix, podIfAddrs[0].IP)
+		}
+
+		// the IPs of an IPAMClaim are only released when the claim is deleted
+		releaseIPs = ipamClaim == nil || len(ipamClaim.Status.IPs) == 0
 		// handle error cases separately first to ensure binding to err, otherwise the
 		// defer will fail
 		if network != nil && network.MacRequest != "" {
@@ -708,6 +740,12 @@ func (bnc *BaseNetworkController) addLogicalPortToNetwork(pod *kapi.Pod, nadName
 			return nil, nil, nil, false, err
 		}
 
+		if ipamClaim != nil && len(ipamClaim.Status.IPs) == 0 {
+			if err = bnc.updateIPAMClaimIPs(ipamClaim, podIfAddrs); err != nil {
+				return nil, nil, nil, false, err
+			}
+		}
+
 		klog.V(5).Infof("Annotation values: ip=%v ; mac=%s ; gw=%s",
 			podIfAddrs, podMac, podAnnotation.Gateways)
 		annoStart := time.Now()
@@ -720,6 +758,12 @@ func (bnc *BaseNetworkController) addLogicalPortToNetwork(pod *kapi.Pod, nadName
 		releaseIPs = false
 	}
 
+	if podAnnotation.TunnelID != 0 {
+		// the network switch is a transit switch spanning all the zones, the port tunnel key allocated by the
+		// cluster manager must be the same in all of them
+		lsp.Options["requested-tnl-key"] = strconv.Itoa(podAnnotation.TunnelID)
+	}
+
 	// set addresses on the port
 	// LSP addresses in OVN are a single space-separated value
 	addresses = []string{podMac.String()}
@@ -739,6 +783,16 @@ func (bnc *BaseNetworkController) addLogicalPortToNetwork(pod *kapi.Pod, nadName
 
 	// CNI depends on the flows from port security, delay setting it until end
 	lsp.PortSecurity = addresses
+	if !bnc.IsSecondary() {
+		exemptions, err := util.GetPodPortSecurityExemptions(pod, config.Default.PortSecurityExemptionCIDRs)
+		if err != nil {
+			return nil, nil, nil, false, util.NewPermanentError(fmt.Errorf("invalid %s annotation on pod %s: %w",
+				util.PortSecurityExemptionsAnnotation, podDesc, err))
+		}
+		if exemptions != nil {
+			lsp.PortSecurity, lsp.Addresses = applyPortSecurityExemptions(addresses, exemptions)
+		}
+	}
 
 	ops, err = libovsdbops.CreateOrUpdateLogicalSwitchPortsOnSwitchOps(bnc.nbClient, nil, ls, lsp)
 	if err != nil {
@@ -749,6 +803,26 @@ func (bnc *BaseNetworkController) addLogicalPortToNetwork(pod *kapi.Pod, nadName
 	return ops, lsp, podAnnotation, needsIP && !lspExist, nil
 }
 
+// applyPortSecurityExemptions returns the port security and the addresses of a pod port with the given exemptions.
+// The extra IPs are allowed along with the pod IPs, and each extra MAC is allowed with the extra IPs. The extra IPs
+// are not added to the port addresses, else OVN would answer the ARP and ND requests for them with the pod MAC while
+// they may move to another pod; when there are extra MACs, the port gets the unknown address instead so that the
+// traffic sent to them reaches the pod.
+func applyPortSecurityExemptions(addresses []string, exemptions *util.PortSecurityExemptions) ([]string, []string) {
+	var extraIPs string
+	for _, ip := range exemptions.IPs {
+		extraIPs += " " + ip.String()
+	}
+	portSecurity := []string{addresses[0] + extraIPs}
+	for _, mac := range exemptions.MACs {
+		portSecurity = append(portSecurity, mac.String()+extraIPs)
+	}
+	if len(exemptions.MACs) > 0 {
+		addresses = append(addresses, "unknown")
+	}
+	return portSecurity, addresses
+}
+
 func (bnc *BaseNetworkController) updatePodAnnotationWithRetry(origPod *kapi.Pod, podInfo *util.PodAnnotation, nadName string) error {
 	resultErr := retry.RetryOnConflict(util.OvnConflictBackoff, func() error {
 		// Informer cache should not be mutated, so get a copy of the object
@@ -770,9 +844,31 @@ func (bnc *BaseNetworkController) updatePodAnnotationWithRetry(origPod *kapi.Pod
 	return nil
 }
 
+// getPodMACPrefix returns the MAC prefix of the pods of the secondary network, derived from the network id that the
+// cluster manager allocated to the network and annotated on the given node. It returns nil for the default network,
+// or if the network id is not annotated yet, in which case the pod MACs are derived from the default prefix.
+func (bnc *BaseNetworkController) getPodMACPrefix(nodeName string) net.HardwareAddr {
+	if !bnc.IsSecondary() {
+		return nil
+	}
+	node, err := bnc.watchFactory.GetNode(nodeName)
+	if err != nil {
+		klog.Warningf("Failed to get node %s to find the MAC prefix of network %s: %v", nodeName, bnc.GetNetworkName(), err)
+		return nil
+	}
+	networkID, err := util.ParseNetworkIDAnnotation(node, bnc.GetNetworkName())
+	if err != nil {
+		if !util.IsAnnotationNotSetError(err) {
+			klog.Warningf("Failed to get the network id of network %s from node %s: %v", bnc.GetNetworkName(), nodeName, err)
+		}
+		return nil
+	}
+	return util.GetNetworkMACPrefix(networkID)
+}
+
 // Given a switch, gets the next set of addresses (from the IPAM) for each of the node's
-// subnets to assign to the new pod
-func (bnc *BaseNetworkController) assignPodAddresses(switchName string) (net.HardwareAddr, []*net.IPNet, error) {
+// subnets to assign to the new pod. The pod MAC is made of the given MAC prefix, if any.
+func (bnc *BaseNetworkController) assignPodAddresses(switchName string, macPrefix net.HardwareAddr) (net.HardwareAddr, []*net.IPNet, error) {
 	var (
 		podMAC   net.HardwareAddr
 		podCIDRs []*net.IPNet
@@ -785,6 +881,9 @@ func (bnc *BaseNetworkController) assignPodAddresses(switchName string) (net.Har
 		if err != nil {
 			return nil, nil, err
 		}
+		if len(macPrefix) == 2 {
+			copy(mac, macPrefix)
+		}
 		return mac, nil, nil
 	}
 	podCIDRs, err = bnc.lsManager.AllocateNextIPs(switchName)
@@ -792,7 +891,7 @@ func (bnc *BaseNetworkController) assignPodAddresses(switchName string) (net.Har
 		return nil, nil, err
 	}
 	if len(podCIDRs) > 0 {
-		podMAC = util.IPAddrToHWAddr(podCIDRs[0].IP)
+		podMAC = util.NetworkPodIPToHWAddr(macPrefix, podCIDRs[0].IP)
 	}
 	return podMAC, podCIDRs, nil
 }
@@ -1,6 +1,7 @@
 package ovn
 
 import (
+	"encoding/json"
 	"fmt"
 	"net"
 	"strings"
@@ -15,6 +16,7 @@ import (
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/metrics"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 	kapi "k8s.io/api/core/v1"
 	knet "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -43,6 +45,38 @@ const (
 	ovnStatelessNetPolAnnotationName = "k8s.ovn.org/acl-stateless"
 )
 
+// policyACLLogging holds the ACL logging settings of a network policy, set with the k8s.ovn.org/acl-logging
+// annotation of the policy. They override the namespace settings for the allow ACLs of the policy; the deny ACLs are
+// shared by all the policies of the namespace, so their log level can only be set on the namespace.
+type policyACLLogging struct {
+	Allow *string `json:"allow,omitempty"`
+	// RateLimit is the maximum number of packets per second logged by the ACLs of the policy, enforced with a meter
+	// of the policy instead of the cluster-wide ACL logging meter
+	RateLimit *int `json:"rate-limit,omitempty"`
+}
+
+// parsePolicyACLLogging parses the ACL logging annotation of the given network policy, returning nil if the policy
+// doesn't have one
+func parsePolicyACLLogging(policy *knet.NetworkPolicy) (*policyACLLogging, error) {
+	annotation, ok := policy.Annotations[util.AclLoggingAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	aclLogging := &policyACLLogging{}
+	decoder := json.NewDecoder(strings.NewReader(annotation))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(aclLogging); err != nil {
+		return nil, fmt.Errorf("could not unmarshal network policy ACL logging annotation '%s': %v", annotation, err)
+	}
+	if aclLogging.Allow != nil && !validACLLogLevels.Has(*aclLogging.Allow) {
+		return nil, fmt.Errorf("%q is not a valid log severity", *aclLogging.Allow)
+	}
+	if aclLogging.RateLimit != nil && *aclLogging.RateLimit <= 0 {
+		return nil, fmt.Errorf("invalid rate limit %d, it must be greater than 0", *aclLogging.RateLimit)
+	}
+	return aclLogging, nil
+}
+
 // defaultDenyPortGroups is a shared object and should be used by only 1 thread at a time
 type defaultDenyPortGroups struct {
 	// portName: map[portName]sets.String(policyNames)
@@ -165,6 +199,9 @@ type networkPolicy struct {
 	// peerAddressSets stores PodSelectorAddressSet keys for peers that this network policy was successfully added to.
 	// Required for cleanup.
 	peerAddressSets []string
+	// aclLogging holds the ACL logging settings of the policy annotation, if any. It is set on creation and
+	// doesn't change, since an update of the policy deletes and re-creates it.
+	aclLogging *policyACLLogging
 
 	// localPods is a map of pods affected by this policy.
 	// It is used to update defaultDeny port group port counters, when deleting network policy.
@@ -201,6 +238,62 @@ func NewNetworkPolicy(policy *knet.NetworkPolicy) *networkPolicy {
 
 // syncNetworkPoliciesCommon syncs logical entities associated with existing network policies.
 // It serves both networkpolicies (for default network) and multi-networkpolicies (for secondary networks)
+// getACLLoggingLevels returns the log levels of the ACLs of the policy, given the log levels of its namespace
+func (np *networkPolicy) getACLLoggingLevels(nsLogging *ACLLoggingLevels, meterName string) *ACLLoggingLevels {
+	if np.aclLogging == nil {
+		return nsLogging
+	}
+	aclLogging := *nsLogging
+	if np.aclLogging.Allow != nil {
+		aclLogging.Allow = *np.aclLogging.Allow
+	}
+	if np.aclLogging.RateLimit != nil {
+		aclLogging.Meter = meterName
+	}
+	return &aclLogging
+}
+
+func (bnc *BaseNetworkController) getNetpolACLLoggingMeterDbIDs(namespace, policyName string) *libovsdbops.DbObjectIDs {
+	return libovsdbops.NewDbObjectIDs(libovsdbops.MeterNetworkPolicy, bnc.controllerName,
+		map[libovsdbops.ExternalIDKey]string{
+			libovsdbops.ObjectNameKey: getACLPolicyKey(namespace, policyName),
+		})
+}
+
+// getNetpolACLLoggingMeterName returns the name of the ACL logging meter of a network policy that sets its own rate
+// limit
+func (bnc *BaseNetworkController) getNetpolACLLoggingMeterName(namespace, policyName string) string {
+	// format: acl-logging-<controllerName>-<policyNamespace>:<policyName>
+	return types.OvnACLLoggingMeter + "-" + bnc.controllerName + "-" + getACLPolicyKey(namespace, policyName)
+}
+
+// createNetpolACLLoggingMeterOps returns the ops to create or update the ACL logging meter of the given policy with the
+// given rate
+func (bnc *BaseNetworkController) createNetpolACLLoggingMeterOps(ops []ovsdb.Operation, np *networkPolicy,
+	rate int) ([]ovsdb.Operation, error) {
+	band := &nbdb.MeterBand{
+		Action: types.MeterAction,
+		Rate:   rate,
+	}
+	ops, err := libovsdbops.CreateMeterBandOps(bnc.nbClient, ops, band)
+	if err != nil {
+		return nil, fmt.Errorf("can't create meter band %v: %v", band, err)
+	}
+	meterFairness := true
+	meter := &nbdb.Meter{
+		Name:        bnc.getNetpolACLLoggingMeterName(np.namespace, np.name),
+		Fair:        &meterFairness,
+		Unit:        types.PacketsPerSecond,
+		ExternalIDs: bnc.getNetpolACLLoggingMeterDbIDs(np.namespace, np.name).GetExternalIDs(),
+	}
+	ops, err = libovsdbops.CreateOrUpdateMeterOps(bnc.nbClient, ops, meter, []*nbdb.MeterBand{band},
+		&meter.Bands, &meter.Fair, &meter.Unit, &meter.ExternalIDs)
+	if err != nil {
+		return nil, fmt.Errorf("can't create meter %v: %v", meter, err)
+	}
+	return ops, nil
+}
+
 func (bnc *BaseNetworkController) syncNetworkPoliciesCommon(expectedPolicies map[string]map[string]bool) error {
 	// find network policies that don't exist in k8s anymore, but still present in the dbs, and cleanup.
 	// Peer address sets and network policy's port groups (together with acls) will be cleaned up.
@@ -210,8 +303,7 @@ func (bnc *BaseNetworkController) syncNetworkPoliciesCommon(expectedPolicies map
 	// cleanup port groups based on acl search
 	// netpol-owned port groups first
 	predicateIDs := libovsdbops.NewDbObjectIDs(libovsdbops.ACLNetworkPolicy, bnc.controllerName, nil)
-	p := libovsdbops.GetPredicate[*nbdb.ACL](predicateIDs, nil)
-	netpolACLs, err := libovsdbops.FindACLsWithPredicate(bnc.nbClient, p)
+	netpolACLs, err := libovsdbops.FindACLsWithDbIDs(bnc.nbClient, predicateIDs, nil)
 	if err != nil {
 		return fmt.Errorf("cannot find NetworkPolicy ACLs: %v", err)
 	}
@@ -230,8 +322,7 @@ func (bnc *BaseNetworkController) syncNetworkPoliciesCommon(expectedPolicies map
 	}
 	// default deny port groups
 	predicateIDs = libovsdbops.NewDbObjectIDs(libovsdbops.ACLNetpolNamespace, bnc.controllerName, nil)
-	p = libovsdbops.GetPredicate[*nbdb.ACL](predicateIDs, nil)
-	netpolACLs, err = libovsdbops.FindACLsWithPredicate(bnc.nbClient, p)
+	netpolACLs, err = libovsdbops.FindACLsWithDbIDs(bnc.nbClient, predicateIDs, nil)
 	if err != nil {
 		return fmt.Errorf("cannot find default deny NetworkPolicy ACLs: %v", err)
 	}
@@ -253,6 +344,21 @@ func (bnc *BaseNetworkController) syncNetworkPoliciesCommon(expectedPolicies map
 		klog.Infof("Network policy sync cleaned up %d stale port groups", len(stalePGs))
 	}
 
+	// ACL logging meters of the policies that don't exist anymore, the ACLs referencing them are gone with their
+	// port groups
+	predicateIDs = libovsdbops.NewDbObjectIDs(libovsdbops.MeterNetworkPolicy, bnc.controllerName, nil)
+	staleMeter := libovsdbops.GetPredicate[*nbdb.Meter](predicateIDs, func(item *nbdb.Meter) bool {
+		namespace, policyName, err := parseACLPolicyKey(item.ExternalIDs[libovsdbops.ObjectNameKey.String()])
+		return err == nil && !expectedPolicies[namespace][policyName]
+	})
+	ops, err := libovsdbops.DeleteMetersWithPredicateOps(bnc.nbClient, nil, staleMeter)
+	if err != nil {
+		return fmt.Errorf("failed to get ops to delete stale network policy meters: %v", err)
+	}
+	if _, err = libovsdbops.TransactAndCheck(bnc.nbClient, ops); err != nil {
+		return fmt.Errorf("error removing stale network policy meters: %v", err)
+	}
+
 	return nil
 }
 
@@ -428,7 +534,8 @@ func (bnc *BaseNetworkController) updateACLLoggingForPolicy(np *networkPolicy, a
 		libovsdbops.ObjectNameKey: getACLPolicyKey(np.namespace, np.name),
 	})
 	p := libovsdbops.GetPredicate[*nbdb.ACL](predicateIDs, nil)
-	return UpdateACLLoggingWithPredicate(bnc.nbClient, p, aclLogging)
+	return UpdateACLLoggingWithPredicate(bnc.nbClient, p,
+		np.getACLLoggingLevels(aclLogging, bnc.getNetpolACLLoggingMeterName(np.namespace, np.name)))
 }
 
 func (bnc *BaseNetworkController) updateACLLoggingForDefaultACLs(ns string, nsInfo *namespaceInfo) error {
@@ -443,8 +550,7 @@ func (bnc *BaseNetworkController) updateACLLoggingForDefaultACLs(ns string, nsIn
 				libovsdbops.ObjectNameKey: ns,
 				libovsdbops.TypeKey:       string(defaultDenyACL),
 			})
-		p := libovsdbops.GetPredicate[*nbdb.ACL](predicateIDs, nil)
-		defaultDenyACLs, err := libovsdbops.FindACLsWithPredicate(bnc.nbClient, p)
+		defaultDenyACLs, err := libovsdbops.FindACLsWithDbIDs(bnc.nbClient, predicateIDs, nil)
 		if err != nil {
 			return fmt.Errorf("failed to find netpol default deny acls for namespace %s: %v", ns, err)
 		}
@@ -876,7 +982,14 @@ func (bnc *BaseNetworkController) createNetworkPolicy(policy *knet.NetworkPolicy
 		}
 	}
 
-	err := bnc.networkPolicies.DoWithLock(npKey, func(npKey string) error {
+	policyLogging, err := parsePolicyACLLogging(policy)
+	if err != nil {
+		// don't fail the policy for its logging, use the namespace settings instead
+		klog.Warningf("Network policy %s: using the ACL logging settings of the namespace, the policy ones are "+
+			"invalid: %v", npKey, err)
+	}
+
+	err = bnc.networkPolicies.DoWithLock(npKey, func(npKey string) error {
 		oldNP, found := bnc.networkPolicies.Load(npKey)
 		if found {
 			// 1. Cleanup old policy if it failed to be created
@@ -901,10 +1014,12 @@ func (bnc *BaseNetworkController) createNetworkPolicy(policy *knet.NetworkPolicy
 		// no need to check np.deleted, since the object has just been created
 		// now we have a new np stored in bnc.networkPolicies
 		var err error
+		np.aclLogging = policyLogging
 
-		if aclLogging.Deny != "" || aclLogging.Allow != "" {
+		policyACLLogging := np.getACLLoggingLevels(aclLogging, bnc.getNetpolACLLoggingMeterName(np.namespace, np.name))
+		if aclLogging.Deny != "" || policyACLLogging.Allow != "" {
 			klog.Infof("ACL logging for network policy %s in namespace %s set to deny=%s, allow=%s",
-				policy.Name, policy.Namespace, aclLogging.Deny, aclLogging.Allow)
+				policy.Name, policy.Namespace, aclLogging.Deny, policyACLLogging.Allow)
 		}
 
 		// 2. Build gress policies, create addressSets for peers
@@ -983,7 +1098,13 @@ func (bnc *BaseNetworkController) createNetworkPolicy(policy *knet.NetworkPolicy
 		np.portGroupName = portGroupName
 		ops := []ovsdb.Operation{}
 
-		acls := bnc.buildNetworkPolicyACLs(np, aclLogging)
+		if np.aclLogging != nil && np.aclLogging.RateLimit != nil {
+			ops, err = bnc.createNetpolACLLoggingMeterOps(ops, np, *np.aclLogging.RateLimit)
+			if err != nil {
+				return err
+			}
+		}
+		acls := bnc.buildNetworkPolicyACLs(np, policyACLLogging)
 		ops, err = libovsdbops.CreateOrUpdateACLsOps(bnc.nbClient, ops, acls...)
 		if err != nil {
 			return fmt.Errorf("failed to create ACL ops: %v", err)
@@ -1267,6 +1388,13 @@ func (bnc *BaseNetworkController) cleanupNetworkPolicy(np *networkPolicy) error
 	if err != nil {
 		return fmt.Errorf("failed to get delete network policy port group %s ops: %v", np.portGroupName, err)
 	}
+	// Delete the ACL logging meter of the policy, if any, together with the ACLs referencing it
+	meterIDs := bnc.getNetpolACLLoggingMeterDbIDs(np.namespace, np.name)
+	ops, err = libovsdbops.DeleteMetersWithPredicateOps(bnc.nbClient, ops,
+		libovsdbops.GetPredicate[*nbdb.Meter](meterIDs, nil))
+	if err != nil {
+		return fmt.Errorf("failed to get delete network policy meter ops: %v", err)
+	}
 	recordOps, txOkCallBack, _, err := bnc.AddConfigDurationRecord("networkpolicy", np.namespace, np.name)
 	if err != nil {
 		klog.Errorf("Failed to record config duration: %v", err)
@@ -1386,6 +1514,7 @@ func (bnc *BaseNetworkController) peerNamespaceUpdate(np *networkPolicy, gp *gre
 	if np.deleted {
 		return nil
 	}
+	aclLogging = np.getACLLoggingLevels(aclLogging, bnc.getNetpolACLLoggingMeterName(np.namespace, np.name))
 	// buildLocalPodACLs is safe for concurrent use, see function comment for details
 	acls, deletedACLs := gp.buildLocalPodACLs(np.portGroupName, aclLogging)
 	ops, err := libovsdbops.CreateOrUpdateACLsOps(bnc.nbClient, nil, acls...)
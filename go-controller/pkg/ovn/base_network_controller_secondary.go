@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"net"
 	"reflect"
+	"strconv"
 	"time"
 
 	mnpapi "github.com/k8snetworkplumbingwg/multi-networkpolicy/pkg/apis/k8s.cni.cncf.io/v1beta1"
 	nadapi "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	libovsdbclient "github.com/ovn-org/libovsdb/client"
 	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/libovsdbops"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/metrics"
@@ -22,6 +24,9 @@ import (
 	"k8s.io/klog/v2"
 )
 
+// lportTypeRemote is the type of the logical ports of the remote zone pods on the transit switch of a layer2 network
+const lportTypeRemote = "remote"
+
 func (bsnc *BaseSecondaryNetworkController) getPortInfoForSecondaryNetwork(pod *kapi.Pod) map[string]*lpInfo {
 	if util.PodWantsHostNetwork(pod) {
 		return nil
@@ -243,34 +248,132 @@ func (bsnc *BaseSecondaryNetworkController) ensureLocalZonePodForSecondaryNetwor
 
 // ensureRemoteZonePodForSecondaryNetwork tries to set up remote zone pod bits required to interconnect it.
 //   - Adds the remote pod ips to the pod namespace address set for network policy and egress gw
+//   - Adds the remote pod logical ports to the switch of a layer2 network, which is a transit switch with interconnect
 //
 // It returns nil on success and error on failure; failur indicates the pod set up should be retried later.
 func (bsnc *BaseSecondaryNetworkController) ensureRemoteZonePodForSecondaryNetwork(pod *kapi.Pod, addPort bool) error {
+	var ops []ovsdb.Operation
+	var err error
 
-	if !bsnc.doesNetworkRequireIPAM() {
-		return nil
+	if bsnc.isLayer2Interconnect() {
+		ops, err = bsnc.addRemotePodLogicalPortsOps(ops, pod)
+		if err != nil {
+			return err
+		}
 	}
 
-	podIfAddrs, err := util.GetPodCIDRsWithFullMask(pod, bsnc.NetInfo)
-	if err != nil {
-		return fmt.Errorf("failed to get pod ips for the pod  %s/%s : %w", pod.Namespace, pod.Name, err)
+	if bsnc.doesNetworkRequireIPAM() {
+		podIfAddrs, err := util.GetPodCIDRsWithFullMask(pod, bsnc.NetInfo)
+		if err != nil {
+			return fmt.Errorf("failed to get pod ips for the pod  %s/%s : %w", pod.Namespace, pod.Name, err)
+		}
+		if len(podIfAddrs) > 0 {
+			// Ensure the namespace/nsInfo exists
+			addOps, err := bsnc.addPodToNamespaceForSecondaryNetwork(pod.Namespace, podIfAddrs)
+			if err != nil {
+				return err
+			}
+			ops = append(ops, addOps...)
+		}
 	}
-	if len(podIfAddrs) == 0 {
+
+	if len(ops) == 0 {
 		return nil
 	}
+	_, err = libovsdbops.TransactAndCheck(bsnc.nbClient, ops)
+	if err != nil {
+		return fmt.Errorf("could not set up remote pod %s/%s on network %s - %w", pod.Namespace, pod.Name,
+			bsnc.GetNetworkName(), err)
+	}
+
+	return nil
+}
 
-	// Ensure the namespace/nsInfo exists
-	ops, err := bsnc.addPodToNamespaceForSecondaryNetwork(pod.Namespace, podIfAddrs)
+// Reconfigure reconfigures the network with the updated network information. The reconfigurable settings are not
+// part of the logical entities of the network, they are read when the pods are set up.
+func (bsnc *BaseSecondaryNetworkController) Reconfigure(netInfo util.NetInfo) error {
+	return bsnc.NetInfo.Reconfigure(netInfo)
+}
+
+// isLayer2Interconnect returns true if the network switch is a transit switch spanning all the zones, which holds
+// the logical ports of the remote zone pods
+func (bsnc *BaseSecondaryNetworkController) isLayer2Interconnect() bool {
+	return config.OVNKubernetesFeature.EnableInterconnect && bsnc.TopologyType() == types.Layer2Topology
+}
+
+// addRemotePodLogicalPortsOps returns the ops creating the logical ports of a remote zone pod, of type remote and
+// bound to the pod node chassis with the tunnel keys allocated by the cluster manager. The NADs whose addresses are
+// not annotated yet are skipped, the pod is set up again on its annotation update.
+func (bsnc *BaseSecondaryNetworkController) addRemotePodLogicalPortsOps(ops []ovsdb.Operation, pod *kapi.Pod) ([]ovsdb.Operation, error) {
+	on, networkMap, err := util.GetPodNADToNetworkMapping(pod, bsnc.NetInfo)
 	if err != nil {
-		return err
+		// configuration error, no need to retry, do not return error
+		klog.Errorf("Error getting network-attachment for pod %s/%s network %s: %v",
+			pod.Namespace, pod.Name, bsnc.GetNetworkName(), err)
+		return ops, nil
+	}
+	if !on {
+		return ops, nil
 	}
 
-	_, err = libovsdbops.TransactAndCheck(bsnc.nbClient, ops)
+	switchName, err := bsnc.getExpectedSwitchName(pod)
 	if err != nil {
-		return fmt.Errorf("could not add pod IPs to the namespace address set - %w", err)
+		return nil, err
+	}
+	ls := &nbdb.LogicalSwitch{Name: switchName}
+	for nadName := range networkMap {
+		podAnnotation, err := util.UnmarshalPodAnnotation(pod.Annotations, nadName)
+		if err != nil || podAnnotation.TunnelID == 0 {
+			continue
+		}
+		addresses := podAnnotation.MAC.String()
+		for _, podIfAddr := range podAnnotation.IPs {
+			addresses = addresses + " " + podIfAddr.IP.String()
+		}
+		lsp := &nbdb.LogicalSwitchPort{
+			Name:      bsnc.GetLogicalPortName(pod, nadName),
+			Type:      lportTypeRemote,
+			Addresses: []string{addresses},
+			Options: map[string]string{
+				"requested-tnl-key": strconv.Itoa(podAnnotation.TunnelID),
+				"requested-chassis": pod.Spec.NodeName,
+			},
+			ExternalIDs: map[string]string{
+				"namespace":              pod.Namespace,
+				"pod":                    "true",
+				types.NetworkExternalID:  bsnc.GetNetworkName(),
+				types.NADExternalID:      nadName,
+				types.TopologyExternalID: bsnc.TopologyType(),
+			},
+		}
+		ops, err = libovsdbops.CreateOrUpdateLogicalSwitchPortsOnSwitchOps(bsnc.nbClient, ops, ls, lsp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the logical port of remote pod %s/%s for NAD %s: %w",
+				pod.Namespace, pod.Name, nadName, err)
+		}
 	}
+	return ops, nil
+}
 
-	return nil
+// deleteRemotePodLogicalPorts deletes the logical ports of a remote zone pod from the transit switch of a layer2
+// network
+func (bsnc *BaseSecondaryNetworkController) deleteRemotePodLogicalPorts(pod *kapi.Pod) error {
+	switchName, err := bsnc.getExpectedSwitchName(pod)
+	if err != nil {
+		return err
+	}
+	ls := &nbdb.LogicalSwitch{Name: switchName}
+	p := func(item *nbdb.LogicalSwitchPort) bool {
+		return item.Type == lportTypeRemote && item.ExternalIDs["namespace"] == pod.Namespace &&
+			item.ExternalIDs[types.NetworkExternalID] == bsnc.GetNetworkName() &&
+			item.Name == bsnc.GetLogicalPortName(pod, item.ExternalIDs[types.NADExternalID])
+	}
+	ops, err := libovsdbops.DeleteLogicalSwitchPortsWithPredicateOps(bsnc.nbClient, nil, ls, p)
+	if err != nil {
+		return fmt.Errorf("failed to delete the logical ports of remote pod %s/%s: %w", pod.Namespace, pod.Name, err)
+	}
+	_, err = libovsdbops.TransactAndCheck(bsnc.nbClient, ops)
+	return err
 }
 
 func (bsnc *BaseSecondaryNetworkController) addLogicalPortToNetworkForNAD(pod *kapi.Pod, nadName, switchName string,
@@ -288,7 +391,7 @@ func (bsnc *BaseSecondaryNetworkController) addLogicalPortToNetworkForNAD(pod *k
 		return err
 	}
 
-	if bsnc.doesNetworkRequireIPAM() && util.IsMultiNetworkPoliciesSupportEnabled() {
+	if bsnc.doesNetworkRequireNamespaces() {
 		// Ensure the namespace/nsInfo exists
 		addOps, err := bsnc.addPodToNamespaceForSecondaryNetwork(pod.Namespace, podAnnotation.IPs)
 		if err != nil {
@@ -317,7 +420,13 @@ func (bsnc *BaseSecondaryNetworkController) addLogicalPortToNetworkForNAD(pod *k
 		return fmt.Errorf("UUID is empty from LSP: %+v", *lsp)
 	}
 
-	_ = bsnc.logicalPortCache.add(pod, switchName, nadName, lsp.UUID, podAnnotation.MAC, podAnnotation.IPs)
+	portInfo := bsnc.logicalPortCache.add(pod, switchName, nadName, lsp.UUID, podAnnotation.MAC, podAnnotation.IPs)
+
+	if bsnc.multicastSupport {
+		if err := bsnc.podAddMulticastPolicies(pod, portInfo); err != nil {
+			return err
+		}
+	}
 
 	if newlyCreated {
 		metrics.RecordPodCreated(pod, bsnc.NetInfo)
@@ -336,6 +445,15 @@ func (bsnc *BaseSecondaryNetworkController) removePodForSecondaryNetwork(pod *ka
 		return bsnc.removeLocalZonePodForSecondaryNetwork(pod, portInfoMap)
 	}
 
+	if bsnc.isLayer2Interconnect() {
+		if err := bsnc.deleteRemotePodLogicalPorts(pod); err != nil {
+			return err
+		}
+	}
+	if !bsnc.doesNetworkRequireIPAM() {
+		return nil
+	}
+
 	// For remote pods, we just need to remove the pod IPs from the pod namespace address set
 	return bsnc.removeRemoteZonePodFromNamespaceAddressSet(pod)
 }
@@ -376,6 +494,17 @@ func (bsnc *BaseSecondaryNetworkController) removeLocalZonePodForSecondaryNetwor
 			continue
 		}
 
+		// the IPs of pods referencing an IPAMClaim are kept until the claim is deleted
+		claimed, err := bsnc.isPodIPAMClaimed(pod, nadName)
+		if err != nil {
+			return err
+		}
+		if claimed {
+			klog.Infof("Keeping IPs %s of pod %s/%s on network %s, they are held by an IPAMClaim",
+				util.JoinIPNetIPs(pInfo.ips, " "), pod.Namespace, pod.Name, bsnc.GetNetworkName())
+			continue
+		}
+
 		// Releasing IPs needs to happen last so that we can deterministically know that if delete failed that
 		// the IP of the pod needs to be released. Otherwise we could have a completed pod failed to be removed
 		// and we dont know if the IP was released or not, and subsequently could accidentally release the IP
@@ -448,6 +577,61 @@ func (bsnc *BaseSecondaryNetworkController) addPodToNamespaceForSecondaryNetwork
 	return ops, nil
 }
 
+// initMulticast creates the cluster port groups of the network and its default multicast policies when multicast
+// is enabled on the network, or deletes the default multicast policies otherwise. The default allow policy is only
+// needed for the networks with a cluster router, to forward multicast traffic between the node switches.
+func (bsnc *BaseSecondaryNetworkController) initMulticast(withRouter bool) error {
+	if !bsnc.multicastSupport {
+		return bsnc.disableMulticast()
+	}
+
+	// create both port groups whatever the topology, disableMulticast expects them
+	for _, pgBase := range []string{types.ClusterPortGroupNameBase, types.ClusterRtrPortGroupNameBase} {
+		pgName := bsnc.getClusterPortGroupName(pgBase)
+		_, err := libovsdbops.GetPortGroup(bsnc.nbClient, &nbdb.PortGroup{Name: pgName})
+		if err == nil {
+			continue
+		}
+		if err != libovsdbclient.ErrNotFound {
+			return fmt.Errorf("failed to get port group %s of network %s: %v", pgName, bsnc.GetNetworkName(), err)
+		}
+		pg := bsnc.buildPortGroup(pgName, pgName, nil, nil)
+		if err = libovsdbops.CreateOrUpdatePortGroups(bsnc.nbClient, pg); err != nil {
+			return fmt.Errorf("failed to create port group %s of network %s: %v", pgName, bsnc.GetNetworkName(), err)
+		}
+	}
+
+	if err := bsnc.createDefaultDenyMulticastPolicy(); err != nil {
+		return fmt.Errorf("failed to create default deny multicast policy of network %s: %v", bsnc.GetNetworkName(), err)
+	}
+	if withRouter {
+		if err := bsnc.createDefaultAllowMulticastPolicy(); err != nil {
+			return fmt.Errorf("failed to create default allow multicast policy of network %s: %v", bsnc.GetNetworkName(), err)
+		}
+	}
+	return nil
+}
+
+// podAddMulticastPolicies adds the pod's logical switch port to the network's cluster port group, so that the
+// default deny multicast policy applies to its switch, and to the multicast allow policy of its namespace if
+// multicast is enabled for the namespace.
+func (bsnc *BaseSecondaryNetworkController) podAddMulticastPolicies(pod *kapi.Pod, portInfo *lpInfo) error {
+	pgName := bsnc.getClusterPortGroupName(types.ClusterPortGroupNameBase)
+	if err := libovsdbops.AddPortsToPortGroup(bsnc.nbClient, pgName, portInfo.uuid); err != nil {
+		return fmt.Errorf("failed to add pod %s/%s to port group %s: %v", pod.Namespace, pod.Name, pgName, err)
+	}
+
+	// FIXME: there's a race here with the Namespace multicastUpdateNamespace() handler, same as the default network.
+	ns, err := bsnc.watchFactory.GetNamespace(pod.Namespace)
+	if err != nil {
+		return err
+	}
+	if isNamespaceMulticastEnabled(ns.Annotations) {
+		return bsnc.podAddAllowMulticastPolicy(pod.Namespace, portInfo)
+	}
+	return nil
+}
+
 // AddNamespaceForSecondaryNetwork creates corresponding addressset in ovn db for secondary network
 func (bsnc *BaseSecondaryNetworkController) AddNamespaceForSecondaryNetwork(ns *kapi.Namespace) error {
 	klog.Infof("[%s] adding namespace for network %s", ns.Name, bsnc.GetNetworkName())
@@ -558,3 +742,44 @@ func cleanupPolicyLogicalEntities(nbClient libovsdbclient.Client, ops []ovsdb.Op
 	}
 	return ops, nil
 }
+
+// cleanupSecondaryNetworkLogicalEntities deletes all the OVN logical entities of the given network whatever its
+// topology and interconnect mode: the node and layer2 switches, the interconnect transit switch, the cluster
+// router and the policy entities. It only relies on the network name, so that it can be called from a dummy
+// controller, and succeeds when the entities are already gone.
+func cleanupSecondaryNetworkLogicalEntities(nbClient libovsdbclient.Client, netName string) error {
+	ops, err := libovsdbops.DeleteLogicalSwitchesWithPredicateOps(nbClient, nil,
+		func(item *nbdb.LogicalSwitch) bool {
+			return item.ExternalIDs[types.NetworkExternalID] == netName
+		})
+	if err != nil {
+		return fmt.Errorf("failed to get ops for deleting switches of network %s: %v", netName, err)
+	}
+
+	// the transit switch is shared by all the zones and not tagged with the network name, find it by its name
+	transitSwitchName := util.GetSecondaryNetworkPrefix(netName) + types.TransitSwitch
+	ops, err = libovsdbops.DeleteLogicalSwitchOps(nbClient, ops, transitSwitchName)
+	if err != nil {
+		return fmt.Errorf("failed to get ops for deleting transit switch of network %s: %v", netName, err)
+	}
+
+	ops, err = libovsdbops.DeleteLogicalRoutersWithPredicateOps(nbClient, ops,
+		func(item *nbdb.LogicalRouter) bool {
+			return item.ExternalIDs[types.NetworkExternalID] == netName
+		})
+	if err != nil {
+		return fmt.Errorf("failed to get ops for deleting routers of network %s: %v", netName, err)
+	}
+
+	ops, err = cleanupPolicyLogicalEntities(nbClient, ops, netName)
+	if err != nil {
+		return err
+	}
+
+	// a network may have many entities, and the ones left after a failed transaction are deleted on retry
+	_, err = libovsdbops.TransactAndCheckBatched(nbClient, ops)
+	if err != nil {
+		return fmt.Errorf("failed to delete logical entities of network %s: %v", netName, err)
+	}
+	return nil
+}
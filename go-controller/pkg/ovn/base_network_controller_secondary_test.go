@@ -0,0 +1,81 @@
+package ovn
+
+import (
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+	libovsdbclient "github.com/ovn-org/libovsdb/client"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/libovsdbops"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
+	libovsdbtest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing/libovsdb"
+	ovntypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+)
+
+var _ = ginkgo.Describe("Secondary network cleanup", func() {
+	var nbCleanup *libovsdbtest.Cleanup
+
+	ginkgo.AfterEach(func() {
+		if nbCleanup != nil {
+			nbCleanup.Cleanup()
+		}
+	})
+
+	ginkgo.It("deletes all the logical entities of the network and is idempotent", func() {
+		const netName = "blue"
+		networkExternalIDs := map[string]string{
+			ovntypes.NetworkExternalID:  netName,
+			ovntypes.TopologyExternalID: ovntypes.Layer3Topology,
+		}
+		transitSwitchName := util.GetSecondaryNetworkPrefix(netName) + ovntypes.TransitSwitch
+		otherNetworkSwitch := &nbdb.LogicalSwitch{
+			UUID: "red-switch-UUID",
+			Name: "red_node1",
+			ExternalIDs: map[string]string{
+				ovntypes.NetworkExternalID:  "red",
+				ovntypes.TopologyExternalID: ovntypes.Layer3Topology,
+			},
+		}
+		initialNBDB := libovsdbtest.TestSetup{
+			NBData: []libovsdbtest.TestData{
+				&nbdb.LogicalSwitch{
+					UUID:        "blue-switch-UUID",
+					Name:        "blue_node1",
+					ExternalIDs: networkExternalIDs,
+				},
+				&nbdb.LogicalSwitch{
+					UUID:        "blue-transit-switch-UUID",
+					Name:        transitSwitchName,
+					OtherConfig: map[string]string{"interconn-ts": transitSwitchName},
+				},
+				&nbdb.LogicalRouter{
+					UUID:        "blue-router-UUID",
+					Name:        "blue_ovn_cluster_router",
+					ExternalIDs: networkExternalIDs,
+				},
+				&nbdb.PortGroup{
+					UUID:        "blue-pg-UUID",
+					Name:        "blue_pg",
+					ExternalIDs: map[string]string{ovntypes.NetworkExternalID: netName},
+				},
+				&nbdb.AddressSet{
+					UUID: "blue-as-UUID",
+					ExternalIDs: map[string]string{
+						libovsdbops.OwnerControllerKey.String(): netName + "-network-controller",
+					},
+				},
+				otherNetworkSwitch,
+			},
+		}
+		var err error
+		var nbClient libovsdbclient.Client
+		nbClient, nbCleanup, err = libovsdbtest.NewNBTestHarness(initialNBDB, nil)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		gomega.Expect(cleanupSecondaryNetworkLogicalEntities(nbClient, netName)).To(gomega.Succeed())
+		gomega.Eventually(nbClient).Should(libovsdbtest.HaveData([]libovsdbtest.TestData{otherNetworkSwitch}))
+
+		// cleaning up a network that is already gone succeeds
+		gomega.Expect(cleanupSecondaryNetworkLogicalEntities(nbClient, netName)).To(gomega.Succeed())
+	})
+})
@@ -7,17 +7,20 @@ import (
 	"strconv"
 	"time"
 
-	iputils "github.com/containernetworking/plugins/pkg/ip"
 	mnpapi "github.com/k8snetworkplumbingwg/multi-networkpolicy/pkg/apis/k8s.cni.cncf.io/v1beta1"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	ipamclaimsapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/ipamclaims/v1alpha1"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/libovsdbops"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/metrics"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
+	zoneic "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/zone_interconnect"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/retry"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 
+	kapi "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 	utilnet "k8s.io/utils/net"
 )
@@ -106,6 +109,20 @@ func (h *secondaryLayer2NetworkControllerEventHandler) IsResourceScheduled(obj i
 // if any, yielded during object creation.
 // Given an object to add and a boolean specifying if the function was executed from iterateRetryResources
 func (h *secondaryLayer2NetworkControllerEventHandler) AddResource(obj interface{}, fromRetryLoop bool) error {
+	switch h.objType {
+	case factory.IPAMClaimsType:
+		claim, ok := obj.(*ipamclaimsapi.IPAMClaim)
+		if !ok {
+			return fmt.Errorf("could not cast %T object to *ipamclaimsapi.IPAMClaim", obj)
+		}
+		return h.oc.addIPAMClaim(claim)
+	case factory.NodeType:
+		node, ok := obj.(*kapi.Node)
+		if !ok {
+			return fmt.Errorf("could not cast %T object to *kapi.Node", obj)
+		}
+		return h.oc.addUpdateNodeEvent(node)
+	}
 	return h.oc.AddSecondaryNetworkResourceCommon(h.objType, obj)
 }
 
@@ -114,6 +131,14 @@ func (h *secondaryLayer2NetworkControllerEventHandler) AddResource(obj interface
 // Given an old and a new object; The inRetryCache boolean argument is to indicate if the given resource
 // is in the retryCache or not.
 func (h *secondaryLayer2NetworkControllerEventHandler) UpdateResource(oldObj, newObj interface{}, inRetryCache bool) error {
+	switch h.objType {
+	case factory.NodeType:
+		node, ok := newObj.(*kapi.Node)
+		if !ok {
+			return fmt.Errorf("could not cast newObj of type %T to *kapi.Node", newObj)
+		}
+		return h.oc.addUpdateNodeEvent(node)
+	}
 	return h.oc.UpdateSecondaryNetworkResourceCommon(h.objType, oldObj, newObj, inRetryCache)
 }
 
@@ -121,6 +146,21 @@ func (h *secondaryLayer2NetworkControllerEventHandler) UpdateResource(oldObj, ne
 // Given an object and optionally a cachedObj; cachedObj is the internal cache entry for this object,
 // used for now for pods and network policies.
 func (h *secondaryLayer2NetworkControllerEventHandler) DeleteResource(obj, cachedObj interface{}) error {
+	switch h.objType {
+	case factory.IPAMClaimsType:
+		claim, ok := obj.(*ipamclaimsapi.IPAMClaim)
+		if !ok {
+			return fmt.Errorf("could not cast %T object to *ipamclaimsapi.IPAMClaim", obj)
+		}
+		return h.oc.deleteIPAMClaim(claim)
+	case factory.NodeType:
+		node, ok := obj.(*kapi.Node)
+		if !ok {
+			return fmt.Errorf("could not cast obj of type %T to *kapi.Node", obj)
+		}
+		h.oc.localZoneNodes.Delete(node.Name)
+		return nil
+	}
 	return h.oc.DeleteSecondaryNetworkResourceCommon(h.objType, obj, cachedObj)
 }
 
@@ -135,12 +175,19 @@ func (h *secondaryLayer2NetworkControllerEventHandler) SyncFunc(objs []interface
 		case factory.PodType:
 			syncFunc = h.oc.syncPodsForSecondaryNetwork
 
+		case factory.NodeType:
+			syncFunc = h.oc.syncNodes
+
 		case factory.NamespaceType:
 			syncFunc = h.oc.syncNamespaces
 
 		case factory.MultiNetworkPolicyType:
 			syncFunc = h.oc.syncMultiNetworkPolicies
 
+		case factory.IPAMClaimsType:
+			// the IPs of existing claims are reserved when they are added
+			syncFunc = nil
+
 		default:
 			return fmt.Errorf("no sync function for object type %s", h.objType)
 		}
@@ -161,18 +208,34 @@ func (h *secondaryLayer2NetworkControllerEventHandler) IsObjectInTerminalState(o
 // configuration for secondary layer2/localnet network controller
 type BaseSecondaryLayer2NetworkController struct {
 	BaseSecondaryNetworkController
+
+	// retry framework for IPAMClaims, only set when the network allows persistent IPs
+	retryIPAMClaims *retry.RetryFramework
+	// IPAMClaim events factory handler
+	ipamClaimsHandler *factory.Handler
 }
 
 func (oc *BaseSecondaryLayer2NetworkController) initRetryFramework() {
 	oc.retryPods = oc.newRetryFramework(factory.PodType)
 
+	// With interconnect, only the pods of the local zone nodes get local logical ports
+	if oc.localZoneNodes != nil {
+		oc.retryNodes = oc.newRetryFramework(factory.NodeType)
+	}
+
 	// For secondary networks, we don't have to watch namespace events if
-	// multi-network policy support is not enabled. We don't support
-	// multi-network policy for IPAM-less secondary networks either.
-	if util.IsMultiNetworkPoliciesSupportEnabled() && oc.doesNetworkRequireIPAM() {
+	// neither multi-network policy support nor multicast is enabled. We don't
+	// support them for IPAM-less secondary networks either.
+	if oc.doesNetworkRequireNamespaces() {
 		oc.retryNamespaces = oc.newRetryFramework(factory.NamespaceType)
+	}
+	if util.IsMultiNetworkPoliciesSupportEnabled() && oc.doesNetworkRequireIPAM() {
 		oc.retryNetworkPolicies = oc.newRetryFramework(factory.MultiNetworkPolicyType)
 	}
+
+	if oc.allowPersistentIPs() {
+		oc.retryIPAMClaims = oc.newRetryFramework(factory.IPAMClaimsType)
+	}
 }
 
 // newRetryFramework builds and returns a retry framework for the input resource type;
@@ -188,6 +251,7 @@ func (oc *BaseSecondaryLayer2NetworkController) newRetryFramework(
 	resourceHandler := &retry.ResourceHandler{
 		HasUpdateFunc:          hasResourceAnUpdateFunc(objectType),
 		NeedsUpdateDuringRetry: needsUpdateDuringRetry(objectType),
+		MaxFailedAttempts:      maxFailedAttempts(objectType),
 		ObjType:                objectType,
 		EventHandler:           eventHandler,
 	}
@@ -211,36 +275,22 @@ func (oc *BaseSecondaryLayer2NetworkController) Stop() {
 	if oc.podHandler != nil {
 		oc.watchFactory.RemovePodHandler(oc.podHandler)
 	}
+	if oc.nodeHandler != nil {
+		oc.watchFactory.RemoveNodeHandler(oc.nodeHandler)
+	}
 	if oc.namespaceHandler != nil {
 		oc.watchFactory.RemoveNamespaceHandler(oc.namespaceHandler)
 	}
+	if oc.ipamClaimsHandler != nil {
+		oc.watchFactory.RemoveIPAMClaimsHandler(oc.ipamClaimsHandler)
+	}
 }
 
 // cleanup cleans up logical entities for the given network, called from net-attach-def routine
 // could be called from a dummy Controller (only has CommonNetworkControllerInfo set)
 func (oc *BaseSecondaryLayer2NetworkController) cleanup(topotype, netName string) error {
 	klog.Infof("Delete OVN logical entities for %s network controller of network %s", topotype, netName)
-
-	// delete layer 2 logical switches
-	ops, err := libovsdbops.DeleteLogicalSwitchesWithPredicateOps(oc.nbClient, nil,
-		func(item *nbdb.LogicalSwitch) bool {
-			return item.ExternalIDs[types.NetworkExternalID] == netName
-		})
-	if err != nil {
-		return fmt.Errorf("failed to get ops for deleting switches of network %s: %v", netName, err)
-	}
-
-	ops, err = cleanupPolicyLogicalEntities(oc.nbClient, ops, netName)
-	if err != nil {
-		return err
-	}
-
-	_, err = libovsdbops.TransactAndCheck(oc.nbClient, ops)
-	if err != nil {
-		return fmt.Errorf("failed to deleting switches of network %s: %v", netName, err)
-	}
-
-	return nil
+	return cleanupSecondaryNetworkLogicalEntities(oc.nbClient, netName)
 }
 
 func (oc *BaseSecondaryLayer2NetworkController) Run() error {
@@ -253,6 +303,18 @@ func (oc *BaseSecondaryLayer2NetworkController) Run() error {
 		return err
 	}
 
+	// WatchIPAMClaims needs to reserve the claimed IPs before pods get IPs allocated
+	if err := oc.WatchIPAMClaims(); err != nil {
+		return err
+	}
+
+	// WatchNodes needs to find the local zone nodes before the pods are set up
+	if oc.retryNodes != nil {
+		if err := oc.WatchNodes(); err != nil {
+			return err
+		}
+	}
+
 	if err := oc.WatchPods(); err != nil {
 		return err
 	}
@@ -272,6 +334,71 @@ func (oc *BaseSecondaryLayer2NetworkController) Run() error {
 	return nil
 }
 
+// addUpdateNodeEvent tracks the local zone nodes of the network. The pods of a node moving to or from the local zone
+// are added again, to set them up as local or remote zone pods. The switch of a layer2 network is made a transit
+// switch once the cluster manager has annotated the network id on the local zone nodes.
+func (oc *BaseSecondaryLayer2NetworkController) addUpdateNodeEvent(node *kapi.Node) error {
+	_, wasLocal := oc.localZoneNodes.Load(node.Name)
+	isLocal := oc.isLocalZoneNode(node)
+	if isLocal {
+		oc.localZoneNodes.Store(node.Name, true)
+		if oc.isLayer2Interconnect() {
+			if err := oc.ensureTransitSwitch(node); err != nil {
+				return err
+			}
+		}
+	} else {
+		oc.localZoneNodes.Delete(node.Name)
+	}
+
+	if wasLocal != isLocal {
+		klog.Infof("Node %s of network %s moved to zone %s", node.Name, oc.GetNetworkName(), util.GetNodeZone(node))
+		return kerrors.NewAggregate(oc.addAllPodsOnNode(node.Name))
+	}
+	return nil
+}
+
+// syncNodes finds the local zone nodes, the pods of the network are synced afterwards
+func (oc *BaseSecondaryLayer2NetworkController) syncNodes(nodes []interface{}) error {
+	for _, tmp := range nodes {
+		node, ok := tmp.(*kapi.Node)
+		if !ok {
+			return fmt.Errorf("spurious object in syncNodes: %v", tmp)
+		}
+		if oc.isLocalZoneNode(node) {
+			oc.localZoneNodes.Store(node.Name, true)
+		}
+	}
+	return nil
+}
+
+// ensureTransitSwitch configures the switch of the layer2 network as a transit switch, with the tunnel key derived
+// from the network id annotated on the given node
+func (oc *BaseSecondaryLayer2NetworkController) ensureTransitSwitch(node *kapi.Node) error {
+	networkID, err := util.ParseNetworkIDAnnotation(node, oc.GetNetworkName())
+	if err != nil {
+		return fmt.Errorf("failed to get the network id for the network %s on node %s: %v", oc.GetNetworkName(), node.Name, err)
+	}
+	switchName := oc.GetNetworkScopedName(types.OVNLayer2Switch)
+	logicalSwitch := nbdb.LogicalSwitch{
+		Name:        switchName,
+		OtherConfig: getTransitSwitchOtherConfig(switchName, networkID),
+	}
+	if err := libovsdbops.UpdateLogicalSwitchSetOtherConfig(oc.nbClient, &logicalSwitch); err != nil {
+		return fmt.Errorf("failed to configure the transit switch %s: %w", switchName, err)
+	}
+	return nil
+}
+
+// getTransitSwitchOtherConfig returns the other config of a transit switch spanning all the zones. Unlike the
+// transit switch of the default network, multicast is not enabled on secondary networks.
+func getTransitSwitchOtherConfig(switchName string, networkID int) map[string]string {
+	return map[string]string{
+		"interconn-ts":      switchName,
+		"requested-tnl-key": strconv.Itoa(zoneic.BaseTransitSwitchTunnelKey + networkID),
+	}
+}
+
 func (oc *BaseSecondaryLayer2NetworkController) InitializeLogicalSwitch(switchName string, clusterSubnets []config.CIDRNetworkEntry,
 	excludeSubnets []*net.IPNet) (*nbdb.LogicalSwitch, error) {
 	logicalSwitch := nbdb.LogicalSwitch{
@@ -286,10 +413,38 @@ func (oc *BaseSecondaryLayer2NetworkController) InitializeLogicalSwitch(switchNa
 	for _, clusterSubnet := range clusterSubnets {
 		subnet := clusterSubnet.CIDR
 		hostSubnets = append(hostSubnets, subnet)
+		if logicalSwitch.OtherConfig == nil {
+			logicalSwitch.OtherConfig = map[string]string{}
+		}
+		// dual-stack networks configure both the IPv4 and IPv6 subnets on the switch
 		if utilnet.IsIPv6CIDR(subnet) {
-			logicalSwitch.OtherConfig = map[string]string{"ipv6_prefix": subnet.IP.String()}
+			logicalSwitch.OtherConfig["ipv6_prefix"] = subnet.IP.String()
 		} else {
-			logicalSwitch.OtherConfig = map[string]string{"subnet": subnet.String()}
+			logicalSwitch.OtherConfig["subnet"] = subnet.String()
+		}
+	}
+
+	// If enabled, enable IGMP/MLD snooping on the switch. There is no router on the network to act as querier.
+	if oc.multicastSupport {
+		if logicalSwitch.OtherConfig == nil {
+			logicalSwitch.OtherConfig = map[string]string{}
+		}
+		logicalSwitch.OtherConfig["mcast_snoop"] = "true"
+		logicalSwitch.OtherConfig["mcast_querier"] = "false"
+	}
+
+	if oc.isLayer2Interconnect() {
+		// keep the transit switch configuration, set with the network id of the local zone nodes
+		existingSwitch, err := libovsdbops.GetLogicalSwitch(oc.nbClient, &nbdb.LogicalSwitch{Name: switchName})
+		if err == nil {
+			if logicalSwitch.OtherConfig == nil {
+				logicalSwitch.OtherConfig = map[string]string{}
+			}
+			for key := range getTransitSwitchOtherConfig(switchName, 0) {
+				if value, ok := existingSwitch.OtherConfig[key]; ok {
+					logicalSwitch.OtherConfig[key] = value
+				}
+			}
 		}
 	}
 
@@ -303,16 +458,8 @@ func (oc *BaseSecondaryLayer2NetworkController) InitializeLogicalSwitch(switchNa
 	}
 
 	// FIXME: allocate IP ranges when https://github.com/ovn-org/ovn-kubernetes/issues/3369 is fixed
-	for _, excludeSubnet := range excludeSubnets {
-		for excludeIP := excludeSubnet.IP; excludeSubnet.Contains(excludeIP); excludeIP = iputils.NextIP(excludeIP) {
-			var ipMask net.IPMask
-			if excludeIP.To4() != nil {
-				ipMask = net.CIDRMask(32, 32)
-			} else {
-				ipMask = net.CIDRMask(128, 128)
-			}
-			_ = oc.lsManager.AllocateIPs(switchName, []*net.IPNet{{IP: excludeIP, Mask: ipMask}})
-		}
+	if err = oc.lsManager.ExcludeSubnets(switchName, excludeSubnets); err != nil {
+		return nil, err
 	}
 
 	return &logicalSwitch, nil
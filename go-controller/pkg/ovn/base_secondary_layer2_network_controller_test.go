@@ -0,0 +1,249 @@
+package ovn
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	cnitypes "github.com/containernetworking/cni/pkg/types"
+	nettypes "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+	"github.com/urfave/cli/v2"
+
+	ovncnitypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/cni/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/libovsdbops"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
+	zoneic "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/zone_interconnect"
+	ovntest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing"
+	libovsdbtest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing/libovsdb"
+	ovntypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+var _ = ginkgo.Describe("OVN secondary layer2 network with interconnect", func() {
+	const (
+		namespaceName        = "namespace1"
+		localNodeName        = "node1"
+		remoteNodeName       = "node2"
+		secondaryNetworkName = "network1"
+		nadName              = "nad1"
+		networkID            = 2
+	)
+	var (
+		app     *cli.App
+		fakeOvn *FakeOVN
+		nad     *nettypes.NetworkAttachmentDefinition
+	)
+
+	ginkgo.BeforeEach(func() {
+		var err error
+		// Restore global default values before each testcase
+		config.PrepareTestConfig()
+		config.OVNKubernetesFeature.EnableMultiNetwork = true
+		config.OVNKubernetesFeature.EnableInterconnect = true
+
+		app = cli.NewApp()
+		app.Name = "test"
+		app.Flags = config.Flags
+
+		fakeOvn = NewFakeOVN(true)
+
+		nad, err = newNetworkAttachmentDefinition(
+			namespaceName,
+			nadName,
+			ovncnitypes.NetConf{
+				NetConf: cnitypes.NetConf{
+					Name: secondaryNetworkName,
+					Type: "ovn-k8s-cni-overlay",
+				},
+				Topology: ovntypes.Layer2Topology,
+				NADName:  util.GetNADName(namespaceName, nadName),
+				Subnets:  "10.1.1.0/24",
+			},
+		)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.AfterEach(func() {
+		fakeOvn.shutdown()
+	})
+
+	newICNode := func(name, zone string) v1.Node {
+		node := newNode(name, "192.168.126.202/24")
+		node.Annotations["k8s.ovn.org/zone-name"] = zone
+		node.Annotations["k8s.ovn.org/network-ids"] = fmt.Sprintf(`{"default":"0",%q:"%d"}`,
+			secondaryNetworkName, networkID)
+		return *node
+	}
+
+	newAnnotatedPod := func(name, nodeName, ip string, tunnelID int) v1.Pod {
+		pod := newPod(namespaceName, name, nodeName, "")
+		pod.Annotations = map[string]string{
+			nettypes.NetworkAttachmentAnnot: fmt.Sprintf(`[{"name":%q,"namespace":%q}]`, nadName, namespaceName),
+		}
+		var err error
+		pod.Annotations, err = util.MarshalPodAnnotation(pod.Annotations, &util.PodAnnotation{
+			IPs:      ovntest.MustParseIPNets(ip + "/24"),
+			MAC:      util.IPAddrToHWAddr(ovntest.MustParseIP(ip)),
+			TunnelID: tunnelID,
+		}, util.GetNADName(namespaceName, nadName))
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		return *pod
+	}
+
+	ginkgo.It("makes the network switch a transit switch holding the ports of the remote pods", func() {
+		app.Action = func(ctx *cli.Context) error {
+			switchName := secondaryNetworkName + "_" + ovntypes.OVNLayer2Switch
+			layer2Switch := &nbdb.LogicalSwitch{
+				Name:        switchName,
+				UUID:        switchName + "_UUID",
+				ExternalIDs: map[string]string{ovntypes.NetworkExternalID: secondaryNetworkName},
+			}
+			localPod := newAnnotatedPod("pod1", localNodeName, "10.1.1.4", 3)
+			remotePod := newAnnotatedPod("pod2", remoteNodeName, "10.1.1.5", 4)
+			fakeOvn.startWithDBSetup(libovsdbtest.TestSetup{NBData: []libovsdbtest.TestData{layer2Switch}},
+				&v1.NamespaceList{
+					Items: []v1.Namespace{*newNamespace(namespaceName)},
+				},
+				&v1.NodeList{
+					Items: []v1.Node{newICNode(localNodeName, "global"), newICNode(remoteNodeName, "remote")},
+				},
+				&v1.PodList{
+					Items: []v1.Pod{localPod, remotePod},
+				},
+				&nettypes.NetworkAttachmentDefinitionList{
+					Items: []nettypes.NetworkAttachmentDefinition{*nad},
+				},
+			)
+			ocInfo, ok := fakeOvn.secondaryControllers[secondaryNetworkName]
+			gomega.Expect(ok).To(gomega.BeTrue())
+			oc := ocInfo.bl2nc
+			gomega.Expect(oc).NotTo(gomega.BeNil())
+
+			err := oc.lsManager.AddSwitch(oc.getLogicalSwitchName(), layer2Switch.UUID,
+				[]*net.IPNet{ovntest.MustParseIPNet("10.1.1.0/24")})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			gomega.Expect(oc.WatchNodes()).To(gomega.Succeed())
+			gomega.Expect(oc.WatchPods()).To(gomega.Succeed())
+
+			ls, err := libovsdbops.GetLogicalSwitch(fakeOvn.nbClient, &nbdb.LogicalSwitch{Name: switchName})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(ls.OtherConfig).To(gomega.HaveKeyWithValue("interconn-ts", switchName))
+			gomega.Expect(ls.OtherConfig).To(gomega.HaveKeyWithValue("requested-tnl-key",
+				strconv.Itoa(zoneic.BaseTransitSwitchTunnelKey+networkID)))
+			gomega.Expect(ls.OtherConfig).NotTo(gomega.HaveKey("mcast_snoop"))
+
+			getPort := func(pod v1.Pod) func() (*nbdb.LogicalSwitchPort, error) {
+				return func() (*nbdb.LogicalSwitchPort, error) {
+					return libovsdbops.GetLogicalSwitchPort(fakeOvn.nbClient, &nbdb.LogicalSwitchPort{
+						Name: util.GetSecondaryNetworkLogicalPortName(namespaceName, pod.Name,
+							util.GetNADName(namespaceName, nadName)),
+					})
+				}
+			}
+			gomega.Eventually(getPort(localPod)).Should(gomega.And(
+				gomega.HaveField("Type", ""),
+				gomega.HaveField("Options", gomega.HaveKeyWithValue("requested-tnl-key", "3")),
+			))
+			gomega.Eventually(getPort(remotePod)).Should(gomega.And(
+				gomega.HaveField("Type", lportTypeRemote),
+				gomega.HaveField("Addresses", []string{"0a:58:0a:01:01:05 10.1.1.5"}),
+				gomega.HaveField("Options", gomega.Equal(map[string]string{
+					"requested-tnl-key": "4",
+					"requested-chassis": remoteNodeName,
+				})),
+			))
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("enables multicast snooping and the default deny multicast policy when the network enables multicast", func() {
+		app.Action = func(ctx *cli.Context) error {
+			config.EnableMulticast = true
+			var err error
+			nad, err = newNetworkAttachmentDefinition(
+				namespaceName,
+				nadName,
+				ovncnitypes.NetConf{
+					NetConf: cnitypes.NetConf{
+						Name: secondaryNetworkName,
+						Type: "ovn-k8s-cni-overlay",
+					},
+					Topology:        ovntypes.Layer2Topology,
+					NADName:         util.GetNADName(namespaceName, nadName),
+					Subnets:         "10.1.1.0/24",
+					EnableMulticast: true,
+				},
+			)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			namespace := newNamespace(namespaceName)
+			namespace.Annotations = map[string]string{util.NsMulticastAnnotation: "true"}
+			localPod := newAnnotatedPod("pod1", localNodeName, "10.1.1.4", 3)
+			fakeOvn.startWithDBSetup(libovsdbtest.TestSetup{},
+				&v1.NamespaceList{
+					Items: []v1.Namespace{*namespace},
+				},
+				&v1.NodeList{
+					Items: []v1.Node{newICNode(localNodeName, "global")},
+				},
+				&v1.PodList{
+					Items: []v1.Pod{localPod},
+				},
+				&nettypes.NetworkAttachmentDefinitionList{
+					Items: []nettypes.NetworkAttachmentDefinition{*nad},
+				},
+			)
+			ocInfo, ok := fakeOvn.secondaryControllers[secondaryNetworkName]
+			gomega.Expect(ok).To(gomega.BeTrue())
+			oc := ocInfo.bl2nc
+			gomega.Expect(oc).NotTo(gomega.BeNil())
+			gomega.Expect(oc.multicastSupport).To(gomega.BeTrue())
+
+			// this is what Init does for the layer2 networks
+			switchName := secondaryNetworkName + "_" + ovntypes.OVNLayer2Switch
+			gomega.Expect(oc.initMulticast(false)).To(gomega.Succeed())
+			_, err = oc.InitializeLogicalSwitch(switchName, oc.Subnets(), oc.ExcludeSubnets())
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(oc.WatchNamespaces()).To(gomega.Succeed())
+			gomega.Expect(oc.WatchNodes()).To(gomega.Succeed())
+			gomega.Expect(oc.WatchPods()).To(gomega.Succeed())
+
+			ls, err := libovsdbops.GetLogicalSwitch(fakeOvn.nbClient, &nbdb.LogicalSwitch{Name: switchName})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(ls.OtherConfig).To(gomega.HaveKeyWithValue("mcast_snoop", "true"))
+			gomega.Expect(ls.OtherConfig).To(gomega.HaveKeyWithValue("mcast_querier", "false"))
+
+			lsp, err := libovsdbops.GetLogicalSwitchPort(fakeOvn.nbClient, &nbdb.LogicalSwitchPort{
+				Name: util.GetSecondaryNetworkLogicalPortName(namespaceName, localPod.Name,
+					util.GetNADName(namespaceName, nadName)),
+			})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			// the pod is in the cluster port group holding the default deny ACLs
+			clusterPG, err := libovsdbops.GetPortGroup(fakeOvn.nbClient,
+				&nbdb.PortGroup{Name: oc.getClusterPortGroupName(ovntypes.ClusterPortGroupNameBase)})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(clusterPG.Ports).To(gomega.ConsistOf(lsp.UUID))
+			gomega.Expect(clusterPG.ACLs).To(gomega.HaveLen(2))
+
+			// and in the multicast port group of its namespace, which enables multicast
+			namespacePG, err := libovsdbops.GetPortGroup(fakeOvn.nbClient,
+				&nbdb.PortGroup{Name: oc.getMulticastPortGroupName(namespaceName)})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(namespacePG.Ports).To(gomega.ConsistOf(lsp.UUID))
+			gomega.Expect(namespacePG.ACLs).To(gomega.HaveLen(2))
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+})
@@ -35,8 +35,16 @@ type lbConfig struct {
 	// if true, then vips added on the switch are in "local" mode
 	// that means, remove any non-local endpoints.
 	internalTrafficLocal bool
+	// if true, then vips added on the router of a node with local endpoints
+	// are in "local" mode (skipSNAT, and remove any non-local endpoints) while
+	// the routers of the other nodes still SNAT to all the endpoints.
+	// (see below)
+	preserveSourceIP bool
 	// indicates if this LB is configuring service of type NodePort.
 	hasNodePort bool
+	// if true, then the cluster IP vips added on the switch of a node only
+	// target the endpoints hinted for the topology zone of the node, if any.
+	topologyAware bool
 }
 
 func (c *lbConfig) makeNodeSwitchTargetIPs(node *nodeInfo, epIPs []string) (targetIPs []string, changed bool) {
@@ -62,6 +70,26 @@ func (c *lbConfig) makeNodeSwitchTargetIPs(node *nodeInfo, epIPs []string) (targ
 	return
 }
 
+// makeNodeSwitchZoneTargetIPs returns the switch targets of a topology aware
+// config: the endpoints hinted for the topology zone of the node. Like
+// kube-proxy, it falls back to all the endpoints when the node has no zone or
+// none of the endpoints is hinted for it.
+func (c *lbConfig) makeNodeSwitchZoneTargetIPs(node *nodeInfo, epIPs []string) []string {
+	if node.topologyZone == "" {
+		return epIPs
+	}
+	targetIPs := make([]string, 0, len(epIPs))
+	for _, ip := range epIPs {
+		if c.eps.ZoneHints[ip].Has(node.topologyZone) {
+			targetIPs = append(targetIPs, ip)
+		}
+	}
+	if len(targetIPs) == 0 {
+		return epIPs
+	}
+	return targetIPs
+}
+
 func (c *lbConfig) makeNodeRouterTargetIPs(node *nodeInfo, epIPs []string, hostMasqueradeIP string) (targetIPs []string, changed bool) {
 	targetIPs = epIPs
 	changed = false
@@ -83,6 +111,14 @@ func (c *lbConfig) makeNodeRouterTargetIPs(node *nodeInfo, epIPs []string, hostM
 	return
 }
 
+// makeNodeRouterLocalTargetIPs returns the router targets, without SNAT, for a
+// config preserving the source IP: the endpoints in the pod subnets of the
+// node. Unlike with ExternalTrafficPolicy=Local, host-networked endpoints are
+// not included as ovnkube-node doesn't steer their traffic to the host.
+func (c *lbConfig) makeNodeRouterLocalTargetIPs(node *nodeInfo, epIPs []string) []string {
+	return util.FilterIPsSlice(epIPs, node.podSubnets, true)
+}
+
 // just used for consistent ordering
 var protos = []v1.Protocol{
 	v1.ProtocolTCP,
@@ -106,12 +142,17 @@ var protos = []v1.Protocol{
 // - services with host-network endpoints
 // - services with ExternalTrafficPolicy=Local
 // - services with InternalTrafficPolicy=Local
+// - services with topology aware routing, when enabled
+//
+// - services preserving the source IP
 //
 // Template LBs will be created for
-//   - services with NodePort set but *without* ExternalTrafficPolicy=Local or
-//     affinity timeout set.
+//   - services with NodePort set but *without* ExternalTrafficPolicy=Local,
+//     source IP preservation or affinity timeout set.
 func buildServiceLBConfigs(service *v1.Service, endpointSlices []*discovery.EndpointSlice, useLBGroup, useTemplates bool) (perNodeConfigs, templateConfigs, clusterConfigs []lbConfig) {
 	needsAffinityTimeout := hasSessionAffinityTimeOut(service)
+	preserveSourceIP := service.Spec.ExternalTrafficPolicy != v1.ServiceExternalTrafficPolicyTypeLocal &&
+		hasPreserveSourceIP(service)
 
 	// For each svcPort, determine if it will be applied per-node or cluster-wide
 	for _, svcPort := range service.Spec.Ports {
@@ -132,12 +173,13 @@ func buildServiceLBConfigs(service *v1.Service, endpointSlices []*discovery.Endp
 				eps:                  eps,
 				externalTrafficLocal: externalTrafficLocal,
 				internalTrafficLocal: false, // always false for non-ClusterIPs
+				preserveSourceIP:     preserveSourceIP,
 				hasNodePort:          true,
 			}
-			// Only "plain" NodePort services (no ETP, no affinity timeout)
-			// can use load balancer templates.
+			// Only "plain" NodePort services (no ETP, no source IP
+			// preservation, no affinity timeout) can use load balancer templates.
 			if !useLBGroup || !useTemplates || externalTrafficLocal ||
-				needsAffinityTimeout {
+				preserveSourceIP || needsAffinityTimeout {
 				perNodeConfigs = append(perNodeConfigs, nodePortLBConfig)
 			} else {
 				templateConfigs = append(templateConfigs, nodePortLBConfig)
@@ -148,17 +190,18 @@ func buildServiceLBConfigs(service *v1.Service, endpointSlices []*discovery.Endp
 		vips := util.GetClusterIPs(service)
 		externalVips := util.GetExternalAndLBIPs(service)
 
-		// if ETP=Local, then treat ExternalIPs and LoadBalancer IPs specially
-		// otherwise, they're just cluster IPs
+		// if ETP=Local or the source IP is preserved, then treat ExternalIPs
+		// and LoadBalancer IPs specially otherwise, they're just cluster IPs
 		// This is NEVER influenced by InternalTrafficPolicy
-		if externalTrafficLocal && len(externalVips) > 0 {
+		if (externalTrafficLocal || preserveSourceIP) && len(externalVips) > 0 {
 			externalIPConfig := lbConfig{
 				protocol:             svcPort.Protocol,
 				inport:               svcPort.Port,
 				vips:                 externalVips,
 				eps:                  eps,
-				externalTrafficLocal: true,
+				externalTrafficLocal: externalTrafficLocal,
 				internalTrafficLocal: false, // always false for non-ClusterIPs
+				preserveSourceIP:     preserveSourceIP,
 				hasNodePort:          false,
 			}
 			perNodeConfigs = append(perNodeConfigs, externalIPConfig)
@@ -168,6 +211,7 @@ func buildServiceLBConfigs(service *v1.Service, endpointSlices []*discovery.Endp
 
 		// Build the clusterIP config
 		// This is NEVER influenced by ExternalTrafficPolicy
+		// Topology aware routing is ignored with InternalTrafficPolicy=Local, like in kube-proxy
 		clusterIPConfig := lbConfig{
 			protocol:             svcPort.Protocol,
 			inport:               svcPort.Port,
@@ -176,6 +220,7 @@ func buildServiceLBConfigs(service *v1.Service, endpointSlices []*discovery.Endp
 			externalTrafficLocal: false, // always false for ClusterIPs
 			internalTrafficLocal: internalTrafficLocal,
 			hasNodePort:          false,
+			topologyAware:        !internalTrafficLocal && eps.ZoneHints != nil && hasTopologyAwareRouting(service),
 		}
 
 		// Normally, the ClusterIP LB is global (on all node switches and routers),
@@ -184,7 +229,8 @@ func buildServiceLBConfigs(service *v1.Service, endpointSlices []*discovery.Endp
 		// - ETP=local service backed by non-local-host-networked endpoints
 		//
 		// In that case, we need to create per-node LBs.
-		if hasHostEndpoints(eps.V4IPs) || hasHostEndpoints(eps.V6IPs) || internalTrafficLocal {
+		if hasHostEndpoints(eps.V4IPs) || hasHostEndpoints(eps.V6IPs) || internalTrafficLocal ||
+			clusterIPConfig.topologyAware {
 			perNodeConfigs = append(perNodeConfigs, clusterIPConfig)
 		} else {
 			clusterConfigs = append(clusterConfigs, clusterIPConfig)
@@ -540,6 +586,15 @@ func buildTemplateLBs(service *v1.Service, configs []lbConfig, nodes []nodeInfo,
 // - SkipSNAT enabled
 // - NP LB on the switch will have masqueradeIP as the vip to handle etp=local for LGW case.
 // This results in the creation of an additional load balancer on the GatewayRouters and NodeSwitches.
+//
+// For services preserving the source IP, all "External" IPs on the GatewayRouter of a node with
+// local pod endpoints have targets filtered to only those endpoints and SkipSNAT enabled. Their
+// replies go back through that GatewayRouter via the source IP route of the node's pod subnet on
+// the cluster router, so no additional route is needed. The GatewayRouters of the other nodes
+// keep SNAT and all the targets, so that, unlike with ExternalTrafficPolicy=Local, the service
+// is still reachable through them. Host-networked endpoints are always reached with SNAT: the
+// node side only steers external traffic to local host-networked endpoints for
+// ExternalTrafficPolicy=Local.
 func buildPerNodeLBs(service *v1.Service, configs []lbConfig, nodes []nodeInfo) []LB {
 	cbp := configsByProto(configs)
 	eids := util.ExternalIDsForObject(service)
@@ -566,12 +621,24 @@ func buildPerNodeLBs(service *v1.Service, configs []lbConfig, nodes []nodeInfo)
 				switchV4targetips, _ := config.makeNodeSwitchTargetIPs(&node, config.eps.V4IPs)
 				switchV6targetips, _ := config.makeNodeSwitchTargetIPs(&node, config.eps.V6IPs)
 
+				var switchV4ZoneTargets, switchV6ZoneTargets []Addr
+				if config.topologyAware {
+					switchV4ZoneTargets = joinHostsPort(config.makeNodeSwitchZoneTargetIPs(&node, config.eps.V4IPs), config.eps.Port)
+					switchV6ZoneTargets = joinHostsPort(config.makeNodeSwitchZoneTargetIPs(&node, config.eps.V6IPs), config.eps.Port)
+				}
+
 				routerV4targetips, _ := config.makeNodeRouterTargetIPs(&node, config.eps.V4IPs, types.V4HostMasqueradeIP)
 				routerV6targetips, _ := config.makeNodeRouterTargetIPs(&node, config.eps.V6IPs, types.V6HostMasqueradeIP)
 
 				routerV4targets := joinHostsPort(routerV4targetips, config.eps.Port)
 				routerV6targets := joinHostsPort(routerV6targetips, config.eps.Port)
 
+				var routerV4LocalTargets, routerV6LocalTargets []Addr
+				if config.preserveSourceIP {
+					routerV4LocalTargets = joinHostsPort(config.makeNodeRouterLocalTargetIPs(&node, config.eps.V4IPs), config.eps.Port)
+					routerV6LocalTargets = joinHostsPort(config.makeNodeRouterLocalTargetIPs(&node, config.eps.V6IPs), config.eps.Port)
+				}
+
 				switchV4targets := joinHostsPort(config.eps.V4IPs, config.eps.Port)
 				switchV6targets := joinHostsPort(config.eps.V6IPs, config.eps.Port)
 
@@ -616,6 +683,15 @@ func buildPerNodeLBs(service *v1.Service, configs []lbConfig, nodes []nodeInfo)
 							Source:  Addr{IP: vip, Port: config.inport},
 							Targets: targetsITP,
 						})
+					} else if config.topologyAware && util.IsClusterIP(vip) {
+						zoneTargets := switchV4ZoneTargets
+						if isv6 {
+							zoneTargets = switchV6ZoneTargets
+						}
+						switchRules = append(switchRules, LBRule{
+							Source:  Addr{IP: vip, Port: config.inport},
+							Targets: zoneTargets,
+						})
 					} else {
 						switchRules = append(switchRules, LBRule{
 							Source:  Addr{IP: vip, Port: config.inport},
@@ -637,8 +713,17 @@ func buildPerNodeLBs(service *v1.Service, configs []lbConfig, nodes []nodeInfo)
 					// in other words, is this ExternalTrafficPolicy=local?
 					// if so, this gets a separate load balancer with SNAT disabled
 					// (but there's no need to do this if the list of targets is empty)
+					// if the source IP is preserved and the node has local endpoints,
+					// the same applies to the local endpoints only
+					localTargets := routerV4LocalTargets
+					if isv6 {
+						localTargets = routerV6LocalTargets
+					}
 					if config.externalTrafficLocal && len(targets) > 0 {
 						noSNATRouterRules = append(noSNATRouterRules, rule)
+					} else if config.preserveSourceIP && len(localTargets) > 0 {
+						rule.Targets = localTargets
+						noSNATRouterRules = append(noSNATRouterRules, rule)
 					} else {
 						routerRules = append(routerRules, rule)
 					}
@@ -730,6 +815,38 @@ func hasSessionAffinityTimeOut(service *v1.Service) bool {
 		getSessionAffinityTimeOut(service) > 0
 }
 
+// hasPreserveSourceIP returns true if the source IP of the external traffic
+// to the service should be preserved. This is only supported in shared
+// gateway mode, where the GatewayRouters load balance the external traffic.
+func hasPreserveSourceIP(service *v1.Service) bool {
+	return config.Gateway.Mode == config.GatewayModeShared &&
+		service.Annotations[types.ServicePreserveSourceIPAnnotation] == "true"
+}
+
+// hasTopologyAwareConfig returns true if any of the configs is topology aware
+func hasTopologyAwareConfig(configs []lbConfig) bool {
+	for _, config := range configs {
+		if config.topologyAware {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTopologyAwareRouting returns true if the endpoints of the service should
+// be selected with their zone hints, which requires the feature to be enabled
+// and the service to opt in with the topology mode annotation.
+func hasTopologyAwareRouting(service *v1.Service) bool {
+	if !config.OVNKubernetesFeature.EnableTopologyAwareRouting {
+		return false
+	}
+	mode, ok := service.Annotations[types.ServiceTopologyModeAnnotation]
+	if !ok {
+		mode = service.Annotations[types.ServiceTopologyAwareHintsAnnotation]
+	}
+	return mode == "Auto" || mode == "auto"
+}
+
 // lbOpts generates the OVN load balancer options from the kubernetes Service.
 func lbOpts(service *v1.Service) LBOpts {
 	affinity := service.Spec.SessionAffinity == v1.ServiceAffinityClientIP
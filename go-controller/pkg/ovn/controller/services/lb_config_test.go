@@ -15,6 +15,7 @@ import (
 	discovery "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	utilpointer "k8s.io/utils/pointer"
 )
 
@@ -411,7 +412,7 @@ func Test_buildServiceLBConfigs(t *testing.T) {
 			},
 			resultsSame: true,
 			resultSharedGatewayCluster: []lbConfig{{
-				vips:     []string{"192.168.1.1", "2002::1", "4.2.2.2", "42::42", "5.5.5.5"},
+				vips:     []string{"192.168.1.1", "2002::1", "4.2.2.2", "5.5.5.5", "42::42"},
 				protocol: v1.ProtocolTCP,
 				inport:   inport,
 				eps: util.LbEndpoints{
@@ -463,7 +464,7 @@ func Test_buildServiceLBConfigs(t *testing.T) {
 			},
 			resultSharedGatewayNode: []lbConfig{
 				{
-					vips:                 []string{"4.2.2.2", "42::42", "5.5.5.5"},
+					vips:                 []string{"4.2.2.2", "5.5.5.5", "42::42"},
 					protocol:             v1.ProtocolTCP,
 					inport:               inport,
 					externalTrafficLocal: true,
@@ -661,6 +662,95 @@ func Test_buildServiceLBConfigs(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "one port, endpoints, nodePort, externalIP, preserve source IP",
+			args: args{
+				slices: makeSlices([]string{"10.128.0.2"}, nil, v1.ProtocolTCP),
+				service: &v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        serviceName,
+						Namespace:   ns,
+						Annotations: map[string]string{types.ServicePreserveSourceIPAnnotation: "true"},
+					},
+					Spec: v1.ServiceSpec{
+						Type:        v1.ServiceTypeNodePort,
+						ClusterIP:   "192.168.1.1",
+						ClusterIPs:  []string{"192.168.1.1"},
+						ExternalIPs: []string{"1.2.3.4"},
+						Ports: []v1.ServicePort{{
+							Port:       inport,
+							Protocol:   v1.ProtocolTCP,
+							TargetPort: outportstr,
+							NodePort:   5,
+						}},
+					},
+				},
+			},
+			// In shared gateway mode, the nodeport and external IP are per-node
+			resultSharedGatewayNode: []lbConfig{
+				{
+					vips:     []string{"node"},
+					protocol: v1.ProtocolTCP,
+					inport:   5,
+					eps: util.LbEndpoints{
+						V4IPs: []string{"10.128.0.2"},
+						V6IPs: []string{},
+						Port:  outport,
+					},
+					preserveSourceIP: true,
+					hasNodePort:      true,
+				},
+				{
+					vips:     []string{"1.2.3.4"},
+					protocol: v1.ProtocolTCP,
+					inport:   inport,
+					eps: util.LbEndpoints{
+						V4IPs: []string{"10.128.0.2"},
+						V6IPs: []string{},
+						Port:  outport,
+					},
+					preserveSourceIP: true,
+				},
+			},
+			resultSharedGatewayCluster: []lbConfig{
+				{
+					vips:     []string{"192.168.1.1"},
+					protocol: v1.ProtocolTCP,
+					inport:   inport,
+					eps: util.LbEndpoints{
+						V4IPs: []string{"10.128.0.2"},
+						V6IPs: []string{},
+						Port:  outport,
+					},
+				},
+			},
+			// The annotation is ignored in local gateway mode
+			resultLocalGatewayTemplate: []lbConfig{
+				{
+					vips:     []string{"node"},
+					protocol: v1.ProtocolTCP,
+					inport:   5,
+					eps: util.LbEndpoints{
+						V4IPs: []string{"10.128.0.2"},
+						V6IPs: []string{},
+						Port:  outport,
+					},
+					hasNodePort: true,
+				},
+			},
+			resultLocalGatewayCluster: []lbConfig{
+				{
+					vips:     []string{"192.168.1.1", "1.2.3.4"},
+					protocol: v1.ProtocolTCP,
+					inport:   inport,
+					eps: util.LbEndpoints{
+						V4IPs: []string{"10.128.0.2"},
+						V6IPs: []string{},
+						Port:  outport,
+					},
+				},
+			},
+		},
 		{
 			name: "dual-stack clusterip, one port, endpoints, hostNetwork",
 			args: args{
@@ -986,6 +1076,7 @@ func Test_buildPerNodeLBs(t *testing.T) {
 			gatewayRouterName:  "gr-node-a",
 			switchName:         "switch-node-a",
 			podSubnets:         []net.IPNet{{IP: net.ParseIP("10.128.0.0"), Mask: net.CIDRMask(24, 32)}},
+			topologyZone:       "zone-a",
 		},
 		{
 			name:               "node-b",
@@ -994,6 +1085,7 @@ func Test_buildPerNodeLBs(t *testing.T) {
 			gatewayRouterName:  "gr-node-b",
 			switchName:         "switch-node-b",
 			podSubnets:         []net.IPNet{{IP: net.ParseIP("10.128.1.0"), Mask: net.CIDRMask(24, 32)}},
+			topologyZone:       "zone-b",
 		},
 	}
 
@@ -1423,6 +1515,68 @@ func Test_buildPerNodeLBs(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:    "clusterIP service, standard pods, topology aware routing",
+			service: defaultService,
+			configs: []lbConfig{
+				{
+					vips:          []string{"192.168.0.1"}, // clusterIP config
+					protocol:      v1.ProtocolTCP,
+					inport:        80,
+					topologyAware: true,
+					eps: util.LbEndpoints{
+						V4IPs: []string{"10.128.0.1", "10.128.1.1", "10.128.1.2"},
+						Port:  8080,
+						ZoneHints: map[string]sets.Set[string]{
+							"10.128.0.1": sets.New("zone-a"),
+							"10.128.1.1": sets.New("zone-b"),
+							"10.128.1.2": sets.New("zone-b"),
+						},
+					},
+				},
+			},
+			expectedShared: []LB{
+				{
+					Name:        "Service_testns/foo_TCP_node_router_node-a_merged",
+					ExternalIDs: defaultExternalIDs,
+					Routers:     []string{"gr-node-a", "gr-node-b"},
+					Protocol:    "TCP",
+					Rules: []LBRule{
+						{
+							Source:  Addr{IP: "192.168.0.1", Port: 80},
+							Targets: []Addr{{IP: "10.128.0.1", Port: 8080}, {IP: "10.128.1.1", Port: 8080}, {IP: "10.128.1.2", Port: 8080}}, // no filtering on GR LBs for topology hints
+						},
+					},
+					Opts: defaultOpts,
+				},
+				{
+					Name:        "Service_testns/foo_TCP_node_switch_node-a",
+					ExternalIDs: defaultExternalIDs,
+					Switches:    []string{"switch-node-a"},
+					Protocol:    "TCP",
+					Rules: []LBRule{
+						{
+							Source:  Addr{IP: "192.168.0.1", Port: 80},
+							Targets: []Addr{{IP: "10.128.0.1", Port: 8080}}, // the ep hinted for zone-a
+						},
+					},
+					Opts: defaultOpts,
+				},
+				{
+					Name:        "Service_testns/foo_TCP_node_switch_node-b",
+					ExternalIDs: defaultExternalIDs,
+					Switches:    []string{"switch-node-b"},
+					Protocol:    "TCP",
+					Rules: []LBRule{
+						{
+							Source:  Addr{IP: "192.168.0.1", Port: 80},
+							Targets: []Addr{{IP: "10.128.1.1", Port: 8080}, {IP: "10.128.1.2", Port: 8080}}, // the eps hinted for zone-b
+						},
+					},
+					Opts: defaultOpts,
+				},
+			},
+		},
 		{
 			name:    "clusterIP + externalIP service, standard pods, InternalTrafficPolicy=local",
 			service: defaultService,
@@ -1947,6 +2101,120 @@ func Test_buildPerNodeLBs(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:    "nodeport service preserving the source IP, standard pod",
+			service: defaultService,
+			configs: []lbConfig{
+				{
+					vips:     []string{"node"},
+					protocol: v1.ProtocolTCP,
+					inport:   80,
+					eps: util.LbEndpoints{
+						V4IPs: []string{"10.128.0.2"},
+						Port:  8080,
+					},
+					preserveSourceIP: true,
+					hasNodePort:      true,
+				},
+			},
+			expectedShared: []LB{
+				{
+					Name:        "Service_testns/foo_TCP_node_local_router_node-a",
+					ExternalIDs: defaultExternalIDs,
+					Routers:     []string{"gr-node-a"},
+					Protocol:    "TCP",
+					Rules: []LBRule{
+						{
+							Source:  Addr{IP: "10.0.0.1", Port: 80},
+							Targets: []Addr{{IP: "10.128.0.2", Port: 8080}},
+						},
+						{
+							Source:  Addr{IP: "10.0.0.111", Port: 80},
+							Targets: []Addr{{IP: "10.128.0.2", Port: 8080}},
+						},
+					},
+					Opts: LBOpts{Reject: true, SkipSNAT: true},
+				},
+				{
+					Name:        "Service_testns/foo_TCP_node_switch_node-a",
+					ExternalIDs: defaultExternalIDs,
+					Switches:    []string{"switch-node-a"},
+					Protocol:    "TCP",
+					Rules: []LBRule{
+						{
+							Source:  Addr{IP: "10.0.0.1", Port: 80},
+							Targets: []Addr{{IP: "10.128.0.2", Port: 8080}},
+						},
+						{
+							Source:  Addr{IP: "10.0.0.111", Port: 80},
+							Targets: []Addr{{IP: "10.128.0.2", Port: 8080}},
+						},
+					},
+					Opts: defaultOpts,
+				},
+				{
+					// node-b has no local endpoint, it keeps SNAT to all the endpoints
+					Name:        "Service_testns/foo_TCP_node_router+switch_node-b",
+					ExternalIDs: defaultExternalIDs,
+					Routers:     []string{"gr-node-b"},
+					Switches:    []string{"switch-node-b"},
+					Protocol:    "TCP",
+					Rules: []LBRule{
+						{
+							Source:  Addr{IP: "10.0.0.2", Port: 80},
+							Targets: []Addr{{IP: "10.128.0.2", Port: 8080}},
+						},
+					},
+					Opts: defaultOpts,
+				},
+			},
+		},
+		{
+			// host-networked endpoints are always reached with SNAT
+			name:    "external IP preserving the source IP, host-network pod",
+			service: defaultService,
+			configs: []lbConfig{
+				{
+					vips:     []string{"1.2.3.4"},
+					protocol: v1.ProtocolTCP,
+					inport:   80,
+					eps: util.LbEndpoints{
+						V4IPs: []string{"10.0.0.1"},
+						Port:  8080,
+					},
+					preserveSourceIP: true,
+				},
+			},
+			expectedShared: []LB{
+				{
+					Name:        "Service_testns/foo_TCP_node_router_node-a",
+					ExternalIDs: defaultExternalIDs,
+					Routers:     []string{"gr-node-a"},
+					Protocol:    "TCP",
+					Rules: []LBRule{
+						{
+							Source:  Addr{IP: "1.2.3.4", Port: 80},
+							Targets: []Addr{{IP: "169.254.169.2", Port: 8080}},
+						},
+					},
+					Opts: defaultOpts,
+				},
+				{
+					Name:        "Service_testns/foo_TCP_node_switch_node-a_merged",
+					ExternalIDs: defaultExternalIDs,
+					Routers:     []string{"gr-node-b"},
+					Switches:    []string{"switch-node-a", "switch-node-b"},
+					Protocol:    "TCP",
+					Rules: []LBRule{
+						{
+							Source:  Addr{IP: "1.2.3.4", Port: 80},
+							Targets: []Addr{{IP: "10.0.0.1", Port: 8080}},
+						},
+					},
+					Opts: defaultOpts,
+				},
+			},
+		},
 	}
 
 	for i, tt := range tc {
@@ -53,6 +53,8 @@ type nodeInfo struct {
 
 	// The node's zone
 	zone string
+	// The node's topology zone, from its topology.kubernetes.io/zone label
+	topologyZone string
 }
 
 func (ni *nodeInfo) hostAddressesStr() []string {
@@ -126,12 +128,14 @@ func newNodeTracker(nodeInformer coreinformers.NodeInformer, zone string) (*node
 			// - the name of the node (very rare) has changed
 			// - the `host-addresses` annotation changed
 			// - node changes its zone
+			// - node changes its topology zone label
 			// . No need to trigger update for any other field change.
 			if util.NodeSubnetAnnotationChanged(oldObj, newObj) ||
 				util.NodeL3GatewayAnnotationChanged(oldObj, newObj) ||
 				oldObj.Name != newObj.Name ||
 				util.NodeHostAddressesAnnotationChanged(oldObj, newObj) ||
-				util.NodeZoneAnnotationChanged(oldObj, newObj) {
+				util.NodeZoneAnnotationChanged(oldObj, newObj) ||
+				oldObj.Labels[v1.LabelTopologyZone] != newObj.Labels[v1.LabelTopologyZone] {
 				nt.updateNode(newObj)
 			}
 		},
@@ -161,7 +165,7 @@ func newNodeTracker(nodeInformer coreinformers.NodeInformer, zone string) (*node
 
 // updateNodeInfo updates the node info cache, and syncs all services
 // if it changed.
-func (nt *nodeTracker) updateNodeInfo(nodeName, switchName, routerName, chassisID string, l3gatewayAddresses, hostAddresses []net.IP, podSubnets []*net.IPNet, zone string) {
+func (nt *nodeTracker) updateNodeInfo(nodeName, switchName, routerName, chassisID string, l3gatewayAddresses, hostAddresses []net.IP, podSubnets []*net.IPNet, zone, topologyZone string) {
 	ni := nodeInfo{
 		name:               nodeName,
 		l3gatewayAddresses: l3gatewayAddresses,
@@ -171,6 +175,7 @@ func (nt *nodeTracker) updateNodeInfo(nodeName, switchName, routerName, chassisI
 		switchName:         switchName,
 		chassisID:          chassisID,
 		zone:               zone,
+		topologyZone:       topologyZone,
 	}
 	for i := range podSubnets {
 		ni.podSubnets = append(ni.podSubnets, *podSubnets[i]) // de-pointer
@@ -265,6 +270,7 @@ func (nt *nodeTracker) updateNode(node *v1.Node) {
 		hostAddressesIPs,
 		hsn,
 		util.GetNodeZone(node),
+		node.Labels[v1.LabelTopologyZone],
 	)
 }
 
@@ -20,6 +20,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 
 	coreinformers "k8s.io/client-go/informers/core/v1"
@@ -168,6 +169,11 @@ type Controller struct {
 	alreadyApplied       map[string][]LB
 	alreadyAppliedRWLock sync.RWMutex
 
+	// topologyAwareServices holds the keys of the services load balanced with
+	// topology aware routing
+	topologyAwareServices     sets.Set[string]
+	topologyAwareServicesLock sync.Mutex
+
 	// Lock order considerations: if both nodeInfoRWLock and alreadyAppliedRWLock
 	// need to be taken for some reason then the order in which they're taken is
 	// always: first nodeInfoRWLock and then alreadyAppliedRWLock.
@@ -374,6 +380,7 @@ func (c *Controller) syncService(key string) error {
 			c.alreadyAppliedRWLock.Unlock()
 		}
 
+		c.setTopologyAwareService(key, false)
 		c.repair.serviceSynced(key)
 		return nil
 	}
@@ -401,6 +408,7 @@ func (c *Controller) syncService(key string) error {
 	klog.V(5).Infof("Built service %s LB cluster-wide configs %#v", key, clusterConfigs)
 	klog.V(5).Infof("Built service %s LB per-node configs %#v", key, perNodeConfigs)
 	klog.V(5).Infof("Built service %s LB template configs %#v", key, templateConfigs)
+	c.setTopologyAwareService(key, hasTopologyAwareConfig(perNodeConfigs))
 
 	// Convert the LB configs in to load-balancer objects
 	clusterLBs := buildClusterLBs(service, clusterConfigs, c.nodeInfos, c.useLBGroups)
@@ -447,6 +455,22 @@ func (c *Controller) syncService(key string) error {
 	return nil
 }
 
+// setTopologyAwareService records whether the given service is load balanced
+// with topology aware routing, and updates the metric counting such services
+func (c *Controller) setTopologyAwareService(key string, topologyAware bool) {
+	c.topologyAwareServicesLock.Lock()
+	defer c.topologyAwareServicesLock.Unlock()
+	if c.topologyAwareServices == nil {
+		c.topologyAwareServices = sets.New[string]()
+	}
+	if topologyAware {
+		c.topologyAwareServices.Insert(key)
+	} else {
+		c.topologyAwareServices.Delete(key)
+	}
+	metrics.MetricTopologyAwareServices.Set(float64(c.topologyAwareServices.Len()))
+}
+
 func (c *Controller) syncNodeInfos(nodeInfos []nodeInfo) {
 	c.nodeInfoRWLock.Lock()
 	defer c.nodeInfoRWLock.Unlock()
@@ -503,6 +527,20 @@ func (c *Controller) syncNodeInfos(nodeInfos []nodeInfo) {
 	}
 }
 
+// ForceFullSync rebuilds the cache of applied load balancers from the NB
+// database and re-syncs every service, so that load balancers that drifted
+// from their expected configuration get fixed
+func (c *Controller) ForceFullSync() error {
+	if err := c.initTopLevelCache(); err != nil {
+		return fmt.Errorf("error initializing alreadyApplied cache: %w", err)
+	}
+	c.nodeTracker.Lock()
+	nodeInfos := c.nodeTracker.getZoneNodes()
+	c.nodeTracker.Unlock()
+	c.RequestFullSync(nodeInfos)
+	return nil
+}
+
 // RequestFullSync re-syncs every service that currently exists
 func (c *Controller) RequestFullSync(nodeInfos []nodeInfo) {
 	klog.Info("Full service sync requested")
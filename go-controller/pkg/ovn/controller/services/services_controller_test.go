@@ -144,6 +144,7 @@ func TestSyncServices(t *testing.T) {
 	}
 
 	const nodePort = 8989
+	var affinityTimeout int32 = 300
 
 	tests := []struct {
 		name                 string
@@ -284,6 +285,144 @@ func TestSyncServices(t *testing.T) {
 				nodeIPTemplate(secondNode),
 			},
 		},
+		{
+			name: "update service session affinity sets the affinity timeout",
+			slice: &discovery.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      serviceName + "ab23",
+					Namespace: ns,
+					Labels:    map[string]string{discovery.LabelServiceName: serviceName},
+				},
+				Ports:       []discovery.EndpointPort{},
+				AddressType: discovery.AddressTypeIPv4,
+				Endpoints:   []discovery.Endpoint{},
+			},
+			service: &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: serviceName, Namespace: ns},
+				Spec: v1.ServiceSpec{
+					Type:       v1.ServiceTypeClusterIP,
+					ClusterIP:  "192.168.1.1",
+					ClusterIPs: []string{"192.168.1.1"},
+					Selector:   map[string]string{"foo": "bar"},
+					Ports: []v1.ServicePort{{
+						Port:       80,
+						Protocol:   v1.ProtocolTCP,
+						TargetPort: intstr.FromInt(3456),
+					}},
+					SessionAffinity: v1.ServiceAffinityClientIP,
+					SessionAffinityConfig: &v1.SessionAffinityConfig{
+						ClientIP: &v1.ClientIPConfig{TimeoutSeconds: &affinityTimeout},
+					},
+				},
+			},
+			initialDb: []libovsdbtest.TestData{
+				&nbdb.LoadBalancer{
+					UUID:     loadBalancerClusterWideTCPServiceName(ns, serviceName),
+					Name:     loadBalancerClusterWideTCPServiceName(ns, serviceName),
+					Options:  servicesOptions(),
+					Protocol: &nbdb.LoadBalancerProtocolTCP,
+					Vips: map[string]string{
+						"192.168.1.1:80": "",
+					},
+					ExternalIDs: serviceExternalIDs(namespacedServiceName(ns, serviceName)),
+				},
+				nodeLogicalSwitch(nodeA, initialLsGroups),
+				nodeLogicalSwitch(nodeB, initialLsGroups),
+				nodeLogicalRouter(nodeA, initialLrGroups),
+				nodeLogicalRouter(nodeB, initialLrGroups),
+				lbGroup(types.ClusterLBGroupName, loadBalancerClusterWideTCPServiceName(ns, serviceName)),
+				lbGroup(types.ClusterSwitchLBGroupName),
+				lbGroup(types.ClusterRouterLBGroupName),
+			},
+			expectedDb: []libovsdbtest.TestData{
+				&nbdb.LoadBalancer{
+					UUID:     loadBalancerClusterWideTCPServiceName(ns, serviceName),
+					Name:     loadBalancerClusterWideTCPServiceName(ns, serviceName),
+					Options:  servicesOptionsWithAffinityTimeout(affinityTimeout),
+					Protocol: &nbdb.LoadBalancerProtocolTCP,
+					Vips: map[string]string{
+						"192.168.1.1:80": "",
+					},
+					ExternalIDs: serviceExternalIDs(namespacedServiceName(ns, serviceName)),
+				},
+				nodeLogicalSwitch(nodeA, initialLsGroups),
+				nodeLogicalSwitch(nodeB, initialLsGroups),
+				nodeLogicalRouter(nodeA, initialLrGroups),
+				nodeLogicalRouter(nodeB, initialLrGroups),
+				lbGroup(types.ClusterLBGroupName, loadBalancerClusterWideTCPServiceName(ns, serviceName)),
+				lbGroup(types.ClusterSwitchLBGroupName),
+				lbGroup(types.ClusterRouterLBGroupName),
+				nodeIPTemplate(firstNode),
+				nodeIPTemplate(secondNode),
+			},
+		},
+		{
+			name: "update service without session affinity removes the affinity timeout",
+			slice: &discovery.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      serviceName + "ab23",
+					Namespace: ns,
+					Labels:    map[string]string{discovery.LabelServiceName: serviceName},
+				},
+				Ports:       []discovery.EndpointPort{},
+				AddressType: discovery.AddressTypeIPv4,
+				Endpoints:   []discovery.Endpoint{},
+			},
+			service: &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: serviceName, Namespace: ns},
+				Spec: v1.ServiceSpec{
+					Type:       v1.ServiceTypeClusterIP,
+					ClusterIP:  "192.168.1.1",
+					ClusterIPs: []string{"192.168.1.1"},
+					Selector:   map[string]string{"foo": "bar"},
+					Ports: []v1.ServicePort{{
+						Port:       80,
+						Protocol:   v1.ProtocolTCP,
+						TargetPort: intstr.FromInt(3456),
+					}},
+				},
+			},
+			initialDb: []libovsdbtest.TestData{
+				&nbdb.LoadBalancer{
+					UUID:     loadBalancerClusterWideTCPServiceName(ns, serviceName),
+					Name:     loadBalancerClusterWideTCPServiceName(ns, serviceName),
+					Options:  servicesOptionsWithAffinityTimeout(affinityTimeout),
+					Protocol: &nbdb.LoadBalancerProtocolTCP,
+					Vips: map[string]string{
+						"192.168.1.1:80": "",
+					},
+					ExternalIDs: serviceExternalIDs(namespacedServiceName(ns, serviceName)),
+				},
+				nodeLogicalSwitch(nodeA, initialLsGroups),
+				nodeLogicalSwitch(nodeB, initialLsGroups),
+				nodeLogicalRouter(nodeA, initialLrGroups),
+				nodeLogicalRouter(nodeB, initialLrGroups),
+				lbGroup(types.ClusterLBGroupName, loadBalancerClusterWideTCPServiceName(ns, serviceName)),
+				lbGroup(types.ClusterSwitchLBGroupName),
+				lbGroup(types.ClusterRouterLBGroupName),
+			},
+			expectedDb: []libovsdbtest.TestData{
+				&nbdb.LoadBalancer{
+					UUID:     loadBalancerClusterWideTCPServiceName(ns, serviceName),
+					Name:     loadBalancerClusterWideTCPServiceName(ns, serviceName),
+					Options:  servicesOptions(),
+					Protocol: &nbdb.LoadBalancerProtocolTCP,
+					Vips: map[string]string{
+						"192.168.1.1:80": "",
+					},
+					ExternalIDs: serviceExternalIDs(namespacedServiceName(ns, serviceName)),
+				},
+				nodeLogicalSwitch(nodeA, initialLsGroups),
+				nodeLogicalSwitch(nodeB, initialLsGroups),
+				nodeLogicalRouter(nodeA, initialLrGroups),
+				nodeLogicalRouter(nodeB, initialLrGroups),
+				lbGroup(types.ClusterLBGroupName, loadBalancerClusterWideTCPServiceName(ns, serviceName)),
+				lbGroup(types.ClusterSwitchLBGroupName),
+				lbGroup(types.ClusterRouterLBGroupName),
+				nodeIPTemplate(firstNode),
+				nodeIPTemplate(secondNode),
+			},
+		},
 		{
 			name: "transition to endpoints, create nodeport",
 			slice: &discovery.EndpointSlice{
@@ -750,6 +889,12 @@ func servicesOptions() map[string]string {
 	}
 }
 
+func servicesOptionsWithAffinityTimeout(timeout int32) map[string]string {
+	opts := servicesOptions()
+	opts["affinity_timeout"] = fmt.Sprintf("%d", timeout)
+	return opts
+}
+
 func templateServicesOptions() map[string]string {
 	// Template LBs need "options:template=true" and "options:address-family" set.
 	opts := servicesOptions()
@@ -2,12 +2,19 @@ package ovn
 
 import (
 	"fmt"
+	"sort"
 
 	libovsdbclient "github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/libovsdbops"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
 
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	kapi "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 const (
@@ -23,6 +30,9 @@ const (
 
 	// Default COPP object name
 	defaultCOPPName = "ovnkube-default"
+	// Prefix of the name of the COPP of a gateway router overriding the
+	// default rates, followed by the node name
+	nodeCOPPNamePrefix = "ovnkube-node-"
 )
 
 var defaultProtocolNames = [...]string{
@@ -36,11 +46,65 @@ var defaultProtocolNames = [...]string{
 	OVNTCPRSTRateLimiter,
 }
 
+// coppRates returns the meter rate in packets per second of every protocol
+// protected by a COPP: the default protocols at the default rate, overridden
+// and extended by the configured rates and then by the provided overrides
+func coppRates(overrides map[string]int) map[string]int {
+	rates := make(map[string]int, len(defaultProtocolNames)+len(config.Default.COPPRates)+len(overrides))
+	for _, protocol := range defaultProtocolNames {
+		rates[protocol] = config.Default.COPPDefaultRate
+	}
+	for protocol, rate := range config.Default.COPPRates {
+		rates[protocol] = rate
+	}
+	for protocol, rate := range overrides {
+		rates[protocol] = rate
+	}
+	return rates
+}
+
+// getNodeCOPPName returns the name of the COPP of the gateway router of a node
+// that overrides the default rates
+func getNodeCOPPName(nodeName string) string {
+	return nodeCOPPNamePrefix + nodeName
+}
+
 func getMeterNameForProtocol(protocol string) string {
 	// format: <OVNSupportedProtocolName>-rate-limiter
 	return protocol + "-" + types.OvnRateLimitingMeter
 }
 
+// getCOPPMeterName returns the name of the meter of a protocol of a COPP, the
+// default COPP keeps the meter names it always had
+func getCOPPMeterName(coppName, protocol string) string {
+	if coppName == defaultCOPPName {
+		return getMeterNameForProtocol(protocol)
+	}
+	// format: <OVNSupportedProtocolName>-<COPPName>-rate-limiter
+	return protocol + "-" + coppName + "-" + types.OvnRateLimitingMeter
+}
+
+// getCOPPMeterDbIDs returns the DbObjectIDs of the meter of a protocol of a
+// COPP. COPPs are shared by the routers of all the networks, so their meters
+// are always owned by the default network controller.
+func getCOPPMeterDbIDs(coppName, protocol string) *libovsdbops.DbObjectIDs {
+	return libovsdbops.NewDbObjectIDs(libovsdbops.MeterCOPP, DefaultNetworkControllerName,
+		map[libovsdbops.ExternalIDKey]string{
+			libovsdbops.ObjectNameKey: coppName,
+			libovsdbops.ProtocolKey:   protocol,
+		})
+}
+
+// getCOPPMetersPredicate returns a predicate matching all the meters of a COPP
+// for which f, if provided, returns true
+func getCOPPMetersPredicate(coppName string, f func(item *nbdb.Meter) bool) func(item *nbdb.Meter) bool {
+	dbIDs := libovsdbops.NewDbObjectIDs(libovsdbops.MeterCOPP, DefaultNetworkControllerName,
+		map[libovsdbops.ExternalIDKey]string{
+			libovsdbops.ObjectNameKey: coppName,
+		})
+	return libovsdbops.GetPredicate[*nbdb.Meter](dbIDs, f)
+}
+
 // EnsureDefaultCOPP creates the default COPP that needs to be added to each GR
 // if not already present. Also cleans up old COPP entries if required.
 func EnsureDefaultCOPP(nbClient libovsdbclient.Client) (string, error) {
@@ -51,47 +115,114 @@ func EnsureDefaultCOPP(nbClient libovsdbclient.Client) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed to delete duplicate COPPs: %w", err)
 	}
+	return ensureCOPP(nbClient, ops, defaultCOPPName, coppRates(nil))
+}
+
+// ensureNodeCOPP creates or updates the COPP of the gateway router of the node
+// when the node overrides any rate and returns its UUID. Otherwise it deletes
+// the COPP of the node, if any, and returns the UUID of the default COPP.
+func (oc *DefaultNetworkController) ensureNodeCOPP(node *kapi.Node) (string, error) {
+	overrides, err := util.ParseNodeCOPPRates(node)
+	if err != nil {
+		return "", err
+	}
+	if len(overrides) == 0 {
+		if err := deleteNodeCOPP(oc.nbClient, node.Name); err != nil {
+			return "", err
+		}
+		return oc.defaultCOPPUUID, nil
+	}
+	return ensureCOPP(oc.nbClient, nil, getNodeCOPPName(node.Name), coppRates(overrides))
+}
 
-	band := &nbdb.MeterBand{
-		Action: types.MeterAction,
-		Rate:   int(25), // hard-coding for now. TODO(tssurya): make this configurable if needed
+// deleteNodeCOPP deletes the COPP of the gateway router of the node and its
+// meters, if any
+func deleteNodeCOPP(nbClient libovsdbclient.Client, nodeName string) error {
+	coppName := getNodeCOPPName(nodeName)
+	ops, err := libovsdbops.DeleteMetersWithPredicateOps(nbClient, nil, getCOPPMetersPredicate(coppName, nil))
+	if err != nil {
+		return fmt.Errorf("can't delete the COPP meters of node %s: %v", nodeName, err)
 	}
-	ops, err = libovsdbops.CreateMeterBandOps(nbClient, ops, band)
+	ops, err = libovsdbops.DeleteCOPPsWithPredicateOps(nbClient, ops, func(item *nbdb.Copp) bool {
+		return item.Name == coppName
+	})
 	if err != nil {
-		return "", fmt.Errorf("can't create meter band %v: %v", band, err)
+		return fmt.Errorf("can't delete the COPP of node %s: %v", nodeName, err)
 	}
+	_, err = libovsdbops.TransactAndCheck(nbClient, ops)
+	return err
+}
 
-	meterNames := make(map[string]string, len(defaultProtocolNames))
+// ensureCOPP creates or updates the named COPP with a meter for every protocol
+// at the given rate, deletes the meters it owns of protocols that are no
+// longer protected and returns its UUID
+func ensureCOPP(nbClient libovsdbclient.Client, ops []ovsdb.Operation, coppName string, rates map[string]int) (string, error) {
+	var err error
+	protocols := make([]string, 0, len(rates))
+	for protocol := range rates {
+		protocols = append(protocols, protocol)
+	}
+	sort.Strings(protocols)
+
+	bands := map[int]*nbdb.MeterBand{}
+	meterNames := make(map[string]string, len(rates))
 	meterFairness := true
-	for _, protocol := range defaultProtocolNames {
-		// format: <OVNSupportedProtocolName>-rate-limiter
-		meterName := getMeterNameForProtocol(protocol)
+	for _, protocol := range protocols {
+		rate := rates[protocol]
+		band, ok := bands[rate]
+		if !ok {
+			band = &nbdb.MeterBand{
+				Action: types.MeterAction,
+				Rate:   rate,
+			}
+			ops, err = libovsdbops.CreateMeterBandOps(nbClient, ops, band)
+			if err != nil {
+				return "", fmt.Errorf("can't create meter band %v: %v", band, err)
+			}
+			bands[rate] = band
+		}
+
+		meterName := getCOPPMeterName(coppName, protocol)
 		meterNames[protocol] = meterName
 
 		meter := &nbdb.Meter{
-			Name: meterName,
-			Fair: &meterFairness,
-			Unit: types.PacketsPerSecond,
+			Name:        meterName,
+			Fair:        &meterFairness,
+			Unit:        types.PacketsPerSecond,
+			ExternalIDs: getCOPPMeterDbIDs(coppName, protocol).GetExternalIDs(),
 		}
 		ops, err = libovsdbops.CreateOrUpdateMeterOps(nbClient, ops, meter, []*nbdb.MeterBand{band},
-			&meter.Bands, &meter.Fair, &meter.Unit)
+			&meter.Bands, &meter.Fair, &meter.Unit, &meter.ExternalIDs)
 		if err != nil {
 			return "", fmt.Errorf("can't create meter %v: %v", meter, err)
 		}
 	}
 
-	defaultCOPP := &nbdb.Copp{
-		Name:   defaultCOPPName,
+	// remove the meters of protocols that are no longer protected
+	currentMeters := sets.NewString()
+	for _, meterName := range meterNames {
+		currentMeters.Insert(meterName)
+	}
+	staleMeter := getCOPPMetersPredicate(coppName, func(item *nbdb.Meter) bool {
+		return !currentMeters.Has(item.Name)
+	})
+	ops, err = libovsdbops.DeleteMetersWithPredicateOps(nbClient, ops, staleMeter)
+	if err != nil {
+		return "", fmt.Errorf("can't delete stale COPP meters: %v", err)
+	}
+
+	copp := &nbdb.Copp{
+		Name:   coppName,
 		Meters: meterNames,
 	}
-	ops, err = libovsdbops.CreateOrUpdateCOPPsOps(nbClient, ops, defaultCOPP)
+	ops, err = libovsdbops.CreateOrUpdateCOPPsOps(nbClient, ops, copp)
 	if err != nil {
-		return "", fmt.Errorf("failed to create/update default COPP: %w", err)
+		return "", fmt.Errorf("failed to create/update COPP %s: %w", coppName, err)
 	}
 
-	if _, err := libovsdbops.TransactAndCheckAndSetUUIDs(nbClient, defaultCOPP, ops); err != nil {
-		return "", fmt.Errorf("failed to transact default COPP: %w", err)
+	if _, err := libovsdbops.TransactAndCheckAndSetUUIDs(nbClient, copp, ops); err != nil {
+		return "", fmt.Errorf("failed to transact COPP %s: %w", coppName, err)
 	}
 
-	return defaultCOPP.UUID, nil
+	return copp.UUID, nil
 }
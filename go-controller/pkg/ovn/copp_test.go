@@ -1,12 +1,20 @@
 package ovn
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
+	libovsdbclient "github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/libovsdbops"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
 	libovsdbtest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing/libovsdb"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestEnsureDefaultCOPP(t *testing.T) {
@@ -19,10 +27,11 @@ func TestEnsureDefaultCOPP(t *testing.T) {
 	meterBand := &nbdb.MeterBand{
 		UUID:   "meter-band-UUID",
 		Action: types.MeterAction,
-		Rate:   int(25), // hard-coding for now. TODO(tssurya): make this configurable if needed
+		Rate:   config.Default.COPPDefaultRate,
 	}
 
-	var meters []*nbdb.Meter
+	// meters created before they were owned, without ExternalIDs
+	var meters, ownedMeters []*nbdb.Meter
 	meterFairness := true
 	for i := 0; i < len(defaultProtocolNames); i++ {
 		meters = append(meters, &nbdb.Meter{
@@ -32,6 +41,14 @@ func TestEnsureDefaultCOPP(t *testing.T) {
 			Unit:  types.PacketsPerSecond,
 			Bands: []string{meterBand.UUID},
 		})
+		ownedMeters = append(ownedMeters, &nbdb.Meter{
+			UUID:        fmt.Sprintf("meter-%d-UUID", i),
+			Name:        getMeterNameForProtocol(defaultProtocolNames[i]),
+			Fair:        &meterFairness,
+			Unit:        types.PacketsPerSecond,
+			Bands:       []string{meterBand.UUID},
+			ExternalIDs: getCOPPMeterDbIDs(defaultCOPPName, defaultProtocolNames[i]).GetExternalIDs(),
+		})
 	}
 
 	expectedNBData := []libovsdbtest.TestData{
@@ -42,7 +59,7 @@ func TestEnsureDefaultCOPP(t *testing.T) {
 		},
 		meterBand,
 	}
-	for _, m := range meters {
+	for _, m := range ownedMeters {
 		expectedNBData = append(expectedNBData, m)
 	}
 
@@ -170,3 +187,171 @@ func TestEnsureDefaultCOPP(t *testing.T) {
 		})
 	}
 }
+
+func TestEnsureDefaultCOPPConfiguredRates(t *testing.T) {
+	if err := config.PrepareTestConfig(); err != nil {
+		t.Fatal(err)
+	}
+	config.Default.COPPRates = map[string]int{
+		OVNARPRateLimiter: 100,
+		"dns":             200,
+	}
+	t.Cleanup(func() { _ = config.PrepareTestConfig() })
+
+	meterFairness := true
+	aclLoggingMeter := &nbdb.Meter{
+		UUID: "acl-logging-meter-UUID",
+		Name: types.OvnACLLoggingMeter,
+		Unit: types.PacketsPerSecond,
+	}
+	// a meter that isn't owned by a COPP is kept, even with a rate limiter name
+	foreignMeter := &nbdb.Meter{
+		UUID: "foreign-meter-UUID",
+		Name: "foreign-" + types.OvnRateLimitingMeter,
+		Unit: types.PacketsPerSecond,
+	}
+	initialNBData := []libovsdbtest.TestData{
+		&nbdb.Meter{
+			UUID:        "stale-meter-UUID",
+			Name:        getMeterNameForProtocol("igmp"),
+			Fair:        &meterFairness,
+			Unit:        types.PacketsPerSecond,
+			ExternalIDs: getCOPPMeterDbIDs(defaultCOPPName, "igmp").GetExternalIDs(),
+		},
+		aclLoggingMeter,
+		foreignMeter,
+	}
+
+	bands := map[int]*nbdb.MeterBand{}
+	for _, rate := range []int{25, 100, 200} {
+		bands[rate] = &nbdb.MeterBand{
+			UUID:   fmt.Sprintf("meter-band-%d-UUID", rate),
+			Action: types.MeterAction,
+			Rate:   rate,
+		}
+	}
+	expectedNBData := []libovsdbtest.TestData{aclLoggingMeter, foreignMeter}
+	for _, band := range bands {
+		expectedNBData = append(expectedNBData, band)
+	}
+	meterMap := map[string]string{}
+	for protocol, rate := range coppRates(nil) {
+		meterMap[protocol] = getMeterNameForProtocol(protocol)
+		expectedNBData = append(expectedNBData, &nbdb.Meter{
+			UUID:        protocol + "-meter-UUID",
+			Name:        getMeterNameForProtocol(protocol),
+			Fair:        &meterFairness,
+			Unit:        types.PacketsPerSecond,
+			Bands:       []string{bands[rate].UUID},
+			ExternalIDs: getCOPPMeterDbIDs(defaultCOPPName, protocol).GetExternalIDs(),
+		})
+	}
+	if len(meterMap) != len(defaultProtocolNames)+1 {
+		t.Fatalf("expected the configured dns rate to add a protected protocol, got %v", meterMap)
+	}
+	expectedNBData = append(expectedNBData, &nbdb.Copp{
+		UUID:   "copp-UUID",
+		Name:   defaultCOPPName,
+		Meters: meterMap,
+	})
+
+	nbClient, cleanup, err := libovsdbtest.NewNBTestHarness(libovsdbtest.TestSetup{NBData: initialNBData}, nil)
+	if err != nil {
+		t.Fatalf("failed to set up test harness: %v", err)
+	}
+	t.Cleanup(cleanup.Cleanup)
+
+	if _, err = EnsureDefaultCOPP(nbClient); err != nil {
+		t.Fatalf("EnsureDefaultCOPP() error = %v", err)
+	}
+
+	matcher := libovsdbtest.HaveData(expectedNBData)
+	success, err := matcher.Match(nbClient)
+	if err != nil {
+		t.Fatalf("encountered error: %v", err)
+	}
+	if !success {
+		t.Fatalf("didn't match expected with actual: %v", matcher.FailureMessage(nbClient))
+	}
+}
+
+func TestEnsureNodeCOPP(t *testing.T) {
+	if err := config.PrepareTestConfig(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = config.PrepareTestConfig() })
+
+	nbClient, cleanup, err := libovsdbtest.NewNBTestHarness(libovsdbtest.TestSetup{}, nil)
+	if err != nil {
+		t.Fatalf("failed to set up test harness: %v", err)
+	}
+	t.Cleanup(cleanup.Cleanup)
+
+	defaultCOPPUUID, err := EnsureDefaultCOPP(nbClient)
+	if err != nil {
+		t.Fatalf("EnsureDefaultCOPP() error = %v", err)
+	}
+	oc := &DefaultNetworkController{defaultCOPPUUID: defaultCOPPUUID}
+	oc.nbClient = nbClient
+
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:        "node1",
+		Annotations: map[string]string{util.OvnNodeCOPPRates: "arp=100,dns=200"},
+	}}
+	nodeCOPPUUID, err := oc.ensureNodeCOPP(node)
+	if err != nil {
+		t.Fatalf("ensureNodeCOPP() error = %v", err)
+	}
+	if nodeCOPPUUID == defaultCOPPUUID {
+		t.Fatalf("expected node rate overrides to use their own COPP")
+	}
+	copps := findCOPPTestObjects(t, nbClient, func(item *nbdb.Copp) bool { return item.Name == getNodeCOPPName(node.Name) })
+	if len(copps) != 1 {
+		t.Fatalf("expected the COPP of the node to be created, got %v", copps)
+	}
+	copp := copps[0]
+	if len(copp.Meters) != len(defaultProtocolNames)+1 {
+		t.Fatalf("expected the node COPP to protect the default protocols and dns, got %v", copp.Meters)
+	}
+	for protocol, rate := range map[string]int{"arp": 100, "dns": 200, "bfd": config.Default.COPPDefaultRate} {
+		meters := findCOPPTestObjects(t, nbClient, libovsdbops.GetPredicate[*nbdb.Meter](
+			getCOPPMeterDbIDs(getNodeCOPPName(node.Name), protocol), nil))
+		if len(meters) != 1 {
+			t.Fatalf("expected one %s meter for the node, got %v", protocol, meters)
+		}
+		if meters[0].Name != copp.Meters[protocol] {
+			t.Fatalf("expected COPP %s meter %s, got %s", protocol, meters[0].Name, copp.Meters[protocol])
+		}
+		bands := findCOPPTestObjects(t, nbClient, func(item *nbdb.MeterBand) bool { return item.UUID == meters[0].Bands[0] })
+		if len(bands) != 1 || bands[0].Rate != rate {
+			t.Fatalf("expected %s meter rate %d, got %v", protocol, rate, bands)
+		}
+	}
+
+	// removing the overrides deletes the node COPP and its meters only
+	delete(node.Annotations, util.OvnNodeCOPPRates)
+	nodeCOPPUUID, err = oc.ensureNodeCOPP(node)
+	if err != nil {
+		t.Fatalf("ensureNodeCOPP() error = %v", err)
+	}
+	if nodeCOPPUUID != defaultCOPPUUID {
+		t.Fatalf("expected a node without overrides to use the default COPP")
+	}
+	copps = findCOPPTestObjects(t, nbClient, func(item *nbdb.Copp) bool { return item.Name == getNodeCOPPName(node.Name) })
+	if len(copps) != 0 {
+		t.Fatalf("expected the COPP of the node to be deleted, got %v", copps)
+	}
+	meters := findCOPPTestObjects(t, nbClient, func(item *nbdb.Meter) bool { return true })
+	if len(meters) != len(defaultProtocolNames) {
+		t.Fatalf("expected only the default COPP meters to be left, got %d", len(meters))
+	}
+}
+
+func findCOPPTestObjects[T any](t *testing.T, nbClient libovsdbclient.Client, p func(T) bool) []T {
+	t.Helper()
+	found := []T{}
+	if err := nbClient.WhereCache(p).List(context.Background(), &found); err != nil {
+		t.Fatalf("failed to list %T: %v", found, err)
+	}
+	return found
+}
@@ -10,6 +10,7 @@ import (
 
 	ocpcloudnetworkapi "github.com/openshift/api/cloudnetwork/v1"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	anplisters "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1alpha1/apis/listers/adminnetworkpolicy/v1alpha1"
 	egressfirewall "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1"
 	egressipv1 "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1"
 	egressqoslisters "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1/apis/listers/egressqos/v1"
@@ -24,7 +25,9 @@ import (
 	aclsyncer "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/external_ids_syncer/acl"
 	addrsetsyncer "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/external_ids_syncer/address_set"
 	lsm "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/logical_switch_manager"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/podinspector"
 	zoneic "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/zone_interconnect"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/resync"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/retry"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/syncmap"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
@@ -43,6 +46,16 @@ import (
 
 const DefaultNetworkControllerName = "default-network-controller"
 
+// resyncDebugPath is the path of the resync handler under /debug/ on the metrics server
+const resyncDebugPath = "resync"
+
+// names under which the default network controllers can be forced to resync
+const (
+	servicesResyncName           = "services"
+	egressQoSResyncName          = "egressqoses"
+	adminNetworkPolicyResyncName = "adminnetworkpolicies"
+)
+
 // DefaultNetworkController structure is the object which holds the controls for starting
 // and reacting upon the watched resources (e.g. pods, endpoints) for default l3 network
 type DefaultNetworkController struct {
@@ -57,6 +70,10 @@ type DefaultNetworkController struct {
 
 	// egressFirewalls is a map of namespaces and the egressFirewall attached to it
 	egressFirewalls sync.Map
+	// egressFirewallNodeSelectors is a map of the node selectors used by egressFirewall rules and the address
+	// sets of the nodes they select, shared by all the egressFirewalls
+	egressFirewallNodeSelectors     map[string]*egressFirewallNodeSelector
+	egressFirewallNodeSelectorsLock sync.Mutex
 
 	// EgressQoS
 	egressQoSLister egressqoslisters.EgressQoSLister
@@ -72,6 +89,18 @@ type DefaultNetworkController struct {
 	egressQoSNodeSynced cache.InformerSynced
 	egressQoSNodeQueue  workqueue.RateLimitingInterface
 
+	// AdminNetworkPolicy and BaselineAdminNetworkPolicy
+	anpLister  anplisters.AdminNetworkPolicyLister
+	anpSynced  cache.InformerSynced
+	banpLister anplisters.BaselineAdminNetworkPolicyLister
+	banpSynced cache.InformerSynced
+	anpQueue   workqueue.RateLimitingInterface
+
+	anpPodLister       corev1listers.PodLister
+	anpPodSynced       cache.InformerSynced
+	anpNamespaceLister corev1listers.NamespaceLister
+	anpNamespaceSynced cache.InformerSynced
+
 	// Cluster wide Load_Balancer_Group UUID.
 	// Includes all node switches and node gateway routers.
 	clusterLoadBalancerGroupUUID string
@@ -139,6 +168,9 @@ type DefaultNetworkController struct {
 	// zoneChassisHandler handles the local node and remote nodes in creating or updating the chassis entries in the OVN Southbound DB.
 	// Please see zone_interconnect/chassis_handler.go for more details.
 	zoneChassisHandler *zoneic.ZoneChassisHandler
+
+	// resyncer lets operators force the resync of the controllers started by Run
+	resyncer *resync.Resyncer
 }
 
 // NewDefaultNetworkController creates a new OVN controller for creating logical network
@@ -185,8 +217,9 @@ func newDefaultNetworkControllerCommon(cnci *CommonNetworkControllerInfo,
 			wg:                          defaultWg,
 			localZoneNodes:              &sync.Map{},
 		},
-		externalGWCache: make(map[ktypes.NamespacedName]*externalRouteInfo),
-		exGWCacheMutex:  sync.RWMutex{},
+		externalGWCache:             make(map[ktypes.NamespacedName]*externalRouteInfo),
+		exGWCacheMutex:              sync.RWMutex{},
+		egressFirewallNodeSelectors: make(map[string]*egressFirewallNodeSelector),
 		eIPC: egressIPController{
 			egressIPAssignmentMutex:           &sync.Mutex{},
 			podAssignmentMutex:                &sync.Mutex{},
@@ -200,6 +233,7 @@ func newDefaultNetworkControllerCommon(cnci *CommonNetworkControllerInfo,
 			egressIPTotalTimeout:              config.OVNKubernetesFeature.EgressIPReachabiltyTotalTimeout,
 			reachabilityCheckInterval:         egressIPReachabilityCheckInterval,
 			egressIPNodeHealthCheckPort:       config.OVNKubernetesFeature.EgressIPNodeHealthCheckPort,
+			failoverSLO:                       time.Duration(config.OVNKubernetesFeature.EgressIPFailoverSLO) * time.Second,
 		},
 		loadbalancerClusterCache:     make(map[kapi.Protocol]string),
 		clusterLoadBalancerGroupUUID: "",
@@ -209,6 +243,7 @@ func newDefaultNetworkControllerCommon(cnci *CommonNetworkControllerInfo,
 		svcFactory:                   svcFactory,
 		zoneICHandler:                zoneICHandler,
 		zoneChassisHandler:           zoneChassisHandler,
+		resyncer:                     resync.NewResyncer(),
 	}
 
 	// Allocate IPs for logical router port "GwRouterToJoinSwitchPrefix + OVNClusterRouter". This should always
@@ -257,6 +292,7 @@ func (oc *DefaultNetworkController) newRetryFramework(
 	resourceHandler := &retry.ResourceHandler{
 		HasUpdateFunc:          hasResourceAnUpdateFunc(objectType),
 		NeedsUpdateDuringRetry: needsUpdateDuringRetry(objectType),
+		MaxFailedAttempts:      maxFailedAttempts(objectType),
 		ObjType:                objectType,
 		EventHandler:           eventHandler,
 	}
@@ -314,6 +350,9 @@ func (oc *DefaultNetworkController) Start(ctx context.Context) error {
 
 // Stop gracefully stops the controller
 func (oc *DefaultNetworkController) Stop() {
+	metrics.UnregisterDebugHandler(resyncDebugPath)
+	metrics.UnregisterDebugHandler(nbAuditDebugPath)
+	metrics.UnregisterDebugHandler(podInspectorDebugPath)
 	close(oc.stopChan)
 	oc.wg.Wait()
 }
@@ -422,6 +461,8 @@ func (oc *DefaultNetworkController) Run(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	oc.resyncer.Add(servicesResyncName, oc.svcController.ForceFullSync)
+	metrics.RegisterDebugHandler(resyncDebugPath, oc.resyncer)
 
 	if err := WithSyncDurationMetric("pod", oc.WatchPods); err != nil {
 		return err
@@ -431,6 +472,8 @@ func (oc *DefaultNetworkController) Run(ctx context.Context) error {
 	if err := WithSyncDurationMetric("network policy", oc.WatchNetworkPolicy); err != nil {
 		return err
 	}
+	oc.startNBAudit()
+	metrics.RegisterDebugHandler(podInspectorDebugPath, podinspector.Handler(oc.inspectPod))
 
 	if config.OVNKubernetesFeature.EnableEgressIP {
 		// This is probably the best starting order for all egress IP handlers.
@@ -443,6 +486,10 @@ func (oc *DefaultNetworkController) Run(ctx context.Context) error {
 		// risk performing a bunch of modifications on the EgressIP objects when
 		// we restart and then have these handlers act on stale data when they
 		// sync.
+		if err := oc.watchFactory.WaitForCacheSync(factory.EgressIPType, factory.NamespaceType, factory.PodType,
+			factory.NodeType); err != nil {
+			return err
+		}
 		if err := WithSyncDurationMetric("egress ip namespace", oc.WatchEgressIPNamespaces); err != nil {
 			return err
 		}
@@ -498,6 +545,27 @@ func (oc *DefaultNetworkController) Run(ctx context.Context) error {
 			defer oc.wg.Done()
 			oc.runEgressQoSController(1, oc.stopChan)
 		}()
+		oc.resyncer.Add(egressQoSResyncName, oc.enqueueAllEgressQoSes)
+	}
+
+	if config.OVNKubernetesFeature.EnableAdminNetworkPolicy {
+		err := oc.initAdminNetworkPolicyController(
+			oc.watchFactory.AdminNetworkPolicyInformer(),
+			oc.watchFactory.BaselineAdminNetworkPolicyInformer(),
+			oc.watchFactory.PodCoreInformer(),
+			oc.watchFactory.NamespaceCoreInformer())
+		if err != nil {
+			return err
+		}
+		oc.wg.Add(1)
+		go func() {
+			defer oc.wg.Done()
+			oc.runAdminNetworkPolicyController(1, oc.stopChan)
+		}()
+		oc.resyncer.Add(adminNetworkPolicyResyncName, func() error {
+			oc.enqueueAllAdminNetworkPolicies()
+			return nil
+		})
 	}
 
 	if config.OVNKubernetesFeature.EnableEgressService {
@@ -660,7 +728,7 @@ func (h *defaultNetworkControllerEventHandler) RecordSuccessEvent(obj interface{
 }
 
 // RecordErrorEvent records an error event on the given object.
-// Only used for pods now.
+// Only used for pods, nodes, network policies and egress firewalls now.
 func (h *defaultNetworkControllerEventHandler) RecordErrorEvent(obj interface{}, reason string, err error) {
 	switch h.objType {
 	case factory.PodType:
@@ -671,6 +739,14 @@ func (h *defaultNetworkControllerEventHandler) RecordErrorEvent(obj interface{},
 		node := obj.(*kapi.Node)
 		klog.V(5).Infof("Recording error event for node %s", node.Name)
 		h.oc.recordNodeEvent(reason, err, node)
+	case factory.PolicyType:
+		policy := obj.(*knet.NetworkPolicy)
+		klog.V(5).Infof("Recording error event on network policy %s/%s", policy.Namespace, policy.Name)
+		h.oc.recordNetworkPolicyEvent(reason, err, policy)
+	case factory.EgressFirewallType:
+		egressFirewall := obj.(*egressfirewall.EgressFirewall)
+		klog.V(5).Infof("Recording error event on egress firewall %s/%s", egressFirewall.Namespace, egressFirewall.Name)
+		h.oc.recordEgressFirewallEvent(reason, err, egressFirewall)
 	}
 }
 
@@ -745,14 +821,14 @@ func (h *defaultNetworkControllerEventHandler) AddResource(obj interface{}, from
 	case factory.EgressFirewallType:
 		var err error
 		egressFirewall := obj.(*egressfirewall.EgressFirewall).DeepCopy()
+		zoneStatus := egressFirewallAppliedCorrectly
 		if err = h.oc.addEgressFirewall(egressFirewall); err != nil {
-			egressFirewall.Status.Status = egressFirewallAddError
+			zoneStatus = egressFirewallAddError
 		} else {
-			egressFirewall.Status.Status = egressFirewallAppliedCorrectly
 			metrics.UpdateEgressFirewallRuleCount(float64(len(egressFirewall.Spec.Egress)))
 			metrics.IncrementEgressFirewallCount()
 		}
-		if statusErr := h.oc.updateEgressFirewallStatusWithRetry(egressFirewall); statusErr != nil {
+		if statusErr := h.oc.updateEgressFirewallStatusWithRetry(egressFirewall, zoneStatus); statusErr != nil {
 			klog.Errorf("Failed to update egress firewall status %s, error: %v",
 				getEgressFirewallNamespacedName(egressFirewall), statusErr)
 		}
@@ -810,7 +886,9 @@ func (h *defaultNetworkControllerEventHandler) UpdateResource(oldObj, newObj int
 		oldPod := oldObj.(*kapi.Pod)
 		newPod := newObj.(*kapi.Pod)
 
-		return h.oc.ensurePod(oldPod, newPod, inRetryCache || util.PodScheduled(oldPod) != util.PodScheduled(newPod))
+		// the port security exemptions are set on the logical switch port, update it when they change
+		return h.oc.ensurePod(oldPod, newPod, inRetryCache || util.PodScheduled(oldPod) != util.PodScheduled(newPod) ||
+			oldPod.Annotations[util.PortSecurityExemptionsAnnotation] != newPod.Annotations[util.PortSecurityExemptionsAnnotation])
 
 	case factory.NodeType:
 		newNode, ok := newObj.(*kapi.Node)
@@ -853,7 +931,7 @@ func (h *defaultNetworkControllerEventHandler) UpdateResource(oldObj, newObj int
 				_, failed = h.oc.gatewaysFailed.Load(newNode.Name)
 				gwSync := (failed || gatewayChanged(oldNode, newNode) ||
 					nodeSubnetChanged(oldNode, newNode) || hostAddressesChanged(oldNode, newNode) ||
-					nodeGatewayMTUSupportChanged(oldNode, newNode))
+					nodeGatewayMTUSupportChanged(oldNode, newNode) || nodeCOPPRatesChanged(oldNode, newNode))
 				_, hoSync := h.oc.hybridOverlayFailed.Load(newNode.Name)
 				_, syncZoneIC := h.oc.syncZoneICFailed.Load(newNode.Name)
 				nodeSyncsParam = &nodeSyncs{
@@ -0,0 +1,176 @@
+package ovn
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	libovsdbclient "github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/libovsdbops"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/metrics"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
+	ovntypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	kapi "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+)
+
+// deleteDuplicateLogicalSwitchPorts looks for pod logical switch ports of the local zone node switches that claim
+// the same IP address. Such duplicates are left behind by races, e.g. when ovnkube-master restarted while a pod was
+// being deleted and recreated. For every address claimed more than once, the canonical port is the one of the running
+// pod the address is annotated on, as long as its iface-id-ver option matches the pod UID. The other ports are deleted
+// unless they still belong to a running pod, in which case they are left to the pod handlers.
+func (oc *DefaultNetworkController) deleteDuplicateLogicalSwitchPorts() error {
+	nodes, err := oc.GetLocalZoneNodes()
+	if err != nil {
+		return err
+	}
+
+	// look up the ports before the pods, so that the pod of every port created by the pod handlers is already in
+	// the informer cache
+	portsBySwitch := map[string][]*nbdb.LogicalSwitchPort{}
+	for _, node := range nodes {
+		switchName := node.Name
+		if oc.lsManager.IsNonHostSubnetSwitch(switchName) {
+			continue
+		}
+		lsps, err := libovsdbops.FindLogicalSwitchPortsOnSwitchWithPredicate(oc.nbClient,
+			&nbdb.LogicalSwitch{Name: switchName},
+			func(item *nbdb.LogicalSwitchPort) bool { return item.ExternalIDs["pod"] == "true" })
+		if err != nil {
+			if errors.Is(err, libovsdbclient.ErrNotFound) {
+				continue
+			}
+			return err
+		}
+		portsBySwitch[switchName] = lsps
+	}
+
+	pods, err := oc.watchFactory.GetAllPods()
+	if err != nil {
+		return fmt.Errorf("failed to get pods: %v", err)
+	}
+	podsByPort := make(map[string]*kapi.Pod, len(pods))
+	for _, pod := range pods {
+		if !util.PodScheduled(pod) || util.PodWantsHostNetwork(pod) || util.PodCompleted(pod) {
+			continue
+		}
+		podsByPort[util.GetLogicalPortName(pod.Namespace, pod.Name)] = pod
+	}
+
+	var detected, deleted int
+	var errs []error
+	for switchName, lsps := range portsBySwitch {
+		duplicates := findDuplicateLogicalSwitchPorts(lsps, podsByPort)
+		detected += len(duplicates)
+		stale := make([]*nbdb.LogicalSwitchPort, 0, len(duplicates))
+		staleNames := make([]string, 0, len(duplicates))
+		for _, lsp := range duplicates {
+			if _, ok := podsByPort[lsp.Name]; ok {
+				klog.Warningf("Logical switch port %s on switch %s claims the IP of another pod but belongs to a running pod, not deleting it",
+					lsp.Name, switchName)
+				continue
+			}
+			stale = append(stale, lsp)
+			staleNames = append(staleNames, lsp.Name)
+		}
+		if len(stale) == 0 {
+			continue
+		}
+		if err := libovsdbops.DeleteLogicalSwitchPorts(oc.nbClient, &nbdb.LogicalSwitch{Name: switchName}, stale...); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete duplicate logical switch ports %v from switch %s: %v",
+				staleNames, switchName, err))
+			continue
+		}
+		klog.Infof("Deleted duplicate logical switch ports %v from switch %s", staleNames, switchName)
+		deleted += len(stale)
+	}
+	metrics.RecordDuplicateLogicalSwitchPorts(detected, deleted)
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// findDuplicateLogicalSwitchPorts returns, sorted by name, the ports claiming an IP address that is also claimed by
+// the canonical port of the running pod the address is allocated to. Addresses that have no canonical port are
+// ignored, since there is no telling which of their ports is the legit one.
+func findDuplicateLogicalSwitchPorts(lsps []*nbdb.LogicalSwitchPort, podsByPort map[string]*kapi.Pod) []*nbdb.LogicalSwitchPort {
+	portsByIP := map[string][]*nbdb.LogicalSwitchPort{}
+	for _, lsp := range lsps {
+		for _, ip := range getLogicalSwitchPortIPs(lsp) {
+			portsByIP[ip] = append(portsByIP[ip], lsp)
+		}
+	}
+
+	duplicates := map[string]*nbdb.LogicalSwitchPort{}
+	for ip, ports := range portsByIP {
+		if len(ports) < 2 {
+			continue
+		}
+		var canonical *nbdb.LogicalSwitchPort
+		for _, lsp := range ports {
+			if isCanonicalPodLogicalSwitchPort(lsp, podsByPort[lsp.Name], ip) {
+				canonical = lsp
+				break
+			}
+		}
+		if canonical == nil {
+			klog.Warningf("Found %d logical switch ports claiming IP %s, none of them belonging to the pod the IP is allocated to",
+				len(ports), ip)
+			continue
+		}
+		for _, lsp := range ports {
+			if lsp.UUID != canonical.UUID {
+				duplicates[lsp.UUID] = lsp
+			}
+		}
+	}
+
+	result := make([]*nbdb.LogicalSwitchPort, 0, len(duplicates))
+	for _, lsp := range duplicates {
+		result = append(result, lsp)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// isCanonicalPodLogicalSwitchPort returns true if the port belongs to the given running pod, which has the IP
+// allocated in its pod annotation
+func isCanonicalPodLogicalSwitchPort(lsp *nbdb.LogicalSwitchPort, pod *kapi.Pod, ip string) bool {
+	if pod == nil {
+		return false
+	}
+	if podUID := lsp.Options["iface-id-ver"]; podUID != "" && podUID != string(pod.UID) {
+		return false
+	}
+	podAnnotation, err := util.UnmarshalPodAnnotation(pod.Annotations, ovntypes.DefaultNetworkName)
+	if err != nil {
+		return false
+	}
+	for _, podIP := range podAnnotation.IPs {
+		if podIP.IP.String() == ip {
+			return true
+		}
+	}
+	return false
+}
+
+// getLogicalSwitchPortIPs returns the IPs found in the "<mac> <ip>..." addresses of the port
+func getLogicalSwitchPortIPs(lsp *nbdb.LogicalSwitchPort) []string {
+	var ips []string
+	for _, address := range lsp.Addresses {
+		fields := strings.Fields(address)
+		if len(fields) < 2 {
+			continue
+		}
+		for _, field := range fields[1:] {
+			if ip := net.ParseIP(field); ip != nil {
+				ips = append(ips, ip.String())
+			}
+		}
+	}
+	return ips
+}
@@ -0,0 +1,146 @@
+package ovn
+
+import (
+	"net"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
+	ovntest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing"
+	libovsdbtest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing/libovsdb"
+	ovntypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+var _ = ginkgo.Describe("OVN duplicate logical switch ports", func() {
+	const (
+		namespaceName = "namespace1"
+		nodeName      = "node1"
+	)
+	var (
+		app     *cli.App
+		fakeOvn *FakeOVN
+	)
+
+	ginkgo.BeforeEach(func() {
+		// Restore global default values before each testcase
+		config.PrepareTestConfig()
+
+		app = cli.NewApp()
+		app.Name = "test"
+		app.Flags = config.Flags
+
+		fakeOvn = NewFakeOVN(true)
+	})
+
+	ginkgo.AfterEach(func() {
+		fakeOvn.shutdown()
+	})
+
+	newAnnotatedPod := func(name, podIP string) *v1.Pod {
+		pod := newPod(namespaceName, name, nodeName, podIP)
+		annotations, err := util.MarshalPodAnnotation(map[string]string{}, &util.PodAnnotation{
+			IPs: []*net.IPNet{ovntest.MustParseIPNet(podIP + "/24")},
+			MAC: util.IPAddrToHWAddr(ovntest.MustParseIP(podIP)),
+		}, ovntypes.DefaultNetworkName)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		pod.Annotations = annotations
+		return pod
+	}
+
+	newPodLSP := func(name, podUID, podIP string) *nbdb.LogicalSwitchPort {
+		return &nbdb.LogicalSwitchPort{
+			UUID:        name + "-UUID",
+			Name:        util.GetLogicalPortName(namespaceName, name),
+			Addresses:   []string{util.IPAddrToHWAddr(ovntest.MustParseIP(podIP)).String() + " " + podIP},
+			ExternalIDs: map[string]string{"namespace": namespaceName, "pod": "true"},
+			Options:     map[string]string{"iface-id-ver": podUID},
+		}
+	}
+
+	ginkgo.It("deletes stale ports claiming the IP of a running pod", func() {
+		app.Action = func(ctx *cli.Context) error {
+			// pod1 and the stale port of a deleted pod claim the same IP
+			pod1LSP := newPodLSP("pod1", "pod1", "10.128.1.3")
+			staleLSP := newPodLSP("oldpod", "oldpod", "10.128.1.3")
+			// pod3 claims the IP of pod2 but is running, so it is left to the pod handlers
+			pod2LSP := newPodLSP("pod2", "pod2", "10.128.1.4")
+			pod3LSP := newPodLSP("pod3", "pod3", "10.128.1.4")
+			nodeSwitch := &nbdb.LogicalSwitch{
+				UUID:  nodeName + "-UUID",
+				Name:  nodeName,
+				Ports: []string{pod1LSP.UUID, staleLSP.UUID, pod2LSP.UUID, pod3LSP.UUID},
+			}
+			fakeOvn.startWithDBSetup(
+				libovsdbtest.TestSetup{
+					NBData: []libovsdbtest.TestData{pod1LSP, staleLSP, pod2LSP, pod3LSP, nodeSwitch},
+				},
+				&v1.NamespaceList{
+					Items: []v1.Namespace{*newNamespace(namespaceName)},
+				},
+				&v1.NodeList{
+					Items: []v1.Node{*newNode(nodeName, "192.168.126.202/24")},
+				},
+				&v1.PodList{
+					Items: []v1.Pod{
+						*newAnnotatedPod("pod1", "10.128.1.3"),
+						*newAnnotatedPod("pod2", "10.128.1.4"),
+						*newAnnotatedPod("pod3", "10.128.1.5"),
+					},
+				},
+			)
+
+			err := fakeOvn.controller.deleteDuplicateLogicalSwitchPorts()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			nodeSwitch.Ports = []string{pod1LSP.UUID, pod2LSP.UUID, pod3LSP.UUID}
+			gomega.Expect(fakeOvn.nbClient).To(libovsdbtest.HaveData(
+				[]libovsdbtest.TestData{nodeSwitch, pod1LSP, pod2LSP, pod3LSP}))
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("keeps all the ports claiming an IP that is not allocated to any of their pods", func() {
+		app.Action = func(ctx *cli.Context) error {
+			// the iface-id-ver of pod1's port doesn't match the pod UID, there is no telling which port is legit
+			pod1LSP := newPodLSP("pod1", "other-uid", "10.128.1.3")
+			staleLSP := newPodLSP("oldpod", "oldpod", "10.128.1.3")
+			nodeSwitch := &nbdb.LogicalSwitch{
+				UUID:  nodeName + "-UUID",
+				Name:  nodeName,
+				Ports: []string{pod1LSP.UUID, staleLSP.UUID},
+			}
+			fakeOvn.startWithDBSetup(
+				libovsdbtest.TestSetup{
+					NBData: []libovsdbtest.TestData{pod1LSP, staleLSP, nodeSwitch},
+				},
+				&v1.NamespaceList{
+					Items: []v1.Namespace{*newNamespace(namespaceName)},
+				},
+				&v1.NodeList{
+					Items: []v1.Node{*newNode(nodeName, "192.168.126.202/24")},
+				},
+				&v1.PodList{
+					Items: []v1.Pod{*newAnnotatedPod("pod1", "10.128.1.3")},
+				},
+			)
+
+			err := fakeOvn.controller.deleteDuplicateLogicalSwitchPorts()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(fakeOvn.nbClient).To(libovsdbtest.HaveData(
+				[]libovsdbtest.TestData{nodeSwitch, pod1LSP, staleLSP}))
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+})
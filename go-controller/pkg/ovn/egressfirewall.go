@@ -3,6 +3,8 @@ package ovn
 import (
 	"fmt"
 	"net"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -16,8 +18,8 @@ import (
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util/batching"
 
 	kapi "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/util/retry"
@@ -52,8 +54,9 @@ type destination struct {
 	// Based on this flag we can omit clusterSubnet exclusion from the related ACL.
 	// For dns-based rules, EgressDNS won't add ips from clusterSubnet to the address set.
 	clusterSubnetIntersection bool
-	nodeAddrs                 sets.Set[string]
-	nodeSelector              *metav1.LabelSelector
+	// nodeSelector selects the nodes whose internal IPs are the destination, they are kept in an address set
+	// shared by all the rules with the same selector
+	nodeSelector *metav1.LabelSelector
 }
 
 // cloneEgressFirewall shallow copies the egressfirewallapi.EgressFirewall object provided.
@@ -94,23 +97,11 @@ func (oc *DefaultNetworkController) newEgressFirewallRule(rawEgressFirewallRule
 		efr.to.clusterSubnetIntersection = intersect
 	} else {
 		efr.to.nodeSelector = rawEgressFirewallRule.To.NodeSelector
-		efr.to.nodeAddrs = sets.New[string]()
 		// validate node selector
 		_, err := metav1.LabelSelectorAsSelector(rawEgressFirewallRule.To.NodeSelector)
 		if err != nil {
 			return nil, fmt.Errorf("rule destination has invalid node selector, err: %v", err)
 		}
-		nodes, err := oc.watchFactory.GetNodesBySelector(*rawEgressFirewallRule.To.NodeSelector)
-		if err != nil {
-			return efr, fmt.Errorf("unable to query nodes for egress firewall: %w", err)
-		}
-		for _, node := range nodes {
-			for _, addr := range node.Status.Addresses {
-				if addr.Type == kapi.NodeInternalIP {
-					efr.to.nodeAddrs.Insert(addr.Address)
-				}
-			}
-		}
 	}
 	efr.ports = rawEgressFirewallRule.Ports
 
@@ -165,33 +156,28 @@ func (oc *DefaultNetworkController) syncEgressFirewall(egressFirewalls []interfa
 	}
 
 	// sync the ovn and k8s egressFirewall states
-	existingEFNamespaces := map[string]bool{}
+	existingEFNamespaces := sets.New[string]()
+	existingNodeSelectors := sets.New[string]()
 	for _, efInterface := range egressFirewalls {
 		ef, ok := efInterface.(*egressfirewallapi.EgressFirewall)
 		if !ok {
 			return fmt.Errorf("spurious object in syncEgressFirewall: %v", efInterface)
 		}
-		existingEFNamespaces[ef.Namespace] = true
-	}
-	predicateIDs := libovsdbops.NewDbObjectIDs(libovsdbops.ACLEgressFirewall, oc.controllerName, nil)
-	aclP := libovsdbops.GetPredicate[*nbdb.ACL](predicateIDs, nil)
-	efACLs, err := libovsdbops.FindACLsWithPredicate(oc.nbClient, aclP)
-	if err != nil {
-		return fmt.Errorf("cannot find Egress Firewall ACLs: %v", err)
-	}
-	deleteACLs := []*nbdb.ACL{}
-	for _, efACL := range efACLs {
-		if !existingEFNamespaces[efACL.ExternalIDs[libovsdbops.ObjectNameKey.String()]] {
-			// there is no egress firewall in that namespace, delete
-			deleteACLs = append(deleteACLs, efACL)
+		existingEFNamespaces.Insert(ef.Namespace)
+		for _, rule := range ef.Spec.Egress {
+			if rule.To.NodeSelector != nil {
+				existingNodeSelectors.Insert(getEgressFirewallNodeSelectorKey(rule.To.NodeSelector))
+			}
 		}
 	}
-
-	err = libovsdbops.DeleteACLsFromPortGroups(oc.nbClient, []string{types.ClusterPortGroupNameBase}, deleteACLs...)
+	// delete the acls of the namespaces without egress firewall, and the node selector address sets not used anymore
+	err = libovsdbops.GarbageCollectObjects(oc.nbClient, oc.controllerName, map[*libovsdbops.ObjectIDsType]sets.Set[string]{
+		libovsdbops.ACLEgressFirewall:                    existingEFNamespaces,
+		libovsdbops.AddressSetEgressFirewallNodeSelector: existingNodeSelectors,
+	})
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to delete stale egress firewall objects: %v", err)
 	}
-	klog.Infof("Deleted %d stale egress firewall ACLs", len(deleteACLs))
 	return nil
 }
 
@@ -269,7 +255,7 @@ func (oc *DefaultNetworkController) deleteEgressFirewall(egressFirewallObj *egre
 			break
 		}
 	}
-	// delete acls first, then dns address set that is referenced in these acls
+	// delete acls first, then dns and node selector address sets that are referenced in these acls
 	if err := oc.deleteEgressFirewallRules(egressFirewallObj.Namespace); err != nil {
 		return err
 	}
@@ -278,14 +264,42 @@ func (oc *DefaultNetworkController) deleteEgressFirewall(egressFirewallObj *egre
 			return err
 		}
 	}
+	if err := oc.releaseEgressFirewallNodeSelectors(egressFirewallObj.Namespace, nil); err != nil {
+		return err
+	}
 	oc.egressFirewalls.Delete(egressFirewallObj.Namespace)
 	return nil
 }
 
-func (oc *DefaultNetworkController) updateEgressFirewallStatusWithRetry(egressfirewall *egressfirewallapi.EgressFirewall) error {
+// updateEgressFirewallStatusWithRetry sets the status of the EgressFirewall in the local zone, and aggregates the
+// status of all the zones
+func (oc *DefaultNetworkController) updateEgressFirewallStatusWithRetry(egressfirewall *egressfirewallapi.EgressFirewall,
+	zoneStatus string) error {
 	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		return oc.kube.UpdateEgressFirewall(egressfirewall)
+		// Informer cache should not be mutated, so get a copy of the object
+		ef, err := oc.watchFactory.GetEgressFirewall(egressfirewall.Namespace, egressfirewall.Name)
+		if err != nil {
+			return err
+		}
+		nodes, err := oc.watchFactory.GetNodes()
+		if err != nil {
+			return err
+		}
+		zones := sets.New[string]()
+		for _, node := range nodes {
+			zones.Insert(util.GetNodeZone(node))
+		}
+
+		ef = ef.DeepCopy()
+		if !setEgressFirewallZoneStatus(&ef.Status, oc.zone, zoneStatus, zones) {
+			return nil
+		}
+		return oc.kube.UpdateEgressFirewall(ef)
 	})
+	if apierrors.IsNotFound(retryErr) {
+		// the EgressFirewall was deleted in the meantime
+		return nil
+	}
 	if retryErr != nil {
 		return fmt.Errorf("error in updating status on EgressFirewall %s/%s: %v",
 			egressfirewall.Namespace, egressfirewall.Name, retryErr)
@@ -293,6 +307,37 @@ func (oc *DefaultNetworkController) updateEgressFirewallStatusWithRetry(egressfi
 	return nil
 }
 
+// setEgressFirewallZoneStatus sets the message of the given zone in the EgressFirewall status, drops the messages of
+// the zones that no longer have nodes, and sets the status to the aggregated status of all the zones: the rules are
+// applied only once they are applied in every zone. Returns whether the status changed.
+func setEgressFirewallZoneStatus(status *egressfirewallapi.EgressFirewallStatus, zone, zoneStatus string,
+	zones sets.Set[string]) bool {
+	messages := []string{zone + ": " + zoneStatus}
+	for _, message := range status.Messages {
+		messageZone, _, _ := strings.Cut(message, ": ")
+		if messageZone == zone || !zones.Has(messageZone) {
+			continue
+		}
+		messages = append(messages, message)
+	}
+	sort.Strings(messages)
+
+	aggregatedStatus := egressFirewallAppliedCorrectly
+	for _, message := range messages {
+		if _, messageStatus, _ := strings.Cut(message, ": "); messageStatus != egressFirewallAppliedCorrectly {
+			aggregatedStatus = egressFirewallAddError
+			break
+		}
+	}
+
+	if status.Status == aggregatedStatus && reflect.DeepEqual(status.Messages, messages) {
+		return false
+	}
+	status.Status = aggregatedStatus
+	status.Messages = messages
+	return true
+}
+
 func (oc *DefaultNetworkController) addEgressFirewallRules(ef *egressFirewall, hashedAddressSetNameIPv4,
 	hashedAddressSetNameIPv6 string, aclLogging *ACLLoggingLevels, ruleIDs ...int) error {
 	for _, rule := range ef.egressRules {
@@ -316,13 +361,18 @@ func (oc *DefaultNetworkController) addEgressFirewallRules(ef *egressFirewall, h
 		} else {
 			action = nbdb.ACLActionDrop
 		}
-		if len(rule.to.nodeAddrs) > 0 {
-			for addr := range rule.to.nodeAddrs {
-				if utilnet.IsIPv6String(addr) {
-					matchTargets = append(matchTargets, matchTarget{matchKindV6CIDR, addr, false})
-				} else {
-					matchTargets = append(matchTargets, matchTarget{matchKindV4CIDR, addr, false})
-				}
+		if rule.to.nodeSelector != nil {
+			// rule based on node selector, the address sets are updated on node changes without updating the ACL
+			nodeAddressSet, err := oc.ensureEgressFirewallNodeSelector(ef.namespace, rule.to.nodeSelector)
+			if err != nil {
+				return fmt.Errorf("error with EgressFirewall node selector - %v", err)
+			}
+			nodeIPv4ASHashName, nodeIPv6ASHashName := nodeAddressSet.GetASHashNames()
+			if nodeIPv4ASHashName != "" {
+				matchTargets = append(matchTargets, matchTarget{matchKindV4AddressSet, nodeIPv4ASHashName, false})
+			}
+			if nodeIPv6ASHashName != "" {
+				matchTargets = append(matchTargets, matchTarget{matchKindV6AddressSet, nodeIPv6ASHashName, false})
 			}
 		} else if rule.to.cidrSelector != "" {
 			if utilnet.IsIPv6CIDRString(rule.to.cidrSelector) {
@@ -400,8 +450,7 @@ func (oc *DefaultNetworkController) createEgressFirewallRules(ruleIdx int, match
 func (oc *DefaultNetworkController) deleteEgressFirewallRule(namespace string, ruleIdx int) error {
 	// Find ACLs for a given egressFirewall
 	aclIDs := oc.getEgressFirewallACLDbIDs(namespace, ruleIdx)
-	pACL := libovsdbops.GetPredicate[*nbdb.ACL](aclIDs, nil)
-	egressFirewallACLs, err := libovsdbops.FindACLsWithPredicate(oc.nbClient, pACL)
+	egressFirewallACLs, err := libovsdbops.FindACLsWithDbIDs(oc.nbClient, aclIDs, nil)
 	if err != nil {
 		return fmt.Errorf("unable to list egress firewall ACLs, cannot cleanup old stale data, err: %v", err)
 	}
@@ -425,8 +474,7 @@ func (oc *DefaultNetworkController) deleteEgressFirewallRules(namespace string)
 		map[libovsdbops.ExternalIDKey]string{
 			libovsdbops.ObjectNameKey: namespace,
 		})
-	pACL := libovsdbops.GetPredicate[*nbdb.ACL](predicateIDs, nil)
-	egressFirewallACLs, err := libovsdbops.FindACLsWithPredicate(oc.nbClient, pACL)
+	egressFirewallACLs, err := libovsdbops.FindACLsWithDbIDs(oc.nbClient, predicateIDs, nil)
 	if err != nil {
 		return fmt.Errorf("unable to list egress firewall ACLs, cannot cleanup old stale data, err: %v", err)
 	}
@@ -676,79 +724,3 @@ func (oc *DefaultNetworkController) getEgressFirewallACLDbIDs(namespace string,
 			libovsdbops.RuleIndex:     strconv.Itoa(ruleIdx),
 		})
 }
-
-func getNodeInternalAddrsToString(node *kapi.Node) []string {
-	v4, v6 := util.GetNodeInternalAddrs(node)
-	var addrs []string
-	for _, addr := range []net.IP{v4, v6} {
-		if addr != nil {
-			addrs = append(addrs, addr.String())
-		}
-	}
-
-	return addrs
-}
-
-func (oc *DefaultNetworkController) updateEgressFirewallForNode(oldNode, newNode *kapi.Node) error {
-
-	var addressesToAdd []string
-	var addressesToRemove []string
-
-	if oldNode != nil {
-		addressesToRemove = getNodeInternalAddrsToString(oldNode)
-	}
-
-	if newNode != nil {
-		addressesToAdd = getNodeInternalAddrsToString(newNode)
-	}
-
-	// cycle through egress firewalls and check if any match this node's labels
-	var efErr error
-	oc.egressFirewalls.Range(func(k, v interface{}) bool {
-		ef := v.(*egressFirewall)
-		namespace := k.(string)
-		ef.Lock()
-		defer ef.Unlock()
-		var modifiedRuleIDs []int
-		for _, rule := range ef.egressRules {
-			// nodeSelector will always have a value, but it is mutually exclusive from cidrSelector and dnsName
-			if len(rule.to.cidrSelector) != 0 || len(rule.to.dnsName) != 0 {
-				continue
-			}
-			selector, err := metav1.LabelSelectorAsSelector(rule.to.nodeSelector)
-			if err != nil {
-				klog.Errorf("Error while parsing label selector %#v for egress firewall in namespace %s",
-					rule.to.nodeSelector, namespace)
-				continue
-			}
-			// no need to check selector on old node here, ips are unique and regardless of if selector
-			// matches or not we shouldn't have those addresses anymore
-			rule.to.nodeAddrs.Delete(addressesToRemove...)
-			// check if selector matches
-			if selector.Matches(labels.Set(newNode.Labels)) {
-				rule.to.nodeAddrs.Insert(addressesToAdd...)
-			}
-			modifiedRuleIDs = append(modifiedRuleIDs, rule.id)
-		}
-		if len(modifiedRuleIDs) == 0 {
-			return true
-		}
-		// update egress firewall rules
-		asIndex := getNamespaceAddrSetDbIDs(ef.namespace, oc.controllerName)
-		as, err := oc.addressSetFactory.EnsureAddressSet(asIndex)
-		if err != nil {
-			efErr = fmt.Errorf("cannot ensure addressSet for namespace %s: %v", ef.namespace, err)
-			return false
-		}
-		ipv4HashedAS, ipv6HashedAS := as.GetASHashNames()
-		aclLoggingLevels := oc.GetNamespaceACLLogging(ef.namespace)
-		if err := oc.addEgressFirewallRules(ef, ipv4HashedAS, ipv6HashedAS,
-			aclLoggingLevels, modifiedRuleIDs...); err != nil {
-			efErr = fmt.Errorf("failed to add egress firewall for namespace: %s, error: %w", namespace, err)
-			return false
-		}
-		return true
-	})
-
-	return efErr
-}
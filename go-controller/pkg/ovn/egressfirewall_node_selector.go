@@ -0,0 +1,158 @@
+package ovn
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/libovsdbops"
+	addressset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/address_set"
+
+	kapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// egressFirewallNodeSelector holds the v4 and v6 address sets of the nodes selected by a node selector of
+// egressFirewall rules. All the rules with the same selector share it, so that the address sets are updated
+// incrementally on node changes, once per selector, and the ACLs of the rules are not updated at all.
+type egressFirewallNodeSelector struct {
+	selector   labels.Selector
+	addressSet addressset.AddressSet
+	// namespaces of the egressFirewalls that have rules with this selector
+	namespaces sets.Set[string]
+}
+
+func getEgressFirewallNodeSelectorAddrSetDbIDs(selectorKey, controller string) *libovsdbops.DbObjectIDs {
+	return libovsdbops.NewDbObjectIDs(libovsdbops.AddressSetEgressFirewallNodeSelector, controller,
+		map[libovsdbops.ExternalIDKey]string{
+			libovsdbops.ObjectNameKey: selectorKey,
+		})
+}
+
+// getEgressFirewallNodeSelectorKey returns a string identifying the given node selector. Equivalent selectors have
+// the same key, and an empty selector, that selects all the nodes, has a key that is not a valid selector.
+func getEgressFirewallNodeSelectorKey(nodeSelector *metav1.LabelSelector) string {
+	return metav1.FormatLabelSelector(nodeSelector)
+}
+
+// getNodeInternalIPs returns the internal IPs of the given node
+func getNodeInternalIPs(node *kapi.Node) sets.Set[string] {
+	ips := sets.New[string]()
+	for _, addr := range node.Status.Addresses {
+		if addr.Type != kapi.NodeInternalIP {
+			continue
+		}
+		if ip := net.ParseIP(addr.Address); ip != nil {
+			ips.Insert(ip.String())
+		}
+	}
+	return ips
+}
+
+func parseIPs(ips sets.Set[string]) []net.IP {
+	parsed := make([]net.IP, 0, ips.Len())
+	for _, ip := range sets.List(ips) {
+		parsed = append(parsed, net.ParseIP(ip))
+	}
+	return parsed
+}
+
+// ensureEgressFirewallNodeSelector returns the address set of the nodes selected by the given node selector of a
+// rule of the egressFirewall in the given namespace, creating it with the currently selected nodes if the selector
+// is not used by any other egressFirewall rule yet.
+func (oc *DefaultNetworkController) ensureEgressFirewallNodeSelector(namespace string,
+	nodeSelector *metav1.LabelSelector) (addressset.AddressSet, error) {
+	key := getEgressFirewallNodeSelectorKey(nodeSelector)
+	oc.egressFirewallNodeSelectorsLock.Lock()
+	defer oc.egressFirewallNodeSelectorsLock.Unlock()
+	if efns, ok := oc.egressFirewallNodeSelectors[key]; ok {
+		efns.namespaces.Insert(namespace)
+		return efns.addressSet, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(nodeSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid node selector %s: %v", key, err)
+	}
+	// node events are handled with the lock held, the address set can't miss any of them
+	nodes, err := oc.watchFactory.GetNodesBySelector(*nodeSelector)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query nodes for node selector %s: %w", key, err)
+	}
+	ips := sets.New[string]()
+	for _, node := range nodes {
+		ips = ips.Union(getNodeInternalIPs(node))
+	}
+	addressSet, err := oc.addressSetFactory.NewAddressSet(getEgressFirewallNodeSelectorAddrSetDbIDs(key, oc.controllerName),
+		parseIPs(ips))
+	if err != nil {
+		return nil, fmt.Errorf("cannot create address set for node selector %s: %v", key, err)
+	}
+	oc.egressFirewallNodeSelectors[key] = &egressFirewallNodeSelector{
+		selector:   selector,
+		addressSet: addressSet,
+		namespaces: sets.New(namespace),
+	}
+	return addressSet, nil
+}
+
+// releaseEgressFirewallNodeSelectors removes the egressFirewall in the given namespace from the users of all the
+// node selectors, but the ones in keep, and destroys the address sets of the node selectors that are not used
+// anymore. It must be called once the ACLs referencing the address sets are deleted.
+func (oc *DefaultNetworkController) releaseEgressFirewallNodeSelectors(namespace string, keep sets.Set[string]) error {
+	oc.egressFirewallNodeSelectorsLock.Lock()
+	defer oc.egressFirewallNodeSelectorsLock.Unlock()
+	for key, efns := range oc.egressFirewallNodeSelectors {
+		if !keep.Has(key) {
+			efns.namespaces.Delete(namespace)
+		}
+		// also retry to destroy the address sets that failed to be destroyed before
+		if efns.namespaces.Len() > 0 {
+			continue
+		}
+		if err := efns.addressSet.Destroy(); err != nil {
+			return fmt.Errorf("failed to destroy address set for node selector %s: %v", key, err)
+		}
+		delete(oc.egressFirewallNodeSelectors, key)
+	}
+	return nil
+}
+
+// updateEgressFirewallForNode updates the address sets of the node selectors matching the old or new node with
+// the changes of the node labels and addresses. oldNode is nil on node add, and newNode is nil on node delete.
+func (oc *DefaultNetworkController) updateEgressFirewallForNode(oldNode, newNode *kapi.Node) error {
+	oc.egressFirewallNodeSelectorsLock.Lock()
+	defer oc.egressFirewallNodeSelectorsLock.Unlock()
+	var ops []ovsdb.Operation
+	for key, efns := range oc.egressFirewallNodeSelectors {
+		oldIPs, newIPs := sets.New[string](), sets.New[string]()
+		if oldNode != nil && efns.selector.Matches(labels.Set(oldNode.Labels)) {
+			oldIPs = getNodeInternalIPs(oldNode)
+		}
+		if newNode != nil && efns.selector.Matches(labels.Set(newNode.Labels)) {
+			newIPs = getNodeInternalIPs(newNode)
+		}
+		if ipsToDelete := oldIPs.Difference(newIPs); ipsToDelete.Len() > 0 {
+			deleteOps, err := efns.addressSet.DeleteIPsReturnOps(parseIPs(ipsToDelete))
+			if err != nil {
+				return fmt.Errorf("failed to get ops to delete node IPs from address set for node selector %s: %v",
+					key, err)
+			}
+			ops = append(ops, deleteOps...)
+		}
+		if ipsToAdd := newIPs.Difference(oldIPs); ipsToAdd.Len() > 0 {
+			addOps, err := efns.addressSet.AddIPsReturnOps(parseIPs(ipsToAdd))
+			if err != nil {
+				return fmt.Errorf("failed to get ops to add node IPs to address set for node selector %s: %v",
+					key, err)
+			}
+			ops = append(ops, addOps...)
+		}
+	}
+	if _, err := libovsdbops.TransactAndCheck(oc.nbClient, ops); err != nil {
+		return fmt.Errorf("failed to update egress firewall node selector address sets: %v", err)
+	}
+	return nil
+}
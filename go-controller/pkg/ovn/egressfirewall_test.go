@@ -45,6 +45,14 @@ func newEgressFirewallObject(name, namespace string, egressRules []egressfirewal
 	}
 }
 
+func buildEgressFirewallNodeSelectorAddressSets(nodeSelector *metav1.LabelSelector, ips []net.IP) (*nbdb.AddressSet, *nbdb.AddressSet) {
+	dbIDs := getEgressFirewallNodeSelectorAddrSetDbIDs(getEgressFirewallNodeSelectorKey(nodeSelector), DefaultNetworkControllerName)
+	v4set, v6set := addressset.GetDbObjsForAS(dbIDs, ips)
+	v4set.UUID = v4set.Name + "-UUID"
+	v6set.UUID = v6set.Name + "-UUID"
+	return v4set, v6set
+}
+
 var _ = ginkgo.Describe("OVN EgressFirewall Operations", func() {
 	var (
 		app                    *cli.App
@@ -375,6 +383,14 @@ var _ = ginkgo.Describe("OVN EgressFirewall Operations", func() {
 
 					_, err := fakeOVN.fakeClient.EgressFirewallClient.K8sV1().EgressFirewalls(egressFirewall.Namespace).Get(context.TODO(), egressFirewall.Name, metav1.GetOptions{})
 					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					gomega.Eventually(func() egressfirewallapi.EgressFirewallStatus {
+						ef, err := fakeOVN.fakeClient.EgressFirewallClient.K8sV1().EgressFirewalls(egressFirewall.Namespace).Get(context.TODO(), egressFirewall.Name, metav1.GetOptions{})
+						gomega.Expect(err).NotTo(gomega.HaveOccurred())
+						return ef.Status
+					}).Should(gomega.Equal(egressfirewallapi.EgressFirewallStatus{
+						Status:   egressFirewallAppliedCorrectly,
+						Messages: []string{t.OvnDefaultZone + ": " + egressFirewallAppliedCorrectly},
+					}))
 
 					asHash, _ := getNsAddrSetHashNames(namespace1.Name)
 					dbIDs := fakeOVN.controller.getEgressFirewallACLDbIDs(egressFirewall.Namespace, 0)
@@ -532,18 +548,22 @@ var _ = ginkgo.Describe("OVN EgressFirewall Operations", func() {
 
 					namespace1ASip4, _ := buildNamespaceAddressSets(namespace1.Name, []net.IP{})
 
+					labelKey := "name"
+					labelValue := "test"
+					selector := metav1.LabelSelector{MatchLabels: map[string]string{labelKey: labelValue}}
+					// address set of a node selector that is not used anymore
+					staleNodeSelectorASip4, _ := buildEgressFirewallNodeSelectorAddressSets(
+						&metav1.LabelSelector{MatchLabels: map[string]string{labelKey: noneMatch}}, []net.IP{net.ParseIP(nodeIP)})
+
 					initialTestData := []libovsdbtest.TestData{
 						expectedOVNClusterRouter,
 						expectedClusterPortGroup,
 						namespace1ASip4,
+						staleNodeSelectorASip4,
 					}
 					dbSetup := libovsdbtest.TestSetup{
 						NBData: initialTestData,
 					}
-
-					labelKey := "name"
-					labelValue := "test"
-					selector := metav1.LabelSelector{MatchLabels: map[string]string{labelKey: labelValue}}
 					egressFirewall := newEgressFirewallObject("default", namespace1.Name, []egressfirewallapi.EgressFirewallRule{
 						{
 							Type: "Allow",
@@ -583,30 +603,14 @@ var _ = ginkgo.Describe("OVN EgressFirewall Operations", func() {
 					err = fakeOVN.controller.WatchEgressFwNodes()
 					gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
-					gomega.Eventually(fakeOVN.nbClient).Should(libovsdbtest.HaveData(initialTestData))
-
-					// update the node to match the selector
-					patch := struct {
-						Metadata map[string]interface{} `json:"metadata"`
-					}{
-						Metadata: map[string]interface{}{
-							"labels": map[string]string{labelKey: labelValue},
-						},
-					}
-					ginkgo.By("Updating a node to match nodeSelector on Egress Firewall")
-					patchData, err := json.Marshal(&patch)
-					gomega.Expect(err).NotTo(gomega.HaveOccurred())
-					// trigger update event
-					_, err = fakeOVN.fakeClient.KubeClient.CoreV1().Nodes().Patch(context.TODO(), nodeName,
-						types.MergePatchType, patchData, metav1.PatchOptions{})
-					gomega.Expect(err).NotTo(gomega.HaveOccurred())
 					asHash, _ := getNsAddrSetHashNames(namespace1.Name)
+					nodeSelectorASip4, _ := buildEgressFirewallNodeSelectorAddressSets(&selector, []net.IP{})
 					dbIDs := fakeOVN.controller.getEgressFirewallACLDbIDs(egressFirewall.Namespace, 0)
 					ipv4ACL := libovsdbops.BuildACL(
 						getACLName(dbIDs),
 						nbdb.ACLDirectionToLport,
 						t.EgressFirewallStartPriority,
-						fmt.Sprintf("(ip4.dst == %s) && ip4.src == $%s", nodeIP, asHash),
+						fmt.Sprintf("(ip4.dst == $%s) && ip4.src == $%s", nodeSelectorASip4.Name, asHash),
 						nbdb.ACLActionAllow,
 						t.OvnACLLoggingMeter,
 						"",
@@ -616,10 +620,39 @@ var _ = ginkgo.Describe("OVN EgressFirewall Operations", func() {
 					)
 					ipv4ACL.UUID = "ipv4ACL-UUID"
 
-					// new ACL will be added to the port group
+					// the ACL references the empty node selector address set, the stale one is deleted
 					expectedClusterPortGroup.ACLs = []string{ipv4ACL.UUID}
-					expectedDatabaseState := []libovsdb.TestData{expectedClusterPortGroup, ipv4ACL, expectedOVNClusterRouter, namespace1ASip4}
+					expectedDatabaseState := []libovsdb.TestData{expectedClusterPortGroup, ipv4ACL, expectedOVNClusterRouter,
+						namespace1ASip4, nodeSelectorASip4}
+					gomega.Eventually(fakeOVN.nbClient).Should(libovsdbtest.HaveData(expectedDatabaseState))
+
+					// update the node to match the selector
+					patch := struct {
+						Metadata map[string]interface{} `json:"metadata"`
+					}{
+						Metadata: map[string]interface{}{
+							"labels": map[string]string{labelKey: labelValue},
+						},
+					}
+					ginkgo.By("Updating a node to match nodeSelector on Egress Firewall")
+					patchData, err := json.Marshal(&patch)
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					// trigger update event
+					_, err = fakeOVN.fakeClient.KubeClient.CoreV1().Nodes().Patch(context.TODO(), nodeName,
+						types.MergePatchType, patchData, metav1.PatchOptions{})
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					// only the node selector address set is updated
+					nodeSelectorASip4.Addresses = []string{nodeIP}
+					gomega.Eventually(fakeOVN.nbClient).Should(libovsdbtest.HaveData(expectedDatabaseState))
 
+					ginkgo.By("Updating the address of a node matching nodeSelector on Egress Firewall")
+					newNodeIP := "9.9.9.10"
+					node, err := fakeOVN.fakeClient.KubeClient.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					node.Status.Addresses = []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: newNodeIP}}
+					_, err = fakeOVN.fakeClient.KubeClient.CoreV1().Nodes().UpdateStatus(context.TODO(), node, metav1.UpdateOptions{})
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					nodeSelectorASip4.Addresses = []string{newNodeIP}
 					gomega.Eventually(fakeOVN.nbClient).Should(libovsdbtest.HaveData(expectedDatabaseState))
 
 					ginkgo.By("Updating a node to not match nodeSelector on Egress Firewall")
@@ -630,7 +663,7 @@ var _ = ginkgo.Describe("OVN EgressFirewall Operations", func() {
 					_, err = fakeOVN.fakeClient.KubeClient.CoreV1().Nodes().Patch(context.TODO(), nodeName,
 						types.MergePatchType, patchData, metav1.PatchOptions{})
 					gomega.Expect(err).NotTo(gomega.HaveOccurred())
-					expectedClusterPortGroup.ACLs = []string{}
+					nodeSelectorASip4.Addresses = nil
 					gomega.Eventually(fakeOVN.nbClient).Should(libovsdbtest.HaveData(expectedDatabaseState))
 
 					return nil
@@ -960,13 +993,17 @@ var _ = ginkgo.Describe("OVN EgressFirewall Operations", func() {
 						err = fakeOVN.controller.WatchEgressFwNodes()
 						gomega.Expect(err).NotTo(gomega.HaveOccurred())
 						asHashv4, asHashv6 := getNsAddrSetHashNames(namespace1.Name)
+						nodeSelectorASip4, nodeSelectorASip6 := buildEgressFirewallNodeSelectorAddressSets(&selector,
+							[]net.IP{net.ParseIP(nodeAddr.Address)})
 						var match string
 						if config.IPv4Mode {
-							match = fmt.Sprintf("(ip4.dst == %s) && ip4.src == $%s",
-								nodeIP, asHashv4)
+							match = fmt.Sprintf("(ip4.dst == $%s) && ip4.src == $%s",
+								nodeSelectorASip4.Name, asHashv4)
+							initialData = append(initialData, nodeSelectorASip4)
 						} else {
-							match = fmt.Sprintf("(ip6.dst == %s) && ip6.src == $%s",
-								nodeIP6, asHashv6)
+							match = fmt.Sprintf("(ip6.dst == $%s) && ip6.src == $%s",
+								nodeSelectorASip6.Name, asHashv6)
+							initialData = append(initialData, nodeSelectorASip6)
 						}
 						dbIDs := fakeOVN.controller.getEgressFirewallACLDbIDs(egressFirewall.Namespace, 0)
 						acl := libovsdbops.BuildACL(
@@ -1226,6 +1263,33 @@ var _ = ginkgo.Describe("OVN test basic functions", func() {
 			gomega.Expect(test.expectedMatch).To(gomega.Equal(l4Match))
 		}
 	})
+	ginkgo.It("aggregates the egress firewall status of the zones", func() {
+		status := &egressfirewallapi.EgressFirewallStatus{}
+		gomega.Expect(setEgressFirewallZoneStatus(status, "zone1", egressFirewallAppliedCorrectly,
+			sets.New("zone1", "zone2"))).To(gomega.BeTrue())
+		gomega.Expect(status.Status).To(gomega.Equal(egressFirewallAppliedCorrectly))
+		gomega.Expect(status.Messages).To(gomega.Equal([]string{"zone1: " + egressFirewallAppliedCorrectly}))
+
+		// the rules are not applied as long as a zone failed to apply them
+		gomega.Expect(setEgressFirewallZoneStatus(status, "zone2", egressFirewallAddError,
+			sets.New("zone1", "zone2"))).To(gomega.BeTrue())
+		gomega.Expect(status.Status).To(gomega.Equal(egressFirewallAddError))
+		gomega.Expect(status.Messages).To(gomega.Equal([]string{
+			"zone1: " + egressFirewallAppliedCorrectly,
+			"zone2: " + egressFirewallAddError,
+		}))
+
+		gomega.Expect(setEgressFirewallZoneStatus(status, "zone2", egressFirewallAppliedCorrectly,
+			sets.New("zone1", "zone2"))).To(gomega.BeTrue())
+		gomega.Expect(status.Status).To(gomega.Equal(egressFirewallAppliedCorrectly))
+		gomega.Expect(setEgressFirewallZoneStatus(status, "zone2", egressFirewallAppliedCorrectly,
+			sets.New("zone1", "zone2"))).To(gomega.BeFalse())
+
+		// the messages of the zones without nodes are dropped
+		gomega.Expect(setEgressFirewallZoneStatus(status, "zone2", egressFirewallAppliedCorrectly,
+			sets.New("zone2"))).To(gomega.BeTrue())
+		gomega.Expect(status.Messages).To(gomega.Equal([]string{"zone2: " + egressFirewallAppliedCorrectly}))
+	})
 	ginkgo.It("computes correct match function", func() {
 		type testcase struct {
 			clusterSubnets []string
@@ -1507,7 +1571,7 @@ var _ = ginkgo.Describe("OVN test basic functions", func() {
 				output: egressFirewallRule{
 					id:     1,
 					access: egressfirewallapi.EgressFirewallRuleAllow,
-					to: destination{nodeAddrs: sets.New[string](), nodeSelector: &metav1.LabelSelector{
+					to: destination{nodeSelector: &metav1.LabelSelector{
 						MatchLabels: map[string]string{"no": "match"}}},
 				},
 			},
@@ -1522,7 +1586,7 @@ var _ = ginkgo.Describe("OVN test basic functions", func() {
 				output: egressFirewallRule{
 					id:     1,
 					access: egressfirewallapi.EgressFirewallRuleAllow,
-					to:     destination{nodeAddrs: sets.New("9.9.9.9", "10.10.10.10"), nodeSelector: &metav1.LabelSelector{}},
+					to:     destination{nodeSelector: &metav1.LabelSelector{}},
 				},
 			},
 			// match one node
@@ -1536,7 +1600,7 @@ var _ = ginkgo.Describe("OVN test basic functions", func() {
 				output: egressFirewallRule{
 					id:     1,
 					access: egressfirewallapi.EgressFirewallRuleAllow,
-					to:     destination{nodeAddrs: sets.New(node1Addr), nodeSelector: &metav1.LabelSelector{MatchLabels: nodeLabel}},
+					to:     destination{nodeSelector: &metav1.LabelSelector{MatchLabels: nodeLabel}},
 				},
 			},
 		}
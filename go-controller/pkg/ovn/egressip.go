@@ -2213,6 +2213,8 @@ type egressNode struct {
 	isReachable        bool
 	isEgressAssignable bool
 	name               string
+	// lastReachable is the time of the last successful periodic health check
+	lastReachable time.Time
 }
 
 func (e *egressNode) getAllocationCountForEgressIP(name string) (count int) {
@@ -2300,8 +2302,17 @@ type egressIPController struct {
 	reachabilityCheckInterval time.Duration
 	// EgressIP Node reachability gRPC port (0 means it should use dial instead)
 	egressIPNodeHealthCheckPort int
+	// failoverSLO is the target time to detect an unreachable node and move its
+	// egress IPs away (0 means no SLO, the above timeout and interval are used)
+	failoverSLO time.Duration
 }
 
+// egressIPFailoverSLODivisor splits the failover SLO: both the health check
+// interval and the health check timeout are a quarter of it, so that an
+// unreachable node is detected within half of the SLO, leaving the other half
+// to reassign its egress IPs.
+const egressIPFailoverSLODivisor = 4
+
 // addStandByEgressIPAssignment does the same setup that is done by addPodEgressIPAssignments but for
 // the standby egressIP. This must always be called with a lock on podAssignmentState mutex
 // This is special case function called only from deleteEgressIPAssignments, don't use this for normal setup
@@ -2671,7 +2682,11 @@ func (oc *DefaultNetworkController) deletePodIPsFromAddressSet(addrSetIPs []net.
 // is important because egress IP is based upon routing traffic to these nodes,
 // and if they aren't reachable we shouldn't be using them for egress IP.
 func (oc *DefaultNetworkController) checkEgressNodesReachability() {
-	timer := time.NewTicker(oc.eIPC.reachabilityCheckInterval)
+	interval := oc.eIPC.reachabilityCheckInterval
+	if oc.eIPC.failoverSLO > 0 {
+		interval = oc.eIPC.failoverSLO / egressIPFailoverSLODivisor
+	}
+	timer := time.NewTicker(interval)
 	defer timer.Stop()
 	for {
 		select {
@@ -2686,17 +2701,28 @@ func (oc *DefaultNetworkController) checkEgressNodesReachability() {
 
 func checkEgressNodesReachabilityIterate(oc *DefaultNetworkController) {
 	reAddOrDelete := map[string]bool{}
+	// failoverStart holds, for the nodes detected as unreachable, the time they
+	// were last known to be reachable
+	failoverStart := map[string]time.Time{}
+	checkStart := time.Now()
 	oc.eIPC.allocator.Lock()
+	reachability := oc.probeEgressNodesReachability()
 	for _, eNode := range oc.eIPC.allocator.cache {
-		if eNode.isEgressAssignable && eNode.isReady {
+		if isReachable, probed := reachability[eNode.name]; probed {
 			wasReachable := eNode.isReachable
-			isReachable := oc.isReachable(eNode.name, eNode.mgmtIPs, eNode.healthClient)
 			if wasReachable && !isReachable {
 				reAddOrDelete[eNode.name] = true
+				failoverStart[eNode.name] = eNode.lastReachable
+				if eNode.lastReachable.IsZero() {
+					failoverStart[eNode.name] = checkStart
+				}
 			} else if !wasReachable && isReachable {
 				reAddOrDelete[eNode.name] = false
 			}
 			eNode.isReachable = isReachable
+			if isReachable {
+				eNode.lastReachable = checkStart
+			}
 		} else {
 			// End connection (if there is one). This is important because
 			// it accounts for cases where node is not labelled with
@@ -2712,6 +2738,13 @@ func checkEgressNodesReachabilityIterate(oc *DefaultNetworkController) {
 			klog.Warningf("Node: %s is detected as unreachable, deleting it from egress assignment", nodeName)
 			if err := oc.deleteEgressNode(nodeName); err != nil {
 				klog.Errorf("Node: %s is detected as unreachable, but could not re-assign egress IPs, err: %v", nodeName, err)
+				continue
+			}
+			failoverDuration := time.Since(failoverStart[nodeName])
+			metrics.RecordEgressIPFailover(failoverDuration)
+			if oc.eIPC.failoverSLO > 0 && failoverDuration > oc.eIPC.failoverSLO {
+				klog.Warningf("Node: %s egress IPs were re-assigned after %v, exceeding the failover SLO of %v",
+					nodeName, failoverDuration, oc.eIPC.failoverSLO)
 			}
 		} else {
 			klog.Infof("Node: %s is detected as reachable and ready again, adding it to egress assignment", nodeName)
@@ -2722,6 +2755,42 @@ func checkEgressNodesReachabilityIterate(oc *DefaultNetworkController) {
 	}
 }
 
+// probeEgressNodesReachability health checks the ready egress assignable nodes
+// and returns whether each of them is reachable. In failover SLO mode, the nodes
+// are checked concurrently so that the time it takes does not grow with the
+// number of nodes. Must be called with the allocator lock held.
+func (oc *DefaultNetworkController) probeEgressNodesReachability() map[string]bool {
+	var eNodes []*egressNode
+	for _, eNode := range oc.eIPC.allocator.cache {
+		if eNode.isEgressAssignable && eNode.isReady {
+			eNodes = append(eNodes, eNode)
+		}
+	}
+
+	reachability := make(map[string]bool, len(eNodes))
+	if oc.eIPC.failoverSLO == 0 {
+		for _, eNode := range eNodes {
+			reachability[eNode.name] = oc.isReachable(eNode.name, eNode.mgmtIPs, eNode.healthClient)
+		}
+		return reachability
+	}
+
+	results := make([]bool, len(eNodes))
+	wg := &sync.WaitGroup{}
+	for i, eNode := range eNodes {
+		wg.Add(1)
+		go func(i int, eNode *egressNode) {
+			defer wg.Done()
+			results[i] = oc.isReachable(eNode.name, eNode.mgmtIPs, eNode.healthClient)
+		}(i, eNode)
+	}
+	wg.Wait()
+	for i, eNode := range eNodes {
+		reachability[eNode.name] = results[i]
+	}
+	return reachability
+}
+
 func (oc *DefaultNetworkController) isReachable(nodeName string, mgmtIPs []net.IP, healthClient healthcheck.EgressIPHealthClient) bool {
 	// Check if we need to do node reachability check
 	if oc.eIPC.egressIPTotalTimeout == 0 {
@@ -2731,7 +2800,11 @@ func (oc *DefaultNetworkController) isReachable(nodeName string, mgmtIPs []net.I
 	if oc.eIPC.egressIPNodeHealthCheckPort == 0 {
 		return isReachableLegacy(nodeName, mgmtIPs, oc.eIPC.egressIPTotalTimeout)
 	}
-	return isReachableViaGRPC(mgmtIPs, healthClient, oc.eIPC.egressIPNodeHealthCheckPort, oc.eIPC.egressIPTotalTimeout)
+	timeout := time.Duration(oc.eIPC.egressIPTotalTimeout) * time.Second
+	if oc.eIPC.failoverSLO > 0 {
+		timeout = oc.eIPC.failoverSLO / egressIPFailoverSLODivisor
+	}
+	return isReachableViaGRPC(mgmtIPs, healthClient, oc.eIPC.egressIPNodeHealthCheckPort, timeout)
 }
 
 func isReachableLegacy(node string, mgmtIPs []net.IP, totalTimeout int) bool {
@@ -2804,8 +2877,8 @@ func (hccAlloc *egressIPHealthcheckClientAllocator) allocate(nodeName string) he
 	return healthcheck.NewEgressIPHealthClient(nodeName)
 }
 
-func isReachableViaGRPC(mgmtIPs []net.IP, healthClient healthcheck.EgressIPHealthClient, healthCheckPort, totalTimeout int) bool {
-	dialCtx, dialCancel := context.WithTimeout(context.Background(), time.Duration(totalTimeout)*time.Second)
+func isReachableViaGRPC(mgmtIPs []net.IP, healthClient healthcheck.EgressIPHealthClient, healthCheckPort int, timeout time.Duration) bool {
+	dialCtx, dialCancel := context.WithTimeout(context.Background(), timeout)
 	defer dialCancel()
 
 	if !healthClient.IsConnected() {
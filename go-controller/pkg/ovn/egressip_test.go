@@ -8296,6 +8296,108 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 			err := app.Run([]string{app.Name})
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 		})
+
+		ginkgo.It("should reassign egress IPs of an unreachable node in failover SLO mode", func() {
+			app.Action = func(ctx *cli.Context) error {
+				egressIP := "192.168.126.101"
+				node1IPv4 := "192.168.126.51/24"
+				node2IPv4 := "192.168.126.52/24"
+				newEgressNode := func(name, nodeIPv4 string) v1.Node {
+					return v1.Node{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: name,
+							Annotations: map[string]string{
+								"k8s.ovn.org/node-primary-ifaddr": fmt.Sprintf("{\"ipv4\": \"%s\"}", nodeIPv4),
+								"k8s.ovn.org/node-subnets":        fmt.Sprintf("{\"default\":[\"%s\"]}", v4NodeSubnet),
+							},
+							Labels: map[string]string{
+								"k8s.ovn.org/egress-assignable": "",
+							},
+						},
+						Status: v1.NodeStatus{
+							Conditions: []v1.NodeCondition{
+								{
+									Type:   v1.NodeReady,
+									Status: v1.ConditionTrue,
+								},
+							},
+						},
+					}
+				}
+				node1 := newEgressNode(node1Name, node1IPv4)
+				node2 := newEgressNode(node2Name, node2IPv4)
+				eIP1 := egressipv1.EgressIP{
+					ObjectMeta: newEgressIPMeta(egressIPName),
+					Spec: egressipv1.EgressIPSpec{
+						EgressIPs: []string{egressIP},
+					},
+				}
+				nbData := []libovsdbtest.TestData{
+					&nbdb.LogicalRouter{
+						Name: ovntypes.OVNClusterRouter,
+						UUID: ovntypes.OVNClusterRouter + "-UUID",
+					},
+				}
+				for _, node := range []v1.Node{node1, node2} {
+					nbData = append(nbData,
+						&nbdb.LogicalRouter{
+							Name: ovntypes.GWRouterPrefix + node.Name,
+							UUID: ovntypes.GWRouterPrefix + node.Name + "-UUID",
+						},
+						&nbdb.LogicalSwitchPort{
+							UUID: types.EXTSwitchToGWRouterPrefix + types.GWRouterPrefix + node.Name + "UUID",
+							Name: types.EXTSwitchToGWRouterPrefix + types.GWRouterPrefix + node.Name,
+							Type: "router",
+							Options: map[string]string{
+								"router-port": types.GWRouterToExtSwitchPrefix + "GR_" + node.Name,
+							},
+						},
+					)
+				}
+				fakeOvn.startWithDBSetup(
+					libovsdbtest.TestSetup{NBData: nbData},
+					&egressipv1.EgressIPList{
+						Items: []egressipv1.EgressIP{eIP1},
+					},
+					&v1.NodeList{
+						Items: []v1.Node{node1, node2},
+					},
+				)
+
+				// Virtually disable background reachability check by using a huge SLO
+				fakeOvn.controller.eIPC.failoverSLO = time.Hour
+
+				err := fakeOvn.controller.WatchEgressNodes()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				gomega.Eventually(getEgressIPStatusLen(eIP1.Name)).Should(gomega.Equal(1))
+				_, nodes := getEgressIPStatus(eIP1.Name)
+				assignedNode, otherNode := nodes[0], node1Name
+				if assignedNode == node1Name {
+					otherNode = node2Name
+				}
+
+				// both nodes are health checked and recorded as reachable
+				checkEgressNodesReachabilityIterate(fakeOvn.controller)
+				fakeOvn.controller.eIPC.allocator.Lock()
+				for _, eNode := range fakeOvn.controller.eIPC.allocator.cache {
+					gomega.Expect(eNode.isReachable).To(gomega.BeTrue())
+					gomega.Expect(eNode.lastReachable.IsZero()).To(gomega.BeFalse())
+				}
+				hcClient := fakeOvn.controller.eIPC.allocator.cache[assignedNode].healthClient.(*fakeEgressIPHealthClient)
+				fakeOvn.controller.eIPC.allocator.Unlock()
+
+				hcClient.FakeProbeFailure = true
+				checkEgressNodesReachabilityIterate(fakeOvn.controller)
+				gomega.Eventually(func() []string {
+					_, nodes := getEgressIPStatus(eIP1.Name)
+					return nodes
+				}).Should(gomega.Equal([]string{otherNode}))
+				return nil
+			}
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
 	})
 
 	ginkgo.Context("Dual-stack assignment", func() {
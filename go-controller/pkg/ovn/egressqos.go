@@ -3,6 +3,7 @@ package ovn
 import (
 	"fmt"
 	"net"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -23,6 +24,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	v1coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/tools/cache"
@@ -35,6 +37,9 @@ const (
 	maxEgressQoSRetries        = 10
 	defaultEgressQoSName       = "default"
 	EgressQoSFlowStartPriority = 1000
+	// legacyEgressQoSExternalID was the only ExternalID of the EgressQoS QoSes, set to their namespace,
+	// before they were created with DbObjectIDs. Should only be used for sync.
+	legacyEgressQoSExternalID = "EgressQoS"
 )
 
 type egressQoS struct {
@@ -62,6 +67,13 @@ func getEgressQosAddrSetDbIDs(namespace, priority, controller string) *libovsdbo
 	})
 }
 
+func getEgressQoSDbIDs(namespace string, priority int, controller string) *libovsdbops.DbObjectIDs {
+	return libovsdbops.NewDbObjectIDs(libovsdbops.QoSEgressQoS, controller, map[libovsdbops.ExternalIDKey]string{
+		libovsdbops.ObjectNameKey: namespace,
+		libovsdbops.PriorityKey:   strconv.Itoa(priority),
+	})
+}
+
 // shallow copies the EgressQoS object provided.
 func (oc *DefaultNetworkController) cloneEgressQoS(raw *egressqosapi.EgressQoS) (*egressQoS, error) {
 	eq := &egressQoS{
@@ -301,6 +313,18 @@ func (oc *DefaultNetworkController) onEgressQoSAdd(obj interface{}) {
 	oc.egressQoSQueue.Add(key)
 }
 
+// enqueueAllEgressQoSes queues all the EgressQoSes for processing.
+func (oc *DefaultNetworkController) enqueueAllEgressQoSes() error {
+	eqs, err := oc.egressQoSLister.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("couldn't list EgressQoSes: %v", err)
+	}
+	for _, eq := range eqs {
+		oc.onEgressQoSAdd(eq)
+	}
+	return nil
+}
+
 // onEgressQoSUpdate queues the EgressQoS for processing.
 func (oc *DefaultNetworkController) onEgressQoSUpdate(oldObj, newObj interface{}) {
 	oldEQ := oldObj.(*egressqosapi.EgressQoS)
@@ -375,18 +399,20 @@ func (oc *DefaultNetworkController) repairEgressQoSes() error {
 		return err
 	}
 
-	nsWithQoS := map[string]bool{}
+	nsWithQoS := sets.New[string]()
 	for _, q := range existing {
-		nsWithQoS[q.Namespace] = true
+		nsWithQoS.Insert(q.Namespace)
 	}
 
+	// QoSes created before they had DbObjectIDs are updated with them when their EgressQoS is added,
+	// only the ones of deleted EgressQoSes are left.
 	p := func(q *nbdb.QoS) bool {
-		ns, ok := q.ExternalIDs["EgressQoS"]
+		ns, ok := q.ExternalIDs[legacyEgressQoSExternalID]
 		if !ok {
 			return false
 		}
 
-		return !nsWithQoS[ns]
+		return !nsWithQoS.Has(ns)
 	}
 	existingQoSes, err := libovsdbops.FindQoSesWithPredicate(oc.nbClient, p)
 	if err != nil {
@@ -419,14 +445,12 @@ func (oc *DefaultNetworkController) repairEgressQoSes() error {
 			return fmt.Errorf("unable to remove stale qoses, err: %v", err)
 		}
 	}
-	predicateIDs := libovsdbops.NewDbObjectIDs(libovsdbops.AddressSetEgressQoS, oc.controllerName, nil)
-	predicateFunc := func(as *nbdb.AddressSet) bool {
-		// ObjectNameKey is namespace
-		return !nsWithQoS[as.ExternalIDs[libovsdbops.ObjectNameKey.String()]]
-	}
-	asPredicate := libovsdbops.GetPredicate[*nbdb.AddressSet](predicateIDs, predicateFunc)
-	if err := libovsdbops.DeleteAddressSetsWithPredicate(oc.nbClient, asPredicate); err != nil {
-		return fmt.Errorf("failed to remove stale egress qos address sets, err: %v", err)
+	err = libovsdbops.GarbageCollectObjects(oc.nbClient, oc.controllerName, map[*libovsdbops.ObjectIDsType]sets.Set[string]{
+		libovsdbops.QoSEgressQoS:        nsWithQoS,
+		libovsdbops.AddressSetEgressQoS: nsWithQoS,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove stale egress qos objects, err: %v", err)
 	}
 
 	return nil
@@ -483,14 +507,11 @@ func (oc *DefaultNetworkController) cleanEgressQoSNS(namespace string) error {
 	eq.Lock()
 	defer eq.Unlock()
 
-	p := func(q *nbdb.QoS) bool {
-		eqNs, ok := q.ExternalIDs["EgressQoS"]
-		if !ok { // the QoS is not managed by an EgressQoS
-			return false
-		}
-		return eqNs == eq.namespace
-	}
-	existingQoSes, err := libovsdbops.FindQoSesWithPredicate(oc.nbClient, p)
+	predicateIDs := libovsdbops.NewDbObjectIDs(libovsdbops.QoSEgressQoS, oc.controllerName,
+		map[libovsdbops.ExternalIDKey]string{
+			libovsdbops.ObjectNameKey: eq.namespace,
+		})
+	existingQoSes, err := libovsdbops.FindQoSesWithDbIDs(oc.nbClient, predicateIDs, nil)
 	if err != nil {
 		return err
 	}
@@ -521,7 +542,7 @@ func (oc *DefaultNetworkController) cleanEgressQoSNS(namespace string) error {
 			return fmt.Errorf("failed to delete qos, err: %s", err)
 		}
 	}
-	predicateIDs := libovsdbops.NewDbObjectIDs(libovsdbops.AddressSetEgressQoS, oc.controllerName,
+	predicateIDs = libovsdbops.NewDbObjectIDs(libovsdbops.AddressSetEgressQoS, oc.controllerName,
 		map[libovsdbops.ExternalIDKey]string{
 			libovsdbops.ObjectNameKey: eq.namespace,
 		})
@@ -578,7 +599,7 @@ func (oc *DefaultNetworkController) addEgressQoS(eqObj *egressqosapi.EgressQoS)
 			Match:       match,
 			Priority:    r.priority,
 			Action:      map[string]int{nbdb.QoSActionDSCP: r.dscp},
-			ExternalIDs: map[string]string{"EgressQoS": eq.namespace},
+			ExternalIDs: getEgressQoSDbIDs(eq.namespace, r.priority, oc.controllerName).GetExternalIDs(),
 		}
 		qoses = append(qoses, qos)
 	}
@@ -929,11 +950,8 @@ func (oc *DefaultNetworkController) syncEgressQoSNode(key string) error {
 		return err
 	}
 
-	p := func(q *nbdb.QoS) bool {
-		_, ok := q.ExternalIDs["EgressQoS"]
-		return ok
-	}
-	existingQoSes, err := libovsdbops.FindQoSesWithPredicate(oc.nbClient, p)
+	predicateIDs := libovsdbops.NewDbObjectIDs(libovsdbops.QoSEgressQoS, oc.controllerName, nil)
+	existingQoSes, err := libovsdbops.FindQoSesWithDbIDs(oc.nbClient, predicateIDs, nil)
 	if err != nil {
 		return err
 	}
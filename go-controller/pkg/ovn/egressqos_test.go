@@ -76,12 +76,13 @@ var _ = ginkgo.Describe("OVN EgressQoS Operations", func() {
 				config.IPv4Mode = ipv4Mode
 				config.IPv6Mode = ipv6Mode
 
+				// created before QoSes had DbObjectIDs
 				staleQoS := &nbdb.QoS{
 					Direction:   nbdb.QoSDirectionToLport,
 					Match:       "some-match",
 					Priority:    EgressQoSFlowStartPriority,
 					Action:      map[string]int{nbdb.QoSActionDSCP: 50},
-					ExternalIDs: map[string]string{"EgressQoS": "staleNS"},
+					ExternalIDs: map[string]string{legacyEgressQoSExternalID: "staleNS"},
 					UUID:        "staleQoS-UUID",
 				}
 				staleAddrSet, _ := addressset.GetDbObjsForAS(
@@ -145,7 +146,7 @@ var _ = ginkgo.Describe("OVN EgressQoS Operations", func() {
 					Match:       match1,
 					Priority:    EgressQoSFlowStartPriority,
 					Action:      map[string]int{nbdb.QoSActionDSCP: 50},
-					ExternalIDs: map[string]string{"EgressQoS": namespaceT.Name},
+					ExternalIDs: getEgressQoSDbIDs(namespaceT.Name, EgressQoSFlowStartPriority, controllerName).GetExternalIDs(),
 					UUID:        "qos1-UUID",
 				}
 				qos2 := &nbdb.QoS{
@@ -153,7 +154,7 @@ var _ = ginkgo.Describe("OVN EgressQoS Operations", func() {
 					Match:       match2,
 					Priority:    EgressQoSFlowStartPriority - 1,
 					Action:      map[string]int{nbdb.QoSActionDSCP: 60},
-					ExternalIDs: map[string]string{"EgressQoS": namespaceT.Name},
+					ExternalIDs: getEgressQoSDbIDs(namespaceT.Name, EgressQoSFlowStartPriority-1, controllerName).GetExternalIDs(),
 					UUID:        "qos2-UUID",
 				}
 				node1Switch.QOSRules = []string{qos1.UUID, qos2.UUID}
@@ -184,7 +185,7 @@ var _ = ginkgo.Describe("OVN EgressQoS Operations", func() {
 					Match:       match1,
 					Priority:    EgressQoSFlowStartPriority,
 					Action:      map[string]int{nbdb.QoSActionDSCP: 40},
-					ExternalIDs: map[string]string{"EgressQoS": namespaceT.Name},
+					ExternalIDs: getEgressQoSDbIDs(namespaceT.Name, EgressQoSFlowStartPriority, controllerName).GetExternalIDs(),
 					UUID:        "qos3-UUID",
 				}
 				node1Switch.QOSRules = []string{qos3.UUID}
@@ -239,7 +240,7 @@ var _ = ginkgo.Describe("OVN EgressQoS Operations", func() {
 					Match:       "some-match",
 					Priority:    EgressQoSFlowStartPriority,
 					Action:      map[string]int{nbdb.QoSActionDSCP: 50},
-					ExternalIDs: map[string]string{"EgressQoS": "staleNS"},
+					ExternalIDs: getEgressQoSDbIDs("staleNS", EgressQoSFlowStartPriority, controllerName).GetExternalIDs(),
 					UUID:        "staleQoS-UUID",
 				}
 				staleAddrSet, _ := addressset.GetDbObjsForAS(
@@ -326,7 +327,7 @@ var _ = ginkgo.Describe("OVN EgressQoS Operations", func() {
 					Match:       match1,
 					Priority:    EgressQoSFlowStartPriority,
 					Action:      map[string]int{nbdb.QoSActionDSCP: 50},
-					ExternalIDs: map[string]string{"EgressQoS": namespaceT.Name},
+					ExternalIDs: getEgressQoSDbIDs(namespaceT.Name, EgressQoSFlowStartPriority, controllerName).GetExternalIDs(),
 					UUID:        "qos1-UUID",
 				}
 				qos2 := &nbdb.QoS{
@@ -334,11 +335,13 @@ var _ = ginkgo.Describe("OVN EgressQoS Operations", func() {
 					Match:       match2,
 					Priority:    EgressQoSFlowStartPriority - 1,
 					Action:      map[string]int{nbdb.QoSActionDSCP: 60},
-					ExternalIDs: map[string]string{"EgressQoS": namespaceT.Name},
+					ExternalIDs: getEgressQoSDbIDs(namespaceT.Name, EgressQoSFlowStartPriority-1, controllerName).GetExternalIDs(),
 					UUID:        "qos2-UUID",
 				}
 				node1Switch.QOSRules = []string{qos1.UUID, qos2.UUID}
 				node2Switch.QOSRules = []string{qos1.UUID, qos2.UUID}
+				// stale QoSes are removed from all the switches
+				joinSwitch.QOSRules = []string{}
 				expectedDatabaseState := []libovsdbtest.TestData{
 					qos1,
 					qos2,
@@ -370,7 +373,7 @@ var _ = ginkgo.Describe("OVN EgressQoS Operations", func() {
 					Match:       match1,
 					Priority:    EgressQoSFlowStartPriority,
 					Action:      map[string]int{nbdb.QoSActionDSCP: 40},
-					ExternalIDs: map[string]string{"EgressQoS": namespaceT.Name},
+					ExternalIDs: getEgressQoSDbIDs(namespaceT.Name, EgressQoSFlowStartPriority, controllerName).GetExternalIDs(),
 					UUID:        "qos3-UUID",
 				}
 				node1Switch.QOSRules = []string{qos3.UUID}
@@ -470,7 +473,7 @@ var _ = ginkgo.Describe("OVN EgressQoS Operations", func() {
 				Match:       fmt.Sprintf("(ip4.dst == 1.2.3.4/32) && ip4.src == $%s", asv4),
 				Priority:    EgressQoSFlowStartPriority,
 				Action:      map[string]int{nbdb.QoSActionDSCP: 50},
-				ExternalIDs: map[string]string{"EgressQoS": namespaceT.Name},
+				ExternalIDs: getEgressQoSDbIDs(namespaceT.Name, EgressQoSFlowStartPriority, controllerName).GetExternalIDs(),
 				UUID:        "qos1-UUID",
 			}
 			qos2 := &nbdb.QoS{
@@ -478,7 +481,7 @@ var _ = ginkgo.Describe("OVN EgressQoS Operations", func() {
 				Match:       fmt.Sprintf("(ip4.dst == 5.6.7.8/32) && ip4.src == $%s", asv4),
 				Priority:    EgressQoSFlowStartPriority - 1,
 				Action:      map[string]int{nbdb.QoSActionDSCP: 60},
-				ExternalIDs: map[string]string{"EgressQoS": namespaceT.Name},
+				ExternalIDs: getEgressQoSDbIDs(namespaceT.Name, EgressQoSFlowStartPriority-1, controllerName).GetExternalIDs(),
 				UUID:        "qos2-UUID",
 			}
 			node1Switch.QOSRules = append(node1Switch.QOSRules, qos1.UUID, qos2.UUID)
@@ -604,7 +607,7 @@ var _ = ginkgo.Describe("OVN EgressQoS Operations", func() {
 				Match:       fmt.Sprintf("(ip4.dst == 1.2.3.4/32) && ip4.src == $%s", asv4),
 				Priority:    EgressQoSFlowStartPriority,
 				Action:      map[string]int{nbdb.QoSActionDSCP: 40},
-				ExternalIDs: map[string]string{"EgressQoS": namespaceT.Name},
+				ExternalIDs: getEgressQoSDbIDs(namespaceT.Name, EgressQoSFlowStartPriority, controllerName).GetExternalIDs(),
 				UUID:        "qos1-UUID",
 			}
 			qosAS := getEgressQosAddrSetDbIDs(namespaceT.Name, fmt.Sprintf("%d", EgressQoSFlowStartPriority-1), controllerName)
@@ -614,7 +617,7 @@ var _ = ginkgo.Describe("OVN EgressQoS Operations", func() {
 				Match:       fmt.Sprintf("(ip4.dst == 5.6.7.8/32) && ip4.src == $%s", qosASv4),
 				Priority:    EgressQoSFlowStartPriority - 1,
 				Action:      map[string]int{nbdb.QoSActionDSCP: 50},
-				ExternalIDs: map[string]string{"EgressQoS": namespaceT.Name},
+				ExternalIDs: getEgressQoSDbIDs(namespaceT.Name, EgressQoSFlowStartPriority-1, controllerName).GetExternalIDs(),
 				UUID:        "qos2-UUID",
 			}
 			qosAS = getEgressQosAddrSetDbIDs(namespaceT.Name, fmt.Sprintf("%d", EgressQoSFlowStartPriority-2), controllerName)
@@ -624,7 +627,7 @@ var _ = ginkgo.Describe("OVN EgressQoS Operations", func() {
 				Match:       fmt.Sprintf("(ip4.dst == 5.6.7.8/32) && ip4.src == $%s", qosASv4),
 				Priority:    EgressQoSFlowStartPriority - 2,
 				Action:      map[string]int{nbdb.QoSActionDSCP: 60},
-				ExternalIDs: map[string]string{"EgressQoS": namespaceT.Name},
+				ExternalIDs: getEgressQoSDbIDs(namespaceT.Name, EgressQoSFlowStartPriority-2, controllerName).GetExternalIDs(),
 				UUID:        "qos3-UUID",
 			}
 			node1Switch.QOSRules = append(node1Switch.QOSRules, qos1.UUID, qos2.UUID, qos3.UUID)
@@ -99,7 +99,7 @@ func (syncer *aclSyncer) SyncACLs(existingNodes *v1.NodeList) error {
 	updatedACLs = append(updatedACLs, egressFirewallACLs...)
 
 	// delete stale duplicating acls first
-	_, err = libovsdbops.TransactAndCheck(syncer.nbClient, deleteACLs)
+	_, err = libovsdbops.TransactAndCheckBatched(syncer.nbClient, deleteACLs)
 	if err != nil {
 		return fmt.Errorf("faile to trasact db ops: %v", err)
 	}
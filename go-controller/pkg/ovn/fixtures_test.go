@@ -0,0 +1,51 @@
+package ovn
+
+import (
+	libovsdbclient "github.com/ovn-org/libovsdb/client"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing/fixtures"
+	libovsdbtest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing/libovsdb"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+// fixtureController runs the default network controller of a FakeOVN for
+// declarative fixtures, watching the resources started by watch
+type fixtureController struct {
+	fakeOvn *FakeOVN
+	watch   func(*DefaultNetworkController) error
+}
+
+func (c *fixtureController) Start(objects []runtime.Object, nbData []libovsdbtest.TestData) (libovsdbclient.Client, error) {
+	c.fakeOvn = NewFakeOVN(false)
+	c.fakeOvn.startWithDBSetup(libovsdbtest.TestSetup{NBData: nbData}, objects...)
+	if err := c.watch(c.fakeOvn.controller); err != nil {
+		return nil, err
+	}
+	return c.fakeOvn.nbClient, nil
+}
+
+func (c *fixtureController) Stop() {
+	c.fakeOvn.shutdown()
+}
+
+var _ = ginkgo.Describe("OVN declarative fixtures", func() {
+	ginkgo.BeforeEach(func() {
+		err := config.PrepareTestConfig()
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		config.IPv4Mode = true
+	})
+
+	ginkgo.It("reconciles namespaces", func() {
+		fs, err := fixtures.LoadDir("testdata/fixtures/namespace")
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(fs).NotTo(gomega.BeEmpty())
+		for _, f := range fs {
+			err := f.Run(&fixtureController{watch: (*DefaultNetworkController).WatchNamespaces})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		}
+	})
+})
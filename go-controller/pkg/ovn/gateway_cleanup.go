@@ -74,6 +74,10 @@ func (oc *DefaultNetworkController) gatewayCleanup(nodeName string) error {
 		return fmt.Errorf("failed to delete external switch %s: %v", exGWexternalSwitch, err)
 	}
 
+	if err := deleteNodeCOPP(oc.nbClient, nodeName); err != nil {
+		return err
+	}
+
 	// This will cleanup the NodeSubnetPolicy in local and shared gateway modes. It will be a no-op for any other mode.
 	oc.delPbrAndNatRules(nodeName, nil)
 	return nil
@@ -219,6 +223,10 @@ func (oc *DefaultNetworkController) multiJoinSwitchGatewayCleanup(nodeName strin
 		return fmt.Errorf("failed to delete external switch %s: %v", extSwitchName, err)
 	}
 
+	if err := deleteNodeCOPP(oc.nbClient, nodeName); err != nil {
+		return err
+	}
+
 	// This will cleanup the NodeSubnetPolicy in local and shared gateway modes. It will be a no-op for any other mode.
 	oc.delPbrAndNatRules(nodeName, nil)
 	return nil
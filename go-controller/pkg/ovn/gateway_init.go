@@ -10,6 +10,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
 	utilnet "k8s.io/utils/net"
 
 	libovsdbclient "github.com/ovn-org/libovsdb/client"
@@ -25,6 +26,17 @@ import (
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 )
 
+const (
+	// tcpIPv4HeadersLen and tcpIPv6HeadersLen are the lengths of the IP and TCP
+	// headers without options, i.e. the difference between a path MTU and the
+	// TCP MSS it allows
+	tcpIPv4HeadersLen = 40
+	tcpIPv6HeadersLen = 60
+	// egressMSSBypassMatch exempts all but TCP traffic from the gateway_mtu
+	// check used to clamp the egress TCP MSS
+	egressMSSBypassMatch = "!tcp"
+)
+
 // cleanupStalePodSNATs removes SNATs against nodeIP for the given node if the SNAT.logicalIP isn't an active podIP on this node
 // We don't have to worry about missing SNATs that should be added because addLogicalPort takes care of this for all pods
 // when RequestRetryObjs is called for each node add.
@@ -102,9 +114,10 @@ func (oc *DefaultNetworkController) cleanupStalePodSNATs(nodeName string, nodeIP
 
 // gatewayInit creates a gateway router for the local chassis.
 // enableGatewayMTU enables options:gateway_mtu for gateway routers.
+// coppUUID is the control plane protection of the gateway router.
 func (oc *DefaultNetworkController) gatewayInit(nodeName string, clusterIPSubnet []*net.IPNet, hostSubnets []*net.IPNet,
 	l3GatewayConfig *util.L3GatewayConfig, sctpSupport bool, gwLRPIfAddrs, drLRPIfAddrs []*net.IPNet,
-	enableGatewayMTU bool) error {
+	enableGatewayMTU bool, coppUUID string) error {
 
 	gwLRPIPs := make([]net.IP, 0)
 	for _, gwLRPIfAddr := range gwLRPIfAddrs {
@@ -125,6 +138,10 @@ func (oc *DefaultNetworkController) gatewayInit(nodeName string, clusterIPSubnet
 		"lb_force_snat_ip":              "router_ip",
 		"snat-ct-zone":                  "0",
 	}
+	// the configured options are set on every update, so that they are not lost when ovnkube updates the router
+	for option, value := range config.Default.GatewayRouterOptions {
+		logicalRouterOptions[option] = value
+	}
 	logicalRouterExternalIDs := map[string]string{
 		"physical_ip":  physicalIPs[0],
 		"physical_ips": strings.Join(physicalIPs, ","),
@@ -134,7 +151,7 @@ func (oc *DefaultNetworkController) gatewayInit(nodeName string, clusterIPSubnet
 		Name:        gatewayRouter,
 		Options:     logicalRouterOptions,
 		ExternalIDs: logicalRouterExternalIDs,
-		Copp:        &oc.defaultCOPPUUID,
+		Copp:        &coppUUID,
 	}
 
 	if oc.clusterLoadBalancerGroupUUID != "" && oc.routerLoadBalancerGroupUUID != "" {
@@ -249,6 +266,18 @@ func (oc *DefaultNetworkController) gatewayInit(nodeName string, clusterIPSubnet
 		}
 	}
 
+	// OVN can't rewrite the TCP MSS option, so clamp the MSS of pod egress
+	// traffic by enforcing the matching path MTU on the external ports of the
+	// gateway router: oversized segments trigger ICMP needs-frag/packet-too-big
+	// errors generated by the GR itself, which reach the pods regardless of
+	// what happens upstream of the node SNAT.
+	egressMSS := config.Gateway.EgressMSS
+	if egressMSS > 0 && !enableGatewayMTU {
+		klog.Warningf("Egress TCP MSS clamping is configured but options:gateway_mtu is disabled on node %s, "+
+			"the TCP MSS of its pod egress traffic will not be clamped", nodeName)
+		egressMSS = 0
+	}
+
 	if err := oc.addExternalSwitch("",
 		l3GatewayConfig.InterfaceID,
 		nodeName,
@@ -256,7 +285,8 @@ func (oc *DefaultNetworkController) gatewayInit(nodeName string, clusterIPSubnet
 		l3GatewayConfig.MACAddress.String(),
 		types.PhysicalNetworkName,
 		l3GatewayConfig.IPAddresses,
-		l3GatewayConfig.VLANID); err != nil {
+		l3GatewayConfig.VLANID,
+		egressMSS); err != nil {
 		return err
 	}
 
@@ -268,7 +298,8 @@ func (oc *DefaultNetworkController) gatewayInit(nodeName string, clusterIPSubnet
 			l3GatewayConfig.EgressGWMACAddress.String(),
 			types.PhysicalNetworkExGwName,
 			l3GatewayConfig.EgressGWIPAddresses,
-			nil); err != nil {
+			nil,
+			egressMSS); err != nil {
 			return err
 		}
 	}
@@ -469,9 +500,24 @@ func (oc *DefaultNetworkController) gatewayInit(nodeName string, clusterIPSubnet
 	return nil
 }
 
+// egressMSSGatewayMTU returns the gateway_mtu that clamps the TCP MSS of the
+// traffic of a GR port with the given addresses to egressMSS. The TCP and IP
+// headers are 20 bytes longer with IPv6, but there is a single gateway_mtu per
+// port: on dual-stack ports, the IPv4 value is used and the MSS of IPv6
+// connections is clamped 20 bytes lower than egressMSS.
+func egressMSSGatewayMTU(egressMSS int, ipAddresses []*net.IPNet) int {
+	for _, ip := range ipAddresses {
+		if utilnet.IsIPv4CIDR(ip) {
+			return egressMSS + tcpIPv4HeadersLen
+		}
+	}
+	return egressMSS + tcpIPv6HeadersLen
+}
+
 // addExternalSwitch creates a switch connected to the external bridge and connects it to
-// the gateway router
-func (oc *DefaultNetworkController) addExternalSwitch(prefix, interfaceID, nodeName, gatewayRouter, macAddress, physNetworkName string, ipAddresses []*net.IPNet, vlanID *uint) error {
+// the gateway router. A non-zero egressMSS clamps the TCP MSS of the traffic leaving
+// through the GR port, see egressMSSGatewayMTU.
+func (oc *DefaultNetworkController) addExternalSwitch(prefix, interfaceID, nodeName, gatewayRouter, macAddress, physNetworkName string, ipAddresses []*net.IPNet, vlanID *uint, egressMSS int) error {
 	// Create the GR port that connects to external_switch with mac address of
 	// external interface and that IP address. In the case of `local` gateway
 	// mode, whenever ovnkube-node container restarts a new br-local bridge will
@@ -490,11 +536,18 @@ func (oc *DefaultNetworkController) addExternalSwitch(prefix, interfaceID, nodeN
 		Networks: externalRouterPortNetworks,
 		Name:     externalRouterPort,
 	}
+	if egressMSS > 0 {
+		externalLogicalRouterPort.Options = map[string]string{
+			"gateway_mtu":        strconv.Itoa(egressMSSGatewayMTU(egressMSS, ipAddresses)),
+			"gateway_mtu_bypass": egressMSSBypassMatch,
+		}
+	}
 	logicalRouter := nbdb.LogicalRouter{Name: gatewayRouter}
 
 	err := libovsdbops.CreateOrUpdateLogicalRouterPort(oc.nbClient, &logicalRouter,
 		&externalLogicalRouterPort, nil, &externalLogicalRouterPort.MAC,
-		&externalLogicalRouterPort.Networks, &externalLogicalRouterPort.ExternalIDs)
+		&externalLogicalRouterPort.Networks, &externalLogicalRouterPort.ExternalIDs,
+		&externalLogicalRouterPort.Options)
 	if err != nil {
 		return fmt.Errorf("failed to add logical router port %+v to router %s: %v", externalLogicalRouterPort, gatewayRouter, err)
 	}
@@ -184,6 +184,20 @@ func generateGatewayInitExpectedNB(testData []libovsdb.TestData, expectedOVNClus
 		networks = append(networks, ip.String())
 		physicalIPs = append(physicalIPs, ip.IP.String())
 	}
+	var externalRouterPortOptions map[string]string
+	if gatewayMTU != "" && config.Gateway.EgressMSS > 0 {
+		// a single gateway_mtu per port, the IPv4 one on dual-stack ports
+		headersLen := tcpIPv6HeadersLen
+		for _, ip := range l3GatewayConfig.IPAddresses {
+			if utilnet.IsIPv4CIDR(ip) {
+				headersLen = tcpIPv4HeadersLen
+			}
+		}
+		externalRouterPortOptions = map[string]string{
+			"gateway_mtu":        strconv.Itoa(config.Gateway.EgressMSS + headersLen),
+			"gateway_mtu_bypass": "!tcp",
+		}
+	}
 	testData = append(testData, &nbdb.LogicalRouterPort{
 		UUID: externalRouterPort + "-UUID",
 		Name: externalRouterPort,
@@ -192,6 +206,7 @@ func generateGatewayInitExpectedNB(testData []libovsdb.TestData, expectedOVNClus
 			"gateway-physical-ip": "yes",
 		},
 		Networks: networks,
+		Options:  externalRouterPortOptions,
 	})
 
 	natUUIDs := make([]string, 0, len(clusterIPSubnets))
@@ -226,13 +241,14 @@ func generateGatewayInitExpectedNB(testData []libovsdb.TestData, expectedOVNClus
 		types.OVNTCPRSTRateLimiter:           getMeterNameForProtocol(types.OVNTCPRSTRateLimiter),
 	}
 	fairness := true
-	for _, v := range meters {
+	for k, v := range meters {
 		testData = append(testData, &nbdb.Meter{
-			UUID:  v + "-UUID",
-			Bands: []string{"25-pktps-rate-limiter-UUID"},
-			Name:  v,
-			Unit:  types.PacketsPerSecond,
-			Fair:  &fairness,
+			UUID:        v + "-UUID",
+			Bands:       []string{"25-pktps-rate-limiter-UUID"},
+			Name:        v,
+			Unit:        types.PacketsPerSecond,
+			Fair:        &fairness,
+			ExternalIDs: getCOPPMeterDbIDs(defaultCOPPName, k).GetExternalIDs(),
 		})
 	}
 
@@ -243,16 +259,20 @@ func generateGatewayInitExpectedNB(testData []libovsdb.TestData, expectedOVNClus
 	}
 	testData = append(testData, copp)
 
+	grOptions := map[string]string{
+		"lb_force_snat_ip":              "router_ip",
+		"snat-ct-zone":                  "0",
+		"always_learn_from_arp_request": "false",
+		"dynamic_neigh_routers":         "true",
+		"chassis":                       l3GatewayConfig.ChassisID,
+	}
+	for option, value := range config.Default.GatewayRouterOptions {
+		grOptions[option] = value
+	}
 	testData = append(testData, &nbdb.LogicalRouter{
-		UUID: GRName + "-UUID",
-		Name: GRName,
-		Options: map[string]string{
-			"lb_force_snat_ip":              "router_ip",
-			"snat-ct-zone":                  "0",
-			"always_learn_from_arp_request": "false",
-			"dynamic_neigh_routers":         "true",
-			"chassis":                       l3GatewayConfig.ChassisID,
-		},
+		UUID:    GRName + "-UUID",
+		Name:    GRName,
+		Options: grOptions,
 		ExternalIDs: map[string]string{
 			"physical_ip":  physicalIPs[0],
 			"physical_ips": strings.Join(physicalIPs, ","),
@@ -425,7 +445,7 @@ var _ = ginkgo.Describe("Gateway Init Operations", func() {
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
 			err = fakeOvn.controller.gatewayInit(
-				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, sctpSupport, joinLRPIPs, defLRPIPs, true)
+				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, sctpSupport, joinLRPIPs, defLRPIPs, true, fakeOvn.controller.defaultCOPPUUID)
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
 			testData := []libovsdb.TestData{}
@@ -438,6 +458,17 @@ var _ = ginkgo.Describe("Gateway Init Operations", func() {
 				"1400")
 			gomega.Eventually(fakeOvn.nbClient).Should(libovsdbtest.HaveData(expectedDatabaseState))
 
+			// Clamp the egress MSS, sets option:gateway_mtu on the external GR LRP.
+			config.Gateway.EgressMSS = 1360
+			expectedOVNClusterRouter.StaticRoutes = []string{}
+			err = fakeOvn.controller.gatewayInit(
+				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, sctpSupport, joinLRPIPs, defLRPIPs, true, fakeOvn.controller.defaultCOPPUUID)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			expectedDatabaseState = generateGatewayInitExpectedNB(testData, expectedOVNClusterRouter, expectedNodeSwitch,
+				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, joinLRPIPs, defLRPIPs, skipSnat, mgmtPortIP,
+				"1400")
+			gomega.Eventually(fakeOvn.nbClient).Should(libovsdbtest.HaveData(expectedDatabaseState))
+
 			testData = []libovsdb.TestData{datapath}
 			expectedSBDatabaseState := generateGatewayInitExpectedSB(testData, nodeName)
 			gomega.Eventually(fakeOvn.sbClient).Should(libovsdbtest.HaveData(expectedSBDatabaseState))
@@ -513,7 +544,7 @@ var _ = ginkgo.Describe("Gateway Init Operations", func() {
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
 			err = fakeOvn.controller.gatewayInit(
-				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, sctpSupport, joinLRPIPs, defLRPIPs, true)
+				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, sctpSupport, joinLRPIPs, defLRPIPs, true, fakeOvn.controller.defaultCOPPUUID)
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
 			testData := []libovsdb.TestData{}
@@ -531,6 +562,89 @@ var _ = ginkgo.Describe("Gateway Init Operations", func() {
 			gomega.Eventually(fakeOvn.sbClient).Should(libovsdbtest.HaveData(expectedSBDatabaseState))
 		})
 
+		ginkgo.It("sets the configured gateway router options", func() {
+			var err error
+			config.Default.GatewayRouterOptions, err = config.ParseRouterOptions(
+				"always_learn_from_arp_request=true,mac_binding_age_threshold=300")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			expectedOVNClusterRouter := &nbdb.LogicalRouter{
+				UUID:         types.OVNClusterRouter + "-UUID",
+				Name:         types.OVNClusterRouter,
+				StaticRoutes: []string{},
+			}
+			expectedNodeSwitch := &nbdb.LogicalSwitch{
+				UUID: nodeName + "-UUID",
+				Name: nodeName,
+			}
+			expectedClusterLBGroup := &nbdb.LoadBalancerGroup{
+				UUID: types.ClusterLBGroupName + "-UUID",
+				Name: types.ClusterLBGroupName,
+			}
+			expectedSwitchLBGroup := &nbdb.LoadBalancerGroup{
+				UUID: types.ClusterSwitchLBGroupName + "-UUID",
+				Name: types.ClusterSwitchLBGroupName,
+			}
+			expectedRouterLBGroup := &nbdb.LoadBalancerGroup{
+				UUID: types.ClusterRouterLBGroupName + "-UUID",
+				Name: types.ClusterRouterLBGroupName,
+			}
+			gr := types.GWRouterPrefix + nodeName
+			datapath := &sbdb.DatapathBinding{
+				UUID:        gr + "-UUID",
+				ExternalIDs: map[string]string{"logical-router": gr + "-UUID", "name": gr},
+			}
+			fakeOvn.startWithDBSetup(libovsdbtest.TestSetup{
+				NBData: []libovsdbtest.TestData{
+					&nbdb.LogicalSwitch{
+						UUID: types.OVNJoinSwitch + "-UUID",
+						Name: types.OVNJoinSwitch,
+					},
+					expectedOVNClusterRouter,
+					expectedNodeSwitch,
+					expectedClusterLBGroup,
+					expectedSwitchLBGroup,
+					expectedRouterLBGroup,
+				},
+				SBData: []libovsdbtest.TestData{
+					datapath,
+				},
+			})
+
+			clusterIPSubnets := ovntest.MustParseIPNets("10.128.0.0/14")
+			hostSubnets := ovntest.MustParseIPNets("10.130.0.0/23")
+			joinLRPIPs := ovntest.MustParseIPNets("100.64.0.3/16")
+			defLRPIPs := ovntest.MustParseIPNets("100.64.0.1/16")
+			l3GatewayConfig := &util.L3GatewayConfig{
+				Mode:           config.GatewayModeLocal,
+				ChassisID:      "SYSTEM-ID",
+				InterfaceID:    "INTERFACE-ID",
+				MACAddress:     ovntest.MustParseMAC("11:22:33:44:55:66"),
+				IPAddresses:    ovntest.MustParseIPNets("169.254.33.2/24"),
+				NodePortEnable: true,
+			}
+			sctpSupport := false
+
+			fakeOvn.controller.defaultCOPPUUID, err = EnsureDefaultCOPP(fakeOvn.nbClient)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			// the options are kept when the gateway is initialized again
+			for i := 0; i < 2; i++ {
+				err = fakeOvn.controller.gatewayInit(
+					nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, sctpSupport, joinLRPIPs, defLRPIPs, true, fakeOvn.controller.defaultCOPPUUID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			}
+
+			testData := []libovsdb.TestData{}
+			skipSnat := false
+			// We don't set up the Allow from mgmt port ACL here
+			mgmtPortIP := ""
+			expectedDatabaseState := generateGatewayInitExpectedNB(testData, expectedOVNClusterRouter, expectedNodeSwitch,
+				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, joinLRPIPs, defLRPIPs, skipSnat, mgmtPortIP,
+				"1400")
+			gomega.Eventually(fakeOvn.nbClient).Should(libovsdbtest.HaveData(expectedDatabaseState))
+		})
+
 		ginkgo.It("updates options:gateway_mtu for GR LRP", func() {
 			expectedOVNClusterRouter := &nbdb.LogicalRouter{
 				UUID:         types.OVNClusterRouter + "-UUID",
@@ -602,7 +716,7 @@ var _ = ginkgo.Describe("Gateway Init Operations", func() {
 
 			// Disable option:gateway_mtu.
 			err = fakeOvn.controller.gatewayInit(
-				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, sctpSupport, joinLRPIPs, defLRPIPs, false)
+				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, sctpSupport, joinLRPIPs, defLRPIPs, false, fakeOvn.controller.defaultCOPPUUID)
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
 			expectedDatabaseState := generateGatewayInitExpectedNB(testData, expectedOVNClusterRouter, expectedNodeSwitch,
@@ -613,7 +727,7 @@ var _ = ginkgo.Describe("Gateway Init Operations", func() {
 			// Enable option:gateway_mtu.
 			expectedOVNClusterRouter.StaticRoutes = []string{}
 			err = fakeOvn.controller.gatewayInit(
-				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, sctpSupport, joinLRPIPs, defLRPIPs, true)
+				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, sctpSupport, joinLRPIPs, defLRPIPs, true, fakeOvn.controller.defaultCOPPUUID)
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 			expectedDatabaseState = generateGatewayInitExpectedNB(testData, expectedOVNClusterRouter, expectedNodeSwitch,
 				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, joinLRPIPs, defLRPIPs, skipSnat, mgmtPortIP,
@@ -690,7 +804,7 @@ var _ = ginkgo.Describe("Gateway Init Operations", func() {
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
 			err = fakeOvn.controller.gatewayInit(
-				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, sctpSupport, joinLRPIPs, defLRPIPs, true)
+				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, sctpSupport, joinLRPIPs, defLRPIPs, true, fakeOvn.controller.defaultCOPPUUID)
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
 			testData := []libovsdb.TestData{}
@@ -702,6 +816,18 @@ var _ = ginkgo.Describe("Gateway Init Operations", func() {
 				"1400")
 			gomega.Eventually(fakeOvn.nbClient).Should(libovsdbtest.HaveData(expectedDatabaseState))
 
+			// Clamp the egress MSS, sets option:gateway_mtu to the MSS plus the
+			// IPv6 and TCP headers on the external GR LRP.
+			config.Gateway.EgressMSS = 1340
+			expectedOVNClusterRouter.StaticRoutes = []string{}
+			err = fakeOvn.controller.gatewayInit(
+				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, sctpSupport, joinLRPIPs, defLRPIPs, true, fakeOvn.controller.defaultCOPPUUID)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			expectedDatabaseState = generateGatewayInitExpectedNB(testData, expectedOVNClusterRouter, expectedNodeSwitch,
+				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, joinLRPIPs, defLRPIPs, skipSnat, mgmtPortIP,
+				"1400")
+			gomega.Eventually(fakeOvn.nbClient).Should(libovsdbtest.HaveData(expectedDatabaseState))
+
 			testData = []libovsdb.TestData{datapath}
 			expectedSBDatabaseState := generateGatewayInitExpectedSB(testData, nodeName)
 			gomega.Eventually(fakeOvn.sbClient).Should(libovsdbtest.HaveData(expectedSBDatabaseState))
@@ -772,7 +898,7 @@ var _ = ginkgo.Describe("Gateway Init Operations", func() {
 			config.IPv4Mode = false
 			config.IPv6Mode = true
 			err = fakeOvn.controller.gatewayInit(
-				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, sctpSupport, joinLRPIPs, defLRPIPs, true)
+				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, sctpSupport, joinLRPIPs, defLRPIPs, true, fakeOvn.controller.defaultCOPPUUID)
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
 			testData := []libovsdb.TestData{}
@@ -855,7 +981,7 @@ var _ = ginkgo.Describe("Gateway Init Operations", func() {
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
 			err = fakeOvn.controller.gatewayInit(
-				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, sctpSupport, joinLRPIPs, defLRPIPs, true)
+				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, sctpSupport, joinLRPIPs, defLRPIPs, true, fakeOvn.controller.defaultCOPPUUID)
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
 			testData := []libovsdb.TestData{}
@@ -937,7 +1063,7 @@ var _ = ginkgo.Describe("Gateway Init Operations", func() {
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
 			err = fakeOvn.controller.gatewayInit(
-				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, sctpSupport, joinLRPIPs, defLRPIPs, true)
+				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, sctpSupport, joinLRPIPs, defLRPIPs, true, fakeOvn.controller.defaultCOPPUUID)
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
 			testData := []libovsdb.TestData{}
@@ -1051,7 +1177,7 @@ var _ = ginkgo.Describe("Gateway Init Operations", func() {
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
 			err = fakeOvn.controller.gatewayInit(
-				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, sctpSupport, joinLRPIPs, defLRPIPs, true)
+				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, sctpSupport, joinLRPIPs, defLRPIPs, true, fakeOvn.controller.defaultCOPPUUID)
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
 			testData := []libovsdb.TestData{}
@@ -1162,7 +1288,7 @@ var _ = ginkgo.Describe("Gateway Init Operations", func() {
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
 			err = fakeOvn.controller.gatewayInit(
-				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, sctpSupport, joinLRPIPs, defLRPIPs, true)
+				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, sctpSupport, joinLRPIPs, defLRPIPs, true, fakeOvn.controller.defaultCOPPUUID)
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
 			testData := []libovsdb.TestData{}
@@ -1253,7 +1379,7 @@ var _ = ginkgo.Describe("Gateway Init Operations", func() {
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
 			err = fakeOvn.controller.gatewayInit(
-				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, sctpSupport, joinLRPIPs, defLRPIPs, true)
+				nodeName, clusterIPSubnets, hostSubnets, l3GatewayConfig, sctpSupport, joinLRPIPs, defLRPIPs, true, fakeOvn.controller.defaultCOPPUUID)
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
 			testData := []libovsdb.TestData{}
@@ -8,6 +8,7 @@ import (
 	"reflect"
 	"sync"
 
+	iputils "github.com/containernetworking/plugins/pkg/ip"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/ipallocator"
 	ipam "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/ipallocator"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/ipallocator/allocator"
@@ -235,6 +236,44 @@ func (manager *LogicalSwitchManager) AllocateIPs(switchName string, ipnets []*ne
 	return nil
 }
 
+// ExcludeSubnets blocks off the IPs of the excluded subnets as already allocated for a
+// given switch. Only the IPs within the allocatable range of the switch subnets are
+// reserved, which for large IPv6 subnets is just the start of the subnet, so excluding
+// a large IPv6 subnet does not require walking all of its addresses.
+func (manager *LogicalSwitchManager) ExcludeSubnets(switchName string, excludeSubnets []*net.IPNet) error {
+	manager.RLock()
+	defer manager.RUnlock()
+	lsi, ok := manager.cache[switchName]
+	if !ok {
+		return fmt.Errorf("unable to exclude subnets: %v for switch %s: %w", excludeSubnets, switchName, SwitchNotFound)
+	} else if len(lsi.ipams) == 0 {
+		return fmt.Errorf("unable to exclude subnets: %v for switch: %s: logical switch manager has no IPAM",
+			excludeSubnets, switchName)
+	}
+
+	for _, excludeSubnet := range excludeSubnets {
+		for _, ipam := range lsi.ipams {
+			cidr := ipam.CIDR()
+			excludeIP := excludeSubnet.IP
+			if !cidr.Contains(excludeIP) {
+				if !excludeSubnet.Contains(cidr.IP) {
+					continue
+				}
+				excludeIP = cidr.IP
+			}
+			for ; cidr.Contains(excludeIP) && excludeSubnet.Contains(excludeIP); excludeIP = iputils.NextIP(excludeIP) {
+				err := ipam.Allocate(excludeIP)
+				var notInRange *ipallocator.ErrNotInRange
+				if errors.As(err, &notInRange) && !excludeIP.Equal(cidr.IP) {
+					// past the end of the allocatable range
+					break
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // AllocateNextIPs allocates IP addresses from each of the host subnets
 // for a given switch
 func (manager *LogicalSwitchManager) AllocateNextIPs(switchName string) ([]*net.IPNet, error) {
@@ -392,6 +392,39 @@ var _ = ginkgo.Describe("OVN Logical Switch Manager operations", func() {
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 		})
 
+		ginkgo.It("excludes dual-stack subnets, including large IPv6 ones, from the layer2 allocations", func() {
+			app.Action = func(ctx *cli.Context) error {
+				_, err := config.InitConfig(ctx, fexec, nil)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				lsManager = NewL2SwitchManager()
+				testNode := testNodeSubnetData{
+					switchName: "testNode1",
+					subnets: []string{
+						"10.1.1.0/24",
+						"2000::/48",
+					},
+				}
+				err = lsManager.AddSwitch(testNode.switchName, "", ovntest.MustParseIPNets(testNode.subnets...))
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				// the second IPv6 exclusion is far larger than, and outside of, the allocatable
+				// range of the subnet
+				err = lsManager.ExcludeSubnets(testNode.switchName,
+					ovntest.MustParseIPNets("10.1.1.0/29", "2000::/113", "2000:0:0:1::/64"))
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(lsManager.isAllocatedIP(testNode.switchName, "10.1.1.7")).To(gomega.BeTrue())
+				gomega.Expect(lsManager.isAllocatedIP(testNode.switchName, "2000::7fff")).To(gomega.BeTrue())
+
+				ips, err := lsManager.AllocateNextIPs(testNode.switchName)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(ips).To(gomega.HaveLen(2))
+				gomega.Expect(ips[0].IP.String()).To(gomega.Equal("10.1.1.8"))
+				gomega.Expect(ips[1].IP.String()).To(gomega.Equal("2000::8000"))
+				return nil
+			}
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
 	})
 
 })
@@ -312,8 +312,13 @@ func (oc *DefaultNetworkController) syncGatewayLogicalNetwork(node *kapi.Node, l
 
 	enableGatewayMTU := util.ParseNodeGatewayMTUSupport(node)
 
+	coppUUID, err := oc.ensureNodeCOPP(node)
+	if err != nil {
+		return fmt.Errorf("failed to ensure the control plane protection of node %s: %v", node.Name, err)
+	}
+
 	err = oc.gatewayInit(node.Name, clusterSubnets, hostSubnets, l3GatewayConfig, oc.SCTPSupport, gwLRPIPs, oc.ovnClusterLRPToJoinIfAddrs,
-		enableGatewayMTU)
+		enableGatewayMTU, coppUUID)
 	if err != nil {
 		return fmt.Errorf("failed to init shared interface gateway: %v", err)
 	}
@@ -374,7 +379,11 @@ func (oc *DefaultNetworkController) ensureNodeLogicalNetwork(node *kapi.Node, ho
 		return err
 	}
 
-	return oc.createNodeLogicalSwitch(node.Name, hostSubnets, oc.clusterLoadBalancerGroupUUID, oc.switchLoadBalancerGroupUUID)
+	if err := oc.createNodeLogicalSwitch(node.Name, hostSubnets, oc.clusterLoadBalancerGroupUUID, oc.switchLoadBalancerGroupUUID); err != nil {
+		return err
+	}
+
+	return oc.addNamespaceEgressBandwidthToSwitch(node.Name)
 }
 
 func (oc *DefaultNetworkController) addNode(node *kapi.Node) ([]*net.IPNet, error) {
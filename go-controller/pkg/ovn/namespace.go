@@ -140,6 +140,9 @@ func (oc *DefaultNetworkController) configureNamespace(nsInfo *namespaceInfo, ns
 	if err := oc.configureNamespaceCommon(nsInfo, ns); err != nil {
 		errors = append(errors, err)
 	}
+	if err := oc.updateNamespaceEgressBandwidth(ns, nsInfo); err != nil {
+		errors = append(errors, err)
+	}
 	return kerrors.NewAggregate(errors)
 }
 
@@ -253,6 +256,13 @@ func (oc *DefaultNetworkController) updateNamespace(old, newer *kapi.Namespace)
 	if err := oc.multicastUpdateNamespace(newer, nsInfo); err != nil {
 		errors = append(errors, err)
 	}
+
+	if newer.Annotations[util.NsEgressBandwidthAnnotation] != old.Annotations[util.NsEgressBandwidthAnnotation] ||
+		newer.Annotations[util.NsEgressBurstAnnotation] != old.Annotations[util.NsEgressBurstAnnotation] {
+		if err := oc.updateNamespaceEgressBandwidth(newer, nsInfo); err != nil {
+			errors = append(errors, err)
+		}
+	}
 	return kerrors.NewAggregate(errors)
 }
 
@@ -271,6 +281,28 @@ func (oc *DefaultNetworkController) deleteNamespace(ns *kapi.Namespace) error {
 	if err := oc.multicastDeleteNamespace(ns, nsInfo); err != nil {
 		return fmt.Errorf("failed to delete multicast namespace error %v", err)
 	}
+	if err := oc.deleteNamespaceEgressBandwidth(ns.Name); err != nil {
+		return fmt.Errorf("failed to delete egress bandwidth limit of namespace %s: %v", ns.Name, err)
+	}
+	return nil
+}
+
+// syncNamespaces cleans up the stale namespace address sets, as well as the egress bandwidth limits of the
+// namespaces that are gone or no longer limited
+func (oc *DefaultNetworkController) syncNamespaces(namespaces []interface{}) error {
+	if err := oc.BaseNetworkController.syncNamespaces(namespaces); err != nil {
+		return err
+	}
+	nsWithEgressBandwidth := make(map[string]bool)
+	for _, nsInterface := range namespaces {
+		ns := nsInterface.(*kapi.Namespace)
+		if bandwidth, err := parseNamespaceEgressBandwidth(ns.Annotations); err == nil && bandwidth != nil {
+			nsWithEgressBandwidth[ns.Name] = true
+		}
+	}
+	if err := oc.syncNamespaceEgressBandwidth(nsWithEgressBandwidth); err != nil {
+		return fmt.Errorf("error in syncing egress bandwidth for namespaces: %v", err)
+	}
 	return nil
 }
 
@@ -0,0 +1,218 @@
+package ovn
+
+import (
+	"fmt"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	libovsdbops "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/libovsdbops"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	"github.com/ovn-org/libovsdb/ovsdb"
+	kapi "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+)
+
+// namespaceEgressBandwidth is the egress bandwidth limit of a namespace, in the units of the bandwidth column of
+// the OVN QoS table
+type namespaceEgressBandwidth struct {
+	// rate in kbps
+	rate int
+	// burst in kilobits, 0 lets OVN pick the default burst
+	burst int
+}
+
+// parseNamespaceEgressBandwidth returns the egress bandwidth limit set by the k8s.ovn.org/egress-bandwidth and
+// k8s.ovn.org/egress-burst annotations, or nil if the namespace is not limited. Both annotations are quantities
+// in bits, like the kubernetes.io/egress-bandwidth pod annotation, e.g. "10M".
+func parseNamespaceEgressBandwidth(annotations map[string]string) (*namespaceEgressBandwidth, error) {
+	rateAnnotation, ok := annotations[util.NsEgressBandwidthAnnotation]
+	if !ok {
+		if _, ok := annotations[util.NsEgressBurstAnnotation]; ok {
+			return nil, fmt.Errorf("%s annotation requires the %s annotation", util.NsEgressBurstAnnotation,
+				util.NsEgressBandwidthAnnotation)
+		}
+		return nil, nil
+	}
+	rate, err := parseBitsAnnotation(util.NsEgressBandwidthAnnotation, rateAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	bandwidth := &namespaceEgressBandwidth{rate: rate}
+	if burstAnnotation, ok := annotations[util.NsEgressBurstAnnotation]; ok {
+		if bandwidth.burst, err = parseBitsAnnotation(util.NsEgressBurstAnnotation, burstAnnotation); err != nil {
+			return nil, err
+		}
+	}
+	return bandwidth, nil
+}
+
+// parseBitsAnnotation parses a quantity of bits into kilobits
+func parseBitsAnnotation(name, value string) (int, error) {
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s annotation %q: %v", name, value, err)
+	}
+	kbits := quantity.Value() / 1000
+	if kbits < 1 || kbits > int64(^uint32(0)) {
+		return 0, fmt.Errorf("invalid %s annotation %q: must be between 1k and 4T", name, value)
+	}
+	return int(kbits), nil
+}
+
+// updateNamespaceEgressBandwidth creates, updates or deletes the QoS rule metering the egress traffic of the pods
+// of the namespace, according to its annotations. The rule is added to every local zone node switch, so the limit
+// applies to the aggregate egress traffic of the namespace pods per node.
+// must be called with nsInfo lock
+func (oc *DefaultNetworkController) updateNamespaceEgressBandwidth(ns *kapi.Namespace, nsInfo *namespaceInfo) error {
+	bandwidth, err := parseNamespaceEgressBandwidth(ns.Annotations)
+	if err != nil {
+		// retrying won't help, leave the namespace unlimited until the annotations are fixed
+		klog.Warningf("Namespace %s: egress bandwidth is not limited, malformed annotations: %v", ns.Name, err)
+	}
+	if bandwidth == nil {
+		return oc.deleteNamespaceEgressBandwidth(ns.Name)
+	}
+
+	hashedIPv4, hashedIPv6 := nsInfo.addressSet.GetASHashNames()
+	qos := &nbdb.QoS{
+		Direction:   nbdb.QoSDirectionFromLport,
+		Match:       getNamespaceEgressBandwidthMatch(hashedIPv4, hashedIPv6),
+		Priority:    types.NamespaceEgressBandwidthPriority,
+		Bandwidth:   map[string]int{nbdb.QoSBandwidthRate: bandwidth.rate},
+		ExternalIDs: getNamespaceEgressBandwidthDbIDs(ns.Name, oc.controllerName).GetExternalIDs(),
+	}
+	if bandwidth.burst > 0 {
+		qos.Bandwidth[nbdb.QoSBandwidthBurst] = bandwidth.burst
+	}
+
+	ops, err := libovsdbops.CreateOrUpdateQoSesOps(oc.nbClient, nil, qos)
+	if err != nil {
+		return err
+	}
+	switches, err := oc.namespaceEgressBandwidthSwitches()
+	if err != nil {
+		return err
+	}
+	for _, sw := range switches {
+		ops, err = libovsdbops.AddQoSesToLogicalSwitchOps(oc.nbClient, ops, sw, qos)
+		if err != nil {
+			return err
+		}
+	}
+	if _, err := libovsdbops.TransactAndCheck(oc.nbClient, ops); err != nil {
+		return fmt.Errorf("failed to limit egress bandwidth of namespace %s: %v", ns.Name, err)
+	}
+	klog.Infof("Namespace %s: egress bandwidth is limited to %d kbps per node", ns.Name, bandwidth.rate)
+	return nil
+}
+
+func getNamespaceEgressBandwidthDbIDs(namespace, controller string) *libovsdbops.DbObjectIDs {
+	return libovsdbops.NewDbObjectIDs(libovsdbops.QoSNamespaceEgressBandwidth, controller,
+		map[libovsdbops.ExternalIDKey]string{
+			libovsdbops.ObjectNameKey: namespace,
+		})
+}
+
+func getNamespaceEgressBandwidthMatch(hashedIPv4, hashedIPv6 string) string {
+	switch {
+	case config.IPv4Mode && config.IPv6Mode:
+		return fmt.Sprintf("(ip4.src == $%s || ip6.src == $%s)", hashedIPv4, hashedIPv6)
+	case config.IPv6Mode:
+		return fmt.Sprintf("ip6.src == $%s", hashedIPv6)
+	default:
+		return fmt.Sprintf("ip4.src == $%s", hashedIPv4)
+	}
+}
+
+// deleteNamespaceEgressBandwidth deletes the egress bandwidth limit of the namespace, if any
+func (oc *DefaultNetworkController) deleteNamespaceEgressBandwidth(namespace string) error {
+	return oc.deleteNamespaceEgressBandwidthQoSes(getNamespaceEgressBandwidthDbIDs(namespace, oc.controllerName), nil)
+}
+
+// syncNamespaceEgressBandwidth deletes the egress bandwidth limits of the namespaces that are not in the given set
+func (oc *DefaultNetworkController) syncNamespaceEgressBandwidth(nsWithEgressBandwidth map[string]bool) error {
+	predicateIDs := libovsdbops.NewDbObjectIDs(libovsdbops.QoSNamespaceEgressBandwidth, oc.controllerName, nil)
+	return oc.deleteNamespaceEgressBandwidthQoSes(predicateIDs, func(q *nbdb.QoS) bool {
+		return !nsWithEgressBandwidth[q.ExternalIDs[libovsdbops.ObjectNameKey.String()]]
+	})
+}
+
+func (oc *DefaultNetworkController) deleteNamespaceEgressBandwidthQoSes(predicateIDs *libovsdbops.DbObjectIDs,
+	p libovsdbops.QoSPredicate) error {
+	qoses, err := libovsdbops.FindQoSesWithDbIDs(oc.nbClient, predicateIDs, p)
+	if err != nil {
+		return err
+	}
+	if len(qoses) == 0 {
+		return nil
+	}
+
+	uuids := sets.New[string]()
+	for _, qos := range qoses {
+		uuids.Insert(qos.UUID)
+	}
+	// look up the switches referencing the rules rather than the local zone node switches, the zone of a node
+	// might have changed since the rules were added
+	switches, err := libovsdbops.FindLogicalSwitchesWithPredicate(oc.nbClient, func(item *nbdb.LogicalSwitch) bool {
+		return uuids.HasAny(item.QOSRules...)
+	})
+	if err != nil {
+		return err
+	}
+
+	var ops []ovsdb.Operation
+	for _, sw := range switches {
+		ops, err = libovsdbops.RemoveQoSesFromLogicalSwitchOps(oc.nbClient, ops, sw.Name, qoses...)
+		if err != nil {
+			return err
+		}
+	}
+	ops, err = libovsdbops.DeleteQoSesOps(oc.nbClient, ops, qoses...)
+	if err != nil {
+		return err
+	}
+	if _, err := libovsdbops.TransactAndCheck(oc.nbClient, ops); err != nil {
+		return fmt.Errorf("failed to delete namespace egress bandwidth QoSes: %v", err)
+	}
+	return nil
+}
+
+// addNamespaceEgressBandwidthToSwitch adds the existing namespace egress bandwidth limits to a new node switch
+func (oc *DefaultNetworkController) addNamespaceEgressBandwidthToSwitch(switchName string) error {
+	predicateIDs := libovsdbops.NewDbObjectIDs(libovsdbops.QoSNamespaceEgressBandwidth, oc.controllerName, nil)
+	qoses, err := libovsdbops.FindQoSesWithDbIDs(oc.nbClient, predicateIDs, nil)
+	if err != nil {
+		return err
+	}
+	if len(qoses) == 0 {
+		return nil
+	}
+	ops, err := libovsdbops.AddQoSesToLogicalSwitchOps(oc.nbClient, nil, switchName, qoses...)
+	if err != nil {
+		return err
+	}
+	if _, err := libovsdbops.TransactAndCheck(oc.nbClient, ops); err != nil {
+		return fmt.Errorf("failed to add namespace egress bandwidth QoSes to switch %s: %v", switchName, err)
+	}
+	return nil
+}
+
+// namespaceEgressBandwidthSwitches returns the existing local zone node switches
+func (oc *DefaultNetworkController) namespaceEgressBandwidthSwitches() ([]string, error) {
+	switches, err := libovsdbops.FindLogicalSwitchesWithPredicate(oc.nbClient, func(item *nbdb.LogicalSwitch) bool {
+		_, local := oc.localZoneNodes.Load(item.Name)
+		return local
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch local node switches: %v", err)
+	}
+	names := make([]string, 0, len(switches))
+	for _, sw := range switches {
+		names = append(names, sw.Name)
+	}
+	return names, nil
+}
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/libovsdbops"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
 	addressset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/address_set"
 
@@ -358,5 +359,128 @@ var _ = ginkgo.Describe("OVN Namespace Operations", func() {
 				return fakeOvn.asf.AddressSetExists(namespaceName)
 			}, 21*time.Second).Should(gomega.BeFalse())
 		})
+
+		ginkgo.It("limits the egress bandwidth of an annotated namespace on the node switches", func() {
+			const nodeName = "node1"
+			nodeSwitch := &nbdb.LogicalSwitch{
+				UUID: nodeName + "-UUID",
+				Name: nodeName,
+			}
+			namespace := newNamespace(namespaceName)
+			namespace.Annotations[util.NsEgressBandwidthAnnotation] = "10M"
+			fakeOvn.startWithDBSetup(libovsdbtest.TestSetup{NBData: []libovsdbtest.TestData{nodeSwitch}},
+				&v1.NamespaceList{
+					Items: []v1.Namespace{*namespace},
+				},
+			)
+			fakeOvn.controller.localZoneNodes.Store(nodeName, true)
+			err := fakeOvn.controller.WatchNamespaces()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			hashedIPv4, _ := getNsAddrSetHashNames(namespaceName)
+			qos := &nbdb.QoS{
+				UUID:        "qos-UUID",
+				Direction:   nbdb.QoSDirectionFromLport,
+				Match:       "ip4.src == $" + hashedIPv4,
+				Priority:    ovntypes.NamespaceEgressBandwidthPriority,
+				Bandwidth:   map[string]int{nbdb.QoSBandwidthRate: 10000},
+				ExternalIDs: getNamespaceEgressBandwidthDbIDs(namespaceName, DefaultNetworkControllerName).GetExternalIDs(),
+			}
+			nodeSwitch.QOSRules = []string{qos.UUID}
+			gomega.Eventually(fakeOvn.nbClient).Should(libovsdbtest.HaveData([]libovsdbtest.TestData{qos, nodeSwitch}))
+
+			ginkgo.By("updating the rate and burst of the namespace")
+			namespace.Annotations[util.NsEgressBandwidthAnnotation] = "20M"
+			namespace.Annotations[util.NsEgressBurstAnnotation] = "1M"
+			_, err = fakeOvn.fakeClient.KubeClient.CoreV1().Namespaces().Update(context.TODO(), namespace, metav1.UpdateOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			qos.Bandwidth = map[string]int{nbdb.QoSBandwidthRate: 20000, nbdb.QoSBandwidthBurst: 1000}
+			gomega.Eventually(fakeOvn.nbClient).Should(libovsdbtest.HaveData([]libovsdbtest.TestData{qos, nodeSwitch}))
+
+			ginkgo.By("adding the limit to a new node switch")
+			node2Switch := &nbdb.LogicalSwitch{
+				UUID: "node2-UUID",
+				Name: "node2",
+			}
+			err = libovsdbops.CreateOrUpdateLogicalSwitch(fakeOvn.nbClient, node2Switch)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			err = fakeOvn.controller.addNamespaceEgressBandwidthToSwitch(node2Switch.Name)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			node2Switch.QOSRules = []string{qos.UUID}
+			gomega.Expect(fakeOvn.nbClient).To(libovsdbtest.HaveData([]libovsdbtest.TestData{qos, nodeSwitch, node2Switch}))
+
+			ginkgo.By("removing the annotations of the namespace")
+			delete(namespace.Annotations, util.NsEgressBandwidthAnnotation)
+			delete(namespace.Annotations, util.NsEgressBurstAnnotation)
+			_, err = fakeOvn.fakeClient.KubeClient.CoreV1().Namespaces().Update(context.TODO(), namespace, metav1.UpdateOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			nodeSwitch.QOSRules = nil
+			node2Switch.QOSRules = nil
+			gomega.Eventually(fakeOvn.nbClient).Should(libovsdbtest.HaveData([]libovsdbtest.TestData{nodeSwitch, node2Switch}))
+		})
+
+		ginkgo.It("removes the stale egress bandwidth limits on startup", func() {
+			staleQoS := &nbdb.QoS{
+				UUID:        "stale-qos-UUID",
+				Direction:   nbdb.QoSDirectionFromLport,
+				Match:       "ip4.src == $a123",
+				Priority:    ovntypes.NamespaceEgressBandwidthPriority,
+				Bandwidth:   map[string]int{nbdb.QoSBandwidthRate: 10000},
+				ExternalIDs: getNamespaceEgressBandwidthDbIDs(namespaceName, DefaultNetworkControllerName).GetExternalIDs(),
+			}
+			egressQoS := &nbdb.QoS{
+				UUID:        "egress-qos-UUID",
+				Direction:   nbdb.QoSDirectionToLport,
+				Match:       "ip4.src == $a456",
+				Priority:    EgressQoSFlowStartPriority,
+				Action:      map[string]int{nbdb.QoSActionDSCP: 50},
+				ExternalIDs: getEgressQoSDbIDs(namespaceName, EgressQoSFlowStartPriority, DefaultNetworkControllerName).GetExternalIDs(),
+			}
+			nodeSwitch := &nbdb.LogicalSwitch{
+				UUID:     "node1-UUID",
+				Name:     "node1",
+				QOSRules: []string{staleQoS.UUID, egressQoS.UUID},
+			}
+			fakeOvn.startWithDBSetup(libovsdbtest.TestSetup{NBData: []libovsdbtest.TestData{staleQoS, egressQoS, nodeSwitch}},
+				&v1.NamespaceList{
+					Items: []v1.Namespace{*newNamespace(namespaceName)},
+				},
+			)
+			err := fakeOvn.controller.WatchNamespaces()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			nodeSwitch.QOSRules = []string{egressQoS.UUID}
+			gomega.Expect(fakeOvn.nbClient).To(libovsdbtest.HaveData([]libovsdbtest.TestData{egressQoS, nodeSwitch}))
+		})
+
+		ginkgo.It("does not limit the egress bandwidth of a namespace with malformed annotations", func() {
+			nodeSwitch := &nbdb.LogicalSwitch{
+				UUID: "node1-UUID",
+				Name: "node1",
+			}
+			namespace := newNamespace(namespaceName)
+			namespace.Annotations[util.NsEgressBandwidthAnnotation] = "fast"
+			fakeOvn.startWithDBSetup(libovsdbtest.TestSetup{NBData: []libovsdbtest.TestData{nodeSwitch}},
+				&v1.NamespaceList{
+					Items: []v1.Namespace{*namespace},
+				},
+			)
+			fakeOvn.controller.localZoneNodes.Store(nodeSwitch.Name, true)
+			err := fakeOvn.controller.WatchNamespaces()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			fakeOvn.asf.ExpectEmptyAddressSet(namespaceName)
+			gomega.Expect(fakeOvn.nbClient).To(libovsdbtest.HaveData([]libovsdbtest.TestData{nodeSwitch}))
+
+			for _, annotations := range []map[string]string{
+				{util.NsEgressBandwidthAnnotation: "fast"},
+				{util.NsEgressBandwidthAnnotation: "100"},
+				{util.NsEgressBandwidthAnnotation: "10M", util.NsEgressBurstAnnotation: "-1M"},
+				{util.NsEgressBurstAnnotation: "1M"},
+			} {
+				bandwidth, err := parseNamespaceEgressBandwidth(annotations)
+				gomega.Expect(err).To(gomega.HaveOccurred(), "annotations %v", annotations)
+				gomega.Expect(bandwidth).To(gomega.BeNil())
+			}
+		})
 	})
 })
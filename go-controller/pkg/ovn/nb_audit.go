@@ -0,0 +1,219 @@
+package ovn
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	libovsdbclient "github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/libovsdbops"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/metrics"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/auditor"
+	ovntypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	knet "k8s.io/api/networking/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// nbAuditDebugPath is the path of the NB audit handler under /debug/ on the metrics server
+const nbAuditDebugPath = "nbaudit"
+
+// names of the NB audit checks of the default network controller
+const (
+	podLogicalSwitchPortsAuditName = "pod-logical-switch-ports"
+	namespaceAddressSetsAuditName  = "namespace-address-sets"
+	serviceLoadBalancersAuditName  = "service-load-balancers"
+	networkPolicyACLsAuditName     = "network-policy-acls"
+)
+
+// startNBAudit serves the NB audits of the default network controller on demand and, if configured, starts the
+// periodic audit. Objects that are being added or deleted while an audit runs are reported as drift by that audit,
+// so only drift that persists across audits is meaningful.
+func (oc *DefaultNetworkController) startNBAudit() {
+	nbAuditor := auditor.New(map[string]auditor.Check{
+		podLogicalSwitchPortsAuditName: oc.auditPodLogicalSwitchPorts,
+		namespaceAddressSetsAuditName:  oc.auditNamespaceAddressSets,
+		serviceLoadBalancersAuditName:  oc.auditServiceLoadBalancers,
+		networkPolicyACLsAuditName:     oc.auditNetworkPolicyACLs,
+	}, config.Metrics.NBAuditLogDiff, func(diff *auditor.Diff) {
+		metrics.RecordNBDBDrift(diff.Check, len(diff.Missing), len(diff.Stale), len(diff.Modified))
+	})
+	metrics.RegisterDebugHandler(nbAuditDebugPath, nbAuditor)
+
+	if config.Metrics.NBAuditInterval == 0 {
+		return
+	}
+	oc.wg.Add(1)
+	go func() {
+		defer oc.wg.Done()
+		nbAuditor.Run(time.Duration(config.Metrics.NBAuditInterval)*time.Second, oc.stopChan)
+	}()
+}
+
+// auditPodLogicalSwitchPorts compares the logical switch ports of the pods running on the local zone nodes, keyed by
+// port name, with the addresses found in their pod annotation
+func (oc *DefaultNetworkController) auditPodLogicalSwitchPorts() (map[string]string, map[string]string, error) {
+	nodes, err := oc.GetLocalZoneNodes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	actual := map[string]string{}
+	localNodes := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		if oc.lsManager.IsNonHostSubnetSwitch(node.Name) {
+			continue
+		}
+		localNodes[node.Name] = true
+		lsps, err := libovsdbops.FindLogicalSwitchPortsOnSwitchWithPredicate(oc.nbClient,
+			&nbdb.LogicalSwitch{Name: node.Name},
+			func(item *nbdb.LogicalSwitchPort) bool { return item.ExternalIDs["pod"] == "true" })
+		if err != nil {
+			if errors.Is(err, libovsdbclient.ErrNotFound) {
+				continue
+			}
+			return nil, nil, err
+		}
+		for _, lsp := range lsps {
+			var address string
+			if len(lsp.Addresses) > 0 {
+				address = lsp.Addresses[0]
+			}
+			actual[lsp.Name] = logicalSwitchPortAddressFingerprint(strings.Fields(address))
+		}
+	}
+
+	pods, err := oc.watchFactory.GetAllPods()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get pods: %v", err)
+	}
+	expected := map[string]string{}
+	for _, pod := range pods {
+		if !localNodes[pod.Spec.NodeName] || util.PodWantsHostNetwork(pod) || util.PodCompleted(pod) {
+			continue
+		}
+		podAnnotation, err := util.UnmarshalPodAnnotation(pod.Annotations, ovntypes.DefaultNetworkName)
+		if err != nil {
+			// the pod is not set up yet, its port is not expected
+			continue
+		}
+		fields := []string{podAnnotation.MAC.String()}
+		for _, ip := range podAnnotation.IPs {
+			fields = append(fields, ip.IP.String())
+		}
+		expected[util.GetLogicalPortName(pod.Namespace, pod.Name)] = logicalSwitchPortAddressFingerprint(fields)
+	}
+	return expected, actual, nil
+}
+
+// logicalSwitchPortAddressFingerprint returns the "<mac> <ip>..." address of a logical switch port with the IPs
+// sorted, so that equal addresses have the same fingerprint whatever the order of their IPs
+func logicalSwitchPortAddressFingerprint(fields []string) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	ips := make([]string, 0, len(fields)-1)
+	for _, field := range fields[1:] {
+		if ip := net.ParseIP(field); ip != nil {
+			ips = append(ips, ip.String())
+		}
+	}
+	sort.Strings(ips)
+	return strings.Join(append([]string{strings.ToLower(fields[0])}, ips...), " ")
+}
+
+// auditNamespaceAddressSets compares the namespaces with their address sets, keyed by namespace name. Only the
+// existence of the address sets is audited.
+func (oc *DefaultNetworkController) auditNamespaceAddressSets() (map[string]string, map[string]string, error) {
+	predicateIDs := libovsdbops.NewDbObjectIDs(libovsdbops.AddressSetNamespace, oc.controllerName, nil)
+	addressSets, err := libovsdbops.FindAddressSetsWithDbIDs(oc.nbClient, predicateIDs, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find namespace address sets: %v", err)
+	}
+	actual := make(map[string]string, len(addressSets))
+	for _, addressSet := range addressSets {
+		actual[addressSet.ExternalIDs[libovsdbops.ObjectNameKey.String()]] = ""
+	}
+
+	namespaces, err := oc.watchFactory.GetNamespaces()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get namespaces: %v", err)
+	}
+	expected := make(map[string]string, len(namespaces))
+	for _, namespace := range namespaces {
+		expected[namespace.Name] = ""
+	}
+	return expected, actual, nil
+}
+
+// auditServiceLoadBalancers compares the services that have a cluster IP with their load balancers, keyed by
+// service namespace/name. Only the existence of the load balancers is audited.
+func (oc *DefaultNetworkController) auditServiceLoadBalancers() (map[string]string, map[string]string, error) {
+	lbs, err := libovsdbops.ListLoadBalancers(oc.nbClient)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list load balancers: %v", err)
+	}
+	actual := map[string]string{}
+	for _, lb := range lbs {
+		if lb.ExternalIDs[ovntypes.LoadBalancerKindExternalID] != "Service" {
+			continue
+		}
+		actual[lb.ExternalIDs[ovntypes.LoadBalancerOwnerExternalID]] = ""
+	}
+
+	services, err := oc.watchFactory.GetServices()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get services: %v", err)
+	}
+	expected := make(map[string]string, len(services))
+	for _, service := range services {
+		if !util.ServiceTypeHasClusterIP(service) || !util.IsClusterIPSet(service) || len(service.Spec.Ports) == 0 {
+			continue
+		}
+		key, err := cache.MetaNamespaceKeyFunc(service)
+		if err != nil {
+			return nil, nil, err
+		}
+		expected[key] = ""
+	}
+	return expected, actual, nil
+}
+
+// auditNetworkPolicyACLs compares the network policies that have rules with their ACLs, keyed by policy
+// namespace:name. Only the existence of the ACLs is audited.
+func (oc *DefaultNetworkController) auditNetworkPolicyACLs() (map[string]string, map[string]string, error) {
+	predicateIDs := libovsdbops.NewDbObjectIDs(libovsdbops.ACLNetworkPolicy, oc.controllerName, nil)
+	acls, err := libovsdbops.FindACLsWithDbIDs(oc.nbClient, predicateIDs, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot find NetworkPolicy ACLs: %v", err)
+	}
+	actual := make(map[string]string, len(acls))
+	for _, acl := range acls {
+		actual[acl.ExternalIDs[libovsdbops.ObjectNameKey.String()]] = ""
+	}
+
+	policies, err := oc.watchFactory.GetNetworkPolicies()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get network policies: %v", err)
+	}
+	expected := make(map[string]string, len(policies))
+	for _, policy := range policies {
+		if networkPolicyHasRules(policy) {
+			expected[getACLPolicyKey(policy.Namespace, policy.Name)] = ""
+		}
+	}
+	return expected, actual, nil
+}
+
+// networkPolicyHasRules returns true if the policy has ingress or egress rules, each rule being implemented by at
+// least one ACL. Policies without rules are only implemented by the namespace default deny ACLs.
+func networkPolicyHasRules(policy *knet.NetworkPolicy) bool {
+	policyTypeIngress, policyTypeEgress := getPolicyType(policy)
+	return policyTypeIngress && len(policy.Spec.Ingress) > 0 || policyTypeEgress && len(policy.Spec.Egress) > 0
+}
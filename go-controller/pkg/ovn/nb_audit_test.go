@@ -0,0 +1,138 @@
+package ovn
+
+import (
+	"net"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/auditor"
+	ovntest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing"
+	libovsdbtest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing/libovsdb"
+	ovntypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+var _ = ginkgo.Describe("OVN NB audit", func() {
+	const (
+		namespaceName = "namespace1"
+		nodeName      = "node1"
+	)
+	var (
+		app     *cli.App
+		fakeOvn *FakeOVN
+	)
+
+	ginkgo.BeforeEach(func() {
+		// Restore global default values before each testcase
+		config.PrepareTestConfig()
+
+		app = cli.NewApp()
+		app.Name = "test"
+		app.Flags = config.Flags
+
+		fakeOvn = NewFakeOVN(true)
+	})
+
+	ginkgo.AfterEach(func() {
+		fakeOvn.shutdown()
+	})
+
+	newAnnotatedPod := func(name, podIP string) *v1.Pod {
+		pod := newPod(namespaceName, name, nodeName, podIP)
+		annotations, err := util.MarshalPodAnnotation(map[string]string{}, &util.PodAnnotation{
+			IPs: []*net.IPNet{ovntest.MustParseIPNet(podIP + "/24")},
+			MAC: util.IPAddrToHWAddr(ovntest.MustParseIP(podIP)),
+		}, ovntypes.DefaultNetworkName)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		pod.Annotations = annotations
+		return pod
+	}
+
+	newPodLSP := func(name, podIP string) *nbdb.LogicalSwitchPort {
+		return &nbdb.LogicalSwitchPort{
+			UUID:        name + "-UUID",
+			Name:        util.GetLogicalPortName(namespaceName, name),
+			Addresses:   []string{util.IPAddrToHWAddr(ovntest.MustParseIP(podIP)).String() + " " + podIP},
+			ExternalIDs: map[string]string{"namespace": namespaceName, "pod": "true"},
+		}
+	}
+
+	ginkgo.It("reports missing, stale and modified pod logical switch ports", func() {
+		app.Action = func(ctx *cli.Context) error {
+			pod1LSP := newPodLSP("pod1", "10.128.1.3")
+			// pod2's port has an address that was changed externally
+			pod2LSP := newPodLSP("pod2", "10.128.1.10")
+			// the port of a deleted pod
+			staleLSP := newPodLSP("oldpod", "10.128.1.5")
+			nodeSwitch := &nbdb.LogicalSwitch{
+				UUID:  nodeName + "-UUID",
+				Name:  nodeName,
+				Ports: []string{pod1LSP.UUID, pod2LSP.UUID, staleLSP.UUID},
+			}
+			fakeOvn.startWithDBSetup(
+				libovsdbtest.TestSetup{
+					NBData: []libovsdbtest.TestData{pod1LSP, pod2LSP, staleLSP, nodeSwitch},
+				},
+				&v1.NamespaceList{
+					Items: []v1.Namespace{*newNamespace(namespaceName)},
+				},
+				&v1.NodeList{
+					Items: []v1.Node{*newNode(nodeName, "192.168.126.202/24")},
+				},
+				&v1.PodList{
+					Items: []v1.Pod{
+						*newAnnotatedPod("pod1", "10.128.1.3"),
+						*newAnnotatedPod("pod2", "10.128.1.4"),
+						// pod3's port was deleted externally
+						*newAnnotatedPod("pod3", "10.128.1.6"),
+					},
+				},
+			)
+
+			expected, actual, err := fakeOvn.controller.auditPodLogicalSwitchPorts()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(auditor.Compare(podLogicalSwitchPortsAuditName, expected, actual)).To(gomega.Equal(&auditor.Diff{
+				Check:    podLogicalSwitchPortsAuditName,
+				Missing:  []string{util.GetLogicalPortName(namespaceName, "pod3")},
+				Stale:    []string{staleLSP.Name},
+				Modified: []string{pod2LSP.Name},
+			}))
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("reports missing and stale namespace address sets", func() {
+		app.Action = func(ctx *cli.Context) error {
+			staleAddressSet, _ := buildNamespaceAddressSets("oldnamespace", nil)
+			fakeOvn.startWithDBSetup(
+				libovsdbtest.TestSetup{
+					NBData: []libovsdbtest.TestData{staleAddressSet},
+				},
+				&v1.NamespaceList{
+					Items: []v1.Namespace{*newNamespace(namespaceName)},
+				},
+			)
+
+			expected, actual, err := fakeOvn.controller.auditNamespaceAddressSets()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(auditor.Compare(namespaceAddressSetsAuditName, expected, actual)).To(gomega.Equal(&auditor.Diff{
+				Check:   namespaceAddressSetsAuditName,
+				Missing: []string{namespaceName},
+				Stale:   []string{"oldnamespace"},
+			}))
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+})
@@ -38,6 +38,7 @@ func (bnc *BaseNetworkController) newNetpolRetryFramework(
 	resourceHandler := &retry.ResourceHandler{
 		HasUpdateFunc:          hasResourceAnUpdateFunc(objectType),
 		NeedsUpdateDuringRetry: needsUpdateDuringRetry(objectType),
+		MaxFailedAttempts:      maxFailedAttempts(objectType),
 		ObjType:                objectType,
 		EventHandler:           eventHandler,
 	}
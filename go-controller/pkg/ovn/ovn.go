@@ -12,6 +12,7 @@ import (
 	libovsdbclient "github.com/ovn-org/libovsdb/client"
 
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	egressfirewall "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/metrics"
 	addressset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/address_set"
 	egresssvc "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/controller/egress_services"
@@ -21,6 +22,7 @@ import (
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 
 	kapi "k8s.io/api/core/v1"
+	knet "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
@@ -43,6 +45,9 @@ const (
 type ACLLoggingLevels struct {
 	Allow string `json:"allow,omitempty"`
 	Deny  string `json:"deny,omitempty"`
+	// Meter is the meter that rate limits the logged packets, the cluster-wide
+	// ACL logging meter is used when unset
+	Meter string `json:"-"`
 }
 
 const (
@@ -61,18 +66,24 @@ func getPodNamespacedName(pod *kapi.Pod) string {
 	return util.GetLogicalPortName(pod.Namespace, pod.Name)
 }
 
-// syncPeriodic adds a goroutine that periodically does some work
-// right now there is only one ticker registered
-// for syncNodesPeriodic which deletes chassis records from the sbdb
-// every 5 minutes
+// syncPeriodic adds a goroutine that periodically does some work:
+// syncNodesPeriodic deletes chassis records from the sbdb every 5 minutes and
+// deleteDuplicateLogicalSwitchPorts deletes duplicate pod logical switch ports
+// every 10 minutes
 func (oc *DefaultNetworkController) syncPeriodic() {
 	go func() {
 		nodeSyncTicker := time.NewTicker(5 * time.Minute)
 		defer nodeSyncTicker.Stop()
+		duplicatePortsTicker := time.NewTicker(10 * time.Minute)
+		defer duplicatePortsTicker.Stop()
 		for {
 			select {
 			case <-nodeSyncTicker.C:
 				oc.syncNodesPeriodic()
+			case <-duplicatePortsTicker.C:
+				if err := oc.deleteDuplicateLogicalSwitchPorts(); err != nil {
+					klog.Errorf("Failed to delete duplicate logical switch ports: %v", err)
+				}
 			case <-oc.stopChan:
 				return
 			}
@@ -120,6 +131,28 @@ func (oc *DefaultNetworkController) recordNodeEvent(reason string, addErr error,
 	}
 }
 
+func (oc *DefaultNetworkController) recordNetworkPolicyEvent(reason string, addErr error, policy *knet.NetworkPolicy) {
+	policyRef := kapi.ObjectReference{
+		Kind:      "NetworkPolicy",
+		Namespace: policy.Namespace,
+		Name:      policy.Name,
+		UID:       policy.UID,
+	}
+	klog.V(5).Infof("Posting a %s event for NetworkPolicy %s/%s", kapi.EventTypeWarning, policy.Namespace, policy.Name)
+	oc.recorder.Eventf(&policyRef, kapi.EventTypeWarning, reason, addErr.Error())
+}
+
+func (oc *DefaultNetworkController) recordEgressFirewallEvent(reason string, addErr error, egressFirewall *egressfirewall.EgressFirewall) {
+	egressFirewallRef := kapi.ObjectReference{
+		Kind:      "EgressFirewall",
+		Namespace: egressFirewall.Namespace,
+		Name:      egressFirewall.Name,
+		UID:       egressFirewall.UID,
+	}
+	klog.V(5).Infof("Posting a %s event for EgressFirewall %s/%s", kapi.EventTypeWarning, egressFirewall.Namespace, egressFirewall.Name)
+	oc.recorder.Eventf(&egressFirewallRef, kapi.EventTypeWarning, reason, addErr.Error())
+}
+
 func exGatewayAnnotationsChanged(oldPod, newPod *kapi.Pod) bool {
 	return oldPod.Annotations[util.RoutingNamespaceAnnotation] != newPod.Annotations[util.RoutingNamespaceAnnotation] ||
 		oldPod.Annotations[util.RoutingNetworkAnnotation] != newPod.Annotations[util.RoutingNetworkAnnotation] ||
@@ -397,6 +430,11 @@ func nodeGatewayMTUSupportChanged(oldNode, node *kapi.Node) bool {
 	return util.ParseNodeGatewayMTUSupport(oldNode) != util.ParseNodeGatewayMTUSupport(node)
 }
 
+// nodeCOPPRatesChanged returns true if annotation "k8s.ovn.org/copp-rates" on the node was updated.
+func nodeCOPPRatesChanged(oldNode, node *kapi.Node) bool {
+	return oldNode.Annotations[util.OvnNodeCOPPRates] != node.Annotations[util.OvnNodeCOPPRates]
+}
+
 // shouldUpdateNode() determines if the ovn-kubernetes plugin should update the state of the node.
 // ovn-kube should not perform an update if it does not assign a hostsubnet, or if you want to change
 // whether or not ovn-kubernetes assigns a hostsubnet
@@ -497,7 +535,7 @@ func (oc *DefaultNetworkController) InitEgressServiceController() (*egresssvc.Co
 			return isReachableLegacy(nodeName, mgmtIPs, timeout)
 		}
 
-		return isReachableViaGRPC(mgmtIPs, healthClient, hcPort, timeout)
+		return isReachableViaGRPC(mgmtIPs, healthClient, hcPort, time.Duration(timeout)*time.Second)
 	}
 
 	return egresssvc.NewController(DefaultNetworkControllerName, oc.client, oc.nbClient, oc.addressSetFactory,
@@ -15,6 +15,8 @@ import (
 	libovsdbclient "github.com/ovn-org/libovsdb/client"
 	ovncnitypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/cni/types"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	anpapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1alpha1"
+	anpfake "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1alpha1/apis/clientset/versioned/fake"
 	egressfirewall "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1"
 	egressfirewallfake "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1/apis/clientset/versioned/fake"
 	egressip "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1"
@@ -23,6 +25,8 @@ import (
 	egressqosfake "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1/apis/clientset/versioned/fake"
 	egressservice "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressservice/v1"
 	egressservicefake "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressservice/v1/apis/clientset/versioned/fake"
+	ipamclaimsapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/ipamclaims/v1alpha1"
+	ipamclaimsfake "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/ipamclaims/v1alpha1/apis/clientset/versioned/fake"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/kube"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/metrics"
@@ -52,7 +56,9 @@ const (
 
 type secondaryControllerInfo struct {
 	bnc *BaseSecondaryNetworkController
-	asf *addressset.FakeAddressSetFactory
+	// set for layer2 and localnet networks only
+	bl2nc *BaseSecondaryLayer2NetworkController
+	asf   *addressset.FakeAddressSetFactory
 }
 
 type FakeOVN struct {
@@ -69,6 +75,7 @@ type FakeOVN struct {
 	nbsbCleanup  *libovsdbtest.Cleanup
 	egressQoSWg  *sync.WaitGroup
 	egressSVCWg  *sync.WaitGroup
+	anpWg        *sync.WaitGroup
 
 	// information map of all secondary network controllers
 	secondaryControllers map[string]secondaryControllerInfo
@@ -85,6 +92,7 @@ func NewFakeOVN(useFakeAddressSet bool) *FakeOVN {
 		fakeRecorder: record.NewFakeRecorder(10),
 		egressQoSWg:  &sync.WaitGroup{},
 		egressSVCWg:  &sync.WaitGroup{},
+		anpWg:        &sync.WaitGroup{},
 
 		secondaryControllers: map[string]secondaryControllerInfo{},
 	}
@@ -100,6 +108,8 @@ func (o *FakeOVN) start(objects ...runtime.Object) {
 	egressQoSObjects := []runtime.Object{}
 	multiNetworkPolicyObjects := []runtime.Object{}
 	egressServiceObjects := []runtime.Object{}
+	anpObjects := []runtime.Object{}
+	ipamClaimObjects := []runtime.Object{}
 	v1Objects := []runtime.Object{}
 	nads := []*nettypes.NetworkAttachmentDefinition{}
 	for _, object := range objects {
@@ -117,6 +127,12 @@ func (o *FakeOVN) start(objects ...runtime.Object) {
 			}
 		} else if _, isEgressServiceObject := object.(*egressservice.EgressServiceList); isEgressServiceObject {
 			egressServiceObjects = append(egressServiceObjects, object)
+		} else if _, isANPObject := object.(*anpapi.AdminNetworkPolicyList); isANPObject {
+			anpObjects = append(anpObjects, object)
+		} else if _, isBANPObject := object.(*anpapi.BaselineAdminNetworkPolicyList); isBANPObject {
+			anpObjects = append(anpObjects, object)
+		} else if _, isIPAMClaimObject := object.(*ipamclaimsapi.IPAMClaimList); isIPAMClaimObject {
+			ipamClaimObjects = append(ipamClaimObjects, object)
 		} else {
 			v1Objects = append(v1Objects, object)
 		}
@@ -128,6 +144,8 @@ func (o *FakeOVN) start(objects ...runtime.Object) {
 		EgressQoSClient:          egressqosfake.NewSimpleClientset(egressQoSObjects...),
 		MultiNetworkPolicyClient: mnpfake.NewSimpleClientset(multiNetworkPolicyObjects...),
 		EgressServiceClient:      egressservicefake.NewSimpleClientset(egressServiceObjects...),
+		ANPClient:                anpfake.NewSimpleClientset(anpObjects...),
+		IPAMClaimsClient:         ipamclaimsfake.NewSimpleClientset(ipamClaimObjects...),
 	}
 	o.init(nads)
 }
@@ -143,6 +161,7 @@ func (o *FakeOVN) shutdown() {
 	o.wg.Wait()
 	o.egressQoSWg.Wait()
 	o.egressSVCWg.Wait()
+	o.anpWg.Wait()
 	o.nbsbCleanup.Cleanup()
 }
 
@@ -227,6 +246,7 @@ func NewOvnController(ovnClient *util.OVNMasterClientset, wf *factory.WatchFacto
 			EgressFirewallClient: ovnClient.EgressFirewallClient,
 			CloudNetworkClient:   ovnClient.CloudNetworkClient,
 			EgressServiceClient:  ovnClient.EgressServiceClient,
+			IPAMClaimsClient:     ovnClient.IPAMClaimsClient,
 		},
 		wf,
 		recorder,
@@ -302,6 +322,7 @@ func newNetworkAttachmentDefinition(namespace, name string, netconf ovncnitypes.
 func (o *FakeOVN) NewSecondaryNetworkController(netattachdef *nettypes.NetworkAttachmentDefinition) error {
 	var ocInfo secondaryControllerInfo
 	var secondaryController *BaseSecondaryNetworkController
+	var secondaryLayer2Controller *BaseSecondaryLayer2NetworkController
 	var ok bool
 
 	nadName := util.GetNADName(netattachdef.Namespace, netattachdef.Name)
@@ -332,15 +353,16 @@ func (o *FakeOVN) NewSecondaryNetworkController(netattachdef *nettypes.NetworkAt
 				EIPClient:            o.fakeClient.EgressIPClient,
 				EgressFirewallClient: o.fakeClient.EgressFirewallClient,
 				CloudNetworkClient:   o.fakeClient.CloudNetworkClient,
+				IPAMClaimsClient:     o.fakeClient.IPAMClaimsClient,
 			},
 			o.watcher,
 			o.fakeRecorder,
 			o.nbClient,
 			o.sbClient,
 			&podRecorder,
-			false, // sctp support
-			false, // multicast support
-			true,  // templates support
+			false,                  // sctp support
+			config.EnableMulticast, // multicast support
+			true,                   // templates support
 		)
 		if err != nil {
 			return err
@@ -356,14 +378,16 @@ func (o *FakeOVN) NewSecondaryNetworkController(netattachdef *nettypes.NetworkAt
 			l2Controller := NewSecondaryLayer2NetworkController(cnci, nInfo)
 			l2Controller.addressSetFactory = asf
 			secondaryController = &l2Controller.BaseSecondaryNetworkController
+			secondaryLayer2Controller = &l2Controller.BaseSecondaryLayer2NetworkController
 		case types.LocalnetTopology:
 			localnetController := NewSecondaryLocalnetNetworkController(cnci, nInfo)
 			localnetController.addressSetFactory = asf
 			secondaryController = &localnetController.BaseSecondaryNetworkController
+			secondaryLayer2Controller = &localnetController.BaseSecondaryLayer2NetworkController
 		default:
 			return fmt.Errorf("topoloty type %s not supported", topoType)
 		}
-		ocInfo = secondaryControllerInfo{bnc: secondaryController, asf: asf}
+		ocInfo = secondaryControllerInfo{bnc: secondaryController, bl2nc: secondaryLayer2Controller, asf: asf}
 		o.secondaryControllers[netName] = ocInfo
 
 		if nbZoneFailed {
@@ -0,0 +1,145 @@
+package ovn
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	libovsdbclient "github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/libovsdbops"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/podinspector"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// podInspectorDebugPath is the path of the pod inspection handler under /debug/ on the metrics server
+const podInspectorDebugPath = "pod"
+
+// inspectPod returns the northbound objects of the given pod on the default network, found from the IPs of its
+// logical switch port. Only the NB cache is read, so the objects are the ones programmed so far, whatever the state
+// of the pod in the informer cache.
+func (oc *DefaultNetworkController) inspectPod(namespace, name string) (*podinspector.PodObjects, error) {
+	lsp, err := libovsdbops.GetLogicalSwitchPort(oc.nbClient,
+		&nbdb.LogicalSwitchPort{Name: util.GetLogicalPortName(namespace, name)})
+	if errors.Is(err, libovsdbclient.ErrNotFound) {
+		return nil, podinspector.ErrPodNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the logical switch port of pod %s/%s: %v", namespace, name, err)
+	}
+	_, podIPs, err := util.ExtractPortAddresses(lsp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the addresses of pod %s/%s: %v", namespace, name, err)
+	}
+	ips := sets.New[string]()
+	for _, ip := range podIPs {
+		ips.Insert(ip.String())
+	}
+
+	objects := &podinspector.PodObjects{
+		LogicalSwitchPort: lsp.Name,
+		Addresses:         lsp.Addresses,
+	}
+	switches, err := libovsdbops.FindLogicalSwitchesWithPredicate(oc.nbClient, func(item *nbdb.LogicalSwitch) bool {
+		return sets.New(item.Ports...).Has(lsp.UUID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the logical switch of pod %s/%s: %v", namespace, name, err)
+	}
+	if len(switches) > 0 {
+		objects.LogicalSwitch = switches[0].Name
+	}
+
+	addressSets, err := libovsdbops.FindAddressSetsWithPredicate(oc.nbClient, func(item *nbdb.AddressSet) bool {
+		return ips.HasAny(item.Addresses...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the address sets of pod %s/%s: %v", namespace, name, err)
+	}
+	addressSetRefs := sets.New[string]()
+	for _, addressSet := range addressSets {
+		objects.AddressSets = append(objects.AddressSets, addressSet.Name)
+		addressSetRefs.Insert("$" + addressSet.Name)
+	}
+
+	portGroups, err := libovsdbops.FindPortGroupsWithPredicate(oc.nbClient, func(item *nbdb.PortGroup) bool {
+		return sets.New(item.Ports...).Has(lsp.UUID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the port groups of pod %s/%s: %v", namespace, name, err)
+	}
+	portGroupACLs := sets.New[string]()
+	for _, portGroup := range portGroups {
+		objects.PortGroups = append(objects.PortGroups, portGroup.Name)
+		portGroupACLs.Insert(portGroup.ACLs...)
+	}
+
+	acls, err := libovsdbops.FindACLsWithPredicate(oc.nbClient, func(item *nbdb.ACL) bool {
+		return portGroupACLs.Has(item.UUID) || addressSetRefs.HasAny(matchTokens(item.Match)...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the ACLs of pod %s/%s: %v", namespace, name, err)
+	}
+	for _, acl := range acls {
+		var aclName string
+		if acl.Name != nil {
+			aclName = *acl.Name
+		}
+		objects.ACLs = append(objects.ACLs, fmt.Sprintf("%s: %s", aclName, acl.Match))
+	}
+
+	lbs, err := libovsdbops.ListLoadBalancers(oc.nbClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list load balancers: %v", err)
+	}
+	for _, lb := range lbs {
+		for vip, backends := range lb.Vips {
+			for _, backend := range strings.Split(backends, ",") {
+				if host, _, err := net.SplitHostPort(backend); err == nil && ips.Has(host) {
+					objects.LoadBalancers = append(objects.LoadBalancers, fmt.Sprintf("%s: %s -> %s", lb.Name, vip, backend))
+				}
+			}
+		}
+	}
+
+	routers, err := libovsdbops.FindLogicalRoutersWithPredicate(oc.nbClient, func(item *nbdb.LogicalRouter) bool {
+		return len(item.Policies) > 0
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find logical routers: %v", err)
+	}
+	policyRouters := map[string]string{}
+	for _, router := range routers {
+		for _, policyUUID := range router.Policies {
+			policyRouters[policyUUID] = router.Name
+		}
+	}
+	policies, err := libovsdbops.FindLogicalRouterPoliciesWithPredicate(oc.nbClient,
+		func(item *nbdb.LogicalRouterPolicy) bool {
+			return ips.HasAny(matchTokens(item.Match)...) || ips.HasAny(item.Nexthops...)
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the logical router policies of pod %s/%s: %v", namespace, name, err)
+	}
+	for _, policy := range policies {
+		objects.RouterPolicies = append(objects.RouterPolicies,
+			fmt.Sprintf("%s %d: %s", policyRouters[policy.UUID], policy.Priority, policy.Match))
+	}
+
+	for _, list := range [][]string{objects.AddressSets, objects.PortGroups, objects.ACLs, objects.LoadBalancers,
+		objects.RouterPolicies} {
+		sort.Strings(list)
+	}
+	return objects, nil
+}
+
+// matchTokens splits an OVN match into its operands, e.g. the IPs and the address set and port group references
+func matchTokens(match string) []string {
+	return strings.FieldsFunc(match, func(r rune) bool {
+		return strings.ContainsRune(" \t()!=<>&|{},\"", r)
+	})
+}
@@ -0,0 +1,118 @@
+package ovn
+
+import (
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/podinspector"
+	libovsdbtest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing/libovsdb"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+)
+
+var _ = ginkgo.Describe("OVN pod inspection", func() {
+	const (
+		namespaceName = "namespace1"
+		nodeName      = "node1"
+		podIP         = "10.128.1.3"
+	)
+	var (
+		app     *cli.App
+		fakeOvn *FakeOVN
+	)
+
+	ginkgo.BeforeEach(func() {
+		// Restore global default values before each testcase
+		config.PrepareTestConfig()
+
+		app = cli.NewApp()
+		app.Name = "test"
+		app.Flags = config.Flags
+
+		fakeOvn = NewFakeOVN(true)
+	})
+
+	ginkgo.AfterEach(func() {
+		fakeOvn.shutdown()
+	})
+
+	ginkgo.It("lists the northbound objects referencing the pod", func() {
+		app.Action = func(ctx *cli.Context) error {
+			podLSP := &nbdb.LogicalSwitchPort{
+				UUID:      "pod1-UUID",
+				Name:      util.GetLogicalPortName(namespaceName, "pod1"),
+				Addresses: []string{"0a:58:0a:80:01:03 " + podIP},
+			}
+			otherLSP := &nbdb.LogicalSwitchPort{
+				UUID:      "pod2-UUID",
+				Name:      util.GetLogicalPortName(namespaceName, "pod2"),
+				Addresses: []string{"0a:58:0a:80:01:0a 10.128.1.30"},
+			}
+			podAddressSet := &nbdb.AddressSet{UUID: "as1-UUID", Name: "a1", Addresses: []string{podIP}}
+			// 10.128.1.30 is not a pod IP
+			otherAddressSet := &nbdb.AddressSet{UUID: "as2-UUID", Name: "a2", Addresses: []string{"10.128.1.30"}}
+			portGroupACL := &nbdb.ACL{UUID: "acl1-UUID", Name: &[]string{"pg-acl"}[0], Match: "ip4"}
+			addressSetACL := &nbdb.ACL{UUID: "acl2-UUID", Name: &[]string{"as-acl"}[0], Match: "ip4.src == $a1"}
+			otherACL := &nbdb.ACL{UUID: "acl3-UUID", Name: &[]string{"other-acl"}[0], Match: "ip4.src == $a2"}
+			policy := &nbdb.LogicalRouterPolicy{
+				UUID:     "policy-UUID",
+				Priority: 100,
+				Match:    "ip4.src == " + podIP,
+				Action:   nbdb.LogicalRouterPolicyActionReroute,
+				Nexthops: []string{"100.64.0.2"},
+			}
+			otherPolicy := &nbdb.LogicalRouterPolicy{
+				UUID:     "other-policy-UUID",
+				Priority: 100,
+				Match:    "ip4.src == 10.128.1.30",
+				Action:   nbdb.LogicalRouterPolicyActionAllow,
+			}
+			fakeOvn.startWithDBSetup(
+				libovsdbtest.TestSetup{
+					NBData: []libovsdbtest.TestData{
+						podLSP,
+						otherLSP,
+						&nbdb.LogicalSwitch{UUID: nodeName + "-UUID", Name: nodeName, Ports: []string{podLSP.UUID, otherLSP.UUID}},
+						podAddressSet,
+						otherAddressSet,
+						portGroupACL,
+						addressSetACL,
+						otherACL,
+						&nbdb.PortGroup{UUID: "pg-UUID", Name: "pg", Ports: []string{podLSP.UUID}, ACLs: []string{portGroupACL.UUID}},
+						&nbdb.PortGroup{UUID: "other-pg-UUID", Name: "other_pg", Ports: []string{otherLSP.UUID}},
+						&nbdb.LoadBalancer{
+							UUID: "lb-UUID",
+							Name: "lb",
+							Vips: map[string]string{"172.30.0.10:80": podIP + ":8080,10.128.1.30:8080"},
+						},
+						policy,
+						otherPolicy,
+						&nbdb.LogicalRouter{UUID: "router-UUID", Name: "ovn_cluster_router", Policies: []string{policy.UUID, otherPolicy.UUID}},
+					},
+				},
+			)
+
+			objects, err := fakeOvn.controller.inspectPod(namespaceName, "pod1")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(objects).To(gomega.Equal(&podinspector.PodObjects{
+				LogicalSwitch:     nodeName,
+				LogicalSwitchPort: podLSP.Name,
+				Addresses:         podLSP.Addresses,
+				AddressSets:       []string{"a1"},
+				PortGroups:        []string{"pg"},
+				ACLs:              []string{"as-acl: ip4.src == $a1", "pg-acl: ip4"},
+				LoadBalancers:     []string{"lb: 172.30.0.10:80 -> " + podIP + ":8080"},
+				RouterPolicies:    []string{"ovn_cluster_router 100: ip4.src == " + podIP},
+			}))
+
+			_, err = fakeOvn.controller.inspectPod(namespaceName, "pod3")
+			gomega.Expect(err).To(gomega.MatchError(podinspector.ErrPodNotFound))
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+})
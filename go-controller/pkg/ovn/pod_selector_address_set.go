@@ -733,7 +733,7 @@ func deleteAddrSetsWithoutACLRef(predicateIDs *libovsdbops.DbObjectIDs,
 		}
 	}
 	// update acls to not reference stale address sets
-	_, err = libovsdbops.TransactAndCheck(nbClient, ops)
+	_, err = libovsdbops.TransactAndCheckBatched(nbClient, ops)
 	if err != nil {
 		return fmt.Errorf("faile to trasact db ops: %v", err)
 	}
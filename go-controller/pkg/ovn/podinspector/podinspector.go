@@ -0,0 +1,76 @@
+// Package podinspector gives support engineers a single view of the OVN
+// northbound objects that affect a pod: its logical switch port and the
+// address sets, port groups, ACLs, load balancers and router policies that
+// reference it. Controllers serve their inspection function as a Handler,
+// which should only read from the northbound cache.
+package podinspector
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrPodNotFound is returned by an inspection function when the pod doesn't
+// exist or has no logical switch port on the controller's network
+var ErrPodNotFound = errors.New("pod not found")
+
+// PodObjects holds the northbound objects of a pod on one network
+type PodObjects struct {
+	// LogicalSwitch is the name of the switch the pod's port is attached to
+	LogicalSwitch string `json:"logicalSwitch"`
+	// LogicalSwitchPort is the name of the pod's port
+	LogicalSwitchPort string `json:"logicalSwitchPort"`
+	// Addresses holds the addresses of the pod's port
+	Addresses []string `json:"addresses"`
+	// AddressSets holds the names of the address sets containing a pod IP
+	AddressSets []string `json:"addressSets"`
+	// PortGroups holds the names of the port groups containing the pod's port
+	PortGroups []string `json:"portGroups"`
+	// ACLs holds the ACLs of the pod's port groups and the ACLs matching on one
+	// of the pod's address sets, as "name: match"
+	ACLs []string `json:"acls"`
+	// LoadBalancers holds the load balancer VIPs with a pod IP as backend, as
+	// "name: vip -> backend"
+	LoadBalancers []string `json:"loadBalancers"`
+	// RouterPolicies holds the logical router policies matching on or routing
+	// to a pod IP, as "router priority: match"
+	RouterPolicies []string `json:"routerPolicies"`
+}
+
+// Func returns the northbound objects of the given pod on the network of a
+// controller, or ErrPodNotFound
+type Func func(namespace, name string) (*PodObjects, error)
+
+// Handler serves the inspections of a Func: a GET with the namespace and name
+// query parameters writes the objects of the pod as JSON
+type Handler Func
+
+func (inspect Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	namespace, name := r.URL.Query().Get("namespace"), r.URL.Query().Get("name")
+	if namespace == "" || name == "" {
+		http.Error(w, "missing namespace or name query parameter", http.StatusBadRequest)
+		return
+	}
+	objects, err := inspect(namespace, name)
+	switch {
+	case errors.Is(err, ErrPodNotFound):
+		http.Error(w, fmt.Sprintf("%v: %s/%s", err, namespace, name), http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, fmt.Sprintf("failed to inspect pod %s/%s: %v", namespace, name, err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(objects); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,46 @@
+package podinspector
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	handler := Handler(func(namespace, name string) (*PodObjects, error) {
+		switch name {
+		case "pod1":
+			return &PodObjects{LogicalSwitchPort: namespace + "_" + name, PortGroups: []string{"pg"}}, nil
+		case "failing":
+			return nil, fmt.Errorf("boom")
+		}
+		return nil, ErrPodNotFound
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/pod?namespace=ns&name=pod1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	objects := &PodObjects{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), objects))
+	assert.Equal(t, &PodObjects{LogicalSwitchPort: "ns_pod1", PortGroups: []string{"pg"}}, objects)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/pod?namespace=ns&name=pod2", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/pod?namespace=ns&name=failing", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/pod?namespace=ns", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/debug/pod?namespace=ns&name=pod1", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
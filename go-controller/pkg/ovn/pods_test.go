@@ -443,6 +443,142 @@ var _ = ginkgo.Describe("OVN Pod Operations", func() {
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 		})
 
+		ginkgo.It("allows the port security exemptions of a pod", func() {
+			app.Action = func(ctx *cli.Context) error {
+				_, exemptionCIDR, _ := net.ParseCIDR("10.128.250.0/24")
+				config.Default.PortSecurityExemptionCIDRs = []*net.IPNet{exemptionCIDR}
+				namespaceT := *newNamespace("namespace1")
+				t := newTPod(
+					"node1",
+					"10.128.1.0/24",
+					"10.128.1.2",
+					"10.128.1.1",
+					"myPod",
+					"10.128.1.3",
+					"0a:58:0a:80:01:03",
+					namespaceT.Name,
+				)
+
+				fakeOvn.startWithDBSetup(initialDB,
+					&v1.NamespaceList{
+						Items: []v1.Namespace{
+							namespaceT,
+						},
+					},
+					&v1.NodeList{
+						Items: []v1.Node{
+							*newNode(node1Name, "192.168.126.202/24"),
+						},
+					},
+					&v1.PodList{
+						Items: []v1.Pod{},
+					},
+				)
+
+				t.populateLogicalSwitchCache(fakeOvn, getLogicalSwitchUUID(fakeOvn.controller.nbClient, "node1"))
+				err := fakeOvn.controller.WatchNamespaces()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				err = fakeOvn.controller.WatchPods()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				pod := newPod(t.namespace, t.podName, t.nodeName, t.podIP)
+				pod.Annotations = map[string]string{util.PortSecurityExemptionsAnnotation: `{"ips":["10.128.250.10"],"macs":["00:00:5e:00:01:0a"]}`}
+				_, err = fakeOvn.fakeClient.KubeClient.CoreV1().Pods(t.namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				gomega.Eventually(func() string {
+					return getPodAnnotations(fakeOvn.fakeClient.KubeClient, t.namespace, t.podName)
+				}, 2).Should(gomega.MatchJSON(t.getAnnotationsJson()))
+
+				podAddr := fmt.Sprintf("%s %s", t.podMAC, t.podIP)
+				expectedData := getExpectedDataPodsAndSwitches([]testPod{t}, []string{"node1"})
+				lsp := expectedData[0].(*nbdb.LogicalSwitchPort)
+				lsp.Addresses = []string{podAddr, "unknown"}
+				lsp.PortSecurity = []string{podAddr + " 10.128.250.10", "00:00:5e:00:01:0a 10.128.250.10"}
+				gomega.Eventually(fakeOvn.nbClient).Should(libovsdbtest.HaveData(expectedData))
+
+				// removing the exemptions restores the port security of the pod
+				pod, err = fakeOvn.fakeClient.KubeClient.CoreV1().Pods(t.namespace).Get(context.TODO(), t.podName, metav1.GetOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				delete(pod.Annotations, util.PortSecurityExemptionsAnnotation)
+				_, err = fakeOvn.fakeClient.KubeClient.CoreV1().Pods(t.namespace).Update(context.TODO(), pod, metav1.UpdateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				gomega.Eventually(fakeOvn.nbClient).Should(libovsdbtest.HaveData(getExpectedDataPodsAndSwitches([]testPod{t}, []string{"node1"})))
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("does not retry a pod with invalid port security exemptions", func() {
+			app.Action = func(ctx *cli.Context) error {
+				namespaceT := *newNamespace("namespace1")
+				t := newTPod(
+					"node1",
+					"10.128.1.0/24",
+					"10.128.1.2",
+					"10.128.1.1",
+					"myPod",
+					"10.128.1.3",
+					"0a:58:0a:80:01:03",
+					namespaceT.Name,
+				)
+
+				fakeOvn.startWithDBSetup(initialDB,
+					&v1.NamespaceList{
+						Items: []v1.Namespace{
+							namespaceT,
+						},
+					},
+					&v1.NodeList{
+						Items: []v1.Node{
+							*newNode(node1Name, "192.168.126.202/24"),
+						},
+					},
+					&v1.PodList{
+						Items: []v1.Pod{},
+					},
+				)
+
+				t.populateLogicalSwitchCache(fakeOvn, getLogicalSwitchUUID(fakeOvn.controller.nbClient, "node1"))
+				err := fakeOvn.controller.WatchNamespaces()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				err = fakeOvn.controller.WatchPods()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// no exemption CIDR is configured, the annotation is invalid
+				pod := newPod(t.namespace, t.podName, t.nodeName, t.podIP)
+				pod.Annotations = map[string]string{util.PortSecurityExemptionsAnnotation: `{"ips":["10.128.250.10"]}`}
+				_, err = fakeOvn.fakeClient.KubeClient.CoreV1().Pods(t.namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				gomega.Eventually(fakeOvn.fakeRecorder.Events).Should(gomega.Receive(gomega.ContainSubstring(util.PortSecurityExemptionsAnnotation)))
+				podKey := t.namespace + "/" + t.podName
+				retry.CheckRetryObjectEventually(podKey, false, fakeOvn.controller.retryPods)
+				gomega.Consistently(func() bool {
+					return retry.CheckRetryObj(podKey, fakeOvn.controller.retryPods)
+				}, 2).Should(gomega.BeFalse())
+
+				// fixing the annotation wires the pod
+				pod, err = fakeOvn.fakeClient.KubeClient.CoreV1().Pods(t.namespace).Get(context.TODO(), t.podName, metav1.GetOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				delete(pod.Annotations, util.PortSecurityExemptionsAnnotation)
+				_, err = fakeOvn.fakeClient.KubeClient.CoreV1().Pods(t.namespace).Update(context.TODO(), pod, metav1.UpdateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				gomega.Eventually(func() string {
+					return getPodAnnotations(fakeOvn.fakeClient.KubeClient, t.namespace, t.podName)
+				}, 2).Should(gomega.MatchJSON(t.getAnnotationsJson()))
+				gomega.Eventually(fakeOvn.nbClient).Should(libovsdbtest.HaveData(getExpectedDataPodsAndSwitches([]testPod{t}, []string{"node1"})))
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
 		ginkgo.It("allows allocation after pods are completed", func() {
 			app.Action = func(ctx *cli.Context) error {
 				namespaceT := *newNamespace("namespace1")
@@ -1086,6 +1222,10 @@ var _ = ginkgo.Describe("OVN Pod Operations", func() {
 					nil,                        // skip config
 					gomega.BeNumerically("==", retry.MaxFailedAttempts), // failedAttempts should reach the max
 				)
+				// the persistent failure is reported on the pod
+				gomega.Eventually(fakeOvn.fakeRecorder.Events).Should(gomega.Receive(gomega.And(
+					gomega.ContainSubstring("RetryFailing"),
+					gomega.ContainSubstring(fmt.Sprintf("failed to reconcile after %d attempts", retry.MaxFailedAttempts)))))
 
 				// restore nbdb, trigger a retry and verify that the retry entry gets deleted
 				// because it reached retry.MaxFailedAttempts and the corresponding pod has NOT been added to OVN
@@ -1094,6 +1234,9 @@ var _ = ginkgo.Describe("OVN Pod Operations", func() {
 				resetNBClient(connCtx, fakeOvn.controller.nbClient)
 
 				fakeOvn.controller.retryPods.RequestRetryObjs()
+				gomega.Eventually(fakeOvn.fakeRecorder.Events).Should(gomega.Receive(gomega.And(
+					gomega.ContainSubstring("RetryFailed"),
+					gomega.ContainSubstring("last error"))))
 				// check that pod is in API server
 				pod, err = fakeOvn.fakeClient.KubeClient.CoreV1().Pods(podTest.namespace).Get(
 					context.TODO(), podTest.podName, metav1.GetOptions{})
@@ -1901,6 +1901,67 @@ var _ = ginkgo.Describe("OVN NetworkPolicy Operations", func() {
 			gomega.Expect(app.Run([]string{app.Name})).To(gomega.Succeed())
 		})
 
+		ginkgo.It("policies with their own ACL logging annotation override the namespace allow level and rate limit", func() {
+			app.Action = func(ctx *cli.Context) error {
+				newPolicy := getMatchLabelsNetworkPolicy(netPolicyName1, namespaceName1, namespaceName2, "", true, false)
+				newPolicy.Annotations = map[string]string{
+					util.AclLoggingAnnotation: fmt.Sprintf(`{ "allow": "%s", "rate-limit": 5 }`, nbdb.ACLSeverityWarning),
+				}
+				startOvn(initialDB, []v1.Namespace{originalNamespace}, []knet.NetworkPolicy{*newPolicy}, nil, nil)
+
+				meterName := fakeOvn.controller.getNetpolACLLoggingMeterName(namespaceName1, netPolicyName1)
+				getExpectedData := func(denyLogSeverity nbdb.ACLSeverity) []libovsdb.TestData {
+					band := &nbdb.MeterBand{
+						UUID:   "policy-meter-band-UUID",
+						Action: types.MeterAction,
+						Rate:   5,
+					}
+					meterFairness := true
+					meter := &nbdb.Meter{
+						UUID:        "policy-meter-UUID",
+						Name:        meterName,
+						Fair:        &meterFairness,
+						Unit:        types.PacketsPerSecond,
+						Bands:       []string{band.UUID},
+						ExternalIDs: fakeOvn.controller.getNetpolACLLoggingMeterDbIDs(namespaceName1, netPolicyName1).GetExternalIDs(),
+					}
+					expectedData := append(initialDB.NBData, band, meter)
+					// the allow level of the policy overrides the namespace one, the deny ACLs are the namespace ones
+					for _, data := range getPolicyDataWithLogSev(newPolicy, nil, []string{}, nil, nbdb.ACLSeverityWarning) {
+						if acl, ok := data.(*nbdb.ACL); ok {
+							acl.Meter = &meterName
+						}
+						expectedData = append(expectedData, data)
+					}
+					return append(expectedData, getDefaultDenyDataWithLogSev(newPolicy, nil, denyLogSeverity)...)
+				}
+				// originalACLLogSeverity.Deny == nbdb.ACLSeverityAlert
+				gomega.Eventually(fakeOvn.nbClient).Should(libovsdb.HaveData(getExpectedData(nbdb.ACLSeverityAlert)...))
+
+				// the policy settings survive namespace updates
+				gomega.Expect(
+					updateNamespaceACLLogSeverity(&originalNamespace, nbdb.ACLSeverityDebug, nbdb.ACLSeverityDebug)).To(gomega.Succeed(),
+					"should have managed to update the ACL logging severity within the namespace")
+				gomega.Eventually(fakeOvn.nbClient).Should(libovsdb.HaveData(getExpectedData(nbdb.ACLSeverityDebug)...))
+
+				ginkgo.By("Deleting the network policy, its meter is deleted")
+				err := fakeOvn.fakeClient.KubeClient.NetworkingV1().NetworkPolicies(newPolicy.Namespace).
+					Delete(context.TODO(), newPolicy.Name, metav1.DeleteOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Eventually(func() []string {
+					meters := []nbdb.Meter{}
+					gomega.Expect(fakeOvn.nbClient.List(context.TODO(), &meters)).To(gomega.Succeed())
+					meterNames := []string{}
+					for _, meter := range meters {
+						meterNames = append(meterNames, meter.Name)
+					}
+					return meterNames
+				}).ShouldNot(gomega.ContainElement(meterName))
+				return nil
+			}
+			gomega.Expect(app.Run([]string{app.Name})).To(gomega.Succeed())
+		})
+
 		ginkgo.It("creates stateless OVN ACLs based off of the annotation", func() {
 			app.Action = func(ctx *cli.Context) error {
 				namespace1 := *newNamespace(namespaceName1)
@@ -2152,8 +2213,8 @@ var _ = ginkgo.Describe("OVN NetworkPolicy Low-Level Operations", func() {
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 		}
 		defaultAclLogging := ACLLoggingLevels{
-			nbdb.ACLSeverityInfo,
-			nbdb.ACLSeverityInfo,
+			Allow: nbdb.ACLSeverityInfo,
+			Deny:  nbdb.ACLSeverityInfo,
 		}
 
 		gomega.Expect(gp.addNamespaceAddressSet(one.GetObjectID(libovsdbops.ObjectNameKey), asFactory)).To(gomega.BeTrue())
@@ -4,6 +4,7 @@ import (
 	"context"
 	"sync"
 
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
 	addressset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/address_set"
 	lsm "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/logical_switch_manager"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/syncmap"
@@ -48,9 +49,14 @@ func NewSecondaryLayer2NetworkController(cnci *CommonNetworkControllerInfo, netI
 		},
 	}
 
-	// disable multicast support for secondary networks
-	// TBD: changes needs to be made to support multicast in secondary networks
-	oc.multicastSupport = false
+	// multicast is only supported on the secondary networks that enable it, and it requires the pod IPs
+	oc.multicastSupport = cnci.multicastSupport && netInfo.MulticastEnabled() && oc.doesNetworkRequireIPAM()
+
+	// with interconnect, the network spans all the zones and only the pods of the local zone nodes get local
+	// logical ports
+	if config.OVNKubernetesFeature.EnableInterconnect {
+		oc.localZoneNodes = &sync.Map{}
+	}
 
 	oc.initRetryFramework()
 	return oc
@@ -75,6 +81,9 @@ func (oc *SecondaryLayer2NetworkController) Cleanup(netName string) error {
 func (oc *SecondaryLayer2NetworkController) Init() error {
 	switchName := oc.GetNetworkScopedName(types.OVNLayer2Switch)
 
+	if err := oc.initMulticast(false); err != nil {
+		return err
+	}
 	_, err := oc.InitializeLogicalSwitch(switchName, oc.Subnets(), oc.ExcludeSubnets())
 	return err
 }
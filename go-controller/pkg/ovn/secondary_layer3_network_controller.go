@@ -9,7 +9,6 @@ import (
 	"time"
 
 	mnpapi "github.com/k8snetworkplumbingwg/multi-networkpolicy/pkg/apis/k8s.cni.cncf.io/v1beta1"
-	"github.com/ovn-org/libovsdb/ovsdb"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/libovsdbops"
@@ -287,9 +286,8 @@ func NewSecondaryLayer3NetworkController(cnci *CommonNetworkControllerInfo, netI
 		syncZoneICFailed:            sync.Map{},
 		zoneICHandler:               zoneICHandler,
 	}
-	// disable multicast support for secondary networks
-	// TBD: changes needs to be made to support multicast in secondary networks
-	oc.multicastSupport = false
+	// multicast is only supported on the secondary networks that enable it
+	oc.multicastSupport = cnci.multicastSupport && netInfo.MulticastEnabled()
 
 	oc.initRetryFramework()
 	return oc
@@ -300,9 +298,11 @@ func (oc *SecondaryLayer3NetworkController) initRetryFramework() {
 	oc.retryNodes = oc.newRetryFramework(factory.NodeType)
 
 	// For secondary networks, we don't have to watch namespace events if
-	// multi-network policy support is not enabled.
-	if util.IsMultiNetworkPoliciesSupportEnabled() {
+	// neither multi-network policy support nor multicast is enabled.
+	if oc.doesNetworkRequireNamespaces() {
 		oc.retryNamespaces = oc.newRetryFramework(factory.NamespaceType)
+	}
+	if util.IsMultiNetworkPoliciesSupportEnabled() {
 		oc.retryNetworkPolicies = oc.newRetryFramework(factory.MultiNetworkPolicyType)
 	}
 }
@@ -320,6 +320,7 @@ func (oc *SecondaryLayer3NetworkController) newRetryFramework(
 	resourceHandler := &retry.ResourceHandler{
 		HasUpdateFunc:          hasResourceAnUpdateFunc(objectType),
 		NeedsUpdateDuringRetry: needsUpdateDuringRetry(objectType),
+		MaxFailedAttempts:      maxFailedAttempts(objectType),
 		ObjType:                objectType,
 		EventHandler:           eventHandler,
 	}
@@ -364,47 +365,9 @@ func (oc *SecondaryLayer3NetworkController) Stop() {
 // Cleanup cleans up logical entities for the given network, called from net-attach-def routine
 // could be called from a dummy Controller (only has CommonNetworkControllerInfo set)
 func (oc *SecondaryLayer3NetworkController) Cleanup(netName string) error {
-	// cleans up related OVN logical entities
-	var ops []ovsdb.Operation
-	var err error
-
 	// Note : Cluster manager removes the subnet annotation for the node.
-
 	klog.Infof("Delete OVN logical entities for %s network controller of network %s", types.Layer3Topology, netName)
-	// first delete node logical switches
-	ops, err = libovsdbops.DeleteLogicalSwitchesWithPredicateOps(oc.nbClient, ops,
-		func(item *nbdb.LogicalSwitch) bool {
-			return item.ExternalIDs[types.NetworkExternalID] == netName
-		})
-	if err != nil {
-		return fmt.Errorf("failed to get ops for deleting switches of network %s: %v", netName, err)
-	}
-
-	// now delete cluster router
-	ops, err = libovsdbops.DeleteLogicalRoutersWithPredicateOps(oc.nbClient, ops,
-		func(item *nbdb.LogicalRouter) bool {
-			return item.ExternalIDs[types.NetworkExternalID] == netName
-		})
-	if err != nil {
-		return fmt.Errorf("failed to get ops for deleting routers of network %s: %v", netName, err)
-	}
-
-	ops, err = cleanupPolicyLogicalEntities(oc.nbClient, ops, netName)
-	if err != nil {
-		return err
-	}
-
-	_, err = libovsdbops.TransactAndCheck(oc.nbClient, ops)
-	if err != nil {
-		return fmt.Errorf("failed to deleting routers/switches of network %s: %v", netName, err)
-	}
-
-	if config.OVNKubernetesFeature.EnableInterconnect {
-		if err = oc.zoneICHandler.Cleanup(); err != nil {
-			return fmt.Errorf("failed to delete interconnect transit switch of network %s: %v", netName, err)
-		}
-	}
-	return nil
+	return cleanupSecondaryNetworkLogicalEntities(oc.nbClient, netName)
 }
 
 func (oc *SecondaryLayer3NetworkController) Run() error {
@@ -454,6 +417,9 @@ func (oc *SecondaryLayer3NetworkController) WatchNodes() error {
 }
 
 func (oc *SecondaryLayer3NetworkController) Init() error {
+	if err := oc.initMulticast(true); err != nil {
+		return err
+	}
 	_, err := oc.createOvnClusterRouter()
 	return err
 }
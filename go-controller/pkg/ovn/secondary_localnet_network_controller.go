@@ -2,6 +2,10 @@ package ovn
 
 import (
 	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/libovsdbops"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
 	addressset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/address_set"
@@ -9,7 +13,6 @@ import (
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/syncmap"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
-	"sync"
 
 	"k8s.io/klog/v2"
 )
@@ -49,10 +52,15 @@ func NewSecondaryLocalnetNetworkController(cnci *CommonNetworkControllerInfo, ne
 		},
 	}
 
-	// disable multicast support for secondary networks
-	// TBD: changes needs to be made to support multicast in secondary networks
+	// disable multicast support for localnet networks, the multicast traffic is forwarded to the provider network
 	oc.multicastSupport = false
 
+	// with interconnect, the network spans all the zones and only the pods of the local zone nodes get local
+	// logical ports
+	if config.OVNKubernetesFeature.EnableInterconnect {
+		oc.localZoneNodes = &sync.Map{}
+	}
+
 	oc.initRetryFramework()
 	return oc
 }
@@ -81,6 +89,26 @@ func (oc *SecondaryLocalnetNetworkController) Init() error {
 		return err
 	}
 
+	if err := oc.ensureLocalnetPort(logicalSwitch); err != nil {
+		return err
+	}
+	return oc.ensureLocalnetGuardACLs()
+}
+
+// Reconfigure reconfigures the network with the updated network information and updates the VLAN of its localnet
+// port and its guard ACLs
+func (oc *SecondaryLocalnetNetworkController) Reconfigure(netInfo util.NetInfo) error {
+	if err := oc.BaseSecondaryLayer2NetworkController.Reconfigure(netInfo); err != nil {
+		return err
+	}
+	if err := oc.ensureLocalnetPort(&nbdb.LogicalSwitch{Name: oc.GetNetworkScopedName(types.OVNLocalnetSwitch)}); err != nil {
+		return err
+	}
+	return oc.ensureLocalnetGuardACLs()
+}
+
+// ensureLocalnetPort creates or updates the localnet port of the network switch
+func (oc *SecondaryLocalnetNetworkController) ensureLocalnetPort(logicalSwitch *nbdb.LogicalSwitch) error {
 	// Add external interface as a logical port to external_switch.
 	// This is a learning switch port with "unknown" address. The external
 	// world is accessed via this port.
@@ -97,11 +125,79 @@ func (oc *SecondaryLocalnetNetworkController) Init() error {
 		logicalSwitchPort.TagRequest = &intVlanID
 	}
 
-	err = libovsdbops.CreateOrUpdateLogicalSwitchPortsOnSwitch(oc.nbClient, logicalSwitch, &logicalSwitchPort)
+	err := libovsdbops.CreateOrUpdateLogicalSwitchPortsOnSwitch(oc.nbClient, logicalSwitch, &logicalSwitchPort)
 	if err != nil {
-		klog.Errorf("Failed to add logical port %+v to switch %s: %v", logicalSwitchPort, switchName, err)
+		klog.Errorf("Failed to add logical port %+v to switch %s: %v", logicalSwitchPort, logicalSwitch.Name, err)
 		return err
 	}
 
 	return nil
 }
+
+type localnetGuardACLType string
+
+const (
+	// raGuardACL drops the IPv6 router advertisements sent by the pods
+	raGuardACL localnetGuardACLType = "RA"
+	// dhcpv6GuardACL drops the DHCPv6 server messages sent by the pods
+	dhcpv6GuardACL localnetGuardACLType = "DHCPv6"
+)
+
+func getLocalnetGuardACLDbIDs(guardType localnetGuardACLType, controller string) *libovsdbops.DbObjectIDs {
+	return libovsdbops.NewDbObjectIDs(libovsdbops.ACLLocalnetGuard, controller,
+		map[libovsdbops.ExternalIDKey]string{
+			libovsdbops.TypeKey: string(guardType),
+		})
+}
+
+// ensureLocalnetGuardACLs adds to the network switch the ACLs dropping the router advertisements and the DHCPv6
+// server messages sent by the pods as configured, and removes the ones that are no longer configured. The traffic
+// coming from the provider network through the localnet port is never dropped.
+// There is no delete function for these ACLs, they are garbage-collected along with the network switch.
+func (oc *SecondaryLocalnetNetworkController) ensureLocalnetGuardACLs() error {
+	switchName := oc.GetNetworkScopedName(types.OVNLocalnetSwitch)
+	fromPodsMatch := fmt.Sprintf("inport != %q", oc.GetNetworkScopedName(types.OVNLocalnetPort))
+	guards := []struct {
+		guardType localnetGuardACLType
+		enabled   bool
+		match     string
+	}{
+		{raGuardACL, oc.RAGuard(), "icmp6.type == 134"},
+		{dhcpv6GuardACL, oc.DHCPv6Guard(), "ip6 && udp.src == 547 && udp.dst == 546"},
+	}
+
+	var guardACLs, unguardACLs []*nbdb.ACL
+	for _, guard := range guards {
+		acl := BuildACL(getLocalnetGuardACLDbIDs(guard.guardType, oc.controllerName), types.LocalnetGuardPriority,
+			fromPodsMatch+" && "+guard.match, nbdb.ACLActionDrop, nil, lportEgressAfterLB)
+		if guard.enabled {
+			guardACLs = append(guardACLs, acl)
+		} else {
+			unguardACLs = append(unguardACLs, acl)
+		}
+	}
+
+	ops, err := libovsdbops.CreateOrUpdateACLsOps(oc.nbClient, nil, guardACLs...)
+	if err != nil {
+		return fmt.Errorf("failed to create or update the guard ACLs of switch %s: %v", switchName, err)
+	}
+	ops, err = libovsdbops.AddACLsToLogicalSwitchOps(oc.nbClient, ops, switchName, guardACLs...)
+	if err != nil {
+		return fmt.Errorf("failed to add the guard ACLs to switch %s: %v", switchName, err)
+	}
+
+	staleACLs, err := libovsdbops.FindACLs(oc.nbClient, unguardACLs)
+	if err != nil {
+		return fmt.Errorf("failed to find the stale guard ACLs of switch %s: %v", switchName, err)
+	}
+	if len(staleACLs) > 0 {
+		p := func(sw *nbdb.LogicalSwitch) bool { return sw.Name == switchName }
+		ops, err = libovsdbops.RemoveACLsFromLogicalSwitchesWithPredicateOps(oc.nbClient, ops, p, staleACLs...)
+		if err != nil {
+			return fmt.Errorf("failed to remove the stale guard ACLs from switch %s: %v", switchName, err)
+		}
+	}
+
+	_, err = libovsdbops.TransactAndCheck(oc.nbClient, ops)
+	return err
+}
@@ -0,0 +1,136 @@
+package ovn
+
+import (
+	"context"
+
+	cnitypes "github.com/containernetworking/cni/pkg/types"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+
+	ovncnitypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/cni/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/libovsdbops"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
+	libovsdbtest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing/libovsdb"
+	ovntypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+)
+
+var _ = ginkgo.Describe("OVN secondary localnet network reconfiguration", func() {
+	const netName = "blue"
+	var nbCleanup *libovsdbtest.Cleanup
+
+	ginkgo.AfterEach(func() {
+		if nbCleanup != nil {
+			nbCleanup.Cleanup()
+		}
+	})
+
+	newGuardedNetInfo := func(vlan int, raGuard, dhcpv6Guard bool) util.NetInfo {
+		nInfo, err := util.NewNetInfo(&ovncnitypes.NetConf{
+			NetConf:     cnitypes.NetConf{Name: netName},
+			Topology:    ovntypes.LocalnetTopology,
+			Subnets:     "10.1.1.0/24",
+			VLANID:      vlan,
+			RAGuard:     raGuard,
+			DHCPv6Guard: dhcpv6Guard,
+		})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		return nInfo
+	}
+	newNetInfo := func(vlan int) util.NetInfo {
+		return newGuardedNetInfo(vlan, false, false)
+	}
+
+	ginkgo.It("updates the VLAN of the localnet port", func() {
+		switchName := util.GetSecondaryNetworkPrefix(netName) + ovntypes.OVNLocalnetSwitch
+		portName := util.GetSecondaryNetworkPrefix(netName) + ovntypes.OVNLocalnetPort
+		vlan := 10
+		initialNBDB := libovsdbtest.TestSetup{
+			NBData: []libovsdbtest.TestData{
+				&nbdb.LogicalSwitchPort{
+					UUID:       portName + "-UUID",
+					Name:       portName,
+					Type:       "localnet",
+					Addresses:  []string{"unknown"},
+					TagRequest: &vlan,
+				},
+				&nbdb.LogicalSwitch{
+					UUID:  switchName + "-UUID",
+					Name:  switchName,
+					Ports: []string{portName + "-UUID"},
+				},
+			},
+		}
+		nbClient, cleanup, err := libovsdbtest.NewNBTestHarness(initialNBDB, nil)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		nbCleanup = cleanup
+
+		oc := &SecondaryLocalnetNetworkController{}
+		oc.nbClient = nbClient
+		oc.controllerName = netName + "-network-controller"
+		oc.NetInfo = newNetInfo(vlan)
+
+		getPort := func() (*nbdb.LogicalSwitchPort, error) {
+			return libovsdbops.GetLogicalSwitchPort(nbClient, &nbdb.LogicalSwitchPort{Name: portName})
+		}
+
+		gomega.Expect(oc.Reconfigure(newNetInfo(20))).To(gomega.Succeed())
+		gomega.Expect(oc.Vlan()).To(gomega.BeEquivalentTo(20))
+		gomega.Eventually(getPort).Should(gomega.HaveField("TagRequest", gomega.HaveValue(gomega.Equal(20))))
+
+		// removing the VLAN clears the tag
+		gomega.Expect(oc.Reconfigure(newNetInfo(0))).To(gomega.Succeed())
+		gomega.Eventually(getPort).Should(gomega.HaveField("TagRequest", gomega.BeNil()))
+	})
+
+	ginkgo.It("adds and removes the guard ACLs of the localnet switch", func() {
+		switchName := util.GetSecondaryNetworkPrefix(netName) + ovntypes.OVNLocalnetSwitch
+		portName := util.GetSecondaryNetworkPrefix(netName) + ovntypes.OVNLocalnetPort
+		initialNBDB := libovsdbtest.TestSetup{
+			NBData: []libovsdbtest.TestData{
+				&nbdb.LogicalSwitch{
+					UUID: switchName + "-UUID",
+					Name: switchName,
+				},
+			},
+		}
+		nbClient, cleanup, err := libovsdbtest.NewNBTestHarness(initialNBDB, nil)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		nbCleanup = cleanup
+
+		oc := &SecondaryLocalnetNetworkController{}
+		oc.nbClient = nbClient
+		oc.controllerName = netName + "-network-controller"
+		oc.NetInfo = newNetInfo(0)
+
+		getGuardMatches := func() ([]string, error) {
+			sw, err := libovsdbops.GetLogicalSwitch(nbClient, &nbdb.LogicalSwitch{Name: switchName})
+			if err != nil {
+				return nil, err
+			}
+			matches := []string{}
+			for _, uuid := range sw.ACLs {
+				acl := &nbdb.ACL{UUID: uuid}
+				if err := nbClient.Get(context.Background(), acl); err != nil {
+					return nil, err
+				}
+				gomega.Expect(acl.Action).To(gomega.Equal(nbdb.ACLActionDrop))
+				gomega.Expect(acl.Priority).To(gomega.Equal(ovntypes.LocalnetGuardPriority))
+				matches = append(matches, acl.Match)
+			}
+			return matches, nil
+		}
+		raMatch := `inport != "` + portName + `" && icmp6.type == 134`
+		dhcpv6Match := `inport != "` + portName + `" && ip6 && udp.src == 547 && udp.dst == 546`
+
+		gomega.Expect(oc.Reconfigure(newGuardedNetInfo(0, true, true))).To(gomega.Succeed())
+		gomega.Eventually(getGuardMatches).Should(gomega.ConsistOf(raMatch, dhcpv6Match))
+
+		// disabling a guard removes its ACL from the switch
+		gomega.Expect(oc.Reconfigure(newGuardedNetInfo(0, true, false))).To(gomega.Succeed())
+		gomega.Eventually(getGuardMatches).Should(gomega.ConsistOf(raMatch))
+
+		gomega.Expect(oc.Reconfigure(newNetInfo(0))).To(gomega.Succeed())
+		gomega.Eventually(getGuardMatches).Should(gomega.BeEmpty())
+	})
+})
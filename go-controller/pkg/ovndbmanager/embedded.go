@@ -0,0 +1,234 @@
+package ovndbmanager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/klog/v2"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+)
+
+const (
+	embeddedHealthCheckInterval = 30 * time.Second
+	nbdbFile                    = "ovnnb_db.db"
+	sbdbFile                    = "ovnsb_db.db"
+	backupTimeFormat            = "2006-01-02_150405"
+)
+
+// embeddedDB describes one of the databases run by ovnkube-controller in embedded mode
+type embeddedDB struct {
+	name       string
+	file       string
+	serverSock string
+	// ovn-ctl commands used to manage the database server
+	start  string
+	stop   string
+	status string
+	// appCtl runs an ovn-appctl command against the database server
+	appCtl func(timeout int, args ...string) (string, string, error)
+}
+
+func embeddedDBs() []*embeddedDB {
+	return []*embeddedDB{
+		{
+			name:       "OVN_Northbound",
+			file:       filepath.Join(config.EmbeddedDB.DBDir, nbdbFile),
+			serverSock: nbdbServerSock,
+			start:      "start_nb_ovsdb",
+			stop:       "stop_nb_ovsdb",
+			status:     "status_ovnnb",
+			appCtl:     util.RunOVNNBAppCtlWithTimeout,
+		},
+		{
+			name:       "OVN_Southbound",
+			file:       filepath.Join(config.EmbeddedDB.DBDir, sbdbFile),
+			serverSock: sbdbServerSock,
+			start:      "start_sb_ovsdb",
+			stop:       "stop_sb_ovsdb",
+			status:     "status_ovnsb",
+			appCtl:     util.RunOVNSBAppCtlWithTimeout,
+		},
+	}
+}
+
+// RunEmbeddedDBs launches the NB and SB databases and ovn-northd of a single node interconnect zone, and keeps them
+// running, compacted and backed up until stopCh is closed, at which point they are stopped.
+func RunEmbeddedDBs(stopCh <-chan struct{}, wg *sync.WaitGroup) error {
+	dbs := embeddedDBs()
+	for _, db := range dbs {
+		if err := startEmbeddedDB(db); err != nil {
+			return err
+		}
+	}
+	if err := startNorthd(); err != nil {
+		return err
+	}
+	klog.Infof("Embedded NB and SB databases and ovn-northd started, databases stored in %s", config.EmbeddedDB.DBDir)
+
+	wg.Add(1)
+	go func() {
+		defer utilruntime.HandleCrash()
+		defer wg.Done()
+		runEmbeddedDBs(dbs, stopCh)
+	}()
+	return nil
+}
+
+func runEmbeddedDBs(dbs []*embeddedDB, stopCh <-chan struct{}) {
+	healthTicker := time.NewTicker(embeddedHealthCheckInterval)
+	defer healthTicker.Stop()
+
+	var compactionCh, backupCh <-chan time.Time
+	if config.EmbeddedDB.CompactionInterval > 0 {
+		compactionTicker := time.NewTicker(time.Duration(config.EmbeddedDB.CompactionInterval) * time.Second)
+		defer compactionTicker.Stop()
+		compactionCh = compactionTicker.C
+	}
+	if config.EmbeddedDB.BackupDir != "" {
+		backupTicker := time.NewTicker(time.Duration(config.EmbeddedDB.BackupInterval) * time.Second)
+		defer backupTicker.Stop()
+		backupCh = backupTicker.C
+	}
+
+	for {
+		select {
+		case <-healthTicker.C:
+			for _, db := range dbs {
+				if err := ensureEmbeddedDB(db); err != nil {
+					klog.Errorf("Failed to ensure embedded database %s is running: %v", db.name, err)
+				}
+			}
+			if err := ensureNorthd(); err != nil {
+				klog.Errorf("Failed to ensure embedded ovn-northd is running: %v", err)
+			}
+		case <-compactionCh:
+			for _, db := range dbs {
+				if err := compactEmbeddedDB(db); err != nil {
+					klog.Errorf("Failed to compact embedded database %s: %v", db.name, err)
+				}
+			}
+		case <-backupCh:
+			for _, db := range dbs {
+				if err := backupEmbeddedDB(db, config.EmbeddedDB.BackupDir, config.EmbeddedDB.BackupCount); err != nil {
+					klog.Errorf("Failed to back up embedded database %s: %v", db.name, err)
+				}
+			}
+		case <-stopCh:
+			stopEmbeddedDBs(dbs)
+			return
+		}
+	}
+}
+
+func dbFileArgs() []string {
+	return []string{
+		"--db-nb-file=" + filepath.Join(config.EmbeddedDB.DBDir, nbdbFile),
+		"--db-sb-file=" + filepath.Join(config.EmbeddedDB.DBDir, sbdbFile),
+	}
+}
+
+func startEmbeddedDB(db *embeddedDB) error {
+	args := append(dbFileArgs(), "--no-monitor", db.start)
+	if _, stderr, err := util.RunOVNCtl(args...); err != nil {
+		return fmt.Errorf("failed to start embedded database %s, stderr: %q, error: %w", db.name, stderr, err)
+	}
+	return nil
+}
+
+func startNorthd() error {
+	args := append(dbFileArgs(), "--no-monitor", "--ovn-manage-ovsdb=no", "start_northd")
+	if _, stderr, err := util.RunOVNCtl(args...); err != nil {
+		return fmt.Errorf("failed to start embedded ovn-northd, stderr: %q, error: %w", stderr, err)
+	}
+	return nil
+}
+
+// ensureEmbeddedDB restarts the database server if ovn-ctl no longer reports it as running
+func ensureEmbeddedDB(db *embeddedDB) error {
+	if _, _, err := util.RunOVNCtl(db.status); err == nil {
+		return nil
+	}
+	klog.Warningf("Embedded database %s is not running, restarting it", db.name)
+	return startEmbeddedDB(db)
+}
+
+// ensureNorthd restarts ovn-northd if ovn-ctl no longer reports it as running
+func ensureNorthd() error {
+	if _, _, err := util.RunOVNCtl("status_northd"); err == nil {
+		return nil
+	}
+	klog.Warning("Embedded ovn-northd is not running, restarting it")
+	return startNorthd()
+}
+
+func compactEmbeddedDB(db *embeddedDB) error {
+	if _, stderr, err := db.appCtl(30, "ovsdb-server/compact", db.name); err != nil {
+		return fmt.Errorf("stderr: %q, error: %w", stderr, err)
+	}
+	klog.V(5).Infof("Compacted embedded database %s", db.name)
+	return nil
+}
+
+// backupEmbeddedDB writes a backup of the database to backupDir and removes the oldest backups of the database
+// so that at most backupCount of them are kept
+func backupEmbeddedDB(db *embeddedDB, backupDir string, backupCount int) error {
+	if err := os.MkdirAll(backupDir, 0o750); err != nil {
+		return fmt.Errorf("failed to create backup directory %s: %w", backupDir, err)
+	}
+	out, stderr, err := util.RunOVSDBClient("-t", "30", "backup", db.serverSock, db.name)
+	if err != nil {
+		return fmt.Errorf("stderr: %q, error: %w", stderr, err)
+	}
+	backupFile := filepath.Join(backupDir, backupPrefix(db)+time.Now().UTC().Format(backupTimeFormat))
+	if err := os.WriteFile(backupFile, []byte(out+"\n"), 0o640); err != nil {
+		return fmt.Errorf("failed to write backup file %s: %w", backupFile, err)
+	}
+	klog.Infof("Backed up embedded database %s to %s", db.name, backupFile)
+	return pruneBackups(backupDir, backupPrefix(db), backupCount)
+}
+
+func backupPrefix(db *embeddedDB) string {
+	dbFile := filepath.Base(db.file)
+	return strings.TrimSuffix(dbFile, filepath.Ext(dbFile)) + "_bak_"
+}
+
+// pruneBackups removes the oldest files with the given prefix from backupDir until at most backupCount remain.
+// Backups are named after the time they were taken so their lexical order is their age order.
+func pruneBackups(backupDir, prefix string, backupCount int) error {
+	backups, err := filepath.Glob(filepath.Join(backupDir, prefix+"*"))
+	if err != nil {
+		return err
+	}
+	if len(backups) <= backupCount {
+		return nil
+	}
+	sort.Strings(backups)
+	for _, backup := range backups[:len(backups)-backupCount] {
+		if err := os.Remove(backup); err != nil {
+			return fmt.Errorf("failed to remove old backup %s: %w", backup, err)
+		}
+		klog.V(5).Infof("Removed old backup %s", backup)
+	}
+	return nil
+}
+
+// stopEmbeddedDBs stops ovn-northd before the databases it connects to
+func stopEmbeddedDBs(dbs []*embeddedDB) {
+	klog.Info("Stopping embedded ovn-northd and NB and SB databases")
+	if _, stderr, err := util.RunOVNCtl("stop_northd"); err != nil {
+		klog.Errorf("Failed to stop embedded ovn-northd, stderr: %q, error: %v", stderr, err)
+	}
+	for _, db := range dbs {
+		if _, stderr, err := util.RunOVNCtl(db.stop); err != nil {
+			klog.Errorf("Failed to stop embedded database %s, stderr: %q, error: %v", db.name, stderr, err)
+		}
+	}
+}
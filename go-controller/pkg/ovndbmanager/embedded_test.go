@@ -0,0 +1,69 @@
+package ovndbmanager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestPruneBackups(t *testing.T) {
+	tests := []struct {
+		desc        string
+		backups     []string
+		backupCount int
+		expected    []string
+	}{
+		{
+			desc:        "Fewer backups than the backup count are kept",
+			backups:     []string{"ovnnb_db_bak_2023-01-01_000000", "ovnnb_db_bak_2023-01-02_000000"},
+			backupCount: 3,
+			expected:    []string{"ovnnb_db_bak_2023-01-01_000000", "ovnnb_db_bak_2023-01-02_000000"},
+		},
+		{
+			desc: "Oldest backups are removed",
+			backups: []string{
+				"ovnnb_db_bak_2023-01-03_000000",
+				"ovnnb_db_bak_2023-01-01_000000",
+				"ovnnb_db_bak_2023-01-02_000000",
+			},
+			backupCount: 2,
+			expected:    []string{"ovnnb_db_bak_2023-01-02_000000", "ovnnb_db_bak_2023-01-03_000000"},
+		},
+		{
+			desc: "Backups of other databases are left alone",
+			backups: []string{
+				"ovnnb_db_bak_2023-01-01_000000",
+				"ovnnb_db_bak_2023-01-02_000000",
+				"ovnsb_db_bak_2023-01-01_000000",
+			},
+			backupCount: 1,
+			expected:    []string{"ovnnb_db_bak_2023-01-02_000000", "ovnsb_db_bak_2023-01-01_000000"},
+		},
+	}
+	for i, tc := range tests {
+		t.Run(fmt.Sprintf("%d:%s", i, tc.desc), func(t *testing.T) {
+			tmpDir := t.TempDir()
+			for _, backup := range tc.backups {
+				createDbFile(t, filepath.Join(tmpDir, backup))
+			}
+
+			err := pruneBackups(tmpDir, "ovnnb_db_bak_", tc.backupCount)
+			failOnErrorMismatch(t, err, "")
+
+			entries, err := os.ReadDir(tmpDir)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var remaining []string
+			for _, entry := range entries {
+				remaining = append(remaining, entry.Name())
+			}
+			sort.Strings(remaining)
+			if fmt.Sprint(remaining) != fmt.Sprint(tc.expected) {
+				t.Errorf("Expected backups %v, found %v", tc.expected, remaining)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// Package resync lets operators force a full reconciliation of a single
+// controller, e.g. only services, instead of restarting ovnkube. Controllers
+// add a resync function under a name to a Resyncer and resyncs are requested
+// through its HTTP handler, rate limited per controller.
+package resync
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/klog/v2"
+)
+
+// MinInterval is the minimum time between two forced resyncs of the same
+// controller
+const MinInterval = 30 * time.Second
+
+var (
+	// ErrUnknownController is returned when resyncing a controller that is not
+	// registered
+	ErrUnknownController = errors.New("unknown controller")
+	// ErrThrottled is returned when a controller was resynced less than
+	// MinInterval ago
+	ErrThrottled = errors.New("resync throttled")
+)
+
+// Func forces the full reconciliation of a controller. It is called from the
+// HTTP handler, so it should only queue the work for the controller workers.
+type Func func() error
+
+type controller struct {
+	resync  Func
+	limiter *rate.Limiter
+}
+
+// Resyncer holds the resyncable controllers and serves the resync requests
+type Resyncer struct {
+	lock        sync.Mutex
+	controllers map[string]*controller
+}
+
+// NewResyncer returns a Resyncer without controllers
+func NewResyncer() *Resyncer {
+	return &Resyncer{controllers: map[string]*controller{}}
+}
+
+// Add makes the given controller resyncable, replacing any previous
+// controller with the same name
+func (r *Resyncer) Add(name string, resync Func) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.controllers[name] = &controller{
+		resync:  resync,
+		limiter: rate.NewLimiter(rate.Every(MinInterval), 1),
+	}
+}
+
+// Controllers returns the sorted names of the resyncable controllers
+func (r *Resyncer) Controllers() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	names := make([]string, 0, len(r.controllers))
+	for name := range r.controllers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Trigger forces the full reconciliation of the given controller, unless it
+// was already forced less than MinInterval ago
+func (r *Resyncer) Trigger(name string) error {
+	r.lock.Lock()
+	c, ok := r.controllers[name]
+	r.lock.Unlock()
+	if !ok {
+		return fmt.Errorf("%w %q", ErrUnknownController, name)
+	}
+	if !c.limiter.Allow() {
+		return fmt.Errorf("%w: controller %q can be resynced once every %v", ErrThrottled, name, MinInterval)
+	}
+	klog.Infof("Forcing a full resync of controller %s", name)
+	if err := c.resync(); err != nil {
+		return fmt.Errorf("failed to resync controller %q: %w", name, err)
+	}
+	return nil
+}
+
+// ServeHTTP serves the resync requests: a GET lists the controllers and a
+// POST with a controller query parameter forces its resync
+func (r *Resyncer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		fmt.Fprintln(w, strings.Join(r.Controllers(), "\n"))
+	case http.MethodPost:
+		name := req.URL.Query().Get("controller")
+		if name == "" {
+			http.Error(w, "missing controller query parameter", http.StatusBadRequest)
+			return
+		}
+		err := r.Trigger(name)
+		switch {
+		case err == nil:
+			w.WriteHeader(http.StatusAccepted)
+			fmt.Fprintf(w, "resync of controller %q requested\n", name)
+		case errors.Is(err, ErrUnknownController):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, ErrThrottled):
+			w.Header().Set("Retry-After", fmt.Sprintf("%d", int(MinInterval.Seconds())))
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,84 @@
+package resync
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	resyncs := 0
+	resyncer := NewResyncer()
+	resyncer.Add("services", func() error {
+		resyncs++
+		return nil
+	})
+	resyncer.Add("failing", func() error { return fmt.Errorf("boom") })
+
+	tests := []struct {
+		desc            string
+		method          string
+		target          string
+		expectedCode    int
+		expectedResyncs int
+	}{
+		{
+			desc:         "lists the registered controllers",
+			method:       http.MethodGet,
+			target:       "/debug/resync",
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "rejects a resync without controller",
+			method:       http.MethodPost,
+			target:       "/debug/resync",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:         "rejects a resync of an unknown controller",
+			method:       http.MethodPost,
+			target:       "/debug/resync?controller=pods",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			desc:            "resyncs a registered controller",
+			method:          http.MethodPost,
+			target:          "/debug/resync?controller=services",
+			expectedCode:    http.StatusAccepted,
+			expectedResyncs: 1,
+		},
+		{
+			desc:            "throttles a second resync of the same controller",
+			method:          http.MethodPost,
+			target:          "/debug/resync?controller=services",
+			expectedCode:    http.StatusTooManyRequests,
+			expectedResyncs: 1,
+		},
+		{
+			desc:            "reports resync failures",
+			method:          http.MethodPost,
+			target:          "/debug/resync?controller=failing",
+			expectedCode:    http.StatusInternalServerError,
+			expectedResyncs: 1,
+		},
+		{
+			desc:            "rejects other methods",
+			method:          http.MethodDelete,
+			target:          "/debug/resync?controller=services",
+			expectedCode:    http.StatusMethodNotAllowed,
+			expectedResyncs: 1,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			resyncer.ServeHTTP(w, httptest.NewRequest(tc.method, tc.target, nil))
+			assert.Equal(t, tc.expectedCode, w.Code)
+			assert.Equal(t, tc.expectedResyncs, resyncs)
+		})
+	}
+	assert.Equal(t, []string{"failing", "services"}, resyncer.Controllers())
+}
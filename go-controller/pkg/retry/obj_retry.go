@@ -11,18 +11,29 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/cache"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/klog/v2"
 
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/metrics"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/syncmap"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 )
 
 const RetryObjInterval = 30 * time.Second
-const MaxFailedAttempts = 15 // same value used for the services level-driven controller
+const MaxFailedAttempts = 15 // default of config.Default.RetryMaxFailedAttempts, same value used for the services level-driven controller
 const initialBackoff = 1
 const noBackoff = 0
 
+// RetryForever can be set as ResourceHandler.MaxFailedAttempts for resource types
+// that must never be dropped from the retry cache
+const RetryForever = -1
+
+// persistentFailureAttempts is the number of failed retries after which, and
+// then again every as many retries, an error event is recorded on the object
+const persistentFailureAttempts = 3
+
 // retryObjEntry is a generic object caching with retry mechanism
 // that resources can use to eventually complete their intended operations.
 type retryObjEntry struct {
@@ -36,7 +47,9 @@ type retryObjEntry struct {
 	timeStamp  time.Time
 	backoffSec time.Duration
 	// number of times this object has been unsuccessfully added/updated/deleted
-	failedAttempts uint8
+	failedAttempts int
+	// lastErr is the error of the last failed retry
+	lastErr error
 }
 
 type EventHandler interface {
@@ -66,7 +79,11 @@ type ResourceHandler struct {
 	// an add on the new one.
 	HasUpdateFunc          bool
 	NeedsUpdateDuringRetry bool
-	ObjType                reflect.Type
+	// MaxFailedAttempts is the number of failed attempts after which the retry of an object
+	// is given up. When unset, config.Default.RetryMaxFailedAttempts is used; RetryForever
+	// never gives up. config.Default.RetryMaxFailedAttemptsOverrides takes precedence.
+	MaxFailedAttempts int
+	ObjType           reflect.Type
 	EventHandler
 }
 
@@ -110,9 +127,18 @@ func (r *RetryFramework) DoWithLock(key string, f func(key string)) {
 	f(key)
 }
 
-func (r *RetryFramework) initRetryObjWithAddBackoff(obj interface{}, lockedKey string, backoff time.Duration) *retryObjEntry {
+// loadOrStoreRetryObj returns the retry entry of the given key, storing newEntry if there is none
+func (r *RetryFramework) loadOrStoreRetryObj(lockedKey string, newEntry *retryObjEntry) *retryObjEntry {
 	// even if the object was loaded and changed before with the same lock, LoadOrStore will return reference to the same object
-	entry, _ := r.retryEntries.LoadOrStore(lockedKey, &retryObjEntry{backoffSec: backoff})
+	entry, loaded := r.retryEntries.LoadOrStore(lockedKey, newEntry)
+	if !loaded {
+		r.retryEntriesMetric().Inc()
+	}
+	return entry
+}
+
+func (r *RetryFramework) initRetryObjWithAddBackoff(obj interface{}, lockedKey string, backoff time.Duration) *retryObjEntry {
+	entry := r.loadOrStoreRetryObj(lockedKey, &retryObjEntry{backoffSec: backoff})
 	entry.timeStamp = time.Now()
 	entry.newObj = obj
 	entry.failedAttempts = 0
@@ -128,8 +154,7 @@ func (r *RetryFramework) initRetryObjWithAdd(obj interface{}, lockedKey string)
 
 // initRetryObjWithUpdate tracks objects that failed to be updated to potentially retry later
 func (r *RetryFramework) initRetryObjWithUpdate(oldObj, newObj interface{}, lockedKey string) *retryObjEntry {
-	entry, _ := r.retryEntries.LoadOrStore(lockedKey, &retryObjEntry{config: oldObj, backoffSec: initialBackoff})
-	// even if the object was loaded and changed before with the same lock, LoadOrStore will return reference to the same object
+	entry := r.loadOrStoreRetryObj(lockedKey, &retryObjEntry{config: oldObj, backoffSec: initialBackoff})
 	entry.timeStamp = time.Now()
 	entry.newObj = newObj
 	entry.config = oldObj
@@ -143,8 +168,7 @@ func (r *RetryFramework) initRetryObjWithUpdate(oldObj, newObj interface{}, lock
 // and the object is orphaned from the namespace.
 // The noRetryAdd boolean argument is to indicate whether to retry for addition
 func (r *RetryFramework) InitRetryObjWithDelete(obj interface{}, lockedKey string, config interface{}, noRetryAdd bool) *retryObjEntry {
-	// even if the object was loaded and changed before with the same lock, LoadOrStore will return reference to the same object
-	entry, _ := r.retryEntries.LoadOrStore(lockedKey, &retryObjEntry{config: config, backoffSec: initialBackoff})
+	entry := r.loadOrStoreRetryObj(lockedKey, &retryObjEntry{config: config, backoffSec: initialBackoff})
 	entry.timeStamp = time.Now()
 	entry.oldObj = obj
 	if entry.config == nil {
@@ -176,7 +200,36 @@ func (r *RetryFramework) getRetryObj(lockedKey string) (value *retryObjEntry, fo
 }
 
 func (r *RetryFramework) DeleteRetryObj(lockedKey string) {
+	if _, loaded := r.retryEntries.Load(lockedKey); !loaded {
+		return
+	}
 	r.retryEntries.Delete(lockedKey)
+	r.retryEntriesMetric().Dec()
+}
+
+// resourceTypeName returns the name of the resource type, e.g. Pod
+func (r *RetryFramework) resourceTypeName() string {
+	return r.ResourceHandler.ObjType.Elem().Name()
+}
+
+// retryEntriesMetric returns the gauge of the number of retry entries of the resource type
+func (r *RetryFramework) retryEntriesMetric() prometheus.Gauge {
+	return metrics.MetricResourceRetryEntries.WithLabelValues(r.resourceTypeName())
+}
+
+// maxFailedAttempts returns the number of failed attempts after which the retry of an object is given up,
+// or RetryForever
+func (r *RetryFramework) maxFailedAttempts() int {
+	if maxFailedAttempts, ok := config.Default.RetryMaxFailedAttemptsOverrides[r.resourceTypeName()]; ok {
+		if maxFailedAttempts == 0 {
+			return RetryForever
+		}
+		return maxFailedAttempts
+	}
+	if r.ResourceHandler.MaxFailedAttempts != 0 {
+		return r.ResourceHandler.MaxFailedAttempts
+	}
+	return config.Default.RetryMaxFailedAttempts
 }
 
 // setRetryObjWithNoBackoff sets an object's backoff to be retried
@@ -198,6 +251,39 @@ func (r *RetryFramework) increaseFailedAttemptsCounter(entry *retryObjEntry) {
 	entry.failedAttempts++
 }
 
+// failedAttempt records a failed attempt, with the given error, to handle the object
+// of the retry entry of key. The entry is dropped if the error is permanent: the
+// object is handled again when it changes.
+func (r *RetryFramework) failedAttempt(key string, entry *retryObjEntry, err error) {
+	if util.IsPermanentError(err) {
+		klog.Warningf("Not retrying %s %s, the error is permanent: %v", r.ResourceHandler.ObjType, key, err)
+		r.DeleteRetryObj(key)
+		return
+	}
+	r.increaseFailedAttemptsCounter(entry)
+}
+
+// retryFailed records a failed retry of the given entry, posting an error event
+// on obj with the error and the number of failed attempts so far when the
+// failure persists. The entry is dropped if the error is permanent.
+func (r *RetryFramework) retryFailed(key string, entry *retryObjEntry, obj interface{}, err error) {
+	if util.IsPermanentError(err) {
+		klog.Warningf("Dropping retry entry for %s %s, the error is permanent: %v", r.ResourceHandler.ObjType, key, err)
+		r.DeleteRetryObj(key)
+		if obj != nil {
+			r.ResourceHandler.RecordErrorEvent(obj, "RetryFailed", err)
+		}
+		return
+	}
+	entry.timeStamp = time.Now()
+	entry.failedAttempts++
+	entry.lastErr = err
+	if obj != nil && entry.failedAttempts%persistentFailureAttempts == 0 {
+		r.ResourceHandler.RecordErrorEvent(obj, "RetryFailing",
+			fmt.Errorf("failed to reconcile after %d attempts, will retry: %v", entry.failedAttempts, err))
+	}
+}
+
 // RequestRetryFramework allows a caller to immediately request to iterate through all objects that
 // are in the retry cache. This will ignore any outstanding time wait/backoff state
 func (r *RetryFramework) RequestRetryObjs() {
@@ -228,14 +314,15 @@ func (r *RetryFramework) resourceRetry(objKey string, now time.Time) {
 			return
 		}
 
-		if entry.failedAttempts >= MaxFailedAttempts {
+		if maxFailedAttempts := r.maxFailedAttempts(); maxFailedAttempts != RetryForever && entry.failedAttempts >= maxFailedAttempts {
 			klog.Warningf("Dropping retry entry for %s %s: exceeded number of failed attempts",
 				r.ResourceHandler.ObjType, objKey)
 			r.DeleteRetryObj(key)
 			metrics.MetricResourceRetryFailuresCount.Inc()
 			if entry.newObj != nil {
 				r.ResourceHandler.RecordErrorEvent(entry.newObj, "RetryFailed",
-					fmt.Errorf("failed to reconcile and retried %d times for object: %v", MaxFailedAttempts, entry.newObj))
+					fmt.Errorf("failed to reconcile and retried %d times for object: %v, last error: %v",
+						maxFailedAttempts, entry.newObj, entry.lastErr))
 			}
 			return
 		}
@@ -254,8 +341,8 @@ func (r *RetryFramework) resourceRetry(objKey string, now time.Time) {
 
 		// update backoff for future attempts in case of failure
 		entry.backoffSec = entry.backoffSec * 2
-		if entry.backoffSec > 60 {
-			entry.backoffSec = 60
+		if maxBackoff := time.Duration(config.Default.RetryMaxBackoff); entry.backoffSec > maxBackoff {
+			entry.backoffSec = maxBackoff
 		}
 
 		// storing original obj for metrics
@@ -289,8 +376,7 @@ func (r *RetryFramework) resourceRetry(objKey string, now time.Time) {
 			klog.Infof("%v retry: updating object %s", r.ResourceHandler.ObjType, objKey)
 			if err := r.ResourceHandler.UpdateResource(entry.config, entry.newObj, true); err != nil {
 				klog.Infof("%v retry update failed for %s, will try again later: %v", r.ResourceHandler.ObjType, objKey, err)
-				entry.timeStamp = time.Now()
-				entry.failedAttempts++
+				r.retryFailed(key, entry, entry.newObj, err)
 				return
 			}
 			// successfully cleaned up new and old object, remove it from the retry cache
@@ -309,9 +395,8 @@ func (r *RetryFramework) resourceRetry(objKey string, now time.Time) {
 				if err := r.ResourceHandler.DeleteResource(entry.oldObj, entry.config); err != nil {
 					klog.Infof("Retry delete failed for %s %s, will try again later: %v",
 						r.ResourceHandler.ObjType, objKey, err)
-
-					entry.timeStamp = time.Now()
-					entry.failedAttempts++
+					// the event is only posted if the object still exists
+					r.retryFailed(key, entry, entry.newObj, err)
 					return
 				}
 				// successfully cleaned up old object, remove it from the retry cache
@@ -328,8 +413,7 @@ func (r *RetryFramework) resourceRetry(objKey string, now time.Time) {
 				}
 				if err := r.ResourceHandler.AddResource(entry.newObj, true); err != nil {
 					klog.Infof("Retry add failed for %s %s, will try again later: %v", r.ResourceHandler.ObjType, objKey, err)
-					entry.timeStamp = time.Now()
-					entry.failedAttempts++
+					r.retryFailed(key, entry, entry.newObj, err)
 					return
 				}
 				// successfully cleaned up new object, remove it from the retry cache
@@ -492,7 +576,7 @@ func (r *RetryFramework) WatchResourceFiltered(namespaceForFilteredHandler strin
 					if err := r.ResourceHandler.AddResource(obj, false); err != nil {
 						klog.Errorf("Failed to create %s %s, error: %v", r.ResourceHandler.ObjType, key, err)
 						r.ResourceHandler.RecordErrorEvent(obj, "ErrorAddingResource", err)
-						r.increaseFailedAttemptsCounter(retryObj)
+						r.failedAttempt(key, retryObj, err)
 						return
 					}
 					klog.Infof("Creating %s %s took: %v", r.ResourceHandler.ObjType, key, time.Since(start))
@@ -633,14 +717,14 @@ func (r *RetryFramework) WatchResourceFiltered(namespaceForFilteredHandler strin
 							} else {
 								retryEntry = r.initRetryObjWithAdd(latest, key)
 							}
-							r.increaseFailedAttemptsCounter(retryEntry)
+							r.failedAttempt(key, retryEntry, err)
 							return
 						}
 					} else { // we previously deleted old object, now let's add the new one
 						if err := r.ResourceHandler.AddResource(latest, false); err != nil {
 							r.ResourceHandler.RecordErrorEvent(latest, "ErrorAddingResource", err)
 							retryEntry := r.initRetryObjWithAdd(latest, key)
-							r.increaseFailedAttemptsCounter(retryEntry)
+							r.failedAttempt(key, retryEntry, err)
 							klog.Errorf("Failed to add %s %s, during update: %v",
 								r.ResourceHandler.ObjType, newKey, err)
 							return
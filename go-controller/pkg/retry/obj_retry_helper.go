@@ -67,7 +67,7 @@ func GetConfigFromRetryObj(key string, r *RetryFramework) interface{} {
 	return nil
 }
 
-func SetFailedAttemptsCounterForTestingOnly(key string, val uint8, r *RetryFramework) {
+func SetFailedAttemptsCounterForTestingOnly(key string, val int, r *RetryFramework) {
 	r.DoWithLock(key, func(key string) {
 		entry, found := r.getRetryObj(key)
 		if found {
@@ -0,0 +1,143 @@
+package retry
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	kapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
+)
+
+// failingEventHandler is an EventHandler whose adds always fail
+type failingEventHandler struct {
+	obj         interface{}
+	errorEvents []string
+}
+
+func (h *failingEventHandler) AddResource(interface{}, bool) error {
+	return fmt.Errorf("add failed")
+}
+func (h *failingEventHandler) UpdateResource(interface{}, interface{}, bool) error { return nil }
+func (h *failingEventHandler) DeleteResource(interface{}, interface{}) error       { return nil }
+func (h *failingEventHandler) SyncFunc([]interface{}) error                        { return nil }
+func (h *failingEventHandler) GetResourceFromInformerCache(string) (interface{}, error) {
+	return h.obj, nil
+}
+func (h *failingEventHandler) AreResourcesEqual(interface{}, interface{}) (bool, error) {
+	return false, nil
+}
+func (h *failingEventHandler) GetInternalCacheEntry(interface{}) interface{} { return nil }
+func (h *failingEventHandler) IsResourceScheduled(interface{}) bool          { return true }
+func (h *failingEventHandler) IsObjectInTerminalState(interface{}) bool      { return false }
+func (h *failingEventHandler) RecordAddEvent(interface{})                    {}
+func (h *failingEventHandler) RecordUpdateEvent(interface{})                 {}
+func (h *failingEventHandler) RecordDeleteEvent(interface{})                 {}
+func (h *failingEventHandler) RecordSuccessEvent(interface{})                {}
+func (h *failingEventHandler) RecordErrorEvent(_ interface{}, reason string, _ error) {
+	h.errorEvents = append(h.errorEvents, reason)
+}
+
+// newFailingPodRetry returns a retry framework of pods whose adds always fail, with a pod
+// in the retry cache
+func newFailingPodRetry(maxFailedAttempts int) (*RetryFramework, *failingEventHandler, string) {
+	pod := &kapi.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pod"}}
+	handler := &failingEventHandler{obj: pod}
+	r := NewRetryFramework(make(chan struct{}), &sync.WaitGroup{}, nil, &ResourceHandler{
+		MaxFailedAttempts: maxFailedAttempts,
+		ObjType:           factory.PodType,
+		EventHandler:      handler,
+	})
+	key := "ns/pod"
+	r.DoWithLock(key, func(key string) {
+		r.initRetryObjWithAdd(pod, key)
+	})
+	return r, handler, key
+}
+
+// retryFailedAttempts retries the given key, past its backoff, and returns the failed
+// attempts and backoff of its entry, or false if the entry was dropped
+func retryFailedAttempts(r *RetryFramework, key string) (int, time.Duration, bool) {
+	r.resourceRetry(key, time.Now().Add(time.Hour))
+	entry, found := r.getRetryObj(key)
+	if !found {
+		return 0, 0, false
+	}
+	return entry.failedAttempts, entry.backoffSec, true
+}
+
+func TestResourceRetryMaxBackoff(t *testing.T) {
+	assert.NoError(t, config.PrepareTestConfig())
+	config.Default.RetryMaxBackoff = 5
+	r, _, key := newFailingPodRetry(0)
+
+	for _, expectedBackoff := range []time.Duration{2, 4, 5, 5} {
+		_, backoff, found := retryFailedAttempts(r, key)
+		assert.True(t, found)
+		assert.Equal(t, expectedBackoff, backoff)
+	}
+}
+
+func TestResourceRetryMaxFailedAttempts(t *testing.T) {
+	tests := []struct {
+		desc                 string
+		handlerMaxAttempts   int
+		overrides            map[string]int
+		expectedMaxAttempts  int
+		expectedRetryForever bool
+	}{
+		{
+			desc:                "uses the configured default",
+			expectedMaxAttempts: 3,
+		},
+		{
+			desc:                "uses the limit of the resource type",
+			handlerMaxAttempts:  5,
+			expectedMaxAttempts: 5,
+		},
+		{
+			desc:                 "retries the resource type forever",
+			handlerMaxAttempts:   RetryForever,
+			expectedRetryForever: true,
+		},
+		{
+			desc:                "uses the configured override of the resource type",
+			handlerMaxAttempts:  RetryForever,
+			overrides:           map[string]int{"Pod": 4, "Node": 1},
+			expectedMaxAttempts: 4,
+		},
+		{
+			desc:                 "retries forever when configured",
+			overrides:            map[string]int{"Pod": 0},
+			expectedRetryForever: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.NoError(t, config.PrepareTestConfig())
+			config.Default.RetryMaxFailedAttempts = 3
+			config.Default.RetryMaxFailedAttemptsOverrides = tc.overrides
+			r, handler, key := newFailingPodRetry(tc.handlerMaxAttempts)
+
+			attempts := 20
+			if !tc.expectedRetryForever {
+				attempts = tc.expectedMaxAttempts
+			}
+			for i := 1; i <= attempts; i++ {
+				failedAttempts, _, found := retryFailedAttempts(r, key)
+				assert.True(t, found)
+				assert.Equal(t, i, failedAttempts)
+			}
+			_, _, found := retryFailedAttempts(r, key)
+			assert.Equal(t, tc.expectedRetryForever, found)
+			if !tc.expectedRetryForever {
+				assert.Contains(t, handler.errorEvents, "RetryFailed")
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+// Package fixtures runs declarative regression cases against controllers that
+// write to the OVN northbound database.
+//
+// A fixture is a YAML file describing the Kubernetes objects a controller is
+// started with, the initial content of the NB database, and the NB rows that
+// are expected once the controller has reconciled. Expected rows are looked up
+// by their ObjectIDs external ids rather than by UUID, and only the columns
+// listed in the fixture are compared, e.g.
+//
+//	name: namespace address set
+//	kubernetes:
+//	- apiVersion: v1
+//	  kind: Namespace
+//	  metadata:
+//	    name: namespace1
+//	nbData:
+//	- table: Address_Set
+//	  uuid: stale-as
+//	  columns:
+//	    name: a8852273716019508314
+//	    external_ids:
+//	      k8s.ovn.org/owner-controller: default-network-controller
+//	      k8s.ovn.org/owner-type: Namespace
+//	      k8s.ovn.org/name: stale
+//	      ip-family: v4
+//	expected:
+//	- table: Address_Set
+//	  objectIDs:
+//	    ownerController: default-network-controller
+//	    ownerType: Namespace
+//	    ids:
+//	      k8s.ovn.org/name: namespace1
+//	      ip-family: v4
+//	  columns:
+//	    addresses: []
+//	- table: Address_Set
+//	  absent: true
+//	  objectIDs:
+//	    ownerController: default-network-controller
+//	    ownerType: Namespace
+//	    ids:
+//	      k8s.ovn.org/name: stale
+//
+// Column values use the OVSDB column names and the JSON encoding of the
+// corresponding nbdb model field. Set columns are compared regardless of order.
+package fixtures
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ovn-org/libovsdb/model"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+
+	egressfirewallv1 "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1"
+	egressipv1 "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1"
+	egressqosv1 "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1"
+	egressservicev1 "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressservice/v1"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/libovsdbops"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
+	libovsdbtest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing/libovsdb"
+)
+
+// Fixture is a declarative regression case for a controller
+type Fixture struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Kubernetes holds the manifests of the objects the controller is started with
+	Kubernetes []json.RawMessage `json:"kubernetes,omitempty"`
+	// NBData holds the rows the NB database is populated with before the
+	// controller is started
+	NBData []Row `json:"nbData,omitempty"`
+	// Expected holds the rows that must be found in the NB database once the
+	// controller has reconciled
+	Expected []ExpectedRow `json:"expected"`
+
+	path string
+}
+
+// Row is a NB database row
+type Row struct {
+	Table string `json:"table"`
+	// UUID may be a real UUID or a name that other rows use to reference this one
+	UUID    string                     `json:"uuid,omitempty"`
+	Columns map[string]json.RawMessage `json:"columns,omitempty"`
+}
+
+// ObjectIDs identifies a row by the external ids set by libovsdbops.DbObjectIDs.
+// Only the given ids are matched, so that a partial set of ids can be used in
+// the same way as a predicate search.
+type ObjectIDs struct {
+	OwnerController string            `json:"ownerController"`
+	OwnerType       string            `json:"ownerType"`
+	IDs             map[string]string `json:"ids,omitempty"`
+}
+
+// ExpectedRow describes a row that must be present in the NB database
+type ExpectedRow struct {
+	Table     string    `json:"table"`
+	ObjectIDs ObjectIDs `json:"objectIDs"`
+	// Columns holds the values of the columns to compare, other columns are ignored
+	Columns map[string]json.RawMessage `json:"columns,omitempty"`
+	// Absent requires that no row with the given ObjectIDs exists
+	Absent bool `json:"absent,omitempty"`
+}
+
+var (
+	nbDBModel model.DatabaseModel
+	codecs    serializer.CodecFactory
+)
+
+func init() {
+	clientDBModel, err := nbdb.FullDatabaseModel()
+	if err != nil {
+		panic(err)
+	}
+	var errs []error
+	nbDBModel, errs = model.NewDatabaseModel(nbdb.Schema(), clientDBModel)
+	if len(errs) > 0 {
+		panic(errs[0])
+	}
+
+	scheme := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{
+		clientgoscheme.AddToScheme,
+		egressfirewallv1.AddToScheme,
+		egressipv1.AddToScheme,
+		egressqosv1.AddToScheme,
+		egressservicev1.AddToScheme,
+	} {
+		if err := addToScheme(scheme); err != nil {
+			panic(err)
+		}
+	}
+	codecs = serializer.NewCodecFactory(scheme)
+}
+
+// Load reads the fixture from the YAML file at path
+func Load(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f := &Fixture{path: path}
+	if err := yaml.UnmarshalStrict(data, f); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+	if f.Name == "" {
+		f.Name = filepath.Base(path)
+	}
+	for i, row := range f.Expected {
+		if row.Table == "" || row.ObjectIDs.OwnerController == "" || row.ObjectIDs.OwnerType == "" {
+			return nil, fmt.Errorf("fixture %s: expected row %d must set table, ownerController and ownerType", path, i)
+		}
+	}
+	return f, nil
+}
+
+// LoadDir reads all the *.yaml fixtures in dir, sorted by file name
+func LoadDir(dir string) ([]*Fixture, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	fixtures := make([]*Fixture, 0, len(paths))
+	for _, path := range paths {
+		f, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		fixtures = append(fixtures, f)
+	}
+	return fixtures, nil
+}
+
+func (f *Fixture) String() string {
+	if f.path == "" {
+		return f.Name
+	}
+	return fmt.Sprintf("%s (%s)", f.Name, f.path)
+}
+
+// KubernetesObjects decodes the Kubernetes manifests of the fixture
+func (f *Fixture) KubernetesObjects() ([]runtime.Object, error) {
+	decoder := codecs.UniversalDeserializer()
+	objects := make([]runtime.Object, 0, len(f.Kubernetes))
+	for i, manifest := range f.Kubernetes {
+		obj, _, err := decoder.Decode(manifest, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("fixture %s: failed to decode kubernetes object %d: %w", f, i, err)
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// NBTestData converts the initial NB rows of the fixture to nbdb models that
+// can be used to set up a libovsdb test harness
+func (f *Fixture) NBTestData() ([]libovsdbtest.TestData, error) {
+	data := make([]libovsdbtest.TestData, 0, len(f.NBData))
+	for i, row := range f.NBData {
+		m, err := nbDBModel.NewModel(row.Table)
+		if err != nil {
+			return nil, fmt.Errorf("fixture %s: nbData row %d: %w", f, i, err)
+		}
+		info, err := nbDBModel.NewModelInfo(m)
+		if err != nil {
+			return nil, fmt.Errorf("fixture %s: nbData row %d: %w", f, i, err)
+		}
+		if row.UUID != "" {
+			if err := info.SetField("_uuid", row.UUID); err != nil {
+				return nil, fmt.Errorf("fixture %s: nbData row %d: %w", f, i, err)
+			}
+		}
+		for column, raw := range row.Columns {
+			value, err := decodeColumn(info.Obj, info.Metadata.Fields, column, raw)
+			if err != nil {
+				return nil, fmt.Errorf("fixture %s: nbData row %d: %w", f, i, err)
+			}
+			if err := info.SetField(column, value); err != nil {
+				return nil, fmt.Errorf("fixture %s: nbData row %d: %w", f, i, err)
+			}
+		}
+		data = append(data, m)
+	}
+	return data, nil
+}
+
+// externalIDs returns the external ids a row must have to match o
+func (o ObjectIDs) externalIDs() map[string]string {
+	ids := map[string]string{
+		libovsdbops.OwnerControllerKey.String(): o.OwnerController,
+		libovsdbops.OwnerTypeKey.String():       o.OwnerType,
+	}
+	for k, v := range o.IDs {
+		ids[k] = v
+	}
+	return ids
+}
@@ -0,0 +1,182 @@
+package fixtures
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	libovsdbclient "github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/libovsdb/model"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	libovsdbtest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing/libovsdb"
+)
+
+// DefaultTimeout is how long Run waits for the NB database to converge to the
+// expected rows
+const DefaultTimeout = 5 * time.Second
+
+// Controller adapts a controller under test so that fixtures can be run against it
+type Controller interface {
+	// Start runs the controller with the given Kubernetes objects and initial
+	// NB database content, and returns a client of that NB database
+	Start(objects []runtime.Object, nbData []libovsdbtest.TestData) (libovsdbclient.Client, error)
+	// Stop stops the controller and releases the resources allocated by Start
+	Stop()
+}
+
+// Run starts ctrl with the inputs of the fixture and waits until the NB
+// database holds the expected rows. The returned error describes the
+// mismatches found on the last attempt.
+func (f *Fixture) Run(ctrl Controller) error {
+	objects, err := f.KubernetesObjects()
+	if err != nil {
+		return err
+	}
+	nbData, err := f.NBTestData()
+	if err != nil {
+		return err
+	}
+	nbClient, err := ctrl.Start(objects, nbData)
+	if err != nil {
+		return fmt.Errorf("fixture %s: failed to start controller: %w", f, err)
+	}
+	defer ctrl.Stop()
+
+	var verifyErr error
+	err = wait.PollImmediate(100*time.Millisecond, DefaultTimeout, func() (bool, error) {
+		verifyErr = f.Verify(nbClient)
+		return verifyErr == nil, nil
+	})
+	if err != nil {
+		return verifyErr
+	}
+	return nil
+}
+
+// Verify checks the content of the NB database against the expected rows of
+// the fixture, and returns an error listing every mismatch
+func (f *Fixture) Verify(nbClient libovsdbclient.Client) error {
+	var mismatches []string
+	for i, expected := range f.Expected {
+		if err := expected.verify(nbClient); err != nil {
+			mismatches = append(mismatches, fmt.Sprintf("expected row %d: %v", i, err))
+		}
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("fixture %s:\n%s", f, strings.Join(mismatches, "\n"))
+	}
+	return nil
+}
+
+func (e ExpectedRow) verify(nbClient libovsdbclient.Client) error {
+	table := nbClient.Cache().Table(e.Table)
+	if table == nil {
+		return fmt.Errorf("table %s not found", e.Table)
+	}
+	ids := e.ObjectIDs.externalIDs()
+	var matches []model.Model
+	for _, m := range table.Rows() {
+		info, err := nbDBModel.NewModelInfo(m)
+		if err != nil {
+			return err
+		}
+		value, err := info.FieldByColumn("external_ids")
+		if err != nil {
+			return err
+		}
+		if hasExternalIDs(value.(map[string]string), ids) {
+			matches = append(matches, m)
+		}
+	}
+
+	if e.Absent {
+		if len(matches) > 0 {
+			return fmt.Errorf("%s row with ids %v should not exist, found %d", e.Table, ids, len(matches))
+		}
+		return nil
+	}
+	if len(matches) != 1 {
+		return fmt.Errorf("expected exactly one %s row with ids %v, found %d", e.Table, ids, len(matches))
+	}
+
+	info, err := nbDBModel.NewModelInfo(matches[0])
+	if err != nil {
+		return err
+	}
+	var diffs []string
+	columns := make([]string, 0, len(e.Columns))
+	for column := range e.Columns {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	for _, column := range columns {
+		expected, err := decodeColumn(info.Obj, info.Metadata.Fields, column, e.Columns[column])
+		if err != nil {
+			return err
+		}
+		actual, err := info.FieldByColumn(column)
+		if err != nil {
+			return err
+		}
+		if !columnEqual(expected, actual) {
+			diffs = append(diffs, fmt.Sprintf("column %s: expected %v, got %v", column, expected, actual))
+		}
+	}
+	if len(diffs) > 0 {
+		return fmt.Errorf("%s row with ids %v differs:\n  %s", e.Table, ids, strings.Join(diffs, "\n  "))
+	}
+	return nil
+}
+
+func hasExternalIDs(externalIDs, ids map[string]string) bool {
+	for k, v := range ids {
+		if externalIDs[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// decodeColumn decodes raw into a value of the type of the model field mapped
+// to column
+func decodeColumn(obj interface{}, fields map[string]string, column string, raw json.RawMessage) (interface{}, error) {
+	fieldName, ok := fields[column]
+	if !ok {
+		return nil, fmt.Errorf("column %s not found", column)
+	}
+	field, _ := reflect.TypeOf(obj).Elem().FieldByName(fieldName)
+	value := reflect.New(field.Type)
+	if err := json.Unmarshal(raw, value.Interface()); err != nil {
+		return nil, fmt.Errorf("failed to decode column %s: %w", column, err)
+	}
+	return value.Elem().Interface(), nil
+}
+
+// columnEqual compares column values, treating slices as sets and empty
+// slices and maps as equal to nil ones
+func columnEqual(expected, actual interface{}) bool {
+	e, a := reflect.ValueOf(expected), reflect.ValueOf(actual)
+	switch e.Kind() {
+	case reflect.Slice:
+		return reflect.DeepEqual(sortedElems(e), sortedElems(a))
+	case reflect.Map:
+		if e.Len() == 0 && a.Len() == 0 {
+			return true
+		}
+	}
+	return reflect.DeepEqual(expected, actual)
+}
+
+func sortedElems(v reflect.Value) []string {
+	elems := make([]string, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		elems = append(elems, fmt.Sprintf("%v", v.Index(i).Interface()))
+	}
+	sort.Strings(elems)
+	return elems
+}
@@ -0,0 +1,122 @@
+// Package snapshot loads the watch factory snapshots served by the
+// /debug/snapshot/<cache> handlers of ovnkube into fake clientsets, so that an
+// issue seen on a cluster can be reproduced locally, in a unit test, against
+// the cluster state the controllers acted on.
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	mnpfake "github.com/k8snetworkplumbingwg/multi-networkpolicy/pkg/client/clientset/versioned/fake"
+	ocpcloudnetworkfake "github.com/openshift/client-go/cloudnetwork/clientset/versioned/fake"
+
+	anpfake "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1alpha1/apis/clientset/versioned/fake"
+	egressfirewallfake "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1/apis/clientset/versioned/fake"
+	egressipfake "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1/apis/clientset/versioned/fake"
+	egressqosfake "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1/apis/clientset/versioned/fake"
+	egressservicefake "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressservice/v1/apis/clientset/versioned/fake"
+	ipamclaimsfake "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/ipamclaims/v1alpha1/apis/clientset/versioned/fake"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// Load reads the watch factory snapshot stored in the given file
+func Load(path string) (*factory.Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", path, err)
+	}
+	snapshot := &factory.Snapshot{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s: %w", path, err)
+	}
+	return snapshot, nil
+}
+
+// NewMasterClientset returns fake clientsets holding the objects of the given snapshot
+func NewMasterClientset(snapshot *factory.Snapshot) *util.OVNMasterClientset {
+	var kubeObjects, egressFirewallObjects, egressIPObjects, cloudNetworkObjects, egressQoSObjects,
+		egressServiceObjects, anpObjects, ipamClaimObjects, mnpObjects []runtime.Object
+	for _, obj := range snapshot.Pods {
+		kubeObjects = append(kubeObjects, obj)
+	}
+	for _, obj := range snapshot.Services {
+		kubeObjects = append(kubeObjects, obj)
+	}
+	for _, obj := range snapshot.EndpointSlices {
+		kubeObjects = append(kubeObjects, obj)
+	}
+	for _, obj := range snapshot.NetworkPolicies {
+		kubeObjects = append(kubeObjects, obj)
+	}
+	for _, obj := range snapshot.Namespaces {
+		kubeObjects = append(kubeObjects, obj)
+	}
+	for _, obj := range snapshot.Nodes {
+		kubeObjects = append(kubeObjects, obj)
+	}
+	for _, obj := range snapshot.EgressFirewalls {
+		egressFirewallObjects = append(egressFirewallObjects, obj)
+	}
+	for _, obj := range snapshot.EgressIPs {
+		egressIPObjects = append(egressIPObjects, obj)
+	}
+	for _, obj := range snapshot.CloudPrivateIPConfigs {
+		cloudNetworkObjects = append(cloudNetworkObjects, obj)
+	}
+	for _, obj := range snapshot.EgressQoSes {
+		egressQoSObjects = append(egressQoSObjects, obj)
+	}
+	for _, obj := range snapshot.EgressServices {
+		egressServiceObjects = append(egressServiceObjects, obj)
+	}
+	for _, obj := range snapshot.AdminNetworkPolicies {
+		anpObjects = append(anpObjects, obj)
+	}
+	for _, obj := range snapshot.BaselineAdminNetworkPolicies {
+		anpObjects = append(anpObjects, obj)
+	}
+	for _, obj := range snapshot.IPAMClaims {
+		ipamClaimObjects = append(ipamClaimObjects, obj)
+	}
+	for _, obj := range snapshot.MultiNetworkPolicies {
+		mnpObjects = append(mnpObjects, obj)
+	}
+
+	return &util.OVNMasterClientset{
+		KubeClient:               fake.NewSimpleClientset(kubeObjects...),
+		EgressIPClient:           egressipfake.NewSimpleClientset(egressIPObjects...),
+		EgressFirewallClient:     egressfirewallfake.NewSimpleClientset(egressFirewallObjects...),
+		CloudNetworkClient:       ocpcloudnetworkfake.NewSimpleClientset(cloudNetworkObjects...),
+		EgressQoSClient:          egressqosfake.NewSimpleClientset(egressQoSObjects...),
+		MultiNetworkPolicyClient: mnpfake.NewSimpleClientset(mnpObjects...),
+		EgressServiceClient:      egressservicefake.NewSimpleClientset(egressServiceObjects...),
+		ANPClient:                anpfake.NewSimpleClientset(anpObjects...),
+		IPAMClaimsClient:         ipamclaimsfake.NewSimpleClientset(ipamClaimObjects...),
+	}
+}
+
+// NewMasterWatchFactory loads the snapshot stored in the given file into a master watch factory backed by fake
+// clientsets, and starts it. As for ovnkube, the watch factory only watches the types of the features enabled in the
+// config, so the config must be set up like on the cluster the snapshot was taken on before calling it.
+func NewMasterWatchFactory(path string) (*factory.WatchFactory, *util.OVNMasterClientset, error) {
+	snapshot, err := Load(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	clientset := NewMasterClientset(snapshot)
+	wf, err := factory.NewMasterWatchFactory(clientset)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := wf.Start(); err != nil {
+		wf.Shutdown()
+		return nil, nil, err
+	}
+	return wf, clientset, nil
+}
@@ -0,0 +1,87 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
+
+	kapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	g := gomega.NewWithT(t)
+	g.Expect(config.PrepareTestConfig()).To(gomega.Succeed())
+
+	pod := &kapi.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod1",
+			Namespace: "ns1",
+			Annotations: map[string]string{
+				"k8s.ovn.org/pod-networks":                         `{"default":{}}`,
+				"kubectl.kubernetes.io/last-applied-configuration": `{"spec":{}}`,
+			},
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+		},
+		Spec: kapi.PodSpec{
+			NodeName: "node1",
+			Containers: []kapi.Container{{
+				Name:    "app",
+				Command: []string{"app", "--password=secret"},
+				Env:     []kapi.EnvVar{{Name: "PASSWORD", Value: "secret"}},
+				Ports:   []kapi.ContainerPort{{Name: "http", ContainerPort: 8080}},
+			}},
+		},
+	}
+	node := &kapi.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+		Status:     kapi.NodeStatus{Images: []kapi.ContainerImage{{Names: []string{"app:latest"}}}},
+	}
+	namespace := &kapi.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1"}}
+
+	wf, err := factory.NewMasterWatchFactory(NewMasterClientset(&factory.Snapshot{
+		Pods:       []*kapi.Pod{pod},
+		Nodes:      []*kapi.Node{node},
+		Namespaces: []*kapi.Namespace{namespace},
+	}))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(wf.Start()).To(gomega.Succeed())
+	defer wf.Shutdown()
+
+	snapshot, err := wf.Snapshot()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(snapshot.Pods).To(gomega.HaveLen(1))
+	g.Expect(snapshot.Nodes).To(gomega.HaveLen(1))
+	g.Expect(snapshot.Namespaces).To(gomega.HaveLen(1))
+
+	// the snapshot is sanitized, the informer cache is not changed
+	g.Expect(snapshot.Pods[0].ManagedFields).To(gomega.BeNil())
+	g.Expect(snapshot.Pods[0].Annotations).To(gomega.Equal(map[string]string{"k8s.ovn.org/pod-networks": `{"default":{}}`}))
+	g.Expect(snapshot.Pods[0].Spec.Containers[0].Command).To(gomega.BeNil())
+	g.Expect(snapshot.Pods[0].Spec.Containers[0].Env).To(gomega.BeNil())
+	g.Expect(snapshot.Pods[0].Spec.Containers[0].Ports).To(gomega.Equal(pod.Spec.Containers[0].Ports))
+	g.Expect(snapshot.Nodes[0].Status.Images).To(gomega.BeNil())
+	cachedPod, err := wf.GetPod("ns1", "pod1")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(cachedPod.Spec.Containers[0].Env).To(gomega.Equal(pod.Spec.Containers[0].Env))
+
+	data, err := json.Marshal(snapshot)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	path := filepath.Join(t.TempDir(), "master-snapshot.json")
+	g.Expect(os.WriteFile(path, data, 0o600)).To(gomega.Succeed())
+
+	loadedWF, _, err := NewMasterWatchFactory(path)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	defer loadedWF.Shutdown()
+	loadedPod, err := loadedWF.GetPod("ns1", "pod1")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(loadedPod.Spec).To(gomega.Equal(snapshot.Pods[0].Spec))
+	_, err = loadedWF.GetNode("node1")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+}
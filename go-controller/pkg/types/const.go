@@ -61,6 +61,9 @@ const (
 
 	// ACL Priorities
 
+	// Localnet guard acl rule priority, above any policy so that the guarded traffic
+	// can't be allowed back
+	LocalnetGuardPriority = 31000
 	// Default routed multicast allow acl rule priority
 	DefaultRoutedMcastAllowPriority = 1013
 	// Default multicast allow acl rule priority
@@ -71,6 +74,16 @@ const (
 	DefaultAllowPriority = 1001
 	// Default deny acl rule priority
 	DefaultDenyPriority = 1000
+	// AdminNetworkPolicy acl rule priorities start here and decrease by
+	// AdminNetworkPolicyMaxRules for every AdminNetworkPolicy priority value,
+	// up to AdminNetworkPolicyMaxPriority
+	AdminNetworkPolicyStartPriority = 30000
+	AdminNetworkPolicyMaxPriority   = 99
+	AdminNetworkPolicyMaxRules      = 100
+	// BaselineAdminNetworkPolicy acl rule priorities start right below the
+	// network policy default deny, so that they only apply to pods that are
+	// not isolated by any network policy
+	BaselineAdminNetworkPolicyStartPriority = DefaultDenyPriority - 1
 
 	// priority of logical router policies on the OVNClusterRouter
 	EgressFirewallStartPriority           = 10000
@@ -84,6 +97,10 @@ const (
 	EgressSVCReroutePriority              = 101
 	EgressIPReroutePriority               = 100
 
+	// priority of the QoS rules metering the egress bandwidth of a namespace,
+	// EgressQoS rules only mark DSCP so they never compete with it
+	NamespaceEgressBandwidthPriority = 2000
+
 	V6NodeLocalNATSubnet           = "fd99::/64"
 	V6NodeLocalNATSubnetPrefix     = 64
 	V6NodeLocalNATSubnetNextHop    = "fd99::1"
@@ -186,9 +203,25 @@ const (
 	Layer2Topology   = "layer2"
 	LocalnetTopology = "localnet"
 
+	// ServicePreserveSourceIPAnnotation is set to "true" on a NodePort or
+	// LoadBalancer service to preserve the client source IP of external traffic
+	// in shared gateway mode without setting ExternalTrafficPolicy=Local. Only
+	// the traffic to pod endpoints local to the ingress node is affected.
+	ServicePreserveSourceIPAnnotation = OvnK8sPrefix + "/" + "preserve-source-ip"
+
+	// ServiceTopologyModeAnnotation and its deprecated form enable topology
+	// aware routing for a service when set to "Auto": the EndpointSlice
+	// controller then hints each endpoint with the zones it should serve.
+	ServiceTopologyModeAnnotation       = "service.kubernetes.io/topology-mode"
+	ServiceTopologyAwareHintsAnnotation = "service.kubernetes.io/topology-aware-hints"
+
 	// db index keys
 	// PrimaryIDKey is used as a primary client index
 	PrimaryIDKey = OvnK8sPrefix + "/id"
+	// OwnerControllerKey, OwnerTypeKey and ObjectNameKey are used as secondary client indexes
+	OwnerControllerKey = OvnK8sPrefix + "/owner-controller"
+	OwnerTypeKey       = OvnK8sPrefix + "/owner-type"
+	ObjectNameKey      = OvnK8sPrefix + "/name"
 
 	OvnDefaultZone = "global"
 )
@@ -0,0 +1,75 @@
+package util
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+
+	utilnet "k8s.io/utils/net"
+)
+
+// Components exchange dual-stack addresses through annotations, load balancer
+// VIPs and address sets. Whenever an ordered list of addresses is written, it
+// must be sorted with the primary IP family of the cluster first, so that
+// consumers picking the first address of a list get the same family
+// everywhere.
+
+// IsIPv6PrimaryFamily returns true if IPv6 is the primary IP family of the
+// cluster: the family of the first cluster subnet, or IPv6 in a single stack
+// IPv6 cluster without cluster subnets.
+func IsIPv6PrimaryFamily() bool {
+	if len(config.Default.ClusterSubnets) > 0 {
+		return utilnet.IsIPv6CIDR(config.Default.ClusterSubnets[0].CIDR)
+	}
+	return config.IPv6Mode && !config.IPv4Mode
+}
+
+// sortByFamily stably sorts a list whose i-th element's family is given by
+// isIPv6(i), putting the primary family first
+func sortByFamily(list interface{}, isIPv6 func(i int) bool) {
+	primaryIsIPv6 := IsIPv6PrimaryFamily()
+	sort.SliceStable(list, func(i, j int) bool {
+		return isIPv6(i) == primaryIsIPv6 && isIPv6(j) != primaryIsIPv6
+	})
+}
+
+// SortIPsByFamily sorts ips in place with the IPs of the primary IP family of
+// the cluster first, preserving the order of the IPs of a same family
+func SortIPsByFamily(ips []net.IP) {
+	sortByFamily(ips, func(i int) bool { return utilnet.IsIPv6(ips[i]) })
+}
+
+// SortIPNetsByFamily sorts ipnets in place with the ipnets of the primary IP
+// family of the cluster first, preserving the order of the ipnets of a same
+// family
+func SortIPNetsByFamily(ipnets []*net.IPNet) {
+	sortByFamily(ipnets, func(i int) bool { return utilnet.IsIPv6CIDR(ipnets[i]) })
+}
+
+// SortIPStringsByFamily sorts ips in place with the IPs of the primary IP
+// family of the cluster first, preserving the order of the IPs of a same
+// family. Strings that are not IPs are considered IPv4.
+func SortIPStringsByFamily(ips []string) {
+	sortByFamily(ips, func(i int) bool { return utilnet.IsIPv6String(ips[i]) })
+}
+
+// ValidateDualStackIPNets returns an error if ipnets holds more than one ipnet
+// of an IP family, or if it is not sorted by SortIPNetsByFamily
+func ValidateDualStackIPNets(ipnets []*net.IPNet) error {
+	primaryIsIPv6 := IsIPv6PrimaryFamily()
+	var hasIPv4, hasIPv6 bool
+	for i, ipnet := range ipnets {
+		isIPv6 := utilnet.IsIPv6CIDR(ipnet)
+		if isIPv6 && hasIPv6 || !isIPv6 && hasIPv4 {
+			return fmt.Errorf("more than one %s address in %s", IPFamilyName(isIPv6), JoinIPNets(ipnets, ","))
+		}
+		if i > 0 && isIPv6 == primaryIsIPv6 {
+			return fmt.Errorf("%s address %s must come first in %s", IPFamilyName(isIPv6), ipnet, JoinIPNets(ipnets, ","))
+		}
+		hasIPv4 = hasIPv4 || !isIPv6
+		hasIPv6 = hasIPv6 || isIPv6
+	}
+	return nil
+}
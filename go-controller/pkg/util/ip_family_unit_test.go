@@ -0,0 +1,127 @@
+package util
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	ovntest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func setClusterSubnets(t *testing.T, subnets ...string) {
+	assert.NoError(t, config.PrepareTestConfig())
+	config.Default.ClusterSubnets = nil
+	config.IPv4Mode, config.IPv6Mode = false, false
+	for _, subnet := range subnets {
+		cidr := ovntest.MustParseIPNet(subnet)
+		config.Default.ClusterSubnets = append(config.Default.ClusterSubnets, config.CIDRNetworkEntry{CIDR: cidr})
+		if cidr.IP.To4() == nil {
+			config.IPv6Mode = true
+		} else {
+			config.IPv4Mode = true
+		}
+	}
+}
+
+func TestSortIPStringsByFamily(t *testing.T) {
+	tests := []struct {
+		desc           string
+		clusterSubnets []string
+		input          []string
+		expOutput      []string
+	}{
+		{
+			desc:           "IPv4 primary cluster puts IPv4 first",
+			clusterSubnets: []string{"10.128.0.0/14", "fd00:10:128::/48"},
+			input:          []string{"fd00::1", "10.0.0.1", "fd00::2", "10.0.0.2"},
+			expOutput:      []string{"10.0.0.1", "10.0.0.2", "fd00::1", "fd00::2"},
+		},
+		{
+			desc:           "IPv6 primary cluster puts IPv6 first",
+			clusterSubnets: []string{"fd00:10:128::/48", "10.128.0.0/14"},
+			input:          []string{"10.0.0.1", "fd00::1", "10.0.0.2", "fd00::2"},
+			expOutput:      []string{"fd00::1", "fd00::2", "10.0.0.1", "10.0.0.2"},
+		},
+		{
+			desc:           "sorted input is left unchanged",
+			clusterSubnets: []string{"10.128.0.0/14", "fd00:10:128::/48"},
+			input:          []string{"10.0.0.2", "10.0.0.1", "fd00::2"},
+			expOutput:      []string{"10.0.0.2", "10.0.0.1", "fd00::2"},
+		},
+		{
+			desc:      "single stack IPv6 cluster without cluster subnets puts IPv6 first",
+			input:     []string{"10.0.0.1", "fd00::1"},
+			expOutput: []string{"fd00::1", "10.0.0.1"},
+		},
+	}
+	for i, tc := range tests {
+		t.Run(fmt.Sprintf("%d:%s", i, tc.desc), func(t *testing.T) {
+			setClusterSubnets(t, tc.clusterSubnets...)
+			if len(tc.clusterSubnets) == 0 {
+				config.IPv6Mode = true
+			}
+			SortIPStringsByFamily(tc.input)
+			assert.Equal(t, tc.expOutput, tc.input)
+		})
+	}
+}
+
+func TestSortIPNetsByFamily(t *testing.T) {
+	setClusterSubnets(t, "fd00:10:128::/48", "10.128.0.0/14")
+	ipnets := []*net.IPNet{ovntest.MustParseIPNet("10.128.1.0/24"), ovntest.MustParseIPNet("fd00:10:128:1::/64")}
+	SortIPNetsByFamily(ipnets)
+	assert.Equal(t, "fd00:10:128:1::/64,10.128.1.0/24", JoinIPNets(ipnets, ","))
+
+	ips := []net.IP{ovntest.MustParseIP("10.128.1.1"), ovntest.MustParseIP("fd00:10:128:1::1")}
+	SortIPsByFamily(ips)
+	assert.Equal(t, "fd00:10:128:1::1,10.128.1.1", JoinIPs(ips, ","))
+}
+
+func TestValidateDualStackIPNets(t *testing.T) {
+	tests := []struct {
+		desc           string
+		clusterSubnets []string
+		input          []string
+		expErr         bool
+	}{
+		{
+			desc:           "dual stack with primary family first",
+			clusterSubnets: []string{"10.128.0.0/14", "fd00:10:128::/48"},
+			input:          []string{"10.128.1.0/24", "fd00:10:128:1::/64"},
+		},
+		{
+			desc:           "single IP of the secondary family",
+			clusterSubnets: []string{"10.128.0.0/14", "fd00:10:128::/48"},
+			input:          []string{"fd00:10:128:1::/64"},
+		},
+		{
+			desc:           "dual stack with secondary family first",
+			clusterSubnets: []string{"10.128.0.0/14", "fd00:10:128::/48"},
+			input:          []string{"fd00:10:128:1::/64", "10.128.1.0/24"},
+			expErr:         true,
+		},
+		{
+			desc:           "two IPs of the same family",
+			clusterSubnets: []string{"10.128.0.0/14", "fd00:10:128::/48"},
+			input:          []string{"10.128.1.0/24", "10.128.2.0/24"},
+			expErr:         true,
+		},
+	}
+	for i, tc := range tests {
+		t.Run(fmt.Sprintf("%d:%s", i, tc.desc), func(t *testing.T) {
+			setClusterSubnets(t, tc.clusterSubnets...)
+			var ipnets []*net.IPNet
+			for _, ipnet := range tc.input {
+				ipnets = append(ipnets, ovntest.MustParseIPNet(ipnet))
+			}
+			err := ValidateDualStackIPNets(ipnets)
+			if tc.expErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
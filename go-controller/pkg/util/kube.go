@@ -3,6 +3,7 @@ package util
 import (
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -23,6 +24,7 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/transport"
 	"k8s.io/client-go/util/cert"
 	"k8s.io/klog/v2"
 	utilnet "k8s.io/utils/net"
@@ -31,10 +33,12 @@ import (
 	networkattchmentdefclientset "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned"
 	ocpcloudnetworkclientset "github.com/openshift/client-go/cloudnetwork/clientset/versioned"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	anpclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1alpha1/apis/clientset/versioned"
 	egressfirewallclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1/apis/clientset/versioned"
 	egressipclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1/apis/clientset/versioned"
 	egressqosclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1/apis/clientset/versioned"
 	egressserviceclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressservice/v1/apis/clientset/versioned"
+	ipamclaimsclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/ipamclaims/v1alpha1/apis/clientset/versioned"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
 )
 
@@ -48,6 +52,8 @@ type OVNClientset struct {
 	NetworkAttchDefClient    networkattchmentdefclientset.Interface
 	MultiNetworkPolicyClient multinetworkpolicyclientset.Interface
 	EgressServiceClient      egressserviceclientset.Interface
+	ANPClient                anpclientset.Interface
+	IPAMClaimsClient         ipamclaimsclientset.Interface
 }
 
 // OVNMasterClientset
@@ -59,6 +65,8 @@ type OVNMasterClientset struct {
 	EgressQoSClient          egressqosclientset.Interface
 	MultiNetworkPolicyClient multinetworkpolicyclientset.Interface
 	EgressServiceClient      egressserviceclientset.Interface
+	ANPClient                anpclientset.Interface
+	IPAMClaimsClient         ipamclaimsclientset.Interface
 }
 
 type OVNNodeClientset struct {
@@ -69,6 +77,7 @@ type OVNNodeClientset struct {
 type OVNClusterManagerClientset struct {
 	KubeClient            kubernetes.Interface
 	NetworkAttchDefClient networkattchmentdefclientset.Interface
+	IPAMClaimsClient      ipamclaimsclientset.Interface
 }
 
 func (cs *OVNClientset) GetMasterClientset() *OVNMasterClientset {
@@ -80,6 +89,8 @@ func (cs *OVNClientset) GetMasterClientset() *OVNMasterClientset {
 		EgressQoSClient:          cs.EgressQoSClient,
 		MultiNetworkPolicyClient: cs.MultiNetworkPolicyClient,
 		EgressServiceClient:      cs.EgressServiceClient,
+		ANPClient:                cs.ANPClient,
+		IPAMClaimsClient:         cs.IPAMClaimsClient,
 	}
 }
 
@@ -87,6 +98,7 @@ func (cs *OVNClientset) GetClusterManagerClientset() *OVNClusterManagerClientset
 	return &OVNClusterManagerClientset{
 		KubeClient:            cs.KubeClient,
 		NetworkAttchDefClient: cs.NetworkAttchDefClient,
+		IPAMClaimsClient:      cs.IPAMClaimsClient,
 	}
 }
 
@@ -160,9 +172,34 @@ func newKubernetesRestConfig(conf *config.KubernetesConfig) (*rest.Config, error
 	kconfig.UserAgent = fmt.Sprintf("%s/%s@%s (%s/%s) kubernetes/%s",
 		adjustNodeName(), filepath.Base(os.Args[0]), adjustCommit(), runtime.GOOS, runtime.GOARCH,
 		version.Get().GitVersion)
+	if config.Default.DryRun {
+		kconfig.WrapTransport = transport.Wrappers(kconfig.WrapTransport, newDryRunRoundTripper)
+	}
 	return kconfig, nil
 }
 
+// dryRunRoundTripper sends the write requests to the apiserver as dry-run requests, which are validated but not
+// persisted
+type dryRunRoundTripper struct {
+	rt http.RoundTripper
+}
+
+func newDryRunRoundTripper(rt http.RoundTripper) http.RoundTripper {
+	return &dryRunRoundTripper{rt: rt}
+}
+
+func (d *dryRunRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		req = req.Clone(req.Context())
+		query := req.URL.Query()
+		query.Set("dryRun", metav1.DryRunAll)
+		req.URL.RawQuery = query.Encode()
+		klog.V(5).Infof("Dry run: sending %s %s as a dry-run request", req.Method, req.URL.Path)
+	}
+	return d.rt.RoundTrip(req)
+}
+
 // NewKubernetesClientset creates a Kubernetes clientset from a KubernetesConfig
 func NewKubernetesClientset(conf *config.KubernetesConfig) (*kubernetes.Clientset, error) {
 	kconfig, err := newKubernetesRestConfig(conf)
@@ -218,6 +255,16 @@ func NewOVNClientset(conf *config.KubernetesConfig) (*OVNClientset, error) {
 		return nil, err
 	}
 
+	anpClientset, err := anpclientset.NewForConfig(kconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	ipamClaimsClientset, err := ipamclaimsclientset.NewForConfig(kconfig)
+	if err != nil {
+		return nil, err
+	}
+
 	return &OVNClientset{
 		KubeClient:               kclientset,
 		EgressIPClient:           egressIPClientset,
@@ -227,6 +274,8 @@ func NewOVNClientset(conf *config.KubernetesConfig) (*OVNClientset, error) {
 		NetworkAttchDefClient:    networkAttchmntDefClientset,
 		MultiNetworkPolicyClient: multiNetworkPolicyClientset,
 		EgressServiceClient:      egressserviceClientset,
+		ANPClient:                anpClientset,
+		IPAMClaimsClient:         ipamClaimsClientset,
 	}, nil
 }
 
@@ -235,7 +284,8 @@ func IsClusterIPSet(service *kapi.Service) bool {
 	return service.Spec.ClusterIP != kapi.ClusterIPNone && service.Spec.ClusterIP != ""
 }
 
-// GetClusterIPs return an array with the ClusterIPs present in the service
+// GetClusterIPs return an array with the ClusterIPs present in the service,
+// sorted with the primary IP family of the cluster first
 // for backward compatibility with versions < 1.20
 // we need to handle the case where only ClusterIP exist
 func GetClusterIPs(service *kapi.Service) []string {
@@ -244,6 +294,7 @@ func GetClusterIPs(service *kapi.Service) []string {
 		for _, clusterIP := range service.Spec.ClusterIPs {
 			clusterIPs = append(clusterIPs, utilnet.ParseIPSloppy(clusterIP).String())
 		}
+		SortIPStringsByFamily(clusterIPs)
 		return clusterIPs
 	}
 	if len(service.Spec.ClusterIP) > 0 && service.Spec.ClusterIP != kapi.ClusterIPNone {
@@ -252,7 +303,8 @@ func GetClusterIPs(service *kapi.Service) []string {
 	return []string{}
 }
 
-// GetExternalAndLBIPs returns an array with the ExternalIPs and LoadBalancer IPs present in the service
+// GetExternalAndLBIPs returns an array with the ExternalIPs and LoadBalancer IPs present in the service,
+// sorted with the primary IP family of the cluster first
 func GetExternalAndLBIPs(service *kapi.Service) []string {
 	svcVIPs := []string{}
 	for _, externalIP := range service.Spec.ExternalIPs {
@@ -271,6 +323,7 @@ func GetExternalAndLBIPs(service *kapi.Service) []string {
 			}
 		}
 	}
+	SortIPStringsByFamily(svcVIPs)
 	return svcVIPs
 }
 
@@ -392,12 +445,17 @@ type LbEndpoints struct {
 	V4IPs []string
 	V6IPs []string
 	Port  int32
+	// ZoneHints maps the endpoint IPs to the zones they are hinted for, nil
+	// unless every endpoint has zone hints
+	ZoneHints map[string]sets.Set[string]
 }
 
 // GetLbEndpoints returns the IPv4 and IPv6 addresses of eligible endpoints as slices inside a struct
 func GetLbEndpoints(slices []*discovery.EndpointSlice, svcPort kapi.ServicePort, service *v1.Service) LbEndpoints {
 	v4ips := sets.NewString()
 	v6ips := sets.NewString()
+	zoneHints := map[string]sets.Set[string]{}
+	allHinted := true
 
 	out := LbEndpoints{}
 	// return an empty object so the caller doesn't have to check for nil and can use it as an iterator
@@ -427,9 +485,22 @@ func GetLbEndpoints(slices []*discovery.EndpointSlice, svcPort kapi.ServicePort,
 
 			out.Port = *port.Port
 			ForEachEligibleEndpoint(slice, service, func(endpoint discovery.Endpoint, shortcut *bool) {
+				if endpoint.Hints == nil || len(endpoint.Hints.ForZones) == 0 {
+					allHinted = false
+				}
 				for _, ip := range endpoint.Addresses {
 					klog.V(4).Infof("Adding slice %s endpoint: %v, port: %d", slice.Name, endpoint.Addresses, *port.Port)
 					ipStr := utilnet.ParseIPSloppy(ip).String()
+					if allHinted {
+						zones := zoneHints[ipStr]
+						if zones == nil {
+							zones = sets.New[string]()
+							zoneHints[ipStr] = zones
+						}
+						for _, zone := range endpoint.Hints.ForZones {
+							zones.Insert(zone.Name)
+						}
+					}
 					switch slice.AddressType {
 					case discovery.AddressTypeIPv4:
 						v4ips.Insert(ipStr)
@@ -445,6 +516,9 @@ func GetLbEndpoints(slices []*discovery.EndpointSlice, svcPort kapi.ServicePort,
 
 	out.V4IPs = v4ips.List()
 	out.V6IPs = v6ips.List()
+	if allHinted && len(zoneHints) > 0 {
+		out.ZoneHints = zoneHints
+	}
 	klog.V(4).Infof("LB Endpoints for %s/%s are: %v / %v on port: %d",
 		slices[0].Namespace, slices[0].Labels[discovery.LabelServiceName],
 		out.V4IPs, out.V6IPs, out.Port)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/http"
 	"reflect"
 	"testing"
 
@@ -110,6 +111,44 @@ func TestNewClientset(t *testing.T) {
 	}
 }
 
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestDryRunRoundTripper(t *testing.T) {
+	var sentQuery string
+	rt := newDryRunRoundTripper(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		sentQuery = req.URL.RawQuery
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}))
+
+	tests := []struct {
+		method        string
+		url           string
+		expectedQuery string
+	}{
+		{http.MethodGet, "http://apiserver/api/v1/pods?watch=true", "watch=true"},
+		{http.MethodPost, "http://apiserver/api/v1/namespaces/ns1/events", "dryRun=All"},
+		{http.MethodPut, "http://apiserver/api/v1/namespaces/ns1/pods/pod1", "dryRun=All"},
+		{http.MethodPatch, "http://apiserver/api/v1/nodes/node1?fieldManager=ovnkube", "dryRun=All&fieldManager=ovnkube"},
+		{http.MethodDelete, "http://apiserver/api/v1/namespaces/ns1/pods/pod1", "dryRun=All"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.method, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, tc.url, nil)
+			assert.NoError(t, err)
+			originalQuery := req.URL.RawQuery
+			_, err = rt.RoundTrip(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedQuery, sentQuery)
+			// the request of the caller is not modified
+			assert.Equal(t, originalQuery, req.URL.RawQuery)
+		})
+	}
+}
+
 func TestIsClusterIPSet(t *testing.T) {
 	tests := []struct {
 		desc   string
@@ -476,7 +515,7 @@ func Test_getLbEndpoints(t *testing.T) {
 					Protocol:   v1.ProtocolTCP,
 				},
 			},
-			want: LbEndpoints{[]string{"10.0.0.2"}, []string{}, 80},
+			want: LbEndpoints{V4IPs: []string{"10.0.0.2"}, V6IPs: []string{}, Port: 80},
 		},
 		{
 			name: "slices with different port name",
@@ -512,7 +551,7 @@ func Test_getLbEndpoints(t *testing.T) {
 					Protocol:   v1.ProtocolTCP,
 				},
 			},
-			want: LbEndpoints{[]string{}, []string{}, 0},
+			want: LbEndpoints{V4IPs: []string{}, V6IPs: []string{}, Port: 0},
 		},
 		{
 			name: "slices and service without port name",
@@ -546,7 +585,7 @@ func Test_getLbEndpoints(t *testing.T) {
 					Protocol:   v1.ProtocolTCP,
 				},
 			},
-			want: LbEndpoints{[]string{"10.0.0.2"}, []string{}, 8080},
+			want: LbEndpoints{V4IPs: []string{"10.0.0.2"}, V6IPs: []string{}, Port: 8080},
 		},
 		{
 			name: "slices with different IP family",
@@ -582,7 +621,7 @@ func Test_getLbEndpoints(t *testing.T) {
 					Protocol:   v1.ProtocolTCP,
 				},
 			},
-			want: LbEndpoints{[]string{}, []string{"2001:db2::2"}, 80},
+			want: LbEndpoints{V4IPs: []string{}, V6IPs: []string{"2001:db2::2"}, Port: 80},
 		},
 		{
 			name: "multiples slices with duplicate endpoints",
@@ -641,7 +680,7 @@ func Test_getLbEndpoints(t *testing.T) {
 					Protocol:   v1.ProtocolTCP,
 				},
 			},
-			want: LbEndpoints{[]string{"10.0.0.2", "10.1.1.2", "10.2.2.2"}, []string{}, 80},
+			want: LbEndpoints{V4IPs: []string{"10.0.0.2", "10.1.1.2", "10.2.2.2"}, V6IPs: []string{}, Port: 80},
 		},
 		{
 			name: "slices with non-ready but serving endpoints",
@@ -678,7 +717,7 @@ func Test_getLbEndpoints(t *testing.T) {
 					Protocol:   v1.ProtocolTCP,
 				},
 			},
-			want: LbEndpoints{[]string{}, []string{"2001:db2::2"}, 80},
+			want: LbEndpoints{V4IPs: []string{}, V6IPs: []string{"2001:db2::2"}, Port: 80},
 		},
 		{
 			name: "slices with non-ready non-serving endpoints",
@@ -715,7 +754,104 @@ func Test_getLbEndpoints(t *testing.T) {
 					Protocol:   v1.ProtocolTCP,
 				},
 			},
-			want: LbEndpoints{[]string{}, []string{}, 80},
+			want: LbEndpoints{V4IPs: []string{}, V6IPs: []string{}, Port: 80},
+		},
+		{
+			name: "slices with zone hints on all the endpoints",
+			args: args{
+				slices: []*discovery.EndpointSlice{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "svc-ab23",
+							Namespace: "ns",
+							Labels:    map[string]string{discovery.LabelServiceName: "svc"},
+						},
+						Ports: []discovery.EndpointPort{
+							{
+								Name:     utilpointer.StringPtr("tcp-example"),
+								Protocol: protoPtr(v1.ProtocolTCP),
+								Port:     utilpointer.Int32Ptr(int32(80)),
+							},
+						},
+						AddressType: discovery.AddressTypeIPv4,
+						Endpoints: []discovery.Endpoint{
+							{
+								Conditions: discovery.EndpointConditions{
+									Ready: utilpointer.Bool(true),
+								},
+								Addresses: []string{"10.0.0.2"},
+								Hints: &discovery.EndpointHints{
+									ForZones: []discovery.ForZone{{Name: "zone-a"}},
+								},
+							},
+							{
+								Conditions: discovery.EndpointConditions{
+									Ready: utilpointer.Bool(true),
+								},
+								Addresses: []string{"10.0.0.3"},
+								Hints: &discovery.EndpointHints{
+									ForZones: []discovery.ForZone{{Name: "zone-b"}, {Name: "zone-c"}},
+								},
+							},
+						},
+					},
+				},
+				svcPort: v1.ServicePort{
+					Name:       "tcp-example",
+					TargetPort: intstr.FromInt(80),
+					Protocol:   v1.ProtocolTCP,
+				},
+			},
+			want: LbEndpoints{V4IPs: []string{"10.0.0.2", "10.0.0.3"}, V6IPs: []string{}, Port: 80,
+				ZoneHints: map[string]sets.Set[string]{
+					"10.0.0.2": sets.New("zone-a"),
+					"10.0.0.3": sets.New("zone-b", "zone-c"),
+				}},
+		},
+		{
+			name: "slices with zone hints on some of the endpoints",
+			args: args{
+				slices: []*discovery.EndpointSlice{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "svc-ab23",
+							Namespace: "ns",
+							Labels:    map[string]string{discovery.LabelServiceName: "svc"},
+						},
+						Ports: []discovery.EndpointPort{
+							{
+								Name:     utilpointer.StringPtr("tcp-example"),
+								Protocol: protoPtr(v1.ProtocolTCP),
+								Port:     utilpointer.Int32Ptr(int32(80)),
+							},
+						},
+						AddressType: discovery.AddressTypeIPv4,
+						Endpoints: []discovery.Endpoint{
+							{
+								Conditions: discovery.EndpointConditions{
+									Ready: utilpointer.Bool(true),
+								},
+								Addresses: []string{"10.0.0.2"},
+								Hints: &discovery.EndpointHints{
+									ForZones: []discovery.ForZone{{Name: "zone-a"}},
+								},
+							},
+							{
+								Conditions: discovery.EndpointConditions{
+									Ready: utilpointer.Bool(true),
+								},
+								Addresses: []string{"10.0.0.3"},
+							},
+						},
+					},
+				},
+				svcPort: v1.ServicePort{
+					Name:       "tcp-example",
+					TargetPort: intstr.FromInt(80),
+					Protocol:   v1.ProtocolTCP,
+				},
+			},
+			want: LbEndpoints{V4IPs: []string{"10.0.0.2", "10.0.0.3"}, V6IPs: []string{}, Port: 80},
 		},
 	}
 	for _, tt := range tests {
@@ -11,6 +11,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 
 	kapi "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
 	knet "k8s.io/utils/net"
 
 	nettypes "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
@@ -32,6 +33,10 @@ type BasicNetInfo interface {
 	Subnets() []config.CIDRNetworkEntry
 	ExcludeSubnets() []*net.IPNet
 	Vlan() uint
+	AllowsPersistentIPs() bool
+	RAGuard() bool
+	DHCPv6Guard() bool
+	MulticastEnabled() bool
 
 	// utility methods
 	CompareNetInfo(BasicNetInfo) bool
@@ -46,6 +51,9 @@ type NetInfo interface {
 	AddNAD(nadName string)
 	DeleteNAD(nadName string)
 	HasNAD(nadName string) bool
+	// Reconfigure updates the network information with the reconfigurable configuration of the other, which must
+	// satisfy CanReconfigureNetInfo
+	Reconfigure(other BasicNetInfo) error
 }
 
 type DefaultNetInfo struct{}
@@ -88,6 +96,11 @@ func (nInfo *DefaultNetInfo) HasNAD(nadName string) bool {
 	panic("unexpected call for default network")
 }
 
+// Reconfigure is not supported for the default network
+func (nInfo *DefaultNetInfo) Reconfigure(other BasicNetInfo) error {
+	return fmt.Errorf("the default network can't be reconfigured")
+}
+
 func (nInfo *DefaultNetInfo) CompareNetInfo(netBasicInfo BasicNetInfo) bool {
 	_, ok := netBasicInfo.(*DefaultNetInfo)
 	return ok
@@ -123,16 +136,46 @@ func (nInfo *DefaultNetInfo) Vlan() uint {
 	return config.Gateway.VLANID
 }
 
+// AllowsPersistentIPs returns false since the default network does not
+// support persistent IPs
+func (nInfo *DefaultNetInfo) AllowsPersistentIPs() bool {
+	return false
+}
+
+// RAGuard returns false since the pods of the default network are not
+// guarded against sending router advertisements
+func (nInfo *DefaultNetInfo) RAGuard() bool {
+	return false
+}
+
+// DHCPv6Guard returns false since the pods of the default network are not
+// guarded against sending DHCPv6 server messages
+func (nInfo *DefaultNetInfo) DHCPv6Guard() bool {
+	return false
+}
+
+// MulticastEnabled returns whether multicast is enabled in the cluster
+func (nInfo *DefaultNetInfo) MulticastEnabled() bool {
+	return config.EnableMulticast
+}
+
 // SecondaryNetInfo holds the network name information for secondary network if non-nil
 type secondaryNetInfo struct {
 	netName  string
 	topology string
-	mtu      int
-	vlan     uint
+
+	// configuration that can be reconfigured without recreating the network, protected by the lock
+	sync.RWMutex
+	mtu                int
+	vlan               uint
+	allowPersistentIPs bool
+	raGuard            bool
+	dhcpv6Guard        bool
 
 	ipv4mode, ipv6mode bool
 	subnets            []config.CIDRNetworkEntry
 	excludeSubnets     []*net.IPNet
+	enableMulticast    bool
 
 	// all net-attach-def NAD names for this network, used to determine if a pod needs
 	// to be plumbed for this network
@@ -191,14 +234,47 @@ func (nInfo *secondaryNetInfo) TopologyType() string {
 
 // MTU returns the layer3NetConfInfo's MTU value
 func (nInfo *secondaryNetInfo) MTU() int {
+	nInfo.RLock()
+	defer nInfo.RUnlock()
 	return nInfo.mtu
 }
 
 // Vlan returns the Vlan value
 func (nInfo *secondaryNetInfo) Vlan() uint {
+	nInfo.RLock()
+	defer nInfo.RUnlock()
 	return nInfo.vlan
 }
 
+// AllowsPersistentIPs returns whether pod IPs can be kept across pod
+// recreation through IPAMClaims
+func (nInfo *secondaryNetInfo) AllowsPersistentIPs() bool {
+	nInfo.RLock()
+	defer nInfo.RUnlock()
+	return nInfo.allowPersistentIPs
+}
+
+// RAGuard returns whether the IPv6 router advertisements sent by the pods
+// are dropped
+func (nInfo *secondaryNetInfo) RAGuard() bool {
+	nInfo.RLock()
+	defer nInfo.RUnlock()
+	return nInfo.raGuard
+}
+
+// DHCPv6Guard returns whether the DHCPv6 server messages sent by the pods
+// are dropped
+func (nInfo *secondaryNetInfo) DHCPv6Guard() bool {
+	nInfo.RLock()
+	defer nInfo.RUnlock()
+	return nInfo.dhcpv6Guard
+}
+
+// MulticastEnabled returns whether multicast is enabled on the network
+func (nInfo *secondaryNetInfo) MulticastEnabled() bool {
+	return nInfo.enableMulticast
+}
+
 // IPMode returns the ipv4/ipv6 mode
 func (nInfo *secondaryNetInfo) IPMode() (bool, bool) {
 	return nInfo.ipv4mode, nInfo.ipv6mode
@@ -222,20 +298,72 @@ func (nInfo *secondaryNetInfo) CompareNetInfo(other BasicNetInfo) bool {
 	if nInfo.topology != other.TopologyType() {
 		return false
 	}
-	if nInfo.mtu != other.MTU() {
+	if nInfo.MTU() != other.MTU() {
+		return false
+	}
+	if nInfo.Vlan() != other.Vlan() {
 		return false
 	}
-	if nInfo.vlan != other.Vlan() {
+	if nInfo.AllowsPersistentIPs() != other.AllowsPersistentIPs() {
 		return false
 	}
+	if nInfo.RAGuard() != other.RAGuard() || nInfo.DHCPv6Guard() != other.DHCPv6Guard() {
+		return false
+	}
+	if nInfo.enableMulticast != other.MulticastEnabled() {
+		return false
+	}
+	return compareSubnets(nInfo, other)
+}
 
+// Reconfigure updates the MTU, VLAN, persistent IPs and guard settings of the network with the ones of the other
+func (nInfo *secondaryNetInfo) Reconfigure(other BasicNetInfo) error {
+	if !CanReconfigureNetInfo(nInfo, other) {
+		return fmt.Errorf("network %s can't be reconfigured from %s network %s", nInfo.netName,
+			other.TopologyType(), other.GetNetworkName())
+	}
+	mtu, vlan, allowPersistentIPs := other.MTU(), other.Vlan(), other.AllowsPersistentIPs()
+	raGuard, dhcpv6Guard := other.RAGuard(), other.DHCPv6Guard()
+	nInfo.Lock()
+	defer nInfo.Unlock()
+	nInfo.mtu = mtu
+	nInfo.vlan = vlan
+	nInfo.allowPersistentIPs = allowPersistentIPs
+	nInfo.raGuard = raGuard
+	nInfo.dhcpv6Guard = dhcpv6Guard
+	return nil
+}
+
+// compareSubnets compares for equality the subnets and excluded subnets of the networks
+func compareSubnets(nInfo, other BasicNetInfo) bool {
 	lessCIDRNetworkEntry := func(a, b config.CIDRNetworkEntry) bool { return a.String() < b.String() }
-	if !cmp.Equal(nInfo.subnets, other.Subnets(), cmpopts.SortSlices(lessCIDRNetworkEntry)) {
+	if !cmp.Equal(nInfo.Subnets(), other.Subnets(), cmpopts.SortSlices(lessCIDRNetworkEntry)) {
 		return false
 	}
 
 	lessIPNet := func(a, b net.IPNet) bool { return a.String() < b.String() }
-	return cmp.Equal(nInfo.excludeSubnets, other.ExcludeSubnets(), cmpopts.SortSlices(lessIPNet))
+	return cmp.Equal(nInfo.ExcludeSubnets(), other.ExcludeSubnets(), cmpopts.SortSlices(lessIPNet))
+}
+
+// CanReconfigureNetInfo returns whether a network can be reconfigured from this network information to the other
+// without being recreated. Only its MTU, VLAN, persistent IPs and guard settings can change: a different
+// topology or different subnets, including added subnets or excluded subnets, require to recreate the network and
+// reallocate the IPs of its pods. The pod IP allocators are not able to resize their ranges in place. Enabling or
+// disabling multicast also requires to recreate the network, to set up or tear down its multicast port groups.
+func CanReconfigureNetInfo(nInfo, other BasicNetInfo) bool {
+	if !nInfo.IsSecondary() || !other.IsSecondary() {
+		return false
+	}
+	if nInfo.GetNetworkName() != other.GetNetworkName() {
+		return false
+	}
+	if nInfo.TopologyType() != other.TopologyType() {
+		return false
+	}
+	if nInfo.MulticastEnabled() != other.MulticastEnabled() {
+		return false
+	}
+	return compareSubnets(nInfo, other)
 }
 
 func newLayer3NetConfInfo(netconf *ovncnitypes.NetConf) (NetInfo, error) {
@@ -245,10 +373,11 @@ func newLayer3NetConfInfo(netconf *ovncnitypes.NetConf) (NetInfo, error) {
 	}
 
 	ni := &secondaryNetInfo{
-		netName:  netconf.Name,
-		topology: types.Layer3Topology,
-		subnets:  subnets,
-		mtu:      netconf.MTU,
+		netName:         netconf.Name,
+		topology:        types.Layer3Topology,
+		subnets:         subnets,
+		mtu:             netconf.MTU,
+		enableMulticast: netconf.EnableMulticast,
 	}
 	ni.ipv4mode, ni.ipv6mode = getIPMode(subnets)
 	return ni, nil
@@ -261,11 +390,13 @@ func newLayer2NetConfInfo(netconf *ovncnitypes.NetConf) (NetInfo, error) {
 	}
 
 	ni := &secondaryNetInfo{
-		netName:        netconf.Name,
-		topology:       types.Layer2Topology,
-		subnets:        subnets,
-		excludeSubnets: excludes,
-		mtu:            netconf.MTU,
+		netName:            netconf.Name,
+		topology:           types.Layer2Topology,
+		subnets:            subnets,
+		excludeSubnets:     excludes,
+		mtu:                netconf.MTU,
+		allowPersistentIPs: netconf.AllowPersistentIPs,
+		enableMulticast:    netconf.EnableMulticast,
 	}
 	ni.ipv4mode, ni.ipv6mode = getIPMode(subnets)
 	return ni, nil
@@ -278,12 +409,15 @@ func newLocalnetNetConfInfo(netconf *ovncnitypes.NetConf) (NetInfo, error) {
 	}
 
 	ni := &secondaryNetInfo{
-		netName:        netconf.Name,
-		topology:       types.LocalnetTopology,
-		subnets:        subnets,
-		excludeSubnets: excludes,
-		mtu:            netconf.MTU,
-		vlan:           uint(netconf.VLANID),
+		netName:            netconf.Name,
+		topology:           types.LocalnetTopology,
+		subnets:            subnets,
+		excludeSubnets:     excludes,
+		mtu:                netconf.MTU,
+		vlan:               uint(netconf.VLANID),
+		allowPersistentIPs: netconf.AllowPersistentIPs,
+		raGuard:            netconf.RAGuard,
+		dhcpv6Guard:        netconf.DHCPv6Guard,
 	}
 	ni.ipv4mode, ni.ipv6mode = getIPMode(subnets)
 	return ni, nil
@@ -311,6 +445,17 @@ func parseSubnets(subnetsString, excludeSubnetsString, topology string) ([]confi
 			return nil, nil, err
 		}
 	}
+	allSubnets := subnets
+	if topology != types.Layer3Topology {
+		// the logical switch of a L2 topology is configured with a single
+		// subnet per IP family, the first one is used and the others are
+		// ignored so that the existing networks keep working
+		subnets = firstSubnetPerIPFamily(allSubnets)
+		if len(subnets) != len(allSubnets) {
+			klog.Warningf("%s topology supports at most one IPv4 and one IPv6 subnet, got %q, only using %v",
+				topology, subnetsString, subnets)
+		}
+	}
 
 	var excludeIPNets []*net.IPNet
 	if strings.TrimSpace(excludeSubnetsString) != "" {
@@ -323,7 +468,7 @@ func parseSubnets(subnetsString, excludeSubnetsString, topology string) ([]confi
 		excludeIPNets = make([]*net.IPNet, 0, len(excludeSubnets))
 		for _, excludeSubnet := range excludeSubnets {
 			found := false
-			for _, subnet := range subnets {
+			for _, subnet := range allSubnets {
 				if ContainsCIDR(subnet.CIDR, excludeSubnet.CIDR) {
 					found = true
 					break
@@ -340,6 +485,27 @@ func parseSubnets(subnetsString, excludeSubnetsString, topology string) ([]confi
 	return subnets, excludeIPNets, nil
 }
 
+// firstSubnetPerIPFamily returns the first IPv4 and the first IPv6 subnet of the given subnets
+func firstSubnetPerIPFamily(subnets []config.CIDRNetworkEntry) []config.CIDRNetworkEntry {
+	var first []config.CIDRNetworkEntry
+	var hasIPv4, hasIPv6 bool
+	for _, subnet := range subnets {
+		if knet.IsIPv6CIDR(subnet.CIDR) {
+			if hasIPv6 {
+				continue
+			}
+			hasIPv6 = true
+		} else {
+			if hasIPv4 {
+				continue
+			}
+			hasIPv4 = true
+		}
+		first = append(first, subnet)
+	}
+	return first
+}
+
 func getIPMode(subnets []config.CIDRNetworkEntry) (bool, bool) {
 	var ipv6Mode, ipv4Mode bool
 	for _, subnet := range subnets {
@@ -429,7 +595,8 @@ func ParseNetConf(netattachdef *nettypes.NetworkAttachmentDefinition) (*ovncnity
 //	bool: if this Pod is on this Network; true or false
 //	map[string]*nettypes.NetworkSelectionElement: all NetworkSelectionElement that pod is requested
 //	    for the specified network, key is NADName. Note multiple NADs of the same network are allowed
-//	    on one pod, as long as they are of different NADName.
+//	    on one pod, as long as they are of different NADName. For secondary networks, the
+//	    InterfaceRequest of each element is set to the final name of the pod interface.
 //	error:  error in case of failure
 func GetPodNADToNetworkMapping(pod *kapi.Pod, nInfo NetInfo) (bool, map[string]*nettypes.NetworkSelectionElement, error) {
 	if pod.Spec.HostNetwork {
@@ -457,9 +624,15 @@ func GetPodNADToNetworkMapping(pod *kapi.Pod, nInfo NetInfo) (bool, map[string]*
 		return false, nil, err
 	}
 
-	for _, network := range allNetworks {
+	ifNames, ifNameErrs := GetPodNetworkInterfaceNames(allNetworks)
+	for i, network := range allNetworks {
 		nadName := GetNADName(network.Namespace, network.Name)
 		if nInfo.HasNAD(nadName) {
+			if ifNameErrs[i] != nil {
+				return false, nil, fmt.Errorf("invalid network selection of NAD %s for pod %s: %v",
+					nadName, podDesc, ifNameErrs[i])
+			}
+			network.InterfaceRequest = ifNames[i]
 			if _, ok := networkSelections[nadName]; ok {
 				return false, nil, fmt.Errorf("unexpected error: more than one of the same NAD %s specified for pod %s",
 					nadName, podDesc)
@@ -473,6 +646,76 @@ func GetPodNADToNetworkMapping(pod *kapi.Pod, nInfo NetInfo) (bool, map[string]*
 	return true, networkSelections, nil
 }
 
+// defaultPodInterfaceName is the name of the pod interface attached to the
+// cluster default network
+const defaultPodInterfaceName = "eth0"
+
+// GetPodNetworkInterfaceNames returns the names of the pod interfaces attached
+// to the given secondary networks, following the multus convention: the
+// requested interface name if any, or "net<N>" with N the 1-based position of
+// the network selection element otherwise. Every attachment is validated on its
+// own: the returned errors hold, at the position of each element, whether its
+// requested name is not a valid interface name or collides with the name of
+// the default network interface or of a previous attachment. An invalid
+// attachment doesn't claim its name.
+func GetPodNetworkInterfaceNames(networks []*nettypes.NetworkSelectionElement) ([]string, []error) {
+	names := make([]string, len(networks))
+	errs := make([]error, len(networks))
+	owners := map[string]string{defaultPodInterfaceName: "the default network"}
+	for i, network := range networks {
+		name := network.InterfaceRequest
+		if name == "" {
+			name = fmt.Sprintf("net%d", i+1)
+		} else if err := validateInterfaceName(name); err != nil {
+			errs[i] = err
+			continue
+		}
+		nadName := GetNADName(network.Namespace, network.Name)
+		if owner, ok := owners[name]; ok {
+			errs[i] = fmt.Errorf("interface name %q of network %s collides with %s", name, nadName, owner)
+			continue
+		}
+		owners[name] = "network " + nadName
+		names[i] = name
+	}
+	return names, errs
+}
+
+// GetPodNetworkInterfaceName returns the name of the pod interface attached to
+// the given NAD of a secondary network, as requested in the network selection
+// elements of the pod, see GetPodNetworkInterfaceNames
+func GetPodNetworkInterfaceName(pod *kapi.Pod, nadName string) (string, error) {
+	allNetworks, err := GetK8sPodAllNetworkSelections(pod)
+	if err != nil {
+		return "", err
+	}
+	names, errs := GetPodNetworkInterfaceNames(allNetworks)
+	for i, network := range allNetworks {
+		if GetNADName(network.Namespace, network.Name) == nadName {
+			return names[i], errs[i]
+		}
+	}
+	return "", fmt.Errorf("pod %s/%s doesn't request network %s", pod.Namespace, pod.Name, nadName)
+}
+
+// validateInterfaceName checks that name can be used as a Linux interface name
+func validateInterfaceName(name string) error {
+	if len(name) > 15 {
+		return fmt.Errorf("interface name %q is longer than 15 characters", name)
+	}
+	if name == "." || name == ".." {
+		return fmt.Errorf("interface name %q is not valid", name)
+	}
+	if strings.ContainsAny(name, "/: \t\n") {
+		return fmt.Errorf("interface name %q contains invalid characters", name)
+	}
+	return nil
+}
+
 func IsMultiNetworkPoliciesSupportEnabled() bool {
 	return config.OVNKubernetesFeature.EnableMultiNetwork && config.OVNKubernetesFeature.EnableMultiNetworkPolicy
 }
+
+func IsPersistentIPsEnabled() bool {
+	return config.OVNKubernetesFeature.EnableMultiNetwork && config.OVNKubernetesFeature.EnablePersistentIPs
+}
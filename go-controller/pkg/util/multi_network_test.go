@@ -4,8 +4,13 @@ import (
 	"net"
 	"testing"
 
+	cnitypes "github.com/containernetworking/cni/pkg/types"
+	nadapi "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	"github.com/onsi/gomega"
+	kapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	ovncnitypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/cni/types"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
 	ovntest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
@@ -73,6 +78,31 @@ func TestParseSubnets(t *testing.T) {
 			excludes:    "fda7::38/128",
 			expectError: true,
 		},
+		{
+			desc:     "multiple subnets of the same IP family layer 2 topology",
+			topology: types.Layer2Topology,
+			subnets:  "192.168.1.1/26, 192.168.2.1/26",
+			excludes: "192.168.2.38/32",
+			expectedSubnets: []config.CIDRNetworkEntry{
+				{
+					CIDR: ovntest.MustParseIPNet("192.168.1.0/26"),
+				},
+			},
+			expectedExcludes: ovntest.MustParseIPNets("192.168.2.38/32"),
+		},
+		{
+			desc:     "multiple IPv6 subnets localnet topology",
+			topology: types.LocalnetTopology,
+			subnets:  "fda6::/48, 192.168.1.1/26, fda7::/48",
+			expectedSubnets: []config.CIDRNetworkEntry{
+				{
+					CIDR: ovntest.MustParseIPNet("fda6::/48"),
+				},
+				{
+					CIDR: ovntest.MustParseIPNet("192.168.1.0/26"),
+				},
+			},
+		},
 		{
 			desc:     "multiple subnets and excludes localnet topology",
 			topology: types.LocalnetTopology,
@@ -122,3 +152,229 @@ func TestParseSubnets(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPodNetworkInterfaceNames(t *testing.T) {
+	tests := []struct {
+		desc           string
+		networks       []*nadapi.NetworkSelectionElement
+		expectedNames  []string
+		expectedErrors []string
+	}{
+		{
+			desc: "derives names from the position of the networks without interface request",
+			networks: []*nadapi.NetworkSelectionElement{
+				{Name: "blue", Namespace: "ns1"},
+				{Name: "red", Namespace: "ns1", InterfaceRequest: "data0"},
+				{Name: "green", Namespace: "ns1"},
+			},
+			expectedNames:  []string{"net1", "data0", "net3"},
+			expectedErrors: []string{"", "", ""},
+		},
+		{
+			desc: "fails only the attachment whose requested name collides with a derived one",
+			networks: []*nadapi.NetworkSelectionElement{
+				{Name: "blue", Namespace: "ns1"},
+				{Name: "red", Namespace: "ns1", InterfaceRequest: "net1"},
+			},
+			expectedNames:  []string{"net1", ""},
+			expectedErrors: []string{"", `interface name "net1" of network ns1/red collides with network ns1/blue`},
+		},
+		{
+			desc: "fails only the attachment whose requested name collides with the default network interface",
+			networks: []*nadapi.NetworkSelectionElement{
+				{Name: "blue", Namespace: "ns1", InterfaceRequest: "eth0"},
+				{Name: "red", Namespace: "ns1"},
+			},
+			expectedNames:  []string{"", "net2"},
+			expectedErrors: []string{`interface name "eth0" of network ns1/blue collides with the default network`, ""},
+		},
+		{
+			desc: "an invalid requested name doesn't claim it",
+			networks: []*nadapi.NetworkSelectionElement{
+				{Name: "blue", Namespace: "ns1", InterfaceRequest: "a-very-long-interface"},
+				{Name: "red", Namespace: "ns1", InterfaceRequest: "data0"},
+			},
+			expectedNames:  []string{"", "data0"},
+			expectedErrors: []string{`interface name "a-very-long-interface" is longer than 15 characters`, ""},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			names, errs := GetPodNetworkInterfaceNames(tc.networks)
+			g.Expect(names).To(gomega.Equal(tc.expectedNames))
+			g.Expect(errs).To(gomega.HaveLen(len(tc.expectedErrors)))
+			for i, expectedError := range tc.expectedErrors {
+				if expectedError == "" {
+					g.Expect(errs[i]).NotTo(gomega.HaveOccurred())
+				} else {
+					g.Expect(errs[i]).To(gomega.MatchError(expectedError))
+				}
+			}
+		})
+	}
+}
+
+func TestGetPodNetworkInterfaceName(t *testing.T) {
+	g := gomega.NewWithT(t)
+	pod := &kapi.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "pod1",
+		Namespace: "ns1",
+		Annotations: map[string]string{
+			nadapi.NetworkAttachmentAnnot: `[{"name":"blue","interface":"eth0"},{"name":"red","interface":"data0"}]`,
+		},
+	}}
+
+	// an invalid attachment doesn't fail the other attachments of the pod
+	_, err := GetPodNetworkInterfaceName(pod, "ns1/blue")
+	g.Expect(err).To(gomega.MatchError(`interface name "eth0" of network ns1/blue collides with the default network`))
+	name, err := GetPodNetworkInterfaceName(pod, "ns1/red")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(name).To(gomega.Equal("data0"))
+
+	_, err = GetPodNetworkInterfaceName(pod, "ns1/green")
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+func TestReconfigureNetInfo(t *testing.T) {
+	localnet := func(subnets string, mtu, vlan int) *ovncnitypes.NetConf {
+		return &ovncnitypes.NetConf{
+			NetConf:  cnitypes.NetConf{Name: "blue"},
+			Topology: types.LocalnetTopology,
+			Subnets:  subnets,
+			MTU:      mtu,
+			VLANID:   vlan,
+		}
+	}
+	tests := []struct {
+		desc          string
+		netconf       *ovncnitypes.NetConf
+		expectedMTU   int
+		expectedVlan  uint
+		expectedError bool
+	}{
+		{
+			desc:         "MTU and VLAN changes are reconfigured",
+			netconf:      localnet("10.1.0.0/24", 1300, 20),
+			expectedMTU:  1300,
+			expectedVlan: 20,
+		},
+		{
+			desc: "guard changes are reconfigured",
+			netconf: &ovncnitypes.NetConf{
+				NetConf:     cnitypes.NetConf{Name: "blue"},
+				Topology:    types.LocalnetTopology,
+				Subnets:     "10.1.0.0/24",
+				MTU:         1400,
+				VLANID:      10,
+				RAGuard:     true,
+				DHCPv6Guard: true,
+			},
+			expectedMTU:  1400,
+			expectedVlan: 10,
+		},
+		{
+			desc:          "subnet changes can't be reconfigured",
+			netconf:       localnet("10.2.0.0/24", 1300, 20),
+			expectedMTU:   1400,
+			expectedVlan:  10,
+			expectedError: true,
+		},
+		{
+			desc:          "added subnets can't be reconfigured",
+			netconf:       localnet("10.1.0.0/24,fd10::/64", 1400, 10),
+			expectedMTU:   1400,
+			expectedVlan:  10,
+			expectedError: true,
+		},
+		{
+			desc: "excluded subnet changes can't be reconfigured",
+			netconf: &ovncnitypes.NetConf{
+				NetConf:        cnitypes.NetConf{Name: "blue"},
+				Topology:       types.LocalnetTopology,
+				Subnets:        "10.1.0.0/24",
+				ExcludeSubnets: "10.1.0.0/30",
+				MTU:            1400,
+				VLANID:         10,
+			},
+			expectedMTU:   1400,
+			expectedVlan:  10,
+			expectedError: true,
+		},
+		{
+			desc: "topology changes can't be reconfigured",
+			netconf: &ovncnitypes.NetConf{
+				NetConf:  cnitypes.NetConf{Name: "blue"},
+				Topology: types.Layer2Topology,
+				Subnets:  "10.1.0.0/24",
+			},
+			expectedMTU:   1400,
+			expectedVlan:  10,
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			nInfo, err := NewNetInfo(localnet("10.1.0.0/24", 1400, 10))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			other, err := NewNetInfo(tc.netconf)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(CanReconfigureNetInfo(nInfo, other)).To(gomega.Equal(!tc.expectedError))
+			err = nInfo.Reconfigure(other)
+			if tc.expectedError {
+				g.Expect(err).To(gomega.HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(nInfo.CompareNetInfo(other)).To(gomega.BeTrue())
+			}
+			g.Expect(nInfo.MTU()).To(gomega.Equal(tc.expectedMTU))
+			g.Expect(nInfo.Vlan()).To(gomega.Equal(tc.expectedVlan))
+		})
+	}
+}
+
+func TestMulticastNetInfo(t *testing.T) {
+	netconf := func(topology string, enableMulticast bool) *ovncnitypes.NetConf {
+		return &ovncnitypes.NetConf{
+			NetConf:         cnitypes.NetConf{Name: "blue"},
+			Topology:        topology,
+			Subnets:         "10.1.0.0/16",
+			EnableMulticast: enableMulticast,
+		}
+	}
+	tests := []struct {
+		desc     string
+		topology string
+		expected bool
+	}{
+		{
+			desc:     "layer3 networks support multicast",
+			topology: types.Layer3Topology,
+			expected: true,
+		},
+		{
+			desc:     "layer2 networks support multicast",
+			topology: types.Layer2Topology,
+			expected: true,
+		},
+		{
+			desc:     "localnet networks don't support multicast",
+			topology: types.LocalnetTopology,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			nInfo, err := NewNetInfo(netconf(tc.topology, true))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(nInfo.MulticastEnabled()).To(gomega.Equal(tc.expected))
+
+			other, err := NewNetInfo(netconf(tc.topology, false))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(nInfo.CompareNetInfo(other)).To(gomega.Equal(!tc.expected))
+			g.Expect(CanReconfigureNetInfo(nInfo, other)).To(gomega.Equal(!tc.expected))
+		})
+	}
+}
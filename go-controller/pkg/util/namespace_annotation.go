@@ -21,6 +21,9 @@ const (
 	ExternalGatewayPodIPsAnnotation = "k8s.ovn.org/external-gw-pod-ips"
 	// Annotation for enabling ACL logging to controller's log file
 	AclLoggingAnnotation = "k8s.ovn.org/acl-logging"
+	// Annotations used to limit the aggregate egress bandwidth of the pods in the namespace
+	NsEgressBandwidthAnnotation = "k8s.ovn.org/egress-bandwidth"
+	NsEgressBurstAnnotation     = "k8s.ovn.org/egress-burst"
 )
 
 func UpdateExternalGatewayPodIPsAnnotation(k kube.Interface, namespace string, exgwIPs []string) error {
@@ -10,6 +10,7 @@ import (
 
 	iputils "github.com/containernetworking/plugins/pkg/ip"
 	"github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/libovsdbops"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
 
@@ -152,6 +153,54 @@ func IPAddrToHWAddr(ip net.IP) net.HardwareAddr {
 	return net.HardwareAddr{0x0A, 0x58, hash[0], hash[1], hash[2], hash[3]}
 }
 
+// PodIPToHWAddr returns the MAC address of a pod interface whose primary IP is
+// ip. When deterministic pod MACs are enabled, the MAC is made of the configured
+// pod MAC prefix followed by the four least significant bytes of the IP, for both
+// IPv4 and IPv6, so that pods on the same subnet never share a MAC. Otherwise it
+// falls back to IPAddrToHWAddr.
+func PodIPToHWAddr(ip net.IP) net.HardwareAddr {
+	if !config.Default.DeterministicPodMAC {
+		return IPAddrToHWAddr(ip)
+	}
+	prefix := config.Default.PodMACPrefix
+	if len(prefix) != 2 {
+		prefix = net.HardwareAddr{0x0A, 0x58}
+	}
+	ip16 := ip.To16()
+	return net.HardwareAddr{prefix[0], prefix[1], ip16[12], ip16[13], ip16[14], ip16[15]}
+}
+
+// GetNetworkMACPrefix returns the locally administered unicast MAC prefix of the
+// pods of the secondary network with the given network id, as allocated by the
+// cluster manager. The 4 most significant bits of the 12 bits id are set in the
+// bits 2-5 of the first octet and the 8 least significant bits make the second
+// octet, so that every network id has a distinct prefix.
+func GetNetworkMACPrefix(networkID int) net.HardwareAddr {
+	return net.HardwareAddr{byte(networkID>>8)<<2&0x3c | 0x02, byte(networkID)}
+}
+
+// GetMACPrefixNetworkID returns the network id whose MAC prefix, as returned by
+// GetNetworkMACPrefix, is the given one, or InvalidNetworkID if no network id
+// maps to it.
+func GetMACPrefixNetworkID(prefix net.HardwareAddr) int {
+	if len(prefix) < 2 || prefix[0]&0xc3 != 0x02 {
+		return InvalidNetworkID
+	}
+	return int(prefix[0]>>2)<<8 | int(prefix[1])
+}
+
+// NetworkPodIPToHWAddr returns the MAC address of a pod interface whose primary
+// IP is ip on the network with the given MAC prefix: the prefix followed by the
+// four least significant bytes of the IP. A nil prefix falls back to
+// PodIPToHWAddr.
+func NetworkPodIPToHWAddr(prefix net.HardwareAddr, ip net.IP) net.HardwareAddr {
+	if len(prefix) != 2 {
+		return PodIPToHWAddr(ip)
+	}
+	ip16 := ip.To16()
+	return net.HardwareAddr{prefix[0], prefix[1], ip16[12], ip16[13], ip16[14], ip16[15]}
+}
+
 // HWAddrToIPv6LLA generates the IPv6 link local address from the given hwaddr,
 // with prefix 'fe80:/64'.
 func HWAddrToIPv6LLA(hwaddr net.HardwareAddr) net.IP {
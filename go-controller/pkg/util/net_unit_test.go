@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	iputils "github.com/containernetworking/plugins/pkg/ip"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
 	nbdb "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
 	ovntest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing"
 	mock_k8s_io_utils_exec "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing/mocks/k8s.io/utils/exec"
@@ -405,6 +406,122 @@ func TestIPAddrToHWAddr(t *testing.T) {
 	}
 }
 
+func TestPodIPToHWAddr(t *testing.T) {
+	tests := []struct {
+		desc          string
+		deterministic bool
+		prefix        string
+		inpIP         net.IP
+		outExp        net.HardwareAddr
+	}{
+		{
+			desc:   "falls back to IPAddrToHWAddr when disabled",
+			inpIP:  ovntest.MustParseIP("fd01::1234"),
+			outExp: ovntest.MustParseMAC("0a:58:11:37:a6:26"),
+		},
+		{
+			desc:          "derives the MAC from an IPv4 address",
+			deterministic: true,
+			inpIP:         ovntest.MustParseIP("192.168.1.5"),
+			outExp:        ovntest.MustParseMAC("0a:58:c0:a8:01:05"),
+		},
+		{
+			desc:          "derives the MAC from the low bytes of an IPv6 address",
+			deterministic: true,
+			inpIP:         ovntest.MustParseIP("fd01::1:1234"),
+			outExp:        ovntest.MustParseMAC("0a:58:00:01:12:34"),
+		},
+		{
+			desc:          "uses the configured prefix",
+			deterministic: true,
+			prefix:        "0e:01",
+			inpIP:         ovntest.MustParseIP("10.0.0.3"),
+			outExp:        ovntest.MustParseMAC("0e:01:0a:00:00:03"),
+		},
+	}
+	for i, tc := range tests {
+		t.Run(fmt.Sprintf("%d:%s", i, tc.desc), func(t *testing.T) {
+			assert.NoError(t, config.PrepareTestConfig())
+			config.Default.DeterministicPodMAC = tc.deterministic
+			if tc.prefix != "" {
+				prefix, err := config.ParseMACPrefix(tc.prefix)
+				assert.NoError(t, err)
+				config.Default.PodMACPrefix = prefix
+			}
+			res := PodIPToHWAddr(tc.inpIP)
+			assert.Equal(t, tc.outExp, res)
+		})
+	}
+}
+
+func TestGetNetworkMACPrefix(t *testing.T) {
+	tests := []struct {
+		desc      string
+		networkID int
+		outExp    net.HardwareAddr
+	}{
+		{
+			desc:      "lowest network id",
+			networkID: 1,
+			outExp:    net.HardwareAddr{0x02, 0x01},
+		},
+		{
+			desc:      "highest network id",
+			networkID: 4095,
+			outExp:    net.HardwareAddr{0x3e, 0xff},
+		},
+		{
+			desc:      "network id colliding with the default network prefix",
+			networkID: 600,
+			outExp:    net.HardwareAddr{0x0a, 0x58},
+		},
+	}
+	for i, tc := range tests {
+		t.Run(fmt.Sprintf("%d:%s", i, tc.desc), func(t *testing.T) {
+			res := GetNetworkMACPrefix(tc.networkID)
+			assert.Equal(t, tc.outExp, res)
+			assert.Equal(t, tc.networkID, GetMACPrefixNetworkID(res))
+		})
+	}
+	// not locally administered unicast, no network id maps to it
+	assert.Equal(t, InvalidNetworkID, GetMACPrefixNetworkID(net.HardwareAddr{0x01, 0x00}))
+	assert.Equal(t, InvalidNetworkID, GetMACPrefixNetworkID(nil))
+}
+
+func TestNetworkPodIPToHWAddr(t *testing.T) {
+	tests := []struct {
+		desc   string
+		prefix net.HardwareAddr
+		inpIP  net.IP
+		outExp net.HardwareAddr
+	}{
+		{
+			desc:   "falls back to PodIPToHWAddr without prefix",
+			inpIP:  ovntest.MustParseIP("192.168.1.5"),
+			outExp: ovntest.MustParseMAC("0a:58:c0:a8:01:05"),
+		},
+		{
+			desc:   "uses the network prefix with an IPv4 address",
+			prefix: net.HardwareAddr{0x02, 0x01},
+			inpIP:  ovntest.MustParseIP("192.168.1.5"),
+			outExp: ovntest.MustParseMAC("02:01:c0:a8:01:05"),
+		},
+		{
+			desc:   "uses the network prefix with the low bytes of an IPv6 address",
+			prefix: net.HardwareAddr{0x02, 0x01},
+			inpIP:  ovntest.MustParseIP("fd01::1:1234"),
+			outExp: ovntest.MustParseMAC("02:01:00:01:12:34"),
+		},
+	}
+	for i, tc := range tests {
+		t.Run(fmt.Sprintf("%d:%s", i, tc.desc), func(t *testing.T) {
+			assert.NoError(t, config.PrepareTestConfig())
+			res := NetworkPodIPToHWAddr(tc.prefix, tc.inpIP)
+			assert.Equal(t, tc.outExp, res)
+		})
+	}
+}
+
 func TestJoinIPs(t *testing.T) {
 	tests := []struct {
 		desc         string
@@ -0,0 +1,84 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// This handles the annotation used by the network controllers of each zone to report the state of a secondary
+// network on its net-attach-defs:
+//
+//   annotations:
+//     k8s.ovn.org/network-controller-status: |
+//       {
+//         "zone1": {
+//           "state": "Started",
+//           "lastTransitionTime": "2023-06-01T10:00:00Z"
+//         },
+//         "zone2": {
+//           "state": "Degraded",
+//           "lastError": "failed to create logical switch network1_ovn_layer2_switch: ...",
+//           "lastTransitionTime": "2023-06-01T10:00:05Z"
+//         }
+//       }
+
+// OvnNetworkControllerStatusAnnotation is the net-attach-def annotation holding the state of the network
+// controllers of the network, keyed by zone
+const OvnNetworkControllerStatusAnnotation = "k8s.ovn.org/network-controller-status"
+
+// NetworkControllerState is the state of the network controller of a network in a zone
+type NetworkControllerState string
+
+const (
+	// NetworkControllerSyncing is the state of a network controller that is starting and syncing the existing
+	// objects of its network
+	NetworkControllerSyncing NetworkControllerState = "Syncing"
+	// NetworkControllerStarted is the state of a network controller that started successfully
+	NetworkControllerStarted NetworkControllerState = "Started"
+	// NetworkControllerDegraded is the state of a network controller that failed to start, it is retried
+	NetworkControllerDegraded NetworkControllerState = "Degraded"
+)
+
+// NetworkControllerStatus is the status of the network controller of a network in a zone
+type NetworkControllerStatus struct {
+	State NetworkControllerState `json:"state"`
+	// LastError is the error of the last failed start of the controller
+	LastError          string      `json:"lastError,omitempty"`
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+}
+
+// ParseNetworkControllerStatusAnnotation returns the network controller statuses of the annotations, keyed by zone
+func ParseNetworkControllerStatusAnnotation(annotations map[string]string) (map[string]NetworkControllerStatus, error) {
+	statuses := map[string]NetworkControllerStatus{}
+	annotation, ok := annotations[OvnNetworkControllerStatusAnnotation]
+	if !ok {
+		return statuses, nil
+	}
+	if err := json.Unmarshal([]byte(annotation), &statuses); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s annotation %q: %v",
+			OvnNetworkControllerStatusAnnotation, annotation, err)
+	}
+	return statuses, nil
+}
+
+// UpdateNetworkControllerStatusAnnotation sets the network controller status of the given zone in the annotations,
+// keeping the statuses of the other zones. A malformed annotation is overwritten.
+func UpdateNetworkControllerStatusAnnotation(annotations map[string]string, zone string,
+	status NetworkControllerStatus) (map[string]string, error) {
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	statuses, err := ParseNetworkControllerStatusAnnotation(annotations)
+	if err != nil {
+		statuses = map[string]NetworkControllerStatus{}
+	}
+	statuses[zone] = status
+	bytes, err := json.Marshal(statuses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s annotation: %v", OvnNetworkControllerStatusAnnotation, err)
+	}
+	annotations[OvnNetworkControllerStatusAnnotation] = string(bytes)
+	return annotations, nil
+}
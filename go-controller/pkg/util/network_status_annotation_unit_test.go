@@ -0,0 +1,69 @@
+package util
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUpdateNetworkControllerStatusAnnotation(t *testing.T) {
+	transitionTime := metav1.NewTime(time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC).Local())
+	started := NetworkControllerStatus{State: NetworkControllerStarted, LastTransitionTime: transitionTime}
+	degraded := NetworkControllerStatus{
+		State:              NetworkControllerDegraded,
+		LastError:          "failed to create logical switch",
+		LastTransitionTime: transitionTime,
+	}
+	tests := []struct {
+		desc           string
+		inpAnnotations map[string]string
+		inpZone        string
+		inpStatus      NetworkControllerStatus
+		expectedStatus map[string]NetworkControllerStatus
+	}{
+		{
+			desc:           "status is added to nil annotations",
+			inpZone:        "zone1",
+			inpStatus:      started,
+			expectedStatus: map[string]NetworkControllerStatus{"zone1": started},
+		},
+		{
+			desc: "status of the other zones is kept",
+			inpAnnotations: map[string]string{
+				OvnNetworkControllerStatusAnnotation: `{"zone1":{"state":"Started","lastTransitionTime":"2023-06-01T10:00:00Z"}}`,
+			},
+			inpZone:        "zone2",
+			inpStatus:      degraded,
+			expectedStatus: map[string]NetworkControllerStatus{"zone1": started, "zone2": degraded},
+		},
+		{
+			desc: "status of the zone is replaced",
+			inpAnnotations: map[string]string{
+				OvnNetworkControllerStatusAnnotation: `{"zone1":{"state":"Started","lastTransitionTime":"2023-06-01T10:00:00Z"}}`,
+			},
+			inpZone:        "zone1",
+			inpStatus:      degraded,
+			expectedStatus: map[string]NetworkControllerStatus{"zone1": degraded},
+		},
+		{
+			desc:           "malformed annotation is overwritten",
+			inpAnnotations: map[string]string{OvnNetworkControllerStatusAnnotation: "{"},
+			inpZone:        "zone1",
+			inpStatus:      started,
+			expectedStatus: map[string]NetworkControllerStatus{"zone1": started},
+		},
+	}
+
+	for i, tc := range tests {
+		t.Run(fmt.Sprintf("%d:%s", i, tc.desc), func(t *testing.T) {
+			annotations, err := UpdateNetworkControllerStatusAnnotation(tc.inpAnnotations, tc.inpZone, tc.inpStatus)
+			assert.NoError(t, err)
+			statuses, err := ParseNetworkControllerStatusAnnotation(annotations)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, statuses)
+		})
+	}
+}
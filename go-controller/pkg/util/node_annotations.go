@@ -51,6 +51,10 @@ const (
 	// ovnNodeGatewayMtuSupport determines if option:gateway_mtu shall be set for GR router ports.
 	ovnNodeGatewayMtuSupport = "k8s.ovn.org/gateway-mtu-support"
 
+	// OvnNodeCOPPRates overrides the control plane protection rates of the gateway router of the node,
+	// with the same <protocol>=<rate> format as the copp-rates configuration option
+	OvnNodeCOPPRates = "k8s.ovn.org/copp-rates"
+
 	// OvnDefaultNetworkGateway captures L3 gateway config for default OVN network interface
 	ovnDefaultNetworkGateway = "default"
 
@@ -295,6 +299,16 @@ func ParseNodeGatewayMTUSupport(node *kapi.Node) bool {
 	return node.Annotations[ovnNodeGatewayMtuSupport] != "false"
 }
 
+// ParseNodeCOPPRates parses annotation "k8s.ovn.org/copp-rates", returning the control plane protection rates
+// in packets per second overridden on the node by protocol. A node without the annotation has no overrides.
+func ParseNodeCOPPRates(node *kapi.Node) (map[string]int, error) {
+	rates, err := config.ParseCOPPRates(node.Annotations[OvnNodeCOPPRates])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s annotation of node %q: %v", OvnNodeCOPPRates, node.Name, err)
+	}
+	return rates, nil
+}
+
 // ParseNodeL3GatewayAnnotation returns the parsed l3-gateway-config annotation
 func ParseNodeL3GatewayAnnotation(node *kapi.Node) (*L3GatewayConfig, error) {
 	l3GatewayAnnotation, ok := node.Annotations[ovnNodeL3GatewayConfig]
@@ -516,6 +516,12 @@ func RunOVSDBTool(args ...string) (string, string, error) {
 	return strings.Trim(strings.TrimSpace(stdout.String()), "\""), stderr.String(), err
 }
 
+// RunOVNCtl runs an 'ovn-ctl [OPTIONS] COMMAND' command.
+func RunOVNCtl(args ...string) (string, string, error) {
+	stdout, stderr, err := run(runner.ovnctlPath, args...)
+	return strings.TrimSpace(stdout.String()), stderr.String(), err
+}
+
 // RunOVSDBClientOVN runs an 'ovsdb-client [OPTIONS] COMMAND [SERVER] [ARG...] command' against OVN NB database.
 func RunOVSDBClientOVNNB(command string, args ...string) (string, string, error) {
 	cmdArgs := getNbOVSDBArgs(command, args...)
@@ -5,9 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strings"
 
 	nadapi "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	nadutils "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/utils"
+	ovncnitypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/cni/types"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
 
 	v1 "k8s.io/api/core/v1"
@@ -66,6 +68,9 @@ type PodAnnotation struct {
 	Gateways []net.IP
 	// Routes are additional routes to add to the pod's network namespace
 	Routes []PodRoute
+	// TunnelID is the tunnel key of the pod port on the switch of a layer2
+	// network shared by the interconnected zones, 0 if not allocated
+	TunnelID int
 }
 
 // PodRoute describes any routes to be added to the pod's network namespace
@@ -83,6 +88,8 @@ type podAnnotation struct {
 	Gateways []string   `json:"gateway_ips,omitempty"`
 	Routes   []podRoute `json:"routes,omitempty"`
 
+	TunnelID int `json:"tunnel_id,omitempty"`
+
 	IP      string `json:"ip_address,omitempty"`
 	Gateway string `json:"gateway_ip,omitempty"`
 }
@@ -103,18 +110,29 @@ func MarshalPodAnnotation(annotations map[string]string, podInfo *PodAnnotation,
 		return nil, err
 	}
 	pa := podAnnotation{
-		MAC: podInfo.MAC.String(),
+		MAC:      podInfo.MAC.String(),
+		TunnelID: podInfo.TunnelID,
+	}
+
+	ips := append([]*net.IPNet{}, podInfo.IPs...)
+	SortIPNetsByFamily(ips)
+	if nadName == types.DefaultNetworkName {
+		if err := ValidateDualStackIPNets(ips); err != nil {
+			return nil, fmt.Errorf("bad podNetwork data: %v", err)
+		}
 	}
+	gateways := append([]net.IP{}, podInfo.Gateways...)
+	SortIPsByFamily(gateways)
 
-	if len(podInfo.IPs) == 1 {
-		pa.IP = podInfo.IPs[0].String()
-		if len(podInfo.Gateways) == 1 {
-			pa.Gateway = podInfo.Gateways[0].String()
-		} else if len(podInfo.Gateways) > 1 {
+	if len(ips) == 1 {
+		pa.IP = ips[0].String()
+		if len(gateways) == 1 {
+			pa.Gateway = gateways[0].String()
+		} else if len(gateways) > 1 {
 			return nil, fmt.Errorf("bad podNetwork data: single-stack network can only have a single gateway")
 		}
 	}
-	for _, ip := range podInfo.IPs {
+	for _, ip := range ips {
 		pa.IPs = append(pa.IPs, ip.String())
 	}
 
@@ -130,7 +148,7 @@ func MarshalPodAnnotation(annotations map[string]string, podInfo *PodAnnotation,
 		}
 	}
 
-	for _, gw := range podInfo.Gateways {
+	for _, gw := range gateways {
 		pa.Gateways = append(pa.Gateways, gw.String())
 	}
 
@@ -177,7 +195,7 @@ func UnmarshalPodAnnotation(annotations map[string]string, nadName string) (*Pod
 
 	a := &tempA
 
-	podAnnotation := &PodAnnotation{}
+	podAnnotation := &PodAnnotation{TunnelID: a.TunnelID}
 	podAnnotation.MAC, err = net.ParseMAC(a.MAC)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse pod MAC %q: %v", a.MAC, err)
@@ -249,6 +267,28 @@ func UnmarshalPodAnnotationAllNetworks(annotations map[string]string) (map[strin
 	return podNetworks, nil
 }
 
+// DeletePodAnnotationNetworks removes the pod's network details of the given networks from the corresponding pod
+// annotation, and the annotation itself once it holds no network
+func DeletePodAnnotationNetworks(annotations map[string]string, nadNames ...string) (map[string]string, error) {
+	podNetworks, err := UnmarshalPodAnnotationAllNetworks(annotations)
+	if err != nil {
+		return nil, err
+	}
+	for _, nadName := range nadNames {
+		delete(podNetworks, nadName)
+	}
+	if len(podNetworks) == 0 {
+		delete(annotations, OvnPodAnnotationName)
+		return annotations, nil
+	}
+	bytes, err := json.Marshal(podNetworks)
+	if err != nil {
+		return nil, fmt.Errorf("failed marshaling podNetworks map %v", podNetworks)
+	}
+	annotations[OvnPodAnnotationName] = string(bytes)
+	return annotations, nil
+}
+
 // GetPodCIDRsWithFullMask returns the pod's IP addresses in a CIDR with FullMask format
 // Internally it calls GetPodIPsOfNetwork
 func GetPodCIDRsWithFullMask(pod *v1.Pod, nInfo NetInfo) ([]*net.IPNet, error) {
@@ -375,3 +415,28 @@ func GetK8sPodAllNetworkSelections(pod *v1.Pod) ([]*nadapi.NetworkSelectionEleme
 	}
 	return networks, nil
 }
+
+// GetK8sPodIPAMClaimReference returns the name of the IPAMClaim the pod references for the given NAD in its
+// k8s.v1.cni.cncf.io/networks annotation, or an empty string if there is none. Only the JSON form of the
+// annotation can carry IPAMClaim references.
+func GetK8sPodIPAMClaimReference(pod *v1.Pod, nadName string) (string, error) {
+	netAnnot := strings.TrimSpace(pod.Annotations[nadapi.NetworkAttachmentAnnot])
+	if !strings.HasPrefix(netAnnot, "[") {
+		return "", nil
+	}
+
+	var networks []ovncnitypes.NetworkSelectionElement
+	if err := json.Unmarshal([]byte(netAnnot), &networks); err != nil {
+		return "", fmt.Errorf("failed to parse network selection annotation of pod %s/%s: %v", pod.Namespace, pod.Name, err)
+	}
+	for _, network := range networks {
+		namespace := network.Namespace
+		if namespace == "" {
+			namespace = pod.Namespace
+		}
+		if GetNADName(namespace, network.Name) == nadName {
+			return network.IPAMClaimReference, nil
+		}
+	}
+	return "", nil
+}
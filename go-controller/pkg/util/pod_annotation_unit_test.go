@@ -101,6 +101,13 @@ func TestMarshalPodAnnotation(t *testing.T) {
 			},
 			expectedOutput: map[string]string{"k8s.ovn.org/pod-networks": `{"default":{"ip_addresses":null,"mac_address":"","routes":[{"dest":"192.168.1.0/24","nextHop":""}]}}`},
 		},
+		{
+			desc: "tunnel id set for a layer2 network attachment",
+			inpPodAnnot: PodAnnotation{
+				TunnelID: 5,
+			},
+			expectedOutput: map[string]string{"k8s.ovn.org/pod-networks": `{"default":{"ip_addresses":null,"mac_address":"","tunnel_id":5}}`},
+		},
 	}
 
 	for i, tc := range tests {
@@ -309,3 +316,128 @@ func TestGetPodIPsOfNetwork(t *testing.T) {
 		})
 	}
 }
+
+func TestGetK8sPodIPAMClaimReference(t *testing.T) {
+	tests := []struct {
+		desc      string
+		inpAnnot  string
+		nadName   string
+		errAssert bool
+		outExp    string
+	}{
+		{
+			desc:     "test when the pod has no network selection annotation",
+			inpAnnot: "",
+			nadName:  "ns1/nad1",
+			outExp:   "",
+		},
+		{
+			desc:     "test when the network selection annotation is not in JSON form",
+			inpAnnot: "nad1,ns2/nad2",
+			nadName:  "ns1/nad1",
+			outExp:   "",
+		},
+		{
+			desc:     "test when the claim reference is set for a NAD in the pod namespace",
+			inpAnnot: `[{"name":"nad1","ipam-claim-reference":"vm1.nad1"}]`,
+			nadName:  "ns1/nad1",
+			outExp:   "vm1.nad1",
+		},
+		{
+			desc:     "test when the claim reference is set for a NAD in another namespace",
+			inpAnnot: `[{"name":"nad1"},{"name":"nad2","namespace":"ns2","ipam-claim-reference":"vm1.nad2"}]`,
+			nadName:  "ns2/nad2",
+			outExp:   "vm1.nad2",
+		},
+		{
+			desc:     "test when the NAD is not referenced by the pod",
+			inpAnnot: `[{"name":"nad1","ipam-claim-reference":"vm1.nad1"}]`,
+			nadName:  "ns2/nad1",
+			outExp:   "",
+		},
+		{
+			desc:      "test when the network selection annotation is malformed",
+			inpAnnot:  `[{"name":"nad1"`,
+			nadName:   "ns1/nad1",
+			errAssert: true,
+		},
+	}
+	for i, tc := range tests {
+		t.Run(fmt.Sprintf("%d:%s", i, tc.desc), func(t *testing.T) {
+			pod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "pod1",
+					Namespace:   "ns1",
+					Annotations: map[string]string{},
+				},
+			}
+			if tc.inpAnnot != "" {
+				pod.Annotations["k8s.v1.cni.cncf.io/networks"] = tc.inpAnnot
+			}
+			res, e := GetK8sPodIPAMClaimReference(pod, tc.nadName)
+			t.Log(res, e)
+			if tc.errAssert {
+				assert.Error(t, e)
+			} else {
+				assert.NoError(t, e)
+				assert.Equal(t, tc.outExp, res)
+			}
+		})
+	}
+}
+
+func TestDeletePodAnnotationNetworks(t *testing.T) {
+	tests := []struct {
+		desc      string
+		inpAnnot  map[string]string
+		nadNames  []string
+		errAssert bool
+		outExp    map[string]string
+	}{
+		{
+			desc:     "test when the pod has no network annotation",
+			inpAnnot: map[string]string{},
+			nadNames: []string{"ns1/nad1"},
+			outExp:   map[string]string{},
+		},
+		{
+			desc: "test removing one of the networks",
+			inpAnnot: map[string]string{
+				"k8s.ovn.org/pod-networks": `{"default":{"ip_addresses":["192.168.0.5/24"],"mac_address":"0a:58:fd:98:00:01"},"ns1/nad1":{"ip_addresses":["10.1.0.5/24"],"mac_address":"0a:58:0a:01:00:05"}}`,
+			},
+			nadNames: []string{"ns1/nad1", "ns2/nad2"},
+			outExp: map[string]string{
+				"k8s.ovn.org/pod-networks": `{"default":{"ip_addresses":["192.168.0.5/24"],"mac_address":"0a:58:fd:98:00:01"}}`,
+			},
+		},
+		{
+			desc: "test removing the last network removes the annotation",
+			inpAnnot: map[string]string{
+				"k8s.ovn.org/pod-networks": `{"ns1/nad1":{"ip_addresses":["10.1.0.5/24"],"mac_address":"0a:58:0a:01:00:05"}}`,
+				"foo":                      "bar",
+			},
+			nadNames: []string{"ns1/nad1"},
+			outExp:   map[string]string{"foo": "bar"},
+		},
+		{
+			desc: "test when the network annotation is malformed",
+			inpAnnot: map[string]string{
+				"k8s.ovn.org/pod-networks": `{"ns1/nad1":`,
+			},
+			nadNames:  []string{"ns1/nad1"},
+			errAssert: true,
+		},
+	}
+	for i, tc := range tests {
+		t.Run(fmt.Sprintf("%d:%s", i, tc.desc), func(t *testing.T) {
+			res, e := DeletePodAnnotationNetworks(tc.inpAnnot, tc.nadNames...)
+			t.Log(res, e)
+			if tc.errAssert {
+				assert.Error(t, e)
+			} else {
+				assert.NoError(t, e)
+				assert.Equal(t, tc.outExp, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// PortSecurityExemptionsAnnotation is the pod annotation listing the extra IPs and MACs a pod may send traffic from
+// on the default network, such as the virtual IPs and MACs shared by VRRP pods:
+//
+//	annotations:
+//	  k8s.ovn.org/port-security-exemptions: |
+//	    {"ips": ["10.128.250.10"], "macs": ["00:00:5e:00:01:0a"]}
+const PortSecurityExemptionsAnnotation = "k8s.ovn.org/port-security-exemptions"
+
+// vrrpMACPrefixes are the prefixes of the IPv4 and IPv6 VRRP virtual router MACs, followed by the virtual router id
+// (RFC 5798)
+var vrrpMACPrefixes = []net.HardwareAddr{{0x00, 0x00, 0x5e, 0x00, 0x01}, {0x00, 0x00, 0x5e, 0x00, 0x02}}
+
+// PortSecurityExemptions holds the extra IPs and MACs a pod may send traffic from
+type PortSecurityExemptions struct {
+	IPs  []net.IP
+	MACs []net.HardwareAddr
+}
+
+// Internal struct used to unmarshal the port security exemptions annotation
+type portSecurityExemptions struct {
+	IPs  []string `json:"ips,omitempty"`
+	MACs []string `json:"macs,omitempty"`
+}
+
+// GetPodPortSecurityExemptions returns the port security exemptions of the pod, or nil if it has none. The IPs must
+// belong to one of the allowed CIDRs configured by the admin, and the MACs must be VRRP virtual router MACs.
+func GetPodPortSecurityExemptions(pod *v1.Pod, allowedCIDRs []*net.IPNet) (*PortSecurityExemptions, error) {
+	annotation, ok := pod.Annotations[PortSecurityExemptionsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	if len(allowedCIDRs) == 0 {
+		return nil, fmt.Errorf("port security exemptions are not allowed in the cluster")
+	}
+	var raw portSecurityExemptions
+	if err := json.Unmarshal([]byte(annotation), &raw); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the port security exemptions %q: %v", annotation, err)
+	}
+
+	exemptions := &PortSecurityExemptions{}
+	for _, ipStr := range raw.IPs {
+		ip := net.ParseIP(ipStr)
+		if ip == nil {
+			return nil, fmt.Errorf("failed to parse the port security exemption IP %q", ipStr)
+		}
+		allowed := false
+		for _, cidr := range allowedCIDRs {
+			if cidr.Contains(ip) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return nil, fmt.Errorf("port security exemption IP %s is not in the allowed CIDRs", ipStr)
+		}
+		exemptions.IPs = append(exemptions.IPs, ip)
+	}
+	for _, macStr := range raw.MACs {
+		mac, err := net.ParseMAC(macStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the port security exemption MAC %q: %v", macStr, err)
+		}
+		allowed := false
+		for _, prefix := range vrrpMACPrefixes {
+			if len(mac) == 6 && bytes.HasPrefix(mac, prefix) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return nil, fmt.Errorf("port security exemption MAC %s is not a VRRP virtual router MAC", macStr)
+		}
+		exemptions.MACs = append(exemptions.MACs, mac)
+	}
+	if len(exemptions.IPs) == 0 && len(exemptions.MACs) == 0 {
+		return nil, nil
+	}
+	return exemptions, nil
+}
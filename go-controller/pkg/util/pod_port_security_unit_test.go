@@ -0,0 +1,88 @@
+package util
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ovntest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing"
+)
+
+func TestGetPodPortSecurityExemptions(t *testing.T) {
+	allowedCIDRs := []*net.IPNet{ovntest.MustParseIPNet("10.128.250.0/24"), ovntest.MustParseIPNet("fd00:250::/64")}
+	tests := []struct {
+		desc         string
+		inpAnnot     string
+		allowedCIDRs []*net.IPNet
+		errAssert    bool
+		outExp       *PortSecurityExemptions
+	}{
+		{
+			desc:         "test when the pod has no port security exemptions",
+			allowedCIDRs: allowedCIDRs,
+		},
+		{
+			desc:         "test when the pod has exempted IPs and MACs",
+			inpAnnot:     `{"ips":["10.128.250.10","fd00:250::10"],"macs":["00:00:5e:00:01:0a","00:00:5e:00:02:0a"]}`,
+			allowedCIDRs: allowedCIDRs,
+			outExp: &PortSecurityExemptions{
+				IPs:  []net.IP{ovntest.MustParseIP("10.128.250.10"), ovntest.MustParseIP("fd00:250::10")},
+				MACs: []net.HardwareAddr{ovntest.MustParseMAC("00:00:5e:00:01:0a"), ovntest.MustParseMAC("00:00:5e:00:02:0a")},
+			},
+		},
+		{
+			desc:         "test when the exemptions are empty",
+			inpAnnot:     `{}`,
+			allowedCIDRs: allowedCIDRs,
+		},
+		{
+			desc:      "test when the cluster allows no exemption",
+			inpAnnot:  `{"ips":["10.128.250.10"]}`,
+			errAssert: true,
+		},
+		{
+			desc:         "test when an IP is out of the allowed CIDRs",
+			inpAnnot:     `{"ips":["10.128.0.10"]}`,
+			allowedCIDRs: allowedCIDRs,
+			errAssert:    true,
+		},
+		{
+			desc:         "test when a MAC is not a VRRP MAC",
+			inpAnnot:     `{"macs":["0a:58:0a:80:00:0a"]}`,
+			allowedCIDRs: allowedCIDRs,
+			errAssert:    true,
+		},
+		{
+			desc:         "test when the annotation is malformed",
+			inpAnnot:     `{"ips":`,
+			allowedCIDRs: allowedCIDRs,
+			errAssert:    true,
+		},
+	}
+	for i, tc := range tests {
+		t.Run(fmt.Sprintf("%d:%s", i, tc.desc), func(t *testing.T) {
+			pod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "pod1",
+					Namespace:   "ns1",
+					Annotations: map[string]string{},
+				},
+			}
+			if tc.inpAnnot != "" {
+				pod.Annotations[PortSecurityExemptionsAnnotation] = tc.inpAnnot
+			}
+			res, e := GetPodPortSecurityExemptions(pod, tc.allowedCIDRs)
+			t.Log(res, e)
+			if tc.errAssert {
+				assert.Error(t, e)
+			} else {
+				assert.NoError(t, e)
+				assert.Equal(t, tc.outExp, res)
+			}
+		})
+	}
+}
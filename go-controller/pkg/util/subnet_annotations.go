@@ -57,6 +57,13 @@ func updateSubnetAnnotation(annotations map[string]string, annotationName, netNa
 
 	// add or delete host subnet of the specified network
 	if len(hostSubnets) != 0 {
+		hostSubnets = append([]*net.IPNet{}, hostSubnets...)
+		SortIPNetsByFamily(hostSubnets)
+		if netName == types.DefaultNetworkName {
+			if err := ValidateDualStackIPNets(hostSubnets); err != nil {
+				return fmt.Errorf("invalid host subnets for node annotation %s: %v", annotationName, err)
+			}
+		}
 		subnetsMap[netName] = hostSubnets
 	} else {
 		delete(subnetsMap, netName)
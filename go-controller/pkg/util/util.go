@@ -1,6 +1,7 @@
 package util
 
 import (
+	"errors"
 	"fmt"
 	"hash/fnv"
 	"net"
@@ -199,6 +200,29 @@ func IsAnnotationAlreadySetError(err error) bool {
 	return ok
 }
 
+type permanentError struct {
+	err error
+}
+
+func (pe permanentError) Error() string {
+	return pe.err.Error()
+}
+
+func (pe permanentError) Unwrap() error {
+	return pe.err
+}
+
+// NewPermanentError marks err as permanent: handling the object it was returned for again can't succeed until the
+// object changes, eg because the object is invalid. The retry framework doesn't retry such objects.
+func NewPermanentError(err error) error {
+	return permanentError{err: err}
+}
+
+// IsPermanentError returns true if the error, or any error it wraps, is a permanent error
+func IsPermanentError(err error) bool {
+	return errors.As(err, &permanentError{})
+}
+
 // HashforOVN hashes the provided input to make it a valid addressSet or portGroup name.
 func HashForOVN(s string) string {
 	h := fnv.New64a()
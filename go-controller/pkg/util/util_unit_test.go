@@ -524,3 +524,42 @@ func TestUpdateNodeSwitchExcludeIPs(t *testing.T) {
 		})
 	}
 }
+
+func TestIsPermanentError(t *testing.T) {
+	tests := []struct {
+		desc string
+		err  error
+		want bool
+	}{
+		{
+			desc: "a permanent error",
+			err:  NewPermanentError(fmt.Errorf("invalid object")),
+			want: true,
+		},
+		{
+			desc: "a wrapped permanent error",
+			err:  fmt.Errorf("failed to add object: %w", NewPermanentError(fmt.Errorf("invalid object"))),
+			want: true,
+		},
+		{
+			desc: "a permanent error wrapped with %v",
+			err:  fmt.Errorf("failed to add object: %v", NewPermanentError(fmt.Errorf("invalid object"))),
+			want: false,
+		},
+		{
+			desc: "a transient error",
+			err:  fmt.Errorf("connection refused"),
+			want: false,
+		},
+		{
+			desc: "no error",
+			err:  nil,
+			want: false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.want, IsPermanentError(tc.err))
+		})
+	}
+}